@@ -0,0 +1,301 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+	"sync"
+	"unicode/utf8"
+)
+
+// errInterrupted is returned by ReadLine when the user presses Ctrl-C
+var errInterrupted = errors.New("interrupted")
+
+// Completer returns the candidates completing the word that ends at the cursor in
+// line, and where in line that word starts
+type Completer func(line string) (start int, candidates []string)
+
+// LineEditor reads lines from a terminal with cursor movement, history, and tab
+// completion. The terminal is put in character mode with stty only while a line is
+// read, so output in between behaves as usual. When input isn't a terminal, lines are
+// read as they come, without prompts.
+type LineEditor struct {
+	in       *os.File
+	out      io.Writer
+	reader   *bufio.Reader
+	terminal bool
+
+	History  []string
+	Complete Completer
+
+	// The line being edited, which Print redraws below what it prints
+	mu         sync.Mutex
+	editing    bool
+	prompt     string
+	line       []rune
+	pos        int
+	historyPos int
+	pending    string // The line being written, while browsing history
+	lastWasTab bool
+}
+
+// maxHistory bounds the lines kept in history
+const maxHistory = 500
+
+// NewLineEditor returns an editor reading from in and echoing to out
+func NewLineEditor(in *os.File, out io.Writer) *LineEditor {
+	e := &LineEditor{in: in, out: out, reader: bufio.NewReader(in)}
+	if info, err := in.Stat(); err == nil && info.Mode()&os.ModeCharDevice != 0 {
+		// stty is how this works without a terminal library; without it, read plainly
+		_, err := exec.LookPath("stty")
+		e.terminal = err == nil
+	}
+	return e
+}
+
+// Terminal reports whether lines are edited interactively
+func (e *LineEditor) Terminal() bool {
+	return e.terminal
+}
+
+// AddHistory records an entered line, skipping repeats of the last one
+func (e *LineEditor) AddHistory(line string) {
+	if line == "" || (len(e.History) > 0 && e.History[len(e.History)-1] == line) {
+		return
+	}
+	e.History = append(e.History, line)
+	if len(e.History) > maxHistory {
+		e.History = e.History[len(e.History)-maxHistory:]
+	}
+}
+
+// ReadLine shows prompt and returns the line entered, io.EOF at the end of input or
+// on Ctrl-D at an empty line, and errInterrupted on Ctrl-C
+func (e *LineEditor) ReadLine(prompt string) (string, error) {
+	if !e.terminal {
+		line, err := e.reader.ReadString('\n')
+		if err != nil && (err != io.EOF || line == "") {
+			return "", err
+		}
+		return strings.TrimRight(line, "\r\n"), nil
+	}
+
+	saved, err := e.stty("-g")
+	if err != nil {
+		return "", err
+	}
+	// Character at a time, without echo, and with Ctrl-C read as a key
+	if _, err := e.stty("-icanon", "-echo", "-isig", "min", "1", "time", "0"); err != nil {
+		return "", err
+	}
+	defer e.stty(strings.TrimSpace(saved))
+
+	return e.edit(prompt)
+}
+
+// Print writes text, which should end with a newline, above the line being edited,
+// redrawing that line after it. Other output, such as notifications arriving while a
+// line is entered, goes through it so it doesn't garble the line.
+func (e *LineEditor) Print(text string) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if !e.editing {
+		fmt.Fprint(e.out, text)
+		return
+	}
+	fmt.Fprintf(e.out, "\r\x1b[K%s", strings.ReplaceAll(text, "\n", "\r\n"))
+	e.redraw()
+}
+
+// redraw draws the prompt and line and puts the cursor in place. The caller holds mu.
+func (e *LineEditor) redraw() {
+	fmt.Fprintf(e.out, "\r%s%s\x1b[K", e.prompt, string(e.line))
+	if back := len(e.line) - e.pos; back > 0 {
+		fmt.Fprintf(e.out, "\x1b[%dD", back)
+	}
+}
+
+// edit reads keys until the line is entered
+func (e *LineEditor) edit(prompt string) (string, error) {
+	e.mu.Lock()
+	e.editing, e.prompt, e.line, e.pos = true, prompt, nil, 0
+	e.historyPos, e.pending, e.lastWasTab = len(e.History), "", false
+	e.redraw()
+	e.mu.Unlock()
+	defer func() {
+		e.mu.Lock()
+		e.editing = false
+		e.mu.Unlock()
+	}()
+
+	for {
+		r, _, err := e.reader.ReadRune()
+		if err != nil {
+			return "", err
+		}
+		seq := ""
+		if r == 27 {
+			seq = e.escape()
+		}
+		e.mu.Lock()
+		line, done, err := e.key(r, seq)
+		e.mu.Unlock()
+		if done {
+			return line, err
+		}
+	}
+}
+
+// key applies key r, or the escape sequence seq following ESC, to the line. It
+// reports whether the line is done: entered, or given up with an error. The caller
+// holds mu.
+func (e *LineEditor) key(r rune, seq string) (string, bool, error) {
+	setLine := func(s string) {
+		e.line = []rune(s)
+		e.pos = len(e.line)
+	}
+	tab := false
+	switch r {
+	case '\r', '\n':
+		fmt.Fprint(e.out, "\r\n")
+		return string(e.line), true, nil
+	case 3: // Ctrl-C
+		fmt.Fprint(e.out, "^C\r\n")
+		return "", true, errInterrupted
+	case 4: // Ctrl-D
+		if len(e.line) == 0 {
+			fmt.Fprint(e.out, "\r\n")
+			return "", true, io.EOF
+		}
+		if e.pos < len(e.line) {
+			e.line = append(e.line[:e.pos], e.line[e.pos+1:]...)
+		}
+	case 1: // Ctrl-A
+		e.pos = 0
+	case 5: // Ctrl-E
+		e.pos = len(e.line)
+	case 21: // Ctrl-U
+		e.line = e.line[e.pos:]
+		e.pos = 0
+	case 11: // Ctrl-K
+		e.line = e.line[:e.pos]
+	case 12: // Ctrl-L
+		fmt.Fprint(e.out, "\x1b[H\x1b[2J")
+	case 127, 8: // Backspace
+		if e.pos > 0 {
+			e.line = append(e.line[:e.pos-1], e.line[e.pos:]...)
+			e.pos--
+		}
+	case '\t':
+		tab = true
+		if e.Complete == nil {
+			break
+		}
+		line, pos := e.line, e.pos
+		start, candidates := e.Complete(string(line[:pos]))
+		start = utf8.RuneCountInString(string(line[:pos])[:start])
+		switch {
+		case len(candidates) == 1:
+			e.line = append(append(append([]rune{}, line[:start]...), []rune(candidates[0])...), line[pos:]...)
+			e.pos = start + utf8.RuneCountInString(candidates[0])
+		case len(candidates) > 1:
+			prefix := commonPrefix(candidates)
+			if typed := string(line[start:pos]); len(prefix) > len(typed) {
+				e.line = append(append(append([]rune{}, line[:start]...), []rune(prefix)...), line[pos:]...)
+				e.pos = start + utf8.RuneCountInString(prefix)
+			} else if e.lastWasTab {
+				fmt.Fprintf(e.out, "\r\n%s\r\n", strings.Join(candidates, "  "))
+			}
+		}
+	case 27: // Escape sequences: arrows, Home, End, Delete
+		switch seq {
+		case "[A", "OA": // Up
+			if e.historyPos > 0 {
+				if e.historyPos == len(e.History) {
+					e.pending = string(e.line)
+				}
+				e.historyPos--
+				setLine(e.History[e.historyPos])
+			}
+		case "[B", "OB": // Down
+			if e.historyPos < len(e.History) {
+				e.historyPos++
+				if e.historyPos == len(e.History) {
+					setLine(e.pending)
+				} else {
+					setLine(e.History[e.historyPos])
+				}
+			}
+		case "[C", "OC":
+			if e.pos < len(e.line) {
+				e.pos++
+			}
+		case "[D", "OD":
+			if e.pos > 0 {
+				e.pos--
+			}
+		case "[H", "OH", "[1~":
+			e.pos = 0
+		case "[F", "OF", "[4~":
+			e.pos = len(e.line)
+		case "[3~":
+			if e.pos < len(e.line) {
+				e.line = append(e.line[:e.pos], e.line[e.pos+1:]...)
+			}
+		}
+	default:
+		if r >= ' ' {
+			e.line = append(e.line[:e.pos], append([]rune{r}, e.line[e.pos:]...)...)
+			e.pos++
+		}
+	}
+	e.lastWasTab = tab
+	e.redraw()
+	return "", false, nil
+}
+
+// escape reads the rest of an escape sequence after ESC
+func (e *LineEditor) escape() string {
+	first, err := e.reader.ReadByte()
+	if err != nil || (first != '[' && first != 'O') {
+		return ""
+	}
+	seq := []byte{first}
+	for {
+		b, err := e.reader.ReadByte()
+		if err != nil {
+			return ""
+		}
+		seq = append(seq, b)
+		// Sequences end with a letter or ~
+		if (b >= 'A' && b <= 'Z') || (b >= 'a' && b <= 'z') || b == '~' {
+			return string(seq)
+		}
+	}
+}
+
+// stty runs stty on the editor's terminal
+func (e *LineEditor) stty(args ...string) (string, error) {
+	cmd := exec.Command("stty", args...)
+	cmd.Stdin = e.in
+	out, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("stty %s: %w", strings.Join(args, " "), err)
+	}
+	return string(out), nil
+}
+
+// commonPrefix returns the longest prefix shared by words
+func commonPrefix(words []string) string {
+	prefix := words[0]
+	for _, w := range words[1:] {
+		for !strings.HasPrefix(w, prefix) {
+			prefix = prefix[:len(prefix)-1]
+		}
+	}
+	return prefix
+}
@@ -1,42 +1,26 @@
 package main
 
 import (
-	"bufio"
 	"encoding/json"
+	"errors"
 	"flag"
 	"fmt"
-	"io"
 	"log"
+	"net/http"
 	"os"
-	"os/exec"
+	"os/signal"
+	"strings"
 	"time"
-)
-
-// MCP JSON-RPC message types
-type Request struct {
-	Jsonrpc string `json:"jsonrpc"`
-	ID      any    `json:"id"`
-	Method  string `json:"method"`
-	Params  any    `json:"params,omitempty"`
-}
-
-type Response struct {
-	Jsonrpc string `json:"jsonrpc"`
-	ID      any    `json:"id,omitempty"`
-	Result  any    `json:"result,omitempty"`
-	Error   *Error `json:"error,omitempty"`
-}
 
-type Error struct {
-	Code    int    `json:"code"`
-	Message string `json:"message"`
-	Data    any    `json:"data,omitempty"`
-}
+	"github.com/carlisia/mcp-factcheck/internal/jsonrpc"
+	"github.com/carlisia/mcp-factcheck/internal/secrets"
+)
 
 // Tool call parameters
 type CallToolParams struct {
 	Name      string         `json:"name"`
 	Arguments map[string]any `json:"arguments"`
+	Meta      map[string]any `json:"_meta,omitempty"`
 }
 
 func main() {
@@ -44,7 +28,12 @@ func main() {
 		serverCmd = flag.String("cmd", "./bin/mcp-factcheck-server", "Command to run MCP server")
 		dataDir   = flag.String("data-dir", "./embeddings", "Data directory for server")
 		timeout   = flag.Duration("timeout", 30*time.Second, "Request timeout")
+		framing   = flag.String("framing", "auto", "Message framing over stdio: newline, header (Content-Length, as in LSP), or auto to read either and write newline")
+		maxSize   = flag.Int("max-message-size", jsonrpc.DefaultMaxMessageSize, "Largest message accepted from the server, in bytes")
+		serverURL = flag.String("url", "", "Streamable HTTP endpoint of a running server, such as http://localhost:8080/mcp, instead of starting --cmd")
+		headers   = headerFlags{}
 	)
+	flag.Var(&headers, "header", `HTTP header sent to --url, as "Name: value" (repeatable)`)
 	flag.Parse()
 
 	if len(flag.Args()) == 0 {
@@ -56,18 +45,41 @@ func main() {
 		fmt.Fprintf(os.Stderr, "  resources/list                - List available resources\n")
 		fmt.Fprintf(os.Stderr, "  resources/read <uri>          - Read a resource\n")
 		fmt.Fprintf(os.Stderr, "  prompts/list                  - List available prompts\n")
+		fmt.Fprintf(os.Stderr, "  repl                          - Start an interactive session with one server\n")
+		fmt.Fprintf(os.Stderr, "  run <scenario.yaml>           - Run a scenario of calls and check their results\n")
 		fmt.Fprintf(os.Stderr, "\nExamples:\n")
 		fmt.Fprintf(os.Stderr, "  %s tools/list\n", os.Args[0])
 		fmt.Fprintf(os.Stderr, "  %s tools/call validate_content '{\"content\":\"MCP uses JSON-RPC\"}'\n", os.Args[0])
 		fmt.Fprintf(os.Stderr, "  %s tools/call search_spec '{\"query\":\"tools\",\"top_k\":3}'\n", os.Args[0])
 		fmt.Fprintf(os.Stderr, "  %s tools/call list_spec_versions '{}'\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "  %s repl\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "  %s run cmd/factcheck-curl/scenarios/smoke.yaml\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "  %s --url https://factcheck.example.com/mcp tools/list\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "\nWith --url, a bearer token is read from MCP_FACTCHECK_AUTH_TOKEN unless --header sets Authorization.\n")
 		os.Exit(1)
 	}
 
 	command := flag.Args()[0]
 	args := flag.Args()[1:]
 
-	client, err := NewMCPClient(*serverCmd, *dataDir, *timeout)
+	msgFraming, err := jsonrpc.ParseFraming(*framing)
+	if err != nil {
+		log.Fatalf("Invalid --framing: %v", err)
+	}
+	opts := ClientOptions{Timeout: *timeout, Framing: msgFraming, MaxMessageSize: *maxSize}
+
+	var client *MCPClient
+	if *serverURL != "" {
+		header := http.Header(headers)
+		if header.Get("Authorization") == "" {
+			if token, err := secrets.Lookup("MCP_FACTCHECK_AUTH_TOKEN"); err == nil {
+				header.Set("Authorization", "Bearer "+token)
+			}
+		}
+		client, err = NewHTTPMCPClient(*serverURL, header, opts)
+	} else {
+		client, err = NewMCPClient(*serverCmd, *dataDir, opts)
+	}
 	if err != nil {
 		log.Fatalf("Failed to create MCP client: %v", err)
 	}
@@ -92,6 +104,13 @@ func main() {
 		err = client.ReadResource(args[0])
 	case "prompts/list":
 		err = client.ListPrompts()
+	case "repl":
+		err = client.REPL(os.Stdin, os.Stdout)
+	case "run":
+		if len(args) < 1 {
+			log.Fatalf("run requires a scenario file")
+		}
+		err = client.RunScenario(args[0], os.Stdout)
 	default:
 		log.Fatalf("Unknown command: %s", command)
 	}
@@ -101,103 +120,241 @@ func main() {
 	}
 }
 
-type MCPClient struct {
-	cmd     *exec.Cmd
-	stdin   io.WriteCloser
-	stdout  *bufio.Scanner
-	timeout time.Duration
-	id      int
+// headerFlags collects repeated --header flags
+type headerFlags http.Header
+
+func (h *headerFlags) String() string {
+	return fmt.Sprint(http.Header(*h))
 }
 
-func NewMCPClient(serverCmd, dataDir string, timeout time.Duration) (*MCPClient, error) {
-	cmd := exec.Command(serverCmd, "--data-dir", dataDir)
-	
-	stdin, err := cmd.StdinPipe()
-	if err != nil {
-		return nil, fmt.Errorf("failed to create stdin pipe: %w", err)
+func (h *headerFlags) Set(value string) error {
+	name, val, ok := strings.Cut(value, ":")
+	if !ok || strings.TrimSpace(name) == "" {
+		return fmt.Errorf("header must be \"Name: value\", got %q", value)
 	}
+	http.Header(*h).Add(strings.TrimSpace(name), strings.TrimSpace(val))
+	return nil
+}
 
-	stdout, err := cmd.StdoutPipe()
-	if err != nil {
-		return nil, fmt.Errorf("failed to create stdout pipe: %w", err)
-	}
+// ClientOptions configures a client's connection to its server
+type ClientOptions struct {
+	Timeout        time.Duration   // How long to wait for each response
+	Framing        jsonrpc.Framing // Of stdio messages; responses are read in either framing with FramingAuto
+	MaxMessageSize int             // Bound on one message from the server, or jsonrpc.DefaultMaxMessageSize
+}
+
+// MCPClient talks to one server, a process over stdio or a running server over
+// Streamable HTTP. Each response the transport receives is handed to the request
+// waiting for it by ID, so requests needn't be answered in order; the server's
+// notifications are printed as they arrive, and its requests answered.
+type MCPClient struct {
+	transport transport
+	timeout   time.Duration
+
+	// Notify shows a notification or other message from the server; by default it
+	// writes to stderr
+	Notify func(text string)
+
+	calls *jsonrpc.Calls // Requests awaiting their response
+}
 
-	cmd.Stderr = os.Stderr
+// errCancelled is returned for requests given up on Ctrl-C
+var errCancelled = errors.New("request cancelled")
 
-	if err := cmd.Start(); err != nil {
-		return nil, fmt.Errorf("failed to start server: %w", err)
+// NewMCPClient starts the server command and connects to it over stdio
+func NewMCPClient(serverCmd, dataDir string, opts ClientOptions) (*MCPClient, error) {
+	client := newClient(opts.Timeout)
+	t, err := newStdioTransport(serverCmd, dataDir, opts, client.receive)
+	if err != nil {
+		return nil, err
 	}
+	return client.connect(t)
+}
 
-	client := &MCPClient{
-		cmd:     cmd,
-		stdin:   stdin,
-		stdout:  bufio.NewScanner(stdout),
+// NewHTTPMCPClient connects to a running server's Streamable HTTP endpoint, sending
+// headers, such as Authorization, with every request
+func NewHTTPMCPClient(url string, headers http.Header, opts ClientOptions) (*MCPClient, error) {
+	client := newClient(opts.Timeout)
+	return client.connect(newHTTPTransport(url, headers, opts, client.receive))
+}
+
+func newClient(timeout time.Duration) *MCPClient {
+	return &MCPClient{
 		timeout: timeout,
-		id:      1,
+		Notify:  func(text string) { fmt.Fprint(os.Stderr, text) },
+		calls:   jsonrpc.NewCalls(),
 	}
+}
 
-	// Initialize the connection
-	if err := client.Initialize(); err != nil {
-		client.Close()
+// connect initializes the session over t
+func (c *MCPClient) connect(t transport) (*MCPClient, error) {
+	c.transport = t
+	if err := c.Initialize(); err != nil {
+		c.Close()
 		return nil, fmt.Errorf("failed to initialize: %w", err)
 	}
-
-	return client, nil
+	return c, nil
 }
 
 func (c *MCPClient) Close() {
-	if c.stdin != nil {
-		c.stdin.Close()
+	if c.transport != nil {
+		c.transport.Close()
+	}
+}
+
+// receive handles one message or batch from the server, or the error of one that
+// couldn't be read
+func (c *MCPClient) receive(data []byte, err error) {
+	if err != nil {
+		c.Notify(fmt.Sprintf("[invalid message] %v\n", err))
+		return
 	}
-	if c.cmd != nil && c.cmd.Process != nil {
-		c.cmd.Process.Kill()
-		c.cmd.Wait()
+	msgs, _, err := jsonrpc.Decode(data)
+	if err != nil {
+		c.Notify(fmt.Sprintf("[invalid message] %v: %s\n", err, data))
+		return
+	}
+	for _, msg := range msgs {
+		switch {
+		case msg.IsRequest():
+			c.answer(msg)
+		case msg.IsNotification():
+			c.Notify(formatNotification(msg.Method, msg.Params))
+		default:
+			// Responses to requests given up on, which the server may still send, are dropped
+			c.calls.Deliver(msg)
+		}
 	}
 }
 
-func (c *MCPClient) sendRequest(method string, params any) (*Response, error) {
-	req := Request{
-		Jsonrpc: "2.0",
-		ID:      c.id,
-		Method:  method,
-		Params:  params,
+// answer responds to a request from the server. The client declares the roots
+// capability and has no roots to share; sampling and elicitation aren't supported.
+func (c *MCPClient) answer(req *jsonrpc.Message) {
+	var resp *jsonrpc.Message
+	switch req.Method {
+	case "ping":
+		resp, _ = jsonrpc.NewResponse(req.ID, map[string]any{})
+	case "roots/list":
+		resp, _ = jsonrpc.NewResponse(req.ID, map[string]any{"roots": []any{}})
+	default:
+		c.Notify(fmt.Sprintf("[%s] server request not supported\n", req.Method))
+		resp = jsonrpc.NewErrorResponse(req.ID, jsonrpc.NewError(jsonrpc.CodeMethodNotFound, "method not supported by factcheck-curl: %s", req.Method))
+	}
+	if err := c.write(resp); err != nil {
+		c.Notify(fmt.Sprintf("[%s] failed to answer: %v\n", req.Method, err))
 	}
-	c.id++
+}
 
-	reqData, err := json.Marshal(req)
+// write sends one message to the server
+func (c *MCPClient) write(msg *jsonrpc.Message) error {
+	data, err := json.Marshal(msg)
 	if err != nil {
-		return nil, fmt.Errorf("failed to marshal request: %w", err)
+		return fmt.Errorf("failed to marshal message: %w", err)
 	}
+	return c.transport.Send(data)
+}
 
-	if _, err := c.stdin.Write(append(reqData, '\n')); err != nil {
-		return nil, fmt.Errorf("failed to write request: %w", err)
+// notify sends a notification to the server
+func (c *MCPClient) notify(method string, params any) error {
+	msg, err := jsonrpc.NewNotification(method, params)
+	if err != nil {
+		return err
 	}
+	return c.write(msg)
+}
 
-	// Read response with timeout
-	responseChan := make(chan string, 1)
-	go func() {
-		if c.stdout.Scan() {
-			responseChan <- c.stdout.Text()
-		} else {
-			responseChan <- ""
-		}
-	}()
+func (c *MCPClient) sendRequest(method string, params any) (*jsonrpc.Message, error) {
+	id, responses := c.calls.Start()
 
-	select {
-	case responseText := <-responseChan:
-		if responseText == "" {
-			return nil, fmt.Errorf("no response received")
-		}
+	// Tool calls ask for progress, which the server reports for long validations
+	if call, ok := params.(CallToolParams); ok {
+		call.Meta = map[string]any{"progressToken": id}
+		params = call
+	}
+	req, err := jsonrpc.NewRequest(id, method, params)
+	if err == nil {
+		err = c.write(req)
+	}
+	if err != nil {
+		c.calls.Forget(id)
+		return nil, err
+	}
 
-		var resp Response
-		if err := json.Unmarshal([]byte(responseText), &resp); err != nil {
-			return nil, fmt.Errorf("failed to unmarshal response: %w", err)
-		}
+	// Ctrl-C gives up on the request rather than ending the session
+	interrupt := make(chan os.Signal, 1)
+	signal.Notify(interrupt, os.Interrupt)
+	defer signal.Stop(interrupt)
 
-		return &resp, nil
-	case <-time.After(c.timeout):
+	timer := time.NewTimer(c.timeout)
+	defer timer.Stop()
+	select {
+	case resp := <-responses:
+		return resp, nil
+	case <-c.transport.Done():
+		c.calls.Forget(id)
+		return nil, fmt.Errorf("no response received: server closed the connection")
+	case <-timer.C:
+		c.cancel(id, method, fmt.Sprintf("timed out after %s", c.timeout))
 		return nil, fmt.Errorf("request timeout")
+	case <-interrupt:
+		c.cancel(id, method, "interrupted by the user")
+		return nil, errCancelled
+	}
+}
+
+// cancel gives up on request id and tells the server with notifications/cancelled, so
+// it can stop the work. initialize must not be cancelled; the client just stops waiting.
+func (c *MCPClient) cancel(id jsonrpc.ID, method, reason string) {
+	c.calls.Forget(id)
+	if method == "initialize" {
+		return
+	}
+	c.notify("notifications/cancelled", map[string]any{"requestId": id, "reason": reason})
+}
+
+// formatNotification renders a notification from the server as a line: progress and
+// log messages readably, others with their raw params
+func formatNotification(method string, params json.RawMessage) string {
+	switch method {
+	case "notifications/progress":
+		var p struct {
+			Progress float64  `json:"progress"`
+			Total    *float64 `json:"total"`
+			Message  string   `json:"message"`
+		}
+		if json.Unmarshal(params, &p) == nil {
+			progress := fmt.Sprintf("%g", p.Progress)
+			if p.Total != nil {
+				progress += fmt.Sprintf("/%g", *p.Total)
+			}
+			return fmt.Sprintf("[progress] %s %s\n", progress, p.Message)
+		}
+	case "notifications/message":
+		var p struct {
+			Level  string         `json:"level"`
+			Logger string         `json:"logger"`
+			Data   map[string]any `json:"data"`
+		}
+		if json.Unmarshal(params, &p) == nil && p.Data != nil {
+			message, _ := p.Data["message"].(string)
+			delete(p.Data, "message")
+			text := fmt.Sprintf("[%s] %s", p.Level, message)
+			if len(p.Data) > 0 {
+				fields, _ := json.Marshal(p.Data)
+				text += " " + string(fields)
+			}
+			return text + "\n"
+		}
+	case "notifications/cancelled":
+		var p struct {
+			RequestID any    `json:"requestId"`
+			Reason    string `json:"reason"`
+		}
+		if json.Unmarshal(params, &p) == nil {
+			return fmt.Sprintf("[cancelled] server cancelled request %v: %s\n", p.RequestID, p.Reason)
+		}
 	}
+	return fmt.Sprintf("[%s] %s\n", method, params)
 }
 
 func (c *MCPClient) Initialize() error {
@@ -223,15 +380,18 @@ func (c *MCPClient) Initialize() error {
 		return fmt.Errorf("initialize error: %s", resp.Error.Message)
 	}
 
-	// Send initialized notification
-	initReq := Request{
-		Jsonrpc: "2.0",
-		Method:  "initialized",
+	// Streamable HTTP requests name the negotiated revision in a header
+	if t, ok := c.transport.(interface{ SetProtocolVersion(string) }); ok {
+		var result struct {
+			ProtocolVersion string `json:"protocolVersion"`
+		}
+		if json.Unmarshal(resp.Result, &result) == nil {
+			t.SetProtocolVersion(result.ProtocolVersion)
+		}
 	}
-	initData, _ := json.Marshal(initReq)
-	c.stdin.Write(append(initData, '\n'))
 
-	return nil
+	// Send initialized notification
+	return c.notify("notifications/initialized", nil)
 }
 
 func (c *MCPClient) ListTools() error {
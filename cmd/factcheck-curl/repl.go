@@ -0,0 +1,379 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/carlisia/mcp-factcheck/internal/jsonrpc"
+)
+
+// Prompts of the REPL: for a new command, and for the lines continuing a JSON literal
+const (
+	replPrompt         = "factcheck> "
+	continuationPrompt = "       ... "
+)
+
+// historyFile holds REPL history across sessions, in the user's home directory
+const historyFile = ".factcheck-curl_history"
+
+// replCommands are the REPL's own commands and the methods it completes
+var replCommands = []string{
+	"help", "history", "exit", "quit",
+	"tools/list", "tools/call", "resources/list", "resources/templates/list", "resources/read",
+	"prompts/list", "logging/setLevel", "ping",
+}
+
+const replHelp = `Commands:
+  <tool> [json]                 Call a tool, e.g. search_spec {"query": "tools"}
+  tools/call <tool> [json]      The same, spelled out
+  resources/read <uri>          Read a resource
+  <method> [json]               Send any request, e.g. logging/setLevel {"level": "info"}
+  history                       Show the commands entered
+  help                          Show this help
+  exit, quit, Ctrl-D            Leave
+
+JSON spanning several lines is read until its braces and brackets close. Tab completes
+commands, tool names, and argument keys; Up and Down browse history.`
+
+// REPL runs an interactive session with the client's server, which stays up between
+// commands
+func (c *MCPClient) REPL(in *os.File, out io.Writer) error {
+	tools, err := c.toolArguments()
+	if err != nil {
+		return err
+	}
+
+	editor := NewLineEditor(in, out)
+	historyPath := ""
+	if home, err := os.UserHomeDir(); err == nil {
+		historyPath = filepath.Join(home, historyFile)
+		editor.History = loadHistory(historyPath)
+	}
+
+	if editor.Terminal() {
+		// Notifications can arrive while a command is typed
+		c.Notify = editor.Print
+		fmt.Fprintf(out, "Connected to the MCP server with %d tools. Type help for commands.\n", len(tools))
+	}
+	for {
+		input, err := readCommand(editor, tools)
+		if errors.Is(err, errInterrupted) {
+			continue
+		}
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		input = strings.TrimSpace(input)
+		if input == "" {
+			continue
+		}
+
+		// History keeps multi-line commands on one line, which JSON allows
+		entry := strings.Join(strings.Fields(input), " ")
+		editor.AddHistory(entry)
+		appendHistory(historyPath, entry)
+
+		switch input {
+		case "exit", "quit":
+			return nil
+		case "help":
+			fmt.Fprintln(out, replHelp)
+			continue
+		case "history":
+			for i, line := range editor.History {
+				fmt.Fprintf(out, "%4d  %s\n", i+1, line)
+			}
+			continue
+		}
+
+		method, params, err := parseCommand(input, tools)
+		if err != nil {
+			fmt.Fprintf(out, "error: %v\n", err)
+			continue
+		}
+		resp, err := c.sendRequest(method, params)
+		if err != nil {
+			fmt.Fprintf(out, "error: %v\n", err)
+			continue
+		}
+		printResponse(out, method, resp)
+	}
+}
+
+// toolArguments returns the argument names of each of the server's tools
+func (c *MCPClient) toolArguments() (map[string][]string, error) {
+	resp, err := c.sendRequest("tools/list", nil)
+	if err != nil {
+		return nil, err
+	}
+	if resp.Error != nil {
+		return nil, fmt.Errorf("tools/list error: %s", resp.Error.Message)
+	}
+	var result struct {
+		Tools []struct {
+			Name        string `json:"name"`
+			InputSchema struct {
+				Properties map[string]any `json:"properties"`
+			} `json:"inputSchema"`
+		} `json:"tools"`
+	}
+	if err := json.Unmarshal(resp.Result, &result); err != nil {
+		return nil, fmt.Errorf("failed to parse tools/list result: %w", err)
+	}
+	tools := make(map[string][]string, len(result.Tools))
+	for _, tool := range result.Tools {
+		args := make([]string, 0, len(tool.InputSchema.Properties))
+		for name := range tool.InputSchema.Properties {
+			args = append(args, name)
+		}
+		sort.Strings(args)
+		tools[tool.Name] = args
+	}
+	return tools, nil
+}
+
+// readCommand reads one command, continuing onto more lines while a JSON literal in
+// it is still open
+func readCommand(editor *LineEditor, tools map[string][]string) (string, error) {
+	var input strings.Builder
+	prompt := replPrompt
+	for {
+		previous := input.String()
+		editor.Complete = func(line string) (int, []string) {
+			start, candidates := complete(previous+line, tools)
+			return max(start-len(previous), 0), candidates
+		}
+		line, err := editor.ReadLine(prompt)
+		if err != nil {
+			return "", err
+		}
+		input.WriteString(line)
+		if jsonOpen(input.String()) == 0 {
+			return input.String(), nil
+		}
+		input.WriteString("\n")
+		prompt = continuationPrompt
+	}
+}
+
+// jsonOpen returns how many braces and brackets are still open in s, outside strings
+func jsonOpen(s string) int {
+	depth := 0
+	inString, escaped := false, false
+	for _, r := range s {
+		switch {
+		case escaped:
+			escaped = false
+		case inString && r == '\\':
+			escaped = true
+		case r == '"':
+			inString = !inString
+		case inString:
+		case r == '{' || r == '[':
+			depth++
+		case r == '}' || r == ']':
+			depth--
+		}
+	}
+	return depth
+}
+
+// parseCommand turns a command into the method and params of a request
+func parseCommand(input string, tools map[string][]string) (string, any, error) {
+	name, rest, _ := strings.Cut(input, " ")
+	rest = strings.TrimSpace(rest)
+
+	if _, ok := tools[name]; ok {
+		return toolCall(name, rest)
+	}
+	if !strings.Contains(name, "/") && name != "ping" {
+		return "", nil, fmt.Errorf("unknown command or tool %q; type help for commands", name)
+	}
+	switch {
+	case name == "tools/call":
+		tool, args, _ := strings.Cut(rest, " ")
+		if tool == "" {
+			return "", nil, fmt.Errorf("tools/call needs a tool name")
+		}
+		return toolCall(tool, strings.TrimSpace(args))
+	case name == "resources/read" && rest != "" && !strings.HasPrefix(rest, "{"):
+		return name, map[string]any{"uri": rest}, nil
+	case rest == "":
+		return name, nil, nil
+	}
+	var params any
+	if err := json.Unmarshal([]byte(rest), &params); err != nil {
+		return "", nil, fmt.Errorf("invalid params: %w", err)
+	}
+	return name, params, nil
+}
+
+// toolCall returns a tools/call request for tool with argsJSON, or no arguments
+func toolCall(tool, argsJSON string) (string, any, error) {
+	args := map[string]any{}
+	if argsJSON != "" {
+		if err := json.Unmarshal([]byte(argsJSON), &args); err != nil {
+			return "", nil, fmt.Errorf("invalid arguments: %w", err)
+		}
+	}
+	return "tools/call", CallToolParams{Name: tool, Arguments: args}, nil
+}
+
+// complete returns completions for the word ending input: a command or tool name
+// first, then a tool name after tools/call, then the tool's argument keys inside its
+// JSON arguments
+func complete(input string, tools map[string][]string) (int, []string) {
+	start := strings.LastIndexAny(input, " \t\n{,\"") + 1
+	word := input[start:]
+
+	fields := strings.Fields(input)
+	typingFirst := len(fields) == 0 || !strings.ContainsAny(input, " \t\n")
+	switch {
+	case typingFirst:
+		names := append(slices.Clone(replCommands), toolNames(tools)...)
+		return start, withPrefix(names, word)
+	case fields[0] == "tools/call" && (len(fields) == 1 || (len(fields) == 2 && start > 0 && input[start-1] == ' ' && word != "")):
+		return start, withPrefix(toolNames(tools), word)
+	}
+
+	tool := fields[0]
+	if tool == "tools/call" && len(fields) > 1 {
+		tool = fields[1]
+	}
+	args, ok := tools[tool]
+	if !ok || !strings.Contains(input, "{") {
+		return start, nil
+	}
+	quoted := start > 0 && input[start-1] == '"'
+	var candidates []string
+	for _, arg := range withPrefix(args, word) {
+		if quoted {
+			candidates = append(candidates, arg+`": `)
+		} else {
+			candidates = append(candidates, `"`+arg+`": `)
+		}
+	}
+	return start, candidates
+}
+
+// toolNames returns the names of tools, sorted
+func toolNames(tools map[string][]string) []string {
+	names := make([]string, 0, len(tools))
+	for name := range tools {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// withPrefix returns the words starting with prefix
+func withPrefix(words []string, prefix string) []string {
+	var matches []string
+	for _, w := range words {
+		if strings.HasPrefix(w, prefix) {
+			matches = append(matches, w)
+		}
+	}
+	return matches
+}
+
+// printResponse pretty-prints a response. Tool results and resource contents are
+// shown block by block, with JSON text indented, rather than as the escaped JSON of the
+// whole result.
+func printResponse(out io.Writer, method string, resp *jsonrpc.Message) {
+	if resp.Error != nil {
+		fmt.Fprintf(out, "error %d: %s\n", resp.Error.Code, resp.Error.Message)
+		return
+	}
+	type block struct {
+		Type string `json:"type"`
+		URI  string `json:"uri"`
+		Text string `json:"text"`
+	}
+	var result struct {
+		Content  []block `json:"content"`  // tools/call
+		Contents []block `json:"contents"` // resources/read
+		IsError  bool    `json:"isError"`
+	}
+	if (method == "tools/call" || method == "resources/read") && json.Unmarshal(resp.Result, &result) == nil {
+		blocks := append(result.Content, result.Contents...)
+		if result.IsError {
+			fmt.Fprintln(out, "tool error:")
+		}
+		for _, b := range blocks {
+			switch {
+			case b.URI != "":
+				fmt.Fprintf(out, "%s:\n", b.URI)
+			case b.Type != "text":
+				fmt.Fprintf(out, "[%s content]\n", b.Type)
+				continue
+			}
+			fmt.Fprintln(out, strings.TrimRight(indentJSON(b.Text), "\n"))
+		}
+		if len(blocks) > 0 {
+			return
+		}
+	}
+	output, _ := json.MarshalIndent(resp.Result, "", "  ")
+	fmt.Fprintln(out, string(output))
+}
+
+// indentJSON indents text that is a JSON object or array, and returns other text as is
+func indentJSON(text string) string {
+	trimmed := strings.TrimSpace(text)
+	if !strings.HasPrefix(trimmed, "{") && !strings.HasPrefix(trimmed, "[") {
+		return text
+	}
+	var value any
+	if err := json.Unmarshal([]byte(trimmed), &value); err != nil {
+		return text
+	}
+	output, _ := json.MarshalIndent(value, "", "  ")
+	return string(output)
+}
+
+// loadHistory returns the last lines of the history file, if there is one
+func loadHistory(path string) []string {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil
+	}
+	defer f.Close()
+	var lines []string
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 64*1024), jsonrpc.DefaultMaxMessageSize)
+	for scanner.Scan() {
+		if line := scanner.Text(); line != "" {
+			lines = append(lines, line)
+		}
+	}
+	if len(lines) > maxHistory {
+		lines = lines[len(lines)-maxHistory:]
+	}
+	return lines
+}
+
+// appendHistory adds a line to the history file, ignoring failures: history is a
+// convenience
+func appendHistory(path, line string) {
+	if path == "" {
+		return
+	}
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+	if err != nil {
+		return
+	}
+	defer f.Close()
+	fmt.Fprintln(f, line)
+}
@@ -0,0 +1,295 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"sigs.k8s.io/yaml"
+)
+
+// Scenario is a sequence of MCP calls with the results each must produce, read from a
+// YAML or JSON file by the run command
+type Scenario struct {
+	Name  string `json:"name"`
+	Steps []Step `json:"steps"`
+}
+
+// Step is one call of a scenario: a tool with arguments, or any method with params
+type Step struct {
+	Name      string         `json:"name"`
+	Tool      string         `json:"tool,omitempty"`
+	Arguments map[string]any `json:"arguments,omitempty"`
+	Method    string         `json:"method,omitempty"`
+	Params    any            `json:"params,omitempty"`
+	Expect    Expect         `json:"expect"`
+}
+
+// Expected step outcomes
+const (
+	StatusOK        = "ok"         // A result that isn't a tool error
+	StatusError     = "error"      // A JSON-RPC error
+	StatusToolError = "tool_error" // A tool result with isError set
+)
+
+// Expect lists what a step's response must satisfy. Text checks apply to the text
+// content of tool results and to the JSON of other results or of errors.
+type Expect struct {
+	Status      string      `json:"status,omitempty"` // Default StatusOK
+	Contains    []string    `json:"contains,omitempty"`
+	NotContains []string    `json:"not_contains,omitempty"`
+	JSON        []JSONCheck `json:"json,omitempty"`
+}
+
+// JSONCheck asserts on the value at Path in the result, or in the error when the
+// response is one. Paths are dotted, with indexes in brackets: content[0].text,
+// structuredContent.findings[1].severity.
+type JSONCheck struct {
+	Path     string `json:"path"`
+	Equals   any    `json:"equals,omitempty"`
+	Contains string `json:"contains,omitempty"`
+	Exists   *bool  `json:"exists,omitempty"`
+}
+
+// LoadScenario reads a scenario file
+func LoadScenario(path string) (*Scenario, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read scenario: %w", err)
+	}
+	var scenario Scenario
+	if err := yaml.Unmarshal(data, &scenario); err != nil {
+		return nil, fmt.Errorf("failed to parse scenario %s: %w", path, err)
+	}
+	if len(scenario.Steps) == 0 {
+		return nil, fmt.Errorf("scenario %s has no steps", path)
+	}
+	for i, step := range scenario.Steps {
+		if (step.Tool == "") == (step.Method == "") {
+			return nil, fmt.Errorf("step %d of %s needs one of tool or method", i+1, path)
+		}
+		switch step.Expect.Status {
+		case "", StatusOK, StatusError, StatusToolError:
+		default:
+			return nil, fmt.Errorf("step %d of %s: unknown status %q", i+1, path, step.Expect.Status)
+		}
+	}
+	if scenario.Name == "" {
+		scenario.Name = path
+	}
+	return &scenario, nil
+}
+
+// RunScenario runs every step of the scenario at path, reporting each to out, and
+// returns an error if any failed. Steps run in order in one session, so later steps
+// see the state earlier ones left, such as the log level.
+func (c *MCPClient) RunScenario(path string, out io.Writer) error {
+	scenario, err := LoadScenario(path)
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(out, "Scenario: %s\n", scenario.Name)
+	failed := 0
+	for i, step := range scenario.Steps {
+		name := step.Name
+		if name == "" {
+			name = fmt.Sprintf("step %d", i+1)
+		}
+		start := time.Now()
+		failures := c.runStep(step)
+		elapsed := time.Since(start).Round(time.Millisecond)
+		if len(failures) == 0 {
+			fmt.Fprintf(out, "PASS  %s (%s)\n", name, elapsed)
+			continue
+		}
+		failed++
+		fmt.Fprintf(out, "FAIL  %s (%s)\n", name, elapsed)
+		for _, failure := range failures {
+			fmt.Fprintf(out, "      %s\n", failure)
+		}
+	}
+
+	fmt.Fprintf(out, "%d passed, %d failed\n", len(scenario.Steps)-failed, failed)
+	if failed > 0 {
+		return fmt.Errorf("%d of %d steps failed", failed, len(scenario.Steps))
+	}
+	return nil
+}
+
+// runStep sends a step's request and returns how its response fails the step's
+// expectations
+func (c *MCPClient) runStep(step Step) []string {
+	method, params := step.Method, step.Params
+	if step.Tool != "" {
+		args := step.Arguments
+		if args == nil {
+			args = map[string]any{}
+		}
+		method, params = "tools/call", CallToolParams{Name: step.Tool, Arguments: args}
+	}
+	resp, err := c.sendRequest(method, params)
+	if err != nil {
+		return []string{err.Error()}
+	}
+
+	// Results compare as JSON values
+	var result map[string]any
+	raw := []byte(resp.Result)
+	json.Unmarshal(raw, &result)
+
+	// Servers that can't send structuredContent yet send it as a final JSON text block,
+	// as the spec recommends for backwards compatibility; checks find it either way
+	if _, ok := result["structuredContent"]; !ok && method == "tools/call" {
+		if blocks, _ := result["content"].([]any); len(blocks) > 0 {
+			if last, ok := blocks[len(blocks)-1].(map[string]any); ok {
+				var structured map[string]any
+				if text, ok := last["text"].(string); ok && json.Unmarshal([]byte(text), &structured) == nil {
+					result["structuredContent"] = structured
+				}
+			}
+		}
+	}
+
+	status := StatusOK
+	var subject any = result
+	switch {
+	case resp.Error != nil:
+		status = StatusError
+		raw, _ = json.Marshal(resp.Error)
+		subject = map[string]any{"code": resp.Error.Code, "message": resp.Error.Message, "data": resp.Error.Data}
+	case result["isError"] == true:
+		status = StatusToolError
+	}
+
+	var failures []string
+	want := step.Expect.Status
+	if want == "" {
+		want = StatusOK
+	}
+	if status != want {
+		failures = append(failures, fmt.Sprintf("status: got %s, want %s: %s", status, want, truncate(string(raw), 200)))
+	}
+
+	text := string(raw)
+	if method == "tools/call" && resp.Error == nil {
+		text = resultText(result)
+	}
+	for _, s := range step.Expect.Contains {
+		if !strings.Contains(text, s) {
+			failures = append(failures, fmt.Sprintf("contains: %q not found", s))
+		}
+	}
+	for _, s := range step.Expect.NotContains {
+		if strings.Contains(text, s) {
+			failures = append(failures, fmt.Sprintf("not_contains: %q found", s))
+		}
+	}
+	for _, check := range step.Expect.JSON {
+		if failure := check.Check(subject); failure != "" {
+			failures = append(failures, failure)
+		}
+	}
+	return failures
+}
+
+// Check returns why the value at the check's path in v fails it, or ""
+func (check JSONCheck) Check(v any) string {
+	value, found := lookupPath(v, check.Path)
+	if check.Exists != nil && found != *check.Exists {
+		if found {
+			return fmt.Sprintf("json %s: exists, want absent", check.Path)
+		}
+		return fmt.Sprintf("json %s: absent, want present", check.Path)
+	}
+	if check.Equals == nil && check.Contains == "" {
+		return ""
+	}
+	if !found {
+		return fmt.Sprintf("json %s: absent", check.Path)
+	}
+	if check.Equals != nil && !jsonEqual(value, check.Equals) {
+		got, _ := json.Marshal(value)
+		want, _ := json.Marshal(check.Equals)
+		return fmt.Sprintf("json %s: got %s, want %s", check.Path, truncate(string(got), 200), want)
+	}
+	if check.Contains != "" {
+		s, ok := value.(string)
+		if !ok {
+			b, _ := json.Marshal(value)
+			s = string(b)
+		}
+		if !strings.Contains(s, check.Contains) {
+			return fmt.Sprintf("json %s: %q not found in %s", check.Path, check.Contains, truncate(s, 200))
+		}
+	}
+	return ""
+}
+
+// lookupPath returns the value at a dotted path with bracketed indexes in v, and
+// whether there is one
+func lookupPath(v any, path string) (any, bool) {
+	path = strings.TrimPrefix(strings.TrimPrefix(path, "$"), ".")
+	if path == "" {
+		return v, true
+	}
+	for _, part := range strings.Split(strings.ReplaceAll(path, "[", ".["), ".") {
+		if part == "" {
+			continue
+		}
+		if index, ok := strings.CutPrefix(part, "["); ok {
+			n, err := strconv.Atoi(strings.TrimSuffix(index, "]"))
+			list, isList := v.([]any)
+			if err != nil || !isList || n < 0 || n >= len(list) {
+				return nil, false
+			}
+			v = list[n]
+			continue
+		}
+		object, ok := v.(map[string]any)
+		if !ok {
+			return nil, false
+		}
+		if v, ok = object[part]; !ok {
+			return nil, false
+		}
+	}
+	return v, true
+}
+
+// jsonEqual compares values as JSON, so 3 from YAML equals 3.0 from a response
+func jsonEqual(a, b any) bool {
+	var x, y any
+	ra, _ := json.Marshal(a)
+	rb, _ := json.Marshal(b)
+	json.Unmarshal(ra, &x)
+	json.Unmarshal(rb, &y)
+	return reflect.DeepEqual(x, y)
+}
+
+// resultText joins the text content blocks of a tool result
+func resultText(result map[string]any) string {
+	blocks, _ := result["content"].([]any)
+	var texts []string
+	for _, block := range blocks {
+		if b, ok := block.(map[string]any); ok {
+			if text, ok := b["text"].(string); ok {
+				texts = append(texts, text)
+			}
+		}
+	}
+	return strings.Join(texts, "\n")
+}
+
+// truncate shortens s to about n bytes for failure messages
+func truncate(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	return s[:n] + "..."
+}
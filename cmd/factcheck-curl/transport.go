@@ -0,0 +1,275 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"os"
+	"os/exec"
+	"strings"
+	"sync"
+
+	"github.com/carlisia/mcp-factcheck/internal/jsonrpc"
+)
+
+// transport carries JSON-RPC messages between the client and a server. Messages from
+// the server are passed to the receive function the transport was created with, in the
+// order they arrive, along with the errors of messages that couldn't be read, such as
+// ones over the size limit.
+type transport interface {
+	// Send delivers one message to the server
+	Send(data []byte) error
+	// Done is closed when the server can send no more messages
+	Done() <-chan struct{}
+	Close() error
+}
+
+// stdioTransport runs the server as a child process and exchanges framed messages
+// over its stdin and stdout
+type stdioTransport struct {
+	cmd    *exec.Cmd
+	stdin  io.WriteCloser
+	writer *jsonrpc.Writer
+	done   chan struct{}
+}
+
+func newStdioTransport(serverCmd, dataDir string, opts ClientOptions, receive func([]byte, error)) (*stdioTransport, error) {
+	cmd := exec.Command(serverCmd, "--data-dir", dataDir)
+
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return nil, fmt.Errorf("failed to create stdin pipe: %w", err)
+	}
+
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, fmt.Errorf("failed to create stdout pipe: %w", err)
+	}
+
+	cmd.Stderr = os.Stderr
+
+	if err := cmd.Start(); err != nil {
+		return nil, fmt.Errorf("failed to start server: %w", err)
+	}
+
+	t := &stdioTransport{cmd: cmd, stdin: stdin, writer: jsonrpc.NewWriter(stdin, opts.Framing), done: make(chan struct{})}
+	// One reader for the whole session, so a request that times out doesn't leave a
+	// reader behind to swallow the next response
+	go func() {
+		defer close(t.done)
+		reader := jsonrpc.NewReader(stdout, opts.Framing, opts.MaxMessageSize)
+		for {
+			msg, err := reader.Read()
+			if errors.Is(err, jsonrpc.ErrTooLarge) {
+				receive(nil, err)
+				continue
+			}
+			if err != nil {
+				// Close closes stdout, which ends reading as the server exiting does
+				if err != io.EOF && !errors.Is(err, os.ErrClosed) {
+					receive(nil, err)
+				}
+				return
+			}
+			receive(msg, nil)
+		}
+	}()
+	return t, nil
+}
+
+func (t *stdioTransport) Send(data []byte) error {
+	if err := t.writer.Write(data); err != nil {
+		return fmt.Errorf("failed to write message: %w", err)
+	}
+	return nil
+}
+
+func (t *stdioTransport) Done() <-chan struct{} {
+	return t.done
+}
+
+func (t *stdioTransport) Close() error {
+	t.stdin.Close()
+	if t.cmd.Process != nil {
+		t.cmd.Process.Kill()
+		t.cmd.Wait()
+	}
+	return nil
+}
+
+// Streamable HTTP headers
+const (
+	headerSessionID       = "Mcp-Session-Id"
+	headerProtocolVersion = "MCP-Protocol-Version"
+)
+
+// httpTransport talks to a running server's Streamable HTTP endpoint. Each message is
+// POSTed; the server answers a request with a JSON response, or with an SSE stream
+// carrying the request's notifications before its response, which is read in the
+// background so notifications show as they arrive.
+type httpTransport struct {
+	url     string
+	headers http.Header // Sent with every request, such as Authorization
+	maxSize int
+	receive func([]byte, error)
+	client  *http.Client
+	ctx     context.Context
+	cancel  context.CancelFunc
+	done    chan struct{}
+
+	mu              sync.Mutex
+	sessionID       string // Assigned by the server in its initialize response
+	protocolVersion string // Negotiated at initialization
+}
+
+func newHTTPTransport(url string, headers http.Header, opts ClientOptions, receive func([]byte, error)) *httpTransport {
+	ctx, cancel := context.WithCancel(context.Background())
+	maxSize := opts.MaxMessageSize
+	if maxSize <= 0 {
+		maxSize = jsonrpc.DefaultMaxMessageSize
+	}
+	return &httpTransport{
+		url:     url,
+		headers: headers,
+		maxSize: maxSize,
+		receive: receive,
+		// No client timeout: responses stream for as long as a tool call runs, and
+		// the client's request timeout gives up on them
+		client: &http.Client{},
+		ctx:    ctx,
+		cancel: cancel,
+		done:   make(chan struct{}),
+	}
+}
+
+// SetProtocolVersion sets the revision sent in the MCP-Protocol-Version header, as
+// servers of revision 2025-06-18 expect after initialization
+func (t *httpTransport) SetProtocolVersion(version string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.protocolVersion = version
+}
+
+func (t *httpTransport) Send(data []byte) error {
+	req, err := t.request(http.MethodPost, bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json, text/event-stream")
+
+	resp, err := t.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send message: %w", err)
+	}
+	if id := resp.Header.Get(headerSessionID); id != "" {
+		t.mu.Lock()
+		t.sessionID = id
+		t.mu.Unlock()
+	}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		defer resp.Body.Close()
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		return fmt.Errorf("server returned %s: %s", resp.Status, strings.TrimSpace(string(body)))
+	}
+
+	mediaType, _, _ := mime.ParseMediaType(resp.Header.Get("Content-Type"))
+	switch {
+	case resp.StatusCode == http.StatusAccepted: // Notifications and responses have no reply
+		resp.Body.Close()
+	case mediaType == "text/event-stream":
+		go func() {
+			defer resp.Body.Close()
+			if err := readEvents(resp.Body, t.maxSize, t.receive); err != nil {
+				t.receive(nil, fmt.Errorf("failed to read event stream: %w", err))
+			}
+		}()
+	default:
+		defer resp.Body.Close()
+		body, err := io.ReadAll(io.LimitReader(resp.Body, int64(t.maxSize)+1))
+		if err != nil {
+			return fmt.Errorf("failed to read response: %w", err)
+		}
+		if len(body) > t.maxSize {
+			return fmt.Errorf("%w: response over %d bytes", jsonrpc.ErrTooLarge, t.maxSize)
+		}
+		// A single message or a batch, which the client splits
+		if body = bytes.TrimSpace(body); len(body) > 0 {
+			t.receive(body, nil)
+		}
+	}
+	return nil
+}
+
+// readEvents passes on the data of each event in an SSE stream, whose lines are at
+// most maxSize bytes
+func readEvents(r io.Reader, maxSize int, receive func([]byte, error)) error {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), maxSize)
+	var data []byte
+	for scanner.Scan() {
+		line := scanner.Text()
+		switch {
+		case line == "": // End of the event
+			if len(data) > 0 {
+				receive(data, nil)
+			}
+			data = nil
+		case strings.HasPrefix(line, "data:"):
+			if data != nil {
+				data = append(data, '\n')
+			}
+			data = append(data, strings.TrimPrefix(strings.TrimPrefix(line, "data:"), " ")...)
+		}
+	}
+	if len(data) > 0 {
+		receive(data, nil)
+	}
+	return scanner.Err()
+}
+
+func (t *httpTransport) Done() <-chan struct{} {
+	return t.done
+}
+
+// Close ends the session on the server, which frees its state there
+func (t *httpTransport) Close() error {
+	t.mu.Lock()
+	sessionID := t.sessionID
+	t.mu.Unlock()
+	if sessionID != "" {
+		if req, err := t.request(http.MethodDelete, nil); err == nil {
+			if resp, err := t.client.Do(req); err == nil {
+				resp.Body.Close()
+			}
+		}
+	}
+	t.cancel()
+	close(t.done)
+	return nil
+}
+
+// request returns an HTTP request to the endpoint with the session's headers
+func (t *httpTransport) request(method string, body io.Reader) (*http.Request, error) {
+	req, err := http.NewRequestWithContext(t.ctx, method, t.url, body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	for name, values := range t.headers {
+		req.Header[name] = values
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.sessionID != "" {
+		req.Header.Set(headerSessionID, t.sessionID)
+	}
+	if t.protocolVersion != "" {
+		req.Header.Set(headerProtocolVersion, t.protocolVersion)
+	}
+	return req, nil
+}
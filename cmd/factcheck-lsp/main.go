@@ -0,0 +1,69 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/carlisia/mcp-factcheck/embedding"
+	"github.com/carlisia/mcp-factcheck/internal/bootstrap"
+	"github.com/carlisia/mcp-factcheck/internal/config"
+	mcpembedding "github.com/carlisia/mcp-factcheck/internal/embedding"
+	"github.com/carlisia/mcp-factcheck/internal/lsp"
+	"github.com/carlisia/mcp-factcheck/internal/specs"
+	"github.com/carlisia/mcp-factcheck/internal/version"
+	"github.com/carlisia/mcp-factcheck/pkg/logger"
+	"github.com/joho/godotenv"
+)
+
+func main() {
+	// Load .env file if it exists
+	_ = godotenv.Load()
+
+	// Logs go to stderr; stdout carries the LSP stream
+	if err := logger.Initialize(logger.IsDevMode()); err != nil {
+		log.Fatalf("Failed to initialize logger: %v", err)
+	}
+	defer logger.Sync()
+
+	dataDir := flag.String("data-dir", "", "Directory containing vector database (default: $XDG_DATA_HOME/mcp-factcheck/embeddings)")
+	specVersion := flag.String("spec-version", specs.DefaultSpecVersion, "MCP spec version to validate against")
+	debounce := flag.Duration("debounce", lsp.DefaultDebounce, "Delay after the last edit before a document is validated")
+	flag.Bool("stdio", true, "Communicate over stdin/stdout (the only supported transport)")
+	showVersion := flag.Bool("version", false, "Print build information and exit")
+	flag.Parse()
+
+	if *showVersion {
+		fmt.Println("factcheck-lsp", version.ServerVersion())
+		return
+	}
+
+	if !specs.IsValidSpecVersion(*specVersion) {
+		log.Fatalf("Invalid spec version: %s. Valid versions: %v", *specVersion, specs.ValidSpecVersions)
+	}
+
+	absDataDir, err := config.ResolveDataDir(context.Background(), *dataDir, bootstrap.FromEnv())
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	generator, err := embedding.NewGenerator()
+	if err != nil {
+		log.Fatalf("Failed to create embedding generator: %v", err)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	server := lsp.NewServer(mcpembedding.NewVectorDB(absDataDir), generator, lsp.Config{
+		SpecVersion: *specVersion,
+		Debounce:    *debounce,
+	})
+	if err := server.Serve(ctx, os.Stdin, os.Stdout); err != nil {
+		log.Fatalf("Language server error: %v", err)
+	}
+}
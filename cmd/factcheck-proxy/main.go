@@ -0,0 +1,131 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"os/exec"
+	"os/signal"
+	"path/filepath"
+	"syscall"
+	"time"
+
+	"github.com/carlisia/mcp-factcheck/internal/config"
+	"github.com/carlisia/mcp-factcheck/internal/proxy"
+	"github.com/carlisia/mcp-factcheck/internal/specs"
+	"github.com/carlisia/mcp-factcheck/internal/version"
+)
+
+func main() {
+	// stdout carries the session to the client, so everything else goes to stderr
+	log.SetFlags(0)
+	log.SetPrefix("factcheck-proxy: ")
+
+	flag.Usage = func() {
+		fmt.Fprintln(os.Stderr, "Usage: factcheck-proxy [flags] -- <server command> [args...]")
+		fmt.Fprintln(os.Stderr, "\nRelays an MCP stdio session, recording and validating every message.")
+		fmt.Fprintln(os.Stderr, "\nFlags:")
+		flag.PrintDefaults()
+	}
+	defaultRecord := filepath.Join(config.StateDir(), "proxy", time.Now().UTC().Format("20060102T150405Z")+".jsonl")
+	record := flag.String("record", defaultRecord, "JSON Lines file to record the session to; empty records nothing")
+	specVersion := flag.String("spec-version", "", "MCP spec version to validate against (default: the version the server negotiates)")
+	verbose := flag.Bool("verbose", false, "Report every message, not only those with violations")
+	maxMessageSize := flag.Int("max-message-size", 0, "Largest message relayed, in bytes (default 16 MB)")
+	showVersion := flag.Bool("version", false, "Print build information and exit")
+	flag.Parse()
+
+	if *showVersion {
+		fmt.Println("factcheck-proxy", version.ServerVersion())
+		return
+	}
+
+	if flag.NArg() == 0 {
+		flag.Usage()
+		os.Exit(2)
+	}
+	if *specVersion != "" && !specs.IsValidSpecVersion(*specVersion) {
+		log.Fatalf("Invalid spec version: %s. Valid versions: %v", *specVersion, specs.ValidSpecVersions)
+	}
+
+	cfg := proxy.Config{
+		SpecVersion:    *specVersion,
+		Report:         os.Stderr,
+		Verbose:        *verbose,
+		MaxMessageSize: *maxMessageSize,
+	}
+	if *record != "" {
+		if err := os.MkdirAll(filepath.Dir(*record), 0o755); err != nil {
+			log.Fatalf("Failed to create record directory: %v", err)
+		}
+		f, err := os.Create(*record)
+		if err != nil {
+			log.Fatalf("Failed to create record: %v", err)
+		}
+		cfg.Record = f
+	}
+
+	code := run(flag.Args(), cfg, *record)
+	if f, ok := cfg.Record.(*os.File); ok {
+		f.Close()
+	}
+	os.Exit(code)
+}
+
+// run relays a session with the server command and returns the exit code to exit with
+func run(args []string, cfg proxy.Config, record string) int {
+	cmd := exec.Command(args[0], args[1:]...)
+	cmd.Stderr = os.Stderr
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		log.Printf("Failed to create stdin pipe: %v", err)
+		return 1
+	}
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		log.Printf("Failed to create stdout pipe: %v", err)
+		return 1
+	}
+	if err := cmd.Start(); err != nil {
+		log.Printf("Failed to start server: %v", err)
+		return 1
+	}
+
+	// The server shares the terminal's process group, but a client that runs the proxy
+	// may signal only the proxy, so signals are passed on
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+	go func() {
+		for sig := range signals {
+			cmd.Process.Signal(sig)
+		}
+	}()
+
+	p := proxy.New(cfg)
+	if err := p.Run(os.Stdin, os.Stdout, stdin, stdout); err != nil && !errors.Is(err, io.ErrClosedPipe) {
+		log.Print(err)
+	}
+
+	code := 0
+	if err := cmd.Wait(); err != nil {
+		var exitErr *exec.ExitError
+		if !errors.As(err, &exitErr) {
+			log.Printf("Server failed: %v", err)
+			return 1
+		}
+		// A server killed by a signal has no exit code
+		if code = exitErr.ExitCode(); code < 0 {
+			code = 1
+		}
+	}
+
+	stats := p.Stats()
+	log.Printf("%d messages, %d invalid, %d with warnings", stats.Messages, stats.Invalid, stats.Warnings)
+	if record != "" {
+		log.Printf("Recorded to %s", record)
+	}
+	return code
+}
@@ -0,0 +1,127 @@
+package main
+
+import (
+	"crypto/subtle"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+
+	"github.com/carlisia/mcp-factcheck/internal/auth"
+	"github.com/carlisia/mcp-factcheck/internal/config"
+	"github.com/carlisia/mcp-factcheck/internal/diagnostics"
+	"github.com/carlisia/mcp-factcheck/internal/feedback"
+	"github.com/carlisia/mcp-factcheck/internal/restapi"
+	"github.com/carlisia/mcp-factcheck/internal/secrets"
+	"github.com/carlisia/mcp-factcheck/internal/specs"
+	"github.com/carlisia/mcp-factcheck/pkg/sitecheck"
+	"github.com/spf13/cobra"
+)
+
+var apiCmd = &cobra.Command{
+	Use:   "api",
+	Short: "Serve the REST API and the editor and docs-build endpoints",
+	Long: `Serve HTTP endpoints for editor and docs generator plugins:
+
+  POST /diagnostics  validate a document and return findings anchored to zero-based
+                     line/character ranges (file, range, severity, message, fix)
+  POST /site/check   validate a docs page at build time and return it with a warning
+                     banner injected, plus whether the severity policy fails the build
+  POST /feedback     mark a finding as correct or a false positive
+
+  POST /v1/validate       validate content or code and return the structured result
+  GET  /v1/spec/versions  list spec versions and documentation corpora
+  POST /v1/spec/search    search the spec, a page of results at a time
+  GET  /v1/openapi.json   OpenAPI 3.1 description of the /v1 endpoints
+
+If the config file has an auth section, requests must send one of its API keys
+("X-API-Key: <key>" or "Authorization: Bearer <key>") or an OAuth access token, and
+each caller is rate limited. Otherwise, if FACTCHECK_API_TOKEN is set (env var, *_FILE,
+/run/secrets, or the macOS Keychain), requests must send it as "Authorization: Bearer <token>".`,
+	RunE: runAPI,
+}
+
+var (
+	apiAddr        string
+	apiDataDir     string
+	apiSpecVersion string
+	apiFeedback    string
+	apiConfig      string
+	apiPolicy      = sitecheck.DefaultPolicy()
+)
+
+func init() {
+	apiCmd.Flags().StringVar(&apiAddr, "addr", "127.0.0.1:8787", "Address to listen on")
+	apiCmd.Flags().StringVar(&apiDataDir, "data-dir", "", "Directory containing vector database (default: $XDG_DATA_HOME/mcp-factcheck/embeddings)")
+	apiCmd.Flags().StringVar(&apiSpecVersion, "spec-version", specs.DefaultSpecVersion, "Default MCP spec version when a request doesn't set one")
+	apiCmd.Flags().StringVar(&apiFeedback, "feedback-file", feedback.DefaultPath(), "JSONL file where feedback on findings is recorded")
+	apiCmd.Flags().StringVar(&apiConfig, "config", config.DefaultConfigPath(), "Config file whose auth section guards the endpoints")
+	addPolicyFlags(apiCmd, &apiPolicy)
+}
+
+func runAPI(cmd *cobra.Command, args []string) error {
+	if !specs.IsValidSpecVersion(apiSpecVersion) {
+		return fmt.Errorf("invalid spec version: %s. Valid versions: %v", apiSpecVersion, specs.ValidSpecVersions)
+	}
+	if err := apiPolicy.Validate(); err != nil {
+		return err
+	}
+
+	// The token is optional; the default address only listens on loopback
+	token, _ := secrets.Lookup("FACTCHECK_API_TOKEN")
+	guard := func(next http.Handler) http.Handler { return requireToken(token, next) }
+	var authenticator *auth.Authenticator
+	if apiConfig != "" {
+		cfg, err := config.Load(apiConfig)
+		if err != nil {
+			return err
+		}
+		if cfg.Auth.Enabled() {
+			if authenticator, err = auth.New(cfg.Auth); err != nil {
+				return fmt.Errorf("failed to configure auth: %w", err)
+			}
+			guard = authenticator.Middleware
+		}
+	}
+
+	vectorDB, generator, err := newBackend(apiDataDir)
+	if err != nil {
+		return err
+	}
+	checker, err := sitecheck.NewChecker(vectorDB, generator, apiSpecVersion, apiPolicy)
+	if err != nil {
+		return err
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle("POST /diagnostics", guard(diagnostics.NewHandler(vectorDB, generator, apiSpecVersion)))
+	mux.Handle("POST /site/check", guard(sitecheck.NewHandler(checker)))
+	mux.Handle("POST /feedback", guard(feedback.NewHandler(feedback.NewStore(apiFeedback))))
+	mux.Handle(restapi.Prefix, guard(restapi.NewHandler(vectorDB, generator, apiSpecVersion)))
+	if authenticator != nil && authenticator.ProtectedResourceHandler() != nil {
+		mux.Handle("GET "+auth.ProtectedResourcePath, authenticator.ProtectedResourceHandler())
+	}
+	mux.HandleFunc("GET /healthz", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+
+	log.Printf("API listening on %s (spec %s)", apiAddr, apiSpecVersion)
+	return http.ListenAndServe(apiAddr, mux)
+}
+
+// requireToken rejects requests without the bearer token; an empty token disables the check
+func requireToken(token string, next http.Handler) http.Handler {
+	if token == "" {
+		return next
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		auth := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if subtle.ConstantTimeCompare([]byte(auth), []byte(token)) != 1 {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusUnauthorized)
+			fmt.Fprintln(w, `{"error":"missing or invalid bearer token"}`)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
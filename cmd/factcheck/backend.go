@@ -0,0 +1,26 @@
+package main
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/carlisia/mcp-factcheck/embedding"
+	"github.com/carlisia/mcp-factcheck/internal/bootstrap"
+	"github.com/carlisia/mcp-factcheck/internal/config"
+	mcpembedding "github.com/carlisia/mcp-factcheck/internal/embedding"
+)
+
+// newBackend resolves the data directory and creates the vector database and embedding generator
+func newBackend(dataDir string) (*mcpembedding.VectorDB, *embedding.Generator, error) {
+	absDataDir, err := config.ResolveDataDir(context.Background(), dataDir, bootstrap.FromEnv())
+	if err != nil {
+		return nil, nil, err
+	}
+
+	generator, err := embedding.NewGenerator()
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to create embedding generator: %w", err)
+	}
+
+	return mcpembedding.NewVectorDB(absDataDir), generator, nil
+}
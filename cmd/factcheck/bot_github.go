@@ -0,0 +1,73 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+
+	"github.com/carlisia/mcp-factcheck/internal/githubbot"
+	"github.com/carlisia/mcp-factcheck/internal/secrets"
+	"github.com/carlisia/mcp-factcheck/internal/specs"
+	"github.com/google/go-github/v57/github"
+	"github.com/spf13/cobra"
+)
+
+var githubBotCmd = &cobra.Command{
+	Use:   "github",
+	Short: "Review pull requests that change markdown docs",
+	Long: `Receive GitHub pull_request webhooks, validate changed markdown files against the
+configured MCP spec version, and post review comments with spec citations.
+
+Requires GITHUB_TOKEN (with pull request write access) and GITHUB_WEBHOOK_SECRET,
+resolved like other secrets (env var, *_FILE, /run/secrets, or the macOS Keychain).`,
+	RunE: runGitHubBot,
+}
+
+var (
+	githubBotAddr        string
+	githubBotDataDir     string
+	githubBotSpecVersion string
+	githubBotMaxFiles    int
+)
+
+func init() {
+	githubBotCmd.Flags().StringVar(&githubBotAddr, "addr", ":8080", "Address to listen on for webhooks")
+	githubBotCmd.Flags().StringVar(&githubBotDataDir, "data-dir", "", "Directory containing vector database (default: $XDG_DATA_HOME/mcp-factcheck/embeddings)")
+	githubBotCmd.Flags().StringVar(&githubBotSpecVersion, "spec-version", specs.DefaultSpecVersion, "MCP spec version to validate against")
+	githubBotCmd.Flags().IntVar(&githubBotMaxFiles, "max-files", 20, "Maximum markdown files reviewed per pull request")
+}
+
+func runGitHubBot(cmd *cobra.Command, args []string) error {
+	if !specs.IsValidSpecVersion(githubBotSpecVersion) {
+		return fmt.Errorf("invalid spec version: %s. Valid versions: %v", githubBotSpecVersion, specs.ValidSpecVersions)
+	}
+
+	token, err := secrets.Lookup("GITHUB_TOKEN")
+	if err != nil {
+		return fmt.Errorf("GITHUB_TOKEN is required: provide it via %s", secrets.Sources("GITHUB_TOKEN"))
+	}
+	webhookSecret, err := secrets.Lookup("GITHUB_WEBHOOK_SECRET")
+	if err != nil {
+		return fmt.Errorf("GITHUB_WEBHOOK_SECRET is required: provide it via %s", secrets.Sources("GITHUB_WEBHOOK_SECRET"))
+	}
+
+	vectorDB, generator, err := newBackend(githubBotDataDir)
+	if err != nil {
+		return err
+	}
+
+	bot := githubbot.New(github.NewClient(nil).WithAuthToken(token), vectorDB, generator, githubbot.Config{
+		SpecVersion:   githubBotSpecVersion,
+		WebhookSecret: webhookSecret,
+		MaxFiles:      githubBotMaxFiles,
+	})
+
+	mux := http.NewServeMux()
+	mux.Handle("POST /webhook", bot)
+	mux.HandleFunc("GET /healthz", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+
+	log.Printf("GitHub review bot listening on %s (spec %s)", githubBotAddr, githubBotSpecVersion)
+	return http.ListenAndServe(githubBotAddr, mux)
+}
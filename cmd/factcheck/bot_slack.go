@@ -0,0 +1,72 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+
+	"github.com/carlisia/mcp-factcheck/internal/secrets"
+	"github.com/carlisia/mcp-factcheck/internal/slackbot"
+	"github.com/carlisia/mcp-factcheck/internal/specs"
+	"github.com/spf13/cobra"
+)
+
+var slackBotCmd = &cobra.Command{
+	Use:   "slack",
+	Short: "Answer /factcheck slash commands in Slack",
+	Long: `Serve a Slack slash command: /factcheck <text or link>. The text (or the document
+behind the link) is validated against the configured MCP spec version and the bot
+replies in a thread with a severity-sorted summary and spec citations.
+
+Requires SLACK_SIGNING_SECRET. SLACK_BOT_TOKEN (chat:write scope) is needed for threaded
+replies; without it results are posted through the command's response URL instead.
+Secrets are resolved like others (env var, *_FILE, /run/secrets, or the macOS Keychain).`,
+	RunE: runSlackBot,
+}
+
+var (
+	slackBotAddr        string
+	slackBotDataDir     string
+	slackBotSpecVersion string
+)
+
+func init() {
+	slackBotCmd.Flags().StringVar(&slackBotAddr, "addr", ":8080", "Address to listen on for slash commands")
+	slackBotCmd.Flags().StringVar(&slackBotDataDir, "data-dir", "", "Directory containing vector database (default: $XDG_DATA_HOME/mcp-factcheck/embeddings)")
+	slackBotCmd.Flags().StringVar(&slackBotSpecVersion, "spec-version", specs.DefaultSpecVersion, "MCP spec version to validate against")
+}
+
+func runSlackBot(cmd *cobra.Command, args []string) error {
+	if !specs.IsValidSpecVersion(slackBotSpecVersion) {
+		return fmt.Errorf("invalid spec version: %s. Valid versions: %v", slackBotSpecVersion, specs.ValidSpecVersions)
+	}
+
+	signingSecret, err := secrets.Lookup("SLACK_SIGNING_SECRET")
+	if err != nil {
+		return fmt.Errorf("SLACK_SIGNING_SECRET is required: provide it via %s", secrets.Sources("SLACK_SIGNING_SECRET"))
+	}
+	botToken, err := secrets.Lookup("SLACK_BOT_TOKEN")
+	if err != nil {
+		log.Printf("SLACK_BOT_TOKEN not set; replies will not be threaded")
+	}
+
+	vectorDB, generator, err := newBackend(slackBotDataDir)
+	if err != nil {
+		return err
+	}
+
+	bot := slackbot.New(vectorDB, generator, slackbot.Config{
+		SpecVersion:   slackBotSpecVersion,
+		SigningSecret: signingSecret,
+		BotToken:      botToken,
+	})
+
+	mux := http.NewServeMux()
+	mux.Handle("POST /slack/command", bot)
+	mux.HandleFunc("GET /healthz", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+
+	log.Printf("Slack bot listening on %s (spec %s)", slackBotAddr, slackBotSpecVersion)
+	return http.ListenAndServe(slackBotAddr, mux)
+}
@@ -0,0 +1,165 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/carlisia/mcp-factcheck/internal/specs"
+	"github.com/carlisia/mcp-factcheck/pkg/report"
+	"github.com/carlisia/mcp-factcheck/pkg/sitecheck"
+	"github.com/carlisia/mcp-factcheck/pkg/validator"
+	"github.com/spf13/cobra"
+)
+
+var ciCmd = &cobra.Command{
+	Use:   "ci [file or directory]...",
+	Short: "Validate markdown in CI and fail on serious findings",
+	Long: `Validate markdown files against the MCP specification as a CI gate. Directories
+(default: the current directory) are searched recursively for .md, .mdx, and .markdown files.
+
+Files are validated in-process, which needs OPENAI_API_KEY and the embeddings, or with
+--server by calling a shared MCP server started with --transport=http (its bearer token is
+read from MCP_FACTCHECK_AUTH_TOKEN).
+
+The report is written in --format to --output or stdout, and a summary to stderr. On GitHub
+Actions each finding is also emitted as a workflow annotation. The command exits non-zero
+when any finding meets --fail-on, or when any file scores below --min-score on the 0-100
+accuracy rubric.`,
+	Example: `  factcheck ci --format sarif --output factcheck.sarif docs/
+  factcheck ci --server https://factcheck.internal:8443/mcp --fail-on warning
+  factcheck ci --fail-on none --min-score 80 docs/`,
+	RunE: runCI,
+}
+
+var (
+	ciDataDir     string
+	ciSpecVersion string
+	ciServer      string
+	ciFormat      string
+	ciOutput      string
+	ciFailOn      string
+	ciMinScore    int
+	ciAnnotations bool
+)
+
+func init() {
+	ciCmd.Flags().StringVar(&ciDataDir, "data-dir", "", "Directory containing vector database (default: $XDG_DATA_HOME/mcp-factcheck/embeddings)")
+	ciCmd.Flags().StringVar(&ciSpecVersion, "spec-version", specs.DefaultSpecVersion, "MCP spec version to validate against")
+	ciCmd.Flags().StringVar(&ciServer, "server", "", "Streamable HTTP URL of an MCP fact-check server to validate with instead of running locally")
+	ciCmd.Flags().StringVarP(&ciFormat, "format", "f", "json", "Report format: "+strings.Join(report.Formats, ", "))
+	ciCmd.Flags().StringVarP(&ciOutput, "output", "o", "", "Write the report to a file instead of stdout")
+	ciCmd.Flags().StringVar(&ciFailOn, "fail-on", validator.SeverityCritical, "Minimum severity that fails the run: critical, warning, suggestion, or none")
+	ciCmd.Flags().IntVar(&ciMinScore, "min-score", 0, "Lowest accuracy score (0-100) a file may have without failing the run; 0 disables the check")
+	ciCmd.Flags().BoolVar(&ciAnnotations, "github-annotations", os.Getenv("GITHUB_ACTIONS") == "true", "Emit GitHub Actions workflow annotations for each finding")
+}
+
+func runCI(cmd *cobra.Command, args []string) error {
+	if !specs.IsValidSpecVersion(ciSpecVersion) {
+		return fmt.Errorf("invalid spec version: %s. Valid versions: %v", ciSpecVersion, specs.ValidSpecVersions)
+	}
+	if err := sitecheck.ValidateThreshold(ciFailOn); err != nil {
+		return err
+	}
+	if ciMinScore < 0 || ciMinScore > 100 {
+		return fmt.Errorf("--min-score must be between 0 and 100, got %d", ciMinScore)
+	}
+	if _, err := report.Format(ciFormat, nil, ""); err != nil {
+		return err
+	}
+
+	if len(args) == 0 {
+		args = []string{"."}
+	}
+	files, err := markdownFiles(args)
+	if err != nil {
+		return err
+	}
+	if len(files) == 0 {
+		return fmt.Errorf("no markdown files found in %s", strings.Join(args, ", "))
+	}
+
+	var validate validateFunc
+	if ciServer != "" {
+		var closeClient func() error
+		validate, closeClient, err = remoteValidator(cmd.Context(), ciServer)
+		if err != nil {
+			return err
+		}
+		defer closeClient()
+	} else if validate, err = localValidator(ciDataDir); err != nil {
+		return err
+	}
+
+	docs, err := verifyFiles(cmd.Context(), files, ciSpecVersion, validate)
+	if err != nil {
+		return err
+	}
+	if err := writeReport(ciFormat, ciOutput, docs); err != nil {
+		return err
+	}
+
+	counts := map[string]int{}
+	failing := 0
+	var lowScores []string
+	for _, doc := range docs {
+		if ciMinScore > 0 && doc.Score != nil && doc.Score.Score < ciMinScore {
+			lowScores = append(lowScores, fmt.Sprintf("%s (%d, %s)", doc.Path, doc.Score.Score, doc.Score.Grade))
+		}
+		for _, f := range doc.Findings {
+			counts[f.Severity]++
+			if sitecheck.Meets(f.Severity, ciFailOn) {
+				failing++
+			}
+			if ciAnnotations {
+				fmt.Fprintln(os.Stderr, githubAnnotation(doc.Path, f))
+			}
+		}
+	}
+	fmt.Fprintf(os.Stderr, "factcheck: %d file(s) checked against MCP %s: %d critical, %d warning, %d suggestion\n",
+		len(docs), ciSpecVersion, counts[validator.SeverityCritical], counts[validator.SeverityWarning], counts[validator.SeveritySuggestion])
+
+	if failing > 0 {
+		return fmt.Errorf("%d finding(s) at or above %q", failing, ciFailOn)
+	}
+	if len(lowScores) > 0 {
+		return fmt.Errorf("%d file(s) scored below %d: %s", len(lowScores), ciMinScore, strings.Join(lowScores, ", "))
+	}
+	return nil
+}
+
+// githubAnnotation renders a finding as a GitHub Actions workflow command
+func githubAnnotation(path string, f validator.ValidationError) string {
+	level := "notice"
+	switch f.Severity {
+	case validator.SeverityCritical:
+		level = "error"
+	case validator.SeverityWarning:
+		level = "warning"
+	}
+
+	props := []string{"file=" + escapeProperty(strings.ReplaceAll(path, "\\", "/"))}
+	if f.LineNumber > 0 {
+		props = append(props, fmt.Sprintf("line=%d", f.LineNumber))
+		if f.EndLine > f.LineNumber {
+			props = append(props, fmt.Sprintf("endLine=%d", f.EndLine))
+		}
+	}
+	props = append(props, "title="+escapeProperty("MCP fact-check: "+f.Type))
+
+	message := f.Message
+	if f.SpecSection != "" {
+		message += " [spec: " + f.SpecSection + "]"
+	}
+	return fmt.Sprintf("::%s %s::%s", level, strings.Join(props, ","), escapeData(message))
+}
+
+// escapeData escapes a workflow command message
+func escapeData(s string) string {
+	return strings.NewReplacer("%", "%25", "\r", "%0D", "\n", "%0A").Replace(s)
+}
+
+// escapeProperty escapes a workflow command property value
+func escapeProperty(s string) string {
+	return strings.NewReplacer("%", "%25", "\r", "%0D", "\n", "%0A", ":", "%3A", ",", "%2C").Replace(s)
+}
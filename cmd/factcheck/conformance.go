@@ -0,0 +1,108 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/carlisia/mcp-factcheck/pkg/conformance"
+	"github.com/spf13/cobra"
+)
+
+var conformanceCmd = &cobra.Command{
+	Use:   "conformance [flags] -- <server command> [args...]",
+	Short: "Check an MCP server's conformance to the specification",
+	Long: `Launch an MCP server over stdio and run checks derived from the MCP specification:
+the initialize handshake and version negotiation, the capabilities the server declares,
+tools/list schema validity, the error codes it answers bad requests with, message framing,
+and shutdown. Every message the server sends is validated against the message schema.
+
+The server is started once for each --spec-version (default: every published version).
+The report is written in --format to --output or stdout. The command exits non-zero when
+the server supports none of the versions, or fails a check for one it supports.`,
+	Example: `  factcheck conformance -- npx -y @modelcontextprotocol/server-everything
+  factcheck conformance --spec-version 2025-06-18 --format json -- ./bin/my-server --stdio`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: runConformance,
+}
+
+var (
+	conformanceSpecVersions []string
+	conformanceTimeout      time.Duration
+	conformanceFormat       string
+	conformanceOutput       string
+)
+
+func init() {
+	conformanceCmd.Flags().StringSliceVar(&conformanceSpecVersions, "spec-version", nil, "MCP spec versions to check, repeatable or comma-separated (default: "+strings.Join(conformance.ReleasedVersions(), ", ")+")")
+	conformanceCmd.Flags().DurationVar(&conformanceTimeout, "timeout", conformance.DefaultTimeout, "Time to wait for each response, and for the server to exit at the end")
+	conformanceCmd.Flags().StringVarP(&conformanceFormat, "format", "f", "text", "Report format: text or json")
+	conformanceCmd.Flags().StringVarP(&conformanceOutput, "output", "o", "", "Write the report to a file instead of stdout")
+}
+
+func runConformance(cmd *cobra.Command, args []string) error {
+	if conformanceFormat != "text" && conformanceFormat != "json" {
+		return fmt.Errorf("unknown format %q: want text or json", conformanceFormat)
+	}
+
+	report, err := conformance.Run(cmd.Context(), conformance.Options{
+		Command:      args[0],
+		Args:         args[1:],
+		SpecVersions: conformanceSpecVersions,
+		Timeout:      conformanceTimeout,
+	})
+	if err != nil {
+		return err
+	}
+
+	out := io.Writer(os.Stdout)
+	if conformanceOutput != "" {
+		f, err := os.Create(conformanceOutput)
+		if err != nil {
+			return fmt.Errorf("failed to create report: %w", err)
+		}
+		defer f.Close()
+		out = f
+	}
+	if conformanceFormat == "json" {
+		encoder := json.NewEncoder(out)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(report); err != nil {
+			return err
+		}
+	} else {
+		writeConformanceText(out, report)
+	}
+
+	if !report.Conformant {
+		return fmt.Errorf("%s is not conformant", strings.Join(report.Command, " "))
+	}
+	return nil
+}
+
+// writeConformanceText writes each version's checks, one per line, with their violations
+func writeConformanceText(w io.Writer, report *conformance.Report) {
+	for i, vr := range report.Versions {
+		if i > 0 {
+			fmt.Fprintln(w)
+		}
+		switch {
+		case vr.Supported:
+			fmt.Fprintf(w, "MCP %s:\n", vr.SpecVersion)
+		case vr.NegotiatedVersion != "":
+			fmt.Fprintf(w, "MCP %s: not supported (server offered %s)\n", vr.SpecVersion, vr.NegotiatedVersion)
+		default:
+			fmt.Fprintf(w, "MCP %s: no session\n", vr.SpecVersion)
+		}
+		for _, check := range vr.Checks {
+			fmt.Fprintf(w, "  %-4s  %-28s %s\n", strings.ToUpper(check.Status), check.ID, check.Message)
+			for _, v := range check.Violations {
+				fmt.Fprintf(w, "        %s at %s: %s\n", v.Severity, v.Path, v.Message)
+			}
+		}
+		fmt.Fprintf(w, "  %d passed, %d failed, %d warnings, %d skipped\n", vr.Summary.Passed, vr.Summary.Failed, vr.Summary.Warnings, vr.Summary.Skipped)
+	}
+}
@@ -0,0 +1,99 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/carlisia/mcp-factcheck/internal/eval"
+	"github.com/carlisia/mcp-factcheck/internal/feedback"
+	"github.com/spf13/cobra"
+)
+
+var evalCmd = &cobra.Command{
+	Use:   "eval",
+	Short: "Build evaluation datasets and run golden regression suites",
+}
+
+var evalExportCmd = &cobra.Command{
+	Use:   "export",
+	Short: "Export feedback and recorded interactions as a JSONL eval dataset",
+	Long: `Combine user feedback (report_feedback / POST /feedback) and tool interactions recorded by
+the debug store into one JSON Lines dataset. Each line holds the validated input, the label
+("issue", "no_issue", or "unlabeled" for interactions nobody reviewed), and what the server
+observed (finding type, severity, cited section, confidence, raw response).
+
+Run the same dataset against two releases to compare retrieval and verdict quality, or
+commit it as a regression suite.`,
+	Example: `  factcheck eval export --output eval/2025-06-18.jsonl
+  factcheck eval export --labeled-only --since 2025-07-01`,
+	RunE: runEvalExport,
+}
+
+var (
+	evalFeedbackFile     string
+	evalInteractionsFile string
+	evalOutput           string
+	evalSince            string
+	evalLabeledOnly      bool
+)
+
+func init() {
+	evalExportCmd.Flags().StringVar(&evalFeedbackFile, "feedback-file", feedback.DefaultPath(), "JSONL file holding collected feedback")
+	evalExportCmd.Flags().StringVar(&evalInteractionsFile, "interactions", eval.DefaultInteractionsPath(), "JSONL file of interactions recorded by the server (--interactions-file)")
+	evalExportCmd.Flags().StringVarP(&evalOutput, "output", "o", "", "Write the dataset to a file instead of stdout")
+	evalExportCmd.Flags().StringVar(&evalSince, "since", "", "Only export records on or after this date (YYYY-MM-DD or RFC 3339)")
+	evalExportCmd.Flags().BoolVar(&evalLabeledOnly, "labeled-only", false, "Only export records with user feedback")
+	evalCmd.AddCommand(evalExportCmd)
+}
+
+func runEvalExport(cmd *cobra.Command, args []string) error {
+	var opts eval.Options
+	opts.LabeledOnly = evalLabeledOnly
+	if evalSince != "" {
+		since, err := parseSince(evalSince)
+		if err != nil {
+			return err
+		}
+		opts.Since = since
+	}
+
+	entries, err := feedback.NewStore(evalFeedbackFile).All()
+	if err != nil {
+		return err
+	}
+	interactions, err := eval.ReadInteractions(evalInteractionsFile)
+	if err != nil {
+		return err
+	}
+
+	records := eval.Build(entries, interactions, opts)
+
+	var w io.Writer = os.Stdout
+	if evalOutput != "" {
+		f, err := os.Create(evalOutput)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		w = f
+	}
+	if err := eval.Write(w, records); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(os.Stderr, "Exported %d records (%d feedback entries, %d interactions)\n", len(records), len(entries), len(interactions))
+	return nil
+}
+
+func parseSince(value string) (time.Time, error) {
+	if t, err := time.Parse(time.DateOnly, value); err == nil {
+		return t, nil
+	}
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid --since %q: use YYYY-MM-DD or RFC 3339", value)
+	}
+	return t, nil
+}
@@ -0,0 +1,138 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/carlisia/mcp-factcheck/internal/eval"
+	"github.com/carlisia/mcp-factcheck/internal/specs"
+	"github.com/carlisia/mcp-factcheck/pkg/validator"
+	"github.com/spf13/cobra"
+)
+
+var evalRunCmd = &cobra.Command{
+	Use:   "run <fixture directory>...",
+	Short: "Run golden fixtures through the pipeline and report quality regressions",
+	Long: `Validate each markdown fixture and compare the result with the golden file next to it
+(intro.md is compared with intro.md.golden.json). A golden holds the expected verdict
+and, optionally, the confidence and the number of findings by severity:
+
+  {"valid": true, "confidence": 0.82, "findings": {"warning": 1}}
+
+Changed verdicts, confidence drifts beyond --tolerance, and changed finding counts are
+reported as regressions, and the command fails if there are any. Fixtures without a
+golden fail too, so new fixtures aren't silently skipped.
+
+Run it before and after a retrieval or chunking change; once the differences are
+intended, --update rewrites the goldens from the current results.`,
+	Example: `  factcheck eval run testdata/golden
+  factcheck eval run --update testdata/golden`,
+	Args:         cobra.MinimumNArgs(1),
+	RunE:         runEvalRun,
+	SilenceUsage: true,
+}
+
+var (
+	evalRunDataDir     string
+	evalRunSpecVersion string
+	evalRunTolerance   float64
+	evalRunUpdate      bool
+)
+
+func init() {
+	evalRunCmd.Flags().StringVar(&evalRunDataDir, "data-dir", "", "Directory containing vector database (default: $XDG_DATA_HOME/mcp-factcheck/embeddings)")
+	evalRunCmd.Flags().StringVar(&evalRunSpecVersion, "spec-version", specs.DefaultSpecVersion, "MCP spec version for fixtures whose golden doesn't name one")
+	evalRunCmd.Flags().Float64Var(&evalRunTolerance, "tolerance", 0.05, "Largest confidence drift that isn't a regression")
+	evalRunCmd.Flags().BoolVar(&evalRunUpdate, "update", false, "Rewrite the goldens from the current results instead of comparing")
+	evalCmd.AddCommand(evalRunCmd)
+}
+
+func runEvalRun(cmd *cobra.Command, args []string) error {
+	if !specs.IsValidSpecVersion(evalRunSpecVersion) {
+		return fmt.Errorf("invalid spec version: %s. Valid versions: %v", evalRunSpecVersion, specs.ValidSpecVersions)
+	}
+	if evalRunTolerance < 0 || evalRunTolerance > 1 {
+		return fmt.Errorf("--tolerance must be between 0 and 1, got %v", evalRunTolerance)
+	}
+
+	files, err := markdownFiles(args)
+	if err != nil {
+		return err
+	}
+	if len(files) == 0 {
+		return fmt.Errorf("no markdown fixtures found in %s", strings.Join(args, ", "))
+	}
+
+	vectorDB, generator, err := newBackend(evalRunDataDir)
+	if err != nil {
+		return err
+	}
+
+	out := cmd.OutOrStdout()
+	regressed, missing := 0, 0
+	for _, file := range files {
+		goldenPath := file + eval.GoldenSuffix
+		want, ok, err := eval.ReadGolden(goldenPath)
+		if err != nil {
+			return err
+		}
+		specVersion := want.SpecVersion
+		if specVersion == "" {
+			specVersion = evalRunSpecVersion
+		}
+		if !specs.IsValidSpecVersion(specVersion) {
+			return fmt.Errorf("%s: invalid spec version: %s", goldenPath, specVersion)
+		}
+
+		content, err := os.ReadFile(file)
+		if err != nil {
+			return fmt.Errorf("failed to read %s: %w", file, err)
+		}
+		result, err := validator.Validate(cmd.Context(), vectorDB, generator, validator.Request{
+			Content:     string(content),
+			SpecVersion: specVersion,
+			Chunked:     true,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to validate %s: %w", file, err)
+		}
+		got := eval.Observe(result)
+
+		if evalRunUpdate {
+			if ok && want.SpecVersion == "" {
+				got.SpecVersion = ""
+			}
+			if err := eval.WriteGolden(goldenPath, got); err != nil {
+				return err
+			}
+			fmt.Fprintf(out, "UPDATED  %s\n", file)
+			continue
+		}
+		if !ok {
+			missing++
+			fmt.Fprintf(out, "MISSING  %s: no %s (run with --update to create it)\n", file, goldenPath)
+			continue
+		}
+		regressions := eval.Compare(want, got, evalRunTolerance)
+		if len(regressions) == 0 {
+			fmt.Fprintf(out, "ok       %s\n", file)
+			continue
+		}
+		regressed++
+		fmt.Fprintf(out, "REGRESS  %s\n", file)
+		for _, r := range regressions {
+			fmt.Fprintf(out, "         %s: %s\n", r.Kind, r.Message)
+		}
+	}
+
+	if evalRunUpdate {
+		fmt.Fprintf(os.Stderr, "Updated %d golden(s)\n", len(files))
+		return nil
+	}
+	fmt.Fprintf(os.Stderr, "%d fixture(s): %d ok, %d regressed, %d missing a golden\n", len(files), len(files)-regressed-missing, regressed, missing)
+	if regressed > 0 || missing > 0 {
+		return fmt.Errorf("%d fixture(s) regressed, %d missing a golden", regressed, missing)
+	}
+	return nil
+}
@@ -0,0 +1,68 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/carlisia/mcp-factcheck/internal/config"
+	"github.com/carlisia/mcp-factcheck/internal/feedback"
+	"github.com/spf13/cobra"
+)
+
+var feedbackCmd = &cobra.Command{
+	Use:   "feedback",
+	Short: "Work with feedback collected on findings",
+}
+
+var feedbackCalibrateCmd = &cobra.Command{
+	Use:   "calibrate",
+	Short: "Suggest validator thresholds from collected feedback",
+	Long: `Read feedback recorded by the report_feedback tool and POST /feedback, and suggest the
+similarity threshold that best separates confirmed findings from false positives.
+The output includes a validator settings block that can be pasted into the config file.`,
+	RunE: runFeedbackCalibrate,
+}
+
+var (
+	feedbackFile   string
+	feedbackConfig string
+)
+
+func init() {
+	feedbackCmd.PersistentFlags().StringVar(&feedbackFile, "feedback-file", feedback.DefaultPath(), "JSONL file holding collected feedback")
+	feedbackCalibrateCmd.Flags().StringVar(&feedbackConfig, "config", config.DefaultConfigPath(), "Config file with the current validator settings")
+	feedbackCmd.AddCommand(feedbackCalibrateCmd)
+}
+
+func runFeedbackCalibrate(cmd *cobra.Command, args []string) error {
+	current := config.Default()
+	if feedbackConfig != "" {
+		cfg, err := config.Load(feedbackConfig)
+		if err != nil {
+			return err
+		}
+		current = cfg
+	}
+
+	entries, err := feedback.NewStore(feedbackFile).All()
+	if err != nil {
+		return err
+	}
+	if len(entries) == 0 {
+		return fmt.Errorf("no feedback recorded in %s yet", feedbackFile)
+	}
+
+	calibration := feedback.Calibrate(entries, current.Validator)
+	if !calibration.Reliable {
+		fmt.Fprintf(os.Stderr, "Warning: only %d scored entries (%d correct, %d false positive); collect at least %d with both verdicts before changing thresholds\n",
+			calibration.Scored, calibration.Correct, calibration.FalsePositives, feedback.MinSamples)
+	}
+
+	out, err := json.MarshalIndent(calibration, "", "  ")
+	if err != nil {
+		return err
+	}
+	fmt.Println(string(out))
+	return nil
+}
@@ -0,0 +1,53 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/carlisia/mcp-factcheck/pkg/logger"
+	"github.com/joho/godotenv"
+	"github.com/spf13/cobra"
+)
+
+var rootCmd = &cobra.Command{
+	Use:   "factcheck",
+	Short: "Fact-check documentation against the MCP specification",
+	Long:  "Integrations that run MCP fact-check validation outside of an MCP host: review bots, CI checks, and editor tooling.",
+	// main prints the error once; usage is only useful for flag mistakes, which cobra reports itself
+	SilenceUsage:  true,
+	SilenceErrors: true,
+}
+
+var botCmd = &cobra.Command{
+	Use:   "bot",
+	Short: "Run a chat or code-review bot backed by the validator",
+}
+
+func init() {
+	rootCmd.AddCommand(botCmd)
+	rootCmd.AddCommand(apiCmd)
+	rootCmd.AddCommand(verifyCmd)
+	rootCmd.AddCommand(ciCmd)
+	rootCmd.AddCommand(siteCmd)
+	rootCmd.AddCommand(feedbackCmd)
+	rootCmd.AddCommand(evalCmd)
+	rootCmd.AddCommand(conformanceCmd)
+	botCmd.AddCommand(githubBotCmd)
+	botCmd.AddCommand(slackBotCmd)
+}
+
+func main() {
+	// Load .env file if it exists
+	_ = godotenv.Load()
+
+	if err := logger.Initialize(logger.IsDevMode()); err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to initialize logger: %v\n", err)
+		os.Exit(1)
+	}
+	defer logger.Sync()
+
+	if err := rootCmd.Execute(); err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,81 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/carlisia/mcp-factcheck/internal/secrets"
+	"github.com/carlisia/mcp-factcheck/internal/version"
+	"github.com/carlisia/mcp-factcheck/pkg/validator"
+	"github.com/mark3labs/mcp-go/client"
+	"github.com/mark3labs/mcp-go/client/transport"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// remoteValidator validates documents by calling validate_content on an MCP server
+// served with --transport=http. The bearer token, if any, is read from the
+// MCP_FACTCHECK_AUTH_TOKEN secret.
+func remoteValidator(ctx context.Context, serverURL string) (validateFunc, func() error, error) {
+	var options []transport.StreamableHTTPCOption
+	if token, err := secrets.Lookup("MCP_FACTCHECK_AUTH_TOKEN"); err == nil {
+		options = append(options, transport.WithHTTPHeaders(map[string]string{"Authorization": "Bearer " + token}))
+	}
+
+	c, err := client.NewStreamableHttpClient(serverURL, options...)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to create MCP client: %w", err)
+	}
+	if err := c.Start(ctx); err != nil {
+		return nil, nil, fmt.Errorf("failed to connect to %s: %w", serverURL, err)
+	}
+
+	init := mcp.InitializeRequest{}
+	init.Params.ProtocolVersion = mcp.LATEST_PROTOCOL_VERSION
+	init.Params.ClientInfo = mcp.Implementation{Name: "factcheck", Version: version.ServerVersion()}
+	if _, err := c.Initialize(ctx, init); err != nil {
+		c.Close()
+		return nil, nil, fmt.Errorf("failed to initialize MCP session with %s: %w", serverURL, err)
+	}
+
+	validate := func(ctx context.Context, content, specVersion string) ([]validator.ValidationError, *validator.Score, error) {
+		req := mcp.CallToolRequest{}
+		req.Params.Name = validator.ValidateContentToolName
+		req.Params.Arguments = map[string]any{
+			"content":     content,
+			"specVersion": specVersion,
+			"useChunking": true,
+			"score":       true,
+		}
+		result, err := c.CallTool(ctx, req)
+		if err != nil {
+			return nil, nil, err
+		}
+		text := toolText(result)
+		if result.IsError {
+			return nil, nil, fmt.Errorf("validate_content failed: %s", text)
+		}
+
+		var response struct {
+			ChunkDetails []validator.ChunkValidationResult `json:"chunk_details"`
+			Score        *validator.Score                  `json:"score"`
+		}
+		if err := json.Unmarshal([]byte(text), &response); err != nil {
+			return nil, nil, fmt.Errorf("unexpected validate_content response: %w", err)
+		}
+		return validator.AggregatedValidationResult{ChunkResults: response.ChunkDetails}.Findings(), response.Score, nil
+	}
+	return validate, c.Close, nil
+}
+
+// toolText joins the text content of a tool result
+func toolText(result *mcp.CallToolResult) string {
+	var parts []string
+	for _, content := range result.Content {
+		if text, ok := content.(mcp.TextContent); ok {
+			parts = append(parts, text.Text)
+		}
+	}
+	return strings.Join(parts, "\n")
+}
@@ -0,0 +1,115 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/carlisia/mcp-factcheck/internal/specs"
+	"github.com/carlisia/mcp-factcheck/pkg/report"
+	"github.com/carlisia/mcp-factcheck/pkg/sitecheck"
+	"github.com/spf13/cobra"
+)
+
+var siteCmd = &cobra.Command{
+	Use:   "site <docs directory or file>...",
+	Short: "Check docs pages as a static-site build step",
+	Long: `Validate docs pages before a static site generator (Docusaurus, Hugo, MkDocs/Backstage
+TechDocs) builds them. With --write, pages whose findings meet --banner-on get a warning
+admonition injected after their front matter. The command exits non-zero when any finding
+meets --fail-on, failing the build.
+
+Run it on a disposable checkout (as CI does) when using --write, since pages are edited in place.`,
+	Example: `  # Docusaurus: annotate pages, fail on critical findings
+  factcheck site --write --banner-style docusaurus docs/ && npm run build
+
+  # TechDocs: report only, never fail
+  factcheck site --fail-on none --banner-style mkdocs docs/`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: runSite,
+}
+
+var (
+	siteDataDir     string
+	siteSpecVersion string
+	siteWrite       bool
+	siteFormat      string
+	siteOutput      string
+	sitePolicy      = sitecheck.DefaultPolicy()
+)
+
+func init() {
+	siteCmd.Flags().StringVar(&siteDataDir, "data-dir", "", "Directory containing vector database (default: $XDG_DATA_HOME/mcp-factcheck/embeddings)")
+	siteCmd.Flags().StringVar(&siteSpecVersion, "spec-version", specs.DefaultSpecVersion, "MCP spec version to validate against")
+	siteCmd.Flags().BoolVar(&siteWrite, "write", false, "Inject warning banners into pages in place")
+	siteCmd.Flags().StringVarP(&siteFormat, "format", "f", "text", "Report format: "+strings.Join(report.Formats, ", "))
+	siteCmd.Flags().StringVarP(&siteOutput, "output", "o", "", "Write the report to a file instead of stdout")
+	addPolicyFlags(siteCmd, &sitePolicy)
+}
+
+// addPolicyFlags registers the severity policy flags shared by site and api
+func addPolicyFlags(cmd *cobra.Command, policy *sitecheck.Policy) {
+	cmd.Flags().StringVar(&policy.BannerOn, "banner-on", policy.BannerOn, "Minimum severity that adds a banner: critical, warning, suggestion, or none")
+	cmd.Flags().StringVar(&policy.FailOn, "fail-on", policy.FailOn, "Minimum severity that fails the build: critical, warning, suggestion, or none")
+	cmd.Flags().StringVar(&policy.BannerStyle, "banner-style", policy.BannerStyle, "Banner syntax: "+strings.Join(sitecheck.Styles, ", "))
+}
+
+func runSite(cmd *cobra.Command, args []string) error {
+	if !specs.IsValidSpecVersion(siteSpecVersion) {
+		return fmt.Errorf("invalid spec version: %s. Valid versions: %v", siteSpecVersion, specs.ValidSpecVersions)
+	}
+	if err := sitePolicy.Validate(); err != nil {
+		return err
+	}
+	if _, err := report.Format(siteFormat, nil, ""); err != nil {
+		return err
+	}
+
+	files, err := markdownFiles(args)
+	if err != nil {
+		return err
+	}
+	if len(files) == 0 {
+		return fmt.Errorf("no markdown files found in %s", strings.Join(args, ", "))
+	}
+
+	vectorDB, generator, err := newBackend(siteDataDir)
+	if err != nil {
+		return err
+	}
+	checker, err := sitecheck.NewChecker(vectorDB, generator, siteSpecVersion, sitePolicy)
+	if err != nil {
+		return err
+	}
+
+	var docs []report.Document
+	var failed []string
+	for _, file := range files {
+		content, err := os.ReadFile(file)
+		if err != nil {
+			return fmt.Errorf("failed to read %s: %w", file, err)
+		}
+		result, err := checker.Check(cmd.Context(), sitecheck.Page{Path: file, Content: string(content)})
+		if err != nil {
+			return err
+		}
+
+		docs = append(docs, report.Document{Path: file, SpecVersion: siteSpecVersion, Findings: result.Findings})
+		if result.Fail {
+			failed = append(failed, file)
+		}
+		if siteWrite && result.Banner != "" {
+			if err := os.WriteFile(file, []byte(result.Content), 0o644); err != nil {
+				return fmt.Errorf("failed to write %s: %w", file, err)
+			}
+		}
+	}
+
+	if err := writeReport(siteFormat, siteOutput, docs); err != nil {
+		return err
+	}
+	if len(failed) > 0 {
+		return fmt.Errorf("%d page(s) have findings at or above %q: %s", len(failed), sitePolicy.FailOn, strings.Join(failed, ", "))
+	}
+	return nil
+}
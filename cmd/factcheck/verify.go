@@ -0,0 +1,175 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/carlisia/mcp-factcheck/internal/specs"
+	"github.com/carlisia/mcp-factcheck/internal/version"
+	"github.com/carlisia/mcp-factcheck/pkg/report"
+	"github.com/carlisia/mcp-factcheck/pkg/validator"
+	"github.com/spf13/cobra"
+)
+
+var verifyCmd = &cobra.Command{
+	Use:   "verify <file or directory>...",
+	Short: "Validate markdown files and print a report",
+	Long: `Validate markdown files against the MCP specification and print the findings.
+Directories are searched recursively for .md, .mdx, and .markdown files.
+
+Output formats:
+  text     path:line: severity: message (default)
+  json     findings grouped by document
+  sarif    SARIF 2.1.0, for GitHub code scanning
+  rdjson   reviewdog diagnostic result (reviewdog -f=rdjson)
+  rdjsonl  one reviewdog diagnostic per line (reviewdog -f=rdjsonl)
+  junit    JUnit XML, one test suite per document and one failure per finding`,
+	Example: `  factcheck verify docs/
+  factcheck verify --format rdjsonl docs/ | reviewdog -f=rdjsonl -reporter=github-pr-review`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: runVerify,
+}
+
+var (
+	verifyDataDir     string
+	verifySpecVersion string
+	verifyFormat      string
+	verifyOutput      string
+)
+
+func init() {
+	verifyCmd.Flags().StringVar(&verifyDataDir, "data-dir", "", "Directory containing vector database (default: $XDG_DATA_HOME/mcp-factcheck/embeddings)")
+	verifyCmd.Flags().StringVar(&verifySpecVersion, "spec-version", specs.DefaultSpecVersion, "MCP spec version to validate against")
+	verifyCmd.Flags().StringVarP(&verifyFormat, "format", "f", "text", "Output format: "+strings.Join(report.Formats, ", "))
+	verifyCmd.Flags().StringVarP(&verifyOutput, "output", "o", "", "Write the report to a file instead of stdout")
+}
+
+func runVerify(cmd *cobra.Command, args []string) error {
+	if !specs.IsValidSpecVersion(verifySpecVersion) {
+		return fmt.Errorf("invalid spec version: %s. Valid versions: %v", verifySpecVersion, specs.ValidSpecVersions)
+	}
+	// Reject an unknown format before spending time on validation
+	if _, err := report.Format(verifyFormat, nil, ""); err != nil {
+		return err
+	}
+
+	files, err := markdownFiles(args)
+	if err != nil {
+		return err
+	}
+	if len(files) == 0 {
+		return fmt.Errorf("no markdown files found in %s", strings.Join(args, ", "))
+	}
+
+	validate, err := localValidator(verifyDataDir)
+	if err != nil {
+		return err
+	}
+	docs, err := verifyFiles(cmd.Context(), files, verifySpecVersion, validate)
+	if err != nil {
+		return err
+	}
+
+	return writeReport(verifyFormat, verifyOutput, docs)
+}
+
+// validateFunc validates one document and returns its findings and score
+type validateFunc func(ctx context.Context, content, specVersion string) ([]validator.ValidationError, *validator.Score, error)
+
+// localValidator validates documents in-process against the embeddings in dataDir
+func localValidator(dataDir string) (validateFunc, error) {
+	vectorDB, generator, err := newBackend(dataDir)
+	if err != nil {
+		return nil, err
+	}
+	return func(ctx context.Context, content, specVersion string) ([]validator.ValidationError, *validator.Score, error) {
+		result, err := validator.Validate(ctx, vectorDB, generator, validator.Request{Content: content, SpecVersion: specVersion, Chunked: true, Score: true})
+		if err != nil {
+			return nil, nil, err
+		}
+		return result.Findings(), result.Score, nil
+	}, nil
+}
+
+// verifyFiles validates each file and collects its findings, most severe first, and its score
+func verifyFiles(ctx context.Context, files []string, specVersion string, validate validateFunc) ([]report.Document, error) {
+	docs := make([]report.Document, 0, len(files))
+	for _, file := range files {
+		content, err := os.ReadFile(file)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", file, err)
+		}
+		doc := report.Document{Path: file, SpecVersion: specVersion, Findings: []validator.ValidationError{}}
+		if strings.TrimSpace(string(content)) != "" {
+			findings, score, err := validate(ctx, string(content), specVersion)
+			if err != nil {
+				return nil, fmt.Errorf("failed to validate %s: %w", file, err)
+			}
+			doc.Score = score
+			if findings != nil {
+				doc.Findings = findings
+			}
+			validator.SortBySeverity(doc.Findings)
+		}
+		docs = append(docs, doc)
+	}
+	return docs, nil
+}
+
+func writeReport(format, output string, docs []report.Document) error {
+	data, err := report.Format(format, docs, version.ServerVersion())
+	if err != nil {
+		return err
+	}
+	if output == "" {
+		_, err = os.Stdout.Write(data)
+		return err
+	}
+	return os.WriteFile(output, data, 0o644)
+}
+
+// markdownFiles expands directories into the markdown files they contain
+func markdownFiles(paths []string) ([]string, error) {
+	var files []string
+	for _, path := range paths {
+		info, err := os.Stat(path)
+		if err != nil {
+			return nil, err
+		}
+		if !info.IsDir() {
+			files = append(files, path)
+			continue
+		}
+		err = filepath.WalkDir(path, func(p string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			if d.IsDir() {
+				if p != path && (strings.HasPrefix(d.Name(), ".") || d.Name() == "node_modules") {
+					return filepath.SkipDir
+				}
+				return nil
+			}
+			if isMarkdown(p) {
+				files = append(files, p)
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	return files, nil
+}
+
+func isMarkdown(path string) bool {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".md", ".mdx", ".markdown":
+		return true
+	}
+	return false
+}
@@ -0,0 +1,58 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/carlisia/mcp-factcheck/internal/config"
+	"github.com/carlisia/mcp-factcheck/internal/doctor"
+)
+
+// runDoctor implements `mcp-factcheck-server doctor` and returns the process exit code
+func runDoctor(args []string) int {
+	fs := flag.NewFlagSet("doctor", flag.ExitOnError)
+	dataDir := fs.String("data-dir", "", "Directory containing vector database (default: $XDG_DATA_HOME/mcp-factcheck/embeddings)")
+	configPath := fs.String("config", config.DefaultConfigPath(), "Path to JSON or YAML config file")
+	otlpEndpoint := fs.String("otlp-endpoint", "http://localhost:6006", "OTLP endpoint to check for reachability")
+	offline := fs.Bool("offline", false, "Skip checks that call OpenAI or the telemetry endpoint")
+	fs.Parse(args)
+
+	if *dataDir == "" {
+		*dataDir = config.DefaultDataDir()
+	}
+	absDataDir, err := filepath.Abs(*dataDir)
+	if err != nil {
+		absDataDir = *dataDir
+	}
+
+	results := doctor.Run(context.Background(), doctor.Options{
+		ConfigPath:   *configPath,
+		DataDir:      absDataDir,
+		OTLPEndpoint: *otlpEndpoint,
+		SkipNetwork:  *offline,
+	})
+
+	for _, r := range results {
+		var marker string
+		switch r.Status {
+		case doctor.StatusOK:
+			marker = "✅"
+		case doctor.StatusWarn:
+			marker = "⚠️ "
+		case doctor.StatusFail:
+			marker = "❌"
+		}
+		fmt.Fprintf(os.Stdout, "%s %-24s %s\n", marker, r.Name, r.Message)
+		if r.Fix != "" {
+			fmt.Fprintf(os.Stdout, "   fix: %s\n", r.Fix)
+		}
+	}
+
+	if doctor.Failed(results) {
+		return 1
+	}
+	return 0
+}
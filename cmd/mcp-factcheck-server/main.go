@@ -3,24 +3,53 @@ package main
 import (
 	"context"
 	"flag"
+	"fmt"
 	"log"
 	"os"
 	"os/signal"
-	"path/filepath"
 	"strings"
 	"syscall"
 	"time"
 
+	"github.com/carlisia/mcp-factcheck/embedding"
+	"github.com/carlisia/mcp-factcheck/internal/audit"
+	"github.com/carlisia/mcp-factcheck/internal/auth"
+	"github.com/carlisia/mcp-factcheck/internal/bootstrap"
+	"github.com/carlisia/mcp-factcheck/internal/config"
+	"github.com/carlisia/mcp-factcheck/internal/cost"
+	"github.com/carlisia/mcp-factcheck/internal/features"
+	"github.com/carlisia/mcp-factcheck/internal/feedback"
+	"github.com/carlisia/mcp-factcheck/internal/history"
+	"github.com/carlisia/mcp-factcheck/internal/interactions"
+	"github.com/carlisia/mcp-factcheck/internal/roots"
+	"github.com/carlisia/mcp-factcheck/internal/secrets"
+	"github.com/carlisia/mcp-factcheck/internal/tenant"
+	"github.com/carlisia/mcp-factcheck/internal/version"
 	"github.com/carlisia/mcp-factcheck/pkg"
 	"github.com/carlisia/mcp-factcheck/pkg/logger"
+	"github.com/carlisia/mcp-factcheck/pkg/tokens"
+	"github.com/carlisia/mcp-factcheck/pkg/validator"
+	"github.com/carlisia/mcp-factcheck/vectorstore"
 	"github.com/carlisia/mcp-factcheck/internal/integrations/arizephoenix"
 	"github.com/joho/godotenv"
+	"go.uber.org/zap"
 )
 
 func main() {
 	// Load .env file if it exists
 	_ = godotenv.Load()
 
+	// Subcommands that don't start the server
+	if len(os.Args) > 1 && os.Args[1] == "doctor" {
+		os.Exit(runDoctor(os.Args[2:]))
+	}
+
+	// serve is the container entrypoint: the HTTP transport on every interface, with
+	// flags after it overriding these defaults
+	if len(os.Args) > 1 && os.Args[1] == "serve" {
+		os.Args = append([]string{os.Args[0], "--transport=http", "--addr=0.0.0.0:8080"}, os.Args[2:]...)
+	}
+
 	// Initialize structured logging with Zap
 	if err := logger.Initialize(logger.IsDevMode()); err != nil {
 		log.Fatalf("Failed to initialize logger: %v", err)
@@ -28,15 +57,101 @@ func main() {
 	defer logger.Sync()
 
 	// Parse command line flags
-	dataDir := flag.String("data-dir", "/Users/carlisiacampos/code/src/github.com/carlisia/mcp-factcheck/data/embeddings", "Directory containing vector database")
+	dataDir := flag.String("data-dir", "", "Directory containing vector database (default: $XDG_DATA_HOME/mcp-factcheck/embeddings)")
 	telemetry := flag.Bool("telemetry", false, "Enable OpenTelemetry tracing")
 	otlpEndpoint := flag.String("otlp-endpoint", "http://localhost:4318", "OTLP endpoint for traces")
+	bootstrapURL := flag.String("bootstrap-url", os.Getenv(bootstrap.URLEnvVar), "URL of a .tar.gz of prebuilt embeddings to download when the data directory is empty (default: $"+bootstrap.URLEnvVar+")")
+	bootstrapSHA256 := flag.String("bootstrap-sha256", os.Getenv(bootstrap.SHA256EnvVar), "SHA-256 checksum of the --bootstrap-url archive; read from <url>"+bootstrap.ChecksumSuffix+" when empty (default: $"+bootstrap.SHA256EnvVar+")")
+	tenantName := flag.String("tenant", "", "Tenant from the config file to serve over stdio")
+	configPath := flag.String("config", config.DefaultConfigPath(), "Path to JSON or YAML config file with runtime settings (reloaded on SIGHUP or change)")
+	historyFile := flag.String("history-file", history.DefaultPath(), "JSONL file where every validation run is recorded for get_validation_history; empty disables history")
+	interactionsFile := flag.String("interactions-file", "", "JSONL file where every tool call is recorded with its arguments and response, served at /debug/interactions (try "+interactions.DefaultPath()+"); empty disables recording")
+	interactionsMaxAge := flag.Duration("interactions-max-age", interactions.DefaultMaxAge, "Drop recorded interactions older than this; 0 keeps them")
+	interactionsMaxEntries := flag.Int("interactions-max-entries", interactions.DefaultMaxEntries, "Keep only the newest recorded interactions; 0 keeps them all")
+	auditLog := flag.String("audit-log", "", "File where one JSON line per tool call is written for auditing (request ID, tool, argument hash, verdict, duration, cost); empty disables it")
+	auditLogMaxSize := flag.Int("audit-log-max-size", audit.DefaultMaxBytes>>20, "Size in MB at which the audit log is rotated")
+	auditLogMaxBackups := flag.Int("audit-log-max-backups", audit.DefaultMaxBackups, "Rotated audit logs to keep")
+	feedbackFile := flag.String("feedback-file", feedback.DefaultPath(), "JSONL file where report_feedback records feedback on findings")
+	transport := flag.String("transport", "stdio", "Transport to serve: stdio or http (Streamable HTTP at /mcp, legacy SSE at /sse)")
+	addr := flag.String("addr", "127.0.0.1:8080", "Address to listen on with --transport=http")
+	tlsCert := flag.String("tls-cert", "", "TLS certificate file; enables HTTPS with --tls-key")
+	tlsKey := flag.String("tls-key", "", "TLS private key file; enables HTTPS with --tls-cert")
+	showVersion := flag.Bool("version", false, "Print build information and exit")
+	var rootDirs []string
+	flag.Func("root", "Directory validate_file may read files from; repeat for several (validate_file is only offered when set)", func(dir string) error {
+		rootDirs = append(rootDirs, dir)
+		return nil
+	})
+	embeddingModel := flag.String("embedding-model", "", fmt.Sprintf("Embedding model for queries, matching the stored embeddings: %s (default: $%s or %s)", strings.Join(embedding.Models, ", "), embedding.ModelEnvVar, embedding.DefaultModel))
+	embeddingDimensions := flag.Int("embedding-dimensions", 0, fmt.Sprintf("Vector length requested from text-embedding-3 models (default: $%s or the model's full length)", embedding.DimensionsEnvVar))
+	exactSearchBelow := flag.Int("exact-search-below", vectorstore.DefaultSearchSettings().ExactBelow, "Search collections with fewer chunks by scoring every chunk instead of through their HNSW index; -1 always searches exactly")
+	validatorFlags := config.RegisterValidatorFlags(flag.CommandLine)
 	flag.Parse()
 
-	// Convert to absolute path if relative
-	absDataDir, err := filepath.Abs(*dataDir)
+	if *showVersion {
+		dir := *dataDir
+		if dir == "" {
+			dir = config.DefaultDataDir()
+		}
+		fmt.Print(version.Get(dir))
+		return
+	}
+
+	if *transport != "stdio" && *transport != "http" {
+		log.Fatalf("Invalid --transport %q (valid: stdio, http)", *transport)
+	}
+
+	// Load the tokenizer in the background so the first chunked validation doesn't wait on the download
+	go func() {
+		if err := tokens.Load(); err != nil {
+			logger.Get().Warn("Tokenizer unavailable, estimating token counts", zap.Error(err))
+		}
+	}()
+
+	// Queries must be embedded with the model that embedded the specs
+	model, err := embedding.ResolveModel(*embeddingModel, *embeddingDimensions)
+	if err == nil {
+		err = embedding.SetModel(model)
+	}
+	if err != nil {
+		log.Fatalf("Invalid embedding model: %v", err)
+	}
+	logger.Get().Info("Embedding model", zap.String("model", model.Name), zap.Int("dimensions", model.Dimensions))
+
+	search := vectorstore.DefaultSearchSettings()
+	search.ExactBelow = *exactSearchBelow
+	if err := vectorstore.SetSearchSettings(search); err != nil {
+		log.Fatalf("Invalid search settings: %v", err)
+	}
+
+	// Enable experimental features from the environment; a config file may override them
+	if err := features.Set(features.FromEnv()); err != nil {
+		log.Fatalf("Invalid %s: %v", features.EnvVar, err)
+	}
+
+	// Load runtime settings and watch for changes
+	cfg := config.Default()
+	if *configPath != "" {
+		reloader := config.NewReloader(*configPath)
+		reloader.Override(validatorFlags.Apply)
+		if err := reloader.Reload(); err != nil {
+			log.Fatalf("Failed to load config: %v", err)
+		}
+		cfg = reloader.Current()
+		go reloader.Run(context.Background())
+	} else {
+		validatorFlags.Apply(cfg)
+		if err := validator.SetSettings(cfg.Validator); err != nil {
+			log.Fatalf("Invalid validator flags: %v", err)
+		}
+	}
+
+	// Resolve the data directory, downloading embeddings into it if it is empty
+	bootstrapCtx, stopBootstrap := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	absDataDir, err := config.ResolveDataDir(bootstrapCtx, *dataDir, bootstrap.Source{URL: *bootstrapURL, SHA256: *bootstrapSHA256})
+	stopBootstrap()
 	if err != nil {
-		log.Fatalf("Failed to resolve data directory path: %v", err)
+		log.Fatalf("Invalid data directory: %v", err)
 	}
 
 	// Initialize telemetry if enabled
@@ -49,10 +164,11 @@ func main() {
 		// Check if endpoint looks like Phoenix and use specialized integration
 		if strings.Contains(*otlpEndpoint, "6006") || strings.Contains(*otlpEndpoint, "phoenix") {
 			log.Println("Detected Phoenix endpoint, using clean Phoenix integration")
-			config := arizephoenix.DefaultConfig()
-			config.Endpoint = strings.TrimPrefix(*otlpEndpoint, "http://")
+			phoenixConfig := arizephoenix.DefaultConfig()
+			phoenixConfig.Endpoint = strings.TrimPrefix(*otlpEndpoint, "http://")
+			phoenixConfig.ServiceVersion = version.ServerVersion()
 			
-			phoenixProvider, phoenixMiddleware, err := arizephoenix.Initialize(ctx, config)
+			phoenixProvider, phoenixMiddleware, err := arizephoenix.Initialize(ctx, phoenixConfig)
 			if err != nil {
 				log.Printf("Failed to initialize Phoenix telemetry: %v. Using no-op provider.", err)
 				provider = nil
@@ -92,8 +208,84 @@ func main() {
 		log.Fatalf("Failed to create MCP fact-check server: %v", err)
 	}
 
+	server.SetFeedbackStore(feedback.NewStore(*feedbackFile))
+	if *historyFile == "" {
+		server.SetHistoryStore(nil)
+	} else {
+		server.SetHistoryStore(history.NewStore(*historyFile))
+	}
+	if *interactionsFile != "" {
+		store := interactions.NewStore(*interactionsFile, interactions.Retention{MaxAge: *interactionsMaxAge, MaxEntries: *interactionsMaxEntries})
+		if err := store.Prune(); err != nil {
+			log.Fatalf("Failed to open interactions file: %v", err)
+		}
+		server.SetInteractionStore(store)
+	}
+	if *auditLog != "" {
+		auditor, err := audit.Open(*auditLog, audit.Rotation{MaxBytes: int64(*auditLogMaxSize) << 20, MaxBackups: *auditLogMaxBackups})
+		if err != nil {
+			log.Fatalf("Failed to open audit log: %v", err)
+		}
+		defer auditor.Close()
+		server.SetAuditLog(auditor)
+	}
+
+	if len(rootDirs) > 0 {
+		files, err := roots.Open(rootDirs)
+		if err != nil {
+			log.Fatalf("Failed to open roots: %v", err)
+		}
+		defer files.Close()
+		server.SetRoots(files)
+	}
+
+	// Set up tenant isolation if configured
+	if len(cfg.Tenants) > 0 {
+		registry, err := tenant.NewRegistry(cfg.Tenants)
+		if err != nil {
+			log.Fatalf("Failed to configure tenants: %v", err)
+		}
+		if err := server.SetTenants(registry, *tenantName); err != nil {
+			log.Fatalf("Failed to configure tenants: %v", err)
+		}
+	} else if *tenantName != "" {
+		log.Fatalf("--tenant %s given but no tenants are configured", *tenantName)
+	}
+
+	// Set up API keys and OAuth if configured
+	if cfg.Auth.Enabled() {
+		authenticator, err := auth.New(cfg.Auth)
+		if err != nil {
+			log.Fatalf("Failed to configure auth: %v", err)
+		}
+		server.SetAuthenticator(authenticator)
+	}
+
 	// Run MCP server (blocks until shutdown)
-	if err := server.Run(); err != nil {
+	if *transport == "http" {
+		// A shared token guards the HTTP transport when no tenants or auth are configured
+		authToken, _ := secrets.Lookup("MCP_FACTCHECK_AUTH_TOKEN")
+		ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+		err = server.RunHTTP(ctx, pkg.HTTPOptions{
+			Addr:        *addr,
+			TLSCertFile: *tlsCert,
+			TLSKeyFile:  *tlsKey,
+			AuthToken:   authToken,
+		})
+		stop()
+	} else {
+		err = server.Run()
+	}
+	if stats := cost.Stats(); stats.Total.Requests > 0 {
+		logger.Get().Info("OpenAI usage", zap.Int64("requests", stats.Total.Requests), zap.Float64("usd", stats.Total.USD), zap.Any("today_by_tool", stats.Tools))
+	}
+	for name, usage := range server.TenantUsage() {
+		logger.Get().Info("Tenant usage", zap.String("tenant", name), zap.Any("calls", usage.Calls), zap.Int64("errors", usage.Errors))
+	}
+	for name, usage := range server.CallerUsage() {
+		logger.Get().Info("Caller usage", zap.String("caller", name), zap.Int64("requests", usage.Requests), zap.Int64("errors", usage.Errors), zap.Int64("rate_limited", usage.RateLimited))
+	}
+	if err != nil {
 		log.Fatalf("Server error: %v", err)
 	}
 }
\ No newline at end of file
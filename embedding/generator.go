@@ -2,22 +2,50 @@ package embedding
 
 import (
 	"context"
+	"errors"
 	"fmt"
-	"os"
+	"net/http"
+	"sync/atomic"
 
+	"github.com/carlisia/mcp-factcheck/internal/cost"
+	"github.com/carlisia/mcp-factcheck/internal/secrets"
 	"github.com/sashabaranov/go-openai"
 )
 
-// Generator handles embedding generation using OpenAI
+// DefaultModel is the OpenAI embedding model used for both spec and query embeddings
+// unless another is configured. The shipped spec embeddings were made with it.
+const DefaultModel = openai.AdaEmbeddingV2
+
+// DefaultDimensions is the vector length produced by DefaultModel
+const DefaultDimensions = 1536
+
+// ErrNoAPIKey is returned by NewGenerator when no OpenAI API key is configured
+var ErrNoAPIKey = errors.New("OPENAI_API_KEY is not set")
+
+// ErrProviderUnavailable wraps failed embedding API requests, which have already been
+// retried by the client's retry policy
+var ErrProviderUnavailable = errors.New("embedding provider unavailable")
+
+// Generator handles embedding generation using OpenAI, or LocalModel. Requests made
+// through its client are rate limited and retried according to a RetryPolicy.
 type Generator struct {
-	client *openai.Client
+	client *openai.Client // nil for generators without an API key
+	model  Model
+	policy atomic.Pointer[RetryPolicy] // Overrides CurrentRetryPolicy when set
 }
 
-// NewGenerator creates a new embedding generator using environment variable
+// NewGenerator creates a new embedding generator using the OPENAI_API_KEY secret. The
+// error wraps ErrNoAPIKey when there is none, unless the current model is LocalModel.
 func NewGenerator() (*Generator, error) {
-	apiKey := os.Getenv("OPENAI_API_KEY")
-	if apiKey == "" {
-		return nil, fmt.Errorf("OPENAI_API_KEY environment variable is not set")
+	apiKey, err := secrets.Lookup("OPENAI_API_KEY")
+	if errors.Is(err, secrets.ErrNotFound) && CurrentModel().Name == LocalModel {
+		return NewLocalGenerator(), nil // Embedding locally needs no key
+	}
+	if errors.Is(err, secrets.ErrNotFound) {
+		return nil, fmt.Errorf("%w; provide it via %s", ErrNoAPIKey, secrets.Sources("OPENAI_API_KEY"))
+	}
+	if err != nil {
+		return nil, err
 	}
 
 	return NewGeneratorWithKey(apiKey)
@@ -29,19 +57,73 @@ func NewGeneratorWithKey(apiKey string) (*Generator, error) {
 		return nil, fmt.Errorf("API key cannot be empty")
 	}
 
-	client := openai.NewClient(apiKey)
-	return &Generator{client: client}, nil
+	g := &Generator{model: CurrentModel()}
+	config := openai.DefaultConfig(apiKey)
+	config.HTTPClient = &http.Client{Transport: &retryTransport{
+		base:    http.DefaultTransport,
+		limiter: &limiter{},
+		policy:  g.retryPolicy,
+	}}
+	g.client = openai.NewClientWithConfig(config)
+	return g, nil
 }
 
-// GenerateEmbedding creates an embedding for a single text chunk
-func (g *Generator) GenerateEmbedding(content string) ([]float64, error) {
-	resp, err := g.client.CreateEmbeddings(context.Background(), openai.EmbeddingRequest{
+// NewLocalGenerator creates a generator that embeds with LocalModel and has no
+// OpenAI client, for running without an API key
+func NewLocalGenerator() *Generator {
+	return &Generator{model: Local()}
+}
+
+// SetRetryPolicy gives this generator its own retry policy instead of the
+// process-wide one
+func (g *Generator) SetRetryPolicy(p RetryPolicy) error {
+	if err := p.Validate(); err != nil {
+		return err
+	}
+	g.policy.Store(&p)
+	return nil
+}
+
+func (g *Generator) retryPolicy() RetryPolicy {
+	if p := g.policy.Load(); p != nil {
+		return *p
+	}
+	return CurrentRetryPolicy()
+}
+
+// Model returns the model this generator embeds with, the current model when it was created
+func (g *Generator) Model() Model {
+	return g.model
+}
+
+// Client returns the underlying OpenAI client, for callers that need chat completions
+// with the same credentials, rate limit, and retries. It is nil for a generator made
+// by NewLocalGenerator.
+func (g *Generator) Client() *openai.Client {
+	return g.client
+}
+
+// GenerateEmbedding creates an embedding for a single text chunk. Its cost is
+// attributed to the tool call in ctx, if any.
+func (g *Generator) GenerateEmbedding(ctx context.Context, content string) ([]float64, error) {
+	if g.model.Name == LocalModel {
+		return embedLocally(content), nil
+	}
+	if g.client == nil {
+		return nil, ErrNoAPIKey
+	}
+	if err := cost.Allow(); err != nil {
+		return nil, err
+	}
+	resp, err := g.client.CreateEmbeddings(ctx, openai.EmbeddingRequest{
 		Input: []string{content},
-		Model: openai.AdaEmbeddingV2,
+		Model:      openai.EmbeddingModel(g.model.Name),
+		Dimensions: g.model.requestDimensions(),
 	})
 	if err != nil {
-		return nil, fmt.Errorf("failed to create embedding: %w", err)
+		return nil, fmt.Errorf("%w: failed to create embedding: %w", ErrProviderUnavailable, err)
 	}
+	cost.Record(ctx, g.model.Name, resp.Usage.PromptTokens, 0)
 
 	if len(resp.Data) == 0 {
 		return nil, fmt.Errorf("no embedding data returned")
@@ -54,4 +136,63 @@ func (g *Generator) GenerateEmbedding(content string) ([]float64, error) {
 	}
 
 	return embedding, nil
-}
\ No newline at end of file
+}
+
+// GenerateEmbeddings creates embeddings for several texts in a single API request.
+// Results are returned in the same order as texts.
+func (g *Generator) GenerateEmbeddings(ctx context.Context, texts []string) ([][]float64, error) {
+	if len(texts) == 0 {
+		return nil, nil
+	}
+	if g.model.Name == LocalModel {
+		embeddings := make([][]float64, len(texts))
+		for i, text := range texts {
+			embeddings[i] = embedLocally(text)
+		}
+		return embeddings, nil
+	}
+	if g.client == nil {
+		return nil, ErrNoAPIKey
+	}
+
+	if err := cost.Allow(); err != nil {
+		return nil, err
+	}
+	resp, err := g.client.CreateEmbeddings(ctx, openai.EmbeddingRequest{
+		Input: texts,
+		Model:      openai.EmbeddingModel(g.model.Name),
+		Dimensions: g.model.requestDimensions(),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("%w: failed to create embeddings: %w", ErrProviderUnavailable, err)
+	}
+	cost.Record(ctx, g.model.Name, resp.Usage.PromptTokens, 0)
+	if len(resp.Data) != len(texts) {
+		return nil, fmt.Errorf("expected %d embeddings, got %d", len(texts), len(resp.Data))
+	}
+
+	embeddings := make([][]float64, len(texts))
+	for _, data := range resp.Data {
+		if data.Index < 0 || data.Index >= len(texts) {
+			return nil, fmt.Errorf("embedding index %d out of range", data.Index)
+		}
+		vector := make([]float64, len(data.Embedding))
+		for i, v := range data.Embedding {
+			vector[i] = float64(v)
+		}
+		embeddings[data.Index] = vector
+	}
+
+	return embeddings, nil
+}
+
+// CheckAPIKey verifies the API key is accepted by OpenAI without generating embeddings
+func (g *Generator) CheckAPIKey(ctx context.Context) error {
+	if g.client == nil {
+		return ErrNoAPIKey
+	}
+	if _, err := g.client.ListModels(ctx); err != nil {
+		return fmt.Errorf("OpenAI rejected the API key: %w", err)
+	}
+	return nil
+}
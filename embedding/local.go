@@ -0,0 +1,80 @@
+package embedding
+
+import (
+	"hash/fnv"
+	"math"
+	"strings"
+	"unicode"
+)
+
+// LocalModel is the embedding model built into the binary, used when the server has
+// no OpenAI API key. It hashes words and word pairs into a fixed-length vector, so
+// texts are similar when they share vocabulary, not meaning: searches with it are
+// closer to keyword search than to OpenAI embeddings.
+const LocalModel = "local-hash-v1"
+
+// LocalDimensions is the vector length produced by LocalModel
+const LocalDimensions = 512
+
+// Local returns LocalModel at its full length
+func Local() Model {
+	return Model{Name: LocalModel, Dimensions: LocalDimensions}
+}
+
+// embedLocally embeds text with LocalModel. Each word and pair of adjacent words adds
+// a weight of 1 + log(count) to the dimension its hash selects, with a sign from
+// another bit of the hash so collisions tend to cancel out. The vector is normalized
+// to unit length.
+func embedLocally(text string) []float64 {
+	words := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '/' && r != '_'
+	})
+	counts := map[string]int{}
+	for i, word := range words {
+		counts[word]++
+		if i > 0 {
+			counts[words[i-1]+" "+word]++
+		}
+	}
+
+	vector := make([]float64, LocalDimensions)
+	for feature, count := range counts {
+		h := fnv.New64a()
+		h.Write([]byte(feature))
+		sum := h.Sum64()
+		weight := 1 + math.Log(float64(count))
+		if sum>>63 == 1 {
+			weight = -weight
+		}
+		vector[sum%LocalDimensions] += weight
+	}
+
+	var norm float64
+	for _, v := range vector {
+		norm += v * v
+	}
+	if norm > 0 {
+		norm = math.Sqrt(norm)
+		for i := range vector {
+			vector[i] /= norm
+		}
+	}
+	return vector
+}
+
+// EmbedLocally returns a copy of s with its chunks embedded with LocalModel, for
+// servers without an API key to search embeddings made with another model. s is
+// returned as is when it already uses LocalModel.
+func (s *SpecEmbedding) EmbedLocally() *SpecEmbedding {
+	if s.EmbeddingModel() == LocalModel {
+		return s
+	}
+	local := *s
+	local.Model, local.Dimensions = LocalModel, LocalDimensions
+	local.Chunks = make([]EmbeddedChunk, len(s.Chunks))
+	for i, chunk := range s.Chunks {
+		chunk.Embedding = embedLocally(chunk.Content)
+		local.Chunks[i] = chunk
+	}
+	return &local
+}
@@ -0,0 +1,150 @@
+package embedding
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"sync/atomic"
+
+	"github.com/sashabaranov/go-openai"
+)
+
+// ModelEnvVar selects the embedding model when no --embedding-model flag is given
+const ModelEnvVar = "EMBEDDING_MODEL"
+
+// DimensionsEnvVar sets the vector length requested from text-embedding-3 models
+// when no --embedding-dimensions flag is given
+const DimensionsEnvVar = "EMBEDDING_DIMENSIONS"
+
+// Model is an embedding model and the length of the vectors requested from it.
+// Spec embeddings and query embeddings must come from the same Model.
+type Model struct {
+	Name       string
+	Dimensions int
+}
+
+// nativeDimensions is the full vector length of each supported model. Only the
+// text-embedding-3 models can be asked for shorter vectors.
+var nativeDimensions = map[string]int{
+	string(openai.AdaEmbeddingV2):  1536,
+	string(openai.SmallEmbedding3): 1536,
+	string(openai.LargeEmbedding3): 3072,
+	LocalModel:                     LocalDimensions,
+}
+
+// Models lists the supported embedding models
+var Models = []string{string(openai.AdaEmbeddingV2), string(openai.SmallEmbedding3), string(openai.LargeEmbedding3), LocalModel}
+
+// NewModel checks that name is a supported model that can produce vectors of length
+// dims. An empty name means DefaultModel and a zero dims the model's full length.
+func NewModel(name string, dims int) (Model, error) {
+	if name == "" {
+		name = string(DefaultModel)
+	}
+	native, ok := nativeDimensions[name]
+	if !ok {
+		return Model{}, fmt.Errorf("unsupported embedding model %q (supported: %v)", name, Models)
+	}
+	if dims == 0 {
+		dims = native
+	}
+	switch {
+	case dims < 1 || dims > native:
+		return Model{}, fmt.Errorf("%s produces at most %d dimensions, got %d", name, native, dims)
+	case dims != native && (name == string(openai.AdaEmbeddingV2) || name == LocalModel):
+		return Model{}, fmt.Errorf("%s only produces %d dimensions; choose a text-embedding-3 model for other sizes", name, native)
+	}
+	return Model{Name: name, Dimensions: dims}, nil
+}
+
+// ResolveModel is NewModel with an empty name or zero dims taken from
+// $EMBEDDING_MODEL and $EMBEDDING_DIMENSIONS, for command-line flags that fall back
+// to the environment
+func ResolveModel(name string, dims int) (Model, error) {
+	if name == "" {
+		name = os.Getenv(ModelEnvVar)
+	}
+	if value := os.Getenv(DimensionsEnvVar); dims == 0 && value != "" {
+		var err error
+		if dims, err = strconv.Atoi(value); err != nil {
+			return Model{}, fmt.Errorf("invalid %s %q: %w", DimensionsEnvVar, value, err)
+		}
+	}
+	return NewModel(name, dims)
+}
+
+// requestDimensions is the dimensions parameter sent with embedding requests, which
+// is omitted for the model's full length since older models reject it
+func (m Model) requestDimensions() int {
+	if m.Dimensions == nativeDimensions[m.Name] {
+		return 0
+	}
+	return m.Dimensions
+}
+
+func (m Model) String() string {
+	return fmt.Sprintf("%s (%d dimensions)", m.Name, m.Dimensions)
+}
+
+var currentModel atomic.Pointer[Model]
+
+func init() {
+	// Commands without model flags still follow the environment; invalid values are
+	// reported by the commands that have flags
+	model, err := ResolveModel("", 0)
+	if err != nil {
+		model = Model{Name: string(DefaultModel), Dimensions: DefaultDimensions}
+	}
+	currentModel.Store(&model)
+}
+
+// CurrentModel returns the model used for new spec and query embeddings
+func CurrentModel() Model {
+	return *currentModel.Load()
+}
+
+// SetModel replaces the model used for new embeddings. Embeddings stored with
+// another model must be migrated with `specloader reembed` before they can be searched.
+func SetModel(m Model) error {
+	m, err := NewModel(m.Name, m.Dimensions)
+	if err != nil {
+		return err
+	}
+	currentModel.Store(&m)
+	return nil
+}
+
+// LegacyModel produced every embedding stored before SpecEmbedding recorded its model
+const LegacyModel = string(openai.AdaEmbeddingV2)
+
+// ErrModelMismatch is returned when query and stored embeddings come from different
+// models, whose similarities are meaningless
+var ErrModelMismatch = errors.New("embedding model mismatch")
+
+// EmbeddingModel returns the model that produced the embeddings
+func (s *SpecEmbedding) EmbeddingModel() string {
+	if s.Model == "" {
+		return LegacyModel
+	}
+	return s.Model
+}
+
+// EmbeddingDimensions returns the length of the stored vectors
+func (s *SpecEmbedding) EmbeddingDimensions() int {
+	if s.Dimensions == 0 {
+		return Dimensions(s)
+	}
+	return s.Dimensions
+}
+
+// CheckModel returns an error wrapping ErrModelMismatch unless the embeddings were
+// produced by model
+func (s *SpecEmbedding) CheckModel(model Model) error {
+	stored := Model{Name: s.EmbeddingModel(), Dimensions: s.EmbeddingDimensions()}
+	if stored.Name == model.Name && (stored.Dimensions == model.Dimensions || stored.Dimensions == 0) {
+		return nil
+	}
+	return fmt.Errorf("%w: %s was embedded with %s but queries use %s; re-embed it with `specloader reembed` or set %s to match",
+		ErrModelMismatch, s.Version, stored, model, ModelEnvVar)
+}
@@ -0,0 +1,208 @@
+package embedding
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"net/http"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// RetryPolicy paces and retries OpenAI requests. It applies to every request made
+// through a Generator's client, embeddings and chat completions alike.
+type RetryPolicy struct {
+	RequestsPerMinute int `json:"requests_per_minute"` // Client-side rate limit; 0 disables it
+	Burst             int `json:"burst"`               // Requests that may start at once before pacing applies
+	MaxRetries        int `json:"max_retries"`         // Retries of rate-limited (429), server (5xx), and network failures
+	InitialBackoffMS  int `json:"initial_backoff_ms"`  // Upper bound of the first retry delay; doubles on each attempt
+	MaxBackoffMS      int `json:"max_backoff_ms"`      // Cap on the backoff bound; a longer Retry-After from OpenAI still wins
+}
+
+// DefaultRetryPolicy returns pacing that stays under OpenAI's lowest paid tier
+// and retries that ride out short bursts of rate limiting
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		RequestsPerMinute: 3000,
+		Burst:             20,
+		MaxRetries:        4,
+		InitialBackoffMS:  500,
+		MaxBackoffMS:      20_000,
+	}
+}
+
+// Validate checks that the policy is usable
+func (p RetryPolicy) Validate() error {
+	if p.RequestsPerMinute < 0 || p.MaxRetries < 0 || p.InitialBackoffMS < 0 || p.MaxBackoffMS < 0 {
+		return fmt.Errorf("openai retry settings must not be negative")
+	}
+	if p.RequestsPerMinute > 0 && p.Burst < 1 {
+		return fmt.Errorf("burst must be at least 1 when requests_per_minute is set")
+	}
+	return nil
+}
+
+var currentPolicy atomic.Pointer[RetryPolicy]
+
+func init() {
+	defaults := DefaultRetryPolicy()
+	currentPolicy.Store(&defaults)
+}
+
+// CurrentRetryPolicy returns the policy used by generators without their own
+func CurrentRetryPolicy() RetryPolicy {
+	return *currentPolicy.Load()
+}
+
+// SetRetryPolicy atomically replaces the policy used by subsequent requests
+func SetRetryPolicy(p RetryPolicy) error {
+	if err := p.Validate(); err != nil {
+		return err
+	}
+	currentPolicy.Store(&p)
+	return nil
+}
+
+// backoff returns how long to wait before retry attempt+1: a random duration up to
+// the exponential bound (full jitter), or OpenAI's Retry-After if that is longer
+func (p RetryPolicy) backoff(attempt int, resp *http.Response) time.Duration {
+	bound := time.Duration(p.InitialBackoffMS) * time.Millisecond
+	limit := time.Duration(p.MaxBackoffMS) * time.Millisecond
+	for i := 0; i < attempt && (limit <= 0 || bound < limit); i++ {
+		bound *= 2
+	}
+	if limit > 0 && bound > limit {
+		bound = limit
+	}
+	delay := time.Duration(rand.Int64N(int64(bound) + 1))
+	if after := retryAfter(resp); after > delay {
+		delay = after
+	}
+	return delay
+}
+
+// retryAfter reads OpenAI's retry-after-ms or the standard Retry-After header
+func retryAfter(resp *http.Response) time.Duration {
+	if resp == nil {
+		return 0
+	}
+	if ms, err := strconv.ParseFloat(resp.Header.Get("retry-after-ms"), 64); err == nil && ms > 0 {
+		return time.Duration(ms * float64(time.Millisecond))
+	}
+	if s, err := strconv.Atoi(resp.Header.Get("Retry-After")); err == nil && s > 0 {
+		return time.Duration(s) * time.Second
+	}
+	return 0
+}
+
+// limiter paces requests to a steady rate, letting up to burst start at once
+type limiter struct {
+	mu   sync.Mutex
+	next time.Time // When the next request is scheduled to start
+}
+
+// wait blocks until the caller's turn and returns how long it waited
+func (l *limiter) wait(ctx context.Context, perMinute, burst int) (time.Duration, error) {
+	if perMinute <= 0 {
+		return 0, nil
+	}
+	interval := time.Minute / time.Duration(perMinute)
+
+	l.mu.Lock()
+	now := time.Now()
+	if earliest := now.Add(-time.Duration(burst-1) * interval); l.next.Before(earliest) {
+		l.next = earliest
+	}
+	start := l.next
+	l.next = l.next.Add(interval)
+	l.mu.Unlock()
+
+	wait := start.Sub(now)
+	if wait <= 0 {
+		return 0, nil
+	}
+	timer := time.NewTimer(wait)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return wait, ctx.Err()
+	case <-timer.C:
+		return wait, nil
+	}
+}
+
+// retryTransport rate limits requests and retries transient failures. Waits and
+// retries are recorded on the span in the request's context.
+type retryTransport struct {
+	base    http.RoundTripper
+	limiter *limiter
+	policy  func() RetryPolicy
+}
+
+func (t *retryTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	ctx := req.Context()
+	span := trace.SpanFromContext(ctx)
+	policy := t.policy()
+
+	var waited time.Duration
+	for attempt := 0; ; attempt++ {
+		wait, err := t.limiter.wait(ctx, policy.RequestsPerMinute, policy.Burst)
+		waited += wait
+		if err != nil {
+			return nil, err
+		}
+
+		if attempt > 0 {
+			body, err := req.GetBody()
+			if err != nil {
+				return nil, err
+			}
+			req = req.Clone(ctx)
+			req.Body = body
+		}
+
+		resp, err := t.base.RoundTrip(req)
+		status := 0
+		if resp != nil {
+			status = resp.StatusCode
+		}
+		retryable := status == http.StatusTooManyRequests || status >= 500 || (err != nil && ctx.Err() == nil)
+		rewindable := req.Body == nil || req.GetBody != nil
+		if !retryable || !rewindable || attempt >= policy.MaxRetries {
+			span.SetAttributes(
+				attribute.Int("openai.attempts", attempt+1),
+				attribute.Int64("openai.rate_limit_wait_ms", waited.Milliseconds()),
+			)
+			return resp, err
+		}
+
+		delay := policy.backoff(attempt, resp)
+		attrs := []attribute.KeyValue{
+			attribute.Int("openai.retry.attempt", attempt+1),
+			attribute.Int("openai.retry.status", status),
+			attribute.Int64("openai.retry.delay_ms", delay.Milliseconds()),
+		}
+		if err != nil {
+			attrs = append(attrs, attribute.String("openai.retry.error", err.Error()))
+		}
+		span.AddEvent("openai.retry", trace.WithAttributes(attrs...))
+		if resp != nil {
+			_, _ = io.Copy(io.Discard, resp.Body)
+			resp.Body.Close()
+		}
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
@@ -1,11 +1,14 @@
 package embedding
 
+import "time"
+
 // EmbeddedChunk represents a chunk of text with its embedding
 type EmbeddedChunk struct {
 	ID        string                 `json:"id"`
 	Version   string                 `json:"version"`
 	FilePath  string                 `json:"file_path,omitempty"`
-	Section   string                 `json:"section,omitempty"`
+	Section   string                 `json:"section,omitempty"` // Heading hierarchy, such as "Transports > Streamable HTTP"
+	URL       string                 `json:"url,omitempty"`     // Published page the chunk came from
 	Content   string                 `json:"content"`
 	Embedding []float64              `json:"embedding"`
 	Metadata  map[string]any `json:"metadata,omitempty"`
@@ -13,14 +16,19 @@ type EmbeddedChunk struct {
 
 // SpecEmbedding represents all embeddings for a specific MCP spec version
 type SpecEmbedding struct {
-	Version string          `json:"version"`
-	Chunks  []EmbeddedChunk `json:"chunks"`
-	Count   int             `json:"count"`
+	Version     string          `json:"version"`
+	Commit      string          `json:"commit,omitempty"`       // MCP repository commit the spec was extracted at, if recorded
+	CommittedAt *time.Time      `json:"committed_at,omitempty"` // When that commit was made
+	Model       string          `json:"model,omitempty"`        // Embedding model that produced the vectors; empty means LegacyModel
+	Dimensions  int             `json:"dimensions,omitempty"`   // Length of every vector
+	Chunks      []EmbeddedChunk `json:"chunks"`
+	Count       int             `json:"count"`
 }
 
 // SearchResult represents a similarity search result
 type SearchResult struct {
 	Chunk      EmbeddedChunk `json:"chunk"`
 	Similarity float64       `json:"similarity"`
+	Score      float64       `json:"score"` // Ranking score: the similarity blended with keyword relevance, or the reranker's relevance
 	Rank       int           `json:"rank"`
 }
\ No newline at end of file
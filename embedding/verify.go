@@ -0,0 +1,109 @@
+package embedding
+
+import (
+	"fmt"
+	"math"
+)
+
+// Verify checks a stored spec embedding's integrity and returns the problems found.
+// name is the version or corpus name the file is stored under. Problems that affect
+// many chunks are reported once with a count and the first chunk's ID.
+func Verify(spec *SpecEmbedding, name string) []string {
+	var problems []string
+	if spec.Version != name {
+		problems = append(problems, fmt.Sprintf("stored as %q but records version %q", name, spec.Version))
+	}
+	if len(spec.Chunks) == 0 {
+		return append(problems, "file contains no chunks")
+	}
+	if spec.Count != len(spec.Chunks) {
+		problems = append(problems, fmt.Sprintf("count is %d but the file has %d chunks", spec.Count, len(spec.Chunks)))
+	}
+
+	dims := Dimensions(spec)
+	if spec.Dimensions != 0 && spec.Dimensions != dims {
+		problems = append(problems, fmt.Sprintf("records %d dimensions but its vectors have %d", spec.Dimensions, dims))
+	}
+	counts := map[string]int{}
+	first := map[string]string{}
+	flag := func(problem, id string) {
+		if counts[problem] == 0 {
+			first[problem] = id
+		}
+		counts[problem]++
+	}
+
+	seen := make(map[string]bool, len(spec.Chunks))
+	for _, chunk := range spec.Chunks {
+		switch {
+		case chunk.ID == "":
+			flag("have no ID", "")
+		case seen[chunk.ID]:
+			flag("have a duplicate ID", chunk.ID)
+		}
+		seen[chunk.ID] = true
+
+		if chunk.Version != spec.Version {
+			flag(fmt.Sprintf("record a version other than %q", spec.Version), chunk.ID)
+		}
+		if chunk.Content == "" {
+			flag("have no content", chunk.ID)
+		}
+		switch {
+		case len(chunk.Embedding) == 0:
+			flag("have no embedding", chunk.ID)
+		case len(chunk.Embedding) != dims:
+			flag(fmt.Sprintf("have a dimension other than %d", dims), chunk.ID)
+		case !finite(chunk.Embedding):
+			flag("have NaN or infinite values", chunk.ID)
+		}
+	}
+
+	// Report in a fixed order so repeated runs print the same summary
+	for _, problem := range []string{
+		"have no ID",
+		"have a duplicate ID",
+		fmt.Sprintf("record a version other than %q", spec.Version),
+		"have no content",
+		"have no embedding",
+		fmt.Sprintf("have a dimension other than %d", dims),
+		"have NaN or infinite values",
+	} {
+		if counts[problem] == 0 {
+			continue
+		}
+		message := fmt.Sprintf("%d of %d chunks %s", counts[problem], len(spec.Chunks), problem)
+		if first[problem] != "" {
+			message += fmt.Sprintf(" (first: %s)", first[problem])
+		}
+		problems = append(problems, message)
+	}
+	return problems
+}
+
+// Dimensions returns the most common embedding length among the chunks, or 0 when
+// none has an embedding
+func Dimensions(spec *SpecEmbedding) int {
+	counts := map[int]int{}
+	dims := 0
+	for _, chunk := range spec.Chunks {
+		n := len(chunk.Embedding)
+		if n == 0 {
+			continue
+		}
+		counts[n]++
+		if counts[n] > counts[dims] || (counts[n] == counts[dims] && n < dims) {
+			dims = n
+		}
+	}
+	return dims
+}
+
+func finite(vector []float64) bool {
+	for _, v := range vector {
+		if math.IsNaN(v) || math.IsInf(v, 0) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,164 @@
+// Package audit writes one JSON line per tool call to a size-rotated file, for
+// compliance records and offline analysis. Entries identify the arguments by hash
+// only, so the log holds no validated text.
+package audit
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/carlisia/mcp-factcheck/internal/cost"
+)
+
+// Entry is one tool call
+type Entry struct {
+	Time        time.Time `json:"time"`
+	RequestID   string    `json:"request_id"`
+	Tool        string    `json:"tool"`
+	Tenant      string    `json:"tenant,omitempty"`
+	Session     string    `json:"session,omitempty"`
+	ArgsHash    string    `json:"args_hash"`  // See HashArguments
+	ArgsBytes   int       `json:"args_bytes"` // Size of the JSON arguments
+	SpecVersion string    `json:"spec_version,omitempty"`
+	Status      string    `json:"status"` // "ok" or "error"
+	Error       string    `json:"error,omitempty"`
+	// Validations counts the validation runs the call made. Valid is whether all
+	// passed, Confidence the lowest confidence among them, and Findings their total.
+	Validations int        `json:"validations,omitempty"`
+	Valid       *bool      `json:"valid,omitempty"`
+	Confidence  *float64   `json:"confidence,omitempty"`
+	Findings    int        `json:"findings,omitempty"`
+	DurationMs  int64      `json:"duration_ms"`
+	Usage       cost.Usage `json:"usage"` // OpenAI requests, tokens, and estimated cost
+}
+
+// Entry statuses
+const (
+	StatusOK    = "ok"
+	StatusError = "error"
+)
+
+// HashArguments returns the first 16 hex digits of the SHA-256 of a call's JSON
+// arguments, enough to group identical calls without storing what they contained
+func HashArguments(args []byte) string {
+	sum := sha256.Sum256(args)
+	return hex.EncodeToString(sum[:8])
+}
+
+// Rotation bounds the size of an audit log. Zero fields use the defaults.
+type Rotation struct {
+	MaxBytes   int64 // Size at which the file is rotated
+	MaxBackups int   // Rotated files kept, as path.1 (newest) to path.N
+}
+
+// Rotation defaults
+const (
+	DefaultMaxBytes   = 100 << 20
+	DefaultMaxBackups = 5
+)
+
+// Log appends entries to a file, rotating it when it reaches its maximum size
+type Log struct {
+	path     string
+	rotation Rotation
+
+	mu   sync.Mutex
+	file *os.File
+	size int64
+}
+
+// Open opens or creates the audit log at path, appending to what it holds
+func Open(path string, rotation Rotation) (*Log, error) {
+	if rotation.MaxBytes <= 0 {
+		rotation.MaxBytes = DefaultMaxBytes
+	}
+	if rotation.MaxBackups <= 0 {
+		rotation.MaxBackups = DefaultMaxBackups
+	}
+	l := &Log{path: path, rotation: rotation}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create audit log directory: %w", err)
+	}
+	if err := l.open(); err != nil {
+		return nil, err
+	}
+	return l, nil
+}
+
+func (l *Log) open() error {
+	f, err := os.OpenFile(l.path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o600)
+	if err != nil {
+		return fmt.Errorf("failed to open audit log: %w", err)
+	}
+	info, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return fmt.Errorf("failed to open audit log: %w", err)
+	}
+	l.file, l.size = f, info.Size()
+	return nil
+}
+
+// Write appends an entry, rotating the file first if the entry would take it past
+// its maximum size
+func (l *Log) Write(e Entry) error {
+	line, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+	line = append(line, '\n')
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.file == nil {
+		return fmt.Errorf("audit log %s is closed", l.path)
+	}
+	if l.size > 0 && l.size+int64(len(line)) > l.rotation.MaxBytes {
+		if err := l.rotate(); err != nil {
+			return err
+		}
+	}
+	n, err := l.file.Write(line)
+	l.size += int64(n)
+	if err != nil {
+		return fmt.Errorf("failed to write audit log: %w", err)
+	}
+	return nil
+}
+
+// rotate shifts path.N-1 to path.N down to path to path.1, dropping the oldest, and
+// starts a new file. Callers hold l.mu.
+func (l *Log) rotate() error {
+	if err := l.file.Close(); err != nil {
+		return fmt.Errorf("failed to rotate audit log: %w", err)
+	}
+	l.file = nil
+	for i := l.rotation.MaxBackups - 1; i >= 1; i-- {
+		err := os.Rename(fmt.Sprintf("%s.%d", l.path, i), fmt.Sprintf("%s.%d", l.path, i+1))
+		if err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("failed to rotate audit log: %w", err)
+		}
+	}
+	if err := os.Rename(l.path, l.path+".1"); err != nil {
+		return fmt.Errorf("failed to rotate audit log: %w", err)
+	}
+	return l.open()
+}
+
+// Close closes the file. Later writes fail.
+func (l *Log) Close() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.file == nil {
+		return nil
+	}
+	err := l.file.Close()
+	l.file = nil
+	return err
+}
@@ -0,0 +1,337 @@
+// Package auth authenticates requests to the HTTP transports with static API keys or
+// OAuth access tokens, and limits how fast each caller may send requests.
+package auth
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/carlisia/mcp-factcheck/internal/secrets"
+	"github.com/carlisia/mcp-factcheck/pkg/logger"
+	"go.uber.org/zap"
+)
+
+// APIKeyHeader carries an API key for clients that can't send it as a bearer token
+const APIKeyHeader = "X-API-Key"
+
+// Methods by which a caller authenticated
+const (
+	MethodAPIKey = "api_key"
+	MethodOAuth  = "oauth"
+)
+
+// Config configures authentication of the HTTP transports. It is read once at startup.
+type Config struct {
+	APIKeys   []APIKeyConfig `json:"api_keys,omitempty"`
+	OAuth     *OAuthConfig   `json:"oauth,omitempty"`
+	RateLimit RateLimit      `json:"rate_limit"` // Limit of each caller without its own
+}
+
+// APIKeyConfig describes one static API key
+type APIKeyConfig struct {
+	Name      string     `json:"name"`                 // Identifies the caller in logs and usage
+	Secret    string     `json:"secret"`               // Secret name holding the key (env var, *_FILE, /run/secrets, or the macOS Keychain)
+	RateLimit *RateLimit `json:"rate_limit,omitempty"` // Overrides the default limit for this key
+}
+
+// RateLimit caps how fast one caller may send requests
+type RateLimit struct {
+	RequestsPerMinute int `json:"requests_per_minute"` // Steady rate; 0 disables the limit
+	Burst             int `json:"burst"`               // Requests that may arrive at once before the rate applies
+}
+
+// Enabled reports whether the config authenticates anything
+func (c Config) Enabled() bool {
+	return len(c.APIKeys) > 0 || c.OAuth != nil
+}
+
+// Validate checks the config without resolving its secrets
+func (c Config) Validate() error {
+	if err := c.RateLimit.Validate(); err != nil {
+		return err
+	}
+	names := map[string]bool{}
+	for _, key := range c.APIKeys {
+		if key.Name == "" || key.Secret == "" {
+			return errors.New("every API key needs a name and secret")
+		}
+		if names[key.Name] {
+			return fmt.Errorf("duplicate API key name: %s", key.Name)
+		}
+		names[key.Name] = true
+		if key.RateLimit != nil {
+			if err := key.RateLimit.Validate(); err != nil {
+				return fmt.Errorf("API key %s: %w", key.Name, err)
+			}
+		}
+	}
+	if c.OAuth != nil {
+		return c.OAuth.Validate()
+	}
+	return nil
+}
+
+// Validate checks that the limit is usable
+func (l RateLimit) Validate() error {
+	if l.RequestsPerMinute < 0 || l.Burst < 0 {
+		return errors.New("rate limits must not be negative")
+	}
+	if l.RequestsPerMinute > 0 && l.Burst < 1 {
+		return errors.New("burst must be at least 1 when requests_per_minute is set")
+	}
+	return nil
+}
+
+// Identity is an authenticated caller
+type Identity struct {
+	Name   string // API key name, or the OAuth token's subject or client
+	Method string // MethodAPIKey or MethodOAuth
+}
+
+// Usage counts one caller's requests
+type Usage struct {
+	Method      string    `json:"method"`
+	Requests    int64     `json:"requests"`
+	Errors      int64     `json:"errors"`       // Requests answered with a 4xx or 5xx status
+	RateLimited int64     `json:"rate_limited"` // Requests rejected by the rate limit, not counted in Requests
+	LastRequest time.Time `json:"last_request"`
+}
+
+// caller is the rate limit and usage of one identity
+type caller struct {
+	limit  RateLimit
+	tokens float64
+	filled time.Time
+	usage  Usage
+}
+
+// allow takes a token from the caller's bucket, or returns how long until one is free
+func (c *caller) allow(now time.Time) (bool, time.Duration) {
+	if c.limit.RequestsPerMinute <= 0 {
+		return true, 0
+	}
+	perSecond := float64(c.limit.RequestsPerMinute) / 60
+	if c.filled.IsZero() {
+		c.tokens = float64(c.limit.Burst)
+	} else {
+		c.tokens = math.Min(float64(c.limit.Burst), c.tokens+now.Sub(c.filled).Seconds()*perSecond)
+	}
+	c.filled = now
+	if c.tokens >= 1 {
+		c.tokens--
+		return true, 0
+	}
+	return false, time.Duration((1 - c.tokens) / perSecond * float64(time.Second))
+}
+
+// apiKey is a configured key with its secret resolved
+type apiKey struct {
+	name  string
+	key   string
+	limit RateLimit
+}
+
+// Authenticator checks the credentials of HTTP requests
+type Authenticator struct {
+	keys         []apiKey
+	oauth        *introspector
+	defaultLimit RateLimit
+
+	mu      sync.Mutex
+	callers map[string]*caller // By method and name
+}
+
+// New resolves the config's secrets and creates an authenticator
+func New(cfg Config) (*Authenticator, error) {
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	a := &Authenticator{defaultLimit: cfg.RateLimit, callers: map[string]*caller{}}
+	for _, key := range cfg.APIKeys {
+		value, err := secrets.Lookup(key.Secret)
+		if err != nil {
+			return nil, fmt.Errorf("API key %s: %w", key.Name, err)
+		}
+		limit := cfg.RateLimit
+		if key.RateLimit != nil {
+			limit = *key.RateLimit
+		}
+		a.keys = append(a.keys, apiKey{name: key.Name, key: value, limit: limit})
+	}
+	if cfg.OAuth != nil {
+		oauth, err := newIntrospector(*cfg.OAuth)
+		if err != nil {
+			return nil, err
+		}
+		a.oauth = oauth
+	}
+	return a, nil
+}
+
+// Middleware rejects requests without valid credentials or over their caller's rate
+// limit, and passes the others to next with the caller's Identity in their context
+func (a *Authenticator) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id, limit, err := a.identify(r)
+		if err != nil {
+			a.reject(w, r, err)
+			return
+		}
+
+		c := a.caller(id, limit)
+		a.mu.Lock()
+		ok, wait := c.allow(time.Now())
+		if !ok {
+			c.usage.RateLimited++
+		}
+		a.mu.Unlock()
+		if !ok {
+			w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(wait.Seconds()))))
+			writeError(w, http.StatusTooManyRequests, fmt.Sprintf("rate limit of %d requests per minute exceeded", limit.RequestsPerMinute))
+			return
+		}
+
+		recorder := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+		next.ServeHTTP(recorder, r.WithContext(WithIdentity(r.Context(), id)))
+
+		a.mu.Lock()
+		c.usage.Requests++
+		if recorder.status >= 400 {
+			c.usage.Errors++
+		}
+		c.usage.LastRequest = time.Now()
+		a.mu.Unlock()
+	})
+}
+
+// identify returns the caller a request's credentials belong to, and its rate limit
+func (a *Authenticator) identify(r *http.Request) (Identity, RateLimit, error) {
+	token := r.Header.Get(APIKeyHeader)
+	if token == "" {
+		token, _ = strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	}
+	token = strings.TrimSpace(token)
+	if token == "" {
+		return Identity{}, RateLimit{}, errMissingToken
+	}
+
+	for _, key := range a.keys {
+		if subtle.ConstantTimeCompare([]byte(key.key), []byte(token)) == 1 {
+			return Identity{Name: key.name, Method: MethodAPIKey}, key.limit, nil
+		}
+	}
+	if a.oauth == nil {
+		return Identity{}, RateLimit{}, errInvalidToken
+	}
+	subject, err := a.oauth.introspect(r.Context(), token)
+	if err != nil {
+		return Identity{}, RateLimit{}, err
+	}
+	return Identity{Name: subject, Method: MethodOAuth}, a.defaultLimit, nil
+}
+
+func (a *Authenticator) caller(id Identity, limit RateLimit) *caller {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	k := id.Method + ":" + id.Name
+	c, ok := a.callers[k]
+	if !ok {
+		c = &caller{limit: limit, usage: Usage{Method: id.Method}}
+		a.callers[k] = c
+	}
+	return c
+}
+
+// reject answers a request whose credentials failed, with the WWW-Authenticate
+// challenge RFC 6750 and the MCP authorization spec call for
+func (a *Authenticator) reject(w http.ResponseWriter, r *http.Request, err error) {
+	challenge := `Bearer realm="mcp-factcheck"`
+	if a.oauth != nil {
+		challenge += fmt.Sprintf(`, resource_metadata=%q`, a.oauth.metadataURL())
+	}
+	status := http.StatusUnauthorized
+	var scopeErr *scopeError
+	switch {
+	case errors.As(err, &scopeErr):
+		status = http.StatusForbidden
+		challenge += fmt.Sprintf(`, error="insufficient_scope", scope=%q`, strings.Join(scopeErr.required, " "))
+	case !errors.Is(err, errMissingToken):
+		challenge += `, error="invalid_token"`
+	}
+	if !errors.Is(err, errMissingToken) && !errors.Is(err, errInvalidToken) {
+		logger.WithRequestID(r.Context()).Info("Rejected bearer token", zap.Error(err))
+	}
+	w.Header().Set("WWW-Authenticate", challenge)
+	writeError(w, status, err.Error())
+}
+
+// Usage returns a copy of every caller's usage, keyed by method and name
+func (a *Authenticator) Usage() map[string]Usage {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	usage := make(map[string]Usage, len(a.callers))
+	for k, c := range a.callers {
+		usage[k] = c.usage
+	}
+	return usage
+}
+
+// ProtectedResourceHandler serves this server's OAuth protected resource metadata
+// (RFC 9728), which tells MCP clients where to get tokens. It is nil unless OAuth is
+// configured.
+func (a *Authenticator) ProtectedResourceHandler() http.Handler {
+	if a.oauth == nil {
+		return nil
+	}
+	return http.HandlerFunc(a.oauth.serveMetadata)
+}
+
+var (
+	errMissingToken = errors.New("missing bearer token or API key")
+	errInvalidToken = errors.New("invalid bearer token or API key")
+)
+
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+func (r *statusRecorder) WriteHeader(status int) {
+	r.status = status
+	r.ResponseWriter.WriteHeader(status)
+}
+
+// Flush lets streaming responses, such as SSE, through the recorder
+func (r *statusRecorder) Flush() {
+	if f, ok := r.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+func writeError(w http.ResponseWriter, status int, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(map[string]string{"error": message})
+}
+
+type identityKey struct{}
+
+// WithIdentity stores the authenticated caller in the context
+func WithIdentity(ctx context.Context, id Identity) context.Context {
+	return context.WithValue(ctx, identityKey{}, id)
+}
+
+// FromContext returns the authenticated caller of the current request, if any
+func FromContext(ctx context.Context) (Identity, bool) {
+	id, ok := ctx.Value(identityKey{}).(Identity)
+	return id, ok
+}
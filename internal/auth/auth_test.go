@@ -0,0 +1,71 @@
+package auth
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestMiddleware(t *testing.T) {
+	t.Setenv("TEST_CI_KEY", "ci-secret")
+	server := newIntrospectionServer(t, map[string]string{
+		"oauth-token":   `{"active":true,"sub":"alice","aud":"` + testResource + `","scope":"factcheck"}`,
+		"missing-scope": `{"active":true,"sub":"alice","aud":"` + testResource + `","scope":"read"}`,
+		"no-audience":   `{"active":true,"sub":"alice","scope":"factcheck"}`,
+	})
+	oauth := testOAuthConfig(server.URL)
+	a, err := New(Config{
+		APIKeys:   []APIKeyConfig{{Name: "ci", Secret: "TEST_CI_KEY"}},
+		OAuth:     &oauth,
+		RateLimit: RateLimit{RequestsPerMinute: 1, Burst: 1},
+	})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	handler := a.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id, _ := FromContext(r.Context())
+		_, _ = w.Write([]byte(id.Method + ":" + id.Name))
+	}))
+
+	tests := []struct {
+		name      string
+		header    string // Header carrying the token
+		token     string
+		status    int
+		body      string // Text the response body must contain
+		challenge string // Text the WWW-Authenticate header must contain, for rejections
+	}{
+		{name: "no token", status: http.StatusUnauthorized, challenge: `resource_metadata="` + testResource + ProtectedResourcePath + `"`},
+		{name: "API key header", header: APIKeyHeader, token: "ci-secret", status: http.StatusOK, body: "api_key:ci"},
+		{name: "API key over its rate limit", header: "Authorization", token: "Bearer ci-secret", status: http.StatusTooManyRequests},
+		{name: "OAuth token", header: "Authorization", token: "Bearer oauth-token", status: http.StatusOK, body: "oauth:alice"},
+		{name: "unknown token", header: "Authorization", token: "Bearer nope", status: http.StatusUnauthorized, challenge: `error="invalid_token"`},
+		{name: "token without audience", header: "Authorization", token: "Bearer no-audience", status: http.StatusUnauthorized, challenge: `error="invalid_token"`},
+		{name: "token missing a scope", header: "Authorization", token: "Bearer missing-scope", status: http.StatusForbidden, challenge: `error="insufficient_scope", scope="factcheck"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/mcp", nil)
+			if tt.header != "" {
+				req.Header.Set(tt.header, tt.token)
+			}
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+			if rec.Code != tt.status {
+				t.Fatalf("status = %d, want %d: %s", rec.Code, tt.status, rec.Body)
+			}
+			if !strings.Contains(rec.Body.String(), tt.body) {
+				t.Errorf("body = %q, want it to contain %q", rec.Body, tt.body)
+			}
+			if challenge := rec.Header().Get("WWW-Authenticate"); !strings.Contains(challenge, tt.challenge) {
+				t.Errorf("WWW-Authenticate = %q, want it to contain %q", challenge, tt.challenge)
+			}
+		})
+	}
+
+	usage := a.Usage()
+	if got := usage["api_key:ci"]; got.Requests != 1 || got.RateLimited != 1 {
+		t.Errorf("ci usage = %+v, want 1 request and 1 rate limited", got)
+	}
+}
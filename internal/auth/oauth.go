@@ -0,0 +1,224 @@
+package auth
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/carlisia/mcp-factcheck/internal/secrets"
+)
+
+// ProtectedResourcePath is where the OAuth protected resource metadata is served
+const ProtectedResourcePath = "/.well-known/oauth-protected-resource"
+
+// introspectionCacheTTL bounds how long a token's introspection result is reused
+const introspectionCacheTTL = time.Minute
+
+// OAuthConfig validates OAuth access tokens, as the MCP authorization spec has
+// resource servers do: tokens are checked with the authorization server's token
+// introspection endpoint (RFC 7662) and must be issued for this server.
+type OAuthConfig struct {
+	Resource             string   `json:"resource"`                         // This server's canonical URL; tokens' audience must name it
+	AuthorizationServer  string   `json:"authorization_server"`             // Issuer URL advertised to clients in the protected resource metadata
+	IntrospectionURL     string   `json:"introspection_url"`                // RFC 7662 endpoint of the authorization server
+	ClientID             string   `json:"client_id"`                        // This server's client ID at the introspection endpoint
+	ClientSecret         string   `json:"client_secret,omitempty"`          // Secret name holding its client secret
+	Scopes               []string `json:"scopes,omitempty"`                 // Scopes every token must carry
+	AllowMissingAudience bool     `json:"allow_missing_audience,omitempty"` // Accept tokens without an audience, for authorization servers that don't report one
+}
+
+// Validate checks that the settings are usable
+func (c OAuthConfig) Validate() error {
+	for name, value := range map[string]string{
+		"resource":             c.Resource,
+		"authorization_server": c.AuthorizationServer,
+		"introspection_url":    c.IntrospectionURL,
+	} {
+		u, err := url.Parse(value)
+		if err != nil || u.Host == "" || (u.Scheme != "https" && u.Hostname() != "localhost" && u.Hostname() != "127.0.0.1") {
+			return fmt.Errorf("oauth %s must be an https URL", name)
+		}
+	}
+	if c.ClientID == "" {
+		return errors.New("oauth client_id is required")
+	}
+	return nil
+}
+
+// introspector validates tokens at an introspection endpoint, caching the results
+type introspector struct {
+	cfg          OAuthConfig
+	clientSecret string
+	client       *http.Client
+
+	mu    sync.Mutex
+	cache map[[sha256.Size]byte]introspection
+}
+
+// introspection is a cached result of introspecting a token
+type introspection struct {
+	subject string
+	err     error
+	expires time.Time
+}
+
+// scopeError reports a valid token without the scopes the server requires
+type scopeError struct {
+	required []string
+}
+
+func (e *scopeError) Error() string {
+	return "token lacks required scopes: " + strings.Join(e.required, " ")
+}
+
+func newIntrospector(cfg OAuthConfig) (*introspector, error) {
+	i := &introspector{
+		cfg:    cfg,
+		client: &http.Client{Timeout: 10 * time.Second},
+		cache:  map[[sha256.Size]byte]introspection{},
+	}
+	if cfg.ClientSecret != "" {
+		secret, err := secrets.Lookup(cfg.ClientSecret)
+		if err != nil {
+			return nil, fmt.Errorf("oauth client_secret: %w", err)
+		}
+		i.clientSecret = secret
+	}
+	return i, nil
+}
+
+// introspect returns the subject of a valid token
+func (i *introspector) introspect(ctx context.Context, token string) (string, error) {
+	key := sha256.Sum256([]byte(token))
+	now := time.Now()
+	i.mu.Lock()
+	cached, ok := i.cache[key]
+	i.mu.Unlock()
+	if ok && now.Before(cached.expires) {
+		return cached.subject, cached.err
+	}
+
+	result, err := i.request(ctx, token)
+	if err != nil {
+		return "", err // The endpoint failed, so don't cache the outcome
+	}
+	i.mu.Lock()
+	for k, entry := range i.cache {
+		if !now.Before(entry.expires) {
+			delete(i.cache, k)
+		}
+	}
+	i.cache[key] = result
+	i.mu.Unlock()
+	return result.subject, result.err
+}
+
+// request asks the introspection endpoint about token
+func (i *introspector) request(ctx context.Context, token string) (introspection, error) {
+	form := url.Values{"token": {token}, "token_type_hint": {"access_token"}}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, i.cfg.IntrospectionURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return introspection{}, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+	req.SetBasicAuth(url.QueryEscape(i.cfg.ClientID), url.QueryEscape(i.clientSecret))
+
+	resp, err := i.client.Do(req)
+	if err != nil {
+		return introspection{}, fmt.Errorf("token introspection failed: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return introspection{}, fmt.Errorf("token introspection failed: %s", resp.Status)
+	}
+
+	var body struct {
+		Active   bool     `json:"active"`
+		Subject  string   `json:"sub"`
+		ClientID string   `json:"client_id"`
+		Scope    string   `json:"scope"`
+		Audience audience `json:"aud"`
+		Expiry   int64    `json:"exp"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return introspection{}, fmt.Errorf("token introspection returned invalid JSON: %w", err)
+	}
+
+	result := introspection{subject: body.Subject, expires: time.Now().Add(introspectionCacheTTL)}
+	if body.Expiry > 0 {
+		if exp := time.Unix(body.Expiry, 0); exp.Before(result.expires) {
+			result.expires = exp
+		}
+	}
+	if result.subject == "" {
+		result.subject = body.ClientID
+	}
+	granted := strings.Fields(body.Scope)
+	switch {
+	case !body.Active:
+		result.err = errInvalidToken
+	case len(body.Audience) == 0 && !i.cfg.AllowMissingAudience:
+		// A token without an audience could have been issued for any resource
+		result.err = errors.New("token has no audience")
+	case len(body.Audience) > 0 && !slices.Contains(body.Audience, i.cfg.Resource):
+		result.err = errors.New("token was not issued for this server")
+	case result.subject == "":
+		result.err = errors.New("token has no subject or client")
+	default:
+		var missing []string
+		for _, scope := range i.cfg.Scopes {
+			if !slices.Contains(granted, scope) {
+				missing = append(missing, scope)
+			}
+		}
+		if missing != nil {
+			result.err = &scopeError{required: i.cfg.Scopes}
+		}
+	}
+	return result, nil
+}
+
+// metadataURL returns the URL of the protected resource metadata
+func (i *introspector) metadataURL() string {
+	u, err := url.Parse(i.cfg.Resource)
+	if err != nil {
+		return ProtectedResourcePath
+	}
+	return u.Scheme + "://" + u.Host + ProtectedResourcePath
+}
+
+func (i *introspector) serveMetadata(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(map[string]any{
+		"resource":                 i.cfg.Resource,
+		"authorization_servers":    []string{i.cfg.AuthorizationServer},
+		"scopes_supported":         i.cfg.Scopes,
+		"bearer_methods_supported": []string{"header"},
+	})
+}
+
+// audience decodes the aud claim, which is a string or an array of strings
+type audience []string
+
+func (a *audience) UnmarshalJSON(data []byte) error {
+	var one string
+	if err := json.Unmarshal(data, &one); err == nil {
+		*a = audience{one}
+		return nil
+	}
+	var many []string
+	if err := json.Unmarshal(data, &many); err != nil {
+		return err
+	}
+	*a = many
+	return nil
+}
@@ -0,0 +1,197 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+const testResource = "https://factcheck.example.com"
+
+// introspectionServer answers introspection requests with the body given for each
+// token, and counts the requests
+type introspectionServer struct {
+	*httptest.Server
+	requests atomic.Int64
+}
+
+func newIntrospectionServer(t *testing.T, bodies map[string]string) *introspectionServer {
+	t.Helper()
+	s := &introspectionServer{}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s.requests.Add(1)
+		if id, _, ok := r.BasicAuth(); !ok || id != "mcp-factcheck" {
+			http.Error(w, "unknown client", http.StatusUnauthorized)
+			return
+		}
+		body, ok := bodies[r.PostFormValue("token")]
+		if !ok {
+			body = `{"active":false}`
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(body))
+	}))
+	t.Cleanup(s.Close)
+	return s
+}
+
+func testOAuthConfig(introspectionURL string) OAuthConfig {
+	return OAuthConfig{
+		Resource:            testResource,
+		AuthorizationServer: "https://auth.example.com",
+		IntrospectionURL:    introspectionURL,
+		ClientID:            "mcp-factcheck",
+		Scopes:              []string{"factcheck"},
+	}
+}
+
+func TestIntrospect(t *testing.T) {
+	bodies := map[string]string{
+		"valid":          `{"active":true,"sub":"alice","aud":"` + testResource + `","scope":"factcheck read"}`,
+		"audience list":  `{"active":true,"sub":"alice","aud":["https://other.example.com","` + testResource + `"],"scope":"factcheck"}`,
+		"client only":    `{"active":true,"client_id":"ci-bot","aud":"` + testResource + `","scope":"factcheck"}`,
+		"revoked":        `{"active":false,"sub":"alice","aud":"` + testResource + `","scope":"factcheck"}`,
+		"wrong audience": `{"active":true,"sub":"alice","aud":"https://other.example.com","scope":"factcheck"}`,
+		"no audience":    `{"active":true,"sub":"alice","scope":"factcheck"}`,
+		"empty audience": `{"active":true,"sub":"alice","aud":[],"scope":"factcheck"}`,
+		"missing scope":  `{"active":true,"sub":"alice","aud":"` + testResource + `","scope":"read"}`,
+		"no subject":     `{"active":true,"aud":"` + testResource + `","scope":"factcheck"}`,
+		"invalid json":   `{"active":`,
+	}
+	server := newIntrospectionServer(t, bodies)
+
+	tests := []struct {
+		token                string
+		allowMissingAudience bool
+		want                 string // Subject returned for a valid token
+		wantErr              string // Text the error must contain
+		scopeErr             bool   // Whether the error must be a scopeError
+	}{
+		{token: "valid", want: "alice"},
+		{token: "audience list", want: "alice"},
+		{token: "client only", want: "ci-bot"},
+		{token: "unknown", wantErr: errInvalidToken.Error()},
+		{token: "revoked", wantErr: errInvalidToken.Error()},
+		{token: "wrong audience", wantErr: "not issued for this server"},
+		{token: "wrong audience", allowMissingAudience: true, wantErr: "not issued for this server"},
+		{token: "no audience", wantErr: "no audience"},
+		{token: "empty audience", wantErr: "no audience"},
+		{token: "no audience", allowMissingAudience: true, want: "alice"},
+		{token: "missing scope", wantErr: "lacks required scopes: factcheck", scopeErr: true},
+		{token: "no subject", wantErr: "no subject or client"},
+		{token: "invalid json", wantErr: "invalid JSON"},
+	}
+	for _, tt := range tests {
+		name := tt.token
+		if tt.allowMissingAudience {
+			name += " allowing missing audience"
+		}
+		t.Run(name, func(t *testing.T) {
+			cfg := testOAuthConfig(server.URL)
+			cfg.AllowMissingAudience = tt.allowMissingAudience
+			i, err := newIntrospector(cfg)
+			if err != nil {
+				t.Fatal(err)
+			}
+			subject, err := i.introspect(context.Background(), tt.token)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("introspect = %q, %v; want error %q", subject, err, tt.wantErr)
+				}
+				var scopeErr *scopeError
+				if errors.As(err, &scopeErr) != tt.scopeErr {
+					t.Errorf("error %v is a scopeError: %v, want %v", err, !tt.scopeErr, tt.scopeErr)
+				}
+				return
+			}
+			if err != nil || subject != tt.want {
+				t.Fatalf("introspect = %q, %v; want %q", subject, err, tt.want)
+			}
+		})
+	}
+}
+
+func TestIntrospectionCache(t *testing.T) {
+	soon := time.Now().Add(10 * time.Second)
+	server := newIntrospectionServer(t, map[string]string{
+		"valid":    `{"active":true,"sub":"alice","aud":"` + testResource + `","scope":"factcheck"}`,
+		"expiring": `{"active":true,"sub":"bob","aud":"` + testResource + `","scope":"factcheck","exp":` + strconv.FormatInt(soon.Unix(), 10) + `}`,
+	})
+	i, err := newIntrospector(testOAuthConfig(server.URL))
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+	introspect := func(token string, wantRequests int64) {
+		t.Helper()
+		if _, err := i.introspect(ctx, token); err != nil {
+			t.Fatalf("introspect(%s): %v", token, err)
+		}
+		if got := server.requests.Load(); got != wantRequests {
+			t.Fatalf("after introspect(%s), endpoint saw %d requests, want %d", token, got, wantRequests)
+		}
+	}
+
+	expireAll := func() {
+		i.mu.Lock()
+		defer i.mu.Unlock()
+		for k, entry := range i.cache {
+			entry.expires = time.Now().Add(-time.Second)
+			i.cache[k] = entry
+		}
+	}
+
+	introspect("valid", 1)
+	introspect("valid", 1) // Cached
+
+	// Once the entry expires, the token is introspected again
+	expireAll()
+	introspect("valid", 2)
+
+	// A token expiring before the TTL is cached only until it expires, and
+	// introspecting it removes the stale entries
+	expireAll()
+	introspect("expiring", 3)
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	if len(i.cache) != 1 {
+		t.Errorf("cache holds %d entries, want only the expiring token's", len(i.cache))
+	}
+	for _, entry := range i.cache {
+		if !entry.expires.Equal(soon.Truncate(time.Second)) {
+			t.Errorf("entry expires at %v, want the token's expiry %v", entry.expires, soon.Truncate(time.Second))
+		}
+	}
+}
+
+func TestIntrospectionEndpointFailure(t *testing.T) {
+	var fail atomic.Bool
+	fail.Store(true)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if fail.Load() {
+			http.Error(w, "unavailable", http.StatusServiceUnavailable)
+			return
+		}
+		_, _ = w.Write([]byte(`{"active":true,"sub":"alice","aud":"` + testResource + `","scope":"factcheck"}`))
+	}))
+	defer server.Close()
+	i, err := newIntrospector(testOAuthConfig(server.URL))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := i.introspect(context.Background(), "valid"); err == nil || !strings.Contains(err.Error(), "503") {
+		t.Fatalf("introspect err = %v, want the endpoint's status", err)
+	}
+	// The failure isn't cached, so the token works once the endpoint recovers
+	fail.Store(false)
+	if subject, err := i.introspect(context.Background(), "valid"); err != nil || subject != "alice" {
+		t.Fatalf("introspect after recovery = %q, %v; want alice", subject, err)
+	}
+}
@@ -0,0 +1,267 @@
+// Package bootstrap populates an empty data directory with prebuilt embeddings
+// downloaded from a release, so a container started on a fresh volume can serve
+// without generating or copying embeddings first.
+//
+// A release publishes a gzip-compressed tar archive of a data directory, such as
+//
+//	tar czf embeddings.tar.gz -C data/embeddings .
+//	sha256sum embeddings.tar.gz > embeddings.tar.gz.sha256
+//
+// and the archive is only unpacked after its SHA-256 checksum matches.
+package bootstrap
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+
+	mcpembedding "github.com/carlisia/mcp-factcheck/internal/embedding"
+)
+
+// Environment variables that configure the download when no flags are given
+const (
+	URLEnvVar    = "MCP_FACTCHECK_BOOTSTRAP_URL"
+	SHA256EnvVar = "MCP_FACTCHECK_BOOTSTRAP_SHA256"
+)
+
+// ChecksumSuffix is appended to the archive URL to find its checksum file when no
+// checksum is given
+const ChecksumSuffix = ".sha256"
+
+// MaxArchiveSize caps how much is downloaded
+const MaxArchiveSize = 2 << 30
+
+// extensions are the files an archive may install: embeddings in either storage
+// format and their persisted search indexes
+var extensions = map[string]bool{".json": true, ".emb": true, ".hnsw": true}
+
+var client = &http.Client{Timeout: 10 * time.Minute}
+
+// Source is where to download embeddings from
+type Source struct {
+	URL    string // http(s) URL of a .tar.gz archive of a data directory
+	SHA256 string // Hex checksum of the archive; read from URL + ChecksumSuffix when empty
+}
+
+// FromEnv returns the source configured by URLEnvVar and SHA256EnvVar
+func FromEnv() Source {
+	return Source{URL: os.Getenv(URLEnvVar), SHA256: os.Getenv(SHA256EnvVar)}
+}
+
+// Enabled reports whether the source names an archive
+func (s Source) Enabled() bool {
+	return s.URL != ""
+}
+
+// Validate checks the source without downloading anything
+func (s Source) Validate() error {
+	u, err := url.Parse(s.URL)
+	if err != nil || u.Host == "" || (u.Scheme != "https" && u.Scheme != "http") {
+		return fmt.Errorf("bootstrap URL must be an http(s) URL: %q", s.URL)
+	}
+	if s.SHA256 != "" {
+		if _, err := parseChecksum(s.SHA256); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Install downloads the archive, verifies its checksum, and unpacks its embeddings
+// into dir, skipping files dir already has. It returns the files it wrote, relative
+// to dir. Files are written through temporary files, so an interrupted install, or
+// another replica installing into the same volume, never leaves a partial file behind.
+func Install(ctx context.Context, dir string, src Source) ([]string, error) {
+	if err := src.Validate(); err != nil {
+		return nil, err
+	}
+	want := src.SHA256
+	if want == "" {
+		var err error
+		if want, err = fetchChecksum(ctx, src.URL+ChecksumSuffix); err != nil {
+			return nil, err
+		}
+	}
+	sum, err := parseChecksum(want)
+	if err != nil {
+		return nil, err
+	}
+
+	archive, err := download(ctx, dir, src.URL, sum)
+	if err != nil {
+		return nil, err
+	}
+	defer os.Remove(archive)
+	return unpack(archive, dir)
+}
+
+// download saves the archive to a temporary file in dir and checks its checksum
+func download(ctx context.Context, dir, rawURL string, sum []byte) (string, error) {
+	resp, err := get(ctx, rawURL)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	tmp, err := os.CreateTemp(dir, ".bootstrap-*.tar.gz")
+	if err != nil {
+		return "", err
+	}
+	hash := sha256.New()
+	n, err := io.Copy(io.MultiWriter(tmp, hash), io.LimitReader(resp.Body, MaxArchiveSize+1))
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	switch {
+	case err != nil:
+		err = fmt.Errorf("failed to download %s: %w", rawURL, err)
+	case n > MaxArchiveSize:
+		err = fmt.Errorf("%s is larger than %d bytes", rawURL, MaxArchiveSize)
+	case !bytes.Equal(hash.Sum(nil), sum):
+		err = fmt.Errorf("checksum mismatch for %s: got %x, want %x", rawURL, hash.Sum(nil), sum)
+	}
+	if err != nil {
+		os.Remove(tmp.Name())
+		return "", err
+	}
+	return tmp.Name(), nil
+}
+
+// unpack extracts the embeddings in a verified archive into dir
+func unpack(archive, dir string) ([]string, error) {
+	file, err := os.Open(archive)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	gz, err := gzip.NewReader(file)
+	if err != nil {
+		return nil, fmt.Errorf("bootstrap archive is not gzip-compressed: %w", err)
+	}
+	defer gz.Close()
+
+	var installed []string
+	tr := tar.NewReader(gz)
+	for {
+		header, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return installed, fmt.Errorf("failed to read bootstrap archive: %w", err)
+		}
+		name, ok := target(header)
+		if !ok {
+			continue
+		}
+		dest := filepath.Join(dir, filepath.FromSlash(name))
+		if _, err := os.Stat(dest); err == nil {
+			continue
+		}
+		if err := write(tr, dest); err != nil {
+			return installed, fmt.Errorf("failed to install %s: %w", name, err)
+		}
+		installed = append(installed, name)
+	}
+	if len(installed) == 0 {
+		return nil, errors.New("bootstrap archive holds no new embeddings")
+	}
+	return installed, nil
+}
+
+// target returns where an archive entry is installed, relative to the data
+// directory. Only regular embedding files at the top level or in the corpora
+// directory are installed; everything else is ignored.
+func target(header *tar.Header) (string, bool) {
+	if header.Typeflag != tar.TypeReg {
+		return "", false
+	}
+	name := path.Clean(strings.TrimPrefix(header.Name, "./"))
+	dir, base := path.Split(name)
+	if !extensions[path.Ext(base)] || strings.HasPrefix(base, ".") {
+		return "", false
+	}
+	if dir != "" && dir != mcpembedding.CorporaDir+"/" {
+		return "", false
+	}
+	return name, true
+}
+
+// write copies one archive entry to dest through a temporary file
+func write(r io.Reader, dest string) error {
+	if err := os.MkdirAll(filepath.Dir(dest), 0o755); err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(dest), ".bootstrap-*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := io.Copy(tmp, r); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Chmod(0o644); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), dest)
+}
+
+// fetchChecksum reads a checksum file in sha256sum format
+func fetchChecksum(ctx context.Context, rawURL string) (string, error) {
+	resp, err := get(ctx, rawURL)
+	if err != nil {
+		return "", fmt.Errorf("no checksum given and %w", err)
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 4096))
+	if err != nil {
+		return "", fmt.Errorf("failed to read %s: %w", rawURL, err)
+	}
+	fields := strings.Fields(string(body))
+	if len(fields) == 0 {
+		return "", fmt.Errorf("%s is empty", rawURL)
+	}
+	return fields[0], nil
+}
+
+func get(ctx context.Context, rawURL string) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, rawURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to download %s: %w", rawURL, err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		resp.Body.Close()
+		return nil, fmt.Errorf("failed to download %s: %s", rawURL, resp.Status)
+	}
+	return resp, nil
+}
+
+func parseChecksum(s string) ([]byte, error) {
+	sum, err := hex.DecodeString(strings.TrimSpace(s))
+	if err != nil || len(sum) != sha256.Size {
+		return nil, fmt.Errorf("invalid SHA-256 checksum: %q", s)
+	}
+	return sum, nil
+}
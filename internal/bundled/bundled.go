@@ -0,0 +1,103 @@
+// Package bundled carries gzip-compressed spec embeddings compiled into the binary,
+// so a fresh install can validate without generating or copying embeddings first.
+//
+// Source checkouts bundle nothing. Release builds populate the embeddings directory
+// with go generate before building:
+//
+//	go generate ./internal/bundled
+package bundled
+
+import (
+	"compress/gzip"
+	"embed"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/carlisia/mcp-factcheck/vectorstore"
+)
+
+//go:generate go run ../../utils/cmd bundle --data-dir ../../data/embeddings --output embeddings
+
+// suffix is the file name suffix of a bundled version, after the storage format's extension
+const suffix = ".gz"
+
+//go:embed embeddings
+var files embed.FS
+
+// Versions lists the spec versions with bundled embeddings
+func Versions() []string {
+	var versions []string
+	for _, name := range bundledFiles() {
+		versions = append(versions, strings.TrimSuffix(name, path.Ext(name)))
+	}
+	return versions
+}
+
+// bundledFiles returns the names the bundled embeddings decompress to, such as
+// 2025-06-18.emb
+func bundledFiles() []string {
+	entries, _ := fs.ReadDir(files, "embeddings")
+	var names []string
+	for _, entry := range entries {
+		if name, ok := strings.CutSuffix(entry.Name(), suffix); ok {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// Install decompresses the bundled embeddings into dir, skipping versions dir
+// already has in any format, and returns the versions it wrote
+func Install(dir string) ([]string, error) {
+	store := vectorstore.NewStore(dir)
+	var installed []string
+	for _, name := range bundledFiles() {
+		version := strings.TrimSuffix(name, path.Ext(name))
+		if _, err := store.Path(version); err == nil {
+			continue
+		}
+		if err := install(path.Join("embeddings", name+suffix), filepath.Join(dir, name)); err != nil {
+			return installed, fmt.Errorf("failed to install bundled embeddings for %s: %w", version, err)
+		}
+		installed = append(installed, version)
+	}
+	return installed, nil
+}
+
+// install decompresses one bundled file through a temporary file, so an interrupted
+// install never leaves a partial embeddings file behind
+func install(name, target string) error {
+	src, err := files.Open(name)
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+	gz, err := gzip.NewReader(src)
+	if err != nil {
+		return err
+	}
+	defer gz.Close()
+
+	tmp, err := os.CreateTemp(filepath.Dir(target), ".bundled-*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := io.Copy(tmp, gz); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Chmod(0o644); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), target)
+}
@@ -0,0 +1,181 @@
+// Package calibrate tunes the validator's similarity thresholds against labeled
+// examples. Each example is validated once to get its confidence, and thresholds are
+// swept over those confidences to find where flagging content as an issue is most
+// accurate.
+//
+// Examples are JSON Lines with the fields of an eval dataset record, so the output of
+// `factcheck eval export --labeled-only` is a calibration corpus as is:
+//
+//	{"id": "stdio-1", "input": "MCP servers talk to clients over stdio.", "label": "no_issue"}
+//	{"id": "graphql-1", "input": "MCP uses GraphQL subscriptions.", "label": "issue", "spec_version": "2025-06-18"}
+package calibrate
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"strings"
+
+	"github.com/carlisia/mcp-factcheck/internal/eval"
+)
+
+// Example is one labeled input
+type Example struct {
+	ID          string `json:"id"`
+	Input       string `json:"input"`
+	Label       string `json:"label"`                  // eval.LabelIssue or eval.LabelNoIssue
+	SpecVersion string `json:"spec_version,omitempty"` // The calibration's spec version when empty
+	ContextType string `json:"context_type,omitempty"`
+}
+
+// Issue reports whether the example is labeled as having a spec problem
+func (e Example) Issue() bool {
+	return e.Label == eval.LabelIssue
+}
+
+// Observation is the confidence the validator gave an example
+type Observation struct {
+	Example    Example
+	Confidence float64
+}
+
+// Point is how well one threshold separates the labeled examples. Content is flagged
+// as an issue when its confidence is at or below the threshold, as the validator
+// flags content whose similarity isn't above similarity_threshold.
+type Point struct {
+	Threshold float64 `json:"threshold"`
+	Precision float64 `json:"precision"` // Share of flagged examples labeled issue
+	Recall    float64 `json:"recall"`    // Share of issue examples flagged
+	F1        float64 `json:"f1"`
+	TP        int     `json:"tp"`
+	FP        int     `json:"fp"`
+	FN        int     `json:"fn"`
+	TN        int     `json:"tn"`
+}
+
+// Result is the outcome of a calibration
+type Result struct {
+	SimilarityThreshold    float64 `json:"similarity_threshold"`     // Threshold with the best F1
+	LowSimilarityThreshold float64 `json:"low_similarity_threshold"` // Highest threshold at or below it flagging with the wanted precision
+	Best                   Point   `json:"best"`
+	Critical               Point   `json:"critical"`
+	Points                 []Point `json:"points"`
+}
+
+// Step is the spacing of swept thresholds
+const Step = 0.01
+
+// MinExamples is the number of examples below which a calibration is reported as unreliable
+const MinExamples = 20
+
+// ReadExamples loads labeled examples from a JSON Lines file. Unlabeled records and
+// records without input are skipped.
+func ReadExamples(path string) ([]Example, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var examples []Example
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), 16<<20)
+	for lineNo := 1; scanner.Scan(); lineNo++ {
+		if len(strings.TrimSpace(scanner.Text())) == 0 {
+			continue
+		}
+		var e Example
+		if err := json.Unmarshal(scanner.Bytes(), &e); err != nil {
+			return nil, fmt.Errorf("%s:%d: %w", path, lineNo, err)
+		}
+		switch e.Label {
+		case eval.LabelIssue, eval.LabelNoIssue:
+		case eval.LabelUnlabeled, "":
+			continue
+		default:
+			return nil, fmt.Errorf("%s:%d: unknown label %q (want %q or %q)", path, lineNo, e.Label, eval.LabelIssue, eval.LabelNoIssue)
+		}
+		if strings.TrimSpace(e.Input) == "" {
+			continue
+		}
+		if e.ID == "" {
+			e.ID = fmt.Sprintf("line-%d", lineNo)
+		}
+		examples = append(examples, e)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return examples, nil
+}
+
+// Sweep scores every threshold from Step to 1 against the observations
+func Sweep(observations []Observation) []Point {
+	var points []Point
+	for i := 1; i <= int(math.Round(1/Step)); i++ {
+		threshold := math.Round(float64(i)*Step*100) / 100
+		p := Point{Threshold: threshold}
+		for _, o := range observations {
+			flagged := o.Confidence <= threshold
+			switch {
+			case flagged && o.Example.Issue():
+				p.TP++
+			case flagged:
+				p.FP++
+			case o.Example.Issue():
+				p.FN++
+			default:
+				p.TN++
+			}
+		}
+		if p.TP+p.FP > 0 {
+			p.Precision = float64(p.TP) / float64(p.TP+p.FP)
+		}
+		if p.TP+p.FN > 0 {
+			p.Recall = float64(p.TP) / float64(p.TP+p.FN)
+		}
+		if p.Precision+p.Recall > 0 {
+			p.F1 = 2 * p.Precision * p.Recall / (p.Precision + p.Recall)
+		}
+		points = append(points, p)
+	}
+	return points
+}
+
+// Tune picks thresholds from the observations. similarity_threshold is the threshold
+// with the best F1, preferring higher precision and then the lower threshold on ties.
+// low_similarity_threshold, below which findings are critical, is the highest
+// threshold at or below it whose precision is at least minPrecision, or 0 when none is.
+func Tune(observations []Observation, minPrecision float64) (*Result, error) {
+	issues := 0
+	for _, o := range observations {
+		if o.Example.Issue() {
+			issues++
+		}
+	}
+	if issues == 0 || issues == len(observations) {
+		return nil, errors.New("calibration needs examples labeled both issue and no_issue")
+	}
+
+	points := Sweep(observations)
+	best := points[0]
+	for _, p := range points[1:] {
+		if p.F1 > best.F1 || (p.F1 == best.F1 && p.Precision > best.Precision) {
+			best = p
+		}
+	}
+	result := &Result{SimilarityThreshold: best.Threshold, Best: best, Points: points}
+	for _, p := range points {
+		if p.Threshold > best.Threshold {
+			break
+		}
+		if p.TP > 0 && p.Precision >= minPrecision {
+			result.LowSimilarityThreshold = p.Threshold
+			result.Critical = p
+		}
+	}
+	return result, nil
+}
@@ -0,0 +1,95 @@
+// Package clientlog forwards the log entries of a tool call to the MCP client that
+// made it, as notifications/message, so users can watch validation progress in their
+// MCP host instead of tailing the server's stderr. Clients choose the least severe
+// level they receive with logging/setLevel; until they do, only errors are sent.
+package clientlog
+
+import (
+	"context"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"go.uber.org/zap/zapcore"
+)
+
+// LoggerName names the server as the logger of forwarded entries
+const LoggerName = "mcp-factcheck"
+
+// omittedFields are left out of forwarded entries: the tool call's arguments, which
+// the client sent and which can be whole documents
+var omittedFields = map[string]bool{"request": true}
+
+// core is a zapcore.Core sending entries to the client of one request's session
+type core struct {
+	ctx     context.Context
+	server  *server.MCPServer
+	session server.SessionWithLogging
+	fields  []zapcore.Field
+}
+
+// NewCore returns a core forwarding entries to the client whose session ctx belongs
+// to, or a no-op core when the session can't receive log messages
+func NewCore(ctx context.Context, s *server.MCPServer) zapcore.Core {
+	session, ok := server.ClientSessionFromContext(ctx).(server.SessionWithLogging)
+	if !ok {
+		return zapcore.NewNopCore()
+	}
+	return &core{ctx: ctx, server: s, session: session}
+}
+
+// Enabled reports whether the client receives entries of level, which it can change
+// at any time
+func (c *core) Enabled(level zapcore.Level) bool {
+	return Level(level).ShouldSendTo(c.session.GetLogLevel())
+}
+
+func (c *core) With(fields []zapcore.Field) zapcore.Core {
+	clone := *c
+	clone.fields = append(c.fields[:len(c.fields):len(c.fields)], fields...)
+	return &clone
+}
+
+func (c *core) Check(entry zapcore.Entry, checked *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(entry.Level) {
+		return checked.AddCore(entry, c)
+	}
+	return checked
+}
+
+// Write sends entry as a notifications/message whose data holds the message and the
+// entry's fields. Notifications are best effort: one that can't be sent, because the
+// client went away or isn't reading, is dropped rather than reported as a logging error.
+func (c *core) Write(entry zapcore.Entry, fields []zapcore.Field) error {
+	encoder := zapcore.NewMapObjectEncoder()
+	for _, field := range append(c.fields[:len(c.fields):len(c.fields)], fields...) {
+		if !omittedFields[field.Key] {
+			field.AddTo(encoder)
+		}
+	}
+	data := encoder.Fields
+	data["message"] = entry.Message
+	_ = c.server.SendLogMessageToClient(c.ctx, mcp.NewLoggingMessageNotification(Level(entry.Level), LoggerName, data))
+	return nil
+}
+
+func (c *core) Sync() error {
+	return nil
+}
+
+// Level returns the MCP log level of a zap level
+func Level(level zapcore.Level) mcp.LoggingLevel {
+	switch level {
+	case zapcore.DebugLevel:
+		return mcp.LoggingLevelDebug
+	case zapcore.InfoLevel:
+		return mcp.LoggingLevelInfo
+	case zapcore.WarnLevel:
+		return mcp.LoggingLevelWarning
+	case zapcore.ErrorLevel:
+		return mcp.LoggingLevelError
+	case zapcore.FatalLevel:
+		return mcp.LoggingLevelEmergency
+	default: // DPanic and Panic
+		return mcp.LoggingLevelCritical
+	}
+}
@@ -0,0 +1,144 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/carlisia/mcp-factcheck/embedding"
+	"github.com/carlisia/mcp-factcheck/internal/auth"
+	"github.com/carlisia/mcp-factcheck/internal/cost"
+	"github.com/carlisia/mcp-factcheck/internal/features"
+	"github.com/carlisia/mcp-factcheck/internal/limits"
+	"github.com/carlisia/mcp-factcheck/internal/tenant"
+	"github.com/carlisia/mcp-factcheck/pkg/logger"
+	"github.com/carlisia/mcp-factcheck/pkg/validator"
+	"sigs.k8s.io/yaml"
+)
+
+// Config holds server settings loaded from a config file.
+// Every field except Tenants and Auth can be changed by reloading the file without restarting the server.
+type Config struct {
+	LogLevel  string                `json:"log_level,omitempty"`
+	Validator validator.Settings    `json:"validator"`
+	Features  []string              `json:"features,omitempty"` // Experimental feature flags to enable
+	Limits    limits.Limits         `json:"limits"`
+	Cost      cost.Config           `json:"cost"`
+	OpenAI    embedding.RetryPolicy `json:"openai"`            // Rate limiting and retries of OpenAI requests
+	Tenants   []tenant.Config       `json:"tenants,omitempty"` // Isolated projects; read once at startup
+	Auth      auth.Config           `json:"auth"`              // API keys and OAuth for the HTTP transport; read once at startup
+}
+
+// Default returns the built-in configuration
+func Default() *Config {
+	return &Config{
+		LogLevel:  "info",
+		Validator: validator.DefaultSettings(),
+		Features:  features.FromEnv(),
+		Limits:    limits.Default(),
+		Cost:      cost.Default(),
+		OpenAI:    embedding.DefaultRetryPolicy(),
+	}
+}
+
+// configFileNames are the config files looked for in $XDG_CONFIG_HOME/mcp-factcheck, in order
+var configFileNames = []string{"config.json", "config.yaml", "config.yml"}
+
+// DefaultConfigPath returns the first of $XDG_CONFIG_HOME/mcp-factcheck/config.{json,yaml,yml} that exists
+func DefaultConfigPath() string {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return ""
+	}
+	for _, name := range configFileNames {
+		path := filepath.Join(dir, AppName, name)
+		if _, err := os.Stat(path); err == nil {
+			return path
+		}
+	}
+	return ""
+}
+
+// Load reads a config file, filling unset fields with defaults
+func Load(path string) (*Config, error) {
+	cfg := Default()
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config file %s: %w", path, err)
+	}
+
+	// YAML files use the same field names as JSON
+	if ext := strings.ToLower(filepath.Ext(path)); ext == ".yaml" || ext == ".yml" {
+		if data, err = yaml.YAMLToJSON(data); err != nil {
+			return nil, fmt.Errorf("failed to parse config file %s: %w", path, err)
+		}
+	}
+
+	if err := json.Unmarshal(data, cfg); err != nil {
+		return nil, fmt.Errorf("failed to parse config file %s: %w", path, err)
+	}
+
+	if err := cfg.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid config file %s: %w", path, err)
+	}
+
+	return cfg, nil
+}
+
+// Validate checks the configuration without applying it
+func (c *Config) Validate() error {
+	if err := c.Validator.Validate(); err != nil {
+		return err
+	}
+	if err := features.Validate(c.Features); err != nil {
+		return err
+	}
+	if err := c.Limits.Validate(); err != nil {
+		return err
+	}
+	if err := c.Cost.Validate(); err != nil {
+		return err
+	}
+	if err := c.OpenAI.Validate(); err != nil {
+		return err
+	}
+	names := map[string]bool{}
+	for _, t := range c.Tenants {
+		if t.Name == "" || t.DataDir == "" {
+			return fmt.Errorf("every tenant needs a name and data_dir")
+		}
+		if names[t.Name] {
+			return fmt.Errorf("duplicate tenant name: %s", t.Name)
+		}
+		names[t.Name] = true
+	}
+	if err := c.Auth.Validate(); err != nil {
+		return fmt.Errorf("auth: %w", err)
+	}
+	return nil
+}
+
+// Apply pushes runtime settings to the packages that use them
+func (c *Config) Apply() error {
+	if c.LogLevel != "" {
+		if err := logger.SetLevel(c.LogLevel); err != nil {
+			return err
+		}
+	}
+	if err := validator.SetSettings(c.Validator); err != nil {
+		return err
+	}
+	if err := limits.Set(c.Limits); err != nil {
+		return err
+	}
+	if err := cost.Set(c.Cost); err != nil {
+		return err
+	}
+	if err := embedding.SetRetryPolicy(c.OpenAI); err != nil {
+		return err
+	}
+	return features.Set(c.Features)
+}
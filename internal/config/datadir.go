@@ -0,0 +1,146 @@
+package config
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/carlisia/mcp-factcheck/internal/bootstrap"
+	"github.com/carlisia/mcp-factcheck/internal/bundled"
+	"github.com/carlisia/mcp-factcheck/pkg/logger"
+	"github.com/carlisia/mcp-factcheck/vectorstore"
+	"go.uber.org/zap"
+)
+
+// AppName is the directory name used under the XDG base directories
+const AppName = "mcp-factcheck"
+
+// DataDirEnvVar overrides the resolved data directory when set
+const DataDirEnvVar = "MCP_FACTCHECK_DATA_DIR"
+
+// DefaultDataDir returns the embeddings directory to use when --data-dir is not given.
+//
+// Resolution order:
+//  1. $MCP_FACTCHECK_DATA_DIR
+//  2. the first $XDG_DATA_HOME or $XDG_DATA_DIRS entry that already holds embeddings
+//  3. $XDG_DATA_HOME/mcp-factcheck/embeddings (~/.local/share/mcp-factcheck/embeddings)
+func DefaultDataDir() string {
+	if dir := os.Getenv(DataDirEnvVar); dir != "" {
+		return dir
+	}
+
+	home := dataHome()
+	candidates := []string{filepath.Join(home, AppName, "embeddings")}
+	for _, dir := range dataDirs() {
+		candidates = append(candidates, filepath.Join(dir, AppName, "embeddings"))
+	}
+
+	for _, candidate := range candidates {
+		if hasEmbeddings(candidate) {
+			return candidate
+		}
+	}
+
+	return candidates[0]
+}
+
+// StateDir returns the directory for files the tools write at runtime, such as collected feedback
+func StateDir() string {
+	return filepath.Join(dataHome(), AppName)
+}
+
+// ResolveDataDir returns the absolute data directory to use for dir, which is
+// DefaultDataDir when empty, after making sure it holds embeddings as EnsureDataDir does
+func ResolveDataDir(ctx context.Context, dir string, src bootstrap.Source) (string, error) {
+	if dir == "" {
+		dir = DefaultDataDir()
+	}
+	abs, err := filepath.Abs(dir)
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve data directory path: %w", err)
+	}
+	if err := EnsureDataDir(ctx, abs, src); err != nil {
+		return "", err
+	}
+	return abs, nil
+}
+
+// EnsureDataDir creates the data directory if needed and verifies it contains
+// embeddings. An empty directory is populated from the embeddings bundled into the
+// binary, if there are any, and otherwise downloaded from src, if it is enabled.
+func EnsureDataDir(ctx context.Context, dir string, src bootstrap.Source) error {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("failed to create data directory %s: %w", dir, err)
+	}
+
+	if !hasEmbeddings(dir) {
+		if _, err := bundled.Install(dir); err != nil {
+			return err
+		}
+	}
+
+	if !hasEmbeddings(dir) && src.Enabled() {
+		logger.Get().Info("Downloading embeddings into empty data directory", zap.String("data_dir", dir), zap.String("url", src.URL))
+		installed, err := bootstrap.Install(ctx, dir, src)
+		if err != nil {
+			return fmt.Errorf("failed to bootstrap data directory %s: %w", dir, err)
+		}
+		logger.Get().Info("Installed embeddings", zap.String("data_dir", dir), zap.Strings("files", installed))
+	}
+
+	if !hasEmbeddings(dir) {
+		return fmt.Errorf("no spec embeddings found in %s\n\n%s", dir, DownloadGuidance(dir))
+	}
+
+	return nil
+}
+
+// DownloadGuidance explains how to populate an empty data directory
+func DownloadGuidance(dir string) string {
+	lines := []string{"To populate it, either:"}
+	if versions := bundled.Versions(); len(versions) > 0 {
+		lines = append(lines, fmt.Sprintf("  • start the server, which installs the embeddings bundled into it (%s)", strings.Join(versions, ", ")))
+	}
+	return strings.Join(append(lines,
+		fmt.Sprintf("  • copy the pre-generated files: cp data/embeddings/*.json %s", dir),
+		fmt.Sprintf("  • or generate them: specloader embed --version <version> --data-dir %s", dir),
+		fmt.Sprintf("  • or download a release's embeddings on startup: set $%s to its embeddings.tar.gz URL", bootstrap.URLEnvVar),
+		"Alternatively, point the server at an existing directory with --data-dir or $"+DataDirEnvVar+".",
+	), "\n")
+}
+
+// dataHome returns $XDG_DATA_HOME, defaulting to ~/.local/share
+func dataHome() string {
+	if dir := os.Getenv("XDG_DATA_HOME"); dir != "" && filepath.IsAbs(dir) {
+		return dir
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return os.TempDir()
+	}
+	return filepath.Join(home, ".local", "share")
+}
+
+// dataDirs returns $XDG_DATA_DIRS, defaulting to /usr/local/share:/usr/share
+func dataDirs() []string {
+	value := os.Getenv("XDG_DATA_DIRS")
+	if value == "" {
+		value = "/usr/local/share:/usr/share"
+	}
+
+	var dirs []string
+	for _, dir := range filepath.SplitList(value) {
+		if filepath.IsAbs(dir) {
+			dirs = append(dirs, dir)
+		}
+	}
+	return dirs
+}
+
+// hasEmbeddings reports whether dir contains at least one embedding file
+func hasEmbeddings(dir string) bool {
+	versions, err := vectorstore.NewStore(dir).ListVersions()
+	return err == nil && len(versions) > 0
+}
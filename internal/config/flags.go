@@ -0,0 +1,90 @@
+package config
+
+import (
+	"flag"
+
+	"github.com/carlisia/mcp-factcheck/pkg/validator"
+)
+
+// ValidatorFlags are command-line overrides for validator settings. Flags that
+// are set take precedence over the config file, including after a reload.
+type ValidatorFlags struct {
+	fs                     *flag.FlagSet
+	similarityThreshold    float64
+	lowSimilarityThreshold float64
+	topK                   int
+	chunkTopK              int
+	chunkStrategy          string
+	chunkSize              int
+	chunkOverlap           int
+	chunkWorkers           int
+	batchWorkers           int
+	keywordWeight          float64
+	rerank                 bool
+	coverageThreshold      float64
+}
+
+// RegisterValidatorFlags adds the validator override flags to fs
+func RegisterValidatorFlags(fs *flag.FlagSet) *ValidatorFlags {
+	defaults := Default().Validator
+	f := &ValidatorFlags{fs: fs}
+	fs.Float64Var(&f.similarityThreshold, "similarity-threshold", defaults.SimilarityThreshold, "Average similarity above which content is considered valid")
+	fs.Float64Var(&f.lowSimilarityThreshold, "low-similarity-threshold", defaults.LowSimilarityThreshold, "Average similarity below which content is flagged as critical")
+	fs.IntVar(&f.topK, "top-k", defaults.TopK, "Spec matches retrieved for single validation")
+	fs.IntVar(&f.chunkTopK, "chunk-top-k", defaults.ChunkTopK, "Spec matches retrieved per chunk")
+	fs.StringVar(&f.chunkStrategy, "chunk-strategy", defaults.ChunkStrategy, "How content is split for chunked validation: auto, markdown, recursive, sentence, or token")
+	fs.IntVar(&f.chunkSize, "chunk-size", defaults.ChunkSize, "Maximum tokens per chunk")
+	fs.IntVar(&f.chunkOverlap, "chunk-overlap", defaults.ChunkOverlap, "Tokens shared between adjacent chunks")
+	fs.IntVar(&f.chunkWorkers, "chunk-workers", defaults.ChunkWorkers, "Chunks of one document validated concurrently")
+	fs.IntVar(&f.batchWorkers, "batch-workers", defaults.BatchWorkers, "Documents of one validate_batch call validated concurrently")
+	fs.Float64Var(&f.keywordWeight, "keyword-weight", defaults.KeywordWeight, "Share of BM25 keyword relevance in search ranking; 0 ranks by similarity alone")
+	fs.BoolVar(&f.rerank, "rerank", defaults.Rerank, "Rerank validator search candidates with a chat model")
+	fs.Float64Var(&f.coverageThreshold, "coverage-threshold", defaults.CoverageThreshold, "Similarity to a spec requirement at which content counts as addressing it")
+	return f
+}
+
+// Apply copies the flags that were set on the command line into cfg
+func (f *ValidatorFlags) Apply(cfg *Config) {
+	f.fs.Visit(func(fl *flag.Flag) {
+		switch fl.Name {
+		case "similarity-threshold":
+			cfg.Validator.SimilarityThreshold = f.similarityThreshold
+			f.clearModelThresholds(cfg, func(m *validator.ModelSettings) { m.SimilarityThreshold = 0 })
+		case "low-similarity-threshold":
+			cfg.Validator.LowSimilarityThreshold = f.lowSimilarityThreshold
+			f.clearModelThresholds(cfg, func(m *validator.ModelSettings) { m.LowSimilarityThreshold = 0 })
+		case "top-k":
+			cfg.Validator.TopK = f.topK
+		case "chunk-top-k":
+			cfg.Validator.ChunkTopK = f.chunkTopK
+		case "chunk-strategy":
+			cfg.Validator.ChunkStrategy = f.chunkStrategy
+		case "chunk-size":
+			cfg.Validator.ChunkSize = f.chunkSize
+		case "chunk-overlap":
+			cfg.Validator.ChunkOverlap = f.chunkOverlap
+		case "chunk-workers":
+			cfg.Validator.ChunkWorkers = f.chunkWorkers
+		case "batch-workers":
+			cfg.Validator.BatchWorkers = f.batchWorkers
+		case "keyword-weight":
+			cfg.Validator.KeywordWeight = f.keywordWeight
+		case "rerank":
+			cfg.Validator.Rerank = f.rerank
+		case "coverage-threshold":
+			cfg.Validator.CoverageThreshold = f.coverageThreshold
+			f.clearModelThresholds(cfg, func(m *validator.ModelSettings) { m.CoverageThreshold = 0 })
+		}
+	})
+}
+
+// clearModelThresholds applies clear to the per-model settings of cfg, so that a
+// threshold set by flag is used whatever the embedding model
+func (f *ValidatorFlags) clearModelThresholds(cfg *Config, clear func(*validator.ModelSettings)) {
+	models := make(map[string]validator.ModelSettings, len(cfg.Validator.Models))
+	for name, m := range cfg.Validator.Models {
+		clear(&m)
+		models[name] = m
+	}
+	cfg.Validator.Models = models
+}
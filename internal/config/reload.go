@@ -0,0 +1,118 @@
+package config
+
+import (
+	"context"
+	"os"
+	"os/signal"
+	"sync/atomic"
+	"syscall"
+	"time"
+
+	"github.com/carlisia/mcp-factcheck/pkg/logger"
+	"go.uber.org/zap"
+)
+
+// DefaultWatchInterval is how often the config file is checked for changes
+const DefaultWatchInterval = 2 * time.Second
+
+// Reloader re-applies a config file on SIGHUP or when the file changes on disk
+type Reloader struct {
+	path     string
+	interval time.Duration
+	modTime  time.Time
+	onReload []func(*Config)
+	override func(*Config)
+	current  atomic.Pointer[Config]
+}
+
+// NewReloader creates a reloader for the given config file
+func NewReloader(path string) *Reloader {
+	r := &Reloader{
+		path:     path,
+		interval: DefaultWatchInterval,
+	}
+	if info, err := os.Stat(path); err == nil {
+		r.modTime = info.ModTime()
+	}
+	return r
+}
+
+// OnReload registers a callback invoked after a config is successfully applied
+func (r *Reloader) OnReload(fn func(*Config)) {
+	r.onReload = append(r.onReload, fn)
+}
+
+// Override registers a function that adjusts every loaded config before it is applied,
+// e.g. to give command-line flags precedence over the file
+func (r *Reloader) Override(fn func(*Config)) {
+	r.override = fn
+}
+
+// Reload loads and applies the config file. An invalid file leaves the current settings untouched.
+func (r *Reloader) Reload() error {
+	cfg, err := Load(r.path)
+	if err != nil {
+		return err
+	}
+	if r.override != nil {
+		r.override(cfg)
+		if err := cfg.Validate(); err != nil {
+			return err
+		}
+	}
+	if err := cfg.Apply(); err != nil {
+		return err
+	}
+	r.current.Store(cfg)
+	for _, fn := range r.onReload {
+		fn(cfg)
+	}
+	return nil
+}
+
+// Current returns the most recently applied config, or nil before the first successful Reload
+func (r *Reloader) Current() *Config {
+	return r.current.Load()
+}
+
+// Run watches for SIGHUP and file modifications until ctx is canceled
+func (r *Reloader) Run(ctx context.Context) {
+	log := logger.Get()
+
+	sigChan := make(chan os.Signal, 1)
+	signal.Notify(sigChan, syscall.SIGHUP)
+	defer signal.Stop(sigChan)
+
+	ticker := time.NewTicker(r.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-sigChan:
+			r.reload(log, "sighup")
+		case <-ticker.C:
+			info, err := os.Stat(r.path)
+			if err != nil || !info.ModTime().After(r.modTime) {
+				continue
+			}
+			r.modTime = info.ModTime()
+			r.reload(log, "file_change")
+		}
+	}
+}
+
+func (r *Reloader) reload(log *zap.Logger, trigger string) {
+	if err := r.Reload(); err != nil {
+		log.Error("Failed to reload config, keeping current settings",
+			zap.String("path", r.path),
+			zap.String("trigger", trigger),
+			zap.Error(err))
+		return
+	}
+	log.Info("Reloaded config",
+		zap.String("path", r.path),
+		zap.String("trigger", trigger),
+		zap.String("log_level", logger.Level()))
+}
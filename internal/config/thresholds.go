@@ -0,0 +1,75 @@
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"sigs.k8s.io/yaml"
+)
+
+// WriteThresholds sets the shared validator thresholds in the config file at path,
+// keeping its other settings, and creates the file if it doesn't exist. YAML files are
+// rewritten from their parsed content, so their comments are lost.
+func WriteThresholds(path string, similarity, low float64) error {
+	isYAML := false
+	if ext := strings.ToLower(filepath.Ext(path)); ext == ".yaml" || ext == ".yml" {
+		isYAML = true
+	}
+
+	file := map[string]any{}
+	data, err := os.ReadFile(path)
+	switch {
+	case errors.Is(err, os.ErrNotExist):
+	case err != nil:
+		return fmt.Errorf("failed to read config file %s: %w", path, err)
+	default:
+		if isYAML {
+			if data, err = yaml.YAMLToJSON(data); err != nil {
+				return fmt.Errorf("failed to parse config file %s: %w", path, err)
+			}
+		}
+		if len(strings.TrimSpace(string(data))) > 0 && string(data) != "null" {
+			if err := json.Unmarshal(data, &file); err != nil {
+				return fmt.Errorf("failed to parse config file %s: %w", path, err)
+			}
+		}
+	}
+
+	validatorSettings, _ := file["validator"].(map[string]any)
+	if validatorSettings == nil {
+		validatorSettings = map[string]any{}
+	}
+	validatorSettings["similarity_threshold"] = similarity
+	validatorSettings["low_similarity_threshold"] = low
+	file["validator"] = validatorSettings
+
+	data, err = json.MarshalIndent(file, "", "  ")
+	if err != nil {
+		return err
+	}
+	// Refuse to write a config the server wouldn't load, such as per-tool overrides
+	// below the new low threshold
+	cfg := Default()
+	if err := json.Unmarshal(data, cfg); err != nil {
+		return err
+	}
+	if err := cfg.Validate(); err != nil {
+		return fmt.Errorf("tuned thresholds make %s invalid: %w", path, err)
+	}
+
+	if isYAML {
+		if data, err = yaml.JSONToYAML(data); err != nil {
+			return err
+		}
+	} else {
+		data = append(data, '\n')
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0o644)
+}
@@ -0,0 +1,315 @@
+// Package cost estimates OpenAI spend from the token usage reported with each API
+// response, attributes it to the tool call and session that caused it, and enforces
+// a daily budget.
+package cost
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Price is what a model charges, in US dollars per million tokens
+type Price struct {
+	Input  float64 `json:"input"`
+	Output float64 `json:"output"`
+}
+
+// DefaultPrices are OpenAI's list prices for the models the server uses
+var DefaultPrices = map[string]Price{
+	"text-embedding-ada-002": {Input: 0.10},
+	"text-embedding-3-small": {Input: 0.02},
+	"text-embedding-3-large": {Input: 0.13},
+	"gpt-4o-mini":            {Input: 0.15, Output: 0.60},
+	"gpt-4o":                 {Input: 2.50, Output: 10.00},
+	"gpt-4.1-mini":           {Input: 0.40, Output: 1.60},
+	"gpt-4.1":                {Input: 2.00, Output: 8.00},
+}
+
+// Config sets the daily budget and any price overrides. A zero budget disables enforcement;
+// spend is still tracked.
+type Config struct {
+	DailyBudgetUSD float64          `json:"daily_budget_usd"`
+	Prices         map[string]Price `json:"prices,omitempty"` // Per-model overrides of DefaultPrices
+}
+
+// Default returns the built-in cost settings: no budget, list prices
+func Default() Config {
+	return Config{}
+}
+
+// Validate checks that the budget and prices are not negative
+func (c Config) Validate() error {
+	if c.DailyBudgetUSD < 0 {
+		return fmt.Errorf("daily_budget_usd must not be negative")
+	}
+	for model, p := range c.Prices {
+		if p.Input < 0 || p.Output < 0 {
+			return fmt.Errorf("price for %s must not be negative", model)
+		}
+	}
+	return nil
+}
+
+// price returns the price of model, preferring configured overrides
+func (c Config) price(model string) (Price, bool) {
+	if p, ok := c.Prices[model]; ok {
+		return p, true
+	}
+	p, ok := DefaultPrices[model]
+	return p, ok
+}
+
+var current atomic.Pointer[Config]
+
+func init() {
+	defaults := Default()
+	current.Store(&defaults)
+}
+
+// Current returns the cost settings in effect
+func Current() Config {
+	return *current.Load()
+}
+
+// Set atomically replaces the cost settings used by subsequent calls
+func Set(c Config) error {
+	if err := c.Validate(); err != nil {
+		return err
+	}
+	current.Store(&c)
+	return nil
+}
+
+// Usage is accumulated API usage and its estimated cost
+type Usage struct {
+	Requests         int64   `json:"requests"`
+	PromptTokens     int64   `json:"prompt_tokens"`
+	CompletionTokens int64   `json:"completion_tokens"`
+	USD              float64 `json:"usd"`
+}
+
+func (u *Usage) add(promptTokens, completionTokens int, usd float64) {
+	u.Requests++
+	u.PromptTokens += int64(promptTokens)
+	u.CompletionTokens += int64(completionTokens)
+	u.USD += usd
+}
+
+// Error reports that the daily budget is spent
+type Error struct {
+	Limit     string    `json:"limit"`
+	SpentUSD  float64   `json:"spent_usd"`
+	BudgetUSD float64   `json:"budget_usd"`
+	ResetsAt  time.Time `json:"resets_at"`
+	Message   string    `json:"message"`
+	Retryable bool      `json:"retryable"`
+}
+
+func (e *Error) Error() string {
+	return e.Message
+}
+
+// Tracker accumulates spend for the current UTC day, in total and per tool and session
+type Tracker struct {
+	mu       sync.Mutex
+	day      string
+	today    Usage
+	total    Usage
+	tools    map[string]*Usage
+	sessions map[string]*Usage
+	unpriced map[string]bool
+	now      func() time.Time
+}
+
+// NewTracker creates a tracker with nothing spent
+func NewTracker() *Tracker {
+	return &Tracker{now: time.Now}
+}
+
+// defaultTracker records spend for the whole process
+var defaultTracker = NewTracker()
+
+// rollover starts a new day's accounting if the UTC date changed. Callers hold t.mu.
+func (t *Tracker) rollover() {
+	if day := t.now().UTC().Format(time.DateOnly); day != t.day {
+		t.day = day
+		t.today = Usage{}
+		t.tools = map[string]*Usage{}
+		t.sessions = map[string]*Usage{}
+	}
+}
+
+// Allow returns an *Error once today's spend has reached the daily budget
+func (t *Tracker) Allow() error {
+	budget := Current().DailyBudgetUSD
+	if budget <= 0 {
+		return nil
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.rollover()
+	if t.today.USD < budget {
+		return nil
+	}
+	now := t.now().UTC()
+	resets := time.Date(now.Year(), now.Month(), now.Day()+1, 0, 0, 0, 0, time.UTC)
+	return &Error{
+		Limit:     "daily_budget_usd",
+		SpentUSD:  t.today.USD,
+		BudgetUSD: budget,
+		ResetsAt:  resets,
+		Message:   fmt.Sprintf("daily OpenAI budget of $%.2f is spent ($%.4f used); calls resume at %s", budget, t.today.USD, resets.Format(time.RFC3339)),
+		Retryable: true,
+	}
+}
+
+// Record adds one API request's usage, returning its estimated cost. Models with
+// no known price are counted at zero cost.
+func (t *Tracker) Record(tool, session, model string, promptTokens, completionTokens int) float64 {
+	var usd float64
+	price, priced := Current().price(model)
+	if priced {
+		usd = (float64(promptTokens)*price.Input + float64(completionTokens)*price.Output) / 1e6
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.rollover()
+	t.today.add(promptTokens, completionTokens, usd)
+	t.total.add(promptTokens, completionTokens, usd)
+	if tool != "" {
+		if t.tools[tool] == nil {
+			t.tools[tool] = &Usage{}
+		}
+		t.tools[tool].add(promptTokens, completionTokens, usd)
+	}
+	if session != "" {
+		if t.sessions[session] == nil {
+			t.sessions[session] = &Usage{}
+		}
+		t.sessions[session].add(promptTokens, completionTokens, usd)
+	}
+	if !priced {
+		if t.unpriced == nil {
+			t.unpriced = map[string]bool{}
+		}
+		t.unpriced[model] = true
+	}
+	return usd
+}
+
+// Snapshot is a point-in-time copy of a tracker's accounting
+type Snapshot struct {
+	Day            string           `json:"day"` // UTC date the daily figures cover
+	Today          Usage            `json:"today"`
+	Total          Usage            `json:"total"` // Since the process started
+	DailyBudgetUSD float64          `json:"daily_budget_usd,omitempty"`
+	RemainingUSD   *float64         `json:"remaining_usd,omitempty"`
+	Tools          map[string]Usage `json:"tools"`    // Today, by tool
+	Sessions       map[string]Usage `json:"sessions"` // Today, by session
+	Unpriced       []string         `json:"unpriced_models,omitempty"`
+}
+
+// Snapshot returns a copy of the current accounting
+func (t *Tracker) Snapshot() Snapshot {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.rollover()
+
+	s := Snapshot{
+		Day:      t.day,
+		Today:    t.today,
+		Total:    t.total,
+		Tools:    make(map[string]Usage, len(t.tools)),
+		Sessions: make(map[string]Usage, len(t.sessions)),
+	}
+	for name, u := range t.tools {
+		s.Tools[name] = *u
+	}
+	for id, u := range t.sessions {
+		s.Sessions[id] = *u
+	}
+	for model := range t.unpriced {
+		s.Unpriced = append(s.Unpriced, model)
+	}
+	sort.Strings(s.Unpriced)
+	if budget := Current().DailyBudgetUSD; budget > 0 {
+		remaining := max(budget-t.today.USD, 0)
+		s.DailyBudgetUSD = budget
+		s.RemainingUSD = &remaining
+	}
+	return s
+}
+
+// Stats returns the process-wide accounting
+func Stats() Snapshot {
+	return defaultTracker.Snapshot()
+}
+
+// Call accumulates the spend of one tool call
+type Call struct {
+	Tool    string
+	Session string
+
+	mu    sync.Mutex
+	usage Usage
+}
+
+// Usage returns what the call has spent so far
+func (c *Call) Usage() Usage {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.usage
+}
+
+type contextKey struct{}
+
+// WithCall attributes spend made with the returned context to tool and session
+func WithCall(ctx context.Context, tool, session string) (context.Context, *Call) {
+	call := &Call{Tool: tool, Session: session}
+	return context.WithValue(ctx, contextKey{}, call), call
+}
+
+// FromContext returns the tool call spend is attributed to, or nil
+func FromContext(ctx context.Context) *Call {
+	call, _ := ctx.Value(contextKey{}).(*Call)
+	return call
+}
+
+// Allow returns an *Error if the daily budget is spent, so callers can refuse
+// to make an API request
+func Allow() error {
+	return defaultTracker.Allow()
+}
+
+// Record adds the usage of one API request to the process-wide accounting and to
+// the tool call in ctx, and annotates the current span with its cost
+func Record(ctx context.Context, model string, promptTokens, completionTokens int) float64 {
+	call := FromContext(ctx)
+	var tool, session string
+	if call != nil {
+		tool, session = call.Tool, call.Session
+	}
+	usd := defaultTracker.Record(tool, session, model, promptTokens, completionTokens)
+	if call != nil {
+		call.mu.Lock()
+		call.usage.add(promptTokens, completionTokens, usd)
+		call.mu.Unlock()
+	}
+
+	trace.SpanFromContext(ctx).SetAttributes(
+		attribute.Float64("llm.cost.total", usd),
+		attribute.Int("llm.token_count.prompt", promptTokens),
+		attribute.Int("llm.token_count.completion", completionTokens),
+		attribute.Int("llm.token_count.total", promptTokens+completionTokens),
+	)
+	return usd
+}
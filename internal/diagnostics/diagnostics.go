@@ -0,0 +1,84 @@
+package diagnostics
+
+import (
+	"strings"
+	"unicode/utf16"
+
+	"github.com/carlisia/mcp-factcheck/pkg/validator"
+)
+
+// Position is a zero-based line and UTF-16 character offset, matching editor APIs
+type Position struct {
+	Line      int `json:"line"`
+	Character int `json:"character"`
+}
+
+// Range spans two positions; End is exclusive
+type Range struct {
+	Start Position `json:"start"`
+	End   Position `json:"end"`
+}
+
+// Diagnostic is a finding anchored to a range in a file
+type Diagnostic struct {
+	File        string   `json:"file"`
+	Range       Range    `json:"range"`
+	Severity    string   `json:"severity"` // "error", "warning", or "info"
+	Message     string   `json:"message"`
+	Fix         string   `json:"fix,omitempty"`  // Replacement text or the first actionable suggestion
+	Code        string   `json:"code,omitempty"` // Finding type, e.g. "inaccuracy"
+	SpecSection string   `json:"spec_section,omitempty"`
+	SpecURL     string   `json:"spec_url,omitempty"`
+	Suggestions []string `json:"suggestions,omitempty"`
+}
+
+// FromFindings anchors findings to ranges in text. Each range covers the first line of the flagged section;
+// findings without a line number are placed at the top of the file.
+func FromFindings(file, text string, findings []validator.ValidationError) []Diagnostic {
+	lines := strings.Split(text, "\n")
+	diagnostics := make([]Diagnostic, 0, len(findings))
+	for _, f := range findings {
+		line := 0
+		if f.LineNumber > 0 && f.LineNumber <= len(lines) {
+			line = f.LineNumber - 1
+		}
+		lineText := strings.TrimRight(lines[line], "\r")
+
+		d := Diagnostic{
+			File: file,
+			Range: Range{
+				Start: Position{Line: line, Character: utf16Len(lineText) - utf16Len(strings.TrimLeft(lineText, " \t"))},
+				End:   Position{Line: line, Character: utf16Len(lineText)},
+			},
+			Severity:    Severity(f.Severity),
+			Message:     f.Message,
+			Code:        f.Type,
+			SpecSection: f.SpecSection,
+			SpecURL:     f.SpecURL,
+			Suggestions: f.Suggestions,
+		}
+		if f.Expected != "" {
+			d.Fix = f.Expected
+		} else if len(f.Suggestions) > 0 {
+			d.Fix = f.Suggestions[0]
+		}
+		diagnostics = append(diagnostics, d)
+	}
+	return diagnostics
+}
+
+// Severity maps validator severities onto the error/warning/info levels editors understand
+func Severity(severity string) string {
+	switch severity {
+	case validator.SeverityCritical:
+		return "error"
+	case validator.SeverityWarning:
+		return "warning"
+	default:
+		return "info"
+	}
+}
+
+func utf16Len(s string) int {
+	return len(utf16.Encode([]rune(s)))
+}
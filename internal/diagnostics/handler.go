@@ -0,0 +1,110 @@
+package diagnostics
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/carlisia/mcp-factcheck/embedding"
+	"github.com/carlisia/mcp-factcheck/internal/cost"
+	mcpembedding "github.com/carlisia/mcp-factcheck/internal/embedding"
+	"github.com/carlisia/mcp-factcheck/internal/limits"
+	"github.com/carlisia/mcp-factcheck/internal/specs"
+	"github.com/carlisia/mcp-factcheck/pkg/logger"
+	"github.com/carlisia/mcp-factcheck/pkg/validator"
+	"go.uber.org/zap"
+)
+
+// Request is the body of POST /diagnostics
+type Request struct {
+	File        string `json:"file"`                   // Path echoed back on each diagnostic
+	Content     string `json:"content"`                // Full document text
+	SpecVersion string `json:"spec_version,omitempty"` // Defaults to the handler's spec version
+}
+
+// Response is returned by POST /diagnostics
+type Response struct {
+	File        string       `json:"file"`
+	SpecVersion string       `json:"spec_version"`
+	Diagnostics []Diagnostic `json:"diagnostics"`
+}
+
+type errorResponse struct {
+	Error  string        `json:"error"`
+	Limit  *limits.Error `json:"limit,omitempty"`
+	Budget *cost.Error   `json:"budget,omitempty"`
+}
+
+// Handler serves POST /diagnostics
+type Handler struct {
+	vectorDB    *mcpembedding.VectorDB
+	generator   *embedding.Generator
+	specVersion string
+}
+
+// NewHandler creates a diagnostics handler
+func NewHandler(vectorDB *mcpembedding.VectorDB, generator *embedding.Generator, specVersion string) *Handler {
+	return &Handler{
+		vectorDB:    vectorDB,
+		generator:   generator,
+		specVersion: specVersion,
+	}
+}
+
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	var req Request
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, 4<<20)).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, errorResponse{Error: "invalid JSON body: " + err.Error()})
+		return
+	}
+	if strings.TrimSpace(req.Content) == "" {
+		writeError(w, http.StatusBadRequest, errorResponse{Error: "content is required"})
+		return
+	}
+	if req.SpecVersion == "" {
+		req.SpecVersion = h.specVersion
+	}
+	if !specs.IsValidSpecVersion(req.SpecVersion) {
+		writeError(w, http.StatusBadRequest, errorResponse{Error: "unsupported spec_version: " + req.SpecVersion})
+		return
+	}
+
+	var limitErr *limits.Error
+	if err := limits.CheckContentLength("content", len(req.Content)); errors.As(err, &limitErr) {
+		writeError(w, http.StatusRequestEntityTooLarge, errorResponse{Error: limitErr.Message, Limit: limitErr})
+		return
+	}
+
+	result, err := validator.Validate(r.Context(), h.vectorDB, h.generator, validator.Request{Content: req.Content, SpecVersion: req.SpecVersion, Chunked: true})
+	if err != nil {
+		if errors.As(err, &limitErr) {
+			writeError(w, http.StatusRequestEntityTooLarge, errorResponse{Error: limitErr.Message, Limit: limitErr})
+			return
+		}
+		var budgetErr *cost.Error
+		if errors.As(err, &budgetErr) {
+			writeError(w, http.StatusTooManyRequests, errorResponse{Error: budgetErr.Message, Budget: budgetErr})
+			return
+		}
+		logger.Get().Error("Diagnostics request failed", zap.String("file", req.File), zap.Error(err))
+		writeError(w, http.StatusBadGateway, errorResponse{Error: err.Error()})
+		return
+	}
+
+	findings := result.Findings()
+	validator.SortBySeverity(findings)
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(Response{
+		File:        req.File,
+		SpecVersion: req.SpecVersion,
+		Diagnostics: FromFindings(req.File, req.Content, findings),
+	})
+}
+
+func writeError(w http.ResponseWriter, status int, body errorResponse) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(body)
+}
@@ -0,0 +1,209 @@
+package doctor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/carlisia/mcp-factcheck/embedding"
+	"github.com/carlisia/mcp-factcheck/internal/config"
+	"github.com/carlisia/mcp-factcheck/internal/specs"
+	"github.com/carlisia/mcp-factcheck/vectorstore"
+)
+
+// Status is the outcome of a single diagnostic check
+type Status string
+
+const (
+	StatusOK   Status = "ok"
+	StatusWarn Status = "warn"
+	StatusFail Status = "fail"
+)
+
+// Result describes one check and, when it did not pass, how to fix it
+type Result struct {
+	Name    string `json:"name"`
+	Status  Status `json:"status"`
+	Message string `json:"message"`
+	Fix     string `json:"fix,omitempty"`
+}
+
+// Options controls what the doctor inspects
+type Options struct {
+	ConfigPath   string
+	DataDir      string
+	OTLPEndpoint string
+	SkipNetwork  bool // Skip checks that call external services
+}
+
+// Run executes all diagnostic checks in order
+func Run(ctx context.Context, opts Options) []Result {
+	var results []Result
+	results = append(results, checkConfig(opts.ConfigPath))
+	results = append(results, checkDataDir(opts.DataDir)...)
+	results = append(results, checkAPIKey(ctx, opts.SkipNetwork))
+	results = append(results, checkTelemetry(ctx, opts.OTLPEndpoint, opts.SkipNetwork))
+	return results
+}
+
+// Failed reports whether any check failed
+func Failed(results []Result) bool {
+	for _, r := range results {
+		if r.Status == StatusFail {
+			return true
+		}
+	}
+	return false
+}
+
+func checkConfig(path string) Result {
+	if path == "" {
+		return Result{Name: "config", Status: StatusOK, Message: "no config file, using built-in defaults"}
+	}
+	if _, err := config.Load(path); err != nil {
+		return Result{
+			Name:    "config",
+			Status:  StatusFail,
+			Message: err.Error(),
+			Fix:     "fix the JSON syntax or values in the config file, or remove --config to use defaults",
+		}
+	}
+	return Result{Name: "config", Status: StatusOK, Message: fmt.Sprintf("loaded %s", path)}
+}
+
+func checkDataDir(dir string) []Result {
+	store := vectorstore.NewStore(dir)
+	versions, _ := store.ListVersions()
+	if len(versions) == 0 {
+		return []Result{{
+			Name:    "data_dir",
+			Status:  StatusFail,
+			Message: fmt.Sprintf("no spec embeddings found in %s", dir),
+			Fix:     config.DownloadGuidance(dir),
+		}}
+	}
+
+	results := []Result{{Name: "data_dir", Status: StatusOK, Message: fmt.Sprintf("%s contains %d embedding files", dir, len(versions))}}
+
+	available := map[string]bool{}
+	for _, version := range versions {
+		available[version] = true
+		results = append(results, checkSpecEmbedding(store, version))
+	}
+
+	var missing []string
+	for _, version := range specs.ValidSpecVersions {
+		if !available[version] {
+			missing = append(missing, version)
+		}
+	}
+	if len(missing) > 0 {
+		results = append(results, Result{
+			Name:    "spec_versions",
+			Status:  StatusWarn,
+			Message: fmt.Sprintf("missing embeddings for: %s", strings.Join(missing, ", ")),
+			Fix:     "run `specloader embed --version <version>` for each missing version, or ignore if you don't validate against them",
+		})
+	} else {
+		results = append(results, Result{Name: "spec_versions", Status: StatusOK, Message: "all supported spec versions are present"})
+	}
+
+	return results
+}
+
+func checkSpecEmbedding(store *vectorstore.Store, version string) Result {
+	name := "embeddings/" + version
+	spec, err := store.Load(version)
+	if err != nil {
+		return Result{
+			Name:    name,
+			Status:  StatusFail,
+			Message: err.Error(),
+			Fix:     fmt.Sprintf("the file is corrupt or truncated; regenerate it with `specloader embed --version %s`", version),
+		}
+	}
+	if problems := embedding.Verify(spec, version); len(problems) > 0 {
+		return Result{
+			Name:    name,
+			Status:  StatusFail,
+			Message: strings.Join(problems, "; "),
+			Fix:     fmt.Sprintf("regenerate it with `specloader spec --version %[1]s && specloader embed --version %[1]s`", version),
+		}
+	}
+
+	if err := spec.CheckModel(embedding.CurrentModel()); err != nil {
+		return Result{
+			Name:    name,
+			Status:  StatusFail,
+			Message: err.Error(),
+			Fix:     fmt.Sprintf("re-embed with the server's model: `specloader reembed --version %s`", version),
+		}
+	}
+
+	return Result{Name: name, Status: StatusOK, Message: fmt.Sprintf("%d chunks, %s, %d dimensions", len(spec.Chunks), spec.EmbeddingModel(), spec.EmbeddingDimensions())}
+}
+
+func checkAPIKey(ctx context.Context, skipNetwork bool) Result {
+	generator, err := embedding.NewGenerator()
+	if errors.Is(err, embedding.ErrNoAPIKey) {
+		return Result{
+			Name:    "openai_api_key",
+			Status:  StatusWarn,
+			Message: "not set; the server runs in degraded mode, embedding with " + embedding.LocalModel + " and asking the client's model through MCP sampling",
+			Fix:     "set OPENAI_API_KEY in the MCP host config's env block, or use OPENAI_API_KEY_FILE, for OpenAI embeddings and chat",
+		}
+	}
+	if err != nil {
+		return Result{
+			Name:    "openai_api_key",
+			Status:  StatusFail,
+			Message: err.Error(),
+			Fix:     "set OPENAI_API_KEY in the MCP host config's env block, or use OPENAI_API_KEY_FILE",
+		}
+	}
+	if skipNetwork {
+		return Result{Name: "openai_api_key", Status: StatusOK, Message: "API key found (not verified)"}
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+	if err := generator.CheckAPIKey(ctx); err != nil {
+		return Result{
+			Name:    "openai_api_key",
+			Status:  StatusFail,
+			Message: err.Error(),
+			Fix:     "check the key at https://platform.openai.com/api-keys and that the machine can reach api.openai.com",
+		}
+	}
+	return Result{Name: "openai_api_key", Status: StatusOK, Message: "API key accepted by OpenAI"}
+}
+
+func checkTelemetry(ctx context.Context, endpoint string, skipNetwork bool) Result {
+	if endpoint == "" || skipNetwork {
+		return Result{Name: "telemetry", Status: StatusOK, Message: "not checked"}
+	}
+	if !strings.Contains(endpoint, "://") {
+		endpoint = "http://" + endpoint
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, 3*time.Second)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return Result{Name: "telemetry", Status: StatusWarn, Message: err.Error(), Fix: "pass a valid URL to --otlp-endpoint"}
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return Result{
+			Name:    "telemetry",
+			Status:  StatusWarn,
+			Message: fmt.Sprintf("%s is unreachable: %v", endpoint, err),
+			Fix:     "start Phoenix with `phoenix serve`, or drop --telemetry if tracing isn't needed",
+		}
+	}
+	resp.Body.Close()
+	return Result{Name: "telemetry", Status: StatusOK, Message: fmt.Sprintf("%s is reachable", endpoint)}
+}
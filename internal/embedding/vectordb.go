@@ -1,28 +1,141 @@
 package embedding
 
 import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"time"
+
 	"github.com/carlisia/mcp-factcheck/embedding"
+	"github.com/carlisia/mcp-factcheck/internal/requirements"
 	"github.com/carlisia/mcp-factcheck/vectorstore"
 )
 
+// CorporaDir is the subdirectory of a data directory holding documentation corpora,
+// collections of docs other than the MCP spec that content can be checked against
+const CorporaDir = "corpora"
+
+// CorpusMetadataKey is the chunk metadata key naming the corpus a chunk belongs to
+const CorpusMetadataKey = "corpus"
+
+// corpusName matches valid corpus names, which double as file names
+var corpusName = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._-]*$`)
+
+// ValidateCorpusName checks that name can be used as a corpus name
+func ValidateCorpusName(name string) error {
+	if !corpusName.MatchString(name) {
+		return fmt.Errorf("invalid corpus name %q: use letters, digits, '.', '_', and '-'", name)
+	}
+	return nil
+}
+
 // VectorDB handles MCP-specific vector database operations for the runtime server
 type VectorDB struct {
-	store *vectorstore.Store
+	store   *vectorstore.Store
+	corpora *vectorstore.Store
+
+	requirementsDir string
 }
 
 // NewVectorDB creates a new MCP vector database
 func NewVectorDB(dataDir string) *VectorDB {
 	return &VectorDB{
-		store: vectorstore.NewStore(dataDir),
+		store:   vectorstore.NewStore(dataDir),
+		corpora: vectorstore.NewStore(filepath.Join(dataDir, CorporaDir)),
+
+		requirementsDir: filepath.Join(dataDir, requirements.Dir),
 	}
 }
 
 // Search performs similarity search against a spec version (MCP tool functionality)
-func (db *VectorDB) Search(version string, queryEmbedding []float64, topK int) ([]embedding.SearchResult, error) {
-	return db.store.Search(version, queryEmbedding, topK)
+func (db *VectorDB) Search(ctx context.Context, version string, queryEmbedding []float64, topK int) ([]embedding.SearchResult, error) {
+	return db.store.Search(ctx, version, queryEmbedding, topK)
+}
+
+// HybridSearch ranks chunks by vector similarity blended with keyword relevance to query
+func (db *VectorDB) HybridSearch(ctx context.Context, version, query string, queryEmbedding []float64, topK int, keywordWeight float64) ([]embedding.SearchResult, error) {
+	return db.store.HybridSearch(ctx, version, query, queryEmbedding, topK, keywordWeight)
+}
+
+// Load returns every chunk stored for a spec version
+func (db *VectorDB) Load(version string) (*embedding.SpecEmbedding, error) {
+	return db.store.Load(version)
+}
+
+// Spec returns a spec version's chunks as loaded for searching, without reading the
+// file again. Callers must not modify the result.
+func (db *VectorDB) Spec(version string) (*embedding.SpecEmbedding, error) {
+	return db.store.Cached(version)
+}
+
+// LoadHeader returns a spec version's metadata, such as the commit it was extracted
+// at, without loading its chunks
+func (db *VectorDB) LoadHeader(version string) (*embedding.SpecEmbedding, error) {
+	return db.store.LoadHeader(version)
+}
+
+// ModTime returns when a spec version's embeddings were last written
+func (db *VectorDB) ModTime(version string) (time.Time, error) {
+	return db.store.ModTime(version)
 }
 
 // ListVersions returns all available spec versions (MCP tool functionality)
 func (db *VectorDB) ListVersions() ([]string, error) {
 	return db.store.ListVersions()
-}
\ No newline at end of file
+}
+
+// ListCorpora returns the names of the documentation corpora ingested into the database
+func (db *VectorDB) ListCorpora() ([]string, error) {
+	return db.corpora.ListVersions()
+}
+
+// CheckCorpus returns an error naming the available corpora if corpus hasn't been ingested
+func (db *VectorDB) CheckCorpus(corpus string) error {
+	if err := ValidateCorpusName(corpus); err != nil {
+		return err
+	}
+	available, err := db.ListCorpora()
+	if err != nil {
+		return err
+	}
+	if !slices.Contains(available, corpus) {
+		return fmt.Errorf("unknown corpus %q (available: %v)", corpus, available)
+	}
+	return nil
+}
+
+// SearchCorpus is HybridSearch over a documentation corpus instead of a spec version
+func (db *VectorDB) SearchCorpus(ctx context.Context, corpus, query string, queryEmbedding []float64, topK int, keywordWeight float64) ([]embedding.SearchResult, error) {
+	if err := ValidateCorpusName(corpus); err != nil {
+		return nil, err
+	}
+	return db.corpora.HybridSearch(ctx, corpus, query, queryEmbedding, topK, keywordWeight)
+}
+
+// Corpus returns a documentation corpus's chunks as loaded for searching. Callers must
+// not modify the result.
+func (db *VectorDB) Corpus(corpus string) (*embedding.SpecEmbedding, error) {
+	if err := db.CheckCorpus(corpus); err != nil {
+		return nil, err
+	}
+	return db.corpora.Cached(corpus)
+}
+
+// Requirements returns a spec version's requirement catalog: the one stored by
+// `specloader requirements` if there is one, else one extracted from the version's
+// embedded chunks
+func (db *VectorDB) Requirements(version string) (*requirements.Catalog, error) {
+	catalog, err := requirements.Load(db.requirementsDir, version)
+	if err == nil || !errors.Is(err, fs.ErrNotExist) {
+		return catalog, err
+	}
+	spec, err := db.Spec(version)
+	if err != nil {
+		return nil, err
+	}
+	return requirements.FromEmbeddings(spec), nil
+}
@@ -0,0 +1,162 @@
+// Package eval builds evaluation datasets from recorded tool interactions and user feedback,
+// so retrieval and verdict quality can be compared across releases.
+package eval
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"sort"
+	"time"
+
+	"github.com/carlisia/mcp-factcheck/internal/feedback"
+	"github.com/carlisia/mcp-factcheck/internal/interactions"
+)
+
+// Labels for the expected outcome of a record
+const (
+	LabelIssue     = "issue"     // Content has a real spec problem; a finding is expected
+	LabelNoIssue   = "no_issue"  // Content matches the spec; no finding is expected
+	LabelUnlabeled = "unlabeled" // Outcome recorded but not reviewed by a user
+)
+
+// Interaction is a recorded tool call, as captured by the server's interaction log
+type Interaction = interactions.Interaction
+
+// DefaultInteractionsPath returns where the server persists interactions
+func DefaultInteractionsPath() string {
+	return interactions.DefaultPath()
+}
+
+// Record is one line of an eval dataset
+type Record struct {
+	ID          string          `json:"id"`
+	Source      string          `json:"source"` // "feedback" or "interaction"
+	Time        time.Time       `json:"time"`
+	Tool        string          `json:"tool,omitempty"`
+	SpecVersion string          `json:"spec_version,omitempty"`
+	Input       string          `json:"input,omitempty"`     // Text that was validated
+	Arguments   json.RawMessage `json:"arguments,omitempty"` // Full tool arguments, for interactions
+	Label       string          `json:"label"`
+	Observed    Observed        `json:"observed"`
+	Comment     string          `json:"comment,omitempty"`
+}
+
+// Observed is what the server produced for the input
+type Observed struct {
+	IssueType   string          `json:"issue_type,omitempty"`
+	Severity    string          `json:"severity,omitempty"`
+	SpecSection string          `json:"spec_section,omitempty"`
+	Confidence  float64         `json:"confidence,omitempty"`
+	Response    json.RawMessage `json:"response,omitempty"`
+	Error       string          `json:"error,omitempty"`
+}
+
+// Options filters what goes into a dataset
+type Options struct {
+	Since       time.Time // Skip records older than this; zero keeps everything
+	LabeledOnly bool      // Drop interactions that carry no user feedback
+}
+
+// Build converts feedback entries and interactions into dataset records, oldest first.
+// Identical inputs are exported once, keeping the newest record.
+func Build(entries []feedback.Entry, interactions []Interaction, opts Options) []Record {
+	var records []Record
+	for _, e := range entries {
+		label := LabelIssue
+		if e.Verdict == feedback.VerdictFalsePositive {
+			label = LabelNoIssue
+		}
+		records = append(records, Record{
+			ID:          "feedback-" + e.ID,
+			Source:      "feedback",
+			Time:        e.Time,
+			Tool:        e.Tool,
+			SpecVersion: e.SpecVersion,
+			Input:       e.Finding.Found,
+			Label:       label,
+			Observed: Observed{
+				IssueType:   e.Finding.Type,
+				Severity:    e.Finding.Severity,
+				SpecSection: e.Finding.SpecSection,
+				Confidence:  e.Finding.Confidence,
+			},
+			Comment: e.Comment,
+		})
+	}
+
+	if !opts.LabeledOnly {
+		for _, in := range interactions {
+			records = append(records, Record{
+				ID:          "interaction-" + in.ID,
+				Source:      "interaction",
+				Time:        in.Time,
+				Tool:        in.Tool,
+				SpecVersion: in.SpecVersion,
+				Input:       contentArgument(in.Arguments),
+				Arguments:   in.Arguments,
+				Label:       LabelUnlabeled,
+				Observed:    Observed{Response: in.Response, Error: in.Error},
+			})
+		}
+	}
+
+	sort.SliceStable(records, func(i, j int) bool { return records[i].Time.Before(records[j].Time) })
+
+	// Keep the newest record per (source, tool, input) so repeated runs don't skew the dataset
+	seen := map[string]int{}
+	var result []Record
+	for _, r := range records {
+		if !opts.Since.IsZero() && r.Time.Before(opts.Since) {
+			continue
+		}
+		key := dedupeKey(r)
+		if i, ok := seen[key]; ok {
+			result[i] = r
+			continue
+		}
+		seen[key] = len(result)
+		result = append(result, r)
+	}
+	return result
+}
+
+// Write encodes records as JSON Lines
+func Write(w io.Writer, records []Record) error {
+	encoder := json.NewEncoder(w)
+	for _, r := range records {
+		if err := encoder.Encode(r); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ReadInteractions loads interactions from a JSON Lines file. A missing file yields none.
+func ReadInteractions(path string) ([]Interaction, error) {
+	return interactions.NewStore(path, interactions.Retention{}).All()
+}
+
+// contentArgument extracts the validated text from tool arguments
+func contentArgument(args json.RawMessage) string {
+	var params map[string]any
+	if json.Unmarshal(args, &params) != nil {
+		return ""
+	}
+	for _, key := range []string{"content", "code", "query"} {
+		if v, ok := params[key].(string); ok {
+			return v
+		}
+	}
+	return ""
+}
+
+func dedupeKey(r Record) string {
+	input := r.Input
+	if input == "" {
+		input = string(r.Arguments)
+	}
+	sum := sha256.Sum256([]byte(r.Source + "\x00" + r.Tool + "\x00" + r.SpecVersion + "\x00" + input))
+	return hex.EncodeToString(sum[:])
+}
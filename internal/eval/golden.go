@@ -0,0 +1,126 @@
+package eval
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"strings"
+
+	"github.com/carlisia/mcp-factcheck/pkg/validator"
+)
+
+// GoldenSuffix names the expected result of a fixture: docs/intro.md is checked
+// against docs/intro.md.golden.json
+const GoldenSuffix = ".golden.json"
+
+// Golden is the expected validation of a fixture document. Confidence and Findings
+// are optional, so a hand-written golden file can pin only the verdict.
+type Golden struct {
+	SpecVersion string         `json:"spec_version,omitempty"` // The run's spec version when empty
+	Valid       bool           `json:"valid"`
+	Confidence  *float64       `json:"confidence,omitempty"`
+	Findings    map[string]int `json:"findings"` // Number of findings by severity; not compared when null
+}
+
+// Observe records a validation result as a golden
+func Observe(result *validator.AggregatedValidationResult) Golden {
+	confidence := math.Round(result.Overall.Confidence*1000) / 1000
+	findings := map[string]int{}
+	for _, f := range result.Findings() {
+		findings[f.Severity]++
+	}
+	return Golden{
+		SpecVersion: result.SpecVersion,
+		Valid:       result.Overall.IsValid,
+		Confidence:  &confidence,
+		Findings:    findings,
+	}
+}
+
+// Kinds of regression
+const (
+	RegressionVerdict    = "verdict"
+	RegressionConfidence = "confidence"
+	RegressionFindings   = "findings"
+)
+
+// Regression is a difference between a fixture's golden and its current result
+type Regression struct {
+	Kind    string `json:"kind"`
+	Message string `json:"message"`
+}
+
+// Compare reports how got differs from want. Confidence drifts of more than
+// tolerance in either direction are reported, since a rise can hide a retrieval
+// change as much as a fall.
+func Compare(want, got Golden, tolerance float64) []Regression {
+	var regressions []Regression
+	if want.Valid != got.Valid {
+		regressions = append(regressions, Regression{
+			Kind:    RegressionVerdict,
+			Message: fmt.Sprintf("verdict changed from %s to %s", verdict(want.Valid), verdict(got.Valid)),
+		})
+	}
+	if want.Confidence != nil && got.Confidence != nil {
+		if drift := *got.Confidence - *want.Confidence; math.Abs(drift) > tolerance {
+			regressions = append(regressions, Regression{
+				Kind:    RegressionConfidence,
+				Message: fmt.Sprintf("confidence drifted %+.3f, from %.3f to %.3f", drift, *want.Confidence, *got.Confidence),
+			})
+		}
+	}
+	if want.Findings != nil {
+		var changes []string
+		for _, severity := range []string{validator.SeverityCritical, validator.SeverityWarning, validator.SeveritySuggestion} {
+			if want.Findings[severity] != got.Findings[severity] {
+				changes = append(changes, fmt.Sprintf("%s %d -> %d", severity, want.Findings[severity], got.Findings[severity]))
+			}
+		}
+		if len(changes) > 0 {
+			regressions = append(regressions, Regression{
+				Kind:    RegressionFindings,
+				Message: "findings changed: " + strings.Join(changes, ", "),
+			})
+		}
+	}
+	return regressions
+}
+
+// ReadGolden loads a fixture's golden. A missing file yields ok false.
+func ReadGolden(path string) (golden Golden, ok bool, err error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return Golden{}, false, nil
+	}
+	if err != nil {
+		return Golden{}, false, err
+	}
+	if err := json.Unmarshal(data, &golden); err != nil {
+		return Golden{}, false, fmt.Errorf("%s: %w", path, err)
+	}
+	return golden, true, nil
+}
+
+// WriteGolden saves a fixture's golden
+func WriteGolden(path string, golden Golden) error {
+	// Severities without findings are left out, keeping goldens short
+	for severity, n := range golden.Findings {
+		if n == 0 {
+			delete(golden.Findings, severity)
+		}
+	}
+	data, err := json.MarshalIndent(golden, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(data, '\n'), 0o644)
+}
+
+func verdict(valid bool) string {
+	if valid {
+		return "valid"
+	}
+	return "invalid"
+}
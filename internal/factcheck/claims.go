@@ -0,0 +1,88 @@
+package factcheck
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// Claim verdicts
+const (
+	VerdictSupported    = "supported"
+	VerdictContradicted = "contradicted"
+	VerdictNotAddressed = "not_addressed"
+)
+
+// SpecSection is a retrieved spec passage offered to the model as evidence
+type SpecSection struct {
+	ID      string
+	Content string
+}
+
+// ClaimVerdict is the model's judgement of one claim extracted from the content
+type ClaimVerdict struct {
+	Claim       string `json:"claim"`
+	Verdict     string `json:"verdict"`
+	Explanation string `json:"explanation,omitempty"`
+	Citation    string `json:"citation,omitempty"`      // Verbatim spec text supporting the verdict
+	SpecChunkID string `json:"spec_chunk_id,omitempty"` // Spec passage the citation was taken from
+}
+
+const claimSystemPrompt = `You verify statements about the Model Context Protocol (MCP) against excerpts from its specification.
+
+Extract each factual claim about MCP from the CONTENT. For every claim decide:
+- "supported": the EXCERPTS state or directly imply it
+- "contradicted": the EXCERPTS state something incompatible with it
+- "not_addressed": the EXCERPTS say nothing either way
+
+Only use the EXCERPTS as evidence. For supported or contradicted claims, quote the relevant excerpt text verbatim in "citation" and give its id in "spec_chunk_id".
+
+Reply with a JSON object: {"claims": [{"claim": "...", "verdict": "...", "explanation": "...", "citation": "...", "spec_chunk_id": "..."}]}. Return {"claims": []} if the content makes no claims about MCP.`
+
+// VerifyClaims asks the chat model to extract the claims in content and judge each
+// against the given spec sections
+func VerifyClaims(ctx context.Context, ask Asker, content string, sections []SpecSection) ([]ClaimVerdict, error) {
+	var prompt strings.Builder
+	prompt.WriteString("EXCERPTS:\n")
+	for _, s := range sections {
+		fmt.Fprintf(&prompt, "\n[id: %s]\n%s\n", s.ID, s.Content)
+	}
+	prompt.WriteString("\nCONTENT:\n")
+	prompt.WriteString(content)
+
+	reply, err := ask(ctx, claimSystemPrompt, prompt.String(), true)
+	if err != nil {
+		return nil, err
+	}
+
+	var parsed struct {
+		Claims []ClaimVerdict `json:"claims"`
+	}
+	if err := json.Unmarshal([]byte(reply), &parsed); err != nil {
+		return nil, fmt.Errorf("failed to parse claim verdicts: %w", err)
+	}
+
+	known := map[string]string{}
+	for _, s := range sections {
+		known[s.ID] = normalize(s.Content)
+	}
+	claims := parsed.Claims[:0]
+	for _, c := range parsed.Claims {
+		switch c.Verdict {
+		case VerdictSupported, VerdictContradicted, VerdictNotAddressed:
+		default:
+			c.Verdict = VerdictNotAddressed
+		}
+		// Drop citations the model invented rather than quoted
+		if section, ok := known[c.SpecChunkID]; !ok || !strings.Contains(section, normalize(c.Citation)) {
+			c.SpecChunkID, c.Citation = "", ""
+		}
+		claims = append(claims, c)
+	}
+	return claims, nil
+}
+
+func normalize(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
@@ -0,0 +1,71 @@
+// Package factcheck holds the LLM-backed checks that complement similarity-based validation
+package factcheck
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/carlisia/mcp-factcheck/internal/cost"
+	"github.com/sashabaranov/go-openai"
+)
+
+// ModelEnvVar overrides the chat model used for LLM-backed checks
+const ModelEnvVar = "MCP_FACTCHECK_CHAT_MODEL"
+
+// DefaultModel is the chat model used when ModelEnvVar is unset
+const DefaultModel = openai.GPT4oMini
+
+// Model returns the configured chat model
+func Model() string {
+	if m := os.Getenv(ModelEnvVar); m != "" {
+		return m
+	}
+	return DefaultModel
+}
+
+// Asker sends a system and user prompt to a chat model and returns the reply, as
+// AskOpenAI does for OpenAI
+type Asker func(ctx context.Context, system, prompt string, jsonReply bool) (string, error)
+
+// OpenAI returns an Asker for the configured OpenAI chat model
+func OpenAI(client *openai.Client) Asker {
+	return func(ctx context.Context, system, prompt string, jsonReply bool) (string, error) {
+		return AskOpenAI(ctx, client, system, prompt, jsonReply)
+	}
+}
+
+// AskOpenAI sends a system and user prompt to the chat model and returns the reply.
+// When jsonReply is set the model is constrained to answer with a JSON object.
+// The request's cost is attributed to the tool call in ctx, if any.
+func AskOpenAI(ctx context.Context, client *openai.Client, system, prompt string, jsonReply bool) (string, error) {
+	if client == nil {
+		return "", errors.New("no OpenAI client configured")
+	}
+	if err := cost.Allow(); err != nil {
+		return "", err
+	}
+
+	req := openai.ChatCompletionRequest{
+		Model: Model(),
+		Messages: []openai.ChatCompletionMessage{
+			{Role: openai.ChatMessageRoleSystem, Content: system},
+			{Role: openai.ChatMessageRoleUser, Content: prompt},
+		},
+		Temperature: 0,
+	}
+	if jsonReply {
+		req.ResponseFormat = &openai.ChatCompletionResponseFormat{Type: openai.ChatCompletionResponseFormatTypeJSONObject}
+	}
+
+	resp, err := client.CreateChatCompletion(ctx, req)
+	if err != nil {
+		return "", fmt.Errorf("chat completion failed: %w", err)
+	}
+	cost.Record(ctx, req.Model, resp.Usage.PromptTokens, resp.Usage.CompletionTokens)
+	if len(resp.Choices) == 0 {
+		return "", errors.New("chat completion returned no choices")
+	}
+	return resp.Choices[0].Message.Content, nil
+}
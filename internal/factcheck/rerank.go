@@ -0,0 +1,54 @@
+package factcheck
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+const rerankSystemPrompt = `You rank excerpts from the Model Context Protocol (MCP) specification by how relevant they are to a QUERY. The query may be a question, a passage of documentation, or code.
+
+Score every excerpt from 0 (unrelated) to 10 (directly addresses what the query is about). Prefer excerpts that define or constrain the exact methods, fields, and behaviors the query mentions over excerpts that only share its vocabulary.
+
+Reply with a JSON object: {"scores": [{"id": "...", "score": 0}]}, with one entry per excerpt.`
+
+// Rerank asks the chat model to score how relevant each spec section is to query.
+// It returns scores from 0 to 10 keyed by section ID; sections the model skipped
+// are missing from the map.
+func Rerank(ctx context.Context, ask Asker, query string, sections []SpecSection) (map[string]float64, error) {
+	var prompt strings.Builder
+	prompt.WriteString("EXCERPTS:\n")
+	for _, s := range sections {
+		fmt.Fprintf(&prompt, "\n[id: %s]\n%s\n", s.ID, s.Content)
+	}
+	prompt.WriteString("\nQUERY:\n")
+	prompt.WriteString(query)
+
+	reply, err := ask(ctx, rerankSystemPrompt, prompt.String(), true)
+	if err != nil {
+		return nil, err
+	}
+
+	var parsed struct {
+		Scores []struct {
+			ID    string  `json:"id"`
+			Score float64 `json:"score"`
+		} `json:"scores"`
+	}
+	if err := json.Unmarshal([]byte(reply), &parsed); err != nil {
+		return nil, fmt.Errorf("failed to parse rerank scores: %w", err)
+	}
+
+	known := map[string]bool{}
+	for _, s := range sections {
+		known[s.ID] = true
+	}
+	scores := map[string]float64{}
+	for _, s := range parsed.Scores {
+		if known[s.ID] {
+			scores[s.ID] = min(max(s.Score, 0), 10)
+		}
+	}
+	return scores, nil
+}
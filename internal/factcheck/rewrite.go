@@ -0,0 +1,42 @@
+package factcheck
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+const rewriteSystemPrompt = `You correct statements about the Model Context Protocol (MCP) using excerpts from its specification.
+
+Rewrite the CONTENT so that every statement about MCP agrees with the EXCERPTS. Change only what the ISSUES and EXCERPTS show to be wrong or imprecise, and keep everything else, including formatting, tone, and length, as it is. Don't add claims the EXCERPTS don't support.
+
+Reply with the rewritten content only, without commentary and without a code fence around it.`
+
+// SuggestRewrite asks the model for a corrected version of content, given the spec
+// sections it was compared with and the issues validation found
+func SuggestRewrite(ctx context.Context, ask Asker, content string, sections []SpecSection, issues []string) (string, error) {
+	var prompt strings.Builder
+	prompt.WriteString("EXCERPTS:\n")
+	for _, s := range sections {
+		fmt.Fprintf(&prompt, "\n[id: %s]\n%s\n", s.ID, s.Content)
+	}
+	if len(issues) > 0 {
+		prompt.WriteString("\nISSUES:\n")
+		for _, issue := range issues {
+			fmt.Fprintf(&prompt, "- %s\n", issue)
+		}
+	}
+	prompt.WriteString("\nCONTENT:\n")
+	prompt.WriteString(content)
+
+	reply, err := ask(ctx, rewriteSystemPrompt, prompt.String(), false)
+	if err != nil {
+		return "", err
+	}
+	reply = strings.TrimSpace(reply)
+	if reply == "" {
+		return "", errors.New("model returned an empty rewrite")
+	}
+	return reply, nil
+}
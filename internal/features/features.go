@@ -0,0 +1,122 @@
+package features
+
+import (
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+	"sync"
+)
+
+// Flag names an experimental capability that operators must opt in to
+type Flag string
+
+const (
+	// ClaimCheck enables LLM-backed claim extraction and verification
+	ClaimCheck Flag = "claim_check"
+	// SuggestRewrite enables generated rewrites of inaccurate content
+	SuggestRewrite Flag = "suggest_rewrite"
+	// SamplingJudge makes claim checks ask the client's model through MCP sampling,
+	// when the client supports it, even if the server has an API key
+	SamplingJudge Flag = "sampling_judge"
+	// Rerank enables the rerank argument of search_spec
+	Rerank Flag = "rerank"
+	// ConformanceCheck enables conformance_check, which launches the command it is
+	// given on the server's host
+	ConformanceCheck Flag = "conformance_check"
+)
+
+// EnvVar lists enabled flags as a comma-separated string
+const EnvVar = "MCP_FACTCHECK_FEATURES"
+
+// Known lists every flag this build understands
+var Known = []Flag{ClaimCheck, SuggestRewrite, SamplingJudge, Rerank, ConformanceCheck}
+
+var (
+	mu       sync.RWMutex
+	enabled  = map[Flag]bool{}
+	onChange []func()
+)
+
+// Enabled reports whether a flag is turned on
+func Enabled(f Flag) bool {
+	mu.RLock()
+	defer mu.RUnlock()
+	return enabled[f]
+}
+
+// List returns the enabled flags in a stable order
+func List() []Flag {
+	mu.RLock()
+	defer mu.RUnlock()
+	var flags []Flag
+	for _, f := range Known {
+		if enabled[f] {
+			flags = append(flags, f)
+		}
+	}
+	return flags
+}
+
+// Validate rejects unknown flag names so typos don't silently disable a feature
+func Validate(names []string) error {
+	_, err := parse(names)
+	return err
+}
+
+// Set replaces the enabled flags
+func Set(names []string) error {
+	next, err := parse(names)
+	if err != nil {
+		return err
+	}
+
+	mu.Lock()
+	enabled = next
+	callbacks := slices.Clone(onChange)
+	mu.Unlock()
+
+	for _, fn := range callbacks {
+		fn()
+	}
+	return nil
+}
+
+// FromEnv parses $MCP_FACTCHECK_FEATURES
+func FromEnv() []string {
+	value := os.Getenv(EnvVar)
+	if value == "" {
+		return nil
+	}
+	return strings.Split(value, ",")
+}
+
+// OnChange registers a callback invoked after the enabled flags change
+func OnChange(fn func()) {
+	mu.Lock()
+	defer mu.Unlock()
+	onChange = append(onChange, fn)
+}
+
+func parse(names []string) (map[Flag]bool, error) {
+	flags := map[Flag]bool{}
+	for _, name := range names {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+		if !slices.Contains(Known, Flag(name)) {
+			return nil, fmt.Errorf("unknown feature flag %q (known: %s)", name, knownNames())
+		}
+		flags[Flag(name)] = true
+	}
+	return flags, nil
+}
+
+func knownNames() string {
+	names := make([]string, len(Known))
+	for i, f := range Known {
+		names[i] = string(f)
+	}
+	return strings.Join(names, ", ")
+}
@@ -0,0 +1,111 @@
+package feedback
+
+import (
+	"math"
+	"sort"
+
+	"github.com/carlisia/mcp-factcheck/pkg/validator"
+)
+
+// MinSamples is the number of scored entries below which a calibration is reported as unreliable
+const MinSamples = 20
+
+// Calibration summarizes feedback and proposes validator thresholds
+type Calibration struct {
+	Entries            int                `json:"entries"`
+	Scored             int                `json:"scored"` // Entries carrying a confidence score
+	Correct            int                `json:"correct"`
+	FalsePositives     int                `json:"false_positives"`
+	CurrentThreshold   float64            `json:"current_similarity_threshold"`
+	CurrentPrecision   float64            `json:"current_precision"` // Share of flagged findings users confirmed
+	SuggestedThreshold float64            `json:"suggested_similarity_threshold"`
+	SuggestedPrecision float64            `json:"suggested_precision"`
+	SuggestedRecall    float64            `json:"suggested_recall"` // Share of confirmed findings still flagged
+	Reliable           bool               `json:"reliable"`
+	SuggestedSettings  validator.Settings `json:"suggested_settings"`
+}
+
+// Calibrate proposes a similarity threshold from feedback. A finding is raised when its confidence is
+// below the threshold, so confirmed findings should fall below it and false positives at or above it.
+// The threshold that misclassifies the fewest scored entries wins, preferring the one closest to the
+// current setting on ties.
+func Calibrate(entries []Entry, current validator.Settings) Calibration {
+	c := Calibration{
+		Entries:            len(entries),
+		CurrentThreshold:   current.SimilarityThreshold,
+		SuggestedThreshold: current.SimilarityThreshold,
+		SuggestedSettings:  current,
+	}
+
+	type sample struct {
+		confidence float64
+		correct    bool
+	}
+	var samples []sample
+	for _, e := range entries {
+		if e.Finding.Confidence <= 0 {
+			continue
+		}
+		s := sample{confidence: e.Finding.Confidence, correct: e.Verdict == VerdictCorrect}
+		if s.correct {
+			c.Correct++
+		} else {
+			c.FalsePositives++
+		}
+		samples = append(samples, s)
+	}
+	c.Scored = len(samples)
+	c.Reliable = c.Scored >= MinSamples && c.Correct > 0 && c.FalsePositives > 0
+	if c.Scored == 0 {
+		return c
+	}
+
+	evaluate := func(threshold float64) (errors int, precision, recall float64) {
+		var flaggedCorrect, flagged int
+		for _, s := range samples {
+			isFlagged := s.confidence < threshold
+			if isFlagged {
+				flagged++
+				if s.correct {
+					flaggedCorrect++
+				}
+			}
+			if isFlagged != s.correct {
+				errors++
+			}
+		}
+		if flagged > 0 {
+			precision = float64(flaggedCorrect) / float64(flagged)
+		}
+		if c.Correct > 0 {
+			recall = float64(flaggedCorrect) / float64(c.Correct)
+		}
+		return errors, precision, recall
+	}
+
+	// Candidate thresholds sit just above each observed score, plus the current one
+	candidates := []float64{current.SimilarityThreshold}
+	for _, s := range samples {
+		candidates = append(candidates, math.Round((s.confidence+0.005)*1000)/1000)
+	}
+	sort.Float64s(candidates)
+
+	_, c.CurrentPrecision, _ = evaluate(current.SimilarityThreshold)
+	bestErrors := math.MaxInt
+	for _, t := range candidates {
+		if t <= current.LowSimilarityThreshold || t > 1 {
+			continue
+		}
+		errs, precision, recall := evaluate(t)
+		closer := math.Abs(t-current.SimilarityThreshold) < math.Abs(c.SuggestedThreshold-current.SimilarityThreshold)
+		if errs < bestErrors || (errs == bestErrors && closer) {
+			bestErrors = errs
+			c.SuggestedThreshold = t
+			c.SuggestedPrecision = precision
+			c.SuggestedRecall = recall
+		}
+	}
+
+	c.SuggestedSettings.SimilarityThreshold = c.SuggestedThreshold
+	return c
+}
@@ -0,0 +1,32 @@
+package feedback
+
+import (
+	"encoding/json"
+	"net/http"
+)
+
+// NewHandler serves POST /feedback. The body is an Entry; id and time are assigned by the server.
+func NewHandler(store *Store) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		var entry Entry
+		if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, 1<<20)).Decode(&entry); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			_ = json.NewEncoder(w).Encode(map[string]string{"error": "invalid JSON body: " + err.Error()})
+			return
+		}
+		entry.ID = ""
+		entry.Source = "http"
+
+		stored, err := store.Append(entry)
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			_ = json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+			return
+		}
+
+		w.WriteHeader(http.StatusCreated)
+		_ = json.NewEncoder(w).Encode(stored)
+	})
+}
@@ -0,0 +1,146 @@
+package feedback
+
+import (
+	"bufio"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/carlisia/mcp-factcheck/internal/config"
+	"github.com/carlisia/mcp-factcheck/pkg/validator"
+)
+
+// Verdicts a user can give a finding
+const (
+	VerdictCorrect       = "correct"        // The finding points at a real problem
+	VerdictFalsePositive = "false_positive" // The flagged content actually matches the spec
+)
+
+// Verdicts lists the accepted verdicts
+var Verdicts = []string{VerdictCorrect, VerdictFalsePositive}
+
+// Entry is one piece of feedback on a finding, stored with the evidence and score it was based on
+type Entry struct {
+	ID          string                    `json:"id"`
+	Time        time.Time                 `json:"time"`
+	Verdict     string                    `json:"verdict"`
+	Finding     validator.ValidationError `json:"finding"`
+	SpecVersion string                    `json:"spec_version,omitempty"`
+	Tool        string                    `json:"tool,omitempty"` // Tool or integration that produced the finding
+	Comment     string                    `json:"comment,omitempty"`
+	Tenant      string                    `json:"tenant,omitempty"`
+	Source      string                    `json:"source,omitempty"` // "mcp" or "http"
+}
+
+// Validate checks that an entry can be stored
+func (e Entry) Validate() error {
+	switch e.Verdict {
+	case VerdictCorrect, VerdictFalsePositive:
+	default:
+		return fmt.Errorf("verdict must be %q or %q, got %q", VerdictCorrect, VerdictFalsePositive, e.Verdict)
+	}
+	if e.Finding.Found == "" && e.Finding.Message == "" {
+		return fmt.Errorf("feedback must identify the finding by its flagged text or message")
+	}
+	if e.Finding.Confidence < 0 || e.Finding.Confidence > 1 {
+		return fmt.Errorf("confidence must be in [0, 1], got %v", e.Finding.Confidence)
+	}
+	return nil
+}
+
+// DefaultPath returns the feedback file under the state directory
+func DefaultPath() string {
+	return filepath.Join(config.StateDir(), "feedback.jsonl")
+}
+
+// Store appends feedback entries to a JSON Lines file
+type Store struct {
+	path string
+	mu   sync.Mutex
+}
+
+// NewStore creates a store backed by path. The file is created on the first Append.
+func NewStore(path string) *Store {
+	return &Store{path: path}
+}
+
+// Path returns the file backing the store
+func (s *Store) Path() string {
+	return s.path
+}
+
+// Append validates and records an entry, filling in its ID and time
+func (s *Store) Append(entry Entry) (Entry, error) {
+	if err := entry.Validate(); err != nil {
+		return Entry{}, err
+	}
+	if entry.ID == "" {
+		entry.ID = newID()
+	}
+	if entry.Time.IsZero() {
+		entry.Time = time.Now().UTC()
+	}
+
+	line, err := json.Marshal(entry)
+	if err != nil {
+		return Entry{}, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := os.MkdirAll(filepath.Dir(s.path), 0o755); err != nil {
+		return Entry{}, fmt.Errorf("failed to create feedback directory: %w", err)
+	}
+	f, err := os.OpenFile(s.path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
+	if err != nil {
+		return Entry{}, fmt.Errorf("failed to open feedback file: %w", err)
+	}
+	defer f.Close()
+	if _, err := f.Write(append(line, '\n')); err != nil {
+		return Entry{}, fmt.Errorf("failed to write feedback: %w", err)
+	}
+	return entry, nil
+}
+
+// All reads every stored entry. A missing file means no feedback yet.
+func (s *Store) All() ([]Entry, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	f, err := os.Open(s.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to open feedback file: %w", err)
+	}
+	defer f.Close()
+
+	var entries []Entry
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), 4<<20)
+	for lineNo := 1; scanner.Scan(); lineNo++ {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+		var entry Entry
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			return nil, fmt.Errorf("%s:%d: %w", s.path, lineNo, err)
+		}
+		entries = append(entries, entry)
+	}
+	return entries, scanner.Err()
+}
+
+func newID() string {
+	b := make([]byte, 8)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
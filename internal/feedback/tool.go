@@ -0,0 +1,110 @@
+package feedback
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/carlisia/mcp-factcheck/internal/specs"
+	"github.com/carlisia/mcp-factcheck/internal/tenant"
+	"github.com/carlisia/mcp-factcheck/internal/toolerr"
+	"github.com/carlisia/mcp-factcheck/pkg/validator"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+const ReportFeedbackToolName = "report_feedback"
+
+func GetReportFeedbackTool() mcp.Tool {
+	schema := map[string]any{
+		"type": "object",
+		"properties": map[string]any{
+			"verdict": map[string]any{
+				"type":        "string",
+				"description": "Whether the finding is a real problem (correct) or the content actually matches the spec (false_positive)",
+				"enum":        Verdicts,
+			},
+			"found": map[string]any{
+				"type":        "string",
+				"description": "The flagged text, copied from the finding's 'found' field",
+			},
+			"message": map[string]any{
+				"type":        "string",
+				"description": "The finding's message",
+			},
+			"issueType": map[string]any{
+				"type":        "string",
+				"description": "The finding's type",
+				"enum":        []string{validator.IssueTypeInaccuracy, validator.IssueTypeMissing, validator.IssueTypeImprecise, validator.IssueTypeUnsupported},
+			},
+			"severity": map[string]any{
+				"type":        "string",
+				"description": "The finding's severity",
+				"enum":        []string{validator.SeverityCritical, validator.SeverityWarning, validator.SeveritySuggestion},
+			},
+			"specSection": map[string]any{
+				"type":        "string",
+				"description": "The spec section the finding cited",
+			},
+			"confidence": map[string]any{
+				"type":        "number",
+				"description": "The confidence score reported with the finding, used to calibrate thresholds",
+				"minimum":     0,
+				"maximum":     1,
+			},
+			"specVersion": map[string]any{
+				"type":        "string",
+				"description": "MCP specification version the content was validated against",
+				"enum":        specs.ValidSpecVersions,
+			},
+			"tool": map[string]any{
+				"type":        "string",
+				"description": "Tool that produced the finding, e.g. validate_content",
+			},
+			"comment": map[string]any{
+				"type":        "string",
+				"description": "Why the finding is right or wrong",
+			},
+		},
+		"required": []string{"verdict"},
+	}
+	schemaBytes, _ := json.Marshal(schema)
+	return mcp.NewToolWithRawSchema(ReportFeedbackToolName, "Report whether a fact-check finding was correct or a false positive. Use this when the user confirms or disputes a finding from validate_content or validate_code; the feedback is used to tune validation thresholds.", schemaBytes)
+}
+
+func HandleReportFeedback(ctx context.Context, store *Store, args any) ([]mcp.Content, error) {
+	params, ok := args.(map[string]any)
+	if !ok {
+		return nil, toolerr.InvalidArgs("arguments must be a map")
+	}
+
+	str := func(key string) string {
+		v, _ := params[key].(string)
+		return v
+	}
+	entry := Entry{
+		Verdict: str("verdict"),
+		Finding: validator.ValidationError{
+			Type:        str("issueType"),
+			Severity:    str("severity"),
+			Message:     str("message"),
+			Found:       str("found"),
+			SpecSection: str("specSection"),
+		},
+		SpecVersion: str("specVersion"),
+		Tool:        str("tool"),
+		Comment:     str("comment"),
+		Source:      "mcp",
+	}
+	if confidence, ok := params["confidence"].(float64); ok {
+		entry.Finding.Confidence = confidence
+	}
+	if t := tenant.FromContext(ctx); t != nil {
+		entry.Tenant = t.Name
+	}
+
+	entry, err := store.Append(entry)
+	if err != nil {
+		return nil, err
+	}
+	return []mcp.Content{mcp.NewTextContent(fmt.Sprintf("Recorded %s feedback (id %s). Thank you!", entry.Verdict, entry.ID))}, nil
+}
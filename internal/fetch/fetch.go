@@ -0,0 +1,355 @@
+package fetch
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"mime"
+	"net"
+	"net/http"
+	"net/netip"
+	"net/url"
+	"strings"
+	"syscall"
+	"time"
+
+	"golang.org/x/net/html"
+)
+
+// MaxBodySize caps how much of a remote document is read
+const MaxBodySize = 5 << 20
+
+// publicClient refuses to connect to loopback, private, and link-local addresses, so a
+// server fetching URLs on its clients' behalf can't be pointed at internal services.
+// The check runs on every connection, including redirects, after DNS resolution.
+var publicClient = func() *http.Client {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = nil // A proxy would make the dialed address the proxy's, not the target's
+	transport.DialContext = (&net.Dialer{Timeout: 10 * time.Second, Control: publicOnly}).DialContext
+	return &http.Client{Timeout: 30 * time.Second, Transport: transport}
+}()
+
+// Document is a fetched page
+type Document struct {
+	URL   string // After redirects
+	Title string // From the HTML <title>, if any
+	Text  string // Markdown for HTML pages; markdown and plain text as served
+}
+
+// IsURL reports whether s looks like an http(s) URL
+func IsURL(s string) bool {
+	u, err := url.Parse(strings.TrimSpace(s))
+	return err == nil && (u.Scheme == "http" || u.Scheme == "https") && u.Host != ""
+}
+
+// GetPublic downloads a document and extracts its readable text, refusing URLs that
+// resolve to loopback, private, or link-local addresses. HTML pages are reduced to the
+// markdown of their main content; markdown and plain text are returned as-is. Every
+// URL fetched comes from a caller, so there is deliberately no unguarded variant.
+func GetPublic(ctx context.Context, rawURL string) (*Document, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, strings.TrimSpace(rawURL), nil)
+	if err != nil {
+		return nil, fmt.Errorf("invalid URL: %w", err)
+	}
+	req.Header.Set("User-Agent", "mcp-factcheck")
+	req.Header.Set("Accept", "text/markdown, text/plain, text/html;q=0.9, */*;q=0.5")
+
+	resp, err := publicClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch %s: %w", rawURL, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to fetch %s: HTTP %d", rawURL, resp.StatusCode)
+	}
+
+	contentType, _, _ := mime.ParseMediaType(resp.Header.Get("Content-Type"))
+	if !isText(contentType) {
+		return nil, fmt.Errorf("failed to fetch %s: unsupported content type %s", rawURL, contentType)
+	}
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, MaxBodySize))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", rawURL, err)
+	}
+
+	doc := &Document{URL: resp.Request.URL.String(), Text: string(body)}
+	if strings.Contains(contentType, "html") {
+		if doc.Title, doc.Text, err = htmlMarkdown(string(body)); err != nil {
+			return nil, err
+		}
+	}
+	return doc, nil
+}
+
+// isText reports whether a media type is readable text. Servers that send no type get
+// the benefit of the doubt.
+func isText(contentType string) bool {
+	switch {
+	case contentType == "", strings.HasPrefix(contentType, "text/"):
+		return true
+	case strings.HasSuffix(contentType, "+xml"), strings.HasSuffix(contentType, "+json"):
+		return true
+	}
+	switch contentType {
+	case "application/xml", "application/json", "application/markdown":
+		return true
+	}
+	return false
+}
+
+// publicOnly is a net.Dialer Control function that rejects non-public addresses
+func publicOnly(network, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+	addr, err := netip.ParseAddr(host)
+	if err != nil {
+		return err
+	}
+	addr = addr.Unmap()
+	if addr.IsLoopback() || addr.IsPrivate() || addr.IsLinkLocalUnicast() || addr.IsUnspecified() ||
+		addr.IsMulticast() || addr.IsLinkLocalMulticast() || addr.IsInterfaceLocalMulticast() {
+		return fmt.Errorf("refusing to connect to non-public address %s", addr)
+	}
+	return nil
+}
+
+// htmlMarkdown extracts the title of an HTML document and converts its main content to
+// markdown. The main content is the <main> element, else the first <article>, else the
+// body without its page header and footer; navigation, sidebars, and scripts are dropped.
+func htmlMarkdown(doc string) (string, string, error) {
+	root, err := html.Parse(strings.NewReader(doc))
+	if err != nil {
+		return "", "", fmt.Errorf("failed to parse HTML: %w", err)
+	}
+
+	var title string
+	if n := findElement(root, func(n *html.Node) bool { return n.Data == "title" }); n != nil {
+		title = strings.Join(strings.Fields(textContent(n)), " ")
+	}
+
+	content := findElement(root, func(n *html.Node) bool { return n.Data == "main" || attr(n, "role") == "main" })
+	if content == nil {
+		content = findElement(root, func(n *html.Node) bool { return n.Data == "article" })
+	}
+	w := &markdownWriter{}
+	if content == nil {
+		content = findElement(root, func(n *html.Node) bool { return n.Data == "body" })
+		w.skipPageChrome = true
+	}
+	if content == nil {
+		content = root
+	}
+	w.walk(content)
+	return title, w.String(), nil
+}
+
+// findElement returns the first element, in document order, matching match
+func findElement(n *html.Node, match func(*html.Node) bool) *html.Node {
+	if n.Type == html.ElementNode && match(n) {
+		return n
+	}
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		if found := findElement(c, match); found != nil {
+			return found
+		}
+	}
+	return nil
+}
+
+func attr(n *html.Node, key string) string {
+	for _, a := range n.Attr {
+		if a.Key == key {
+			return a.Val
+		}
+	}
+	return ""
+}
+
+// textContent returns the text under n with whitespace as in the source
+func textContent(n *html.Node) string {
+	if n.Type == html.TextNode {
+		return n.Data
+	}
+	var b strings.Builder
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		b.WriteString(textContent(c))
+	}
+	return b.String()
+}
+
+// markdownWriter renders HTML as markdown: headings, lists, code, and paragraphs.
+// Inline whitespace is collapsed as a browser would; line breaks are only emitted
+// between blocks, and only once text follows, so empty elements leave no gaps.
+type markdownWriter struct {
+	b              strings.Builder
+	breaks         int  // Newlines owed before the next text
+	space          bool // A space is owed before the next inline text
+	lists          []listState
+	skipPageChrome bool // Drop <header> and <footer>, which on a whole page are site chrome
+}
+
+type listState struct {
+	ordered bool
+	items   int
+}
+
+func (w *markdownWriter) String() string {
+	return w.b.String()
+}
+
+// block ends the current paragraph
+func (w *markdownWriter) block() {
+	if w.b.Len() > 0 {
+		w.breaks = 2
+	}
+	w.space = false
+}
+
+// line ends the current line
+func (w *markdownWriter) line() {
+	if w.b.Len() > 0 {
+		w.breaks = max(w.breaks, 1)
+	}
+	w.space = false
+}
+
+// raw writes s verbatim after any owed line breaks
+func (w *markdownWriter) raw(s string) {
+	for ; w.breaks > 0; w.breaks-- {
+		w.b.WriteByte('\n')
+	}
+	w.b.WriteString(s)
+}
+
+// text writes inline text with its whitespace collapsed
+func (w *markdownWriter) text(s string) {
+	words := strings.Fields(s)
+	if len(words) == 0 {
+		if s != "" {
+			w.space = true
+		}
+		return
+	}
+	leading := s[0] == ' ' || s[0] == '\t' || s[0] == '\n' || s[0] == '\r'
+	if (w.space || leading) && w.breaks == 0 && w.b.Len() > 0 && !strings.HasSuffix(w.b.String(), " ") {
+		w.raw(" ")
+	}
+	w.raw(strings.Join(words, " "))
+	last := s[len(s)-1]
+	w.space = last == ' ' || last == '\t' || last == '\n' || last == '\r'
+}
+
+func (w *markdownWriter) walk(n *html.Node) {
+	switch n.Type {
+	case html.TextNode:
+		w.text(n.Data)
+		return
+	case html.ElementNode:
+	default:
+		w.children(n)
+		return
+	}
+
+	switch n.Data {
+	case "script", "style", "noscript", "template", "nav", "aside", "form", "button", "svg", "iframe", "img":
+		return
+	case "header", "footer":
+		if w.skipPageChrome {
+			return
+		}
+		w.block()
+		w.children(n)
+		w.block()
+	case "h1", "h2", "h3", "h4", "h5", "h6":
+		w.block()
+		w.raw(strings.Repeat("#", int(n.Data[1]-'0')) + " ")
+		w.children(n)
+		w.block()
+	case "pre":
+		w.block()
+		w.raw("```\n" + strings.TrimRight(textContent(n), "\n") + "\n```")
+		w.block()
+	case "code":
+		if code := strings.Join(strings.Fields(textContent(n)), " "); code != "" {
+			w.inline("`" + code + "`")
+		}
+	case "br":
+		w.line()
+	case "hr":
+		w.block()
+	case "ul", "ol":
+		if len(w.lists) == 0 {
+			w.block()
+		} else {
+			w.line()
+		}
+		w.lists = append(w.lists, listState{ordered: n.Data == "ol"})
+		w.children(n)
+		w.lists = w.lists[:len(w.lists)-1]
+		if len(w.lists) == 0 {
+			w.block()
+		} else {
+			w.line()
+		}
+	case "li":
+		w.line()
+		marker := "- "
+		if depth := len(w.lists); depth > 0 {
+			list := &w.lists[depth-1]
+			list.items++
+			if list.ordered {
+				marker = fmt.Sprintf("%d. ", list.items)
+			}
+			marker = strings.Repeat("  ", depth-1) + marker
+		}
+		w.raw(marker)
+		w.children(n)
+		w.line()
+	case "td", "th":
+		if hasPrevElement(n) {
+			w.inline("|")
+			w.space = true
+		}
+		w.children(n)
+		w.space = true
+	case "tr", "dt", "dd", "figcaption", "caption":
+		w.line()
+		w.children(n)
+		w.line()
+	case "p", "div", "section", "article", "main", "blockquote", "table", "dl", "figure", "details", "summary":
+		w.block()
+		w.children(n)
+		w.block()
+	default:
+		w.children(n)
+	}
+}
+
+func (w *markdownWriter) children(n *html.Node) {
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		w.walk(c)
+	}
+}
+
+// inline writes s as a single inline token, separated from preceding text if a space is owed
+func (w *markdownWriter) inline(s string) {
+	if w.space && w.breaks == 0 && w.b.Len() > 0 {
+		w.raw(" ")
+	}
+	w.raw(s)
+	w.space = false
+}
+
+// hasPrevElement reports whether n has an element sibling before it
+func hasPrevElement(n *html.Node) bool {
+	for s := n.PrevSibling; s != nil; s = s.PrevSibling {
+		if s.Type == html.ElementNode {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,34 @@
+package fetch
+
+import "testing"
+
+func TestPublicOnly(t *testing.T) {
+	tests := []struct {
+		address string
+		public  bool
+	}{
+		{address: "93.184.215.14:443", public: true},
+		{address: "[2606:2800:21f:cb07:6820:80da:af6b:8b2c]:443", public: true},
+		{address: "127.0.0.1:80"},
+		{address: "[::1]:80"},
+		{address: "10.0.0.5:443"},
+		{address: "172.16.3.4:443"},
+		{address: "192.168.1.1:80"},
+		{address: "[fd00::1]:443"},
+		{address: "169.254.169.254:80"}, // Cloud metadata endpoint
+		{address: "[fe80::1]:80"},
+		{address: "0.0.0.0:80"},
+		{address: "[::]:80"},
+		{address: "224.0.0.1:80"},
+		{address: "[::ffff:127.0.0.1]:80"}, // IPv4-mapped loopback
+		{address: "[::ffff:10.0.0.1]:80"},
+		{address: "localhost:80"}, // Dialers pass resolved addresses, so names are refused
+		{address: "127.0.0.1"},    // No port
+	}
+	for _, tt := range tests {
+		err := publicOnly("tcp", tt.address, nil)
+		if (err == nil) != tt.public {
+			t.Errorf("publicOnly(%s) = %v, want public %v", tt.address, err, tt.public)
+		}
+	}
+}
@@ -0,0 +1,291 @@
+package githubbot
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/carlisia/mcp-factcheck/embedding"
+	mcpembedding "github.com/carlisia/mcp-factcheck/internal/embedding"
+	"github.com/carlisia/mcp-factcheck/pkg/logger"
+	"github.com/carlisia/mcp-factcheck/pkg/validator"
+	"github.com/google/go-github/v57/github"
+	"go.uber.org/zap"
+)
+
+// Config controls which pull requests the bot reviews and how
+type Config struct {
+	SpecVersion   string
+	WebhookSecret string
+	MaxFiles      int           // Maximum markdown files reviewed per pull request
+	Timeout       time.Duration // Maximum time spent reviewing one pull request
+}
+
+// Bot reviews markdown changes in pull requests against the MCP specification
+type Bot struct {
+	client    *github.Client
+	vectorDB  *mcpembedding.VectorDB
+	generator *embedding.Generator
+	config    Config
+}
+
+// New creates a GitHub review bot
+func New(client *github.Client, vectorDB *mcpembedding.VectorDB, generator *embedding.Generator, config Config) *Bot {
+	if config.MaxFiles <= 0 {
+		config.MaxFiles = 20
+	}
+	if config.Timeout <= 0 {
+		config.Timeout = 10 * time.Minute
+	}
+	return &Bot{
+		client:    client,
+		vectorDB:  vectorDB,
+		generator: generator,
+		config:    config,
+	}
+}
+
+// ServeHTTP receives GitHub webhooks and reviews opened or updated pull requests in the background
+func (b *Bot) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	log := logger.Get()
+
+	payload, err := github.ValidatePayload(r, []byte(b.config.WebhookSecret))
+	if err != nil {
+		log.Warn("Rejected webhook", zap.Error(err))
+		http.Error(w, "invalid signature", http.StatusUnauthorized)
+		return
+	}
+
+	event, err := github.ParseWebHook(github.WebHookType(r), payload)
+	if err != nil {
+		http.Error(w, "unsupported payload", http.StatusBadRequest)
+		return
+	}
+
+	prEvent, ok := event.(*github.PullRequestEvent)
+	if !ok {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+
+	switch prEvent.GetAction() {
+	case "opened", "synchronize", "reopened", "ready_for_review":
+	default:
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+
+	owner := prEvent.GetRepo().GetOwner().GetLogin()
+	repo := prEvent.GetRepo().GetName()
+	number := prEvent.GetNumber()
+	headSHA := prEvent.GetPullRequest().GetHead().GetSHA()
+
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), b.config.Timeout)
+		defer cancel()
+		if err := b.ReviewPullRequest(ctx, owner, repo, number, headSHA); err != nil {
+			log.Error("Pull request review failed",
+				zap.String("repo", owner+"/"+repo),
+				zap.Int("pr", number),
+				zap.Error(err))
+		}
+	}()
+
+	w.WriteHeader(http.StatusAccepted)
+}
+
+// ReviewPullRequest validates changed markdown files at headSHA and posts a review
+func (b *Bot) ReviewPullRequest(ctx context.Context, owner, repo string, number int, headSHA string) error {
+	log := logger.Get().With(zap.String("repo", owner+"/"+repo), zap.Int("pr", number))
+
+	files, err := b.changedMarkdownFiles(ctx, owner, repo, number)
+	if err != nil {
+		return err
+	}
+	if len(files) == 0 {
+		log.Info("No markdown changes to review")
+		return nil
+	}
+
+	var comments []*github.DraftReviewComment
+	var outsideDiff []string
+	var totalFindings int
+
+	for _, file := range files {
+		content, err := b.fileContent(ctx, owner, repo, file.GetFilename(), headSHA)
+		if err != nil {
+			log.Warn("Skipping unreadable file", zap.String("file", file.GetFilename()), zap.Error(err))
+			continue
+		}
+
+		result, err := validator.Validate(ctx, b.vectorDB, b.generator, validator.Request{Content: content, SpecVersion: b.config.SpecVersion, Chunked: true})
+		if err != nil {
+			return fmt.Errorf("failed to validate %s: %w", file.GetFilename(), err)
+		}
+
+		commentable := CommentableLines(file.GetPatch())
+		for _, finding := range result.Findings() {
+			totalFindings++
+			body := FormatComment(finding, b.config.SpecVersion)
+			if finding.LineNumber > 0 && commentable[finding.LineNumber] {
+				comments = append(comments, &github.DraftReviewComment{
+					Path: github.String(file.GetFilename()),
+					Line: github.Int(finding.LineNumber),
+					Side: github.String("RIGHT"),
+					Body: github.String(body),
+				})
+				continue
+			}
+			// GitHub only accepts inline comments on lines in the diff
+			outsideDiff = append(outsideDiff, fmt.Sprintf("**%s** (line %d)\n\n%s", file.GetFilename(), finding.LineNumber, body))
+		}
+	}
+
+	review := &github.PullRequestReviewRequest{
+		CommitID: github.String(headSHA),
+		Event:    github.String("COMMENT"),
+		Body:     github.String(reviewSummary(len(files), totalFindings, outsideDiff, b.config.SpecVersion)),
+		Comments: comments,
+	}
+	if _, _, err := b.client.PullRequests.CreateReview(ctx, owner, repo, number, review); err != nil {
+		return fmt.Errorf("failed to post review: %w", err)
+	}
+
+	log.Info("Posted pull request review",
+		zap.Int("files", len(files)),
+		zap.Int("findings", totalFindings),
+		zap.Int("inline_comments", len(comments)))
+	return nil
+}
+
+func (b *Bot) changedMarkdownFiles(ctx context.Context, owner, repo string, number int) ([]*github.CommitFile, error) {
+	var files []*github.CommitFile
+	opts := &github.ListOptions{PerPage: 100}
+	for {
+		page, resp, err := b.client.PullRequests.ListFiles(ctx, owner, repo, number, opts)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list pull request files: %w", err)
+		}
+		for _, f := range page {
+			if f.GetStatus() == "removed" || !IsMarkdown(f.GetFilename()) {
+				continue
+			}
+			files = append(files, f)
+			if len(files) >= b.config.MaxFiles {
+				return files, nil
+			}
+		}
+		if resp.NextPage == 0 {
+			return files, nil
+		}
+		opts.Page = resp.NextPage
+	}
+}
+
+func (b *Bot) fileContent(ctx context.Context, owner, repo, filePath, ref string) (string, error) {
+	file, _, _, err := b.client.Repositories.GetContents(ctx, owner, repo, filePath, &github.RepositoryContentGetOptions{Ref: ref})
+	if err != nil {
+		return "", err
+	}
+	if file == nil {
+		return "", fmt.Errorf("%s is not a file", filePath)
+	}
+	return file.GetContent()
+}
+
+// IsMarkdown reports whether a path is a markdown or MDX document
+func IsMarkdown(name string) bool {
+	switch strings.ToLower(path.Ext(name)) {
+	case ".md", ".mdx", ".markdown":
+		return true
+	}
+	return false
+}
+
+// CommentableLines returns the new-file line numbers present in a unified diff patch
+func CommentableLines(patch string) map[int]bool {
+	lines := map[int]bool{}
+	newLine := 0
+	for _, line := range strings.Split(patch, "\n") {
+		if strings.HasPrefix(line, "@@") {
+			// @@ -a,b +c,d @@
+			var oldStart, newStart int
+			if _, err := fmt.Sscanf(line, "@@ -%d", &oldStart); err != nil {
+				continue
+			}
+			if i := strings.Index(line, "+"); i >= 0 {
+				fmt.Sscanf(line[i:], "+%d", &newStart)
+			}
+			newLine = newStart
+			continue
+		}
+		if newLine == 0 {
+			continue
+		}
+		switch {
+		case strings.HasPrefix(line, "-"):
+			// Removed lines don't exist in the new file
+		case strings.HasPrefix(line, "\\"):
+			// "\ No newline at end of file"
+		default:
+			lines[newLine] = true
+			newLine++
+		}
+	}
+	return lines
+}
+
+// FormatComment renders a finding as a markdown review comment with its spec citation
+func FormatComment(f validator.ValidationError, specVersion string) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s\n", severityHeading(f))
+	if f.Found != "" {
+		fmt.Fprintf(&b, "\n> %s\n", strings.ReplaceAll(f.Found, "\n", "\n> "))
+	}
+	if f.SpecSection != "" {
+		fmt.Fprintf(&b, "\n**Spec reference (%s):** %s\n", specVersion, f.SpecSection)
+	}
+	if f.Expected != "" {
+		fmt.Fprintf(&b, "\n**Expected:** %s\n", f.Expected)
+	}
+	if f.SuggestedRewrite != "" {
+		fmt.Fprintf(&b, "\n**Suggested rewrite:**\n\n> %s\n", strings.ReplaceAll(f.SuggestedRewrite, "\n", "\n> "))
+	}
+	if len(f.Suggestions) > 0 {
+		b.WriteString("\n")
+		for _, s := range f.Suggestions {
+			fmt.Fprintf(&b, "- %s\n", s)
+		}
+	}
+	return b.String()
+}
+
+func severityHeading(f validator.ValidationError) string {
+	switch f.Severity {
+	case validator.SeverityCritical:
+		return "❌ **" + f.Message + "**"
+	case validator.SeverityWarning:
+		return "⚠️ **" + f.Message + "**"
+	default:
+		return "💡 **" + f.Message + "**"
+	}
+}
+
+func reviewSummary(files, findings int, outsideDiff []string, specVersion string) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "### MCP fact-check (spec %s)\n\n", specVersion)
+	if findings == 0 {
+		fmt.Fprintf(&b, "Checked %d markdown file(s); no issues found.\n", files)
+		return b.String()
+	}
+	fmt.Fprintf(&b, "Checked %d markdown file(s) and found %d potential issue(s).\n", files, findings)
+	if len(outsideDiff) > 0 {
+		b.WriteString("\nThese findings are on lines outside this diff:\n\n")
+		b.WriteString(strings.Join(outsideDiff, "\n\n---\n\n"))
+		b.WriteString("\n")
+	}
+	return b.String()
+}
@@ -0,0 +1,54 @@
+package history
+
+import "github.com/carlisia/mcp-factcheck/pkg/validator"
+
+// Diff is what changed between two runs
+type Diff struct {
+	From             string                      `json:"from"`
+	To               string                      `json:"to"`
+	SameInput        bool                        `json:"same_input"` // The text was unchanged, so differences come from the validator
+	ConfidenceChange float64                     `json:"confidence_change"`
+	Added            []validator.ValidationError `json:"added,omitempty"`    // Findings only in the later run
+	Resolved         []validator.ValidationError `json:"resolved,omitempty"` // Findings only in the earlier run
+	Unchanged        int                         `json:"unchanged"`
+}
+
+// findingKey identifies a finding across runs. Line numbers are left out, since
+// edits elsewhere in a document move findings without changing them.
+type findingKey struct {
+	issueType, message, found string
+}
+
+func keyOf(f validator.ValidationError) findingKey {
+	return findingKey{f.Type, f.Message, f.Found}
+}
+
+// Compare reports how the findings of to differ from those of from
+func Compare(from, to Run) Diff {
+	d := Diff{
+		From:             from.ID,
+		To:               to.ID,
+		SameInput:        from.InputHash == to.InputHash,
+		ConfidenceChange: to.Confidence - from.Confidence,
+	}
+
+	remaining := map[findingKey]int{}
+	for _, f := range from.Findings {
+		remaining[keyOf(f)]++
+	}
+	for _, f := range to.Findings {
+		if key := keyOf(f); remaining[key] > 0 {
+			remaining[key]--
+			d.Unchanged++
+		} else {
+			d.Added = append(d.Added, f)
+		}
+	}
+	for _, f := range from.Findings {
+		if key := keyOf(f); remaining[key] > 0 {
+			remaining[key]--
+			d.Resolved = append(d.Resolved, f)
+		}
+	}
+	return d
+}
@@ -0,0 +1,320 @@
+package history
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/carlisia/mcp-factcheck/internal/tenant"
+)
+
+// pageLimit is how many runs the history page lists; exports include every match
+const pageLimit = 200
+
+// filterParams are the query parameters that select runs, kept across the page's forms
+var filterParams = []string{"document", "tool", "result", "q", "since", "until"}
+
+var pageTemplate = template.Must(template.New("history").Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>Validation history{{with .Document}} - {{.}}{{end}}</title>
+<style>
+body { font-family: system-ui, sans-serif; margin: 2em; }
+table { border-collapse: collapse; }
+th, td { border-bottom: 1px solid #ddd; padding: 4px 10px; text-align: left; vertical-align: top; }
+.valid { color: #17803d; } .invalid { color: #b42318; }
+code { font-size: 90%; }
+form.filters label { margin-right: 1em; }
+</style>
+</head>
+<body>
+<h1>Validation history</h1>
+{{if .Error}}<p class="invalid">{{.Error}}</p>{{end}}
+<form method="get" class="filters">
+<p>
+<label>Document <select name="document">
+<option value="">All documents</option>
+{{range .Documents}}<option value="{{.}}"{{if eq . $.Document}} selected{{end}}>{{.}}</option>{{end}}
+</select></label>
+<label>Tool <select name="tool">
+<option value="">All tools</option>
+{{range .Tools}}<option value="{{.}}"{{if eq . $.Tool}} selected{{end}}>{{.}}</option>{{end}}
+</select></label>
+<label>Result <select name="result">
+<option value="">Any</option>
+<option value="valid"{{if eq .Result "valid"}} selected{{end}}>Valid</option>
+<option value="flagged"{{if eq .Result "flagged"}} selected{{end}}>Flagged</option>
+</select></label>
+</p>
+<p>
+<label>Search <input type="search" name="q" value="{{.Query}}" placeholder="Document, finding, section..."></label>
+<label>From <input type="datetime-local" name="since" value="{{.Since}}"></label>
+<label>To <input type="datetime-local" name="until" value="{{.Until}}"></label> (UTC)
+</p>
+<p>
+<button type="submit">Filter</button>
+<button type="submit" name="format" value="json">Export JSON</button>
+<button type="submit" name="format" value="csv">Export CSV</button>
+{{.Matched}} matching run(s){{if gt .Matched (len .Runs)}}, the newest {{len .Runs}} shown{{end}}
+</p>
+</form>
+{{with .Diff}}
+<h2>Changes from <code>{{.From}}</code> to <code>{{.To}}</code></h2>
+<p>Confidence {{printf "%+.3f" .ConfidenceChange}}{{if .SameInput}} (same text){{end}};
+{{len .Added}} added, {{len .Resolved}} resolved, {{.Unchanged}} unchanged.</p>
+{{if .Added}}<h3>Added</h3><ul>{{range .Added}}<li><b>{{.Severity}}</b> {{.Message}}{{with .Found}}: <q>{{.}}</q>{{end}}</li>{{end}}</ul>{{end}}
+{{if .Resolved}}<h3>Resolved</h3><ul>{{range .Resolved}}<li><b>{{.Severity}}</b> {{.Message}}{{with .Found}}: <q>{{.}}</q>{{end}}</li>{{end}}</ul>{{end}}
+{{end}}
+<form method="get">
+{{range $name, $value := .Params}}<input type="hidden" name="{{$name}}" value="{{$value}}">
+{{end}}<table>
+<tr><th>From</th><th>To</th><th>Time</th><th>Tool</th><th>Document</th><th>Spec</th><th>Result</th><th>Confidence</th><th>Critical</th><th>Warning</th><th>Suggestion</th><th>Input</th><th>ID</th></tr>
+{{range .Runs}}<tr>
+<td><input type="radio" name="from" value="{{.ID}}"></td>
+<td><input type="radio" name="to" value="{{.ID}}"></td>
+<td>{{.Time.Format "2006-01-02 15:04:05"}}</td>
+<td>{{.Tool}}</td>
+<td>{{.Document}}</td>
+<td>{{.SpecVersion}}</td>
+<td>{{if .Valid}}<span class="valid">valid</span>{{else}}<span class="invalid">flagged</span>{{end}}</td>
+<td>{{printf "%.3f" .Confidence}}</td>
+<td>{{.Counts.Critical}}</td>
+<td>{{.Counts.Warning}}</td>
+<td>{{.Counts.Suggestion}}</td>
+<td><code>{{slice .InputHash 0 12}}</code> ({{.InputLength}} chars)</td>
+<td><code>{{.ID}}</code></td>
+</tr>{{else}}<tr><td colspan="13">No validation runs match.</td></tr>{{end}}
+</table>
+<p><button type="submit">Compare selected runs</button></p>
+</form>
+</body>
+</html>
+`))
+
+// NewPage serves an HTML page listing recorded runs that diffs two runs selected with
+// the from and to query parameters. Runs are filtered by document, tool, result
+// (valid or flagged), text (q), and time (since and until, in UTC); format=json or
+// format=csv downloads every matching run instead. Requests carrying a tenant only
+// see that tenant's runs.
+func NewPage(store *Store) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var tenantName string
+		if t := tenant.FromContext(r.Context()); t != nil {
+			tenantName = t.Name
+		}
+		query := r.URL.Query()
+		data := struct {
+			Document  string
+			Tool      string
+			Result    string
+			Query     string
+			Since     string
+			Until     string
+			Params    map[string]string // Filters to keep when comparing runs
+			Documents []string
+			Tools     []string
+			Runs      []Run
+			Matched   int
+			Diff      *Diff
+			Error     string
+		}{
+			Document: query.Get("document"),
+			Tool:     query.Get("tool"),
+			Result:   query.Get("result"),
+			Query:    strings.TrimSpace(query.Get("q")),
+			Since:    query.Get("since"),
+			Until:    query.Get("until"),
+			Params:   map[string]string{},
+		}
+		for _, name := range filterParams {
+			if value := query.Get(name); value != "" {
+				data.Params[name] = value
+			}
+		}
+
+		filter, err := pageFilter(query)
+		if err != nil {
+			if format := query.Get("format"); format != "" {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+			data.Error = err.Error()
+		}
+		filter.Tenant = tenantName
+
+		all, err := store.Query(Filter{Tenant: tenantName})
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		data.Documents = Documents(all)
+		data.Tools = Tools(all)
+		var matched []Run
+		for _, run := range all {
+			if filter.matches(run) {
+				matched = append(matched, run)
+			}
+		}
+
+		switch format := query.Get("format"); format {
+		case "":
+		case "json", "csv":
+			if err := writeExport(w, format, matched); err != nil {
+				http.Error(w, err.Error(), http.StatusInternalServerError)
+			}
+			return
+		default:
+			http.Error(w, fmt.Sprintf("unknown format %q: want json or csv", format), http.StatusBadRequest)
+			return
+		}
+
+		data.Matched = len(matched)
+		data.Runs = matched
+		if len(data.Runs) > pageLimit {
+			data.Runs = data.Runs[:pageLimit]
+		}
+
+		if from, to := query.Get("from"), query.Get("to"); from != "" && to != "" {
+			fromRun, err := store.Get(from, tenantName)
+			if err == nil {
+				var toRun Run
+				if toRun, err = store.Get(to, tenantName); err == nil {
+					diff := Compare(fromRun, toRun)
+					data.Diff = &diff
+				}
+			}
+			if err != nil {
+				data.Error = err.Error()
+			}
+		}
+
+		var page bytes.Buffer
+		if err := pageTemplate.Execute(&page, data); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		_, _ = page.WriteTo(w)
+	})
+}
+
+// pageFilter reads the page's filters from its query. A filter that can't be parsed
+// is left out, and its error returned with the others applied.
+func pageFilter(query url.Values) (Filter, error) {
+	filter := Filter{
+		Document: query.Get("document"),
+		Tool:     query.Get("tool"),
+		Text:     strings.TrimSpace(query.Get("q")),
+	}
+	var errs []string
+	switch result := query.Get("result"); result {
+	case "":
+	case "valid", "flagged":
+		valid := result == "valid"
+		filter.Valid = &valid
+	default:
+		errs = append(errs, fmt.Sprintf("unknown result %q: want valid or flagged", result))
+	}
+	var err error
+	if filter.Since, err = parseTime(query.Get("since"), false); err != nil {
+		errs = append(errs, "since: "+err.Error())
+	}
+	if filter.Until, err = parseTime(query.Get("until"), true); err != nil {
+		errs = append(errs, "until: "+err.Error())
+	}
+	if len(errs) > 0 {
+		return filter, fmt.Errorf("%s", strings.Join(errs, "; "))
+	}
+	return filter, nil
+}
+
+// timeLayouts are the times the page accepts, in UTC unless they carry a zone: what
+// datetime-local inputs send, with and without seconds, dates, and RFC 3339
+var timeLayouts = []string{"2006-01-02T15:04", "2006-01-02T15:04:05", "2006-01-02", time.RFC3339}
+
+// parseTime parses a time filter; an empty value is no bound. As an upper bound, a
+// time without seconds or a date includes the whole minute or day.
+func parseTime(value string, upper bool) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+	for _, layout := range timeLayouts {
+		t, err := time.ParseInLocation(layout, value, time.UTC)
+		if err != nil {
+			continue
+		}
+		if upper {
+			switch layout {
+			case "2006-01-02T15:04":
+				t = t.Add(time.Minute)
+			case "2006-01-02":
+				t = t.AddDate(0, 0, 1)
+			case "2006-01-02T15:04:05":
+				t = t.Add(time.Second)
+			}
+		}
+		return t, nil
+	}
+	return time.Time{}, fmt.Errorf("invalid time %q: want YYYY-MM-DD, YYYY-MM-DDTHH:MM, or RFC 3339", value)
+}
+
+// csvHeader names the columns of a CSV export
+var csvHeader = []string{"id", "time", "tool", "document", "spec_version", "valid", "confidence", "critical", "warning", "suggestion", "input_hash", "input_length", "tenant", "findings"}
+
+// writeExport writes runs as a downloadable JSON array or CSV file. Each CSV row
+// lists its findings one per line in the findings column.
+func writeExport(w http.ResponseWriter, format string, runs []Run) error {
+	filename := "validation-history-" + time.Now().UTC().Format("20060102T150405Z") + "." + format
+	w.Header().Set("Content-Disposition", `attachment; filename="`+filename+`"`)
+
+	if format == "json" {
+		if runs == nil {
+			runs = []Run{}
+		}
+		w.Header().Set("Content-Type", "application/json")
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(runs)
+	}
+
+	w.Header().Set("Content-Type", "text/csv; charset=utf-8")
+	writer := csv.NewWriter(w)
+	if err := writer.Write(csvHeader); err != nil {
+		return err
+	}
+	for _, run := range runs {
+		findings := make([]string, len(run.Findings))
+		for i, f := range run.Findings {
+			findings[i] = f.Severity + ": " + f.Message
+		}
+		err := writer.Write([]string{
+			run.ID,
+			run.Time.Format(time.RFC3339),
+			run.Tool,
+			run.Document,
+			run.SpecVersion,
+			strconv.FormatBool(run.Valid),
+			strconv.FormatFloat(run.Confidence, 'f', 4, 64),
+			strconv.Itoa(run.Counts.Critical),
+			strconv.Itoa(run.Counts.Warning),
+			strconv.Itoa(run.Counts.Suggestion),
+			run.InputHash,
+			strconv.Itoa(run.InputLength),
+			run.Tenant,
+			strings.Join(findings, "\n"),
+		})
+		if err != nil {
+			return err
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
@@ -0,0 +1,261 @@
+// Package history records every validation the server runs, so users can see whether a
+// document is improving over time and compare one run with another.
+package history
+
+import (
+	"bufio"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/carlisia/mcp-factcheck/internal/config"
+	"github.com/carlisia/mcp-factcheck/pkg/validator"
+)
+
+// Run is one recorded validation. The validated text itself is not stored, only its hash.
+type Run struct {
+	ID          string                      `json:"id"`
+	Time        time.Time                   `json:"time"`
+	Tool        string                      `json:"tool"`
+	Document    string                      `json:"document,omitempty"` // URL, file path, or caller-given name
+	InputHash   string                      `json:"input_hash"`         // SHA-256 of the validated text
+	InputLength int                         `json:"input_length"`
+	SpecVersion string                      `json:"spec_version"`
+	Valid       bool                        `json:"valid"`
+	Confidence  float64                     `json:"confidence"`
+	Counts      Counts                      `json:"counts"`
+	Findings    []validator.ValidationError `json:"findings,omitempty"`
+	Tenant      string                      `json:"tenant,omitempty"`
+}
+
+// Counts is the number of findings at each severity
+type Counts struct {
+	Critical   int `json:"critical"`
+	Warning    int `json:"warning"`
+	Suggestion int `json:"suggestion"`
+}
+
+// NewRun builds a run from a validation outcome
+func NewRun(tool, tenant string, outcome validator.Outcome) Run {
+	sum := sha256.Sum256([]byte(outcome.Content))
+	run := Run{
+		Tool:        tool,
+		Document:    outcome.Document,
+		InputHash:   hex.EncodeToString(sum[:]),
+		InputLength: len(outcome.Content),
+		SpecVersion: outcome.SpecVersion,
+		Valid:       outcome.Valid,
+		Confidence:  outcome.Confidence,
+		Findings:    outcome.Findings,
+		Tenant:      tenant,
+	}
+	for _, f := range outcome.Findings {
+		switch f.Severity {
+		case validator.SeverityCritical:
+			run.Counts.Critical++
+		case validator.SeverityWarning:
+			run.Counts.Warning++
+		default:
+			run.Counts.Suggestion++
+		}
+	}
+	return run
+}
+
+// DefaultPath returns the history file under the state directory
+func DefaultPath() string {
+	return filepath.Join(config.StateDir(), "history.jsonl")
+}
+
+// Store appends runs to a JSON Lines file
+type Store struct {
+	path string
+	mu   sync.Mutex
+}
+
+// NewStore creates a store backed by path. The file is created on the first Append.
+func NewStore(path string) *Store {
+	return &Store{path: path}
+}
+
+// Path returns the file backing the store
+func (s *Store) Path() string {
+	return s.path
+}
+
+// Append records a run, filling in its ID and time
+func (s *Store) Append(run Run) (Run, error) {
+	if run.ID == "" {
+		run.ID = newID()
+	}
+	if run.Time.IsZero() {
+		run.Time = time.Now().UTC()
+	}
+
+	line, err := json.Marshal(run)
+	if err != nil {
+		return Run{}, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := os.MkdirAll(filepath.Dir(s.path), 0o755); err != nil {
+		return Run{}, fmt.Errorf("failed to create history directory: %w", err)
+	}
+	f, err := os.OpenFile(s.path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
+	if err != nil {
+		return Run{}, fmt.Errorf("failed to open history file: %w", err)
+	}
+	defer f.Close()
+	if _, err := f.Write(append(line, '\n')); err != nil {
+		return Run{}, fmt.Errorf("failed to write history: %w", err)
+	}
+	return run, nil
+}
+
+// All reads every stored run, oldest first. A missing file means no history yet.
+func (s *Store) All() ([]Run, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	f, err := os.Open(s.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to open history file: %w", err)
+	}
+	defer f.Close()
+
+	var runs []Run
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), 16<<20)
+	for lineNo := 1; scanner.Scan(); lineNo++ {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+		var run Run
+		if err := json.Unmarshal(scanner.Bytes(), &run); err != nil {
+			return nil, fmt.Errorf("%s:%d: %w", s.path, lineNo, err)
+		}
+		runs = append(runs, run)
+	}
+	return runs, scanner.Err()
+}
+
+// Filter selects runs. Zero fields match everything.
+type Filter struct {
+	Document  string
+	InputHash string
+	Tool      string
+	Tenant    string // Runs recorded for other tenants are excluded when set
+	Valid     *bool  // Only runs with this result
+	Text      string // Only runs whose document, tool, ID, or findings contain it, ignoring case
+	Since     time.Time
+	Until     time.Time // Exclusive
+	Limit     int
+}
+
+func (f Filter) matches(run Run) bool {
+	return (f.Document == "" || run.Document == f.Document) &&
+		(f.InputHash == "" || run.InputHash == f.InputHash) &&
+		(f.Tool == "" || run.Tool == f.Tool) &&
+		(f.Tenant == "" || run.Tenant == f.Tenant) &&
+		(f.Valid == nil || run.Valid == *f.Valid) &&
+		(f.Since.IsZero() || !run.Time.Before(f.Since)) &&
+		(f.Until.IsZero() || run.Time.Before(f.Until)) &&
+		(f.Text == "" || run.contains(f.Text))
+}
+
+// contains reports whether text appears in the run's recorded fields, ignoring case.
+// The validated text itself isn't stored, so it can't be searched.
+func (run Run) contains(text string) bool {
+	fields := []string{run.ID, run.Tool, run.Document, run.SpecVersion, run.InputHash}
+	for _, f := range run.Findings {
+		fields = append(fields, f.Type, f.Message, f.Found, f.Expected, f.SpecSection)
+		fields = append(fields, f.Suggestions...)
+	}
+	text = strings.ToLower(text)
+	for _, field := range fields {
+		if strings.Contains(strings.ToLower(field), text) {
+			return true
+		}
+	}
+	return false
+}
+
+// Query returns the runs matching filter, newest first
+func (s *Store) Query(filter Filter) ([]Run, error) {
+	all, err := s.All()
+	if err != nil {
+		return nil, err
+	}
+	var runs []Run
+	for i := len(all) - 1; i >= 0; i-- {
+		if filter.matches(all[i]) {
+			runs = append(runs, all[i])
+			if filter.Limit > 0 && len(runs) == filter.Limit {
+				break
+			}
+		}
+	}
+	return runs, nil
+}
+
+// Get returns the run with id, visible to tenant ("" sees every run)
+func (s *Store) Get(id, tenant string) (Run, error) {
+	all, err := s.All()
+	if err != nil {
+		return Run{}, err
+	}
+	for _, run := range all {
+		if run.ID == id && (tenant == "" || run.Tenant == tenant) {
+			return run, nil
+		}
+	}
+	return Run{}, fmt.Errorf("no validation run with id %s", id)
+}
+
+// Documents returns the names of the documents with recorded runs, sorted
+func Documents(runs []Run) []string {
+	seen := map[string]bool{}
+	var docs []string
+	for _, run := range runs {
+		if run.Document != "" && !seen[run.Document] {
+			seen[run.Document] = true
+			docs = append(docs, run.Document)
+		}
+	}
+	sort.Strings(docs)
+	return docs
+}
+
+// Tools returns the names of the tools with recorded runs, sorted
+func Tools(runs []Run) []string {
+	seen := map[string]bool{}
+	var tools []string
+	for _, run := range runs {
+		if !seen[run.Tool] {
+			seen[run.Tool] = true
+			tools = append(tools, run.Tool)
+		}
+	}
+	sort.Strings(tools)
+	return tools
+}
+
+func newID() string {
+	b := make([]byte, 8)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
@@ -0,0 +1,133 @@
+package history
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/carlisia/mcp-factcheck/internal/tenant"
+	"github.com/carlisia/mcp-factcheck/internal/toolerr"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+const GetValidationHistoryToolName = "get_validation_history"
+
+// Limits on how many runs get_validation_history lists
+const (
+	defaultLimit = 10
+	maxLimit     = 100
+)
+
+func GetValidationHistoryTool() mcp.Tool {
+	schema := map[string]any{
+		"type": "object",
+		"properties": map[string]any{
+			"document": map[string]any{
+				"type":        "string",
+				"description": "Only list runs of this document: the URL given to validate_url, the path given to validate_file, or the document name given to validate_content. Omit to list recent runs and the documents that have history",
+			},
+			"tool": map[string]any{
+				"type":        "string",
+				"description": "Only list runs of this tool, e.g. validate_content",
+			},
+			"inputHash": map[string]any{
+				"type":        "string",
+				"description": "Only list runs of exactly this text, by the input_hash reported with each run",
+			},
+			"limit": map[string]any{
+				"type":        "integer",
+				"description": fmt.Sprintf("Maximum number of runs to list, newest first (default %d, at most %d)", defaultLimit, maxLimit),
+				"minimum":     1,
+				"maximum":     maxLimit,
+			},
+			"fromRun": map[string]any{
+				"type":        "string",
+				"description": "ID of the earlier run to diff. With toRun, reports which findings were added and resolved between the two runs",
+			},
+			"toRun": map[string]any{
+				"type":        "string",
+				"description": "ID of the later run to diff",
+			},
+		},
+	}
+	schemaBytes, _ := json.Marshal(schema)
+	return mcp.NewToolWithRawSchema(GetValidationHistoryToolName, "List past fact-check runs and how their findings changed. Use this when users ask whether their docs are improving, what changed since the last check, or to compare two validation runs. With a document and at least two runs, the two most recent runs are diffed automatically.", schemaBytes)
+}
+
+// historyResponse is the get_validation_history result
+type historyResponse struct {
+	Runs      []Run    `json:"runs"`
+	Documents []string `json:"documents,omitempty"`
+	Diff      *Diff    `json:"diff,omitempty"`
+}
+
+func HandleGetValidationHistory(ctx context.Context, store *Store, args any) ([]mcp.Content, error) {
+	params, ok := args.(map[string]any)
+	if !ok {
+		return nil, toolerr.InvalidArgs("arguments must be a map")
+	}
+	str := func(key string) string {
+		v, _ := params[key].(string)
+		return v
+	}
+
+	var tenantName string
+	if t := tenant.FromContext(ctx); t != nil {
+		tenantName = t.Name
+	}
+
+	limit := defaultLimit
+	if l, ok := params["limit"].(float64); ok {
+		limit = min(max(int(l), 1), maxLimit)
+	}
+	filter := Filter{
+		Document:  str("document"),
+		InputHash: str("inputHash"),
+		Tool:      str("tool"),
+		Tenant:    tenantName,
+		Limit:     limit,
+	}
+	runs, err := store.Query(filter)
+	if err != nil {
+		return nil, err
+	}
+
+	response := historyResponse{Runs: make([]Run, len(runs))}
+	for i, run := range runs {
+		// Listings carry counts; findings are reported through diffs
+		run.Findings = nil
+		response.Runs[i] = run
+	}
+
+	fromID, toID := str("fromRun"), str("toRun")
+	switch {
+	case fromID != "" || toID != "":
+		if fromID == "" || toID == "" {
+			return nil, toolerr.InvalidArgs("fromRun and toRun must be given together")
+		}
+		from, err := store.Get(fromID, tenantName)
+		if err != nil {
+			return nil, err
+		}
+		to, err := store.Get(toID, tenantName)
+		if err != nil {
+			return nil, err
+		}
+		diff := Compare(from, to)
+		response.Diff = &diff
+	case filter.Document != "" && len(runs) >= 2:
+		diff := Compare(runs[1], runs[0])
+		response.Diff = &diff
+	}
+
+	if filter.Document == "" {
+		all, err := store.Query(Filter{Tenant: tenantName})
+		if err != nil {
+			return nil, err
+		}
+		response.Documents = Documents(all)
+	}
+
+	jsonBytes, _ := json.MarshalIndent(response, "", "  ")
+	return []mcp.Content{mcp.NewTextContent(string(jsonBytes))}, nil
+}
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"time"
 
+	"github.com/carlisia/mcp-factcheck/internal/cost"
 	"github.com/carlisia/mcp-factcheck/pkg/telemetry"
 	"go.opentelemetry.io/otel/attribute"
 )
@@ -71,9 +72,20 @@ func (m *Middleware) WrapToolHandler(toolName string, handler telemetry.ToolHand
 			attribute.String("output.mime_type", "application/json"),
 		)
 
+		// Spend of the OpenAI requests the call made, when the server accounts for it
+		if call := cost.FromContext(ctx); call != nil && m.config.EnableCostTracking {
+			usage := call.Usage()
+			span.SetAttributes(
+				attribute.Float64("llm.cost.total", usage.USD),
+				attribute.Int64("cost.openai_requests", usage.Requests),
+				attribute.Int64("llm.token_count.prompt", usage.PromptTokens),
+				attribute.Int64("llm.token_count.completion", usage.CompletionTokens),
+			)
+		}
+
 		if err != nil {
 			span.SetAttributes(attribute.String("tool.error", err.Error()))
-			span.RecordError(err)
+			telemetry.RecordError(span, err)
 		}
 
 		return result, err
@@ -0,0 +1,49 @@
+package interactions
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/carlisia/mcp-factcheck/internal/tenant"
+)
+
+// NewHandler serves recorded interactions as JSON, newest first, a page at a time.
+// Interactions are filtered by tool; limit sets the page size and cursor, the
+// next_cursor of the previous page, continues from it. Requests carrying a tenant only
+// see that tenant's interactions.
+func NewHandler(store *Store) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		q := Query{
+			Tool:   query.Get("tool"),
+			Cursor: query.Get("cursor"),
+		}
+		if t := tenant.FromContext(r.Context()); t != nil {
+			q.Tenant = t.Name
+		}
+		if limit := query.Get("limit"); limit != "" {
+			n, err := strconv.Atoi(limit)
+			if err != nil || n <= 0 {
+				http.Error(w, "limit must be a positive integer", http.StatusBadRequest)
+				return
+			}
+			q.Limit = n
+		}
+
+		page, err := store.List(q)
+		if errors.Is(err, ErrUnknownCursor) {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		_ = encoder.Encode(page)
+	})
+}
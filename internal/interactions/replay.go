@@ -0,0 +1,292 @@
+package interactions
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"net/http"
+	"strings"
+
+	"github.com/carlisia/mcp-factcheck/internal/tenant"
+)
+
+// Replayer re-sends an interaction's arguments to the live tool and returns the new call
+type Replayer func(ctx context.Context, in Interaction) (Interaction, error)
+
+type observerKey struct{}
+
+// WithObserver makes the calls recorded with ctx report the recorded interaction to
+// observe, so a replay can show the call it made
+func WithObserver(ctx context.Context, observe func(Interaction)) context.Context {
+	return context.WithValue(ctx, observerKey{}, observe)
+}
+
+// Observe passes a recorded interaction to the observer in ctx, if any
+func Observe(ctx context.Context, in Interaction) {
+	if observe, _ := ctx.Value(observerKey{}).(func(Interaction)); observe != nil {
+		observe(in)
+	}
+}
+
+// Get returns the interaction with the given ID. A non-empty tenant only finds its own.
+func (s *Store) Get(id, tenant string) (Interaction, error) {
+	all, err := s.All()
+	if err != nil {
+		return Interaction{}, err
+	}
+	for _, in := range all {
+		if in.ID == id && (tenant == "" || in.Tenant == tenant) {
+			return in, nil
+		}
+	}
+	return Interaction{}, fmt.Errorf("no interaction with id %s", id)
+}
+
+// Comparison is an interaction and the result of sending its arguments again
+type Comparison struct {
+	Original Interaction `json:"original"`
+	Replay   Interaction `json:"replay"`
+	// Changed reports whether the response text or error differs
+	Changed bool      `json:"changed"`
+	Diff    []DiffRow `json:"diff"`
+}
+
+// DiffRow is one line of a side-by-side diff of two responses
+type DiffRow struct {
+	Kind string `json:"kind"` // "same", "changed", "removed", or "added"
+	Old  string `json:"old,omitempty"`
+	New  string `json:"new,omitempty"`
+}
+
+// maxDiffCells bounds the alignment table; larger responses are shown as wholly changed
+const maxDiffCells = 4_000_000
+
+var replayTemplate = template.Must(template.New("replay").Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>Replay {{.Original.Tool}} - {{.Original.ID}}</title>
+<style>
+body { font-family: system-ui, sans-serif; margin: 2em; }
+table { border-collapse: collapse; width: 100%; table-layout: fixed; }
+th, td { border-bottom: 1px solid #eee; padding: 2px 8px; text-align: left; vertical-align: top; }
+td { font-family: ui-monospace, monospace; font-size: 85%; white-space: pre-wrap; word-break: break-word; }
+.removed, .changed .old { background: #fde8e8; } .added, .changed .new { background: #e6f4ea; }
+.invalid { color: #b42318; }
+pre { background: #f6f8fa; padding: 8px; overflow-x: auto; }
+</style>
+</head>
+<body>
+<h1>Replay <code>{{.Original.Tool}}</code></h1>
+<p>Recorded {{.Original.Time.Format "2006-01-02 15:04:05"}} UTC{{with .Original.SpecVersion}}, MCP {{.}}{{end}}{{with .Original.Tenant}}, tenant {{.}}{{end}}, {{.Original.DurationMs}} ms. ID <code>{{.Original.ID}}</code></p>
+<h2>Arguments</h2>
+<pre>{{.Arguments}}</pre>
+<form method="post">
+<input type="hidden" name="id" value="{{.Original.ID}}">
+<button type="submit">Replay against the live server</button>
+</form>
+{{if .Replayed}}
+<h2>{{if .Changed}}Response changed{{else}}Response unchanged{{end}}</h2>
+<p>Replayed in {{.Replay.DurationMs}} ms{{with .Replay.SpecVersion}}, MCP {{.}}{{end}}, and recorded as interaction <code>{{.Replay.ID}}</code>.</p>
+<table>
+<tr><th>Recorded</th><th>Replay</th></tr>
+{{range .Diff}}<tr class="{{.Kind}}"><td class="old">{{.Old}}</td><td class="new">{{.New}}</td></tr>
+{{end}}</table>
+{{else}}
+<h2>Recorded response</h2>
+{{with .Original.Error}}<p class="invalid">{{.}}</p>{{end}}
+<pre>{{.Response}}</pre>
+{{end}}
+</body>
+</html>
+`))
+
+// NewReplayPage serves a page showing the interaction named by the id parameter.
+// Posting the page replays the interaction with replay and shows the recorded and new
+// responses side by side; with format=json the comparison is returned as JSON instead.
+// Requests carrying a tenant can only see and replay that tenant's interactions.
+func NewReplayPage(store *Store, replay Replayer) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet && r.Method != http.MethodPost {
+			w.Header().Set("Allow", "GET, POST")
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		var tenantName string
+		if t := tenant.FromContext(r.Context()); t != nil {
+			tenantName = t.Name
+		}
+		original, err := store.Get(r.FormValue("id"), tenantName)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		}
+
+		data := struct {
+			Comparison
+			Replayed  bool
+			Arguments string
+			Response  string
+		}{
+			Comparison: Comparison{Original: original},
+			Arguments:  indent(original.Arguments),
+			Response:   responseText(original.Response),
+		}
+		if r.Method == http.MethodPost {
+			replayed, err := replay(r.Context(), original)
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusBadGateway)
+				return
+			}
+			data.Comparison = Compare(original, replayed)
+			data.Replayed = true
+		}
+
+		if r.FormValue("format") == "json" {
+			w.Header().Set("Content-Type", "application/json")
+			encoder := json.NewEncoder(w)
+			encoder.SetIndent("", "  ")
+			_ = encoder.Encode(data.Comparison)
+			return
+		}
+		var page bytes.Buffer
+		if err := replayTemplate.Execute(&page, data); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		_, _ = page.WriteTo(w)
+	})
+}
+
+// Compare diffs the text of two interactions' responses, with any error on its first line
+func Compare(original, replay Interaction) Comparison {
+	before, after := displayText(original), displayText(replay)
+	return Comparison{
+		Original: original,
+		Replay:   replay,
+		Changed:  before != after,
+		Diff:     diffRows(strings.Split(before, "\n"), strings.Split(after, "\n")),
+	}
+}
+
+func displayText(in Interaction) string {
+	text := responseText(in.Response)
+	if in.Error != "" {
+		text = strings.TrimSuffix("error: "+in.Error+"\n"+text, "\n")
+	}
+	return text
+}
+
+// responseText returns the text of a recorded response: the text of its content
+// items when it is tool content, or indented JSON otherwise
+func responseText(response json.RawMessage) string {
+	if len(response) == 0 || string(response) == "null" {
+		return ""
+	}
+	var content []map[string]any
+	if json.Unmarshal(response, &content) != nil {
+		var result struct {
+			Content []map[string]any `json:"content"`
+		}
+		if json.Unmarshal(response, &result) != nil || result.Content == nil {
+			return indent(response)
+		}
+		content = result.Content
+	}
+	var parts []string
+	for _, item := range content {
+		if text, ok := item["text"].(string); ok {
+			parts = append(parts, text)
+			continue
+		}
+		raw, _ := json.MarshalIndent(item, "", "  ")
+		parts = append(parts, string(raw))
+	}
+	return strings.Join(parts, "\n")
+}
+
+func indent(raw json.RawMessage) string {
+	var out bytes.Buffer
+	if json.Indent(&out, raw, "", "  ") != nil {
+		return string(raw)
+	}
+	return out.String()
+}
+
+// diffRows aligns the lines of a and b by their longest common subsequence and pairs
+// up the lines removed and added between common lines
+func diffRows(a, b []string) []DiffRow {
+	prefix := 0
+	for prefix < len(a) && prefix < len(b) && a[prefix] == b[prefix] {
+		prefix++
+	}
+	suffix := 0
+	for suffix < len(a)-prefix && suffix < len(b)-prefix && a[len(a)-1-suffix] == b[len(b)-1-suffix] {
+		suffix++
+	}
+	var rows []DiffRow
+	for _, line := range a[:prefix] {
+		rows = append(rows, DiffRow{Kind: "same", Old: line, New: line})
+	}
+	x, y := a[prefix:len(a)-suffix], b[prefix:len(b)-suffix]
+
+	var removed, added []string
+	flush := func() {
+		for i := 0; i < len(removed) || i < len(added); i++ {
+			switch {
+			case i < len(removed) && i < len(added):
+				rows = append(rows, DiffRow{Kind: "changed", Old: removed[i], New: added[i]})
+			case i < len(removed):
+				rows = append(rows, DiffRow{Kind: "removed", Old: removed[i]})
+			default:
+				rows = append(rows, DiffRow{Kind: "added", New: added[i]})
+			}
+		}
+		removed, added = nil, nil
+	}
+
+	if (len(x)+1)*(len(y)+1) > maxDiffCells {
+		removed, added = x, y
+	} else {
+		lcs := make([][]int32, len(x)+1)
+		for i := range lcs {
+			lcs[i] = make([]int32, len(y)+1)
+		}
+		for i := len(x) - 1; i >= 0; i-- {
+			for j := len(y) - 1; j >= 0; j-- {
+				if x[i] == y[j] {
+					lcs[i][j] = lcs[i+1][j+1] + 1
+				} else {
+					lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+				}
+			}
+		}
+		i, j := 0, 0
+		for i < len(x) && j < len(y) {
+			switch {
+			case x[i] == y[j]:
+				flush()
+				rows = append(rows, DiffRow{Kind: "same", Old: x[i], New: y[j]})
+				i++
+				j++
+			case lcs[i+1][j] >= lcs[i][j+1]:
+				removed = append(removed, x[i])
+				i++
+			default:
+				added = append(added, y[j])
+				j++
+			}
+		}
+		removed = append(removed, x[i:]...)
+		added = append(added, y[j:]...)
+	}
+	flush()
+
+	for _, line := range a[len(a)-suffix:] {
+		rows = append(rows, DiffRow{Kind: "same", Old: line, New: line})
+	}
+	return rows
+}
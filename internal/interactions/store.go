@@ -0,0 +1,279 @@
+// Package interactions records every tool call the server handles, with its full
+// arguments and response, so a day of testing can be browsed afterwards and turned
+// into an eval dataset (factcheck eval export). Unlike the validation history, it keeps
+// the text that was validated, so recording is off unless a file is configured.
+package interactions
+
+import (
+	"bufio"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/carlisia/mcp-factcheck/internal/config"
+)
+
+// Interaction is a recorded tool call
+type Interaction struct {
+	ID          string          `json:"id"`
+	Time        time.Time       `json:"time"`
+	Tool        string          `json:"tool"`
+	Arguments   json.RawMessage `json:"arguments"`
+	Response    json.RawMessage `json:"response,omitempty"`
+	Error       string          `json:"error,omitempty"`
+	DurationMs  int64           `json:"duration_ms,omitempty"`
+	SpecVersion string          `json:"spec_version,omitempty"`
+	Tenant      string          `json:"tenant,omitempty"`
+}
+
+// DefaultPath returns the interactions file under the state directory
+func DefaultPath() string {
+	return filepath.Join(config.StateDir(), "interactions.jsonl")
+}
+
+// Retention bounds what a store keeps. Zero fields keep everything.
+type Retention struct {
+	MaxAge     time.Duration // Interactions older than this are dropped
+	MaxEntries int           // Only the newest this many are kept
+}
+
+// Default retention keeps a day of testing
+const (
+	DefaultMaxAge     = 24 * time.Hour
+	DefaultMaxEntries = 10000
+)
+
+// pruneEvery is how many appends go by between retention passes, which rewrite the file
+const pruneEvery = 100
+
+// Store appends interactions to a JSON Lines file and prunes it to its retention
+type Store struct {
+	path      string
+	retention Retention
+
+	mu      sync.Mutex
+	appends int
+}
+
+// NewStore creates a store backed by path. The file is created on the first Append.
+func NewStore(path string, retention Retention) *Store {
+	return &Store{path: path, retention: retention}
+}
+
+// Path returns the file backing the store
+func (s *Store) Path() string {
+	return s.path
+}
+
+// Append records an interaction, filling in its ID and time. Retention is applied every
+// pruneEvery appends, so the file can briefly outgrow it; call Prune on startup too.
+func (s *Store) Append(in Interaction) (Interaction, error) {
+	if in.ID == "" {
+		in.ID = newID()
+	}
+	if in.Time.IsZero() {
+		in.Time = time.Now().UTC()
+	}
+	line, err := json.Marshal(in)
+	if err != nil {
+		return Interaction{}, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := os.MkdirAll(filepath.Dir(s.path), 0o755); err != nil {
+		return Interaction{}, fmt.Errorf("failed to create interactions directory: %w", err)
+	}
+	// The file holds the text users validated, so only they can read it
+	f, err := os.OpenFile(s.path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o600)
+	if err != nil {
+		return Interaction{}, fmt.Errorf("failed to open interactions file: %w", err)
+	}
+	_, err = f.Write(append(line, '\n'))
+	f.Close()
+	if err != nil {
+		return Interaction{}, fmt.Errorf("failed to write interaction: %w", err)
+	}
+
+	s.appends++
+	if s.appends%pruneEvery == 0 {
+		if err := s.pruneLocked(time.Now()); err != nil {
+			return in, err
+		}
+	}
+	return in, nil
+}
+
+// All reads every stored interaction, oldest first. A missing file means none yet.
+func (s *Store) All() ([]Interaction, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.readLocked()
+}
+
+func (s *Store) readLocked() ([]Interaction, error) {
+	f, err := os.Open(s.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to open interactions file: %w", err)
+	}
+	defer f.Close()
+
+	var all []Interaction
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), 16<<20)
+	for lineNo := 1; scanner.Scan(); lineNo++ {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+		var in Interaction
+		if err := json.Unmarshal(scanner.Bytes(), &in); err != nil {
+			return nil, fmt.Errorf("%s:%d: %w", s.path, lineNo, err)
+		}
+		all = append(all, in)
+	}
+	return all, scanner.Err()
+}
+
+// Prune drops the interactions retention no longer keeps
+func (s *Store) Prune() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.pruneLocked(time.Now())
+}
+
+// pruneLocked rewrites the file without the interactions outside retention, if there
+// are any. The new file replaces the old one whole, so a crash loses nothing.
+func (s *Store) pruneLocked(now time.Time) error {
+	if s.retention == (Retention{}) {
+		return nil
+	}
+	all, err := s.readLocked()
+	if err != nil {
+		return err
+	}
+	kept := all
+	if s.retention.MaxAge > 0 {
+		cutoff := now.Add(-s.retention.MaxAge)
+		for len(kept) > 0 && kept[0].Time.Before(cutoff) {
+			kept = kept[1:]
+		}
+	}
+	if s.retention.MaxEntries > 0 && len(kept) > s.retention.MaxEntries {
+		kept = kept[len(kept)-s.retention.MaxEntries:]
+	}
+	if len(kept) == len(all) {
+		return nil
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(s.path), filepath.Base(s.path)+".*")
+	if err != nil {
+		return fmt.Errorf("failed to prune interactions: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+	writer := bufio.NewWriter(tmp)
+	encoder := json.NewEncoder(writer)
+	for _, in := range kept {
+		if err := encoder.Encode(in); err != nil {
+			tmp.Close()
+			return fmt.Errorf("failed to prune interactions: %w", err)
+		}
+	}
+	if err := writer.Flush(); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to prune interactions: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to prune interactions: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), s.path); err != nil {
+		return fmt.Errorf("failed to prune interactions: %w", err)
+	}
+	return nil
+}
+
+// Query selects a page of interactions. Zero fields match everything.
+type Query struct {
+	Tool   string
+	Tenant string // Interactions recorded for other tenants are excluded when set
+	// Cursor continues from the NextCursor of an earlier page
+	Cursor string
+	Limit  int // Page size; zero is DefaultPageSize
+}
+
+// Page sizes
+const (
+	DefaultPageSize = 50
+	MaxPageSize     = 500
+)
+
+// Page is one page of interactions, newest first
+type Page struct {
+	Interactions []Interaction `json:"interactions"`
+	// NextCursor, when set, fetches the next, older page
+	NextCursor string `json:"next_cursor,omitempty"`
+}
+
+// ErrUnknownCursor is returned for a cursor naming an interaction the store no
+// longer has, such as one retention dropped
+var ErrUnknownCursor = errors.New("unknown cursor")
+
+// List returns a page of the interactions matching q, newest first. A cursor is the
+// ID of the last interaction of the previous page, so pages stay stable as new
+// interactions are recorded.
+func (s *Store) List(q Query) (Page, error) {
+	limit := q.Limit
+	if limit <= 0 {
+		limit = DefaultPageSize
+	}
+	if limit > MaxPageSize {
+		limit = MaxPageSize
+	}
+	all, err := s.All()
+	if err != nil {
+		return Page{}, err
+	}
+
+	start := len(all) - 1
+	if q.Cursor != "" {
+		start = -2
+		for i := len(all) - 1; i >= 0; i-- {
+			if all[i].ID == q.Cursor {
+				start = i - 1
+				break
+			}
+		}
+		if start == -2 {
+			return Page{}, fmt.Errorf("%w %q: the interaction may have been pruned", ErrUnknownCursor, q.Cursor)
+		}
+	}
+
+	page := Page{Interactions: []Interaction{}}
+	for i := start; i >= 0; i-- {
+		in := all[i]
+		if (q.Tool != "" && in.Tool != q.Tool) || (q.Tenant != "" && in.Tenant != q.Tenant) {
+			continue
+		}
+		if len(page.Interactions) == limit {
+			page.NextCursor = page.Interactions[limit-1].ID
+			break
+		}
+		page.Interactions = append(page.Interactions, in)
+	}
+	return page, nil
+}
+
+func newID() string {
+	b := make([]byte, 8)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
@@ -0,0 +1,238 @@
+// Package jsonrpc encodes JSON-RPC 2.0 messages and frames them on byte streams, for
+// the clients and servers in this repository that speak JSON-RPC themselves rather
+// than through mcp-go.
+//
+// Message covers requests, notifications, and responses; Decode reads single
+// messages and batches; Calls correlates responses with pending requests by ID. MCP's
+// stdio transport sends one message per line; the LSP base protocol, which some MCP
+// servers speak too, sends a Content-Length header before each message. Reader
+// handles either, or detects which, and bounds message size without the line limit of
+// bufio.Scanner.
+package jsonrpc
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/textproto"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Framing is how messages are delimited on a stream
+type Framing int
+
+const (
+	// FramingAuto reads either framing, detected per message, and writes newlines
+	FramingAuto Framing = iota
+	// FramingNewline puts one message per line, as MCP's stdio transport does. A
+	// message spanning lines, such as pretty-printed JSON, is read until it is complete.
+	FramingNewline
+	// FramingHeader precedes each message with a Content-Length header and a blank
+	// line, as the LSP base protocol does
+	FramingHeader
+)
+
+// ParseFraming returns the framing named auto, newline, or header
+func ParseFraming(name string) (Framing, error) {
+	switch name {
+	case "auto", "":
+		return FramingAuto, nil
+	case "newline":
+		return FramingNewline, nil
+	case "header":
+		return FramingHeader, nil
+	}
+	return 0, fmt.Errorf("unknown framing %q: want auto, newline, or header", name)
+}
+
+func (f Framing) String() string {
+	switch f {
+	case FramingNewline:
+		return "newline"
+	case FramingHeader:
+		return "header"
+	default:
+		return "auto"
+	}
+}
+
+// DefaultMaxMessageSize bounds messages when no other limit is set; whole spec
+// sections can be long
+const DefaultMaxMessageSize = 16 * 1024 * 1024
+
+// ErrTooLarge is returned for a message over the reader's size limit. The message is
+// skipped, so reading can go on with the next one.
+var ErrTooLarge = errors.New("message too large")
+
+// headerPrefix starts a message with header framing
+const headerPrefix = "content-length:"
+
+// Reader reads framed messages from a stream
+type Reader struct {
+	r       *bufio.Reader
+	framing Framing
+	maxSize int
+	last    Framing // Framing of the last message read
+}
+
+// NewReader returns a reader of messages framed as framing, of at most maxSize bytes,
+// or DefaultMaxMessageSize when maxSize isn't positive
+func NewReader(r io.Reader, framing Framing, maxSize int) *Reader {
+	if maxSize <= 0 {
+		maxSize = DefaultMaxMessageSize
+	}
+	return &Reader{r: bufio.NewReader(r), framing: framing, maxSize: maxSize}
+}
+
+// Read returns the next message. It returns io.EOF at the end of the stream, and
+// ErrTooLarge, wrapped, for a message over the size limit, which it skips.
+func (r *Reader) Read() ([]byte, error) {
+	framing := r.framing
+	if framing == FramingAuto {
+		var err error
+		if framing, err = r.detect(); err != nil {
+			return nil, err
+		}
+	}
+	r.last = framing
+	if framing == FramingHeader {
+		return r.readHeader()
+	}
+	return r.readLines()
+}
+
+// Framing returns the framing of the last message read, as detected with FramingAuto
+func (r *Reader) Framing() Framing {
+	return r.last
+}
+
+// detect skips blank space before the next message and returns its framing
+func (r *Reader) detect() (Framing, error) {
+	for {
+		b, err := r.r.Peek(1)
+		if err != nil {
+			return 0, err
+		}
+		if b[0] != ' ' && b[0] != '\t' && b[0] != '\r' && b[0] != '\n' {
+			break
+		}
+		r.r.ReadByte()
+	}
+	// A peek shorter than the prefix means the stream ends first; it can't be a header
+	prefix, _ := r.r.Peek(len(headerPrefix))
+	if strings.EqualFold(string(prefix), headerPrefix) {
+		return FramingHeader, nil
+	}
+	return FramingNewline, nil
+}
+
+// readHeader reads a message after its headers
+func (r *Reader) readHeader() ([]byte, error) {
+	headers, err := textproto.NewReader(r.r).ReadMIMEHeader()
+	if err != nil {
+		return nil, err
+	}
+	length, err := strconv.Atoi(headers.Get("Content-Length"))
+	if err != nil || length < 0 {
+		return nil, fmt.Errorf("invalid Content-Length header: %q", headers.Get("Content-Length"))
+	}
+	if length > r.maxSize {
+		if _, err := r.r.Discard(length); err != nil {
+			return nil, err
+		}
+		return nil, fmt.Errorf("%w: %d bytes, over %d", ErrTooLarge, length, r.maxSize)
+	}
+
+	body := make([]byte, length)
+	if _, err := io.ReadFull(r.r, body); err != nil {
+		return nil, err
+	}
+	return body, nil
+}
+
+// readLines reads lines until they hold a complete JSON value, skipping blank lines
+// before it
+func (r *Reader) readLines() ([]byte, error) {
+	var msg []byte
+	tooLarge := false
+	for {
+		line, err := r.r.ReadSlice('\n')
+		if errors.Is(err, bufio.ErrBufferFull) {
+			err = nil // The line goes on; ReadSlice returns it in pieces
+		}
+		if !tooLarge {
+			msg = append(msg, line...)
+			if len(msg) > r.maxSize {
+				tooLarge, msg = true, nil
+			}
+		}
+		if err != nil {
+			if err == io.EOF && len(bytes.TrimSpace(msg)) > 0 {
+				return bytes.TrimSpace(msg), nil // The last message needn't end with a newline
+			}
+			if err == io.EOF && tooLarge {
+				err = io.ErrUnexpectedEOF
+			}
+			return nil, err
+		}
+		if len(line) == 0 || line[len(line)-1] != '\n' {
+			continue
+		}
+
+		if tooLarge {
+			// Its content dropped, the message is taken to end with the line, as
+			// newline-framed messages do
+			return nil, fmt.Errorf("%w: over %d bytes", ErrTooLarge, r.maxSize)
+		}
+		trimmed := bytes.TrimSpace(msg)
+		if len(trimmed) == 0 {
+			msg = msg[:0]
+			continue
+		}
+		if complete(trimmed) {
+			return trimmed, nil
+		}
+	}
+}
+
+// complete reports whether data holds a whole JSON value, or can't become one with
+// more input: a syntax error is returned to the caller as the message, to fail there
+func complete(data []byte) bool {
+	var v json.RawMessage
+	err := json.NewDecoder(bytes.NewReader(data)).Decode(&v)
+	return !errors.Is(err, io.ErrUnexpectedEOF)
+}
+
+// Writer writes framed messages to a stream. It is safe for concurrent use.
+type Writer struct {
+	mu      sync.Mutex
+	w       io.Writer
+	framing Framing
+}
+
+// NewWriter returns a writer of messages framed as framing, where FramingAuto writes
+// newlines
+func NewWriter(w io.Writer, framing Framing) *Writer {
+	return &Writer{w: w, framing: framing}
+}
+
+// Write writes one message, which must not contain a newline unless framing is
+// FramingHeader
+func (w *Writer) Write(msg []byte) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.framing == FramingHeader {
+		if _, err := fmt.Fprintf(w.w, "Content-Length: %d\r\n\r\n", len(msg)); err != nil {
+			return err
+		}
+		_, err := w.w.Write(msg)
+		return err
+	}
+	_, err := w.w.Write(append(msg[:len(msg):len(msg)], '\n'))
+	return err
+}
@@ -0,0 +1,220 @@
+package jsonrpc
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// result is one Read of a Reader: a message or an error
+type result struct {
+	msg     string
+	framing Framing // Framing the reader reports after the read, for FramingAuto
+	err     string  // Text the error must contain
+}
+
+func TestReader(t *testing.T) {
+	ping := `{"jsonrpc":"2.0","id":1,"method":"ping"}`
+	header := func(msg string) string {
+		return "Content-Length: " + strconv.Itoa(len(msg)) + "\r\n\r\n" + msg
+	}
+
+	tests := []struct {
+		name    string
+		framing Framing
+		maxSize int
+		input   string
+		want    []result // Reads in order
+		end     error    // Error of the read after them, matched with errors.Is; io.EOF when nil
+	}{
+		{
+			name:    "newline",
+			framing: FramingNewline,
+			input:   ping + "\n" + ping + "\n",
+			want:    []result{{msg: ping}, {msg: ping}},
+		},
+		{
+			name:    "newline skips blank lines and trims CRLF",
+			framing: FramingNewline,
+			input:   "\n\r\n" + ping + "\r\n\n",
+			want:    []result{{msg: ping}},
+		},
+		{
+			name:    "newline without a final newline",
+			framing: FramingNewline,
+			input:   ping,
+			want:    []result{{msg: ping}},
+		},
+		{
+			name:    "newline message spanning lines",
+			framing: FramingNewline,
+			input:   "{\n  \"jsonrpc\": \"2.0\",\n  \"method\": \"ping\"\n}\n" + ping + "\n",
+			want:    []result{{msg: "{\n  \"jsonrpc\": \"2.0\",\n  \"method\": \"ping\"\n}"}, {msg: ping}},
+		},
+		{
+			name:    "newline syntax error is returned as the message",
+			framing: FramingNewline,
+			input:   "{oops}\n" + ping + "\n",
+			want:    []result{{msg: "{oops}"}, {msg: ping}},
+		},
+		{
+			name:    "header",
+			framing: FramingHeader,
+			input:   header(ping) + header(`{"a":"b\nc"}`),
+			want:    []result{{msg: ping}, {msg: `{"a":"b\nc"}`}},
+		},
+		{
+			name:    "header with other headers",
+			framing: FramingHeader,
+			input:   "Content-Type: application/vscode-jsonrpc; charset=utf-8\r\ncontent-length: " + strconv.Itoa(len(ping)) + "\r\n\r\n" + ping,
+			want:    []result{{msg: ping}},
+		},
+		{
+			name:    "header with invalid length",
+			framing: FramingHeader,
+			input:   "Content-Length: many\r\n\r\n" + ping,
+			want:    []result{{err: "invalid Content-Length"}, {err: "malformed MIME header"}},
+		},
+		{
+			name:    "header with truncated body",
+			framing: FramingHeader,
+			input:   "Content-Length: 100\r\n\r\n" + ping,
+			end:     io.ErrUnexpectedEOF,
+		},
+		{
+			name:    "auto detects each message",
+			framing: FramingAuto,
+			input:   ping + "\n" + header(ping) + "\r\n" + ping + "\n",
+			want: []result{
+				{msg: ping, framing: FramingNewline},
+				{msg: ping, framing: FramingHeader},
+				{msg: ping, framing: FramingNewline},
+			},
+		},
+		{
+			name:    "auto detects a lowercase header after blank space",
+			framing: FramingAuto,
+			input:   " \r\n\tcontent-length: " + strconv.Itoa(len(ping)) + "\r\n\r\n" + ping,
+			want:    []result{{msg: ping, framing: FramingHeader}},
+		},
+		{
+			name:    "newline over the size limit is skipped",
+			framing: FramingNewline,
+			maxSize: 50,
+			input:   `{"jsonrpc":"2.0","method":"x","params":"` + strings.Repeat("a", 100) + `"}` + "\n" + ping + "\n",
+			want:    []result{{err: ErrTooLarge.Error()}, {msg: ping}},
+		},
+		{
+			name:    "newline over the size limit at the end of the stream",
+			framing: FramingNewline,
+			maxSize: 50,
+			input:   strings.Repeat("a", 100),
+			end:     io.ErrUnexpectedEOF,
+		},
+		{
+			name:    "header over the size limit is skipped",
+			framing: FramingHeader,
+			maxSize: 50,
+			input:   header(strings.Repeat("a", 100)) + header(ping),
+			want:    []result{{err: ErrTooLarge.Error()}, {msg: ping}},
+		},
+		{
+			name:    "message at the size limit",
+			framing: FramingAuto,
+			maxSize: len(ping) + 1,
+			input:   ping + "\n" + header(ping),
+			want:    []result{{msg: ping, framing: FramingNewline}, {msg: ping, framing: FramingHeader}},
+		},
+		{
+			name:    "line longer than the read buffer",
+			framing: FramingNewline,
+			input:   `"` + strings.Repeat("a", 10000) + `"` + "\n",
+			want:    []result{{msg: `"` + strings.Repeat("a", 10000) + `"`}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := NewReader(strings.NewReader(tt.input), tt.framing, tt.maxSize)
+			for i, want := range tt.want {
+				msg, err := r.Read()
+				if want.err != "" {
+					if err == nil || !strings.Contains(err.Error(), want.err) {
+						t.Fatalf("read %d = %q, %v; want error %q", i, msg, err, want.err)
+					}
+					continue
+				}
+				if err != nil {
+					t.Fatalf("read %d: unexpected error %v", i, err)
+				}
+				if string(msg) != want.msg {
+					t.Errorf("read %d = %q, want %q", i, msg, want.msg)
+				}
+				if tt.framing == FramingAuto && r.Framing() != want.framing {
+					t.Errorf("read %d framing = %s, want %s", i, r.Framing(), want.framing)
+				}
+			}
+
+			end := tt.end
+			if end == nil {
+				end = io.EOF
+			}
+			if msg, err := r.Read(); !errors.Is(err, end) {
+				t.Fatalf("final read = %q, %v; want %v", msg, err, end)
+			}
+		})
+	}
+}
+
+func TestWriterRoundTrip(t *testing.T) {
+	msgs := []string{
+		`{"jsonrpc":"2.0","id":1,"method":"ping"}`,
+		`{"jsonrpc":"2.0","id":1,"result":{}}`,
+	}
+	for _, framing := range []Framing{FramingNewline, FramingHeader, FramingAuto} {
+		t.Run(framing.String(), func(t *testing.T) {
+			var buf bytes.Buffer
+			w := NewWriter(&buf, framing)
+			for _, msg := range msgs {
+				if err := w.Write([]byte(msg)); err != nil {
+					t.Fatal(err)
+				}
+			}
+			r := NewReader(&buf, FramingAuto, 0)
+			for _, want := range msgs {
+				got, err := r.Read()
+				if err != nil {
+					t.Fatal(err)
+				}
+				if string(got) != want {
+					t.Errorf("read %q, want %q", got, want)
+				}
+			}
+			if _, err := r.Read(); err != io.EOF {
+				t.Errorf("final read err = %v, want io.EOF", err)
+			}
+		})
+	}
+}
+
+func TestParseFraming(t *testing.T) {
+	tests := []struct {
+		name    string
+		want    Framing
+		wantErr bool
+	}{
+		{name: "", want: FramingAuto},
+		{name: "auto", want: FramingAuto},
+		{name: "newline", want: FramingNewline},
+		{name: "header", want: FramingHeader},
+		{name: "lsp", wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := ParseFraming(tt.name)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("ParseFraming(%q) = %v, %v; want %v, error %v", tt.name, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
@@ -0,0 +1,241 @@
+package jsonrpc
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"sync"
+)
+
+// Version is the JSON-RPC version every message carries
+const Version = "2.0"
+
+// Error codes defined by JSON-RPC 2.0
+const (
+	CodeParseError     = -32700
+	CodeInvalidRequest = -32600
+	CodeMethodNotFound = -32601
+	CodeInvalidParams  = -32602
+	CodeInternalError  = -32603
+)
+
+// ID identifies a request, and the response to it. It holds the ID's JSON, a number
+// or a string, so IDs round-trip as peers sent them and compare as map keys; the zero
+// ID is no ID, as in notifications.
+type ID string
+
+// IntID returns the ID n
+func IntID(n int64) ID {
+	return ID(strconv.FormatInt(n, 10))
+}
+
+// StringID returns the ID s
+func StringID(s string) ID {
+	raw, _ := json.Marshal(s)
+	return ID(raw)
+}
+
+// Int returns the ID as a number, if it is one
+func (id ID) Int() (int64, bool) {
+	n, err := strconv.ParseInt(string(id), 10, 64)
+	return n, err == nil
+}
+
+func (id ID) String() string {
+	var s string
+	if json.Unmarshal([]byte(id), &s) == nil {
+		return s
+	}
+	return string(id)
+}
+
+func (id ID) MarshalJSON() ([]byte, error) {
+	if id == "" {
+		return []byte("null"), nil
+	}
+	return []byte(id), nil
+}
+
+func (id *ID) UnmarshalJSON(data []byte) error {
+	data = bytes.TrimSpace(data)
+	if bytes.Equal(data, []byte("null")) {
+		*id = ""
+		return nil
+	}
+	var v any
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+	switch v.(type) {
+	case string, float64:
+		*id = ID(data)
+		return nil
+	}
+	return fmt.Errorf("invalid JSON-RPC id %s: must be a number or string", data)
+}
+
+// Message is a JSON-RPC 2.0 request, notification, or response. Params and results
+// stay raw until their receiver, which knows their type, decodes them.
+type Message struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      ID              `json:"id,omitempty"`
+	Method  string          `json:"method,omitempty"`
+	Params  json.RawMessage `json:"params,omitempty"`
+	Result  json.RawMessage `json:"result,omitempty"`
+	Error   *Error          `json:"error,omitempty"`
+}
+
+// IsRequest reports whether m is a request, which expects a response
+func (m *Message) IsRequest() bool {
+	return m.Method != "" && m.ID != ""
+}
+
+// IsNotification reports whether m is a notification, which has no response
+func (m *Message) IsNotification() bool {
+	return m.Method != "" && m.ID == ""
+}
+
+// IsResponse reports whether m answers a request
+func (m *Message) IsResponse() bool {
+	return m.Method == ""
+}
+
+// Error is the error of a response. It implements error, so handlers can return one
+// to choose the code their caller answers with.
+type Error struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+	Data    any    `json:"data,omitempty"`
+}
+
+func (e *Error) Error() string {
+	return e.Message
+}
+
+// NewError returns an error with code and a formatted message
+func NewError(code int, format string, args ...any) *Error {
+	return &Error{Code: code, Message: fmt.Sprintf(format, args...)}
+}
+
+// NewRequest returns a request calling method with params, which may be nil
+func NewRequest(id ID, method string, params any) (*Message, error) {
+	raw, err := marshalOptional(params)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal %s params: %w", method, err)
+	}
+	return &Message{JSONRPC: Version, ID: id, Method: method, Params: raw}, nil
+}
+
+// NewNotification returns a notification of method with params, which may be nil
+func NewNotification(method string, params any) (*Message, error) {
+	return NewRequest("", method, params)
+}
+
+// NewResponse returns the response to request id with result. A nil result is sent
+// as null, as a response must carry a result or an error.
+func NewResponse(id ID, result any) (*Message, error) {
+	raw, err := json.Marshal(result)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal result: %w", err)
+	}
+	return &Message{JSONRPC: Version, ID: id, Result: raw}, nil
+}
+
+// NewErrorResponse returns the response to request id failing with err
+func NewErrorResponse(id ID, err *Error) *Message {
+	return &Message{JSONRPC: Version, ID: id, Error: err}
+}
+
+// marshalOptional marshals v, leaving nil out
+func marshalOptional(v any) (json.RawMessage, error) {
+	if v == nil {
+		return nil, nil
+	}
+	if raw, ok := v.(json.RawMessage); ok {
+		return raw, nil
+	}
+	return json.Marshal(v)
+}
+
+// Decode parses a message, or a batch of them, and reports whether it was a batch.
+// A malformed message is a parse error; an empty batch is an invalid request.
+func Decode(data []byte) ([]*Message, bool, error) {
+	data = bytes.TrimSpace(data)
+	if len(data) > 0 && data[0] == '[' {
+		var batch []*Message
+		if err := json.Unmarshal(data, &batch); err != nil {
+			return nil, true, NewError(CodeParseError, "invalid batch: %v", err)
+		}
+		if len(batch) == 0 {
+			return nil, true, NewError(CodeInvalidRequest, "empty batch")
+		}
+		for _, msg := range batch {
+			if msg == nil {
+				return nil, true, NewError(CodeInvalidRequest, "null message in batch")
+			}
+		}
+		return batch, true, nil
+	}
+	var msg Message
+	if err := json.Unmarshal(data, &msg); err != nil {
+		return nil, false, NewError(CodeParseError, "invalid message: %v", err)
+	}
+	return []*Message{&msg}, false, nil
+}
+
+// EncodeBatch marshals messages as a batch, or as a single message when there is one
+func EncodeBatch(msgs []*Message) ([]byte, error) {
+	if len(msgs) == 1 {
+		return json.Marshal(msgs[0])
+	}
+	return json.Marshal(msgs)
+}
+
+// ErrUnknownID is returned for a response to no pending request
+var ErrUnknownID = errors.New("response to no pending request")
+
+// Calls correlates responses with the requests awaiting them. IDs are numbers from 1.
+type Calls struct {
+	mu      sync.Mutex
+	next    int64
+	pending map[ID]chan *Message
+}
+
+// NewCalls returns a correlator with no pending requests
+func NewCalls() *Calls {
+	return &Calls{next: 1, pending: map[ID]chan *Message{}}
+}
+
+// Start returns the ID for a new request and the channel its response will arrive on
+func (c *Calls) Start() (ID, <-chan *Message) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	id := IntID(c.next)
+	c.next++
+	ch := make(chan *Message, 1)
+	c.pending[id] = ch
+	return id, ch
+}
+
+// Deliver hands resp to the request it answers. It returns ErrUnknownID when no
+// request awaits it, as when the request was forgotten after a timeout.
+func (c *Calls) Deliver(resp *Message) error {
+	c.mu.Lock()
+	ch, ok := c.pending[resp.ID]
+	delete(c.pending, resp.ID)
+	c.mu.Unlock()
+	if !ok {
+		return fmt.Errorf("%w: id %s", ErrUnknownID, resp.ID)
+	}
+	ch <- resp
+	return nil
+}
+
+// Forget stops awaiting the response to id
+func (c *Calls) Forget(id ID) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.pending, id)
+}
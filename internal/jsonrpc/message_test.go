@@ -0,0 +1,162 @@
+package jsonrpc
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestDecode(t *testing.T) {
+	tests := []struct {
+		name     string
+		data     string
+		batch    bool
+		ids      []ID
+		methods  []string
+		wantCode int // Error code expected, or 0 for success
+	}{
+		{
+			name:    "request",
+			data:    `{"jsonrpc":"2.0","id":1,"method":"ping"}`,
+			ids:     []ID{"1"},
+			methods: []string{"ping"},
+		},
+		{
+			name:    "string id",
+			data:    `{"jsonrpc":"2.0","id":"a-1","method":"tools/list","params":{}}`,
+			ids:     []ID{`"a-1"`},
+			methods: []string{"tools/list"},
+		},
+		{
+			name:    "notification",
+			data:    `{"jsonrpc":"2.0","method":"notifications/initialized"}`,
+			ids:     []ID{""},
+			methods: []string{"notifications/initialized"},
+		},
+		{
+			name:    "response with surrounding space",
+			data:    "  \n{\"jsonrpc\":\"2.0\",\"id\":2,\"result\":{}}\n",
+			ids:     []ID{"2"},
+			methods: []string{""},
+		},
+		{
+			name:    "batch",
+			data:    `[{"jsonrpc":"2.0","id":1,"method":"ping"},{"jsonrpc":"2.0","method":"notifications/initialized"}]`,
+			batch:   true,
+			ids:     []ID{"1", ""},
+			methods: []string{"ping", "notifications/initialized"},
+		},
+		{
+			name:    "batch of one",
+			data:    ` [{"jsonrpc":"2.0","id":"x","result":null}]`,
+			batch:   true,
+			ids:     []ID{`"x"`},
+			methods: []string{""},
+		},
+		{name: "malformed message", data: `{"jsonrpc":"2.0",`, wantCode: CodeParseError},
+		{name: "invalid id", data: `{"jsonrpc":"2.0","id":{},"method":"ping"}`, wantCode: CodeParseError},
+		{name: "malformed batch", data: `[{"jsonrpc":"2.0"},`, batch: true, wantCode: CodeParseError},
+		{name: "empty batch", data: `[]`, batch: true, wantCode: CodeInvalidRequest},
+		{name: "null in batch", data: `[{"jsonrpc":"2.0","id":1,"method":"ping"},null]`, batch: true, wantCode: CodeInvalidRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			msgs, batch, err := Decode([]byte(tt.data))
+			if batch != tt.batch {
+				t.Errorf("batch = %v, want %v", batch, tt.batch)
+			}
+			if tt.wantCode != 0 {
+				var rpcErr *Error
+				if !errors.As(err, &rpcErr) || rpcErr.Code != tt.wantCode {
+					t.Fatalf("err = %v, want code %d", err, tt.wantCode)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if len(msgs) != len(tt.ids) {
+				t.Fatalf("got %d messages, want %d", len(msgs), len(tt.ids))
+			}
+			for i, msg := range msgs {
+				if msg.ID != tt.ids[i] || msg.Method != tt.methods[i] {
+					t.Errorf("message %d = id %q method %q, want id %q method %q", i, msg.ID, msg.Method, tt.ids[i], tt.methods[i])
+				}
+			}
+		})
+	}
+}
+
+func TestMessageKinds(t *testing.T) {
+	tests := []struct {
+		data                              string
+		request, notification, isResponse bool
+	}{
+		{data: `{"jsonrpc":"2.0","id":1,"method":"ping"}`, request: true},
+		{data: `{"jsonrpc":"2.0","id":null,"method":"ping"}`, notification: true},
+		{data: `{"jsonrpc":"2.0","method":"notifications/progress"}`, notification: true},
+		{data: `{"jsonrpc":"2.0","id":1,"result":{}}`, isResponse: true},
+		{data: `{"jsonrpc":"2.0","id":1,"error":{"code":-32601,"message":"no"}}`, isResponse: true},
+	}
+	for _, tt := range tests {
+		msgs, _, err := Decode([]byte(tt.data))
+		if err != nil {
+			t.Fatalf("Decode(%s): %v", tt.data, err)
+		}
+		msg := msgs[0]
+		if msg.IsRequest() != tt.request || msg.IsNotification() != tt.notification || msg.IsResponse() != tt.isResponse {
+			t.Errorf("%s: request %v, notification %v, response %v", tt.data, msg.IsRequest(), msg.IsNotification(), msg.IsResponse())
+		}
+	}
+}
+
+func TestCalls(t *testing.T) {
+	calls := NewCalls()
+	first, firstCh := calls.Start()
+	second, secondCh := calls.Start()
+	forgotten, _ := calls.Start()
+	if first != IntID(1) || second != IntID(2) || forgotten != IntID(3) {
+		t.Fatalf("ids = %s, %s, %s; want 1, 2, 3", first, second, forgotten)
+	}
+	calls.Forget(forgotten)
+
+	tests := []struct {
+		name    string
+		resp    string
+		want    <-chan *Message // Channel the response must arrive on, or nil
+		unknown bool
+	}{
+		// Responses may arrive in any order
+		{name: "second", resp: `{"jsonrpc":"2.0","id":2,"result":"two"}`, want: secondCh},
+		{name: "first", resp: `{"jsonrpc":"2.0","id":1,"result":"one"}`, want: firstCh},
+		{name: "duplicate", resp: `{"jsonrpc":"2.0","id":1,"result":"again"}`, unknown: true},
+		{name: "forgotten", resp: `{"jsonrpc":"2.0","id":3,"result":"late"}`, unknown: true},
+		{name: "string id", resp: `{"jsonrpc":"2.0","id":"2","result":"two"}`, unknown: true},
+		{name: "never sent", resp: `{"jsonrpc":"2.0","id":9,"result":"nine"}`, unknown: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			msgs, _, err := Decode([]byte(tt.resp))
+			if err != nil {
+				t.Fatal(err)
+			}
+			err = calls.Deliver(msgs[0])
+			if tt.unknown {
+				if !errors.Is(err, ErrUnknownID) {
+					t.Fatalf("Deliver err = %v, want ErrUnknownID", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Deliver: %v", err)
+			}
+			select {
+			case got := <-tt.want:
+				if got != msgs[0] {
+					t.Errorf("got response %s, want %s", got.Result, msgs[0].Result)
+				}
+			default:
+				t.Fatal("response not delivered to its request")
+			}
+		})
+	}
+}
@@ -0,0 +1,172 @@
+package limits
+
+import (
+	"fmt"
+	"sync"
+	"sync/atomic"
+)
+
+// Limits caps the resources a single call or session may use. Zero disables a limit.
+type Limits struct {
+	MaxContentLength         int   `json:"max_content_length"`         // Characters in any single string argument
+	MaxChunks                int   `json:"max_chunks"`                 // Chunks produced from one document
+	MaxBatchItems            int   `json:"max_batch_items"`            // Documents in one batch validation
+	MaxConcurrentValidations int   `json:"max_concurrent_validations"` // Validation calls running at once, server-wide
+	SessionMemoryBudget      int64 `json:"session_memory_budget"`      // Bytes of arguments in flight per session
+}
+
+// Default returns the built-in limits
+func Default() Limits {
+	return Limits{
+		MaxContentLength:         200_000,
+		MaxChunks:                300,
+		MaxBatchItems:            50,
+		MaxConcurrentValidations: 8,
+		SessionMemoryBudget:      16 << 20,
+	}
+}
+
+// Validate checks that no limit is negative
+func (l Limits) Validate() error {
+	if l.MaxContentLength < 0 || l.MaxChunks < 0 || l.MaxBatchItems < 0 || l.MaxConcurrentValidations < 0 || l.SessionMemoryBudget < 0 {
+		return fmt.Errorf("limits must not be negative")
+	}
+	return nil
+}
+
+var current atomic.Pointer[Limits]
+
+func init() {
+	defaults := Default()
+	current.Store(&defaults)
+}
+
+// Current returns the limits in effect
+func Current() Limits {
+	return *current.Load()
+}
+
+// Set atomically replaces the limits used by subsequent calls
+func Set(l Limits) error {
+	if err := l.Validate(); err != nil {
+		return err
+	}
+	current.Store(&l)
+	return nil
+}
+
+// Error reports which limit a call exceeded
+type Error struct {
+	Limit     string `json:"limit"`
+	Value     int64  `json:"value"`
+	Max       int64  `json:"max"`
+	Message   string `json:"message"`
+	Retryable bool   `json:"retryable"`
+}
+
+func (e *Error) Error() string {
+	return e.Message
+}
+
+// CheckContentLength rejects string arguments longer than MaxContentLength
+func CheckContentLength(name string, length int) error {
+	max := Current().MaxContentLength
+	if max > 0 && length > max {
+		return &Error{
+			Limit:   "max_content_length",
+			Value:   int64(length),
+			Max:     int64(max),
+			Message: fmt.Sprintf("%s is %d characters, which exceeds the limit of %d; split it into smaller documents", name, length, max),
+		}
+	}
+	return nil
+}
+
+// CheckChunks rejects documents that split into more than MaxChunks chunks
+func CheckChunks(count int) error {
+	max := Current().MaxChunks
+	if max > 0 && count > max {
+		return &Error{
+			Limit:   "max_chunks",
+			Value:   int64(count),
+			Max:     int64(max),
+			Message: fmt.Sprintf("content splits into %d chunks, which exceeds the limit of %d; validate it in smaller sections", count, max),
+		}
+	}
+	return nil
+}
+
+// CheckBatchItems rejects batches of more than MaxBatchItems documents
+func CheckBatchItems(count int) error {
+	max := Current().MaxBatchItems
+	if max > 0 && count > max {
+		return &Error{
+			Limit:   "max_batch_items",
+			Value:   int64(count),
+			Max:     int64(max),
+			Message: fmt.Sprintf("batch has %d documents, which exceeds the limit of %d; split it into smaller batches", count, max),
+		}
+	}
+	return nil
+}
+
+// Enforcer tracks concurrent validations and per-session memory use
+type Enforcer struct {
+	mu          sync.Mutex
+	validations int
+	sessions    map[string]int64
+}
+
+// NewEnforcer creates an enforcer with nothing in flight
+func NewEnforcer() *Enforcer {
+	return &Enforcer{sessions: map[string]int64{}}
+}
+
+// Acquire reserves a validation slot (if validation is true) and size bytes of the session's
+// memory budget. The returned release func must be called when the call finishes.
+func (e *Enforcer) Acquire(sessionID string, size int64, validation bool) (func(), error) {
+	l := Current()
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if validation && l.MaxConcurrentValidations > 0 && e.validations >= l.MaxConcurrentValidations {
+		return nil, &Error{
+			Limit:     "max_concurrent_validations",
+			Value:     int64(e.validations + 1),
+			Max:       int64(l.MaxConcurrentValidations),
+			Message:   fmt.Sprintf("server is already running %d validations; retry shortly", e.validations),
+			Retryable: true,
+		}
+	}
+
+	inUse := e.sessions[sessionID]
+	if l.SessionMemoryBudget > 0 && inUse+size > l.SessionMemoryBudget {
+		return nil, &Error{
+			Limit:     "session_memory_budget",
+			Value:     inUse + size,
+			Max:       l.SessionMemoryBudget,
+			Message:   fmt.Sprintf("session has %d bytes in flight and this call needs %d more, exceeding the budget of %d; wait for running calls to finish", inUse, size, l.SessionMemoryBudget),
+			Retryable: inUse > 0,
+		}
+	}
+
+	if validation {
+		e.validations++
+	}
+	e.sessions[sessionID] = inUse + size
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			e.mu.Lock()
+			defer e.mu.Unlock()
+			if validation {
+				e.validations--
+			}
+			if e.sessions[sessionID] -= size; e.sessions[sessionID] <= 0 {
+				delete(e.sessions, sessionID)
+			}
+		})
+	}, nil
+}
@@ -0,0 +1,70 @@
+package lsp
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+
+	"github.com/carlisia/mcp-factcheck/internal/jsonrpc"
+)
+
+// conn reads and writes LSP base-protocol framed messages (Content-Length headers + JSON body)
+type conn struct {
+	reader *jsonrpc.Reader
+	writer *jsonrpc.Writer
+}
+
+func newConn(r io.Reader, w io.Writer) *conn {
+	return &conn{
+		reader: jsonrpc.NewReader(r, jsonrpc.FramingHeader, jsonrpc.DefaultMaxMessageSize),
+		writer: jsonrpc.NewWriter(w, jsonrpc.FramingHeader),
+	}
+}
+
+// read returns the next message. Messages that can't be read or parsed are returned
+// as a *jsonrpc.Error to answer with, and reading can go on.
+func (c *conn) read() (*jsonrpc.Message, error) {
+	body, err := c.reader.Read()
+	if errors.Is(err, jsonrpc.ErrTooLarge) {
+		return nil, jsonrpc.NewError(jsonrpc.CodeInvalidRequest, "%v", err)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// LSP doesn't use batches, so a message is a single object
+	var msg jsonrpc.Message
+	if err := json.Unmarshal(body, &msg); err != nil {
+		return nil, jsonrpc.NewError(jsonrpc.CodeParseError, "%v", err)
+	}
+	return &msg, nil
+}
+
+func (c *conn) write(msg *jsonrpc.Message) error {
+	body, err := json.Marshal(msg)
+	if err != nil {
+		return err
+	}
+	return c.writer.Write(body)
+}
+
+func (c *conn) reply(id jsonrpc.ID, result any) error {
+	// A nil result is sent as null, which must still be present in the response
+	msg, err := jsonrpc.NewResponse(id, result)
+	if err != nil {
+		return err
+	}
+	return c.write(msg)
+}
+
+func (c *conn) replyError(id jsonrpc.ID, code int, text string) error {
+	return c.write(jsonrpc.NewErrorResponse(id, &jsonrpc.Error{Code: code, Message: text}))
+}
+
+func (c *conn) notify(method string, params any) error {
+	msg, err := jsonrpc.NewNotification(method, params)
+	if err != nil {
+		return err
+	}
+	return c.write(msg)
+}
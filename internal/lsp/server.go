@@ -0,0 +1,355 @@
+package lsp
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/carlisia/mcp-factcheck/embedding"
+	"github.com/carlisia/mcp-factcheck/internal/diagnostics"
+	mcpembedding "github.com/carlisia/mcp-factcheck/internal/embedding"
+	"github.com/carlisia/mcp-factcheck/internal/jsonrpc"
+	"github.com/carlisia/mcp-factcheck/internal/version"
+	"github.com/carlisia/mcp-factcheck/pkg/logger"
+	"github.com/carlisia/mcp-factcheck/pkg/validator"
+	"go.uber.org/zap"
+)
+
+// Source identifies this server's diagnostics in the editor
+const Source = "mcp-factcheck"
+
+// DefaultDebounce is how long the server waits after the last edit before validating
+const DefaultDebounce = 1500 * time.Millisecond
+
+// maxCachedChunks bounds the chunk cache; it is cleared when full
+const maxCachedChunks = 5000
+
+// Config controls validation behavior
+type Config struct {
+	SpecVersion string
+	Debounce    time.Duration
+}
+
+// Server is a language server that publishes MCP spec diagnostics for markdown documents
+type Server struct {
+	vectorDB  *mcpembedding.VectorDB
+	generator *embedding.Generator
+	config    Config
+	conn      *conn
+
+	mu        sync.Mutex
+	documents map[string]*document
+	cache     map[string]validator.ChunkValidationResult
+}
+
+type document struct {
+	version int
+	text    string
+	timer   *time.Timer
+	cancel  context.CancelFunc
+}
+
+// NewServer creates a language server
+func NewServer(vectorDB *mcpembedding.VectorDB, generator *embedding.Generator, config Config) *Server {
+	if config.Debounce <= 0 {
+		config.Debounce = DefaultDebounce
+	}
+	return &Server{
+		vectorDB:  vectorDB,
+		generator: generator,
+		config:    config,
+		documents: make(map[string]*document),
+		cache:     make(map[string]validator.ChunkValidationResult),
+	}
+}
+
+// Serve handles LSP messages on r/w until the client sends exit or the stream closes
+func (s *Server) Serve(ctx context.Context, r io.Reader, w io.Writer) error {
+	log := logger.Get()
+	s.conn = newConn(r, w)
+
+	for {
+		msg, err := s.conn.read()
+		if err != nil {
+			var rpcErr *jsonrpc.Error
+			if errors.As(err, &rpcErr) {
+				_ = s.conn.replyError("", rpcErr.Code, rpcErr.Message)
+				continue
+			}
+			if errors.Is(err, io.EOF) {
+				return nil
+			}
+			return err
+		}
+
+		if msg.Method == "exit" {
+			return nil
+		}
+		if err := s.handle(ctx, msg); err != nil {
+			log.Warn("Failed to handle LSP message", zap.String("method", msg.Method), zap.Error(err))
+		}
+	}
+}
+
+func (s *Server) handle(ctx context.Context, msg *jsonrpc.Message) error {
+	switch msg.Method {
+	case "initialize":
+		return s.conn.reply(msg.ID, initializeResult{
+			Capabilities: serverCapabilities{
+				TextDocumentSync: textDocumentSyncOptions{OpenClose: true, Change: textDocumentSyncFull},
+			},
+			ServerInfo: serverInfo{Name: "factcheck-lsp", Version: version.ServerVersion()},
+		})
+
+	case "shutdown":
+		s.closeAll()
+		return s.conn.reply(msg.ID, nil)
+
+	case "textDocument/didOpen":
+		var params didOpenParams
+		if err := json.Unmarshal(msg.Params, &params); err != nil {
+			return err
+		}
+		doc := params.TextDocument
+		if !isMarkdown(doc.URI, doc.LanguageID) {
+			return nil
+		}
+		s.update(ctx, doc.URI, doc.Version, doc.Text)
+		return nil
+
+	case "textDocument/didChange":
+		var params didChangeParams
+		if err := json.Unmarshal(msg.Params, &params); err != nil {
+			return err
+		}
+		if len(params.ContentChanges) == 0 {
+			return nil
+		}
+		s.mu.Lock()
+		_, tracked := s.documents[params.TextDocument.URI]
+		s.mu.Unlock()
+		if !tracked {
+			return nil
+		}
+		// Full sync: the last change holds the complete document
+		s.update(ctx, params.TextDocument.URI, params.TextDocument.Version, params.ContentChanges[len(params.ContentChanges)-1].Text)
+		return nil
+
+	case "textDocument/didClose":
+		var params didCloseParams
+		if err := json.Unmarshal(msg.Params, &params); err != nil {
+			return err
+		}
+		s.close(params.TextDocument.URI)
+		return s.conn.notify("textDocument/publishDiagnostics", publishDiagnosticsParams{URI: params.TextDocument.URI, Diagnostics: []Diagnostic{}})
+	}
+
+	if msg.IsRequest() {
+		return s.conn.replyError(msg.ID, jsonrpc.CodeMethodNotFound, fmt.Sprintf("method not supported: %s", msg.Method))
+	}
+	// Other notifications (initialized, didSave, $/cancelRequest, ...) need no action
+	return nil
+}
+
+// update records the latest document text and (re)starts its debounce timer
+func (s *Server) update(ctx context.Context, uri string, version int, text string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	doc, ok := s.documents[uri]
+	if !ok {
+		doc = &document{}
+		s.documents[uri] = doc
+	}
+	doc.version = version
+	doc.text = text
+
+	if doc.timer != nil {
+		doc.timer.Stop()
+	}
+	if doc.cancel != nil {
+		// A newer edit supersedes any validation still in flight
+		doc.cancel()
+		doc.cancel = nil
+	}
+	doc.timer = time.AfterFunc(s.config.Debounce, func() {
+		s.validate(ctx, uri, version, text)
+	})
+}
+
+func (s *Server) close(uri string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if doc, ok := s.documents[uri]; ok {
+		if doc.timer != nil {
+			doc.timer.Stop()
+		}
+		if doc.cancel != nil {
+			doc.cancel()
+		}
+		delete(s.documents, uri)
+	}
+}
+
+func (s *Server) closeAll() {
+	s.mu.Lock()
+	uris := make([]string, 0, len(s.documents))
+	for uri := range s.documents {
+		uris = append(uris, uri)
+	}
+	s.mu.Unlock()
+	for _, uri := range uris {
+		s.close(uri)
+	}
+}
+
+// validate checks one document version and publishes diagnostics unless a newer edit arrived meanwhile
+func (s *Server) validate(ctx context.Context, uri string, version int, text string) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	s.mu.Lock()
+	doc, ok := s.documents[uri]
+	if !ok || doc.version != version {
+		s.mu.Unlock()
+		return
+	}
+	doc.cancel = cancel
+	s.mu.Unlock()
+
+	log := logger.Get().With(zap.String("uri", uri), zap.Int("version", version))
+
+	chunks := validator.ChunkContent(text).Chunks
+	results := make([]validator.ChunkValidationResult, 0, len(chunks))
+	var cacheHits, failures int
+	for _, chunk := range chunks {
+		if ctx.Err() != nil {
+			return
+		}
+		key := s.cacheKey(chunk.Text)
+		if cached, ok := s.cached(key); ok {
+			// Same text, possibly at a new position in the document
+			cached.Chunk = chunk
+			results = append(results, cached)
+			cacheHits++
+			continue
+		}
+		result := validator.ValidateChunk(ctx, s.vectorDB, s.generator, chunk, s.config.SpecVersion)
+		if result.Error != "" {
+			log.Warn("Chunk validation failed", zap.String("chunk", chunk.ID), zap.String("error", result.Error))
+			failures++
+		} else {
+			s.store(key, result)
+		}
+		results = append(results, result)
+	}
+
+	s.mu.Lock()
+	current, ok := s.documents[uri]
+	stale := !ok || current.version != version
+	s.mu.Unlock()
+	if stale {
+		return
+	}
+	if len(chunks) > 0 && failures == len(chunks) {
+		// Keep the previous diagnostics rather than clearing them on an outage
+		return
+	}
+
+	aggregated := validator.AggregatedValidationResult{ChunkResults: results, SpecVersion: s.config.SpecVersion}
+	diagnostics := Diagnostics(text, aggregated.Findings())
+	log.Debug("Publishing diagnostics",
+		zap.Int("chunks", len(chunks)),
+		zap.Int("cache_hits", cacheHits),
+		zap.Int("diagnostics", len(diagnostics)))
+
+	if err := s.conn.notify("textDocument/publishDiagnostics", publishDiagnosticsParams{
+		URI:         uri,
+		Version:     version,
+		Diagnostics: diagnostics,
+	}); err != nil {
+		log.Warn("Failed to publish diagnostics", zap.Error(err))
+	}
+}
+
+func (s *Server) cacheKey(text string) string {
+	sum := sha256.Sum256([]byte(s.config.SpecVersion + "\x00" + text))
+	return hex.EncodeToString(sum[:])
+}
+
+func (s *Server) cached(key string) (validator.ChunkValidationResult, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	result, ok := s.cache[key]
+	return result, ok
+}
+
+func (s *Server) store(key string, result validator.ChunkValidationResult) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if len(s.cache) >= maxCachedChunks {
+		s.cache = make(map[string]validator.ChunkValidationResult)
+	}
+	s.cache[key] = result
+}
+
+// Diagnostics converts findings into LSP diagnostics spanning each finding's first line
+func Diagnostics(text string, findings []validator.ValidationError) []Diagnostic {
+	anchored := diagnostics.FromFindings("", text, findings)
+	result := make([]Diagnostic, 0, len(anchored))
+	for _, d := range anchored {
+		message := d.Message
+		if d.SpecSection != "" {
+			message += " (spec: " + d.SpecSection + ")"
+		}
+		for _, suggestion := range d.Suggestions {
+			message += "\n• " + suggestion
+		}
+		diagnostic := Diagnostic{
+			Range: Range{
+				Start: Position(d.Range.Start),
+				End:   Position(d.Range.End),
+			},
+			Severity: lspSeverity(d.Severity),
+			Code:     d.Code,
+			Source:   Source,
+			Message:  message,
+		}
+		if d.SpecURL != "" {
+			diagnostic.CodeDescription = &CodeDescription{Href: d.SpecURL}
+		}
+		result = append(result, diagnostic)
+	}
+	return result
+}
+
+func lspSeverity(severity string) int {
+	switch severity {
+	case "error":
+		return SeverityError
+	case "warning":
+		return SeverityWarning
+	default:
+		return SeverityInformation
+	}
+}
+
+func isMarkdown(uri, languageID string) bool {
+	switch languageID {
+	case "markdown", "mdx":
+		return true
+	}
+	switch strings.ToLower(path.Ext(uri)) {
+	case ".md", ".mdx", ".markdown":
+		return true
+	}
+	return false
+}
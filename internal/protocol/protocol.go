@@ -0,0 +1,185 @@
+// Package protocol negotiates the MCP protocol revision of each client session and
+// adapts responses to it, so clients of older revisions such as 2024-11-05 don't
+// receive fields their revision doesn't define.
+//
+// mcp-go answers initialize with the client's version when it knows it and otherwise
+// its own latest, whatever the server implements, and writes the same responses for
+// every revision. Sessions replaces the negotiated version with one from
+// SupportedVersions and, through mcp-go hooks, strips what the session's revision
+// predates from tools/list and tools/call responses.
+package protocol
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"slices"
+	"sync"
+
+	"github.com/carlisia/mcp-factcheck/pkg/logger"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"go.uber.org/zap"
+)
+
+// MCP protocol revisions the server implements
+const (
+	Version20241105 = "2024-11-05"
+	Version20250326 = "2025-03-26"
+	Version20250618 = "2025-06-18"
+)
+
+// Latest is the newest revision the server implements, answered to clients that
+// request one it doesn't
+const Latest = Version20250618
+
+// DefaultVersion is assumed for requests that can't be tied to a negotiated session
+// and carry no MCP-Protocol-Version header, as the 2025-06-18 transports spec directs
+const DefaultVersion = Version20250326
+
+// SupportedVersions lists the revisions the server implements, newest first
+var SupportedVersions = []string{Version20250618, Version20250326, Version20241105}
+
+// HeaderVersion is the HTTP header carrying the negotiated revision on requests after
+// initialization
+const HeaderVersion = "MCP-Protocol-Version"
+
+// maxSessions bounds how many sessions' versions are remembered. Streamable HTTP
+// sessions aren't unregistered when they end, so without it the map would only grow.
+const maxSessions = 10000
+
+// Feature is a part of the protocol added in a revision
+type Feature struct {
+	Name  string
+	Since string // First revision defining it
+}
+
+// Features whose responses are adapted for older revisions
+var (
+	StructuredContent = Feature{Name: "structured tool output", Since: Version20250618}
+)
+
+// IsSupported reports whether version is a revision the server implements
+func IsSupported(version string) bool {
+	return slices.Contains(SupportedVersions, version)
+}
+
+// Negotiate returns the revision to use with a client requesting requested: that
+// revision when the server implements it, else Latest, leaving the client to
+// disconnect if it can't speak it
+func Negotiate(requested string) string {
+	if IsSupported(requested) {
+		return requested
+	}
+	return Latest
+}
+
+// Has reports whether revision version defines f. Revisions are dates, so they
+// compare as strings.
+func Has(version string, f Feature) bool {
+	return version >= f.Since
+}
+
+// Sessions records the revision negotiated with each client session
+type Sessions struct {
+	mu       sync.Mutex
+	versions map[string]string
+}
+
+// NewSessions creates an empty record
+func NewSessions() *Sessions {
+	return &Sessions{versions: map[string]string{}}
+}
+
+// OnInitialize negotiates the session's revision and answers with it. It is an
+// mcp-go after-initialize hook.
+func (s *Sessions) OnInitialize(ctx context.Context, id any, req *mcp.InitializeRequest, result *mcp.InitializeResult) {
+	requested := req.Params.ProtocolVersion
+	result.ProtocolVersion = Negotiate(requested)
+	if cs := server.ClientSessionFromContext(ctx); cs != nil && cs.SessionID() != "" {
+		s.mu.Lock()
+		if len(s.versions) >= maxSessions {
+			for key := range s.versions {
+				delete(s.versions, key)
+				break
+			}
+		}
+		s.versions[cs.SessionID()] = result.ProtocolVersion
+		s.mu.Unlock()
+	}
+
+	log := logger.Get().With(
+		zap.String("requested", requested),
+		zap.String("negotiated", result.ProtocolVersion),
+		zap.String("client", req.Params.ClientInfo.Name))
+	if result.ProtocolVersion != requested {
+		log.Warn("Client requested an unsupported MCP protocol version")
+	} else {
+		log.Debug("Negotiated MCP protocol version")
+	}
+}
+
+// OnUnregister forgets a session that ended. It is an mcp-go unregister-session hook.
+func (s *Sessions) OnUnregister(ctx context.Context, cs server.ClientSession) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.versions, cs.SessionID())
+}
+
+// Version returns the revision of the session a request arrived in: the one
+// negotiated at its initialization, else the one its MCP-Protocol-Version header
+// names, else DefaultVersion
+func (s *Sessions) Version(ctx context.Context, header http.Header) string {
+	if cs := server.ClientSessionFromContext(ctx); cs != nil {
+		s.mu.Lock()
+		version, ok := s.versions[cs.SessionID()]
+		s.mu.Unlock()
+		if ok {
+			return version
+		}
+	}
+	if version := header.Get(HeaderVersion); IsSupported(version) {
+		return version
+	}
+	return DefaultVersion
+}
+
+// AdaptTools removes output schemas from tools/list results for revisions without
+// structured tool output. It is an mcp-go after-list-tools hook.
+func (s *Sessions) AdaptTools(ctx context.Context, id any, req *mcp.ListToolsRequest, result *mcp.ListToolsResult) {
+	if Has(s.Version(ctx, req.Header), StructuredContent) {
+		return
+	}
+	// The tools are copies made for this response, so they can be changed in place
+	for i := range result.Tools {
+		result.Tools[i].RawOutputSchema = nil
+	}
+}
+
+// AdaptToolResult removes structured content from tools/call results for revisions
+// without structured tool output. Their text content is left as is. It is an mcp-go
+// after-call-tool hook.
+func (s *Sessions) AdaptToolResult(ctx context.Context, id any, req *mcp.CallToolRequest, result *mcp.CallToolResult) {
+	if result == nil || Has(s.Version(ctx, req.Header), StructuredContent) {
+		return
+	}
+	result.StructuredContent = nil
+}
+
+// CheckHeader rejects HTTP requests naming a revision the server doesn't implement
+// in their MCP-Protocol-Version header with 400 Bad Request, as the 2025-06-18
+// transports spec requires. Requests without the header pass.
+func CheckHeader(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if version := r.Header.Get(HeaderVersion); version != "" && !IsSupported(version) {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusBadRequest)
+			json.NewEncoder(w).Encode(map[string]any{
+				"error":     "unsupported MCP protocol version " + version,
+				"supported": SupportedVersions,
+			})
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
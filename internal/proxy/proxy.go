@@ -0,0 +1,275 @@
+// Package proxy relays an MCP stdio session between a client and a server while
+// recording every message and validating it against the MCP schema, so any server,
+// or any client, can be checked for conformance in the session it really has.
+package proxy
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/carlisia/mcp-factcheck/internal/jsonrpc"
+	"github.com/carlisia/mcp-factcheck/internal/specs"
+	"github.com/carlisia/mcp-factcheck/pkg/schema"
+)
+
+// Directions a message travels in
+const (
+	ClientToServer = "client_to_server"
+	ServerToClient = "server_to_client"
+)
+
+// arrows show directions in reports
+var arrows = map[string]string{ClientToServer: "client → server", ServerToClient: "server → client"}
+
+// Config configures a proxy
+type Config struct {
+	// SpecVersion is the MCP spec version messages are validated against. When empty,
+	// it is the protocol version the server answers initialize with, and
+	// specs.DefaultSpecVersion until then.
+	SpecVersion string
+	// Record receives an Entry per message as JSON Lines; nil records nothing
+	Record io.Writer
+	// Report receives a line per message with violations, or per message when Verbose
+	Report  io.Writer
+	Verbose bool
+	// MaxMessageSize bounds one message, or jsonrpc.DefaultMaxMessageSize
+	MaxMessageSize int
+}
+
+// Entry is the record of one message
+type Entry struct {
+	Time        time.Time          `json:"time"`
+	Direction   string             `json:"direction"`
+	Message     json.RawMessage    `json:"message,omitempty"`
+	Raw         string             `json:"raw,omitempty"` // The message as sent, when it isn't JSON
+	Kind        string             `json:"kind,omitempty"`
+	Method      string             `json:"method,omitempty"`
+	RespondsTo  string             `json:"responds_to,omitempty"` // Method of the request a response answers
+	LatencyMS   float64            `json:"latency_ms,omitempty"`  // Since that request, for responses
+	SpecVersion string             `json:"spec_version"`
+	Valid       bool               `json:"valid"`
+	Violations  []schema.Violation `json:"violations,omitempty"`
+}
+
+// Stats counts the messages a proxy relayed
+type Stats struct {
+	Messages int `json:"messages"`
+	Invalid  int `json:"invalid"`  // Messages with error violations
+	Warnings int `json:"warnings"` // Messages with only warnings
+}
+
+// call is a request awaiting its response
+type call struct {
+	method string
+	sent   time.Time
+}
+
+// Proxy relays and checks one session
+type Proxy struct {
+	cfg Config
+
+	mu       sync.Mutex
+	version  string
+	pending  map[string]call // Requests by direction and ID
+	stats    Stats
+	recorder *json.Encoder
+}
+
+// New returns a proxy for one session
+func New(cfg Config) *Proxy {
+	p := &Proxy{cfg: cfg, version: cfg.SpecVersion, pending: map[string]call{}}
+	if p.version == "" {
+		p.version = specs.DefaultSpecVersion
+	}
+	if cfg.Record != nil {
+		p.recorder = json.NewEncoder(cfg.Record)
+	}
+	return p
+}
+
+// Run relays messages from clientIn to serverIn and from serverOut to clientOut. When
+// the client's input ends, the server's input is closed so it can exit; Run returns
+// when the server's output ends.
+func (p *Proxy) Run(clientIn io.Reader, clientOut io.Writer, serverIn io.WriteCloser, serverOut io.Reader) error {
+	go func() {
+		p.pump(ClientToServer, clientIn, serverIn)
+		serverIn.Close()
+	}()
+	return p.pump(ServerToClient, serverOut, clientOut)
+}
+
+// Stats returns the counts so far
+func (p *Proxy) Stats() Stats {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.stats
+}
+
+// pump relays messages in one direction until its input ends. Each message is
+// forwarded before it is checked, so checks never delay the session.
+func (p *Proxy) pump(direction string, in io.Reader, out io.Writer) error {
+	reader := jsonrpc.NewReader(in, jsonrpc.FramingAuto, p.cfg.MaxMessageSize)
+	writer := jsonrpc.NewWriter(out, jsonrpc.FramingNewline)
+	for {
+		raw, err := reader.Read()
+		if err != nil {
+			if err == io.EOF {
+				return nil
+			}
+			p.report("%s: %v", arrows[direction], err)
+			// An oversized message is dropped, and the session goes on without it
+			if errors.Is(err, jsonrpc.ErrTooLarge) {
+				continue
+			}
+			return fmt.Errorf("failed to read %s: %w", arrows[direction], err)
+		}
+
+		// Stdio messages are one per line. Messages with headers are passed on without
+		// them, and multi-line ones compacted, so the receiver can read them; both are
+		// flagged.
+		var framingErr string
+		multiline := bytes.ContainsAny(raw, "\r\n")
+		switch {
+		case reader.Framing() == jsonrpc.FramingHeader:
+			framingErr = "message has a Content-Length header; stdio messages are delimited by newlines"
+		case multiline:
+			framingErr = "message contains a newline; stdio messages must be one line"
+		}
+		forwarded := raw
+		if multiline {
+			var compact bytes.Buffer
+			if json.Compact(&compact, raw) == nil {
+				forwarded = compact.Bytes()
+			}
+		}
+		if err := writer.Write(forwarded); err != nil {
+			return fmt.Errorf("failed to write %s: %w", arrows[direction], err)
+		}
+
+		p.check(direction, raw, framingErr)
+	}
+}
+
+// check validates, records, and reports one message, adding framingErr, if any, to
+// its violations
+func (p *Proxy) check(direction string, raw []byte, framingErr string) {
+	now := time.Now()
+	msgs, batch, _ := jsonrpc.Decode(raw)
+
+	p.mu.Lock()
+	entry := Entry{Time: now, Direction: direction, SpecVersion: p.version}
+
+	// A response is checked against the request it answers, which went the other way
+	requestDirection := ServerToClient
+	if direction == ServerToClient {
+		requestDirection = ClientToServer
+	}
+	for _, msg := range msgs {
+		switch {
+		case msg.IsRequest():
+			p.pending[direction+string(msg.ID)] = call{method: msg.Method, sent: now}
+		case msg.IsResponse():
+			key := requestDirection + string(msg.ID)
+			if c, ok := p.pending[key]; ok {
+				delete(p.pending, key)
+				if !batch {
+					entry.RespondsTo = c.method
+					entry.LatencyMS = float64(now.Sub(c.sent).Microseconds()) / 1000
+				}
+				if c.method == "initialize" && direction == ServerToClient {
+					p.negotiated(msg)
+				}
+			}
+		}
+	}
+	p.mu.Unlock()
+
+	result := schema.Validate(raw, entry.SpecVersion, entry.RespondsTo)
+	if framingErr != "" {
+		result.Violations = append(result.Violations, schema.Violation{
+			Path:     "$",
+			Severity: schema.SeverityError,
+			Message:  framingErr,
+		})
+		result.Valid = false
+	}
+	entry.Kind, entry.Method, entry.Valid, entry.Violations = result.Kind, result.Method, result.Valid, result.Violations
+	if json.Valid(raw) {
+		entry.Message = raw
+	} else {
+		entry.Raw = string(raw)
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.stats.Messages++
+	switch {
+	case !entry.Valid:
+		p.stats.Invalid++
+	case len(entry.Violations) > 0:
+		p.stats.Warnings++
+	}
+	if p.recorder != nil {
+		if err := p.recorder.Encode(entry); err != nil {
+			p.reportLocked("failed to record message: %v", err)
+		}
+	}
+	if len(entry.Violations) > 0 || p.cfg.Verbose {
+		p.reportEntryLocked(entry)
+	}
+}
+
+// negotiated switches validation to the protocol version the server answered
+// initialize with, unless the configuration fixes one. The caller holds mu.
+func (p *Proxy) negotiated(resp *jsonrpc.Message) {
+	if p.cfg.SpecVersion != "" || resp.Error != nil {
+		return
+	}
+	var result struct {
+		ProtocolVersion string `json:"protocolVersion"`
+	}
+	if json.Unmarshal(resp.Result, &result) != nil {
+		return
+	}
+	if !specs.IsValidSpecVersion(result.ProtocolVersion) {
+		p.reportLocked("server negotiated protocol version %q, which isn't a known spec version; validating against %s", result.ProtocolVersion, p.version)
+		return
+	}
+	p.version = result.ProtocolVersion
+}
+
+// reportEntryLocked writes a message's summary and violations. The caller holds mu.
+func (p *Proxy) reportEntryLocked(entry Entry) {
+	name := entry.Method
+	if entry.RespondsTo != "" {
+		name = entry.RespondsTo
+	}
+	status := "ok"
+	if !entry.Valid {
+		status = "INVALID"
+	} else if len(entry.Violations) > 0 {
+		status = "warnings"
+	}
+	p.reportLocked("%s %s %s: %s", arrows[entry.Direction], entry.Kind, name, status)
+	for _, v := range entry.Violations {
+		p.reportLocked("    %s at %s: %s", v.Severity, v.Path, v.Message)
+	}
+}
+
+func (p *Proxy) report(format string, args ...any) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.reportLocked(format, args...)
+}
+
+// reportLocked writes a line to the report. The caller holds mu.
+func (p *Proxy) reportLocked(format string, args ...any) {
+	if p.cfg.Report != nil {
+		fmt.Fprintf(p.cfg.Report, "factcheck-proxy: "+format+"\n", args...)
+	}
+}
@@ -0,0 +1,282 @@
+// Package requirements extracts the normative statements of the MCP specification,
+// the sentences using RFC 2119 keywords such as MUST and SHOULD, into a catalog per
+// spec version.
+package requirements
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"path"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/carlisia/mcp-factcheck/embedding"
+	"github.com/carlisia/mcp-factcheck/internal/specs"
+)
+
+// Level is the strength of a requirement
+type Level string
+
+const (
+	LevelMust   Level = "MUST"   // MUST, MUST NOT, REQUIRED, SHALL, and SHALL NOT
+	LevelShould Level = "SHOULD" // SHOULD, SHOULD NOT, RECOMMENDED, and NOT RECOMMENDED
+	LevelMay    Level = "MAY"    // MAY and OPTIONAL
+)
+
+// Levels lists the requirement levels, strongest first
+var Levels = []Level{LevelMust, LevelShould, LevelMay}
+
+// ParseLevel returns the level named by name, in any case
+func ParseLevel(name string) (Level, error) {
+	for _, level := range Levels {
+		if strings.EqualFold(string(level), name) {
+			return level, nil
+		}
+	}
+	return "", fmt.Errorf("unknown requirement level %q (valid: %v)", name, Levels)
+}
+
+// keywordLevels maps each RFC 2119 keyword to its level
+var keywordLevels = map[string]Level{
+	"MUST":            LevelMust,
+	"MUST NOT":        LevelMust,
+	"REQUIRED":        LevelMust,
+	"SHALL":           LevelMust,
+	"SHALL NOT":       LevelMust,
+	"SHOULD":          LevelShould,
+	"SHOULD NOT":      LevelShould,
+	"RECOMMENDED":     LevelShould,
+	"NOT RECOMMENDED": LevelShould,
+	"MAY":             LevelMay,
+	"OPTIONAL":        LevelMay,
+}
+
+// keyword matches RFC 2119 keywords, negated forms first so they win
+var keyword = regexp.MustCompile(`\b(NOT RECOMMENDED|MUST NOT|SHALL NOT|SHOULD NOT|MUST|REQUIRED|SHALL|SHOULD|RECOMMENDED|MAY|OPTIONAL)\b`)
+
+var (
+	sentenceEnd = regexp.MustCompile(`[.!?]["')\]]*\s+`)
+	listMarker  = regexp.MustCompile(`^([-*+]|\d+[.)])\s+`)
+	codeSpan    = regexp.MustCompile("`([^`\n]+)`")
+)
+
+// maxStatement caps the length of a statement, for sentences run together by tables
+const maxStatement = 500
+
+// OverviewPage is the page name of the specification's overview page
+const OverviewPage = "index"
+
+// Requirement is one normative statement of the spec
+type Requirement struct {
+	ID          string   `json:"id"`                // Page and a hash of the statement, stable while the statement is unchanged
+	Level       Level    `json:"level"`             // Strongest level of the keywords in the statement
+	Keyword     string   `json:"keyword"`           // First keyword of that level as written, such as "MUST NOT"
+	Statement   string   `json:"statement"`         // The sentence stating the requirement
+	Page        string   `json:"page,omitempty"`    // Spec page, such as "basic/lifecycle"; empty when unknown
+	Section     string   `json:"section,omitempty"` // Heading hierarchy above the statement
+	URL         string   `json:"url,omitempty"`     // Published page, anchored at the nearest heading
+	Terms       []string `json:"terms,omitempty"`   // Lowercased code spans in the statement, such as "initialize"
+	ContentHash string   `json:"content_hash"`      // Hex SHA-256 of the spec passage stating it, as embedded chunks record
+}
+
+// Prohibition reports whether r forbids something rather than requiring it
+func (r Requirement) Prohibition() bool {
+	return strings.Contains(r.Keyword, "NOT")
+}
+
+// Catalog is the requirements of one spec version, in spec order
+type Catalog struct {
+	Version      string        `json:"version"`
+	Commit       string        `json:"commit,omitempty"`       // MCP repository commit the spec was extracted at, when known
+	CommittedAt  *time.Time    `json:"committed_at,omitempty"` // When that commit was made
+	Requirements []Requirement `json:"requirements"`
+	Count        int           `json:"count"`
+}
+
+// Filter returns the requirements at one of levels on one of pages or their
+// subpages. Empty levels or pages match every requirement.
+func (c *Catalog) Filter(levels []Level, pages []string) []Requirement {
+	var matched []Requirement
+	for _, r := range c.Requirements {
+		if len(levels) > 0 && !slices.Contains(levels, r.Level) {
+			continue
+		}
+		if len(pages) > 0 && !InPages(r.Page, pages) {
+			continue
+		}
+		matched = append(matched, r)
+	}
+	return matched
+}
+
+// Passage is a passage of spec markdown to extract requirements from
+type Passage struct {
+	Content  string
+	FilePath string // Relative to the spec version's directory
+	Section  string
+	URL      string
+}
+
+// Extract collects the normative statements of a spec version's passages. Fenced
+// code blocks are skipped, and a statement repeated on one page is kept once.
+func Extract(version string, passages []Passage) *Catalog {
+	catalog := &Catalog{Version: version, Requirements: []Requirement{}}
+	seen := map[string]bool{}
+	for _, passage := range passages {
+		if strings.HasPrefix(strings.TrimSpace(passage.Content), "```") {
+			continue
+		}
+		page := Page(passage.FilePath, passage.URL, version)
+		contentHash := fmt.Sprintf("%x", sha256.Sum256([]byte(passage.Content)))
+		for _, sentence := range sentences(passage.Content) {
+			level, word := strongest(sentence)
+			if level == "" {
+				continue
+			}
+			id := statementID(page, sentence)
+			if seen[id] {
+				continue
+			}
+			seen[id] = true
+			if len(sentence) > maxStatement {
+				sentence = sentence[:maxStatement] + "..."
+			}
+			catalog.Requirements = append(catalog.Requirements, Requirement{
+				ID:          id,
+				Level:       level,
+				Keyword:     word,
+				Statement:   sentence,
+				Page:        page,
+				Section:     passage.Section,
+				URL:         passage.URL,
+				Terms:       terms(sentence),
+				ContentHash: contentHash,
+			})
+		}
+	}
+	catalog.Count = len(catalog.Requirements)
+	return catalog
+}
+
+// FromEmbeddings extracts the requirements of a spec version from its embedded
+// chunks, for versions without a stored catalog
+func FromEmbeddings(spec *embedding.SpecEmbedding) *Catalog {
+	passages := make([]Passage, len(spec.Chunks))
+	for i, chunk := range spec.Chunks {
+		passages[i] = Passage{Content: chunk.Content, FilePath: chunk.FilePath, Section: chunk.Section, URL: chunk.URL}
+	}
+	catalog := Extract(spec.Version, passages)
+	catalog.Commit, catalog.CommittedAt = spec.Commit, spec.CommittedAt
+	return catalog
+}
+
+// Page returns the spec page a passage of version came from, such as
+// "basic/transports" or OverviewPage, from its file path or else its URL. It returns
+// "" when neither is known, as for passages extracted before they carried either.
+func Page(filePath, url, version string) string {
+	if filePath != "" {
+		page := strings.TrimSuffix(strings.TrimSuffix(filepath.ToSlash(filePath), ".mdx"), ".md")
+		if path.Base(page) == "index" && page != OverviewPage {
+			page = path.Dir(page)
+		}
+		return page
+	}
+	prefix := specs.PageURL(version, "")
+	if url, _, _ := strings.Cut(url, "#"); url == prefix {
+		return OverviewPage
+	} else if strings.HasPrefix(url, prefix+"/") {
+		return strings.TrimPrefix(url, prefix+"/")
+	}
+	return ""
+}
+
+// PageURL returns the published URL of a page of version
+func PageURL(version, page string) string {
+	if page == OverviewPage {
+		page = ""
+	}
+	return specs.PageURL(version, page)
+}
+
+// InPages reports whether page is one of pages or a subpage of one. The unknown
+// page "" is in none.
+func InPages(page string, pages []string) bool {
+	if page == "" {
+		return false
+	}
+	for _, p := range pages {
+		if page == p || strings.HasPrefix(page, p+"/") {
+			return true
+		}
+	}
+	return false
+}
+
+// sentences splits a passage into trimmed sentences and list items, keeping their
+// closing punctuation. Lines wrapped within a paragraph or list item are joined first.
+func sentences(content string) []string {
+	var lines []string
+	for _, line := range strings.Split(content, "\n") {
+		trimmed := strings.TrimSpace(line)
+		starts := trimmed == "" || listMarker.MatchString(trimmed) || strings.HasPrefix(trimmed, "|") || strings.HasPrefix(trimmed, "#")
+		if len(lines) > 0 && !starts && lines[len(lines)-1] != "" {
+			lines[len(lines)-1] += " " + trimmed
+			continue
+		}
+		lines = append(lines, trimmed)
+	}
+
+	var found []string
+	add := func(s string) {
+		if s = strings.TrimSpace(s); s != "" {
+			found = append(found, s)
+		}
+	}
+	for _, line := range lines {
+		line = listMarker.ReplaceAllString(line, "")
+		start := 0
+		for _, loc := range sentenceEnd.FindAllStringIndex(line, -1) {
+			add(line[start:loc[1]]) // The trailing space is trimmed
+			start = loc[1]
+		}
+		add(line[start:])
+	}
+	return found
+}
+
+// strongest returns the strongest level among a sentence's keywords and the first
+// keyword of that level, or "" if the sentence has none
+func strongest(sentence string) (Level, string) {
+	var best Level
+	var word string
+	for _, match := range keyword.FindAllString(sentence, -1) {
+		level := keywordLevels[match]
+		if best == "" || slices.Index(Levels, level) < slices.Index(Levels, best) {
+			best, word = level, match
+		}
+	}
+	return best, word
+}
+
+// statementID identifies a statement by its page and a hash of its text
+func statementID(page, statement string) string {
+	if page == "" {
+		page = "spec"
+	}
+	return fmt.Sprintf("%s:%x", page, sha256.Sum256([]byte(statement)))[:len(page)+1+12]
+}
+
+// terms returns the code spans of a statement worth matching in content. Short spans
+// like `id` or `true` appear in any protocol text.
+func terms(statement string) []string {
+	var found []string
+	for _, match := range codeSpan.FindAllStringSubmatch(statement, -1) {
+		if term := strings.ToLower(match[1]); len(term) >= 4 && !slices.Contains(found, term) {
+			found = append(found, term)
+		}
+	}
+	return found
+}
@@ -0,0 +1,57 @@
+package requirements
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// Dir is the subdirectory of a data directory holding requirement catalogs
+const Dir = "requirements"
+
+// Path returns the file holding a version's catalog in dir
+func Path(dir, version string) string {
+	return filepath.Join(dir, version+".json")
+}
+
+// Load reads a version's catalog from dir. The error wraps fs.ErrNotExist when the
+// version has no stored catalog.
+func Load(dir, version string) (*Catalog, error) {
+	data, err := os.ReadFile(Path(dir, version))
+	if err != nil {
+		return nil, err
+	}
+	var catalog Catalog
+	if err := json.Unmarshal(data, &catalog); err != nil {
+		return nil, fmt.Errorf("failed to decode requirements of %s: %w", version, err)
+	}
+	return &catalog, nil
+}
+
+// Save writes a catalog to dir through a temporary file, so readers never see a
+// partial catalog
+func Save(dir string, catalog *Catalog) error {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+	file, err := os.CreateTemp(dir, "."+catalog.Version+".json.tmp-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(file.Name())
+
+	encoder := json.NewEncoder(file)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(catalog); err != nil {
+		file.Close()
+		return err
+	}
+	if err := file.Close(); err != nil {
+		return err
+	}
+	if err := os.Chmod(file.Name(), 0644); err != nil {
+		return err
+	}
+	return os.Rename(file.Name(), Path(dir, catalog.Version))
+}
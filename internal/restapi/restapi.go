@@ -0,0 +1,374 @@
+// Package restapi serves version 1 of the fact-check REST API, which gives clients that
+// don't speak MCP, such as CI bots and web frontends, the same validation and spec
+// search as the MCP tools.
+package restapi
+
+import (
+	_ "embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/carlisia/mcp-factcheck/embedding"
+	"github.com/carlisia/mcp-factcheck/internal/cost"
+	mcpembedding "github.com/carlisia/mcp-factcheck/internal/embedding"
+	"github.com/carlisia/mcp-factcheck/internal/limits"
+	"github.com/carlisia/mcp-factcheck/pkg/logger"
+	"github.com/carlisia/mcp-factcheck/pkg/spec"
+	"github.com/carlisia/mcp-factcheck/pkg/telemetry"
+	"github.com/carlisia/mcp-factcheck/pkg/validator"
+	"go.uber.org/zap"
+)
+
+// Prefix is the path prefix of every endpoint
+const Prefix = "/v1/"
+
+// RequestIDHeader carries a request's ID. A client may set it to correlate requests
+// with its own logs; otherwise the server assigns one. It is echoed on every response.
+const RequestIDHeader = "X-Request-ID"
+
+// DefaultPageSize is the number of search results per page when a request doesn't set one
+const DefaultPageSize = 10
+
+// maxBodyBytes caps request bodies
+const maxBodyBytes = 4 << 20
+
+// clientRequestID matches request IDs accepted from clients
+var clientRequestID = regexp.MustCompile(`^[A-Za-z0-9._:-]{1,128}$`)
+
+//go:embed openapi.json
+var openAPISpec []byte
+
+// Codes of error responses
+const (
+	CodeInvalidArgument  = "invalid_argument"
+	CodeNotFound         = "not_found"
+	CodeMethodNotAllowed = "method_not_allowed"
+	CodeTooLarge         = "payload_too_large"
+	CodeBudgetExhausted  = "budget_exhausted"
+	CodeUnavailable      = "unavailable"
+	CodeInternal         = "internal"
+)
+
+// Error is the body of every error response, under "error"
+type Error struct {
+	Code      string        `json:"code"`
+	Message   string        `json:"message"`
+	RequestID string        `json:"request_id"`
+	Retryable bool          `json:"retryable"`
+	Limit     *limits.Error `json:"limit,omitempty"`  // The size limit exceeded, for payload_too_large
+	Budget    *cost.Error   `json:"budget,omitempty"` // The spend budget exhausted, for budget_exhausted
+}
+
+// ValidateRequest is the body of POST /v1/validate: a validator.Request, with source
+// code given as code instead of content
+type ValidateRequest struct {
+	validator.Request
+	Code string `json:"code,omitempty"` // Source code to validate instead of content; language defaults to go
+}
+
+// ValidateResponse is returned by POST /v1/validate
+type ValidateResponse struct {
+	RequestID   string                                `json:"request_id"`
+	SpecVersion string                                `json:"spec_version"`
+	Valid       bool                                  `json:"valid"`
+	Confidence  float64                               `json:"confidence"`
+	Findings    []validator.ValidationError           `json:"findings"` // Most severe first
+	Result      *validator.AggregatedValidationResult `json:"result"`
+}
+
+// Version describes an available spec version
+type Version struct {
+	Version        string     `json:"version"`
+	Default        bool       `json:"default,omitempty"`
+	Commit         string     `json:"commit,omitempty"`       // MCP repository commit the embeddings were built from
+	CommittedAt    *time.Time `json:"committed_at,omitempty"` // When that commit was made
+	EmbeddingModel string     `json:"embedding_model"`
+}
+
+// VersionsResponse is returned by GET /v1/spec/versions
+type VersionsResponse struct {
+	RequestID string    `json:"request_id"`
+	Versions  []Version `json:"versions"`
+	Corpora   []string  `json:"corpora"` // Documentation corpora, for the corpus option
+}
+
+// SearchRequest is the body of POST /v1/spec/search
+type SearchRequest struct {
+	Query       string `json:"query"`
+	SpecVersion string `json:"spec_version,omitempty"`
+	Corpus      string `json:"corpus,omitempty"`
+	Rerank      bool   `json:"rerank,omitempty"`
+	PageSize    int    `json:"page_size,omitempty"`  // DefaultPageSize when unset
+	PageToken   string `json:"page_token,omitempty"` // next_page_token of the previous page
+	// Leave out results whose similarity to the query is below this, 0-1
+	MinSimilarity float64 `json:"min_similarity,omitempty"`
+}
+
+// SearchResult is a spec section found by a search
+type SearchResult struct {
+	Rank       int     `json:"rank"`
+	Similarity float64 `json:"similarity"`
+	Score      float64 `json:"score"` // Ranking score: similarity blended with keyword relevance, or the reranker's relevance
+	ChunkID    string  `json:"chunk_id"`
+	Section    string  `json:"section,omitempty"`
+	URL        string  `json:"url,omitempty"`
+	Corpus     string  `json:"corpus,omitempty"` // Set for sections of a documentation corpus
+	Content    string  `json:"content"`
+}
+
+// SearchResponse is returned by POST /v1/spec/search
+type SearchResponse struct {
+	RequestID     string         `json:"request_id"`
+	SpecVersion   string         `json:"spec_version"`
+	Reranked      bool           `json:"reranked"`
+	RerankError   string         `json:"rerank_error,omitempty"` // Why reranking failed, leaving results in search order
+	Results       []SearchResult `json:"results"`
+	NextPageToken string         `json:"next_page_token,omitempty"` // Empty on the last page
+}
+
+// Handler serves the endpoints under Prefix
+type Handler struct {
+	vectorDB    *mcpembedding.VectorDB
+	generator   *embedding.Generator
+	specVersion string
+	mux         *http.ServeMux
+}
+
+// NewHandler creates a handler that validates against specVersion unless a request
+// sets another
+func NewHandler(vectorDB *mcpembedding.VectorDB, generator *embedding.Generator, specVersion string) *Handler {
+	h := &Handler{
+		vectorDB:    vectorDB,
+		generator:   generator,
+		specVersion: specVersion,
+		mux:         http.NewServeMux(),
+	}
+	h.route(http.MethodPost, Prefix+"validate", h.validate)
+	h.route(http.MethodGet, Prefix+"spec/versions", h.versions)
+	h.route(http.MethodPost, Prefix+"spec/search", h.search)
+	h.route(http.MethodGet, Prefix+"openapi.json", h.openAPI)
+	h.mux.HandleFunc(Prefix, func(w http.ResponseWriter, r *http.Request) {
+		writeError(w, r, http.StatusNotFound, Error{Code: CodeNotFound, Message: "no endpoint at " + r.URL.Path})
+	})
+	return h
+}
+
+// route serves path for method, and answers other methods with method_not_allowed
+func (h *Handler) route(method, path string, handler http.HandlerFunc) {
+	h.mux.HandleFunc(method+" "+path, handler)
+	h.mux.HandleFunc(path, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Allow", method)
+		writeError(w, r, http.StatusMethodNotAllowed, Error{Code: CodeMethodNotAllowed, Message: r.Method + " is not supported; use " + method})
+	})
+}
+
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	if id := r.Header.Get(RequestIDHeader); clientRequestID.MatchString(id) {
+		ctx = telemetry.WithGivenRequestID(ctx, id)
+	} else {
+		ctx = telemetry.WithRequestID(ctx)
+	}
+	w.Header().Set(RequestIDHeader, telemetry.GetRequestID(ctx))
+	h.mux.ServeHTTP(w, r.WithContext(ctx))
+}
+
+func (h *Handler) validate(w http.ResponseWriter, r *http.Request) {
+	var req ValidateRequest
+	if !decode(w, r, &req) {
+		return
+	}
+	switch {
+	case req.Code != "" && req.Content != "":
+		writeError(w, r, http.StatusBadRequest, Error{Code: CodeInvalidArgument, Message: "set content or code, not both"})
+		return
+	case req.Code != "":
+		req.Content = req.Code
+		if req.Language == "" {
+			req.Language = "go"
+		}
+	}
+	if strings.TrimSpace(req.Content) == "" {
+		writeError(w, r, http.StatusBadRequest, Error{Code: CodeInvalidArgument, Message: "content or code is required"})
+		return
+	}
+	if err := limits.CheckContentLength("content", len(req.Content)); err != nil {
+		writeFailure(w, r, err)
+		return
+	}
+	if req.SpecVersion == "" {
+		req.SpecVersion = h.specVersion
+	}
+
+	result, err := validator.Validate(r.Context(), h.vectorDB, h.generator, req.Request)
+	if err != nil {
+		writeFailure(w, r, err)
+		return
+	}
+	findings := result.Findings()
+	if findings == nil {
+		findings = []validator.ValidationError{}
+	}
+	validator.SortBySeverity(findings)
+	writeJSON(w, http.StatusOK, ValidateResponse{
+		RequestID:   telemetry.GetRequestID(r.Context()),
+		SpecVersion: result.SpecVersion,
+		Valid:       result.Overall.IsValid,
+		Confidence:  result.Overall.Confidence,
+		Findings:    findings,
+		Result:      result,
+	})
+}
+
+func (h *Handler) versions(w http.ResponseWriter, r *http.Request) {
+	names, err := h.vectorDB.ListVersions()
+	if err != nil {
+		writeFailure(w, r, fmt.Errorf("failed to list spec versions: %w", err))
+		return
+	}
+	response := VersionsResponse{
+		RequestID: telemetry.GetRequestID(r.Context()),
+		Versions:  make([]Version, 0, len(names)),
+		Corpora:   []string{},
+	}
+	for _, name := range names {
+		version := Version{Version: name, Default: name == h.specVersion}
+		if header, err := h.vectorDB.LoadHeader(name); err == nil {
+			version.Commit, version.CommittedAt = header.Commit, header.CommittedAt
+			version.EmbeddingModel = header.EmbeddingModel()
+		}
+		response.Versions = append(response.Versions, version)
+	}
+	if corpora, err := h.vectorDB.ListCorpora(); err == nil && corpora != nil {
+		response.Corpora = corpora
+	}
+	writeJSON(w, http.StatusOK, response)
+}
+
+func (h *Handler) search(w http.ResponseWriter, r *http.Request) {
+	var req SearchRequest
+	if !decode(w, r, &req) {
+		return
+	}
+	if strings.TrimSpace(req.Query) == "" {
+		writeError(w, r, http.StatusBadRequest, Error{Code: CodeInvalidArgument, Message: "query is required"})
+		return
+	}
+	if req.SpecVersion == "" {
+		req.SpecVersion = h.specVersion
+	}
+	if req.PageSize == 0 {
+		req.PageSize = DefaultPageSize
+	}
+	offset := 0
+	if req.PageToken != "" {
+		var err error
+		if offset, err = strconv.Atoi(req.PageToken); err != nil || offset < 0 {
+			writeError(w, r, http.StatusBadRequest, Error{Code: CodeInvalidArgument, Message: "invalid page_token"})
+			return
+		}
+	}
+
+	found, err := spec.Search(r.Context(), h.vectorDB, h.generator, spec.SearchSpecArgs{
+		Query:         req.Query,
+		SpecVersion:   req.SpecVersion,
+		TopK:          req.PageSize,
+		Rerank:        req.Rerank,
+		Corpus:        req.Corpus,
+		Offset:        offset,
+		MinSimilarity: req.MinSimilarity,
+	})
+	if err != nil {
+		writeFailure(w, r, err)
+		return
+	}
+
+	response := SearchResponse{
+		RequestID:   telemetry.GetRequestID(r.Context()),
+		SpecVersion: req.SpecVersion,
+		Reranked:    found.Reranked,
+		Results:     make([]SearchResult, len(found.Results)),
+	}
+	if found.RerankErr != nil {
+		response.RerankError = found.RerankErr.Error()
+	}
+	for i, result := range found.Results {
+		response.Results[i] = SearchResult{
+			Rank:       result.Rank,
+			Similarity: result.Similarity,
+			Score:      result.Score,
+			ChunkID:    result.Chunk.ID,
+			Section:    result.Chunk.Section,
+			URL:        result.Chunk.URL,
+			Corpus:     validator.ResultSource(result),
+			Content:    result.Chunk.Content,
+		}
+	}
+	if found.HasMore {
+		response.NextPageToken = strconv.Itoa(offset + len(found.Results))
+	}
+	writeJSON(w, http.StatusOK, response)
+}
+
+func (h *Handler) openAPI(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	_, _ = w.Write(openAPISpec)
+}
+
+// decode reads a JSON body into v, answering with an error and returning false when
+// it can't
+func decode(w http.ResponseWriter, r *http.Request, v any) bool {
+	decoder := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxBodyBytes))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(v); err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			writeError(w, r, http.StatusRequestEntityTooLarge, Error{Code: CodeTooLarge, Message: fmt.Sprintf("request body exceeds %d bytes", tooLarge.Limit)})
+			return false
+		}
+		writeError(w, r, http.StatusBadRequest, Error{Code: CodeInvalidArgument, Message: "invalid JSON body: " + err.Error()})
+		return false
+	}
+	return true
+}
+
+// writeFailure answers with the error response for err
+func writeFailure(w http.ResponseWriter, r *http.Request, err error) {
+	var (
+		requestErr *validator.RequestError
+		limitErr   *limits.Error
+		budgetErr  *cost.Error
+	)
+	switch {
+	case errors.As(err, &requestErr):
+		writeError(w, r, http.StatusBadRequest, Error{Code: CodeInvalidArgument, Message: err.Error()})
+	case errors.As(err, &limitErr):
+		writeError(w, r, http.StatusRequestEntityTooLarge, Error{Code: CodeTooLarge, Message: limitErr.Message, Retryable: limitErr.Retryable, Limit: limitErr})
+	case errors.As(err, &budgetErr):
+		writeError(w, r, http.StatusTooManyRequests, Error{Code: CodeBudgetExhausted, Message: budgetErr.Message, Retryable: budgetErr.Retryable, Budget: budgetErr})
+	case errors.Is(err, validator.ErrNoChatModel), errors.Is(err, embedding.ErrNoAPIKey):
+		writeError(w, r, http.StatusServiceUnavailable, Error{Code: CodeUnavailable, Message: err.Error()})
+	case errors.Is(err, embedding.ErrProviderUnavailable):
+		writeError(w, r, http.StatusServiceUnavailable, Error{Code: CodeUnavailable, Message: err.Error(), Retryable: true})
+	default:
+		logger.WithRequestID(r.Context()).Error("REST API request failed", zap.String("path", r.URL.Path), zap.Error(err))
+		writeError(w, r, http.StatusInternalServerError, Error{Code: CodeInternal, Message: err.Error(), Retryable: true})
+	}
+}
+
+func writeError(w http.ResponseWriter, r *http.Request, status int, body Error) {
+	body.RequestID = telemetry.GetRequestID(r.Context())
+	writeJSON(w, status, map[string]Error{"error": body})
+}
+
+func writeJSON(w http.ResponseWriter, status int, body any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(body)
+}
@@ -0,0 +1,209 @@
+// Package roots confines file access to a set of directories, the way MCP roots bound
+// what a server may read on the client's machine. Paths are opened through os.Root, so
+// ".." components and symlinks that lead outside a root are refused.
+package roots
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Set is a list of root directories
+type Set struct {
+	roots []root
+}
+
+type root struct {
+	dir      string   // Absolute path as given
+	resolved string   // dir with symlinks resolved, so absolute paths through either form match
+	fs       *os.Root // Confines opens to the directory tree
+}
+
+// Open opens each directory as a root. The directories must exist.
+func Open(dirs []string) (*Set, error) {
+	s := &Set{}
+	for _, dir := range dirs {
+		abs, err := filepath.Abs(dir)
+		if err != nil {
+			s.Close()
+			return nil, fmt.Errorf("invalid root %s: %w", dir, err)
+		}
+		resolved, err := filepath.EvalSymlinks(abs)
+		if err != nil {
+			s.Close()
+			return nil, fmt.Errorf("invalid root %s: %w", dir, err)
+		}
+		rootFS, err := os.OpenRoot(resolved)
+		if err != nil {
+			s.Close()
+			return nil, fmt.Errorf("invalid root %s: %w", dir, err)
+		}
+		s.roots = append(s.roots, root{dir: abs, resolved: resolved, fs: rootFS})
+	}
+	return s, nil
+}
+
+// Dirs returns the root directories
+func (s *Set) Dirs() []string {
+	dirs := make([]string, len(s.roots))
+	for i, r := range s.roots {
+		dirs[i] = r.dir
+	}
+	return dirs
+}
+
+// Close releases the roots
+func (s *Set) Close() error {
+	var errs []error
+	for _, r := range s.roots {
+		errs = append(errs, r.fs.Close())
+	}
+	return errors.Join(errs...)
+}
+
+// Open opens a regular file inside one of the roots and returns it with its absolute
+// path. path may be a file:// URI, an absolute path, or a path relative to a root;
+// relative paths are tried against each root in order.
+func (s *Set) Open(path string) (*os.File, string, error) {
+	if len(s.roots) == 0 {
+		return nil, "", fmt.Errorf("no roots are configured")
+	}
+	if strings.HasPrefix(path, "file:") {
+		var err error
+		if path, err = filePath(path); err != nil {
+			return nil, "", err
+		}
+	}
+	if path == "" {
+		return nil, "", fmt.Errorf("path is required")
+	}
+
+	if filepath.IsAbs(path) {
+		path = filepath.Clean(path)
+		for _, r := range s.roots {
+			for _, dir := range []string{r.dir, r.resolved} {
+				if rel, ok := within(dir, path); ok {
+					return r.open(rel)
+				}
+			}
+		}
+		return nil, "", fmt.Errorf("%s is outside the allowed roots %v", path, s.Dirs())
+	}
+
+	var firstErr error
+	for _, r := range s.roots {
+		f, abs, err := r.open(path)
+		if err == nil {
+			return f, abs, nil
+		}
+		if !errors.Is(err, fs.ErrNotExist) {
+			return nil, "", err
+		}
+		if firstErr == nil {
+			firstErr = err
+		}
+	}
+	return nil, "", firstErr
+}
+
+// Within returns the parts of the roots that are also inside one of dirs, such as the
+// roots an MCP client declares as file:// URIs: each of dirs that is inside a root,
+// and each root that is inside one of dirs. The roots bound the result, so a dir
+// reached through ".." or a symlink can't widen it. Dirs outside every root, missing,
+// not directories, or not file:// URIs are skipped. Close the result when done with it.
+func (s *Set) Within(dirs []string) (*Set, error) {
+	narrowed := &Set{}
+	seen := map[string]bool{}
+	add := func(r root, rel string) error {
+		dir := filepath.Join(r.dir, rel)
+		if seen[dir] {
+			return nil
+		}
+		// Skip dirs that are missing, aren't directories, or lead out of the root
+		// through a symlink, rather than failing for all of dirs
+		if info, err := r.fs.Stat(rel); err != nil || !info.IsDir() {
+			return nil
+		}
+		sub, err := r.fs.OpenRoot(rel)
+		if err != nil {
+			return fmt.Errorf("invalid root %s: %w", dir, err)
+		}
+		seen[dir] = true
+		narrowed.roots = append(narrowed.roots, root{dir: dir, resolved: filepath.Join(r.resolved, rel), fs: sub})
+		return nil
+	}
+
+	for _, dir := range dirs {
+		if strings.HasPrefix(dir, "file:") {
+			var err error
+			if dir, err = filePath(dir); err != nil {
+				continue
+			}
+		}
+		if !filepath.IsAbs(dir) {
+			continue
+		}
+		dir = filepath.Clean(dir)
+		for _, r := range s.roots {
+			for _, base := range []string{r.dir, r.resolved} {
+				rel, inside := within(base, dir)
+				if inside {
+					if err := add(r, rel); err != nil {
+						narrowed.Close()
+						return nil, err
+					}
+					break
+				}
+				if _, contains := within(dir, base); contains {
+					if err := add(r, "."); err != nil {
+						narrowed.Close()
+						return nil, err
+					}
+					break
+				}
+			}
+		}
+	}
+	return narrowed, nil
+}
+
+// filePath returns the local path of a file:// URI
+func filePath(uri string) (string, error) {
+	u, err := url.Parse(uri)
+	if err != nil || u.Scheme != "file" || (u.Host != "" && u.Host != "localhost") {
+		return "", fmt.Errorf("invalid file URI: %s", uri)
+	}
+	return filepath.FromSlash(u.Path), nil
+}
+
+// open opens rel, a path relative to the root, as a regular file
+func (r root) open(rel string) (*os.File, string, error) {
+	f, err := r.fs.Open(rel)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to open %s: %w", rel, err)
+	}
+	info, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return nil, "", fmt.Errorf("failed to stat %s: %w", rel, err)
+	}
+	if !info.Mode().IsRegular() {
+		f.Close()
+		return nil, "", fmt.Errorf("%s is not a regular file", rel)
+	}
+	return f, filepath.Join(r.dir, rel), nil
+}
+
+// within returns path relative to dir if path is dir or below it
+func within(dir, path string) (string, bool) {
+	rel, err := filepath.Rel(dir, path)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", false
+	}
+	return rel, true
+}
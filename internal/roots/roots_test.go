@@ -0,0 +1,83 @@
+package roots
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+func TestWithin(t *testing.T) {
+	tmp := t.TempDir()
+	base := filepath.Join(tmp, "base")
+	outside := filepath.Join(tmp, "outside")
+	for _, dir := range []string{filepath.Join(base, "project", "docs"), filepath.Join(base, "other"), outside} {
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for _, file := range []string{filepath.Join(base, "project", "docs", "spec.md"), filepath.Join(base, "other", "notes.md")} {
+		if err := os.WriteFile(file, []byte("# MCP"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.Symlink(outside, filepath.Join(base, "escape")); err != nil {
+		t.Fatal(err)
+	}
+
+	s, err := Open([]string{base})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+
+	tests := []struct {
+		name string
+		dirs []string
+		want []string
+	}{
+		{name: "dir inside the root", dirs: []string{filepath.Join(base, "project")}, want: []string{filepath.Join(base, "project")}},
+		{name: "file URI", dirs: []string{"file://" + filepath.ToSlash(filepath.Join(base, "project"))}, want: []string{filepath.Join(base, "project")}},
+		{name: "root inside the dir", dirs: []string{tmp}, want: []string{base}},
+		{name: "duplicates", dirs: []string{filepath.Join(base, "project"), filepath.Join(base, "project") + "/"}, want: []string{filepath.Join(base, "project")}},
+		{name: "dot-dot can't widen the root", dirs: []string{filepath.Join(base, "project") + "/../.."}, want: []string{base}},
+		{name: "dir outside every root", dirs: []string{outside}},
+		{name: "symlink out of the root", dirs: []string{filepath.Join(base, "escape")}},
+		{name: "missing dir", dirs: []string{filepath.Join(base, "missing")}},
+		{name: "file instead of a dir", dirs: []string{filepath.Join(base, "other", "notes.md")}},
+		{name: "relative dir", dirs: []string{"project"}},
+		{name: "other URI scheme", dirs: []string{"https://example.com" + base}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			narrowed, err := s.Within(tt.dirs)
+			if err != nil {
+				t.Fatalf("Within(%v): %v", tt.dirs, err)
+			}
+			defer narrowed.Close()
+			if got := narrowed.Dirs(); !slices.Equal(got, tt.want) {
+				t.Errorf("Within(%v) = %v, want %v", tt.dirs, got, tt.want)
+			}
+		})
+	}
+
+	// The narrowed set only opens files inside its own dirs
+	narrowed, err := s.Within([]string{filepath.Join(base, "project")})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer narrowed.Close()
+	if f, _, err := narrowed.Open(filepath.Join(base, "project", "docs", "spec.md")); err != nil {
+		t.Errorf("Open inside the narrowed root: %v", err)
+	} else {
+		f.Close()
+	}
+	if f, _, err := narrowed.Open(filepath.Join(base, "other", "notes.md")); err == nil {
+		f.Close()
+		t.Error("Open outside the narrowed root succeeded")
+	}
+	if f, _, err := narrowed.Open("docs/../../other/notes.md"); err == nil {
+		f.Close()
+		t.Error("Open through .. out of the narrowed root succeeded")
+	}
+}
@@ -0,0 +1,345 @@
+// Package sampling asks the MCP client's model for completions with
+// sampling/createMessage requests, so LLM-backed features can run on the host's model
+// without the server holding an API key. It also asks the client for its roots with
+// roots/list requests.
+//
+// mcp-go's server can't send requests to clients or see their responses, so a Client
+// sits between the stdio transport and the server: requests are written to the
+// transport's output, and the responses are picked out of its input before the server
+// reads it.
+package sampling
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// ErrUnavailable is returned when the client can't be asked for a completion: it
+// didn't advertise the sampling capability, or it isn't connected over stdio
+var ErrUnavailable = errors.New("the MCP client doesn't support sampling")
+
+// ErrRootsUnavailable is returned when the client can't be asked for its roots: it
+// didn't advertise the roots capability, or it isn't connected over stdio
+var ErrRootsUnavailable = errors.New("the MCP client doesn't support roots")
+
+// requestPrefix starts the IDs of the requests a Client sends, so their responses can
+// be told apart from the client's own requests
+const requestPrefix = "factcheck-sampling-"
+
+// Methods of the requests a Client sends
+const (
+	methodCreateMessage = "sampling/createMessage"
+	methodListRoots     = "roots/list"
+)
+
+// DefaultMaxTokens caps the length of a completion when the caller sets no limit
+const DefaultMaxTokens = 2048
+
+// Client sends sampling and roots requests over one stdio connection
+type Client struct {
+	supported      atomic.Bool
+	rootsSupported atomic.Bool
+	attached       atomic.Bool
+	nextID         atomic.Int64
+
+	writeMu sync.Mutex // Serializes writes to out, which the MCP server shares
+	out     io.Writer
+
+	mu      sync.Mutex
+	pending map[string]chan response
+}
+
+// response is a client's reply to a request
+type response struct {
+	Result json.RawMessage `json:"result"`
+	Error  *struct {
+		Code    int    `json:"code"`
+		Message string `json:"message"`
+	} `json:"error"`
+}
+
+// NewClient returns a client that can't sample until it's attached to a transport and
+// the MCP client advertises sampling
+func NewClient() *Client {
+	return &Client{pending: map[string]chan response{}}
+}
+
+// OnInitialize records whether the MCP client advertised the sampling and roots
+// capabilities. It is an mcp-go after-initialize hook.
+func (c *Client) OnInitialize(ctx context.Context, id any, req *mcp.InitializeRequest, result *mcp.InitializeResult) {
+	c.supported.Store(req.Params.Capabilities.Sampling != nil)
+	c.rootsSupported.Store(req.Params.Capabilities.Roots != nil)
+}
+
+// Available reports whether completions can be requested from the MCP client
+func (c *Client) Available() bool {
+	return c != nil && c.attached.Load() && c.supported.Load()
+}
+
+// RootsAvailable reports whether the MCP client can be asked for its roots
+func (c *Client) RootsAvailable() bool {
+	return c != nil && c.attached.Load() && c.rootsSupported.Load()
+}
+
+// Attach wraps a stdio transport. The MCP server must read from and write to the
+// returned reader and writer instead of in and out.
+func (c *Client) Attach(in io.Reader, out io.Writer) (io.Reader, io.Writer) {
+	c.out = out
+	c.attached.Store(true)
+
+	q := &queue{}
+	q.ready = sync.NewCond(&q.mu)
+	go func() {
+		reader := bufio.NewReader(in)
+		for {
+			line, err := reader.ReadBytes('\n')
+			if len(line) > 0 && !c.deliver(line) {
+				q.push(line)
+			}
+			if err != nil {
+				q.close(err)
+				c.attached.Store(false)
+				return
+			}
+		}
+	}()
+	return q, &lockedWriter{c}
+}
+
+// deliver hands a line to the request it answers, and reports whether it was such a
+// response
+func (c *Client) deliver(line []byte) bool {
+	if !bytes.Contains(line, []byte(requestPrefix)) {
+		return false
+	}
+	var message struct {
+		ID     any    `json:"id"`
+		Method string `json:"method"`
+	}
+	if err := json.Unmarshal(line, &message); err != nil || message.Method != "" {
+		return false
+	}
+	id, ok := message.ID.(string)
+	if !ok || !strings.HasPrefix(id, requestPrefix) {
+		return false
+	}
+
+	c.mu.Lock()
+	ch, ok := c.pending[id]
+	delete(c.pending, id)
+	c.mu.Unlock()
+	if ok {
+		var resp response
+		if err := json.Unmarshal(line, &resp); err != nil {
+			resp.Error = &struct {
+				Code    int    `json:"code"`
+				Message string `json:"message"`
+			}{Code: mcp.PARSE_ERROR, Message: err.Error()}
+		}
+		ch <- resp
+	}
+	return true // A late answer to a request given up on is dropped too
+}
+
+// Ask requests a completion of prompt under the system prompt from the MCP client's
+// model and returns its text. When jsonReply is set the model is told to answer with
+// a JSON object only, and any code fence around the reply is removed. Its signature
+// matches factcheck.Asker.
+func (c *Client) Ask(ctx context.Context, system, prompt string, jsonReply bool) (string, error) {
+	if jsonReply {
+		system += "\n\nReply with a single JSON object and nothing else."
+	}
+	result, err := c.CreateMessage(ctx, mcp.CreateMessageParams{
+		Messages: []mcp.SamplingMessage{{
+			Role:    mcp.RoleUser,
+			Content: mcp.NewTextContent(prompt),
+		}},
+		SystemPrompt:   system,
+		IncludeContext: "none",
+		MaxTokens:      DefaultMaxTokens,
+	})
+	if err != nil {
+		return "", err
+	}
+
+	text, err := resultText(result)
+	if err != nil {
+		return "", err
+	}
+	if jsonReply {
+		text = strings.TrimSpace(text)
+		text = strings.TrimPrefix(text, "```json")
+		text = strings.TrimPrefix(text, "```")
+		text = strings.TrimSuffix(text, "```")
+	}
+	return strings.TrimSpace(text), nil
+}
+
+// CreateMessage sends a sampling/createMessage request and waits for the client's
+// result, or until ctx is done
+func (c *Client) CreateMessage(ctx context.Context, params mcp.CreateMessageParams) (*mcp.CreateMessageResult, error) {
+	if !c.Available() {
+		return nil, ErrUnavailable
+	}
+	raw, err := c.request(ctx, methodCreateMessage, params)
+	if err != nil {
+		return nil, err
+	}
+	var result mcp.CreateMessageResult
+	if err := json.Unmarshal(raw, &result); err != nil {
+		return nil, fmt.Errorf("client returned an invalid sampling result: %w", err)
+	}
+	return &result, nil
+}
+
+// ListRoots sends a roots/list request and returns the roots the client declares, or
+// waits until ctx is done
+func (c *Client) ListRoots(ctx context.Context) ([]mcp.Root, error) {
+	if !c.RootsAvailable() {
+		return nil, ErrRootsUnavailable
+	}
+	raw, err := c.request(ctx, methodListRoots, nil)
+	if err != nil {
+		return nil, err
+	}
+	var result mcp.ListRootsResult
+	if err := json.Unmarshal(raw, &result); err != nil {
+		return nil, fmt.Errorf("client returned an invalid roots result: %w", err)
+	}
+	return result.Roots, nil
+}
+
+// request sends a request to the client and waits for its result, or until ctx is done
+func (c *Client) request(ctx context.Context, method string, params any) (json.RawMessage, error) {
+	id := fmt.Sprintf("%s%d", requestPrefix, c.nextID.Add(1))
+	message := map[string]any{
+		"jsonrpc": mcp.JSONRPC_VERSION,
+		"id":      id,
+		"method":  method,
+	}
+	if params != nil {
+		message["params"] = params
+	}
+	payload, err := json.Marshal(message)
+	if err != nil {
+		return nil, err
+	}
+
+	ch := make(chan response, 1)
+	c.mu.Lock()
+	c.pending[id] = ch
+	c.mu.Unlock()
+	defer func() {
+		c.mu.Lock()
+		delete(c.pending, id)
+		c.mu.Unlock()
+	}()
+
+	if _, err := c.write(append(payload, '\n')); err != nil {
+		return nil, fmt.Errorf("failed to send %s request: %w", method, err)
+	}
+
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case resp := <-ch:
+		if resp.Error != nil {
+			return nil, fmt.Errorf("client declined %s request: %s (code %d)", method, resp.Error.Message, resp.Error.Code)
+		}
+		if len(resp.Result) == 0 || string(resp.Result) == "null" {
+			return nil, fmt.Errorf("client returned an empty %s result", method)
+		}
+		return resp.Result, nil
+	}
+}
+
+func (c *Client) write(p []byte) (int, error) {
+	c.writeMu.Lock()
+	defer c.writeMu.Unlock()
+	return c.out.Write(p)
+}
+
+// resultText returns the text of a sampling result, whose content decodes as a map
+func resultText(result *mcp.CreateMessageResult) (string, error) {
+	switch content := result.Content.(type) {
+	case map[string]any:
+		if content["type"] == "text" {
+			if text, ok := content["text"].(string); ok {
+				return text, nil
+			}
+		}
+		return "", fmt.Errorf("client returned %v content instead of text", content["type"])
+	case mcp.TextContent:
+		return content.Text, nil
+	}
+	return "", errors.New("client returned no text content")
+}
+
+// lockedWriter writes the MCP server's messages between the client's requests
+type lockedWriter struct {
+	c *Client
+}
+
+func (w *lockedWriter) Write(p []byte) (int, error) {
+	return w.c.write(p)
+}
+
+// queue buffers the input lines meant for the MCP server. It is unbounded so that
+// reading the response to a sampling request never waits on the server, which is
+// busy with the tool call that sent it.
+type queue struct {
+	mu    sync.Mutex
+	ready *sync.Cond
+	buf   bytes.Buffer
+	err   error
+}
+
+func (q *queue) push(line []byte) {
+	q.mu.Lock()
+	q.buf.Write(line)
+	q.mu.Unlock()
+	q.ready.Signal()
+}
+
+func (q *queue) close(err error) {
+	q.mu.Lock()
+	q.err = err
+	q.mu.Unlock()
+	q.ready.Broadcast()
+}
+
+func (q *queue) Read(p []byte) (int, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	for q.buf.Len() == 0 && q.err == nil {
+		q.ready.Wait()
+	}
+	if q.buf.Len() > 0 {
+		return q.buf.Read(p)
+	}
+	return 0, q.err
+}
+
+type clientKey struct{}
+
+// WithClient makes c available to tool handlers run with ctx
+func WithClient(ctx context.Context, c *Client) context.Context {
+	return context.WithValue(ctx, clientKey{}, c)
+}
+
+// FromContext returns the client set with WithClient, or nil. A nil client is never
+// Available.
+func FromContext(ctx context.Context) *Client {
+	c, _ := ctx.Value(clientKey{}).(*Client)
+	return c
+}
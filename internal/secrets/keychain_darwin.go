@@ -0,0 +1,17 @@
+//go:build darwin
+
+package secrets
+
+import (
+	"os/exec"
+	"strings"
+)
+
+// lookupKeychain reads a generic password from the login keychain
+func lookupKeychain(name string) (string, error) {
+	out, err := exec.Command("security", "find-generic-password", "-s", KeychainService, "-a", name, "-w").Output()
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(out)), nil
+}
@@ -0,0 +1,8 @@
+//go:build !darwin
+
+package secrets
+
+// lookupKeychain is only supported on macOS
+func lookupKeychain(name string) (string, error) {
+	return "", ErrNotFound
+}
@@ -0,0 +1,75 @@
+package secrets
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// ErrNotFound is returned when a secret is not available from any source
+var ErrNotFound = errors.New("secret not found")
+
+// DirEnvVar overrides the directory searched for mounted secret files
+const DirEnvVar = "MCP_FACTCHECK_SECRETS_DIR"
+
+// DefaultDir is where Docker and Kubernetes mount secrets by convention
+const DefaultDir = "/run/secrets"
+
+// KeychainService is the macOS Keychain service name secrets are stored under
+const KeychainService = "mcp-factcheck"
+
+// Lookup resolves a secret by name, checking in order:
+//  1. the environment variable NAME
+//  2. the file named by the environment variable NAME_FILE
+//  3. a mounted secret file NAME or name in $MCP_FACTCHECK_SECRETS_DIR (default /run/secrets)
+//  4. the macOS Keychain (service "mcp-factcheck", account NAME)
+func Lookup(name string) (string, error) {
+	if value := os.Getenv(name); value != "" {
+		return value, nil
+	}
+
+	if path := os.Getenv(name + "_FILE"); path != "" {
+		value, err := readSecretFile(path)
+		if err != nil {
+			return "", fmt.Errorf("failed to read %s_FILE: %w", name, err)
+		}
+		return value, nil
+	}
+
+	dir := os.Getenv(DirEnvVar)
+	if dir == "" {
+		dir = DefaultDir
+	}
+	for _, file := range []string{name, strings.ToLower(name)} {
+		value, err := readSecretFile(filepath.Join(dir, file))
+		if err == nil {
+			return value, nil
+		}
+	}
+
+	if value, err := lookupKeychain(name); err == nil && value != "" {
+		return value, nil
+	}
+
+	return "", fmt.Errorf("%w: %s", ErrNotFound, name)
+}
+
+// Sources describes where Lookup searches for a secret, for use in error messages
+func Sources(name string) string {
+	return fmt.Sprintf("$%s, $%s_FILE, %s/%s, or the macOS Keychain (service %q, account %q)",
+		name, name, DefaultDir, strings.ToLower(name), KeychainService, name)
+}
+
+func readSecretFile(path string) (string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+	value := strings.TrimSpace(string(data))
+	if value == "" {
+		return "", fmt.Errorf("secret file %s is empty", path)
+	}
+	return value, nil
+}
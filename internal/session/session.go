@@ -0,0 +1,96 @@
+// Package session remembers, for each MCP session, the documents validated in it, so
+// a client re-checking a document it already validated gets the earlier result back
+// when nothing changed, and only the edited sections are checked when something did.
+package session
+
+import (
+	"sync"
+	"time"
+
+	"github.com/carlisia/mcp-factcheck/pkg/validator"
+)
+
+// MaxDocuments caps the documents remembered per session; the least recently
+// validated is forgotten first. Remembered results hold chunk embeddings, so this
+// bounds each session's memory.
+const MaxDocuments = 20
+
+// IdleTimeout is how long a session's documents are kept after its last validation,
+// for transports that don't report when a session ends
+const IdleTimeout = time.Hour
+
+// Store holds the documents of every session
+type Store struct {
+	mu       sync.Mutex
+	sessions map[string]*Documents
+}
+
+// NewStore creates an empty store
+func NewStore() *Store {
+	return &Store{sessions: map[string]*Documents{}}
+}
+
+// For returns the documents of the session with id, creating them if needed
+func (s *Store) For(id string) *Documents {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := time.Now()
+	for key, docs := range s.sessions {
+		if key != id && docs.idle(now) {
+			delete(s.sessions, key)
+		}
+	}
+	docs, ok := s.sessions[id]
+	if !ok {
+		docs = &Documents{docs: map[string]validator.DocumentState{}, used: now}
+		s.sessions[id] = docs
+	}
+	return docs
+}
+
+// Remove forgets a session that ended
+func (s *Store) Remove(id string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.sessions, id)
+}
+
+// Documents are the documents validated in one session. It implements
+// validator.DocumentMemory.
+type Documents struct {
+	mu   sync.Mutex
+	docs map[string]validator.DocumentState
+	used time.Time
+}
+
+// Recall returns the last validation of the named document
+func (d *Documents) Recall(name string) (validator.DocumentState, bool) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.used = time.Now()
+	state, ok := d.docs[name]
+	return state, ok
+}
+
+// Remember records the latest validation of the named document
+func (d *Documents) Remember(name string, state validator.DocumentState) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.used = time.Now()
+	d.docs[name] = state
+	for len(d.docs) > MaxDocuments {
+		var oldest string
+		for n, s := range d.docs {
+			if oldest == "" || s.ValidatedAt.Before(d.docs[oldest].ValidatedAt) {
+				oldest = n
+			}
+		}
+		delete(d.docs, oldest)
+	}
+}
+
+func (d *Documents) idle(now time.Time) bool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return now.Sub(d.used) > IdleTimeout
+}
@@ -0,0 +1,315 @@
+package slackbot
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/carlisia/mcp-factcheck/embedding"
+	mcpembedding "github.com/carlisia/mcp-factcheck/internal/embedding"
+	"github.com/carlisia/mcp-factcheck/internal/fetch"
+	"github.com/carlisia/mcp-factcheck/pkg/logger"
+	"github.com/carlisia/mcp-factcheck/pkg/validator"
+	"go.uber.org/zap"
+)
+
+// maxRequestAge is how old a signed request may be before it is treated as a replay
+const maxRequestAge = 5 * time.Minute
+
+// maxListedFindings caps how many findings are listed in one reply
+const maxListedFindings = 10
+
+const postMessageURL = "https://slack.com/api/chat.postMessage"
+
+// Config holds Slack credentials and validation options
+type Config struct {
+	SpecVersion   string
+	SigningSecret string
+	BotToken      string
+	Timeout       time.Duration // Maximum time spent on one fact-check
+}
+
+// Bot answers /factcheck slash commands by validating text or a linked document
+type Bot struct {
+	vectorDB   *mcpembedding.VectorDB
+	generator  *embedding.Generator
+	config     Config
+	httpClient *http.Client
+}
+
+// New creates a Slack bot
+func New(vectorDB *mcpembedding.VectorDB, generator *embedding.Generator, config Config) *Bot {
+	if config.Timeout <= 0 {
+		config.Timeout = 5 * time.Minute
+	}
+	return &Bot{
+		vectorDB:   vectorDB,
+		generator:  generator,
+		config:     config,
+		httpClient: &http.Client{Timeout: 30 * time.Second},
+	}
+}
+
+// ServeHTTP handles a slash command request. Slack requires an answer within three seconds,
+// so the command is acknowledged immediately and the result is posted when validation finishes.
+func (b *Bot) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	log := logger.Get()
+
+	body, err := io.ReadAll(io.LimitReader(r.Body, 1<<20))
+	if err != nil {
+		http.Error(w, "failed to read request", http.StatusBadRequest)
+		return
+	}
+	if err := VerifySignature(b.config.SigningSecret, r.Header, body, time.Now()); err != nil {
+		log.Warn("Rejected Slack request", zap.Error(err))
+		http.Error(w, "invalid signature", http.StatusUnauthorized)
+		return
+	}
+
+	form, err := url.ParseQuery(string(body))
+	if err != nil {
+		http.Error(w, "invalid form body", http.StatusBadRequest)
+		return
+	}
+
+	input := strings.TrimSpace(form.Get("text"))
+	if input == "" {
+		writeJSON(w, message{ResponseType: "ephemeral", Text: "Usage: `/factcheck <text or link>`"})
+		return
+	}
+
+	cmd := command{
+		input:       input,
+		channelID:   form.Get("channel_id"),
+		userID:      form.Get("user_id"),
+		responseURL: form.Get("response_url"),
+	}
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), b.config.Timeout)
+		defer cancel()
+		if err := b.run(ctx, cmd); err != nil {
+			log.Error("Slack fact-check failed", zap.String("channel", cmd.channelID), zap.Error(err))
+			_ = b.respond(ctx, cmd.responseURL, message{ResponseType: "ephemeral", Text: "Fact-check failed: " + err.Error()})
+		}
+	}()
+
+	writeJSON(w, message{ResponseType: "ephemeral", Text: "Fact-checking against MCP spec " + b.config.SpecVersion + "…"})
+}
+
+type command struct {
+	input       string
+	channelID   string
+	userID      string
+	responseURL string
+}
+
+// run validates the command input and posts the summary as a thread reply under a short parent message
+func (b *Bot) run(ctx context.Context, cmd command) error {
+	content := cmd.input
+	source := "text"
+	if link := unwrapLink(cmd.input); fetch.IsURL(link) {
+		// Findings quote the fetched text back to the channel, so internal URLs are refused
+		doc, err := fetch.GetPublic(ctx, link)
+		if err != nil {
+			return err
+		}
+		content = doc.Text
+		source = link
+	}
+
+	result, err := validator.Validate(ctx, b.vectorDB, b.generator, validator.Request{Content: content, SpecVersion: b.config.SpecVersion, Chunked: true})
+	if err != nil {
+		return err
+	}
+	findings := result.Findings()
+	validator.SortBySeverity(findings)
+	summary := FormatSummary(findings, b.config.SpecVersion)
+
+	if b.config.BotToken == "" {
+		// Without a bot token the reply can only go to the channel, not a thread
+		return b.respond(ctx, cmd.responseURL, message{ResponseType: "in_channel", Text: summary})
+	}
+
+	parent, err := b.postMessage(ctx, message{
+		Channel: cmd.channelID,
+		Text:    fmt.Sprintf("<@%s> asked for a fact-check of %s: %s", cmd.userID, source, headline(findings)),
+	})
+	if err != nil {
+		return err
+	}
+	_, err = b.postMessage(ctx, message{Channel: cmd.channelID, ThreadTS: parent, Text: summary})
+	return err
+}
+
+// FormatSummary renders severity-sorted findings with spec citations as Slack mrkdwn
+func FormatSummary(findings []validator.ValidationError, specVersion string) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "*MCP fact-check (spec %s)*\n", specVersion)
+	if len(findings) == 0 {
+		b.WriteString("No issues found.")
+		return b.String()
+	}
+
+	for i, f := range findings {
+		if i == maxListedFindings {
+			fmt.Fprintf(&b, "\n_…and %d more_", len(findings)-maxListedFindings)
+			break
+		}
+		fmt.Fprintf(&b, "\n%s *%s*", severityEmoji(f.Severity), f.Message)
+		if f.LineNumber > 0 {
+			fmt.Fprintf(&b, " (line %d)", f.LineNumber)
+		}
+		b.WriteString("\n")
+		if f.Found != "" {
+			fmt.Fprintf(&b, "> %s\n", strings.ReplaceAll(f.Found, "\n", " "))
+		}
+		if f.SpecSection != "" {
+			fmt.Fprintf(&b, "📖 Spec: %s\n", f.SpecSection)
+		}
+	}
+	return b.String()
+}
+
+func headline(findings []validator.ValidationError) string {
+	var critical, warning int
+	for _, f := range findings {
+		switch f.Severity {
+		case validator.SeverityCritical:
+			critical++
+		case validator.SeverityWarning:
+			warning++
+		}
+	}
+	if len(findings) == 0 {
+		return "no issues found"
+	}
+	return fmt.Sprintf("%d critical, %d warning, %d other", critical, warning, len(findings)-critical-warning)
+}
+
+func severityEmoji(severity string) string {
+	switch severity {
+	case validator.SeverityCritical:
+		return "❌"
+	case validator.SeverityWarning:
+		return "⚠️"
+	default:
+		return "💡"
+	}
+}
+
+// unwrapLink strips Slack's <url> or <url|label> link formatting
+func unwrapLink(s string) string {
+	if strings.HasPrefix(s, "<") && strings.HasSuffix(s, ">") {
+		s = strings.TrimSuffix(strings.TrimPrefix(s, "<"), ">")
+		if i := strings.Index(s, "|"); i >= 0 {
+			s = s[:i]
+		}
+	}
+	return s
+}
+
+// VerifySignature checks Slack's v0 request signature and rejects stale timestamps
+func VerifySignature(secret string, header http.Header, body []byte, now time.Time) error {
+	timestamp := header.Get("X-Slack-Request-Timestamp")
+	signature := header.Get("X-Slack-Signature")
+	if timestamp == "" || signature == "" {
+		return fmt.Errorf("missing signature headers")
+	}
+
+	ts, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid timestamp: %w", err)
+	}
+	if age := now.Sub(time.Unix(ts, 0)); age > maxRequestAge || age < -maxRequestAge {
+		return fmt.Errorf("request timestamp outside the allowed window")
+	}
+
+	mac := hmac.New(sha256.New, []byte(secret))
+	fmt.Fprintf(mac, "v0:%s:", timestamp)
+	mac.Write(body)
+	expected := "v0=" + hex.EncodeToString(mac.Sum(nil))
+	if !hmac.Equal([]byte(expected), []byte(signature)) {
+		return fmt.Errorf("signature mismatch")
+	}
+	return nil
+}
+
+type message struct {
+	Channel      string `json:"channel,omitempty"`
+	ThreadTS     string `json:"thread_ts,omitempty"`
+	ResponseType string `json:"response_type,omitempty"`
+	Text         string `json:"text"`
+}
+
+// postMessage calls chat.postMessage and returns the timestamp of the new message
+func (b *Bot) postMessage(ctx context.Context, msg message) (string, error) {
+	resp, err := b.post(ctx, postMessageURL, msg, b.config.BotToken)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	var result struct {
+		OK    bool   `json:"ok"`
+		Error string `json:"error"`
+		TS    string `json:"ts"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return "", fmt.Errorf("failed to decode chat.postMessage response: %w", err)
+	}
+	if !result.OK {
+		return "", fmt.Errorf("chat.postMessage failed: %s", result.Error)
+	}
+	return result.TS, nil
+}
+
+// respond posts to the slash command's response_url
+func (b *Bot) respond(ctx context.Context, responseURL string, msg message) error {
+	if responseURL == "" {
+		return fmt.Errorf("no response_url to reply to")
+	}
+	resp, err := b.post(ctx, responseURL, msg, "")
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("response_url returned HTTP %d", resp.StatusCode)
+	}
+	return nil
+}
+
+func (b *Bot) post(ctx context.Context, url string, msg message, token string) (*http.Response, error) {
+	payload, err := json.Marshal(msg)
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(payload))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json; charset=utf-8")
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	resp, err := b.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to call Slack: %w", err)
+	}
+	return resp, nil
+}
+
+func writeJSON(w http.ResponseWriter, msg message) {
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(msg)
+}
@@ -0,0 +1,68 @@
+package slackbot
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestVerifySignature(t *testing.T) {
+	const secret = "8f742231b10e8888abcd99yyyzzz85a5"
+	body := []byte("token=xyz&team_id=T1&command=%2Ffactcheck&text=MCP+servers+expose+tools")
+	now := time.Unix(1_700_000_000, 0)
+	sign := func(secret string, ts int64, body []byte) string {
+		mac := hmac.New(sha256.New, []byte(secret))
+		mac.Write([]byte("v0:" + strconv.FormatInt(ts, 10) + ":"))
+		mac.Write(body)
+		return "v0=" + hex.EncodeToString(mac.Sum(nil))
+	}
+
+	tests := []struct {
+		name      string
+		timestamp string
+		signature string
+		body      []byte
+		wantErr   string // Text the error must contain, or empty for a valid request
+	}{
+		{name: "valid", timestamp: strconv.FormatInt(now.Unix(), 10), signature: sign(secret, now.Unix(), body)},
+		{name: "within the window", timestamp: strconv.FormatInt(now.Unix()-240, 10), signature: sign(secret, now.Unix()-240, body)},
+		{name: "missing signature", timestamp: strconv.FormatInt(now.Unix(), 10), wantErr: "missing signature headers"},
+		{name: "missing timestamp", signature: sign(secret, now.Unix(), body), wantErr: "missing signature headers"},
+		{name: "invalid timestamp", timestamp: "yesterday", signature: "v0=00", wantErr: "invalid timestamp"},
+		{name: "replayed", timestamp: strconv.FormatInt(now.Unix()-600, 10), signature: sign(secret, now.Unix()-600, body), wantErr: "outside the allowed window"},
+		{name: "from the future", timestamp: strconv.FormatInt(now.Unix()+600, 10), signature: sign(secret, now.Unix()+600, body), wantErr: "outside the allowed window"},
+		{name: "wrong secret", timestamp: strconv.FormatInt(now.Unix(), 10), signature: sign("other", now.Unix(), body), wantErr: "signature mismatch"},
+		{name: "tampered body", timestamp: strconv.FormatInt(now.Unix(), 10), signature: sign(secret, now.Unix(), body), body: []byte("token=xyz&text=anything"), wantErr: "signature mismatch"},
+		{name: "signature for another timestamp", timestamp: strconv.FormatInt(now.Unix(), 10), signature: sign(secret, now.Unix()-1, body), wantErr: "signature mismatch"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			header := http.Header{}
+			if tt.timestamp != "" {
+				header.Set("X-Slack-Request-Timestamp", tt.timestamp)
+			}
+			if tt.signature != "" {
+				header.Set("X-Slack-Signature", tt.signature)
+			}
+			sent := body
+			if tt.body != nil {
+				sent = tt.body
+			}
+			err := VerifySignature(secret, header, sent, now)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("VerifySignature: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("VerifySignature err = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
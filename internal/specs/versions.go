@@ -11,4 +11,16 @@ const DefaultSpecVersion = "2025-06-18"
 // IsValidSpecVersion checks if the provided version is supported
 func IsValidSpecVersion(version string) bool {
 	return slices.Contains(ValidSpecVersions, version)
-}
\ No newline at end of file
+}
+
+// SiteURL is where the MCP specification is published
+const SiteURL = "https://modelcontextprotocol.io/specification"
+
+// PageURL returns the published URL of a page of a spec version, such as
+// "basic/lifecycle#initialization". An empty page is the version's overview.
+func PageURL(version, page string) string {
+	if page == "" {
+		return SiteURL + "/" + version
+	}
+	return SiteURL + "/" + version + "/" + page
+}
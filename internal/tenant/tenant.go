@@ -0,0 +1,195 @@
+package tenant
+
+import (
+	"context"
+	"crypto/subtle"
+	"fmt"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"github.com/carlisia/mcp-factcheck/embedding"
+	mcpembedding "github.com/carlisia/mcp-factcheck/internal/embedding"
+	"github.com/carlisia/mcp-factcheck/internal/secrets"
+	"github.com/carlisia/mcp-factcheck/internal/specs"
+)
+
+// Config describes one tenant in the server config file
+type Config struct {
+	Name               string `json:"name"`
+	DataDir            string `json:"data_dir"`
+	DefaultSpecVersion string `json:"default_spec_version,omitempty"`
+	APIKeySecret       string `json:"api_key_secret,omitempty"` // Secret name holding this tenant's OpenAI key (default OPENAI_API_KEY)
+	TokenSecret        string `json:"token_secret,omitempty"`   // Secret name holding the bearer token that selects this tenant
+}
+
+// Tenant is an isolated project with its own spec data, credentials, and usage counters
+type Tenant struct {
+	Name               string
+	DefaultSpecVersion string
+	VectorDB           *mcpembedding.VectorDB
+	Generator          *embedding.Generator
+	Usage              *Usage
+
+	token string
+}
+
+// Usage accumulates per-tenant tool call counts
+type Usage struct {
+	mu     sync.Mutex
+	calls  map[string]int64
+	errors atomic.Int64
+}
+
+// UsageSnapshot is a point-in-time copy of a tenant's usage
+type UsageSnapshot struct {
+	Calls  map[string]int64 `json:"calls"`
+	Errors int64            `json:"errors"`
+}
+
+// Record counts one call to toolName
+func (u *Usage) Record(toolName string, err error) {
+	u.mu.Lock()
+	u.calls[toolName]++
+	u.mu.Unlock()
+	if err != nil {
+		u.errors.Add(1)
+	}
+}
+
+// Snapshot returns a copy of the current counters
+func (u *Usage) Snapshot() UsageSnapshot {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	calls := make(map[string]int64, len(u.calls))
+	for k, v := range u.calls {
+		calls[k] = v
+	}
+	return UsageSnapshot{Calls: calls, Errors: u.errors.Load()}
+}
+
+// New builds a tenant from its config, resolving secrets and creating its backends
+func New(cfg Config) (*Tenant, error) {
+	if cfg.Name == "" {
+		return nil, fmt.Errorf("tenant name is required")
+	}
+	if cfg.DataDir == "" {
+		return nil, fmt.Errorf("tenant %s: data_dir is required", cfg.Name)
+	}
+	if cfg.DefaultSpecVersion != "" && !specs.IsValidSpecVersion(cfg.DefaultSpecVersion) {
+		return nil, fmt.Errorf("tenant %s: invalid default_spec_version %s", cfg.Name, cfg.DefaultSpecVersion)
+	}
+
+	dataDir, err := filepath.Abs(cfg.DataDir)
+	if err != nil {
+		return nil, fmt.Errorf("tenant %s: failed to resolve data_dir: %w", cfg.Name, err)
+	}
+
+	keySecret := cfg.APIKeySecret
+	if keySecret == "" {
+		keySecret = "OPENAI_API_KEY"
+	}
+	apiKey, err := secrets.Lookup(keySecret)
+	if err != nil {
+		return nil, fmt.Errorf("tenant %s: %w", cfg.Name, err)
+	}
+	generator, err := embedding.NewGeneratorWithKey(apiKey)
+	if err != nil {
+		return nil, fmt.Errorf("tenant %s: failed to create embedding generator: %w", cfg.Name, err)
+	}
+
+	var token string
+	if cfg.TokenSecret != "" {
+		token, err = secrets.Lookup(cfg.TokenSecret)
+		if err != nil {
+			return nil, fmt.Errorf("tenant %s: %w", cfg.Name, err)
+		}
+	}
+
+	return &Tenant{
+		Name:               cfg.Name,
+		DefaultSpecVersion: cfg.DefaultSpecVersion,
+		VectorDB:           mcpembedding.NewVectorDB(dataDir),
+		Generator:          generator,
+		Usage:              &Usage{calls: map[string]int64{}},
+		token:              token,
+	}, nil
+}
+
+// Registry holds all configured tenants
+type Registry struct {
+	tenants map[string]*Tenant
+	order   []string
+}
+
+// NewRegistry creates tenants for every config entry
+func NewRegistry(configs []Config) (*Registry, error) {
+	r := &Registry{tenants: map[string]*Tenant{}}
+	for _, cfg := range configs {
+		if _, exists := r.tenants[cfg.Name]; exists {
+			return nil, fmt.Errorf("duplicate tenant name: %s", cfg.Name)
+		}
+		t, err := New(cfg)
+		if err != nil {
+			return nil, err
+		}
+		r.tenants[t.Name] = t
+		r.order = append(r.order, t.Name)
+	}
+	return r, nil
+}
+
+// Get returns a tenant by name
+func (r *Registry) Get(name string) (*Tenant, bool) {
+	t, ok := r.tenants[name]
+	return t, ok
+}
+
+// All returns tenants in config order
+func (r *Registry) All() []*Tenant {
+	all := make([]*Tenant, 0, len(r.order))
+	for _, name := range r.order {
+		all = append(all, r.tenants[name])
+	}
+	return all
+}
+
+// Authenticate returns the tenant whose bearer token matches
+func (r *Registry) Authenticate(token string) (*Tenant, bool) {
+	if token == "" {
+		return nil, false
+	}
+	for _, t := range r.tenants {
+		if t.token != "" && subtle.ConstantTimeCompare([]byte(t.token), []byte(token)) == 1 {
+			return t, true
+		}
+	}
+	return nil, false
+}
+
+// ContextFromRequest attaches the tenant selected by the request's bearer token, if any
+func (r *Registry) ContextFromRequest(ctx context.Context, req *http.Request) context.Context {
+	token, ok := strings.CutPrefix(req.Header.Get("Authorization"), "Bearer ")
+	if !ok {
+		return ctx
+	}
+	if t, ok := r.Authenticate(strings.TrimSpace(token)); ok {
+		return WithTenant(ctx, t)
+	}
+	return ctx
+}
+
+type contextKey struct{}
+
+// WithTenant stores the tenant in the context
+func WithTenant(ctx context.Context, t *Tenant) context.Context {
+	return context.WithValue(ctx, contextKey{}, t)
+}
+
+// FromContext returns the tenant for the current request, or nil
+func FromContext(ctx context.Context) *Tenant {
+	t, _ := ctx.Value(contextKey{}).(*Tenant)
+	return t
+}
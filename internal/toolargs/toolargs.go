@@ -0,0 +1,404 @@
+// Package toolargs checks tool call arguments against the tool's input schema before
+// the handler sees them, so every tool rejects misspelled keys and wrong types the same
+// way instead of silently ignoring them.
+//
+// Arguments are coerced where the intent is unambiguous: numeric strings become
+// numbers, "true" and "false" become booleans, numbers and booleans become strings,
+// and a single value where an array is expected becomes a one-element array. JSON
+// nulls are treated as absent.
+package toolargs
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/carlisia/mcp-factcheck/internal/toolerr"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// Schema is a compiled tool input schema
+type Schema struct {
+	tool string
+	root *node
+}
+
+// node is the subset of JSON Schema tool input schemas use
+type node struct {
+	Type                 string           `json:"type"`
+	Properties           map[string]*node `json:"properties"`
+	Required             []string         `json:"required"`
+	Items                *node            `json:"items"`
+	Enum                 []any            `json:"enum"`
+	Minimum              *float64         `json:"minimum"`
+	Maximum              *float64         `json:"maximum"`
+	MinItems             *int             `json:"minItems"`
+	MaxItems             *int             `json:"maxItems"`
+	AdditionalProperties json.RawMessage  `json:"additionalProperties"`
+
+	// Parsed from AdditionalProperties: whether keys other than Properties are
+	// accepted, and the schema their values must match, if any
+	openKeys bool
+	extra    *node
+}
+
+// Compile reads the input schema of tool, whether given as a raw schema or as fields
+func Compile(tool mcp.Tool) (*Schema, error) {
+	raw, err := json.Marshal(tool)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read input schema of %s: %w", tool.Name, err)
+	}
+	var doc struct {
+		InputSchema *node `json:"inputSchema"`
+	}
+	if err := json.Unmarshal(raw, &doc); err != nil {
+		return nil, fmt.Errorf("failed to read input schema of %s: %w", tool.Name, err)
+	}
+	if doc.InputSchema == nil {
+		doc.InputSchema = &node{Type: "object"}
+	}
+	if err := doc.InputSchema.compile(); err != nil {
+		return nil, fmt.Errorf("invalid input schema of %s: %w", tool.Name, err)
+	}
+	return &Schema{tool: tool.Name, root: doc.InputSchema}, nil
+}
+
+func (n *node) compile() error {
+	switch string(n.AdditionalProperties) {
+	case "", "false":
+	case "true":
+		n.openKeys = true
+	default:
+		n.extra = &node{}
+		if err := json.Unmarshal(n.AdditionalProperties, n.extra); err != nil {
+			return fmt.Errorf("additionalProperties: %w", err)
+		}
+		n.openKeys = true
+	}
+	// An object schema without properties doesn't restrict its keys
+	if n.Type == "object" && n.Properties == nil {
+		n.openKeys = true
+	}
+	for _, child := range n.Properties {
+		if err := child.compile(); err != nil {
+			return err
+		}
+	}
+	for _, child := range []*node{n.Items, n.extra} {
+		if child != nil {
+			if err := child.compile(); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// Accepts reports whether the tool takes an argument named key
+func (s *Schema) Accepts(key string) bool {
+	_, declared := s.root.Properties[key]
+	return declared || s.root.openKeys
+}
+
+// Details are the arguments a tool accepts, reported with invalid_args errors
+type Details struct {
+	Accepted []string `json:"accepted_arguments"`
+	Required []string `json:"required_arguments,omitempty"`
+}
+
+// Decode checks args against the schema and returns them coerced to the declared
+// types, leaving args itself unchanged. Nil arguments are an empty object. Every
+// problem found is reported in a single invalid_args error whose hint lists the
+// accepted arguments.
+func (s *Schema) Decode(args any) (map[string]any, error) {
+	if args == nil {
+		args = map[string]any{}
+	}
+	d := &decoder{}
+	value := d.value("", args, s.root)
+	if len(d.problems) > 0 {
+		failure := toolerr.InvalidArgs("invalid arguments for %s: %s", s.tool, strings.Join(d.problems, "; "))
+		details := Details{Accepted: s.root.keys(), Required: s.root.Required}
+		failure.Hint = "Accepted arguments are " + s.root.describeKeys() + "."
+		failure.Details = details
+		return nil, failure
+	}
+	params, _ := value.(map[string]any)
+	return params, nil
+}
+
+type decoder struct {
+	problems []string
+}
+
+func (d *decoder) fail(format string, a ...any) {
+	d.problems = append(d.problems, fmt.Sprintf(format, a...))
+}
+
+// value checks v against n and returns it coerced; path names it in problems
+func (d *decoder) value(path string, v any, n *node) any {
+	name := path
+	if name == "" {
+		name = "arguments"
+	}
+	problems := len(d.problems)
+	v = d.typed(name, path, v, n)
+	// Compare to the enum after coercion, unless the value is already wrong
+	if len(n.Enum) > 0 && len(d.problems) == problems && !inEnum(n.Enum, v) {
+		d.fail("%s must be one of %s, got %s", name, describeEnum(n.Enum), describe(v))
+	}
+	return v
+}
+
+func (d *decoder) typed(name, path string, v any, n *node) any {
+	switch n.Type {
+	case "object":
+		return d.object(path, name, v, n)
+	case "array":
+		return d.array(path, name, v, n)
+	case "string":
+		switch v := v.(type) {
+		case string:
+			return v
+		case float64:
+			return strconv.FormatFloat(v, 'f', -1, 64)
+		case bool:
+			return strconv.FormatBool(v)
+		}
+		d.fail("%s must be a string, got %s", name, describe(v))
+	case "boolean":
+		switch v := v.(type) {
+		case bool:
+			return v
+		case string:
+			if b, err := strconv.ParseBool(strings.TrimSpace(v)); err == nil {
+				return b
+			}
+		}
+		d.fail("%s must be a boolean, got %s", name, describe(v))
+	case "number", "integer":
+		number, ok := v.(float64)
+		if str, isString := v.(string); isString {
+			parsed, err := strconv.ParseFloat(strings.TrimSpace(str), 64)
+			number, ok = parsed, err == nil && !math.IsInf(parsed, 0) && !math.IsNaN(parsed)
+		}
+		kind := "a number"
+		if n.Type == "integer" {
+			kind = "an integer"
+			ok = ok && number == math.Trunc(number)
+		}
+		if !ok {
+			d.fail("%s must be %s, got %s", name, kind, describe(v))
+			return v
+		}
+		if n.Minimum != nil && number < *n.Minimum {
+			d.fail("%s must be at least %s, got %s", name, formatNumber(*n.Minimum), formatNumber(number))
+		}
+		if n.Maximum != nil && number > *n.Maximum {
+			d.fail("%s must be at most %s, got %s", name, formatNumber(*n.Maximum), formatNumber(number))
+		}
+		// Handlers read numbers as float64, as encoding/json decodes them
+		return number
+	default:
+		return v
+	}
+	return v
+}
+
+func (d *decoder) object(path, name string, v any, n *node) any {
+	fields, ok := v.(map[string]any)
+	if !ok {
+		d.fail("%s must be an object, got %s", name, describe(v))
+		return v
+	}
+	out := make(map[string]any, len(fields))
+	keys := make([]string, 0, len(fields))
+	for key := range fields {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+	for _, key := range keys {
+		value := fields[key]
+		if value == nil {
+			continue
+		}
+		child := n.Properties[key]
+		if child == nil {
+			child = n.extra
+		}
+		if child == nil && !n.openKeys {
+			msg := fmt.Sprintf("unknown argument %s", join(path, key))
+			if suggestion := n.suggest(key); suggestion != "" {
+				msg += fmt.Sprintf(" (did you mean %s?)", suggestion)
+			} else if path != "" {
+				msg += fmt.Sprintf(" (%s accepts %s)", name, n.describeKeys())
+			}
+			d.problems = append(d.problems, msg)
+			continue
+		}
+		if child == nil {
+			out[key] = value
+			continue
+		}
+		out[key] = d.value(join(path, key), value, child)
+	}
+	for _, key := range n.Required {
+		if _, ok := out[key]; !ok {
+			d.fail("%s is required", join(path, key))
+		}
+	}
+	return out
+}
+
+func (d *decoder) array(path, name string, v any, n *node) any {
+	items, ok := v.([]any)
+	if !ok {
+		if _, isObject := v.(map[string]any); isObject && (n.Items == nil || n.Items.Type != "object") {
+			d.fail("%s must be an array, got %s", name, describe(v))
+			return v
+		}
+		// A single value stands for an array holding it
+		items = []any{v}
+	}
+	if n.MinItems != nil && len(items) < *n.MinItems {
+		d.fail("%s must have at least %d items, got %d", name, *n.MinItems, len(items))
+	}
+	if n.MaxItems != nil && len(items) > *n.MaxItems {
+		d.fail("%s must have at most %d items, got %d", name, *n.MaxItems, len(items))
+	}
+	if n.Items == nil {
+		return items
+	}
+	out := make([]any, len(items))
+	for i, item := range items {
+		itemPath := fmt.Sprintf("%s[%d]", name, i)
+		if item == nil {
+			d.fail("%s must not be null", itemPath)
+			continue
+		}
+		out[i] = d.value(itemPath, item, n.Items)
+	}
+	return out
+}
+
+// keys returns the properties of an object schema, sorted
+func (n *node) keys() []string {
+	keys := make([]string, 0, len(n.Properties))
+	for key := range n.Properties {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+	return keys
+}
+
+// describeKeys lists the properties of an object schema, marking the required ones
+func (n *node) describeKeys() string {
+	keys := n.keys()
+	if len(keys) == 0 {
+		return "none"
+	}
+	for i, key := range keys {
+		if slices.Contains(n.Required, key) {
+			keys[i] += " (required)"
+		}
+	}
+	return strings.Join(keys, ", ")
+}
+
+// suggest returns the property key was most likely meant to be: one that differs only
+// in case, underscores, or hyphens, as spec_version does from specVersion, or by a
+// typo of a letter or two
+func (n *node) suggest(key string) string {
+	normalized := normalize(key)
+	best, bestDistance := "", 3
+	for _, candidate := range n.keys() {
+		distance := editDistance(normalized, normalize(candidate))
+		if distance == 0 {
+			return candidate
+		}
+		if distance < bestDistance && len(normalized) > 3 {
+			best, bestDistance = candidate, distance
+		}
+	}
+	return best
+}
+
+func normalize(key string) string {
+	key = strings.ToLower(key)
+	return strings.NewReplacer("_", "", "-", "").Replace(key)
+}
+
+// editDistance is the Levenshtein distance between a and b
+func editDistance(a, b string) int {
+	prev := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		cur := make([]int, len(b)+1)
+		cur[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev = cur
+	}
+	return prev[len(b)]
+}
+
+// inEnum reports whether v is one of values. Only scalars can be; comparing arrays
+// or objects with == would panic.
+func inEnum(values []any, v any) bool {
+	switch v.(type) {
+	case string, float64, bool:
+		return slices.Contains(values, v)
+	}
+	return false
+}
+
+func join(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
+
+func describeEnum(values []any) string {
+	parts := make([]string, len(values))
+	for i, v := range values {
+		parts[i] = describe(v)
+	}
+	return strings.Join(parts, ", ")
+}
+
+// describe shows a value in a problem: strings quoted, other values by JSON type
+func describe(v any) string {
+	switch v := v.(type) {
+	case string:
+		if len(v) > 40 {
+			v = v[:40] + "..."
+		}
+		return strconv.Quote(v)
+	case float64:
+		return formatNumber(v)
+	case bool:
+		return strconv.FormatBool(v)
+	case []any:
+		return "an array"
+	case map[string]any:
+		return "an object"
+	case nil:
+		return "null"
+	}
+	return fmt.Sprintf("%v", v)
+}
+
+func formatNumber(f float64) string {
+	return strconv.FormatFloat(f, 'f', -1, 64)
+}
@@ -0,0 +1,170 @@
+// Package toolerr classifies why a tool call failed, so clients and the models driving
+// them can tell a bad argument from an outage and know whether and when to retry.
+//
+// mcp-go answers errors returned by tool handlers with a generic internal error
+// (-32603) and no data, so classified errors are returned as tool results with isError
+// set, carrying the code, a hint, retryability, and the JSON-RPC code the failure
+// corresponds to. Failures that aren't classified remain internal errors.
+package toolerr
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/carlisia/mcp-factcheck/internal/specs"
+)
+
+// Code identifies a kind of failure
+type Code string
+
+// Failure codes
+const (
+	CodeInvalidArgs                  Code = "invalid_args"                   // An argument is missing, of the wrong type, or out of range
+	CodeSpecVersionNotFound          Code = "spec_version_not_found"         // The spec version isn't one the server knows
+	CodeEmbeddingProviderUnavailable Code = "embedding_provider_unavailable" // Embeddings couldn't be generated
+	CodeChatModelUnavailable         Code = "chat_model_unavailable"         // No chat model to verify claims or suggest fixes
+	CodeBudgetExceeded               Code = "budget_exceeded"                // The daily OpenAI budget is spent
+	CodeLimitExceeded                Code = "limit_exceeded"                 // A size or rate limit was hit
+)
+
+// JSON-RPC error codes failures correspond to
+const (
+	RPCInvalidParams = -32602
+	RPCInternalError = -32603
+)
+
+// RPCCode returns the JSON-RPC error code a failure of this kind corresponds to:
+// invalid params for bad arguments, internal error otherwise
+func (c Code) RPCCode() int {
+	switch c {
+	case CodeInvalidArgs, CodeSpecVersionNotFound, CodeLimitExceeded:
+		return RPCInvalidParams
+	}
+	return RPCInternalError
+}
+
+// Error is a classified tool failure
+type Error struct {
+	Code    Code
+	Message string
+	Hint    string // What the caller can do about it
+	// Retryable reports whether the same call may succeed later; RetryAfter, when set,
+	// is how long to wait first
+	Retryable  bool
+	RetryAfter time.Duration
+	// Details are fields of the underlying error merged into the JSON, such as the
+	// limit and value of a limits.Error, so existing clients keep finding them
+	Details any
+	Err     error
+}
+
+func (e *Error) Error() string {
+	return e.Message
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// MarshalJSON writes the error's details with its classification over them
+func (e *Error) MarshalJSON() ([]byte, error) {
+	fields := map[string]any{}
+	if e.Details != nil {
+		raw, err := json.Marshal(e.Details)
+		if err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal(raw, &fields); err != nil {
+			return nil, fmt.Errorf("details must marshal to a JSON object: %w", err)
+		}
+	}
+	fields["code"] = e.Code
+	fields["jsonrpc_code"] = e.Code.RPCCode()
+	fields["message"] = e.Message
+	fields["retryable"] = e.Retryable
+	if e.Hint != "" {
+		fields["hint"] = e.Hint
+	}
+	if e.RetryAfter > 0 {
+		fields["retry_after_seconds"] = int64(e.RetryAfter.Round(time.Second) / time.Second)
+	}
+	return json.Marshal(fields)
+}
+
+// InvalidArgs reports a bad argument
+func InvalidArgs(format string, a ...any) *Error {
+	return &Error{
+		Code:    CodeInvalidArgs,
+		Message: fmt.Sprintf(format, a...),
+		Hint:    "Fix the argument and call the tool again; its input schema describes the expected arguments.",
+	}
+}
+
+// SpecVersionNotFound reports a spec version the server doesn't know
+func SpecVersionNotFound(version string) *Error {
+	return &Error{
+		Code:    CodeSpecVersionNotFound,
+		Message: fmt.Sprintf("invalid spec version: %s", version),
+		Hint:    "Use one of " + strings.Join(specs.ValidSpecVersions, ", ") + ", or call list_spec_versions.",
+	}
+}
+
+// EmbeddingProviderUnavailable reports that embeddings couldn't be generated. Without
+// an API key the call can't succeed until the server is reconfigured; other failures,
+// such as rate limits and outages, are worth retrying.
+func EmbeddingProviderUnavailable(err error, configured bool) *Error {
+	e := &Error{
+		Code:    CodeEmbeddingProviderUnavailable,
+		Message: err.Error(),
+		Err:     err,
+	}
+	if configured {
+		e.Hint = "The embedding API failed after retries; try again shortly."
+		e.Retryable, e.RetryAfter = true, 30*time.Second
+	} else {
+		e.Hint = "The server has no OpenAI API key; set OPENAI_API_KEY where the server runs."
+	}
+	return e
+}
+
+// ChatModelUnavailable reports that no chat model can answer
+func ChatModelUnavailable(err error) *Error {
+	return &Error{
+		Code:    CodeChatModelUnavailable,
+		Message: err.Error(),
+		Hint:    "Call again without claim verification or suggested fixes, or use a client that supports MCP sampling.",
+		Err:     err,
+	}
+}
+
+// BudgetExceeded reports a spent budget that resets at resetsAt. details carries the
+// budget's fields.
+func BudgetExceeded(err error, resetsAt time.Time, details any) *Error {
+	return &Error{
+		Code:       CodeBudgetExceeded,
+		Message:    err.Error(),
+		Hint:       "Wait for the budget to reset, or raise cost.daily_budget_usd in the server config.",
+		Retryable:  true,
+		RetryAfter: time.Until(resetsAt),
+		Details:    details,
+		Err:        err,
+	}
+}
+
+// LimitExceeded reports a size or rate limit. details carries the limit's fields.
+func LimitExceeded(err error, retryable bool, details any) *Error {
+	hint := "Reduce the input, for example by splitting it into smaller documents."
+	if retryable {
+		hint = "The server is busy with other calls; retry shortly."
+	}
+	return &Error{
+		Code:      CodeLimitExceeded,
+		Message:   err.Error(),
+		Hint:      hint,
+		Retryable: retryable,
+		Details:   details,
+		Err:       err,
+	}
+}
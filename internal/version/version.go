@@ -0,0 +1,131 @@
+package version
+
+import (
+	"fmt"
+	"os"
+	"runtime"
+	"runtime/debug"
+	"strings"
+	"time"
+
+	"github.com/carlisia/mcp-factcheck/vectorstore"
+)
+
+// Build metadata, set at link time:
+//
+//	go build -ldflags "-X github.com/carlisia/mcp-factcheck/internal/version.Commit=$(git rev-parse --short HEAD) \
+//	  -X github.com/carlisia/mcp-factcheck/internal/version.BuildDate=$(date -u +%Y-%m-%dT%H:%M:%SZ) \
+//	  -X github.com/carlisia/mcp-factcheck/internal/version.SpecData=$(ls data/embeddings | sed 's/\.[a-z]*$//' | paste -sd, -)"
+var (
+	Version   = "0.1.0"
+	Commit    = ""
+	BuildDate = ""
+	SpecData  = "" // Comma-separated spec versions whose embeddings shipped with this build
+)
+
+func init() {
+	// Fall back to the VCS stamp Go embeds in module builds
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		return
+	}
+	for _, setting := range info.Settings {
+		switch setting.Key {
+		case "vcs.revision":
+			if Commit == "" && len(setting.Value) >= 7 {
+				Commit = setting.Value[:7]
+			}
+		case "vcs.time":
+			if BuildDate == "" {
+				BuildDate = setting.Value
+			}
+		}
+	}
+}
+
+// Info describes the running build
+type Info struct {
+	Version   string         `json:"version"`
+	Commit    string         `json:"commit,omitempty"`
+	BuildDate string         `json:"build_date,omitempty"`
+	GoVersion string         `json:"go_version"`
+	SpecData  []string       `json:"spec_data,omitempty"`
+	DataFiles []DataFileInfo `json:"data_files,omitempty"`
+}
+
+// DataFileInfo identifies one embedding file loaded at runtime
+type DataFileInfo struct {
+	SpecVersion string    `json:"spec_version"`
+	Size        int64     `json:"size"`
+	Modified    time.Time `json:"modified"`
+}
+
+// Get returns build information, including the embedding files found in dataDir if non-empty
+func Get(dataDir string) Info {
+	info := Info{
+		Version:   Version,
+		Commit:    Commit,
+		BuildDate: BuildDate,
+		GoVersion: runtime.Version(),
+	}
+	if SpecData != "" {
+		info.SpecData = strings.Split(SpecData, ",")
+	}
+	if dataDir != "" {
+		info.DataFiles = dataFiles(dataDir)
+	}
+	return info
+}
+
+// ServerVersion returns the semver reported to MCP clients, with the commit as build metadata
+func ServerVersion() string {
+	if Commit == "" {
+		return Version
+	}
+	return Version + "+" + Commit
+}
+
+// String renders build information for --version output
+func (i Info) String() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "mcp-factcheck-server %s\n", i.Version)
+	fmt.Fprintf(&b, "  commit:     %s\n", valueOr(i.Commit, "unknown"))
+	fmt.Fprintf(&b, "  built:      %s\n", valueOr(i.BuildDate, "unknown"))
+	fmt.Fprintf(&b, "  go:         %s\n", i.GoVersion)
+	if len(i.SpecData) > 0 {
+		fmt.Fprintf(&b, "  spec data:  %s\n", strings.Join(i.SpecData, ", "))
+	}
+	for _, f := range i.DataFiles {
+		fmt.Fprintf(&b, "  data file:  %s (%d bytes, modified %s)\n", f.SpecVersion, f.Size, f.Modified.Format(time.RFC3339))
+	}
+	return b.String()
+}
+
+func dataFiles(dir string) []DataFileInfo {
+	store := vectorstore.NewStore(dir)
+	versions, _ := store.ListVersions()
+	var infos []DataFileInfo
+	for _, version := range versions {
+		file, err := store.Path(version)
+		if err != nil {
+			continue
+		}
+		stat, err := os.Stat(file)
+		if err != nil {
+			continue
+		}
+		infos = append(infos, DataFileInfo{
+			SpecVersion: version,
+			Size:        stat.Size(),
+			Modified:    stat.ModTime().UTC(),
+		})
+	}
+	return infos
+}
+
+func valueOr(value, fallback string) string {
+	if value == "" {
+		return fallback
+	}
+	return value
+}
@@ -0,0 +1,503 @@
+// Package conformance checks an MCP server against the specification by talking to
+// it. It launches the server over stdio once per spec version, and runs checks
+// derived from the spec: the initialize handshake and version negotiation, the
+// capabilities the server declares, the tools it lists, the error codes it answers
+// bad requests with, message framing, and shutdown. Every message the server sends
+// is validated against the message schema of the negotiated version.
+package conformance
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/carlisia/mcp-factcheck/internal/jsonrpc"
+	"github.com/carlisia/mcp-factcheck/internal/specs"
+	"github.com/carlisia/mcp-factcheck/internal/version"
+	"github.com/carlisia/mcp-factcheck/pkg/schema"
+)
+
+// Check statuses
+const (
+	StatusPass = "pass"
+	StatusFail = "fail"
+	StatusWarn = "warn"
+	StatusSkip = "skip"
+)
+
+// DefaultTimeout bounds each request, and the server's exit after its input closes
+const DefaultTimeout = 10 * time.Second
+
+// maxToolPages bounds how many pages of tools/list are read
+const maxToolPages = 10
+
+// Options selects the server to check and how
+type Options struct {
+	Command string
+	Args    []string
+	Env     []string // The server's environment; nil inherits this process's
+	Dir     string   // The server's working directory; empty is this process's
+	// SpecVersions are checked in turn, each in a fresh session; empty checks
+	// ReleasedVersions
+	SpecVersions []string
+	Timeout      time.Duration // Per request; zero is DefaultTimeout
+}
+
+// Report is the outcome of checking a server against one or more spec versions
+type Report struct {
+	Command    []string        `json:"command"`
+	Conformant bool            `json:"conformant"` // Some version is supported, and no supported version fails a check
+	Versions   []VersionReport `json:"versions"`
+}
+
+// VersionReport is the outcome of checking a server against one spec version
+type VersionReport struct {
+	SpecVersion       string         `json:"spec_version"`
+	NegotiatedVersion string         `json:"negotiated_version,omitempty"`
+	Supported         bool           `json:"supported"` // The server agreed to the version in initialize
+	ServerInfo        map[string]any `json:"server_info,omitempty"`
+	Capabilities      []string       `json:"capabilities,omitempty"` // Declared in the initialize result
+	Conformant        bool           `json:"conformant"`
+	Summary           Summary        `json:"summary"`
+	Checks            []Check        `json:"checks"`
+}
+
+// Summary counts checks by status
+type Summary struct {
+	Passed   int `json:"passed"`
+	Failed   int `json:"failed"`
+	Warnings int `json:"warnings"`
+	Skipped  int `json:"skipped"`
+}
+
+// Check is the outcome of one spec-derived check
+type Check struct {
+	ID         string             `json:"id"`
+	Status     string             `json:"status"`
+	Message    string             `json:"message"`
+	SpecURL    string             `json:"spec_url"` // The spec section the check derives from
+	Violations []schema.Violation `json:"violations,omitempty"`
+}
+
+// ReleasedVersions returns the published spec versions, which have protocol version
+// strings a server can negotiate
+func ReleasedVersions() []string {
+	var versions []string
+	for _, v := range specs.ValidSpecVersions {
+		if v != "draft" {
+			versions = append(versions, v)
+		}
+	}
+	return versions
+}
+
+// Run checks the server against each spec version. It returns an error only when the
+// checks can't run, such as for a command that doesn't start.
+func Run(ctx context.Context, opts Options) (*Report, error) {
+	if opts.Command == "" {
+		return nil, errors.New("command is required")
+	}
+	if opts.Timeout <= 0 {
+		opts.Timeout = DefaultTimeout
+	}
+	versions := opts.SpecVersions
+	if len(versions) == 0 {
+		versions = ReleasedVersions()
+	}
+	for _, v := range versions {
+		if !slices.Contains(ReleasedVersions(), v) {
+			return nil, fmt.Errorf("invalid spec version %q: want one of %s", v, strings.Join(ReleasedVersions(), ", "))
+		}
+	}
+
+	report := &Report{Command: append([]string{opts.Command}, opts.Args...)}
+	supported := false
+	conformant := true
+	for _, v := range versions {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		vr, err := runVersion(ctx, opts, v)
+		if err != nil {
+			return nil, err
+		}
+		report.Versions = append(report.Versions, *vr)
+		if vr.Supported {
+			supported = true
+			conformant = conformant && vr.Conformant
+		}
+	}
+	report.Conformant = supported && conformant
+	return report, nil
+}
+
+// checker runs the checks of one session
+type checker struct {
+	s       *session
+	version string // The negotiated version, once known, which messages are validated against
+	report  *VersionReport
+}
+
+func (c *checker) add(id, status, page, format string, args ...any) *Check {
+	c.report.Checks = append(c.report.Checks, Check{
+		ID:      id,
+		Status:  status,
+		Message: fmt.Sprintf(format, args...),
+		SpecURL: specs.PageURL(c.version, page),
+	})
+	return &c.report.Checks[len(c.report.Checks)-1]
+}
+
+// validate checks a message against the schema, a response against the schema of
+// the request method it answers
+func (c *checker) validate(r reply, method string) []schema.Violation {
+	result := schema.Validate(r.raw, c.version, method)
+	return result.Violations
+}
+
+// runVersion checks one spec version in a fresh session
+func runVersion(ctx context.Context, opts Options, specVersion string) (*VersionReport, error) {
+	s, err := startSession(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+	vr := &VersionReport{SpecVersion: specVersion}
+	c := &checker{s: s, version: specVersion, report: vr}
+
+	if c.initialize() {
+		c.ping()
+		c.capabilities()
+		c.tools()
+		c.errorCodes()
+	}
+	c.unsolicited()
+	c.framing()
+	c.shutdown()
+
+	vr.Conformant = true
+	for _, check := range vr.Checks {
+		switch check.Status {
+		case StatusPass:
+			vr.Summary.Passed++
+		case StatusFail:
+			vr.Summary.Failed++
+			vr.Conformant = false
+		case StatusWarn:
+			vr.Summary.Warnings++
+		case StatusSkip:
+			vr.Summary.Skipped++
+		}
+	}
+	return vr, nil
+}
+
+// initialize performs the handshake and reports whether the session can go on with
+// the version under test
+func (c *checker) initialize() bool {
+	const page = "basic/lifecycle#initialization"
+	requested := c.version
+	r, err := c.s.call("initialize", map[string]any{
+		"protocolVersion": requested,
+		"capabilities":    map[string]any{},
+		"clientInfo":      map[string]any{"name": "factcheck-conformance", "version": version.ServerVersion()},
+	})
+	if err != nil {
+		c.add("initialize.response", StatusFail, page, "%s%s", err, c.stderr())
+		return false
+	}
+	violations := c.validate(r, "initialize")
+	if r.msg.Error != nil {
+		check := c.add("initialize.response", StatusFail, page, "initialize failed with error %d: %s", r.msg.Error.Code, r.msg.Error.Message)
+		check.Violations = violations
+		return false
+	}
+	check := c.add("initialize.response", statusOf(violations), page, "initialize result has the required protocolVersion, capabilities, and serverInfo")
+	check.Violations = violations
+	if check.Status == StatusFail {
+		check.Message = "initialize result doesn't match the schema"
+	}
+
+	var result struct {
+		ProtocolVersion string         `json:"protocolVersion"`
+		Capabilities    map[string]any `json:"capabilities"`
+		ServerInfo      map[string]any `json:"serverInfo"`
+	}
+	json.Unmarshal(r.msg.Result, &result)
+	c.report.NegotiatedVersion = result.ProtocolVersion
+	c.report.ServerInfo = result.ServerInfo
+	for name := range result.Capabilities {
+		c.report.Capabilities = append(c.report.Capabilities, name)
+	}
+	slices.Sort(c.report.Capabilities)
+
+	// A server that supports the requested version must answer with it; otherwise it
+	// answers with another version it supports
+	const versionPage = "basic/lifecycle#version-negotiation"
+	switch {
+	case result.ProtocolVersion == requested:
+		c.report.Supported = true
+		c.add("initialize.version", StatusPass, versionPage, "server agreed to protocol version %s", requested)
+	case slices.Contains(ReleasedVersions(), result.ProtocolVersion):
+		c.version = result.ProtocolVersion
+		c.add("initialize.version", StatusPass, versionPage, "server doesn't support %s and offered %s instead, which it must then support", requested, result.ProtocolVersion)
+	default:
+		c.add("initialize.version", StatusFail, versionPage, "server answered with protocol version %q, which isn't a published version", result.ProtocolVersion)
+	}
+
+	if err := c.s.notify("notifications/initialized", nil); err != nil {
+		c.add("initialize.initialized", StatusFail, page, "failed to send notifications/initialized: %v", err)
+		return false
+	}
+	if !c.report.Supported {
+		c.add("session", StatusSkip, page, "remaining checks skipped: the server doesn't support %s", requested)
+	}
+	return c.report.Supported
+}
+
+// ping checks that the server answers ping with an empty result
+func (c *checker) ping() {
+	const page = "basic/utilities/ping"
+	r, err := c.s.call("ping", nil)
+	switch {
+	case err != nil:
+		c.add("ping", StatusFail, page, "%v", err)
+	case r.msg.Error != nil:
+		c.add("ping", StatusFail, page, "ping failed with error %d: %s; servers must answer ping", r.msg.Error.Code, r.msg.Error.Message)
+	case strings.TrimSpace(string(r.msg.Result)) != "{}":
+		c.add("ping", StatusWarn, page, "ping result is %s; it should be empty", r.msg.Result)
+	default:
+		c.add("ping", StatusPass, page, "server answered ping with an empty result")
+	}
+}
+
+// capabilityChecks pairs each server capability with a request it makes available
+var capabilityChecks = []struct {
+	name   string
+	method string
+	params any
+	page   string
+}{
+	{"tools", "tools/list", nil, "server/tools#capabilities"},
+	{"resources", "resources/list", nil, "server/resources#capabilities"},
+	{"prompts", "prompts/list", nil, "server/prompts#capabilities"},
+	{"logging", "logging/setLevel", map[string]any{"level": "info"}, "server/utilities/logging#capabilities"},
+}
+
+// capabilities checks that every declared capability's requests succeed with
+// results that match the schema
+func (c *checker) capabilities() {
+	for _, cc := range capabilityChecks {
+		id := "capabilities." + cc.name
+		if !slices.Contains(c.report.Capabilities, cc.name) {
+			c.add(id, StatusSkip, cc.page, "%s capability not declared", cc.name)
+			continue
+		}
+		r, err := c.s.call(cc.method, cc.params)
+		if err != nil {
+			c.add(id, StatusFail, cc.page, "%s: %v", cc.method, err)
+			continue
+		}
+		// tools.list checks the tools it lists in detail
+		var violations []schema.Violation
+		if cc.name != "tools" {
+			violations = c.validate(r, cc.method)
+		}
+		if r.msg.Error != nil {
+			check := c.add(id, StatusFail, cc.page, "%s capability declared, but %s failed with error %d: %s", cc.name, cc.method, r.msg.Error.Code, r.msg.Error.Message)
+			check.Violations = violations
+			continue
+		}
+		check := c.add(id, statusOf(violations), cc.page, "%s capability declared and %s succeeds", cc.name, cc.method)
+		check.Violations = violations
+	}
+}
+
+// tools checks every page of tools/list: results match the schema, names are unique,
+// and input and output schemas describe objects
+func (c *checker) tools() {
+	const page = "server/tools#listing-tools"
+	if !slices.Contains(c.report.Capabilities, "tools") {
+		c.add("tools.list", StatusSkip, page, "tools capability not declared")
+		return
+	}
+
+	var violations []schema.Violation
+	names := map[string]bool{}
+	cursor := ""
+	pages := 0
+	for {
+		var params any
+		if cursor != "" {
+			params = map[string]any{"cursor": cursor}
+		}
+		r, err := c.s.call("tools/list", params)
+		if err != nil {
+			c.add("tools.list", StatusFail, page, "tools/list: %v", err)
+			return
+		}
+		if r.msg.Error != nil {
+			c.add("tools.list", StatusFail, page, "tools/list failed with error %d: %s", r.msg.Error.Code, r.msg.Error.Message)
+			return
+		}
+		pages++
+		prefix := ""
+		if pages > 1 {
+			prefix = fmt.Sprintf("page %d: ", pages)
+		}
+		for _, v := range c.validate(r, "tools/list") {
+			v.Message = prefix + v.Message
+			violations = append(violations, v)
+		}
+
+		var result struct {
+			Tools []struct {
+				Name         string         `json:"name"`
+				InputSchema  map[string]any `json:"inputSchema"`
+				OutputSchema map[string]any `json:"outputSchema"`
+			} `json:"tools"`
+			NextCursor string `json:"nextCursor"`
+		}
+		json.Unmarshal(r.msg.Result, &result)
+		for i, tool := range result.Tools {
+			path := fmt.Sprintf("result.tools[%d]", i)
+			if names[tool.Name] {
+				violations = append(violations, schema.Violation{Path: path + ".name", Severity: schema.SeverityError, Message: prefix + fmt.Sprintf("tool name %q is listed more than once", tool.Name)})
+			}
+			names[tool.Name] = true
+			if tool.InputSchema != nil && tool.InputSchema["type"] != "object" {
+				violations = append(violations, schema.Violation{Path: path + ".inputSchema.type", Severity: schema.SeverityError, Message: prefix + fmt.Sprintf("inputSchema of %q must have type \"object\"", tool.Name)})
+			}
+			if tool.OutputSchema != nil && tool.OutputSchema["type"] != "object" {
+				violations = append(violations, schema.Violation{Path: path + ".outputSchema.type", Severity: schema.SeverityError, Message: prefix + fmt.Sprintf("outputSchema of %q must have type \"object\"", tool.Name)})
+			}
+		}
+
+		if result.NextCursor == "" {
+			break
+		}
+		if pages == maxToolPages {
+			violations = append(violations, schema.Violation{Path: "result.nextCursor", Severity: schema.SeverityWarning, Message: fmt.Sprintf("stopped after %d pages", maxToolPages)})
+			break
+		}
+		cursor = result.NextCursor
+	}
+
+	check := c.add("tools.list", statusOf(violations), page, "%d tools listed over %d page(s), with valid schemas", len(names), pages)
+	check.Violations = violations
+	if check.Status == StatusFail {
+		check.Message = fmt.Sprintf("%d tools listed over %d page(s); some don't match the schema", len(names), pages)
+	}
+}
+
+// errorCodes checks the errors the server answers bad requests with
+func (c *checker) errorCodes() {
+	c.expectError("errors.method_not_found", "basic#responses", jsonrpc.CodeMethodNotFound, "an unknown method", StatusFail, func() (reply, error) {
+		return c.s.call("factcheck/no_such_method", nil)
+	})
+
+	if slices.Contains(c.report.Capabilities, "tools") {
+		// Reporting it as a tool error instead lets the model see it, which servers do
+		c.expectError("errors.unknown_tool", "server/tools#error-handling", jsonrpc.CodeInvalidParams, "a call to an unknown tool", StatusWarn, func() (reply, error) {
+			return c.s.call("tools/call", map[string]any{"name": "factcheck_no_such_tool", "arguments": map[string]any{}})
+		})
+	} else {
+		c.add("errors.unknown_tool", StatusSkip, "server/tools#error-handling", "tools capability not declared")
+	}
+
+	// Malformed messages come last, as they are the likeliest to bring a server down
+	c.expectError("errors.invalid_request", "basic#requests", jsonrpc.CodeInvalidRequest, `a request with "jsonrpc": "1.0"`, StatusFail, func() (reply, error) {
+		return c.s.send([]byte(`{"jsonrpc":"1.0","id":"factcheck-invalid","method":"ping"}`), jsonrpc.StringID("factcheck-invalid"), "")
+	})
+	c.expectError("errors.parse_error", "basic#responses", jsonrpc.CodeParseError, "malformed JSON", StatusFail, func() (reply, error) {
+		return c.s.send([]byte(`{"jsonrpc":"2.0","id":`), "")
+	})
+}
+
+// expectError sends a bad request and checks that it fails with code. A result
+// instead gets resultStatus.
+func (c *checker) expectError(id, page string, code int, what, resultStatus string, send func() (reply, error)) {
+	r, err := send()
+	if err != nil {
+		c.add(id, StatusFail, page, "%s: %v", what, err)
+		return
+	}
+	violations := c.validate(r, "")
+	switch {
+	case r.msg.Error == nil:
+		c.add(id, resultStatus, page, "%s returned a result; want error %d", what, code)
+	case r.msg.Error.Code != code:
+		check := c.add(id, StatusFail, page, "%s failed with error %d; want %d", what, r.msg.Error.Code, code)
+		check.Violations = violations
+	default:
+		check := c.add(id, statusOf(violations), page, "%s failed with error %d", what, code)
+		check.Violations = violations
+	}
+}
+
+// unsolicited validates the notifications and requests the server sent on its own
+func (c *checker) unsolicited() {
+	const page = "basic#notifications"
+	msgs := c.s.takeUnsolicited()
+	var violations []schema.Violation
+	for i, r := range msgs {
+		for _, v := range schema.Validate(r.raw, c.version, "").Violations {
+			v.Path = fmt.Sprintf("messages[%d].%s", i, strings.TrimPrefix(v.Path, "$."))
+			violations = append(violations, v)
+		}
+	}
+	if len(msgs) == 0 {
+		c.add("messages.unsolicited", StatusPass, page, "server sent no notifications or requests of its own")
+		return
+	}
+	check := c.add("messages.unsolicited", statusOf(violations), page, "%d notifications and requests sent by the server match the schema", len(msgs))
+	check.Violations = violations
+}
+
+// framing checks that every message was one line, as the stdio transport requires
+func (c *checker) framing() {
+	const page = "basic/transports#stdio"
+	problems := c.s.framingProblems()
+	if len(problems) == 0 {
+		c.add("transport.framing", StatusPass, page, "every message was a single line")
+		return
+	}
+	check := c.add("transport.framing", StatusFail, page, "%d message(s) not framed as single lines", len(problems))
+	for _, p := range problems {
+		check.Violations = append(check.Violations, schema.Violation{Path: "$", Severity: schema.SeverityError, Message: p})
+	}
+}
+
+// shutdown checks that the server exits when its input closes
+func (c *checker) shutdown() {
+	const page = "basic/lifecycle#stdio"
+	if c.s.shutdown() {
+		c.add("shutdown", StatusPass, page, "server exited after its input closed")
+		return
+	}
+	c.add("shutdown", StatusWarn, page, "server didn't exit within %s of its input closing and was killed", c.s.timeout)
+}
+
+// stderr returns the server's last output on stderr, to explain a failure
+func (c *checker) stderr() string {
+	if tail := c.s.stderr.String(); tail != "" {
+		return "; server stderr: " + tail
+	}
+	return ""
+}
+
+// statusOf returns fail for violations with errors, warn for warnings only, and pass
+// for none
+func statusOf(violations []schema.Violation) string {
+	status := StatusPass
+	for _, v := range violations {
+		if v.Severity == schema.SeverityError {
+			return StatusFail
+		}
+		status = StatusWarn
+	}
+	return status
+}
@@ -0,0 +1,290 @@
+package conformance
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os/exec"
+	"sync"
+	"time"
+
+	"github.com/carlisia/mcp-factcheck/internal/jsonrpc"
+)
+
+// errTimeout is returned for a request the server doesn't answer in time
+var errTimeout = errors.New("no response before the timeout")
+
+// errExited is returned for a request the server exits without answering
+var errExited = errors.New("server exited before responding")
+
+// reply is a server message: its raw JSON, as validated, and its decoding
+type reply struct {
+	raw []byte
+	msg *jsonrpc.Message
+}
+
+// session is one run of the server under test, spoken to over stdio. Messages the
+// server sends unprompted, and how every message was framed, are kept for checks.
+type session struct {
+	cmd     *exec.Cmd
+	writer  *jsonrpc.Writer
+	stdin   io.WriteCloser
+	stderr  *tailBuffer
+	timeout time.Duration
+	done    chan struct{} // Closed when the server's output ends
+
+	mu          sync.Mutex
+	nextID      int64
+	pending     map[jsonrpc.ID]chan reply
+	unsolicited []reply  // Notifications and requests from the server
+	framing     []string // Framing problems, one per message
+}
+
+// startSession launches the server
+func startSession(ctx context.Context, opts Options) (*session, error) {
+	cmd := exec.CommandContext(ctx, opts.Command, opts.Args...)
+	cmd.Env = opts.Env
+	cmd.Dir = opts.Dir
+	stderr := &tailBuffer{max: 4096}
+	cmd.Stderr = stderr
+	// Processes the server leaves behind holding its output don't keep Wait waiting
+	cmd.WaitDelay = time.Second
+
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return nil, fmt.Errorf("failed to create stdin pipe: %w", err)
+	}
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, fmt.Errorf("failed to create stdout pipe: %w", err)
+	}
+	if err := cmd.Start(); err != nil {
+		return nil, fmt.Errorf("failed to start server: %w", err)
+	}
+
+	s := &session{
+		cmd:     cmd,
+		writer:  jsonrpc.NewWriter(stdin, jsonrpc.FramingNewline),
+		stdin:   stdin,
+		stderr:  stderr,
+		timeout: opts.Timeout,
+		done:    make(chan struct{}),
+		nextID:  1,
+		pending: map[jsonrpc.ID]chan reply{},
+	}
+	go s.read(stdout)
+	return s, nil
+}
+
+// read dispatches the server's messages until its output ends
+func (s *session) read(stdout io.Reader) {
+	defer close(s.done)
+	reader := jsonrpc.NewReader(stdout, jsonrpc.FramingAuto, 0)
+	for {
+		raw, err := reader.Read()
+		if errors.Is(err, jsonrpc.ErrTooLarge) {
+			s.noteFraming(err.Error())
+			continue
+		}
+		if err != nil {
+			return
+		}
+		switch {
+		case reader.Framing() == jsonrpc.FramingHeader:
+			s.noteFraming("message sent with a Content-Length header; stdio messages are delimited by newlines")
+		case bytes.ContainsAny(raw, "\r\n"):
+			s.noteFraming("message spans lines; stdio messages must not contain embedded newlines")
+		}
+
+		msgs, _, err := jsonrpc.Decode(raw)
+		if err != nil || len(msgs) != 1 {
+			// Batches and garbage answer nothing; they are checked as unsolicited
+			s.mu.Lock()
+			s.unsolicited = append(s.unsolicited, reply{raw: raw})
+			s.mu.Unlock()
+			continue
+		}
+		msg := msgs[0]
+		if msg.IsResponse() {
+			s.deliver(reply{raw: raw, msg: msg})
+			continue
+		}
+
+		s.mu.Lock()
+		s.unsolicited = append(s.unsolicited, reply{raw: raw, msg: msg})
+		s.mu.Unlock()
+		if msg.IsRequest() {
+			s.answer(msg)
+		}
+	}
+}
+
+// deliver hands a response to the request awaiting it. A response with a null ID,
+// as to a message the server couldn't parse, goes to whatever awaits the zero ID.
+func (s *session) deliver(r reply) {
+	s.mu.Lock()
+	ch, ok := s.pending[r.msg.ID]
+	delete(s.pending, r.msg.ID)
+	if !ok {
+		s.unsolicited = append(s.unsolicited, r)
+	}
+	s.mu.Unlock()
+	if ok {
+		ch <- r
+	}
+}
+
+// answer responds to the server's requests as a client with no features would
+func (s *session) answer(msg *jsonrpc.Message) {
+	var resp *jsonrpc.Message
+	switch msg.Method {
+	case "ping":
+		resp, _ = jsonrpc.NewResponse(msg.ID, struct{}{})
+	case "roots/list":
+		resp, _ = jsonrpc.NewResponse(msg.ID, map[string]any{"roots": []any{}})
+	default:
+		resp = jsonrpc.NewErrorResponse(msg.ID, jsonrpc.NewError(jsonrpc.CodeMethodNotFound, "method not found: %s", msg.Method))
+	}
+	data, _ := json.Marshal(resp)
+	s.writer.Write(data)
+}
+
+func (s *session) noteFraming(problem string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.framing = append(s.framing, problem)
+}
+
+// call sends a request and waits for its response
+func (s *session) call(method string, params any) (reply, error) {
+	s.mu.Lock()
+	id := jsonrpc.IntID(s.nextID)
+	s.nextID++
+	s.mu.Unlock()
+
+	msg, err := jsonrpc.NewRequest(id, method, params)
+	if err != nil {
+		return reply{}, err
+	}
+	data, err := json.Marshal(msg)
+	if err != nil {
+		return reply{}, err
+	}
+	return s.send(data, id)
+}
+
+// send writes a message and waits for a response with one of ids, where the zero ID
+// stands for null
+func (s *session) send(data []byte, ids ...jsonrpc.ID) (reply, error) {
+	ch := make(chan reply, len(ids))
+	s.mu.Lock()
+	for _, id := range ids {
+		s.pending[id] = ch
+	}
+	s.mu.Unlock()
+	defer func() {
+		s.mu.Lock()
+		for _, id := range ids {
+			delete(s.pending, id)
+		}
+		s.mu.Unlock()
+	}()
+
+	if err := s.writer.Write(data); err != nil {
+		return reply{}, fmt.Errorf("failed to send message: %w", err)
+	}
+	timer := time.NewTimer(s.timeout)
+	defer timer.Stop()
+	select {
+	case r := <-ch:
+		return r, nil
+	case <-s.done:
+		// The response may have been read just before the output ended
+		select {
+		case r := <-ch:
+			return r, nil
+		default:
+			return reply{}, errExited
+		}
+	case <-timer.C:
+		return reply{}, errTimeout
+	}
+}
+
+// notify sends a notification
+func (s *session) notify(method string, params any) error {
+	msg, err := jsonrpc.NewNotification(method, params)
+	if err != nil {
+		return err
+	}
+	data, err := json.Marshal(msg)
+	if err != nil {
+		return err
+	}
+	return s.writer.Write(data)
+}
+
+// shutdown ends the session as the stdio transport prescribes: close the server's
+// input and wait for it to exit, then terminate it. It reports whether the server
+// exited on its own within the timeout.
+func (s *session) shutdown() bool {
+	s.stdin.Close()
+	exited := make(chan struct{})
+	go func() {
+		// Output is read to its end before Wait closes it
+		<-s.done
+		s.cmd.Wait()
+		close(exited)
+	}()
+	select {
+	case <-exited:
+		return true
+	case <-time.After(s.timeout):
+	}
+	s.cmd.Process.Kill()
+	<-exited
+	return false
+}
+
+// takeUnsolicited returns and clears the messages the server sent unprompted
+func (s *session) takeUnsolicited() []reply {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	msgs := s.unsolicited
+	s.unsolicited = nil
+	return msgs
+}
+
+// framingProblems returns the framing problems seen so far
+func (s *session) framingProblems() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]string(nil), s.framing...)
+}
+
+// tailBuffer keeps the last max bytes written to it, such as a server's last words
+// on stderr before it failed
+type tailBuffer struct {
+	mu  sync.Mutex
+	max int
+	buf []byte
+}
+
+func (b *tailBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.buf = append(b.buf, p...)
+	if len(b.buf) > b.max {
+		b.buf = b.buf[len(b.buf)-b.max:]
+	}
+	return len(p), nil
+}
+
+func (b *tailBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return string(bytes.TrimSpace(b.buf))
+}
@@ -0,0 +1,129 @@
+package conformance
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"time"
+
+	"github.com/carlisia/mcp-factcheck/internal/toolerr"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+const ConformanceCheckToolName = "conformance_check"
+
+// maxToolTimeout bounds the per-request timeout a caller can ask for
+const maxToolTimeout = 2 * time.Minute
+
+// inheritedEnv names the variables a server launched by the tool inherits. Others,
+// such as this server's API keys, are left out; the env argument adds what the
+// server needs.
+var inheritedEnv = []string{"PATH", "HOME", "USER", "LANG", "TMPDIR", "SYSTEMROOT"}
+
+func GetConformanceCheckTool() mcp.Tool {
+	schema := map[string]any{
+		"type": "object",
+		"properties": map[string]any{
+			"command": map[string]any{
+				"type":        "string",
+				"description": "Executable that starts the MCP server to check over stdio, such as \"npx\" or a path to a binary",
+			},
+			"args": map[string]any{
+				"type":        "array",
+				"items":       map[string]any{"type": "string"},
+				"description": "Arguments to the command",
+			},
+			"env": map[string]any{
+				"type":                 "object",
+				"additionalProperties": map[string]any{"type": "string"},
+				"description":          "Environment variables for the server, in addition to PATH, HOME, USER, LANG, and TMPDIR",
+			},
+			"specVersions": map[string]any{
+				"type":        "array",
+				"items":       map[string]any{"type": "string", "enum": ReleasedVersions()},
+				"description": "Spec versions to check, each in a fresh session (default: every published version)",
+			},
+			"timeoutSeconds": map[string]any{
+				"type":        "number",
+				"description": "Seconds to wait for each response, and for the server to exit at the end",
+				"default":     DefaultTimeout.Seconds(),
+				"maximum":     maxToolTimeout.Seconds(),
+			},
+		},
+		"required": []string{"command"},
+	}
+	schemaBytes, _ := json.Marshal(schema)
+	description := `Launch an MCP server and check it against the specification. For each spec version, starts the server over stdio and runs checks derived from the spec: the initialize handshake and version negotiation, the capabilities the server declares, tools/list schema validity, the error codes it answers bad requests with, message framing, and shutdown. Every message the server sends is validated against the message schema.
+
+Returns a conformance report per spec version: whether the server supports it, and each check's status (pass, fail, warn, or skip) with the spec section it derives from and any schema violations.`
+	return mcp.NewToolWithRawSchema(ConformanceCheckToolName, description, schemaBytes)
+}
+
+// HandleConformanceCheck checks the server the arguments describe and returns the report
+func HandleConformanceCheck(ctx context.Context, args any) ([]mcp.Content, error) {
+	params, ok := args.(map[string]any)
+	if !ok {
+		return nil, toolerr.InvalidArgs("arguments must be a map")
+	}
+
+	opts := Options{Env: []string{}}
+	opts.Command, _ = params["command"].(string)
+	if opts.Command == "" {
+		return nil, toolerr.InvalidArgs("command must be a non-empty string")
+	}
+	var err error
+	if opts.Args, err = stringList(params["args"], "args"); err != nil {
+		return nil, err
+	}
+	if opts.SpecVersions, err = stringList(params["specVersions"], "specVersions"); err != nil {
+		return nil, err
+	}
+	if seconds, ok := params["timeoutSeconds"].(float64); ok {
+		if seconds <= 0 || seconds > maxToolTimeout.Seconds() {
+			return nil, toolerr.InvalidArgs("timeoutSeconds must be between 0 and %g", maxToolTimeout.Seconds())
+		}
+		opts.Timeout = time.Duration(seconds * float64(time.Second))
+	}
+
+	for _, name := range inheritedEnv {
+		if value, ok := os.LookupEnv(name); ok {
+			opts.Env = append(opts.Env, name+"="+value)
+		}
+	}
+	if env, ok := params["env"].(map[string]any); ok {
+		for name, value := range env {
+			s, ok := value.(string)
+			if !ok {
+				return nil, toolerr.InvalidArgs("env.%s must be a string", name)
+			}
+			opts.Env = append(opts.Env, name+"="+s)
+		}
+	}
+
+	report, err := Run(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+	jsonBytes, _ := json.MarshalIndent(report, "", "  ")
+	return []mcp.Content{mcp.NewTextContent(string(jsonBytes))}, nil
+}
+
+// stringList returns an optional array argument of strings
+func stringList(v any, name string) ([]string, error) {
+	if v == nil {
+		return nil, nil
+	}
+	items, ok := v.([]any)
+	if !ok {
+		return nil, toolerr.InvalidArgs("%s must be an array of strings", name)
+	}
+	list := make([]string, len(items))
+	for i, item := range items {
+		s, ok := item.(string)
+		if !ok {
+			return nil, toolerr.InvalidArgs("%s must be an array of strings", name)
+		}
+		list[i] = s
+	}
+	return list, nil
+}
@@ -0,0 +1,165 @@
+// Package factcheck is the Go API of the fact-checker, for programs that validate
+// content against the MCP specification in process, without going through MCP or
+// HTTP. The MCP server, CLI, and HTTP API are frontends over the same validation.
+//
+//	client, err := factcheck.New(factcheck.Options{})
+//	if err != nil {
+//		return err
+//	}
+//	result, err := client.Validate(ctx, factcheck.Request{Content: doc, SpecVersion: "2025-06-18"})
+package factcheck
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/carlisia/mcp-factcheck/embedding"
+	"github.com/carlisia/mcp-factcheck/internal/bootstrap"
+	"github.com/carlisia/mcp-factcheck/internal/config"
+	mcpembedding "github.com/carlisia/mcp-factcheck/internal/embedding"
+	"github.com/carlisia/mcp-factcheck/pkg/logger"
+	"github.com/carlisia/mcp-factcheck/pkg/spec"
+	"github.com/carlisia/mcp-factcheck/pkg/validator"
+	"go.uber.org/zap"
+)
+
+// Request is a request to validate content; see validator.Request
+type Request = validator.Request
+
+// Result is the result of a validation: the overall verdict, the best matching spec
+// sections or the results of each chunk, and the findings
+type Result = validator.AggregatedValidationResult
+
+// SearchRequest is a spec search; see spec.SearchSpecArgs
+type SearchRequest = spec.SearchSpecArgs
+
+// SearchResults are the results of a spec search, in rank order
+type SearchResults = spec.SearchResults
+
+// Rule is a custom check run on prose alongside the spec checks; see validator.Rule
+type Rule = validator.Rule
+
+// RegisterRule adds a custom rule to every later validation in the process
+func RegisterRule(rule Rule) {
+	validator.RegisterRule(rule)
+}
+
+// DefaultTopK is the number of search results returned when a SearchRequest doesn't say
+const DefaultTopK = 5
+
+// Options configure a Client. The zero value uses the default data directory and the
+// OPENAI_API_KEY secret.
+type Options struct {
+	// DataDir holds the spec embeddings; config.DefaultDataDir when empty. An empty
+	// directory is populated from the embeddings bundled into the binary, if any, or
+	// downloaded from $MCP_FACTCHECK_BOOTSTRAP_URL.
+	DataDir string
+	// APIKey is the OpenAI API key used for embeddings and LLM-backed checks. When
+	// empty, the OPENAI_API_KEY secret is used, and without one the client runs
+	// degraded: queries are embedded locally and claim checks are unavailable.
+	APIKey string
+	// EmbeddingModel and EmbeddingDimensions select the model queries are embedded
+	// with, which must match the stored embeddings. When empty, $EMBEDDING_MODEL and
+	// $EMBEDDING_DIMENSIONS apply. The model is process-wide.
+	EmbeddingModel      string
+	EmbeddingDimensions int
+}
+
+// Client validates content and searches the spec. It is safe for concurrent use.
+type Client struct {
+	dataDir   string
+	vectorDB  *mcpembedding.VectorDB
+	generator *embedding.Generator
+}
+
+// New creates a client with the given options
+func New(opts Options) (*Client, error) {
+	if opts.EmbeddingModel != "" || opts.EmbeddingDimensions != 0 {
+		model, err := embedding.ResolveModel(opts.EmbeddingModel, opts.EmbeddingDimensions)
+		if err == nil {
+			err = embedding.SetModel(model)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid embedding model: %w", err)
+		}
+	}
+
+	dataDir, err := config.ResolveDataDir(context.Background(), opts.DataDir, bootstrap.FromEnv())
+	if err != nil {
+		return nil, err
+	}
+
+	var generator *embedding.Generator
+	if opts.APIKey != "" {
+		generator, err = embedding.NewGeneratorWithKey(opts.APIKey)
+	} else {
+		generator, err = embedding.NewGenerator()
+	}
+	if errors.Is(err, embedding.ErrNoAPIKey) {
+		// Degraded mode: embed locally and leave LLM-backed checks to MCP sampling
+		if err := embedding.SetModel(embedding.Local()); err != nil {
+			return nil, err
+		}
+		generator = embedding.NewLocalGenerator()
+		logger.Get().Warn("OPENAI_API_KEY is not set; running in degraded mode",
+			zap.String("embedding_model", embedding.LocalModel),
+			zap.String("chat_model", "MCP sampling, when the client supports it"))
+	} else if err != nil {
+		return nil, fmt.Errorf("failed to create embedding generator: %w", err)
+	}
+
+	return &Client{
+		dataDir:   dataDir,
+		vectorDB:  mcpembedding.NewVectorDB(dataDir),
+		generator: generator,
+	}, nil
+}
+
+// Validate validates content against the spec, as validate_content does. Errors caused
+// by the request's options are validator.RequestErrors.
+func (c *Client) Validate(ctx context.Context, req Request) (*Result, error) {
+	return validator.Validate(ctx, c.vectorDB, c.generator, req)
+}
+
+// ValidateCode validates source code in language against the spec, as validate_code
+// does. specVersion is the default spec version when empty.
+func (c *Client) ValidateCode(ctx context.Context, code, language, specVersion string) (*Result, error) {
+	if language == "" {
+		language = "go"
+	}
+	return c.Validate(ctx, Request{Content: code, Language: language, SpecVersion: specVersion})
+}
+
+// SearchSpec finds the spec sections most relevant to req.Query, as search_spec does.
+// req.TopK is DefaultTopK when zero.
+func (c *Client) SearchSpec(ctx context.Context, req SearchRequest) (*SearchResults, error) {
+	if req.TopK == 0 {
+		req.TopK = DefaultTopK
+	}
+	return spec.Search(ctx, c.vectorDB, c.generator, req)
+}
+
+// ListVersions returns the spec versions there are embeddings for
+func (c *Client) ListVersions() ([]string, error) {
+	versions, err := c.vectorDB.ListVersions()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list spec versions: %w", err)
+	}
+	return versions, nil
+}
+
+// DataDir returns the absolute data directory the client reads embeddings from
+func (c *Client) DataDir() string {
+	return c.dataDir
+}
+
+// VectorDB returns the client's vector database, for the frontends in this module
+func (c *Client) VectorDB() *mcpembedding.VectorDB {
+	return c.vectorDB
+}
+
+// Generator returns the client's embedding generator
+func (c *Client) Generator() *embedding.Generator {
+	return c.generator
+}
@@ -0,0 +1,198 @@
+package pkg
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/carlisia/mcp-factcheck/internal/auth"
+	"github.com/carlisia/mcp-factcheck/internal/cost"
+	"github.com/carlisia/mcp-factcheck/internal/history"
+	"github.com/carlisia/mcp-factcheck/internal/interactions"
+	"github.com/carlisia/mcp-factcheck/internal/protocol"
+	"github.com/carlisia/mcp-factcheck/internal/tenant"
+	"github.com/carlisia/mcp-factcheck/pkg/logger"
+	"github.com/mark3labs/mcp-go/server"
+	"go.uber.org/zap"
+)
+
+// HTTP endpoint paths served by RunHTTP
+const (
+	StreamablePath   = "/mcp"
+	SSEPath          = "/sse"
+	MessagePath      = "/message"
+	HealthPath       = "/healthz"
+	StatsPath        = "/debug/stats"
+	HistoryPath      = "/debug/history"
+	InteractionsPath = "/debug/interactions"
+	ReplayPath       = "/debug/interactions/replay"
+)
+
+// HTTPOptions configures the HTTP transport
+type HTTPOptions struct {
+	// Addr is the address to listen on, e.g. "127.0.0.1:8080"
+	Addr string
+	// TLSCertFile and TLSKeyFile enable HTTPS when both are set
+	TLSCertFile string
+	TLSKeyFile  string
+	// AuthToken, if set, is a shared bearer token required on every request
+	// when no tenants are configured. Tenant tokens take precedence.
+	AuthToken string
+	// ShutdownTimeout bounds how long in-flight requests may take to finish
+	ShutdownTimeout time.Duration
+}
+
+// Validate checks that the options are usable
+func (o HTTPOptions) Validate() error {
+	if o.Addr == "" {
+		return errors.New("listen address is required")
+	}
+	if (o.TLSCertFile == "") != (o.TLSKeyFile == "") {
+		return errors.New("both a TLS certificate and key are required to enable TLS")
+	}
+	return nil
+}
+
+// HTTPHandler returns a handler serving the MCP Streamable HTTP transport at
+// /mcp, the legacy SSE transport at /sse and /message, a health check, OpenAI
+// spend at /debug/stats, unless it is disabled, validation history at /debug/history,
+// and, when recording is enabled, tool interactions at /debug/interactions with a page
+// replaying them at /debug/interactions/replay.
+func (s *FactCheckServer) HTTPHandler(authToken string) http.Handler {
+	streamable := server.NewStreamableHTTPServer(s.mcpServer,
+		server.WithEndpointPath(StreamablePath),
+		server.WithHeartbeatInterval(30*time.Second),
+	)
+	sse := server.NewSSEServer(s.mcpServer,
+		server.WithSSEEndpoint(SSEPath),
+		server.WithMessageEndpoint(MessagePath),
+		server.WithKeepAlive(true),
+	)
+
+	mux := http.NewServeMux()
+	mux.Handle(StreamablePath, s.authenticate(authToken, protocol.CheckHeader(streamable)))
+	mux.Handle(SSEPath, s.authenticate(authToken, sse.SSEHandler()))
+	mux.Handle(MessagePath, s.authenticate(authToken, sse.MessageHandler()))
+	mux.HandleFunc(HealthPath, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprintln(w, `{"status":"ok"}`)
+	})
+	mux.Handle(StatsPath, s.authenticate(authToken, http.HandlerFunc(s.serveStats)))
+	if s.history != nil {
+		mux.Handle(HistoryPath, s.authenticate(authToken, history.NewPage(s.history)))
+	}
+	if s.interactions != nil {
+		mux.Handle(InteractionsPath, s.authenticate(authToken, interactions.NewHandler(s.interactions)))
+		mux.Handle(ReplayPath, s.authenticate(authToken, interactions.NewReplayPage(s.interactions, s.ReplayInteraction)))
+	}
+	if s.tenants == nil && s.auth != nil {
+		if metadata := s.auth.ProtectedResourceHandler(); metadata != nil {
+			mux.Handle(auth.ProtectedResourcePath, metadata)
+		}
+	}
+	return mux
+}
+
+// RunHTTP serves the MCP server over HTTP until ctx is cancelled
+func (s *FactCheckServer) RunHTTP(ctx context.Context, opts HTTPOptions) error {
+	if err := opts.Validate(); err != nil {
+		return err
+	}
+	if opts.ShutdownTimeout == 0 {
+		opts.ShutdownTimeout = 10 * time.Second
+	}
+
+	if s.tenants == nil && s.auth == nil && opts.AuthToken == "" {
+		logger.Get().Warn("HTTP transport has no authentication; bind to a trusted network only",
+			zap.String("addr", opts.Addr))
+	}
+
+	srv := &http.Server{
+		Addr:              opts.Addr,
+		Handler:           s.HTTPHandler(opts.AuthToken),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	scheme := "http"
+	if opts.TLSCertFile != "" {
+		scheme = "https"
+	}
+	logger.Get().Info("Serving MCP over HTTP", zap.String("url", scheme+"://"+opts.Addr+StreamablePath))
+
+	errCh := make(chan error, 1)
+	go func() {
+		if opts.TLSCertFile != "" {
+			errCh <- srv.ListenAndServeTLS(opts.TLSCertFile, opts.TLSKeyFile)
+			return
+		}
+		errCh <- srv.ListenAndServe()
+	}()
+
+	select {
+	case err := <-errCh:
+		return err
+	case <-ctx.Done():
+	}
+
+	logger.Get().Info("Shutting down HTTP transport")
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), opts.ShutdownTimeout)
+	defer cancel()
+	if err := srv.Shutdown(shutdownCtx); err != nil {
+		return fmt.Errorf("shutting down HTTP transport: %w", err)
+	}
+	if err := <-errCh; err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
+
+// serveStats reports OpenAI spend. Stats span every session, so they are not
+// served to tenants, who must not see each other's sessions.
+func (s *FactCheckServer) serveStats(w http.ResponseWriter, r *http.Request) {
+	if s.tenants != nil {
+		http.NotFound(w, r)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(map[string]any{"cost": cost.Stats(), "callers": s.CallerUsage()})
+}
+
+// authenticate attaches the tenant selected by the request's bearer token. When
+// tenants are configured every request must carry a valid tenant token; otherwise
+// an API key or OAuth token is required when they are configured, and the shared
+// token, if any, when they aren't.
+func (s *FactCheckServer) authenticate(authToken string, next http.Handler) http.Handler {
+	if s.tenants == nil && s.auth != nil {
+		return s.auth.Middleware(next)
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		token := strings.TrimSpace(strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer "))
+		switch {
+		case s.tenants != nil:
+			t, ok := s.tenants.Authenticate(token)
+			if !ok {
+				unauthorized(w)
+				return
+			}
+			r = r.WithContext(tenant.WithTenant(r.Context(), t))
+		case authToken != "":
+			if subtle.ConstantTimeCompare([]byte(token), []byte(authToken)) != 1 {
+				unauthorized(w)
+				return
+			}
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+func unauthorized(w http.ResponseWriter) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("WWW-Authenticate", `Bearer realm="mcp-factcheck"`)
+	w.WriteHeader(http.StatusUnauthorized)
+	fmt.Fprintln(w, `{"error":"missing or invalid bearer token"}`)
+}
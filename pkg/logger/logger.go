@@ -2,15 +2,18 @@ package logger
 
 import (
 	"context"
+	"fmt"
 	"os"
 
 	"github.com/carlisia/mcp-factcheck/pkg/telemetry"
 	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
 )
 
 var (
 	globalLogger *zap.Logger
 	sugar        *zap.SugaredLogger
+	level        = zap.NewAtomicLevelAt(zap.InfoLevel)
 )
 
 // Initialize sets up the global logger with appropriate configuration
@@ -29,6 +32,10 @@ func Initialize(isDevelopment bool) error {
 	config.OutputPaths = []string{"stderr"}
 	config.ErrorOutputPaths = []string{"stderr"}
 	
+	// Share an atomic level so it can be changed at runtime
+	level.SetLevel(config.Level.Level())
+	config.Level = level
+	
 	logger, err := config.Build()
 	if err != nil {
 		return err
@@ -58,9 +65,24 @@ func Sugar() *zap.SugaredLogger {
 	return sugar
 }
 
-// WithRequestID returns a logger with the request ID from context
+// coreKey is the context key of a core that request loggers also write to
+type coreKey struct{}
+
+// WithCore returns a context whose request loggers (see WithRequestID) also write to
+// core, such as one forwarding entries to the MCP client that made the request
+func WithCore(ctx context.Context, core zapcore.Core) context.Context {
+	return context.WithValue(ctx, coreKey{}, core)
+}
+
+// WithRequestID returns a logger with the request ID from context, also writing to
+// the core set with WithCore, if any
 func WithRequestID(ctx context.Context) *zap.Logger {
 	logger := Get()
+	if core, ok := ctx.Value(coreKey{}).(zapcore.Core); ok {
+		logger = logger.WithOptions(zap.WrapCore(func(base zapcore.Core) zapcore.Core {
+			return zapcore.NewTee(base, core)
+		}))
+	}
 	
 	if requestID := telemetry.GetRequestID(ctx); requestID != "" {
 		return logger.With(zap.String("request_id", requestID))
@@ -74,6 +96,21 @@ func WithRequestIDSugar(ctx context.Context) *zap.SugaredLogger {
 	return WithRequestID(ctx).Sugar()
 }
 
+// SetLevel changes the minimum enabled log level at runtime
+func SetLevel(name string) error {
+	l, err := zapcore.ParseLevel(name)
+	if err != nil {
+		return fmt.Errorf("invalid log level %q: %w", name, err)
+	}
+	level.SetLevel(l)
+	return nil
+}
+
+// Level returns the current minimum enabled log level
+func Level() string {
+	return level.Level().String()
+}
+
 // Sync flushes any buffered log entries
 func Sync() {
 	if globalLogger != nil {
@@ -0,0 +1,200 @@
+// Package prompts provides MCP prompts that walk a client through common fact-check workflows
+package prompts
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/carlisia/mcp-factcheck/internal/fetch"
+	"github.com/carlisia/mcp-factcheck/internal/specs"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// Prompt names
+const (
+	FactCheckPostName    = "fact_check_blog_post"
+	ReviewServerName     = "review_server_implementation"
+	SummarizeChangesName = "summarize_spec_differences"
+)
+
+// maxFetchedContentSize caps how much of a fetched post is embedded in the prompt
+const maxFetchedContentSize = 200000
+
+// All returns every prompt with its handler
+func All() []server.ServerPrompt {
+	return []server.ServerPrompt{
+		{Prompt: factCheckPostPrompt(), Handler: handleFactCheckPost},
+		{Prompt: reviewServerPrompt(), Handler: handleReviewServer},
+		{Prompt: summarizeChangesPrompt(), Handler: handleSummarizeChanges},
+	}
+}
+
+func specVersionArgument() mcp.PromptOption {
+	return mcp.WithArgument("specVersion",
+		mcp.ArgumentDescription(fmt.Sprintf("MCP specification version to check against (%s; default %s)", strings.Join(specs.ValidSpecVersions, ", "), specs.DefaultSpecVersion)),
+	)
+}
+
+func factCheckPostPrompt() mcp.Prompt {
+	return mcp.NewPrompt(FactCheckPostName,
+		mcp.WithPromptDescription("Fact-check a blog post or article about MCP and list every inaccurate claim with the correct spec language"),
+		mcp.WithArgument("content",
+			mcp.ArgumentDescription("The post's text, or an http(s) URL to fetch it from"),
+			mcp.RequiredArgument(),
+		),
+		specVersionArgument(),
+	)
+}
+
+func handleFactCheckPost(ctx context.Context, req mcp.GetPromptRequest) (*mcp.GetPromptResult, error) {
+	args := req.Params.Arguments
+	specVersion, err := specVersionArg(args)
+	if err != nil {
+		return nil, err
+	}
+	content := strings.TrimSpace(args["content"])
+	if content == "" {
+		return nil, fmt.Errorf("content is required")
+	}
+	source := "the post below"
+	if fetch.IsURL(content) {
+		source = content
+		// Refuse internal addresses, since callers of a shared server could otherwise
+		// read internal services through the prompt
+		doc, err := fetch.GetPublic(ctx, content)
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch %s: %w", content, err)
+		}
+		text := doc.Text
+		if len(text) > maxFetchedContentSize {
+			text = text[:maxFetchedContentSize]
+		}
+		content = text
+	}
+
+	instructions := fmt.Sprintf(`Fact-check %s against MCP specification %s.
+
+1. Call validate_content with the full text, specVersion "%s", and useChunking true.
+2. For each flagged section, call search_spec to find the exact specification text that applies.
+3. Report every inaccurate, imprecise, or unsupported claim as a list: quote the claim, explain what the specification actually says, cite the spec section, and propose corrected wording.
+4. Finish with a one-paragraph verdict on the post's overall accuracy. Do not call a claim correct unless the tools or spec text confirm it.
+
+--- POST ---
+%s`, source, specVersion, specVersion, content)
+
+	return mcp.NewGetPromptResult(
+		fmt.Sprintf("Fact-check a post against MCP %s", specVersion),
+		[]mcp.PromptMessage{mcp.NewPromptMessage(mcp.RoleUser, mcp.NewTextContent(instructions))},
+	), nil
+}
+
+func reviewServerPrompt() mcp.Prompt {
+	return mcp.NewPrompt(ReviewServerName,
+		mcp.WithPromptDescription("Review an MCP server implementation for protocol compliance"),
+		mcp.WithArgument("code",
+			mcp.ArgumentDescription("Source code of the server, or the parts that implement MCP"),
+			mcp.RequiredArgument(),
+		),
+		mcp.WithArgument("language",
+			mcp.ArgumentDescription("Programming language of the code, e.g. go, typescript, python"),
+		),
+		specVersionArgument(),
+	)
+}
+
+func handleReviewServer(ctx context.Context, req mcp.GetPromptRequest) (*mcp.GetPromptResult, error) {
+	args := req.Params.Arguments
+	specVersion, err := specVersionArg(args)
+	if err != nil {
+		return nil, err
+	}
+	code := args["code"]
+	if strings.TrimSpace(code) == "" {
+		return nil, fmt.Errorf("code is required")
+	}
+	language := args["language"]
+
+	instructions := fmt.Sprintf(`Review this MCP server implementation for compliance with MCP specification %s.
+
+1. Call validate_code with the code, specVersion "%s"%s.
+2. Use search_spec to check the lifecycle (initialize request, capability negotiation, initialized notification), the JSON-RPC message shapes, and the capabilities the server advertises against what it implements.
+3. List each compliance problem with the offending code, the requirement it breaks (quote the spec and note MUST/SHOULD/MAY), and a concrete fix.
+4. Separately list spec features the server advertises but does not implement, and optional features it could add.
+
+--- CODE ---
+%s`, specVersion, specVersion, languageClause(language), fence(code, language))
+
+	return mcp.NewGetPromptResult(
+		fmt.Sprintf("Review an MCP server against MCP %s", specVersion),
+		[]mcp.PromptMessage{mcp.NewPromptMessage(mcp.RoleUser, mcp.NewTextContent(instructions))},
+	), nil
+}
+
+func summarizeChangesPrompt() mcp.Prompt {
+	return mcp.NewPrompt(SummarizeChangesName,
+		mcp.WithPromptDescription("Summarize how the MCP specification changed on a topic between two versions"),
+		mcp.WithArgument("topic",
+			mcp.ArgumentDescription("Topic to compare, e.g. \"transports\" or \"tool annotations\""),
+			mcp.RequiredArgument(),
+		),
+		mcp.WithArgument("fromVersion",
+			mcp.ArgumentDescription("Older specification version"),
+			mcp.RequiredArgument(),
+		),
+		mcp.WithArgument("toVersion",
+			mcp.ArgumentDescription("Newer specification version"),
+			mcp.RequiredArgument(),
+		),
+	)
+}
+
+func handleSummarizeChanges(ctx context.Context, req mcp.GetPromptRequest) (*mcp.GetPromptResult, error) {
+	args := req.Params.Arguments
+	topic := strings.TrimSpace(args["topic"])
+	if topic == "" {
+		return nil, fmt.Errorf("topic is required")
+	}
+	from, to := args["fromVersion"], args["toVersion"]
+	for _, v := range []string{from, to} {
+		if !specs.IsValidSpecVersion(v) {
+			return nil, fmt.Errorf("invalid spec version: %q", v)
+		}
+	}
+
+	instructions := fmt.Sprintf(`Summarize how the MCP specification's treatment of %q changed from %s to %s.
+
+1. Call compare_spec_versions with query %q, fromVersion "%s", and toVersion "%s".
+2. Group the results into added, removed, and reworded requirements. Ignore passages reported as unchanged and changes that only touch links or version numbers.
+3. For each change, quote the old and new wording and say what a documentation writer or implementer must update.
+4. If the comparison finds nothing relevant, say so rather than guessing.`, topic, from, to, topic, from, to)
+
+	return mcp.NewGetPromptResult(
+		fmt.Sprintf("Changes to %q from MCP %s to %s", topic, from, to),
+		[]mcp.PromptMessage{mcp.NewPromptMessage(mcp.RoleUser, mcp.NewTextContent(instructions))},
+	), nil
+}
+
+// specVersionArg returns the requested spec version, or the default when unset
+func specVersionArg(args map[string]string) (string, error) {
+	v := args["specVersion"]
+	if v == "" {
+		return specs.DefaultSpecVersion, nil
+	}
+	if !specs.IsValidSpecVersion(v) {
+		return "", fmt.Errorf("invalid spec version: %s", v)
+	}
+	return v, nil
+}
+
+func languageClause(language string) string {
+	if language == "" {
+		return ""
+	}
+	return fmt.Sprintf(", and language %q", language)
+}
+
+func fence(code, language string) string {
+	return "```" + language + "\n" + strings.TrimRight(code, "\n") + "\n```"
+}
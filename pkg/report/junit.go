@@ -0,0 +1,91 @@
+package report
+
+import (
+	"encoding/xml"
+	"fmt"
+)
+
+type junitTestSuites struct {
+	XMLName  xml.Name         `xml:"testsuites"`
+	Name     string           `xml:"name,attr"`
+	Tests    int              `xml:"tests,attr"`
+	Failures int              `xml:"failures,attr"`
+	Suites   []junitTestSuite `xml:"testsuite"`
+}
+
+type junitTestSuite struct {
+	Name       string          `xml:"name,attr"`
+	Tests      int             `xml:"tests,attr"`
+	Failures   int             `xml:"failures,attr"`
+	Properties []junitProperty `xml:"properties>property,omitempty"`
+	Cases      []junitTestCase `xml:"testcase"`
+}
+
+type junitProperty struct {
+	Name  string `xml:"name,attr"`
+	Value string `xml:"value,attr"`
+}
+
+type junitTestCase struct {
+	Name      string        `xml:"name,attr"`
+	ClassName string        `xml:"classname,attr"`
+	File      string        `xml:"file,attr,omitempty"`
+	Line      int           `xml:"line,attr,omitempty"`
+	Failure   *junitFailure `xml:"failure,omitempty"`
+}
+
+type junitFailure struct {
+	Message string `xml:"message,attr"`
+	Type    string `xml:"type,attr"`
+	Body    string `xml:",chardata"`
+}
+
+// FormatJUnit renders findings as JUnit XML: each document is a test suite and each finding a failed
+// test case. Documents without findings get a single passing case so CI dashboards count them.
+func FormatJUnit(docs []Document) ([]byte, error) {
+	suites := junitTestSuites{Name: ToolName}
+	for _, doc := range docs {
+		path := toSlash(doc.Path)
+		suite := junitTestSuite{
+			Name:       path,
+			Properties: []junitProperty{{Name: "spec_version", Value: doc.SpecVersion}},
+		}
+
+		if len(doc.Findings) == 0 {
+			suite.Cases = append(suite.Cases, junitTestCase{
+				Name:      "matches MCP specification " + doc.SpecVersion,
+				ClassName: path,
+				File:      path,
+			})
+		}
+		for _, f := range doc.Findings {
+			name := f.Message
+			if f.LineNumber > 0 {
+				name = fmt.Sprintf("line %d: %s", f.LineNumber, f.Message)
+			}
+			suite.Cases = append(suite.Cases, junitTestCase{
+				Name:      name,
+				ClassName: path,
+				File:      path,
+				Line:      f.LineNumber,
+				Failure: &junitFailure{
+					Message: f.Message,
+					Type:    f.Severity + "/" + f.Type,
+					Body:    f.FormatErrorMessage(),
+				},
+			})
+			suite.Failures++
+		}
+
+		suite.Tests = len(suite.Cases)
+		suites.Tests += suite.Tests
+		suites.Failures += suite.Failures
+		suites.Suites = append(suites.Suites, suite)
+	}
+
+	data, err := xml.MarshalIndent(suites, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte(xml.Header), append(data, '\n')...), nil
+}
@@ -0,0 +1,107 @@
+package report
+
+import (
+	"bytes"
+	"encoding/json"
+
+	"github.com/carlisia/mcp-factcheck/pkg/validator"
+)
+
+// Reviewdog Diagnostic Format, see https://github.com/reviewdog/reviewdog/tree/master/proto/rdf
+
+type rdResult struct {
+	Source      rdSource       `json:"source"`
+	Diagnostics []rdDiagnostic `json:"diagnostics"`
+}
+
+type rdSource struct {
+	Name string `json:"name"`
+	URL  string `json:"url,omitempty"`
+}
+
+type rdDiagnostic struct {
+	Message  string     `json:"message"`
+	Location rdLocation `json:"location"`
+	Severity string     `json:"severity"`
+	Source   *rdSource  `json:"source,omitempty"`
+	Code     *rdCode    `json:"code,omitempty"`
+}
+
+type rdLocation struct {
+	Path  string   `json:"path"`
+	Range *rdRange `json:"range,omitempty"`
+}
+
+type rdRange struct {
+	Start rdPosition  `json:"start"`
+	End   *rdPosition `json:"end,omitempty"`
+}
+
+type rdPosition struct {
+	Line int `json:"line"`
+}
+
+type rdCode struct {
+	Value string `json:"value"`
+	URL   string `json:"url,omitempty"`
+}
+
+// FormatRDJSON renders findings as a single reviewdog DiagnosticResult (reviewdog -f=rdjson)
+func FormatRDJSON(docs []Document) ([]byte, error) {
+	result := rdResult{
+		Source:      rdSource{Name: ToolName, URL: ToolURI},
+		Diagnostics: []rdDiagnostic{},
+	}
+	for _, doc := range docs {
+		for _, f := range doc.Findings {
+			result.Diagnostics = append(result.Diagnostics, rdDiagnosticFor(doc, f, false))
+		}
+	}
+	return json.MarshalIndent(result, "", "  ")
+}
+
+// FormatRDJSONL renders findings as one reviewdog Diagnostic per line (reviewdog -f=rdjsonl)
+func FormatRDJSONL(docs []Document) ([]byte, error) {
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	for _, doc := range docs {
+		for _, f := range doc.Findings {
+			if err := encoder.Encode(rdDiagnosticFor(doc, f, true)); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return buf.Bytes(), nil
+}
+
+func rdDiagnosticFor(doc Document, f validator.ValidationError, withSource bool) rdDiagnostic {
+	d := rdDiagnostic{
+		Message:  f.FormatErrorMessage(),
+		Location: rdLocation{Path: toSlash(doc.Path)},
+		Severity: RDSeverity(f.Severity),
+		Code:     &rdCode{Value: f.Type},
+	}
+	if f.LineNumber > 0 {
+		d.Location.Range = &rdRange{Start: rdPosition{Line: f.LineNumber}}
+		if f.EndLine > f.LineNumber {
+			d.Location.Range.End = &rdPosition{Line: f.EndLine}
+		}
+	}
+	if withSource {
+		// rdjsonl has no enclosing result, so each line names its source
+		d.Source = &rdSource{Name: ToolName, URL: ToolURI}
+	}
+	return d
+}
+
+// RDSeverity maps a finding severity to a reviewdog severity
+func RDSeverity(severity string) string {
+	switch severity {
+	case validator.SeverityCritical:
+		return "ERROR"
+	case validator.SeverityWarning:
+		return "WARNING"
+	default:
+		return "INFO"
+	}
+}
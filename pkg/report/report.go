@@ -0,0 +1,89 @@
+package report
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/carlisia/mcp-factcheck/pkg/validator"
+)
+
+// Document holds the validation findings for one source file
+type Document struct {
+	Path        string                      `json:"path"`
+	SpecVersion string                      `json:"spec_version"`
+	Findings    []validator.ValidationError `json:"findings"`
+	Score       *validator.Score            `json:"score,omitempty"`
+}
+
+// ToolName identifies this project in machine-readable reports
+const ToolName = "mcp-factcheck"
+
+// ToolURI links report consumers to the project
+const ToolURI = "https://github.com/carlisia/mcp-factcheck"
+
+// ruleDescriptions explains each issue type, used as rule metadata by report formats
+var ruleDescriptions = map[string]string{
+	validator.IssueTypeInaccuracy:  "Claim contradicts or is not supported by the MCP specification",
+	validator.IssueTypeMissing:     "Required MCP specification element is missing",
+	validator.IssueTypeImprecise:   "Language could be more precise or spec-compliant",
+	validator.IssueTypeUnsupported: "Refers to a feature the MCP specification does not support",
+}
+
+// ruleIDs returns the issue types in a stable order
+func ruleIDs() []string {
+	return []string{
+		validator.IssueTypeInaccuracy,
+		validator.IssueTypeMissing,
+		validator.IssueTypeImprecise,
+		validator.IssueTypeUnsupported,
+	}
+}
+
+// Formats lists the output formats accepted by Format
+var Formats = []string{"text", "json", "sarif", "rdjson", "rdjsonl", "junit"}
+
+// Format renders documents in the named output format
+func Format(format string, docs []Document, toolVersion string) ([]byte, error) {
+	switch format {
+	case "text":
+		return FormatText(docs), nil
+	case "json":
+		return json.MarshalIndent(docs, "", "  ")
+	case "sarif":
+		return FormatSARIF(docs, toolVersion)
+	case "rdjson":
+		return FormatRDJSON(docs)
+	case "rdjsonl":
+		return FormatRDJSONL(docs)
+	case "junit":
+		return FormatJUnit(docs)
+	default:
+		return nil, fmt.Errorf("unknown format %q (valid: %s)", format, strings.Join(Formats, ", "))
+	}
+}
+
+// FormatText renders findings as compiler-style "path:line: severity: message" lines
+func FormatText(docs []Document) []byte {
+	var b strings.Builder
+	var total int
+	for _, doc := range docs {
+		for _, f := range doc.Findings {
+			total++
+			location := toSlash(doc.Path)
+			if f.LineNumber > 0 {
+				location = fmt.Sprintf("%s:%d", location, f.LineNumber)
+			}
+			fmt.Fprintf(&b, "%s: %s: %s", location, f.Severity, f.Message)
+			switch {
+			case f.SpecSection != "" && f.SpecURL != "":
+				fmt.Fprintf(&b, " [spec: %s, %s]", f.SpecSection, f.SpecURL)
+			case f.SpecSection != "":
+				fmt.Fprintf(&b, " [spec: %s]", f.SpecSection)
+			}
+			b.WriteString("\n")
+		}
+	}
+	fmt.Fprintf(&b, "%d finding(s) in %d document(s)\n", total, len(docs))
+	return []byte(b.String())
+}
@@ -0,0 +1,183 @@
+package report
+
+import (
+	"encoding/json"
+	"strings"
+
+	"github.com/carlisia/mcp-factcheck/pkg/validator"
+)
+
+const (
+	sarifVersion = "2.1.0"
+	sarifSchema  = "https://json.schemastore.org/sarif-2.1.0.json"
+)
+
+type sarifLog struct {
+	Version string     `json:"version"`
+	Schema  string     `json:"$schema"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool    sarifTool     `json:"tool"`
+	Results []sarifResult `json:"results"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name           string      `json:"name"`
+	Version        string      `json:"version,omitempty"`
+	InformationURI string      `json:"informationUri"`
+	Rules          []sarifRule `json:"rules"`
+}
+
+type sarifRule struct {
+	ID                   string             `json:"id"`
+	Name                 string             `json:"name"`
+	ShortDescription     sarifMessage       `json:"shortDescription"`
+	DefaultConfiguration sarifConfiguration `json:"defaultConfiguration"`
+}
+
+type sarifConfiguration struct {
+	Level string `json:"level"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifResult struct {
+	RuleID     string          `json:"ruleId"`
+	RuleIndex  int             `json:"ruleIndex"`
+	Level      string          `json:"level"`
+	Message    sarifMessage    `json:"message"`
+	Locations  []sarifLocation `json:"locations,omitempty"`
+	Properties map[string]any  `json:"properties,omitempty"`
+}
+
+type sarifLocation struct {
+	PhysicalLocation sarifPhysicalLocation `json:"physicalLocation"`
+}
+
+type sarifPhysicalLocation struct {
+	ArtifactLocation sarifArtifactLocation `json:"artifactLocation"`
+	Region           *sarifRegion          `json:"region,omitempty"`
+}
+
+type sarifArtifactLocation struct {
+	URI string `json:"uri"`
+}
+
+type sarifRegion struct {
+	StartLine int `json:"startLine"`
+	EndLine   int `json:"endLine,omitempty"`
+}
+
+// FormatSARIF renders findings as a SARIF 2.1.0 log for GitHub code scanning and other SARIF consumers
+func FormatSARIF(docs []Document, toolVersion string) ([]byte, error) {
+	ids := ruleIDs()
+	ruleIndex := map[string]int{}
+	rules := make([]sarifRule, len(ids))
+	for i, id := range ids {
+		ruleIndex[id] = i
+		rules[i] = sarifRule{
+			ID:                   id,
+			Name:                 ruleName(id),
+			ShortDescription:     sarifMessage{Text: ruleDescriptions[id]},
+			DefaultConfiguration: sarifConfiguration{Level: "warning"},
+		}
+	}
+
+	results := []sarifResult{}
+	for _, doc := range docs {
+		for _, f := range doc.Findings {
+			index, ok := ruleIndex[f.Type]
+			if !ok {
+				index = len(rules)
+				ruleIndex[f.Type] = index
+				rules = append(rules, sarifRule{
+					ID:                   f.Type,
+					Name:                 ruleName(f.Type),
+					ShortDescription:     sarifMessage{Text: f.Message},
+					DefaultConfiguration: sarifConfiguration{Level: "warning"},
+				})
+			}
+
+			location := sarifLocation{PhysicalLocation: sarifPhysicalLocation{
+				ArtifactLocation: sarifArtifactLocation{URI: toSlash(doc.Path)},
+			}}
+			if f.LineNumber > 0 {
+				location.PhysicalLocation.Region = &sarifRegion{StartLine: f.LineNumber}
+				if f.EndLine > f.LineNumber {
+					location.PhysicalLocation.Region.EndLine = f.EndLine
+				}
+			}
+
+			properties := map[string]any{"spec_version": doc.SpecVersion}
+			if f.SpecSection != "" {
+				properties["spec_section"] = f.SpecSection
+			}
+			if f.SpecURL != "" {
+				properties["spec_url"] = f.SpecURL
+			}
+			if f.Rule != "" {
+				properties["rule"] = f.Rule
+			}
+			if len(f.Suggestions) > 0 {
+				properties["suggestions"] = f.Suggestions
+			}
+
+			results = append(results, sarifResult{
+				RuleID:     f.Type,
+				RuleIndex:  index,
+				Level:      SARIFLevel(f.Severity),
+				Message:    sarifMessage{Text: f.FormatErrorMessage()},
+				Locations:  []sarifLocation{location},
+				Properties: properties,
+			})
+		}
+	}
+
+	log := sarifLog{
+		Version: sarifVersion,
+		Schema:  sarifSchema,
+		Runs: []sarifRun{{
+			Tool: sarifTool{Driver: sarifDriver{
+				Name:           ToolName,
+				Version:        toolVersion,
+				InformationURI: ToolURI,
+				Rules:          rules,
+			}},
+			Results: results,
+		}},
+	}
+	return json.MarshalIndent(log, "", "  ")
+}
+
+// SARIFLevel maps a finding severity to a SARIF result level
+func SARIFLevel(severity string) string {
+	switch severity {
+	case validator.SeverityCritical:
+		return "error"
+	case validator.SeverityWarning:
+		return "warning"
+	default:
+		return "note"
+	}
+}
+
+// ruleName turns an issue type into a PascalCase rule name
+func ruleName(id string) string {
+	var b strings.Builder
+	for _, part := range strings.FieldsFunc(id, func(r rune) bool { return r == '_' || r == '-' }) {
+		b.WriteString(strings.ToUpper(part[:1]) + part[1:])
+	}
+	return b.String()
+}
+
+func toSlash(path string) string {
+	return strings.ReplaceAll(path, "\\", "/")
+}
@@ -0,0 +1,354 @@
+// Package schema validates raw JSON-RPC messages against the MCP message schema of
+// each specification version. Unlike the embedding-based validators it checks
+// structure exactly: method names, field names and types, required fields, and
+// error codes.
+package schema
+
+// Field types
+const (
+	TypeString  = "string"
+	TypeNumber  = "number"
+	TypeInteger = "integer"
+	TypeBoolean = "boolean"
+	TypeObject  = "object"
+	TypeArray   = "array"
+	TypeID      = "string or number" // request IDs and progress tokens
+	TypeAny     = "any"
+)
+
+// Message kinds
+const (
+	KindRequest      = "request"
+	KindNotification = "notification"
+	KindResponse     = "response"
+	KindError        = "error response"
+	KindBatch        = "batch"
+)
+
+// field describes one property of a message object
+type field struct {
+	Name     string
+	Type     string
+	Required bool
+	Enum     []string
+	Fields   []field // properties of an object; nil leaves the object unchecked
+	Items    []field // properties of each object in an array; nil leaves items unchecked
+	Since    string  // first spec version that defines the field; empty for all
+}
+
+// method describes a request or notification and the shape of its params and result
+type method struct {
+	Name   string
+	Kind   string
+	Params []field // nil for methods without params
+	Result []field // nil for notifications
+	Since  string
+}
+
+// since sets the first spec version that defines f
+func (f field) since(version string) field {
+	f.Since = version
+	return f
+}
+
+func str(name string) field     { return field{Name: name, Type: TypeString} }
+func num(name string) field     { return field{Name: name, Type: TypeNumber} }
+func boolean(name string) field { return field{Name: name, Type: TypeBoolean} }
+func obj(name string, fields ...field) field {
+	return field{Name: name, Type: TypeObject, Fields: fields}
+}
+
+// openObj is an object whose properties aren't defined by the schema
+func openObj(name string) field { return field{Name: name, Type: TypeObject} }
+
+func arr(name string, items ...field) field {
+	return field{Name: name, Type: TypeArray, Items: items}
+}
+
+func required(f field) field {
+	f.Required = true
+	return f
+}
+
+func enum(f field, values ...string) field {
+	f.Enum = values
+	return f
+}
+
+var (
+	loggingLevels = []string{"debug", "info", "notice", "warning", "error", "critical", "alert", "emergency"}
+
+	cursorParams = []field{str("cursor")}
+
+	implementation = []field{required(str("name")), required(str("version")), str("title").since("2025-06-18")}
+
+	contentItem = []field{
+		required(enum(str("type"), "text", "image", "audio", "resource", "resource_link")),
+		str("text"),
+		str("data"),
+		str("mimeType"),
+		openObj("resource"),
+		openObj("annotations"),
+		str("uri"),
+		str("name"),
+		str("title").since("2025-06-18"),
+		str("description"),
+		num("size"),
+	}
+
+	listChanged = []field{boolean("listChanged")}
+)
+
+// methods lists every method defined by the MCP specification, with the version
+// that introduced it
+var methods = []method{
+	{
+		Name: "initialize",
+		Kind: KindRequest,
+		Params: []field{
+			required(str("protocolVersion")),
+			required(obj("capabilities",
+				openObj("experimental"),
+				obj("roots", listChanged...),
+				openObj("sampling"),
+				openObj("elicitation").since("2025-06-18"),
+			)),
+			required(obj("clientInfo", implementation...)),
+		},
+		Result: []field{
+			required(str("protocolVersion")),
+			required(obj("capabilities",
+				openObj("experimental"),
+				openObj("logging"),
+				openObj("completions").since("2025-03-26"),
+				obj("prompts", listChanged...),
+				obj("resources", boolean("subscribe"), boolean("listChanged")),
+				obj("tools", listChanged...),
+			)),
+			required(obj("serverInfo", implementation...)),
+			str("instructions"),
+		},
+	},
+	{Name: "ping", Kind: KindRequest, Result: []field{}},
+	{
+		Name:   "resources/list",
+		Kind:   KindRequest,
+		Params: cursorParams,
+		Result: []field{
+			required(arr("resources", required(str("uri")), required(str("name")), str("title").since("2025-06-18"), str("description"), str("mimeType"), num("size"), openObj("annotations"))),
+			str("nextCursor"),
+		},
+	},
+	{
+		Name:   "resources/templates/list",
+		Kind:   KindRequest,
+		Params: cursorParams,
+		Result: []field{
+			required(arr("resourceTemplates", required(str("uriTemplate")), required(str("name")), str("title").since("2025-06-18"), str("description"), str("mimeType"), openObj("annotations"))),
+			str("nextCursor"),
+		},
+	},
+	{
+		Name:   "resources/read",
+		Kind:   KindRequest,
+		Params: []field{required(str("uri"))},
+		Result: []field{required(arr("contents", required(str("uri")), str("mimeType"), str("text"), str("blob")))},
+	},
+	{Name: "resources/subscribe", Kind: KindRequest, Params: []field{required(str("uri"))}, Result: []field{}},
+	{Name: "resources/unsubscribe", Kind: KindRequest, Params: []field{required(str("uri"))}, Result: []field{}},
+	{
+		Name:   "prompts/list",
+		Kind:   KindRequest,
+		Params: cursorParams,
+		Result: []field{
+			required(arr("prompts", required(str("name")), str("title").since("2025-06-18"), str("description"), arr("arguments"))),
+			str("nextCursor"),
+		},
+	},
+	{
+		Name:   "prompts/get",
+		Kind:   KindRequest,
+		Params: []field{required(str("name")), openObj("arguments")},
+		Result: []field{
+			str("description"),
+			required(arr("messages", required(enum(str("role"), "user", "assistant")), required(openObj("content")))),
+		},
+	},
+	{
+		Name:   "tools/list",
+		Kind:   KindRequest,
+		Params: cursorParams,
+		Result: []field{
+			required(arr("tools",
+				required(str("name")),
+				str("title").since("2025-06-18"),
+				str("description"),
+				required(openObj("inputSchema")),
+				openObj("outputSchema").since("2025-06-18"),
+				openObj("annotations").since("2025-03-26"),
+			)),
+			str("nextCursor"),
+		},
+	},
+	{
+		Name:   "tools/call",
+		Kind:   KindRequest,
+		Params: []field{required(str("name")), openObj("arguments")},
+		Result: []field{
+			required(arr("content", contentItem...)),
+			openObj("structuredContent").since("2025-06-18"),
+			boolean("isError"),
+		},
+	},
+	{
+		Name:   "logging/setLevel",
+		Kind:   KindRequest,
+		Params: []field{required(enum(str("level"), loggingLevels...))},
+		Result: []field{},
+	},
+	{
+		Name: "completion/complete",
+		Kind: KindRequest,
+		Params: []field{
+			required(obj("ref", required(enum(str("type"), "ref/prompt", "ref/resource")), str("name"), str("uri"), str("title").since("2025-06-18"))),
+			required(obj("argument", required(str("name")), required(str("value")))),
+			obj("context", openObj("arguments")).since("2025-06-18"),
+		},
+		Result: []field{
+			required(obj("completion", required(arr("values")), num("total"), boolean("hasMore"))),
+		},
+	},
+	{
+		Name: "sampling/createMessage",
+		Kind: KindRequest,
+		Params: []field{
+			required(arr("messages", required(enum(str("role"), "user", "assistant")), required(openObj("content")))),
+			openObj("modelPreferences"),
+			str("systemPrompt"),
+			enum(str("includeContext"), "none", "thisServer", "allServers"),
+			num("temperature"),
+			required(field{Name: "maxTokens", Type: TypeInteger}),
+			arr("stopSequences"),
+			openObj("metadata"),
+		},
+		Result: []field{
+			required(enum(str("role"), "user", "assistant")),
+			required(openObj("content")),
+			required(str("model")),
+			str("stopReason"),
+		},
+	},
+	{
+		Name:   "roots/list",
+		Kind:   KindRequest,
+		Result: []field{required(arr("roots", required(str("uri")), str("name")))},
+	},
+	{
+		Name:   "elicitation/create",
+		Kind:   KindRequest,
+		Params: []field{required(str("message")), required(openObj("requestedSchema"))},
+		Result: []field{
+			required(enum(str("action"), "accept", "decline", "cancel")),
+			openObj("content"),
+		},
+		Since: "2025-06-18",
+	},
+	{Name: "notifications/initialized", Kind: KindNotification},
+	{
+		Name:   "notifications/cancelled",
+		Kind:   KindNotification,
+		Params: []field{required(field{Name: "requestId", Type: TypeID}), str("reason")},
+	},
+	{
+		Name: "notifications/progress",
+		Kind: KindNotification,
+		Params: []field{
+			required(field{Name: "progressToken", Type: TypeID}),
+			required(num("progress")),
+			num("total"),
+			str("message").since("2025-03-26"),
+		},
+	},
+	{
+		Name: "notifications/message",
+		Kind: KindNotification,
+		Params: []field{
+			required(enum(str("level"), loggingLevels...)),
+			str("logger"),
+			required(field{Name: "data", Type: TypeAny}),
+		},
+	},
+	{Name: "notifications/resources/updated", Kind: KindNotification, Params: []field{required(str("uri"))}},
+	{Name: "notifications/resources/list_changed", Kind: KindNotification},
+	{Name: "notifications/prompts/list_changed", Kind: KindNotification},
+	{Name: "notifications/tools/list_changed", Kind: KindNotification},
+	{Name: "notifications/roots/list_changed", Kind: KindNotification},
+}
+
+// contentTypesSince records content block types added after the first spec version
+var contentTypesSince = map[string]string{
+	"audio":         "2025-03-26",
+	"resource_link": "2025-06-18",
+}
+
+// JSON-RPC error codes
+var errorCodes = map[int]string{
+	-32700: "Parse error",
+	-32600: "Invalid Request",
+	-32601: "Method not found",
+	-32602: "Invalid params",
+	-32603: "Internal error",
+	-32002: "Resource not found",
+}
+
+// lookupMethod returns the definition of a method, regardless of version
+func lookupMethod(name string) (method, bool) {
+	for _, m := range methods {
+		if m.Name == name {
+			return m, true
+		}
+	}
+	return method{}, false
+}
+
+// definedIn reports whether something introduced in since exists in version.
+// Released versions are dates, so they compare as strings; the draft is newer
+// than every release.
+func definedIn(version, since string) bool {
+	switch {
+	case since == "" || version == "draft":
+		return true
+	case since == "draft":
+		return false
+	default:
+		return version >= since
+	}
+}
+
+// MethodInfo describes an MCP method for callers outside this package
+type MethodInfo struct {
+	Name  string
+	Kind  string // KindRequest or KindNotification
+	Since string // first spec version that defines the method; empty for all
+}
+
+// DefinedIn reports whether the method exists in a spec version
+func (m MethodInfo) DefinedIn(version string) bool {
+	return definedIn(version, m.Since)
+}
+
+// LookupMethod returns the definition of an MCP method name in any spec version
+func LookupMethod(name string) (MethodInfo, bool) {
+	m, ok := lookupMethod(name)
+	if !ok {
+		return MethodInfo{}, false
+	}
+	return MethodInfo{Name: m.Name, Kind: m.Kind, Since: m.Since}, true
+}
+
+// ClosestMethod returns the method defined in version that name is most likely a
+// typo of, or "" if none is close
+func ClosestMethod(name, version string) string {
+	v := &validator{version: version}
+	return closest(name, v.methodNames())
+}
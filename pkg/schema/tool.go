@@ -0,0 +1,71 @@
+package schema
+
+import (
+	"encoding/json"
+	"strings"
+
+	"github.com/carlisia/mcp-factcheck/internal/specs"
+	"github.com/carlisia/mcp-factcheck/internal/toolerr"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+const ValidateMessageToolName = "validate_message"
+
+func GetValidateMessageTool() mcp.Tool {
+	schema := map[string]any{
+		"type": "object",
+		"properties": map[string]any{
+			"message": map[string]any{
+				"type":        "string",
+				"description": "Raw JSON-RPC message to validate: a request, notification, response, or error response",
+			},
+			"specVersion": map[string]any{
+				"type":        "string",
+				"description": "MCP specification version to validate against",
+				"enum":        specs.ValidSpecVersions,
+				"default":     specs.DefaultSpecVersion,
+			},
+			"method": map[string]any{
+				"type":        "string",
+				"description": "For responses, the method of the request being answered (e.g. \"tools/call\"), so the result can be checked against that method's schema",
+			},
+		},
+		"required": []string{"message"},
+	}
+	schemaBytes, _ := json.Marshal(schema)
+	return mcp.NewToolWithRawSchema(ValidateMessageToolName, "Validate a raw MCP JSON-RPC message against the message schema of a specification version. Reports exact structural violations (unknown methods, misspelled or missing fields, wrong types, invalid error codes) with the JSON path of each, rather than a similarity score.", schemaBytes)
+}
+
+func HandleValidateMessage(args any) ([]mcp.Content, error) {
+	params, ok := args.(map[string]any)
+	if !ok {
+		return nil, toolerr.InvalidArgs("arguments must be a map")
+	}
+
+	var raw []byte
+	switch message := params["message"].(type) {
+	case string:
+		if strings.TrimSpace(message) == "" {
+			return nil, toolerr.InvalidArgs("message must be a non-empty string")
+		}
+		raw = []byte(message)
+	case map[string]any, []any:
+		// Clients sometimes send the message as JSON rather than a string
+		raw, _ = json.Marshal(message)
+	default:
+		return nil, toolerr.InvalidArgs("message must be a JSON-RPC message string")
+	}
+
+	specVersion, ok := params["specVersion"].(string)
+	if !ok || specVersion == "" {
+		specVersion = specs.DefaultSpecVersion
+	}
+	if !specs.IsValidSpecVersion(specVersion) {
+		return nil, toolerr.SpecVersionNotFound(specVersion)
+	}
+	method, _ := params["method"].(string)
+
+	result := Validate(raw, specVersion, method)
+	jsonBytes, _ := json.MarshalIndent(result, "", "  ")
+	return []mcp.Content{mcp.NewTextContent(string(jsonBytes))}, nil
+}
@@ -0,0 +1,497 @@
+package schema
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+)
+
+// Violation severities
+const (
+	SeverityError   = "error"
+	SeverityWarning = "warning"
+)
+
+// Violation is one structural problem in a message
+type Violation struct {
+	Path     string `json:"path"` // JSON path of the offending value, e.g. "params.clientInfo.name"
+	Severity string `json:"severity"`
+	Message  string `json:"message"`
+}
+
+// Result is the outcome of validating a message
+type Result struct {
+	Valid       bool        `json:"valid"`
+	Kind        string      `json:"kind,omitempty"`
+	Method      string      `json:"method,omitempty"`
+	SpecVersion string      `json:"spec_version"`
+	Violations  []Violation `json:"violations"`
+}
+
+// jsonrpcMembers are the only members a JSON-RPC 2.0 message may have
+var jsonrpcMembers = []string{"jsonrpc", "id", "method", "params", "result", "error"}
+
+// validator accumulates violations for one message
+type validator struct {
+	version    string
+	violations []Violation
+}
+
+func (v *validator) add(severity, path, format string, args ...any) {
+	v.violations = append(v.violations, Violation{Path: path, Severity: severity, Message: fmt.Sprintf(format, args...)})
+}
+
+// Validate checks a raw JSON-RPC message, or a batch of messages, against the MCP
+// schema of specVersion. responseTo names the request method a response answers;
+// when empty, a response's result is only checked for JSON-RPC conformance.
+func Validate(raw []byte, specVersion, responseTo string) Result {
+	v := &validator{version: specVersion}
+	result := Result{SpecVersion: specVersion}
+
+	decoder := json.NewDecoder(bytes.NewReader(raw))
+	decoder.UseNumber()
+	var message any
+	if err := decoder.Decode(&message); err != nil {
+		v.add(SeverityError, "$", "message is not valid JSON: %v", err)
+		return v.finish(result)
+	}
+	if decoder.More() {
+		v.add(SeverityError, "$", "message contains more than one JSON value")
+	}
+
+	switch m := message.(type) {
+	case map[string]any:
+		result.Kind, result.Method = v.message("$", m, responseTo)
+	case []any:
+		result.Kind = KindBatch
+		v.batch(m, responseTo)
+	default:
+		v.add(SeverityError, "$", "message must be a JSON object, got %s", jsonType(message))
+	}
+	return v.finish(result)
+}
+
+// finish attaches the accumulated violations to result
+func (v *validator) finish(result Result) Result {
+	result.Violations = v.violations
+	if result.Violations == nil {
+		result.Violations = []Violation{}
+	}
+	result.Valid = !slices.ContainsFunc(result.Violations, func(viol Violation) bool { return viol.Severity == SeverityError })
+	return result
+}
+
+// batch validates a JSON-RPC batch, which only the 2025-03-26 spec supports
+func (v *validator) batch(messages []any, responseTo string) {
+	switch {
+	case v.version == "2025-03-26":
+	case definedIn(v.version, "2025-06-18"):
+		v.add(SeverityError, "$", "JSON-RPC batching was removed in 2025-06-18; send each message separately")
+	default:
+		v.add(SeverityWarning, "$", "JSON-RPC batching is not described by MCP %s; support was added in 2025-03-26", v.version)
+	}
+	if len(messages) == 0 {
+		v.add(SeverityError, "$", "a batch must contain at least one message")
+	}
+	for i, message := range messages {
+		path := fmt.Sprintf("[%d]", i)
+		m, ok := message.(map[string]any)
+		if !ok {
+			v.add(SeverityError, path, "batch entries must be JSON objects, got %s", jsonType(message))
+			continue
+		}
+		v.message(path, m, responseTo)
+	}
+}
+
+// message validates one JSON-RPC message and returns its kind and method
+func (v *validator) message(path string, m map[string]any, responseTo string) (string, string) {
+	if jsonrpc, ok := m["jsonrpc"]; !ok {
+		v.add(SeverityError, join(path, "jsonrpc"), `missing required field; must be "2.0"`)
+	} else if jsonrpc != "2.0" {
+		v.add(SeverityError, join(path, "jsonrpc"), `must be the string "2.0", got %s`, describe(jsonrpc))
+	}
+	for _, key := range sortedKeys(m) {
+		if !slices.Contains(jsonrpcMembers, key) {
+			v.add(SeverityError, join(path, key), "unknown JSON-RPC member%s", suggestion(key, jsonrpcMembers))
+		}
+	}
+
+	_, hasMethod := m["method"]
+	_, hasResult := m["result"]
+	_, hasError := m["error"]
+	switch {
+	case hasMethod:
+		if hasResult || hasError {
+			v.add(SeverityError, path, "a message with a method cannot also carry result or error")
+		}
+		return v.request(path, m)
+	case hasResult && hasError:
+		v.add(SeverityError, path, "a response must contain either result or error, not both")
+		return KindResponse, responseTo
+	case hasResult:
+		v.responseID(path, m, false)
+		v.result(path, m["result"], responseTo)
+		return KindResponse, responseTo
+	case hasError:
+		v.responseID(path, m, true)
+		v.errorObject(join(path, "error"), m["error"])
+		return KindError, responseTo
+	default:
+		v.add(SeverityError, path, "message has no method, result, or error")
+		return "", ""
+	}
+}
+
+// request validates a request or notification
+func (v *validator) request(path string, m map[string]any) (string, string) {
+	name, ok := m["method"].(string)
+	if !ok {
+		v.add(SeverityError, join(path, "method"), "must be a string, got %s", describe(m["method"]))
+		return "", ""
+	}
+	id, hasID := m["id"]
+	kind := KindRequest
+	if !hasID {
+		kind = KindNotification
+	}
+
+	def, known := lookupMethod(name)
+	switch {
+	case !known:
+		v.add(SeverityError, join(path, "method"), "%q is not defined in MCP %s%s", name, v.version, suggestion(name, v.methodNames()))
+	case !definedIn(v.version, def.Since):
+		v.add(SeverityError, join(path, "method"), "%q was introduced in %s and is not available in MCP %s", name, def.Since, v.version)
+	}
+
+	if hasID {
+		v.requestID(join(path, "id"), id)
+	}
+	if known {
+		switch {
+		case def.Kind == KindNotification && hasID:
+			v.add(SeverityError, join(path, "id"), "%q is a notification and must not include an id", name)
+		case def.Kind == KindRequest && !hasID:
+			v.add(SeverityError, path, "%q is a request and requires an id; only notifications omit it", name)
+		}
+	}
+
+	params, hasParams := m["params"]
+	switch {
+	case !hasParams:
+		if known && slices.ContainsFunc(def.Params, func(f field) bool { return f.Required && definedIn(v.version, f.Since) }) {
+			v.add(SeverityError, join(path, "params"), "missing required params for %q", name)
+		}
+	case !isObject(params):
+		v.add(SeverityError, join(path, "params"), "must be an object, got %s", jsonType(params))
+	case known:
+		v.object(join(path, "params"), params.(map[string]any), def.Params, true)
+	}
+	return kind, name
+}
+
+// requestID checks a request ID, which MCP restricts to strings and integers
+func (v *validator) requestID(path string, id any) {
+	switch id := id.(type) {
+	case string:
+	case json.Number:
+		if !isInteger(id) {
+			v.add(SeverityWarning, path, "request IDs should be integers when numeric, got %s", id)
+		}
+	case nil:
+		v.add(SeverityError, path, "must not be null in MCP; use a string or integer")
+	default:
+		v.add(SeverityError, path, "must be a string or integer, got %s", jsonType(id))
+	}
+}
+
+// responseID checks a response ID; error responses may use null when the request ID
+// couldn't be determined
+func (v *validator) responseID(path string, m map[string]any, isError bool) {
+	id, ok := m["id"]
+	switch {
+	case !ok:
+		v.add(SeverityError, join(path, "id"), "missing required field; a response must echo the request id")
+	case id == nil && isError:
+	default:
+		v.requestID(join(path, "id"), id)
+	}
+}
+
+// result validates a success response's result, against the method's result schema when known
+func (v *validator) result(path string, result any, responseTo string) {
+	path = join(path, "result")
+	obj, ok := result.(map[string]any)
+	if !ok {
+		v.add(SeverityError, path, "must be an object, got %s", jsonType(result))
+		return
+	}
+	if responseTo == "" {
+		return
+	}
+	def, known := lookupMethod(responseTo)
+	switch {
+	case !known:
+		v.add(SeverityWarning, "$", "cannot check the result: %q is not an MCP method%s", responseTo, suggestion(responseTo, v.methodNames()))
+	case def.Kind == KindNotification:
+		v.add(SeverityError, "$", "%q is a notification; notifications never receive a response", responseTo)
+	default:
+		v.object(path, obj, def.Result, true)
+	}
+}
+
+// errorObject validates a JSON-RPC error object
+func (v *validator) errorObject(path string, value any) {
+	obj, ok := value.(map[string]any)
+	if !ok {
+		v.add(SeverityError, path, "must be an object with code and message, got %s", jsonType(value))
+		return
+	}
+	code, ok := obj["code"].(json.Number)
+	switch {
+	case obj["code"] == nil:
+		v.add(SeverityError, join(path, "code"), "missing required field")
+	case !ok || !isInteger(code):
+		v.add(SeverityError, join(path, "code"), "must be an integer, got %s", describe(obj["code"]))
+	default:
+		n, _ := code.Int64()
+		if _, defined := errorCodes[int(n)]; !defined && n >= -32768 && n < -32099 {
+			v.add(SeverityError, join(path, "code"), "%d is in the range reserved by JSON-RPC but is not a defined error code; use -32700, -32600 to -32603, or the server range -32099 to -32000", n)
+		}
+	}
+	if obj["message"] == nil {
+		v.add(SeverityError, join(path, "message"), "missing required field")
+	} else if _, ok := obj["message"].(string); !ok {
+		v.add(SeverityError, join(path, "message"), "must be a string, got %s", jsonType(obj["message"]))
+	}
+	for _, key := range sortedKeys(obj) {
+		if !slices.Contains([]string{"code", "message", "data"}, key) {
+			v.add(SeverityError, join(path, key), "unknown error member%s", suggestion(key, []string{"code", "message", "data"}))
+		}
+	}
+}
+
+// object checks an object's properties against fields. allowMeta permits the
+// reserved _meta property that MCP allows on params and results.
+func (v *validator) object(path string, obj map[string]any, fields []field, allowMeta bool) {
+	var names []string
+	for _, f := range fields {
+		names = append(names, f.Name)
+		value, present := obj[f.Name]
+		switch {
+		case !present:
+			if f.Required && definedIn(v.version, f.Since) {
+				v.add(SeverityError, join(path, f.Name), "missing required field")
+			}
+		case !definedIn(v.version, f.Since):
+			v.add(SeverityWarning, join(path, f.Name), "field was introduced in %s; MCP %s receivers will ignore it", f.Since, v.version)
+		default:
+			v.value(join(path, f.Name), value, f)
+		}
+	}
+
+	for _, key := range sortedKeys(obj) {
+		if slices.Contains(names, key) || (allowMeta && key == "_meta") {
+			continue
+		}
+		if hint := suggestion(key, names); hint != "" {
+			v.add(SeverityError, join(path, key), "unknown field%s", hint)
+		} else {
+			v.add(SeverityWarning, join(path, key), "field is not defined by MCP %s", v.version)
+		}
+	}
+}
+
+// value checks a single value's type, enum, and nested properties
+func (v *validator) value(path string, value any, f field) {
+	if !hasType(value, f.Type) {
+		v.add(SeverityError, path, "must be %s, got %s", article(f.Type), jsonType(value))
+		return
+	}
+	if len(f.Enum) > 0 {
+		s, _ := value.(string)
+		if !slices.Contains(f.Enum, s) {
+			v.add(SeverityError, path, "must be one of %s, got %s", strings.Join(f.Enum, ", "), describe(value))
+		} else if since, ok := contentTypesSince[s]; ok && f.Name == "type" && !definedIn(v.version, since) {
+			v.add(SeverityError, path, "%q content was introduced in %s and is not available in MCP %s", s, since, v.version)
+		}
+	}
+	switch value := value.(type) {
+	case map[string]any:
+		if f.Fields != nil {
+			v.object(path, value, f.Fields, true)
+		}
+	case []any:
+		if f.Items == nil {
+			return
+		}
+		for i, item := range value {
+			itemPath := fmt.Sprintf("%s[%d]", path, i)
+			obj, ok := item.(map[string]any)
+			if !ok {
+				v.add(SeverityError, itemPath, "must be an object, got %s", jsonType(item))
+				continue
+			}
+			v.object(itemPath, obj, f.Items, true)
+		}
+	}
+}
+
+// methodNames lists the methods defined in the validator's spec version
+func (v *validator) methodNames() []string {
+	var names []string
+	for _, m := range methods {
+		if definedIn(v.version, m.Since) {
+			names = append(names, m.Name)
+		}
+	}
+	return names
+}
+
+func hasType(value any, typ string) bool {
+	switch typ {
+	case TypeAny:
+		return true
+	case TypeString:
+		_, ok := value.(string)
+		return ok
+	case TypeNumber:
+		_, ok := value.(json.Number)
+		return ok
+	case TypeInteger:
+		n, ok := value.(json.Number)
+		return ok && isInteger(n)
+	case TypeBoolean:
+		_, ok := value.(bool)
+		return ok
+	case TypeObject:
+		return isObject(value)
+	case TypeArray:
+		_, ok := value.([]any)
+		return ok
+	case TypeID:
+		if _, ok := value.(string); ok {
+			return true
+		}
+		n, ok := value.(json.Number)
+		return ok && isInteger(n)
+	}
+	return false
+}
+
+func isObject(value any) bool {
+	_, ok := value.(map[string]any)
+	return ok
+}
+
+func isInteger(n json.Number) bool {
+	_, err := n.Int64()
+	return err == nil
+}
+
+// jsonType names the JSON type of a decoded value
+func jsonType(value any) string {
+	switch value := value.(type) {
+	case nil:
+		return "null"
+	case string:
+		return "a string"
+	case json.Number:
+		if isInteger(value) {
+			return "an integer"
+		}
+		return "a number"
+	case bool:
+		return "a boolean"
+	case []any:
+		return "an array"
+	case map[string]any:
+		return "an object"
+	}
+	return fmt.Sprintf("%T", value)
+}
+
+// describe renders a scalar value for a message, or its type for containers
+func describe(value any) string {
+	switch value := value.(type) {
+	case string:
+		return fmt.Sprintf("%q", value)
+	case json.Number:
+		return value.String()
+	case bool:
+		return fmt.Sprintf("%t", value)
+	}
+	return jsonType(value)
+}
+
+func article(typ string) string {
+	switch typ {
+	case TypeObject, TypeArray, TypeInteger:
+		return "an " + typ
+	case TypeID:
+		return "a string or integer"
+	}
+	return "a " + typ
+}
+
+// join appends a property to a path; "$" is the message itself
+func join(path, key string) string {
+	if path == "$" {
+		return key
+	}
+	return path + "." + key
+}
+
+func sortedKeys(m map[string]any) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// suggestion returns a "; did you mean" hint when name is a likely typo of a candidate
+func suggestion(name string, candidates []string) string {
+	if best := closest(name, candidates); best != "" {
+		return fmt.Sprintf("; did you mean %q?", best)
+	}
+	return ""
+}
+
+// closest returns the candidate name is most likely a typo of, or "" if none is close
+func closest(name string, candidates []string) string {
+	// Short names are only matched for near misses
+	limit := max(1, min(2, len(name)/4))
+	best, bestDistance := "", limit+1
+	for _, c := range candidates {
+		if d := editDistance(strings.ToLower(name), strings.ToLower(c)); d < bestDistance {
+			best, bestDistance = c, d
+		}
+	}
+	return best
+}
+
+// editDistance is the Levenshtein distance between a and b
+func editDistance(a, b string) int {
+	prev := make([]int, len(b)+1)
+	curr := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		curr[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+	return prev[len(b)]
+}
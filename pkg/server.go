@@ -2,11 +2,42 @@ package pkg
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"os"
+	"os/signal"
+	"slices"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
 
 	"github.com/carlisia/mcp-factcheck/embedding"
+	"github.com/carlisia/mcp-factcheck/internal/audit"
+	"github.com/carlisia/mcp-factcheck/internal/auth"
+	"github.com/carlisia/mcp-factcheck/internal/clientlog"
+	"github.com/carlisia/mcp-factcheck/internal/cost"
+	"github.com/carlisia/mcp-factcheck/internal/features"
+	"github.com/carlisia/mcp-factcheck/internal/feedback"
+	"github.com/carlisia/mcp-factcheck/internal/history"
+	"github.com/carlisia/mcp-factcheck/internal/interactions"
+	"github.com/carlisia/mcp-factcheck/internal/limits"
+	"github.com/carlisia/mcp-factcheck/internal/protocol"
+	"github.com/carlisia/mcp-factcheck/internal/roots"
+	"github.com/carlisia/mcp-factcheck/internal/sampling"
+	"github.com/carlisia/mcp-factcheck/internal/session"
+	"github.com/carlisia/mcp-factcheck/internal/specs"
 	mcpembedding "github.com/carlisia/mcp-factcheck/internal/embedding"
+	"github.com/carlisia/mcp-factcheck/internal/tenant"
+	"github.com/carlisia/mcp-factcheck/internal/toolargs"
+	"github.com/carlisia/mcp-factcheck/internal/toolerr"
+	"github.com/carlisia/mcp-factcheck/internal/version"
+	"github.com/carlisia/mcp-factcheck/pkg/conformance"
+	"github.com/carlisia/mcp-factcheck/pkg/factcheck"
 	"github.com/carlisia/mcp-factcheck/pkg/logger"
+	"github.com/carlisia/mcp-factcheck/pkg/prompts"
+	"github.com/carlisia/mcp-factcheck/pkg/schema"
 	"github.com/carlisia/mcp-factcheck/pkg/spec"
 	"github.com/carlisia/mcp-factcheck/pkg/telemetry"
 	"github.com/carlisia/mcp-factcheck/pkg/validator"
@@ -17,54 +48,547 @@ import (
 
 // FactCheckServer wraps the actual MCP server with fact-check specific functionality
 type FactCheckServer struct {
-	vectorDB   *mcpembedding.VectorDB
-	generator  *embedding.Generator
+	client     *factcheck.Client // Validation for requests without a tenant
 	mcpServer  *server.MCPServer
 	provider   any
 	middleware any
+
+	experimentalMu    sync.Mutex
+	experimentalTools []experimentalTool
+
+	tenants      *tenant.Registry
+	stdioTenant  *tenant.Tenant
+	auth         *auth.Authenticator // API keys and OAuth tokens for the HTTP transport; nil when not configured
+	limits       *limits.Enforcer
+	feedback     *feedback.Store
+	history      *history.Store      // nil when history is disabled
+	interactions *interactions.Store // nil unless interactions are recorded
+	audit        *audit.Log          // nil unless audit logging is enabled
+	sampling     *sampling.Client    // Sends sampling requests to the stdio client
+	sessions     *session.Store      // Documents validated in each MCP session
+}
+
+// experimentalTool is a tool exposed only while its feature flag is enabled
+type experimentalTool struct {
+	flag       features.Flag
+	tool       server.ServerTool
+	registered bool
 }
 
 // NewFactCheckServer creates a new fact-check server instance using clean telemetry abstractions
 func NewFactCheckServer(dataDir string, provider any, middleware any) (*FactCheckServer, error) {
-	vectorDB := mcpembedding.NewVectorDB(dataDir)
-
-	generator, err := embedding.NewGenerator()
+	client, err := factcheck.New(factcheck.Options{DataDir: dataDir})
 	if err != nil {
-		return nil, fmt.Errorf("failed to create embedding generator: %w", err)
+		return nil, err
 	}
+	return NewFactCheckServerFromClient(client, provider, middleware)
+}
+
+// NewFactCheckServerFromClient creates a server exposing client's validation as MCP
+// tools, for programs that embed the factcheck API and also serve it over MCP
+func NewFactCheckServerFromClient(client *factcheck.Client, provider any, middleware any) (*FactCheckServer, error) {
+	// Report exact build details so bug reports identify the binary and spec data
+	hooks := &server.Hooks{}
+	buildInfo := version.Get(client.DataDir())
+	hooks.AddAfterInitialize(func(ctx context.Context, id any, req *mcp.InitializeRequest, result *mcp.InitializeResult) {
+		if result.Meta == nil {
+			result.Meta = map[string]any{}
+		}
+		result.Meta["build"] = buildInfo
+	})
+	sampler := sampling.NewClient()
+	hooks.AddAfterInitialize(sampler.OnInitialize)
+	sessions := session.NewStore()
+	hooks.AddOnUnregisterSession(func(ctx context.Context, cs server.ClientSession) {
+		sessions.Remove(cs.SessionID())
+	})
+	// Negotiate protocol versions from the ones this server implements, and adapt
+	// tool responses for clients of older versions
+	versions := protocol.NewSessions()
+	hooks.AddAfterInitialize(versions.OnInitialize)
+	hooks.AddOnUnregisterSession(versions.OnUnregister)
+	hooks.AddAfterListTools(versions.AdaptTools)
+	hooks.AddAfterCallTool(versions.AdaptToolResult)
 
 	// Create the actual MCP server
 	mcpServer := server.NewMCPServer(
 		"mcp-factcheck-server",
-		"0.1.0",
+		version.ServerVersion(),
+		server.WithToolCapabilities(true), // Experimental tools come and go with feature flags
+		// Resources and prompts are fixed at startup and can't be subscribed to
+		server.WithResourceCapabilities(false, false),
+		server.WithPromptCapabilities(false),
+		server.WithLogging(), // Tool calls' log entries are forwarded with notifications/message
+		server.WithHooks(hooks),
 	)
 
 	// Store provider and middleware as-is (can be nil)
 
 	factCheckServer := &FactCheckServer{
-		vectorDB:   vectorDB,
-		generator:  generator,
+		client:     client,
 		mcpServer:  mcpServer,
 		provider:   provider,
 		middleware: middleware,
+		limits:     limits.NewEnforcer(),
+		feedback:   feedback.NewStore(feedback.DefaultPath()),
+		history:    history.NewStore(history.DefaultPath()),
+		sampling:   sampler,
+		sessions:   sessions,
 	}
 
 	// Register tools with the MCP server
 	factCheckServer.registerTools()
+	factCheckServer.registerResources()
+	factCheckServer.mcpServer.AddPrompts(prompts.All()...)
+	factCheckServer.syncExperimentalTools()
+	features.OnChange(factCheckServer.syncExperimentalTools)
 
 	return factCheckServer, nil
 }
 
-// wrapToolHandler wraps a tool handler with telemetry if middleware is available
-func (s *FactCheckServer) wrapToolHandler(toolName string, handler telemetry.ToolHandler) telemetry.ToolHandler {
+// SetTenants enables multi-tenant isolation. stdioTenant, if non-empty, names the
+// tenant used for the stdio transport, which has no way to authenticate.
+func (s *FactCheckServer) SetTenants(registry *tenant.Registry, stdioTenant string) error {
+	s.tenants = registry
+	if stdioTenant != "" {
+		t, ok := registry.Get(stdioTenant)
+		if !ok {
+			return fmt.Errorf("unknown tenant: %s", stdioTenant)
+		}
+		s.stdioTenant = t
+	}
+	return nil
+}
+
+// SetAuthenticator makes the HTTP transport require an API key or OAuth access token,
+// rate limited per caller, when no tenants are configured
+func (s *FactCheckServer) SetAuthenticator(a *auth.Authenticator) {
+	s.auth = a
+}
+
+// SetFeedbackStore changes where report_feedback records feedback
+func (s *FactCheckServer) SetFeedbackStore(store *feedback.Store) {
+	s.feedback = store
+}
+
+// SetRoots exposes validate_file, which reads files inside the given roots on the
+// server side so clients can validate a file by path. When the client declares roots,
+// reads are confined to the parts of files inside them.
+func (s *FactCheckServer) SetRoots(files *roots.Set) {
+	handler := telemetry.ToolHandler(func(ctx context.Context, req any) (any, error) {
+		// Add request ID to context
+		ctx = telemetry.WithRequestID(ctx)
+		
+		// Create structured logger with request ID
+		log := logger.WithRequestID(ctx)
+		log.Info("Starting validate_file request", 
+			zap.String("tool", "validate_file"),
+			zap.Any("request", req))
+		
+		allowed, err := s.clientRoots(ctx, files)
+		if err != nil {
+			log.Error("validate_file request failed", zap.Error(err))
+			return nil, err
+		}
+		if allowed != files {
+			defer allowed.Close()
+		}
+
+		vectorDB, generator := s.backend(ctx)
+		result, err := validator.HandleValidateFile(ctx, vectorDB, generator, allowed, req)
+		if err != nil {
+			log.Error("validate_file request failed", zap.Error(err))
+		} else {
+			log.Info("validate_file request completed successfully")
+		}
+		
+		return result, err
+	})
+	s.addTool(validator.GetValidateFileTool(files), handler)
+}
+
+// clientRootsTimeout bounds how long validate_file waits for the client's roots
+const clientRootsTimeout = 10 * time.Second
+
+// clientRoots returns the parts of files inside the roots the stdio client declares,
+// asking it with roots/list on every call so changes to its roots apply at once.
+// files is returned as is when the client doesn't support roots.
+func (s *FactCheckServer) clientRoots(ctx context.Context, files *roots.Set) (*roots.Set, error) {
+	client := sampling.FromContext(ctx)
+	if !client.RootsAvailable() {
+		return files, nil
+	}
+	listCtx, cancel := context.WithTimeout(ctx, clientRootsTimeout)
+	defer cancel()
+	declared, err := client.ListRoots(listCtx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list the client's roots: %w", err)
+	}
+	uris := make([]string, len(declared))
+	for i, r := range declared {
+		uris[i] = r.URI
+	}
+	allowed, err := files.Within(uris)
+	if err != nil {
+		return nil, err
+	}
+	if len(allowed.Dirs()) == 0 {
+		allowed.Close()
+		failure := toolerr.InvalidArgs("none of the client's roots %v are inside the server's roots %v", uris, files.Dirs())
+		failure.Hint = "Open a workspace inside one of the server's --root directories, or start the server with a --root that contains it."
+		return nil, failure
+	}
+	logger.WithRequestID(ctx).Debug("Confined validate_file to the client's roots", zap.Strings("roots", allowed.Dirs()))
+	return allowed, nil
+}
+
+// SetHistoryStore changes where validation runs are recorded. A nil store disables
+// recording and removes get_validation_history.
+func (s *FactCheckServer) SetHistoryStore(store *history.Store) {
+	s.history = store
+	if store == nil {
+		s.mcpServer.DeleteTools(history.GetValidationHistoryToolName)
+	}
+}
+
+// SetInteractionStore records every tool call, with its arguments and response, in
+// store. A nil store stops recording.
+func (s *FactCheckServer) SetInteractionStore(store *interactions.Store) {
+	s.interactions = store
+}
+
+// SetAuditLog writes an entry for every tool call to log. A nil log stops audit logging.
+func (s *FactCheckServer) SetAuditLog(log *audit.Log) {
+	s.audit = log
+}
+
+// ReplayInteraction calls the interaction's tool again with its recorded arguments,
+// through the same path as a client's tools/call, and returns the new interaction.
+// The tool sees the current thresholds, prompts, embeddings, and feature flags.
+func (s *FactCheckServer) ReplayInteraction(ctx context.Context, in interactions.Interaction) (interactions.Interaction, error) {
+	var replayed *interactions.Interaction
+	ctx = interactions.WithObserver(ctx, func(recorded interactions.Interaction) {
+		replayed = &recorded
+	})
+	message, err := json.Marshal(map[string]any{
+		"jsonrpc": mcp.JSONRPC_VERSION,
+		"id":      "replay-" + in.ID,
+		"method":  string(mcp.MethodToolsCall),
+		"params":  map[string]any{"name": in.Tool, "arguments": in.Arguments},
+	})
+	if err != nil {
+		return interactions.Interaction{}, err
+	}
+	response := s.mcpServer.HandleMessage(ctx, message)
+	if replayed != nil {
+		return *replayed, nil
+	}
+	if rpcErr, ok := response.(mcp.JSONRPCError); ok {
+		return interactions.Interaction{}, fmt.Errorf("replay failed: %s", rpcErr.Error.Message)
+	}
+	return interactions.Interaction{}, fmt.Errorf("replay of %s was not recorded", in.Tool)
+}
+
+// isValidationTool reports whether a tool validates content, so its calls count
+// against validation limits and are recorded in the history and session
+func isValidationTool(toolName string) bool {
+	return strings.HasPrefix(toolName, "validate") || toolName == validator.RevalidateChangedSectionsToolName
+}
+
+// withHistory records the outcome of validation calls in the history store
+func (s *FactCheckServer) withHistory(toolName string, handler telemetry.ToolHandler) telemetry.ToolHandler {
+	if !isValidationTool(toolName) {
+		return handler
+	}
+	return func(ctx context.Context, req any) (any, error) {
+		store := s.history
+		if store == nil {
+			return handler(ctx, req)
+		}
+		var tenantName string
+		if t := tenant.FromContext(ctx); t != nil {
+			tenantName = t.Name
+		}
+		ctx = validator.WithObserver(ctx, func(outcome validator.Outcome) {
+			if _, err := store.Append(history.NewRun(toolName, tenantName, outcome)); err != nil {
+				logger.WithRequestID(ctx).Warn("Failed to record validation history", zap.Error(err))
+			}
+		})
+		return handler(ctx, req)
+	}
+}
+
+// withInteractions records each call's arguments, response or error, and duration in
+// the interaction store
+func (s *FactCheckServer) withInteractions(toolName string, handler telemetry.ToolHandler) telemetry.ToolHandler {
+	return func(ctx context.Context, req any) (any, error) {
+		store := s.interactions
+		if store == nil {
+			return handler(ctx, req)
+		}
+		in := interactions.Interaction{Tool: toolName}
+		in.Arguments, _ = json.Marshal(req)
+		if params, ok := req.(map[string]any); ok {
+			in.SpecVersion, _ = params["specVersion"].(string)
+		}
+		if t := tenant.FromContext(ctx); t != nil {
+			in.Tenant = t.Name
+		}
+
+		start := time.Now()
+		result, err := handler(ctx, req)
+		in.DurationMs = time.Since(start).Milliseconds()
+		if err != nil {
+			in.Error = err.Error()
+		} else {
+			in.Response, _ = json.Marshal(result)
+		}
+		if recorded, appendErr := store.Append(in); appendErr != nil {
+			logger.WithRequestID(ctx).Warn("Failed to record interaction", zap.Error(appendErr))
+		} else {
+			interactions.Observe(ctx, recorded)
+		}
+		return result, err
+	}
+}
+
+// withAudit writes an audit entry for each call: who made it, a hash of its arguments,
+// the verdict of any validations it ran, how long it took, and what it cost. It runs
+// inside withCost so the call's spend is known when it returns.
+func (s *FactCheckServer) withAudit(toolName string, handler telemetry.ToolHandler) telemetry.ToolHandler {
+	return func(ctx context.Context, req any) (any, error) {
+		log := s.audit
+		if log == nil {
+			return handler(ctx, req)
+		}
+		ctx = telemetry.WithRequestID(ctx)
+		entry := audit.Entry{Tool: toolName, RequestID: telemetry.GetRequestID(ctx), Status: audit.StatusOK}
+		args, _ := json.Marshal(req)
+		entry.ArgsHash, entry.ArgsBytes = audit.HashArguments(args), len(args)
+		if params, ok := req.(map[string]any); ok {
+			entry.SpecVersion, _ = params["specVersion"].(string)
+		}
+		if t := tenant.FromContext(ctx); t != nil {
+			entry.Tenant = t.Name
+		}
+		if session := server.ClientSessionFromContext(ctx); session != nil {
+			entry.Session = session.SessionID()
+		}
+
+		var mu sync.Mutex
+		ctx = validator.WithObserver(ctx, func(outcome validator.Outcome) {
+			mu.Lock()
+			defer mu.Unlock()
+			entry.Validations++
+			valid := outcome.Valid && (entry.Valid == nil || *entry.Valid)
+			entry.Valid = &valid
+			if entry.Confidence == nil || outcome.Confidence < *entry.Confidence {
+				confidence := outcome.Confidence
+				entry.Confidence = &confidence
+			}
+			entry.Findings += len(outcome.Findings)
+		})
+
+		entry.Time = time.Now().UTC()
+		result, err := handler(ctx, req)
+		entry.DurationMs = time.Since(entry.Time).Milliseconds()
+		if err != nil {
+			entry.Status, entry.Error = audit.StatusError, err.Error()
+		} else if r, ok := result.(*mcp.CallToolResult); ok && r.IsError {
+			entry.Status = audit.StatusError
+		}
+		if call := cost.FromContext(ctx); call != nil {
+			entry.Usage = call.Usage()
+		}
+		mu.Lock()
+		defer mu.Unlock()
+		if writeErr := log.Write(entry); writeErr != nil {
+			logger.WithRequestID(ctx).Warn("Failed to write audit log", zap.Error(writeErr))
+		}
+		return result, err
+	}
+}
+
+// withSession lets validation calls remember the documents they validate in the
+// caller's MCP session
+func (s *FactCheckServer) withSession(toolName string, handler telemetry.ToolHandler) telemetry.ToolHandler {
+	if !isValidationTool(toolName) {
+		return handler
+	}
+	return func(ctx context.Context, req any) (any, error) {
+		if cs := server.ClientSessionFromContext(ctx); cs != nil && cs.SessionID() != "" {
+			ctx = validator.WithDocumentMemory(ctx, s.sessions.For(cs.SessionID()))
+		}
+		return handler(ctx, req)
+	}
+}
+
+// backend returns the vector database and embedding generator for the request's tenant
+func (s *FactCheckServer) backend(ctx context.Context) (*mcpembedding.VectorDB, *embedding.Generator) {
+	if t := tenant.FromContext(ctx); t != nil {
+		return t.VectorDB, t.Generator
+	}
+	return s.client.VectorDB(), s.client.Generator()
+}
+
+// withTenant applies the request tenant's defaults and records its usage
+func (s *FactCheckServer) withTenant(toolName string, inputSchema *toolargs.Schema, handler telemetry.ToolHandler) telemetry.ToolHandler {
+	// Tools without a spec version would reject the default as an unknown argument
+	takesSpecVersion := inputSchema == nil || inputSchema.Accepts("specVersion")
+	return func(ctx context.Context, req any) (any, error) {
+		t := tenant.FromContext(ctx)
+		if t == nil {
+			return handler(ctx, req)
+		}
+
+		if params, ok := req.(map[string]any); ok && t.DefaultSpecVersion != "" && takesSpecVersion {
+			if _, set := params["specVersion"]; !set {
+				params["specVersion"] = t.DefaultSpecVersion
+			}
+		}
+
+		result, err := handler(ctx, req)
+		t.Usage.Record(toolName, err)
+		return result, err
+	}
+}
+
+// withLimits enforces argument size, concurrency, and session memory limits before calling handler
+func (s *FactCheckServer) withLimits(toolName string, handler telemetry.ToolHandler) telemetry.ToolHandler {
+	isValidation := isValidationTool(toolName)
+	return func(ctx context.Context, req any) (any, error) {
+		var size int64
+		check := func(name, str string) error {
+			size += int64(len(str))
+			return limits.CheckContentLength(name, len(str))
+		}
+		if params, ok := req.(map[string]any); ok {
+			for name, value := range params {
+				switch value := value.(type) {
+				case string:
+					if err := check(name, value); err != nil {
+						return nil, err
+					}
+				case []any:
+					// Arrays of objects, like validate_batch items, carry a document in each
+					for i, item := range value {
+						fields, _ := item.(map[string]any)
+						for field, v := range fields {
+							if str, ok := v.(string); ok {
+								if err := check(fmt.Sprintf("%s[%d].%s", name, i, field), str); err != nil {
+									return nil, err
+								}
+							}
+						}
+					}
+				}
+			}
+		}
+
+		var sessionID string
+		if session := server.ClientSessionFromContext(ctx); session != nil {
+			sessionID = session.SessionID()
+		}
+		release, err := s.limits.Acquire(sessionID, size, isValidation)
+		if err != nil {
+			return nil, err
+		}
+		defer release()
+
+		return handler(ctx, req)
+	}
+}
+
+// withCancellation stops calls whose request was canceled before they start, and reports
+// calls canceled while running as canceled even if the handler finished its work
+func (s *FactCheckServer) withCancellation(handler telemetry.ToolHandler) telemetry.ToolHandler {
+	return func(ctx context.Context, req any) (any, error) {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		result, err := handler(ctx, req)
+		if ctxErr := ctx.Err(); ctxErr != nil && err == nil {
+			return nil, ctxErr
+		}
+		return result, err
+	}
+}
+
+// withClientLog forwards the call's log entries to the client, at the level it set
+// with logging/setLevel
+func (s *FactCheckServer) withClientLog(handler telemetry.ToolHandler) telemetry.ToolHandler {
+	return func(ctx context.Context, req any) (any, error) {
+		return handler(logger.WithCore(ctx, clientlog.NewCore(ctx, s.mcpServer)), req)
+	}
+}
+
+// withCost attributes the OpenAI spend of a call to its tool and session and logs it
+func (s *FactCheckServer) withCost(toolName string, handler telemetry.ToolHandler) telemetry.ToolHandler {
+	return func(ctx context.Context, req any) (any, error) {
+		var sessionID string
+		if session := server.ClientSessionFromContext(ctx); session != nil {
+			sessionID = session.SessionID()
+		}
+		ctx, call := cost.WithCall(ctx, toolName, sessionID)
+
+		result, err := handler(ctx, req)
+		if usage := call.Usage(); usage.Requests > 0 {
+			logger.Get().Debug("Tool call cost",
+				zap.String("tool", toolName),
+				zap.String("session", sessionID),
+				zap.Int64("openai_requests", usage.Requests),
+				zap.Int64("tokens", usage.PromptTokens+usage.CompletionTokens),
+				zap.Float64("usd", usage.USD))
+		}
+		return result, err
+	}
+}
+
+// withArguments checks the arguments of calls against the tool's input schema, rejecting
+// unknown keys and wrong types with an invalid_args error, and passes handler the
+// arguments coerced to the declared types
+func (s *FactCheckServer) withArguments(inputSchema *toolargs.Schema, handler telemetry.ToolHandler) telemetry.ToolHandler {
+	if inputSchema == nil {
+		return handler
+	}
+	return func(ctx context.Context, req any) (any, error) {
+		params, err := inputSchema.Decode(req)
+		if err != nil {
+			return nil, err
+		}
+		return handler(ctx, params)
+	}
+}
+
+// wrapToolHandler wraps a tool handler with cancellation, history, session memory, limits, argument validation, interaction recording,
+// tenant handling, telemetry if middleware is available, audit logging, and cost accounting
+func (s *FactCheckServer) wrapToolHandler(tool mcp.Tool, handler telemetry.ToolHandler) telemetry.ToolHandler {
+	toolName := tool.Name
+	inputSchema, err := toolargs.Compile(tool)
+	if err != nil {
+		logger.Get().Warn("Tool arguments won't be validated", zap.String("tool", toolName), zap.Error(err))
+	}
+	handler = s.withCancellation(handler)
+	handler = s.withClientLog(handler)
+	handler = s.withHistory(toolName, handler)
+	handler = s.withSession(toolName, handler)
+	handler = s.withLimits(toolName, handler)
+	// Inside withTenant, so the tenant's defaults are checked too. withTenant only adds
+	// defaults the tool's schema accepts.
+	handler = s.withArguments(inputSchema, handler)
+	// Inside withTenant, so recorded arguments include the tenant's defaults
+	handler = s.withInteractions(toolName, handler)
+	handler = s.withTenant(toolName, inputSchema, handler)
 	if s.middleware != nil {
 		if mw, ok := s.middleware.(interface {
 			WrapToolHandler(string, telemetry.ToolHandler) telemetry.ToolHandler
 		}); ok {
-			return mw.WrapToolHandler(toolName, handler)
+			handler = mw.WrapToolHandler(toolName, handler)
 		}
 	}
-	return handler
+	handler = s.withAudit(toolName, handler)
+	// Outermost, so telemetry middleware and the audit log can report the call's cost
+	return s.withCost(toolName, handler)
 }
 
 // registerTools registers all fact-check tools with the MCP server
@@ -80,7 +604,8 @@ func (s *FactCheckServer) registerTools() {
 			zap.String("tool", "validate_content"),
 			zap.Any("request", req))
 		
-		result, err := validator.HandleValidateContent(ctx, s.vectorDB, s.generator, req)
+		vectorDB, generator := s.backend(ctx)
+		result, err := validator.HandleValidateContent(ctx, vectorDB, generator, req)
 		if err != nil {
 			log.Error("validate_content request failed", zap.Error(err))
 		} else {
@@ -90,6 +615,27 @@ func (s *FactCheckServer) registerTools() {
 		return result, err
 	})
 
+	validateURLHandler := telemetry.ToolHandler(func(ctx context.Context, req any) (any, error) {
+		// Add request ID to context
+		ctx = telemetry.WithRequestID(ctx)
+		
+		// Create structured logger with request ID
+		log := logger.WithRequestID(ctx)
+		log.Info("Starting validate_url request", 
+			zap.String("tool", "validate_url"),
+			zap.Any("request", req))
+		
+		vectorDB, generator := s.backend(ctx)
+		result, err := validator.HandleValidateURL(ctx, vectorDB, generator, req)
+		if err != nil {
+			log.Error("validate_url request failed", zap.Error(err))
+		} else {
+			log.Info("validate_url request completed successfully")
+		}
+		
+		return result, err
+	})
+
 	validateCodeHandler := telemetry.ToolHandler(func(ctx context.Context, req any) (any, error) {
 		// Add request ID to context
 		ctx = telemetry.WithRequestID(ctx)
@@ -100,7 +646,8 @@ func (s *FactCheckServer) registerTools() {
 			zap.String("tool", "validate_code"),
 			zap.Any("request", req))
 		
-		result, err := validator.HandleValidateCode(ctx, s.vectorDB, s.generator, req)
+		vectorDB, generator := s.backend(ctx)
+		result, err := validator.HandleValidateCode(ctx, vectorDB, generator, req)
 		if err != nil {
 			log.Error("validate_code request failed", zap.Error(err))
 		} else {
@@ -120,7 +667,8 @@ func (s *FactCheckServer) registerTools() {
 			zap.String("tool", "search_spec"),
 			zap.Any("request", req))
 		
-		result, err := spec.HandleSearchSpec(s.vectorDB, s.generator, req)
+		vectorDB, generator := s.backend(ctx)
+		result, err := spec.HandleSearchSpec(ctx, vectorDB, generator, req)
 		if err != nil {
 			log.Error("search_spec request failed", zap.Error(err))
 		} else {
@@ -130,6 +678,48 @@ func (s *FactCheckServer) registerTools() {
 		return result, err
 	})
 
+	getSpecChunkHandler := telemetry.ToolHandler(func(ctx context.Context, req any) (any, error) {
+		// Add request ID to context
+		ctx = telemetry.WithRequestID(ctx)
+		
+		// Create structured logger with request ID
+		log := logger.WithRequestID(ctx)
+		log.Info("Starting get_spec_chunk request", 
+			zap.String("tool", "get_spec_chunk"),
+			zap.Any("request", req))
+		
+		vectorDB, _ := s.backend(ctx)
+		result, err := spec.HandleGetSpecChunk(vectorDB, req)
+		if err != nil {
+			log.Error("get_spec_chunk request failed", zap.Error(err))
+		} else {
+			log.Info("get_spec_chunk request completed successfully")
+		}
+		
+		return result, err
+	})
+
+	getSpecSectionHandler := telemetry.ToolHandler(func(ctx context.Context, req any) (any, error) {
+		// Add request ID to context
+		ctx = telemetry.WithRequestID(ctx)
+		
+		// Create structured logger with request ID
+		log := logger.WithRequestID(ctx)
+		log.Info("Starting get_spec_section request", 
+			zap.String("tool", "get_spec_section"),
+			zap.Any("request", req))
+		
+		vectorDB, _ := s.backend(ctx)
+		result, err := spec.HandleGetSpecSection(vectorDB, req)
+		if err != nil {
+			log.Error("get_spec_section request failed", zap.Error(err))
+		} else {
+			log.Info("get_spec_section request completed successfully")
+		}
+		
+		return result, err
+	})
+
 	listVersionsHandler := telemetry.ToolHandler(func(ctx context.Context, req any) (any, error) {
 		// Add request ID to context
 		ctx = telemetry.WithRequestID(ctx)
@@ -140,7 +730,8 @@ func (s *FactCheckServer) registerTools() {
 			zap.String("tool", "list_spec_versions"),
 			zap.Any("request", req))
 		
-		result, err := spec.HandleListSpecVersions(s.vectorDB, req)
+		vectorDB, _ := s.backend(ctx)
+		result, err := spec.HandleListSpecVersions(vectorDB, req)
 		if err != nil {
 			log.Error("list_spec_versions request failed", zap.Error(err))
 		} else {
@@ -150,76 +741,423 @@ func (s *FactCheckServer) registerTools() {
 		return result, err
 	})
 
-	// Wrap handlers with telemetry middleware
-	validateContentHandler = s.wrapToolHandler("validate_content", validateContentHandler)
-	validateCodeHandler = s.wrapToolHandler("validate_code", validateCodeHandler)
-	searchSpecHandler = s.wrapToolHandler("search_spec", searchSpecHandler)
-	listVersionsHandler = s.wrapToolHandler("list_spec_versions", listVersionsHandler)
+	listRequirementsHandler := telemetry.ToolHandler(func(ctx context.Context, req any) (any, error) {
+		// Add request ID to context
+		ctx = telemetry.WithRequestID(ctx)
+		
+		// Create structured logger with request ID
+		log := logger.WithRequestID(ctx)
+		log.Info("Starting list_requirements request", 
+			zap.String("tool", "list_requirements"),
+			zap.Any("request", req))
+		
+		vectorDB, _ := s.backend(ctx)
+		result, err := spec.HandleListRequirements(vectorDB, req)
+		if err != nil {
+			log.Error("list_requirements request failed", zap.Error(err))
+		} else {
+			log.Info("list_requirements request completed successfully")
+		}
+		
+		return result, err
+	})
 
-	// Convert to MCP-compatible handlers
-	mcpValidateContentHandler := func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-		result, err := validateContentHandler(ctx, req.Params.Arguments)
+	compareVersionsHandler := telemetry.ToolHandler(func(ctx context.Context, req any) (any, error) {
+		// Add request ID to context
+		ctx = telemetry.WithRequestID(ctx)
+		
+		// Create structured logger with request ID
+		log := logger.WithRequestID(ctx)
+		log.Info("Starting compare_spec_versions request", 
+			zap.String("tool", "compare_spec_versions"),
+			zap.Any("request", req))
+		
+		vectorDB, generator := s.backend(ctx)
+		result, err := spec.HandleCompareSpecVersions(ctx, vectorDB, generator, req)
 		if err != nil {
-			return nil, err
+			log.Error("compare_spec_versions request failed", zap.Error(err))
+		} else {
+			log.Info("compare_spec_versions request completed successfully")
 		}
-		if content, ok := result.([]mcp.Content); ok {
-			return &mcp.CallToolResult{Content: content}, nil
+		
+		return result, err
+	})
+
+	validateMessageHandler := telemetry.ToolHandler(func(ctx context.Context, req any) (any, error) {
+		// Add request ID to context
+		ctx = telemetry.WithRequestID(ctx)
+		
+		// Create structured logger with request ID
+		log := logger.WithRequestID(ctx)
+		log.Info("Starting validate_message request", 
+			zap.String("tool", "validate_message"),
+			zap.Any("request", req))
+		
+		result, err := schema.HandleValidateMessage(req)
+		if err != nil {
+			log.Error("validate_message request failed", zap.Error(err))
+		} else {
+			log.Info("validate_message request completed successfully")
 		}
-		return nil, fmt.Errorf("unexpected result type from validate_content")
-	}
+		
+		return result, err
+	})
 
-	mcpValidateCodeHandler := func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-		result, err := validateCodeHandler(ctx, req.Params.Arguments)
+	checkTerminologyHandler := telemetry.ToolHandler(func(ctx context.Context, req any) (any, error) {
+		// Add request ID to context
+		ctx = telemetry.WithRequestID(ctx)
+		
+		// Create structured logger with request ID
+		log := logger.WithRequestID(ctx)
+		log.Info("Starting check_terminology request", 
+			zap.String("tool", "check_terminology"),
+			zap.Any("request", req))
+		
+		result, err := validator.HandleCheckTerminology(req)
 		if err != nil {
-			return nil, err
+			log.Error("check_terminology request failed", zap.Error(err))
+		} else {
+			log.Info("check_terminology request completed successfully")
 		}
-		if content, ok := result.([]mcp.Content); ok {
-			return &mcp.CallToolResult{Content: content}, nil
+		
+		return result, err
+	})
+
+	validationHistoryHandler := telemetry.ToolHandler(func(ctx context.Context, req any) (any, error) {
+		// Add request ID to context
+		ctx = telemetry.WithRequestID(ctx)
+		
+		// Create structured logger with request ID
+		log := logger.WithRequestID(ctx)
+		log.Info("Starting get_validation_history request", 
+			zap.String("tool", "get_validation_history"),
+			zap.Any("request", req))
+		
+		result, err := history.HandleGetValidationHistory(ctx, s.history, req)
+		if err != nil {
+			log.Error("get_validation_history request failed", zap.Error(err))
+		} else {
+			log.Info("get_validation_history request completed successfully")
 		}
-		return nil, fmt.Errorf("unexpected result type from validate_code")
-	}
+		
+		return result, err
+	})
 
-	mcpSearchSpecHandler := func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-		result, err := searchSpecHandler(ctx, req.Params.Arguments)
+	validateDiffHandler := telemetry.ToolHandler(func(ctx context.Context, req any) (any, error) {
+		// Add request ID to context
+		ctx = telemetry.WithRequestID(ctx)
+		
+		// Create structured logger with request ID
+		log := logger.WithRequestID(ctx)
+		log.Info("Starting validate_diff request", 
+			zap.String("tool", "validate_diff"),
+			zap.Any("request", req))
+		
+		vectorDB, generator := s.backend(ctx)
+		result, err := validator.HandleValidateDiff(ctx, vectorDB, generator, req)
 		if err != nil {
-			return nil, err
+			log.Error("validate_diff request failed", zap.Error(err))
+		} else {
+			log.Info("validate_diff request completed successfully")
+		}
+		
+		return result, err
+	})
+
+	validateBatchHandler := telemetry.ToolHandler(func(ctx context.Context, req any) (any, error) {
+		// Add request ID to context
+		ctx = telemetry.WithRequestID(ctx)
+		
+		// Create structured logger with request ID
+		log := logger.WithRequestID(ctx)
+		log.Info("Starting validate_batch request", 
+			zap.String("tool", "validate_batch"))
+		
+		vectorDB, generator := s.backend(ctx)
+		result, err := validator.HandleValidateBatch(ctx, vectorDB, generator, req)
+		if err != nil {
+			log.Error("validate_batch request failed", zap.Error(err))
+		} else {
+			log.Info("validate_batch request completed successfully")
+		}
+		
+		return result, err
+	})
+
+	revalidateHandler := telemetry.ToolHandler(func(ctx context.Context, req any) (any, error) {
+		// Add request ID to context
+		ctx = telemetry.WithRequestID(ctx)
+		
+		// Create structured logger with request ID
+		log := logger.WithRequestID(ctx)
+		log.Info("Starting revalidate_changed_sections request", 
+			zap.String("tool", "revalidate_changed_sections"),
+			zap.Any("request", req))
+		
+		vectorDB, generator := s.backend(ctx)
+		result, err := validator.HandleRevalidateChangedSections(ctx, vectorDB, generator, req)
+		if err != nil {
+			log.Error("revalidate_changed_sections request failed", zap.Error(err))
+		} else {
+			log.Info("revalidate_changed_sections request completed successfully")
+		}
+		
+		return result, err
+	})
+
+	reportFeedbackHandler := telemetry.ToolHandler(func(ctx context.Context, req any) (any, error) {
+		// Add request ID to context
+		ctx = telemetry.WithRequestID(ctx)
+		
+		// Create structured logger with request ID
+		log := logger.WithRequestID(ctx)
+		log.Info("Starting report_feedback request", 
+			zap.String("tool", "report_feedback"),
+			zap.Any("request", req))
+		
+		result, err := feedback.HandleReportFeedback(ctx, s.feedback, req)
+		if err != nil {
+			log.Error("report_feedback request failed", zap.Error(err))
+		} else {
+			log.Info("report_feedback request completed successfully")
+		}
+		
+		return result, err
+	})
+
+	conformanceCheckHandler := telemetry.ToolHandler(func(ctx context.Context, req any) (any, error) {
+		// Add request ID to context
+		ctx = telemetry.WithRequestID(ctx)
+		
+		// Create structured logger with request ID
+		log := logger.WithRequestID(ctx)
+		log.Info("Starting conformance_check request", 
+			zap.String("tool", "conformance_check"),
+			zap.Any("request", req))
+		
+		result, err := conformance.HandleConformanceCheck(ctx, req)
+		if err != nil {
+			log.Error("conformance_check request failed", zap.Error(err))
+		} else {
+			log.Info("conformance_check request completed successfully")
 		}
-		if content, ok := result.([]mcp.Content); ok {
-			return &mcp.CallToolResult{Content: content}, nil
+		
+		return result, err
+	})
+
+	// Register tools with the MCP server, wrapped with telemetry middleware
+	s.addTool(validator.GetValidateContentTool(), validateContentHandler)
+	s.addTool(validator.GetValidateURLTool(), validateURLHandler)
+	s.addTool(validator.GetValidateCodeTool(), validateCodeHandler)
+	s.addTool(validator.GetRevalidateChangedSectionsTool(), revalidateHandler)
+	s.addTool(validator.GetValidateDiffTool(), validateDiffHandler)
+	s.addTool(validator.GetValidateBatchTool(), validateBatchHandler)
+	s.addTool(spec.GetSearchSpecTool(), searchSpecHandler)
+	s.addTool(spec.GetSpecChunkTool(), getSpecChunkHandler)
+	s.addTool(spec.GetSpecSectionTool(), getSpecSectionHandler)
+	s.addTool(spec.GetListSpecVersionsTool(), listVersionsHandler)
+	s.addTool(spec.GetListRequirementsTool(), listRequirementsHandler)
+	s.addTool(spec.GetCompareSpecVersionsTool(), compareVersionsHandler)
+	s.addTool(schema.GetValidateMessageTool(), validateMessageHandler)
+	s.addTool(validator.GetCheckTerminologyTool(), checkTerminologyHandler)
+	s.addTool(feedback.GetReportFeedbackTool(), reportFeedbackHandler)
+	s.addTool(history.GetValidationHistoryTool(), validationHistoryHandler)
+	// Launches commands on the server's host, so operators must opt in
+	s.addExperimentalTool(features.ConformanceCheck, conformance.GetConformanceCheckTool(), conformanceCheckHandler)
+}
+
+// registerResources exposes each embedded spec version as browsable section resources
+func (s *FactCheckServer) registerResources() {
+	resources := spec.NewResources(func(ctx context.Context) *mcpembedding.VectorDB {
+		vectorDB, _ := s.backend(ctx)
+		return vectorDB
+	})
+
+	available, err := s.client.ListVersions()
+	if err != nil {
+		logger.Get().Warn("Failed to list spec versions for resources", zap.Error(err))
+	}
+	for _, v := range specs.ValidSpecVersions {
+		if slices.Contains(available, v) {
+			s.mcpServer.AddResource(resources.IndexResource(v), resources.HandleRead)
 		}
-		return nil, fmt.Errorf("unexpected result type from search_spec")
 	}
+	s.mcpServer.AddResourceTemplate(resources.ChunkTemplate(), resources.HandleRead)
+}
 
-	mcpListVersionsHandler := func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-		result, err := listVersionsHandler(ctx, req.Params.Arguments)
+// addTool registers a tool with the MCP server, wrapped with telemetry middleware
+func (s *FactCheckServer) addTool(tool mcp.Tool, handler telemetry.ToolHandler) {
+	s.mcpServer.AddTool(tool, s.toMCPHandler(tool, handler))
+}
+
+// toMCPHandler wraps a tool handler with telemetry and adapts it to the MCP handler signature.
+// Failures toolFailure classifies, such as bad arguments, limit violations, and an exhausted
+// budget, are returned as structured tool errors so clients can act on them.
+func (s *FactCheckServer) toMCPHandler(tool mcp.Tool, handler telemetry.ToolHandler) server.ToolHandlerFunc {
+	toolName := tool.Name
+	handler = s.wrapToolHandler(tool, handler)
+	return func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		if req.Params.Meta != nil && req.Params.Meta.ProgressToken != nil {
+			ctx = validator.WithProgress(ctx, s.progressNotifier(ctx, req.Params.Meta.ProgressToken))
+		}
+		result, err := handler(ctx, req.Params.Arguments)
+		if failure := toolFailure(err); failure != nil {
+			return toolError(failure), nil
+		}
 		if err != nil {
 			return nil, err
 		}
-		if content, ok := result.([]mcp.Content); ok {
-			return &mcp.CallToolResult{Content: content}, nil
+		switch result := result.(type) {
+		case []mcp.Content:
+			return &mcp.CallToolResult{Content: result}, nil
+		case *mcp.CallToolResult:
+			// Tools with an output schema return structured content too
+			return result, nil
 		}
-		return nil, fmt.Errorf("unexpected result type from list_spec_versions")
+		return nil, fmt.Errorf("unexpected result type from %s", toolName)
 	}
+}
 
-	// Register tools with the MCP server
-	s.mcpServer.AddTool(validator.GetValidateContentTool(), mcpValidateContentHandler)
-	s.mcpServer.AddTool(validator.GetValidateCodeTool(), mcpValidateCodeHandler)
-	s.mcpServer.AddTool(spec.GetSearchSpecTool(), mcpSearchSpecHandler)
-	s.mcpServer.AddTool(spec.GetListSpecVersionsTool(), mcpListVersionsHandler)
+// progressNotifier returns a progress reporter sending notifications/progress with
+// token to the client that made the request, so hosts can show progress bars for
+// long validations rather than time out. Notifications that can't be sent are dropped.
+func (s *FactCheckServer) progressNotifier(ctx context.Context, token mcp.ProgressToken) validator.ProgressFunc {
+	return func(done, total int, message string) {
+		err := s.mcpServer.SendNotificationToClient(ctx, "notifications/progress", map[string]any{
+			"progressToken": token,
+			"progress":      done,
+			"total":         total,
+			"message":       message,
+		})
+		if err != nil {
+			logger.WithRequestID(ctx).Debug("Failed to send progress notification", zap.Error(err))
+		}
+	}
+}
+
+// toolFailure classifies err, returning nil for failures with no class, which are
+// answered as internal errors
+func toolFailure(err error) *toolerr.Error {
+	var (
+		failure    *toolerr.Error
+		limitErr   *limits.Error
+		budgetErr  *cost.Error
+		requestErr *validator.RequestError
+	)
+	switch {
+	case err == nil:
+		return nil
+	case errors.As(err, &failure):
+		// Keep any context the error was wrapped with
+		classified := *failure
+		classified.Message = err.Error()
+		return &classified
+	case errors.As(err, &limitErr):
+		return toolerr.LimitExceeded(limitErr, limitErr.Retryable, limitErr)
+	case errors.As(err, &budgetErr):
+		return toolerr.BudgetExceeded(budgetErr, budgetErr.ResetsAt, budgetErr)
+	case errors.Is(err, embedding.ErrNoAPIKey):
+		return toolerr.EmbeddingProviderUnavailable(err, false)
+	case errors.Is(err, embedding.ErrProviderUnavailable):
+		return toolerr.EmbeddingProviderUnavailable(err, true)
+	case errors.Is(err, validator.ErrNoChatModel):
+		return toolerr.ChatModelUnavailable(err)
+	case errors.As(err, &requestErr):
+		failure = toolerr.InvalidArgs("%s", err.Error())
+		failure.Err = err
+		return failure
+	}
+	return nil
+}
+
+// toolError returns err as a JSON tool error result
+func toolError(err error) *mcp.CallToolResult {
+	payload, _ := json.MarshalIndent(map[string]any{"error": err}, "", "  ")
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{mcp.NewTextContent(string(payload))},
+		IsError: true,
+	}
+}
+
+// addExperimentalTool registers a tool that is only listed while flag is enabled
+func (s *FactCheckServer) addExperimentalTool(flag features.Flag, tool mcp.Tool, handler telemetry.ToolHandler) {
+	s.experimentalMu.Lock()
+	defer s.experimentalMu.Unlock()
+	s.experimentalTools = append(s.experimentalTools, experimentalTool{
+		flag: flag,
+		tool: server.ServerTool{Tool: tool, Handler: s.toMCPHandler(tool, handler)},
+	})
 }
 
-// Run starts the MCP server using stdio transport
+// syncExperimentalTools adds or removes experimental tools to match the enabled feature flags
+func (s *FactCheckServer) syncExperimentalTools() {
+	s.experimentalMu.Lock()
+	defer s.experimentalMu.Unlock()
+
+	for i := range s.experimentalTools {
+		et := &s.experimentalTools[i]
+		enabled := features.Enabled(et.flag)
+		switch {
+		case enabled && !et.registered:
+			s.mcpServer.AddTools(et.tool)
+			logger.Get().Info("Enabled experimental tool",
+				zap.String("tool", et.tool.Tool.Name),
+				zap.String("feature", string(et.flag)))
+		case !enabled && et.registered:
+			s.mcpServer.DeleteTools(et.tool.Tool.Name)
+			logger.Get().Info("Disabled experimental tool",
+				zap.String("tool", et.tool.Tool.Name),
+				zap.String("feature", string(et.flag)))
+		}
+		et.registered = enabled
+	}
+}
+
+// Run starts the MCP server using stdio transport. Tool handlers can send sampling
+// requests to the client through sampling.FromContext.
 func (s *FactCheckServer) Run() error {
-	return server.ServeStdio(s.mcpServer)
+	stdio := server.NewStdioServer(s.mcpServer)
+	stdio.SetContextFunc(func(ctx context.Context) context.Context {
+		ctx = sampling.WithClient(ctx, s.sampling)
+		if s.stdioTenant != nil {
+			return tenant.WithTenant(ctx, s.stdioTenant)
+		}
+		return ctx
+	})
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGTERM, syscall.SIGINT)
+	defer stop()
+	in, out := s.sampling.Attach(os.Stdin, os.Stdout)
+	return stdio.Listen(ctx, in, out)
+}
+
+// TenantUsage returns usage counters for every configured tenant
+func (s *FactCheckServer) TenantUsage() map[string]tenant.UsageSnapshot {
+	usage := map[string]tenant.UsageSnapshot{}
+	if s.tenants == nil {
+		return usage
+	}
+	for _, t := range s.tenants.All() {
+		usage[t.Name] = t.Usage.Snapshot()
+	}
+	return usage
+}
+
+// CallerUsage returns the HTTP requests of each API key and OAuth caller
+func (s *FactCheckServer) CallerUsage() map[string]auth.Usage {
+	if s.auth == nil {
+		return map[string]auth.Usage{}
+	}
+	return s.auth.Usage()
 }
 
 // GetVectorDB returns the vector database instance
 func (s *FactCheckServer) GetVectorDB() *mcpembedding.VectorDB {
-	return s.vectorDB
+	return s.client.VectorDB()
 }
 
 // GetGenerator returns the embedding generator instance
 func (s *FactCheckServer) GetGenerator() *embedding.Generator {
-	return s.generator
+	return s.client.Generator()
 }
 
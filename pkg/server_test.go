@@ -0,0 +1,145 @@
+package pkg
+
+import (
+	"context"
+	"encoding/json"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/carlisia/mcp-factcheck/internal/history"
+	"github.com/carlisia/mcp-factcheck/internal/tenant"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+const testDataDir = "../data/embeddings"
+
+func newTestServer(t *testing.T) *FactCheckServer {
+	t.Helper()
+	t.Setenv("XDG_DATA_HOME", t.TempDir())
+	t.Setenv("EMBEDDING_MODEL", "local-hash-v1")
+	t.Setenv("OPENAI_API_KEY", "")
+	s, err := NewFactCheckServer(testDataDir, nil, nil)
+	if err != nil {
+		t.Fatalf("NewFactCheckServer: %v", err)
+	}
+	s.SetHistoryStore(history.NewStore(filepath.Join(t.TempDir(), "history.jsonl")))
+	return s
+}
+
+// callTool calls a tool through the MCP server as a client would
+func callTool(t *testing.T, ctx context.Context, s *FactCheckServer, name string, args map[string]any) *mcp.CallToolResult {
+	t.Helper()
+	raw, err := json.Marshal(map[string]any{
+		"jsonrpc": "2.0",
+		"id":      1,
+		"method":  "tools/call",
+		"params":  map[string]any{"name": name, "arguments": args},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	reply, err := json.Marshal(s.mcpServer.HandleMessage(ctx, raw))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var resp struct {
+		Result *mcp.CallToolResult `json:"result"`
+		Error  *struct {
+			Message string `json:"message"`
+		} `json:"error"`
+	}
+	if err := json.Unmarshal(reply, &resp); err != nil {
+		t.Fatalf("decode %s reply: %v", name, err)
+	}
+	if resp.Error != nil {
+		t.Fatalf("%s: %s", name, resp.Error.Message)
+	}
+	return resp.Result
+}
+
+func resultText(result *mcp.CallToolResult) string {
+	var text strings.Builder
+	for _, content := range result.Content {
+		if c, ok := content.(mcp.TextContent); ok {
+			text.WriteString(c.Text)
+		}
+	}
+	return text.String()
+}
+
+func TestTenantDefaultSpecVersion(t *testing.T) {
+	s := newTestServer(t)
+	t.Setenv("TEST_TENANT_KEY", "sk-test")
+	acme, err := tenant.New(tenant.Config{
+		Name:               "acme",
+		DataDir:            testDataDir,
+		DefaultSpecVersion: "2025-03-26",
+		APIKeySecret:       "TEST_TENANT_KEY",
+	})
+	if err != nil {
+		t.Fatalf("tenant.New: %v", err)
+	}
+	ctx := tenant.WithTenant(context.Background(), acme)
+
+	tests := []struct {
+		tool string
+		args map[string]any
+		want string // Text the result must contain
+	}{
+		// Tools without a specVersion argument must not receive the default
+		{tool: "list_spec_versions", args: map[string]any{}},
+		{tool: "get_validation_history", args: map[string]any{}},
+		// Tools with one get the tenant's version unless the call gives one
+		{tool: "list_requirements", args: map[string]any{"limit": 1}, want: "2025-03-26"},
+		{tool: "list_requirements", args: map[string]any{"limit": 1, "specVersion": "2025-06-18"}, want: "2025-06-18"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.tool, func(t *testing.T) {
+			result := callTool(t, ctx, s, tt.tool, tt.args)
+			text := resultText(result)
+			if result.IsError {
+				t.Fatalf("%s returned an error: %s", tt.tool, text)
+			}
+			if !strings.Contains(text, tt.want) {
+				t.Errorf("%s result doesn't mention %s: %s", tt.tool, tt.want, text)
+			}
+		})
+	}
+}
+
+func TestLocalModelValidation(t *testing.T) {
+	s := newTestServer(t)
+	ctx := context.Background()
+
+	tests := []struct {
+		name    string
+		content string
+		valid   bool
+	}{
+		{
+			name:    "spec prose",
+			content: "MCP servers expose tools that language models can invoke. Clients discover them with a tools/list request and invoke one with tools/call, passing the tool name and its arguments.",
+			valid:   true,
+		},
+		{
+			name:    "unrelated prose",
+			content: "Preheat the oven, then knead the dough for ten minutes until it is smooth and elastic.",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := callTool(t, ctx, s, "validate_content", map[string]any{"content": tt.content})
+			text := resultText(result)
+			if result.IsError {
+				t.Fatalf("validate_content returned an error: %s", text)
+			}
+			if !strings.Contains(text, `"low_confidence"`) {
+				t.Errorf("result doesn't flag the local model as low confidence: %s", text)
+			}
+			if valid := strings.Contains(text, `"is_valid": true`) || strings.Contains(text, `"is_valid":true`); valid != tt.valid {
+				t.Errorf("is_valid = %v, want %v: %s", valid, tt.valid, text)
+			}
+		})
+	}
+}
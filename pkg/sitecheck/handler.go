@@ -0,0 +1,72 @@
+package sitecheck
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/carlisia/mcp-factcheck/internal/limits"
+	"github.com/carlisia/mcp-factcheck/pkg/logger"
+	"go.uber.org/zap"
+)
+
+// Request is the body of POST /site/check. Policy fields left empty use the server's policy.
+type Request struct {
+	Page
+	BannerOn    string `json:"banner_on,omitempty"`
+	FailOn      string `json:"fail_on,omitempty"`
+	BannerStyle string `json:"banner_style,omitempty"`
+}
+
+// NewHandler serves POST /site/check for docs generator plugins written in other languages
+func NewHandler(checker *Checker) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req Request
+		if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, 4<<20)).Decode(&req); err != nil {
+			writeError(w, http.StatusBadRequest, "invalid JSON body: "+err.Error())
+			return
+		}
+
+		policy := checker.Policy()
+		if req.BannerOn != "" {
+			policy.BannerOn = req.BannerOn
+		}
+		if req.FailOn != "" {
+			policy.FailOn = req.FailOn
+		}
+		if req.BannerStyle != "" {
+			policy.BannerStyle = req.BannerStyle
+		}
+		pageChecker, err := checker.WithPolicy(policy)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		if err := limits.CheckContentLength("content", len(req.Content)); err != nil {
+			writeError(w, http.StatusRequestEntityTooLarge, err.Error())
+			return
+		}
+
+		result, err := pageChecker.Check(r.Context(), req.Page)
+		if err != nil {
+			var limitErr *limits.Error
+			if errors.As(err, &limitErr) {
+				writeError(w, http.StatusRequestEntityTooLarge, limitErr.Message)
+				return
+			}
+			logger.Get().Error("Site page check failed", zap.String("path", req.Path), zap.Error(err))
+			writeError(w, http.StatusBadGateway, err.Error())
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(result)
+	})
+}
+
+func writeError(w http.ResponseWriter, status int, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(map[string]string{"error": message})
+}
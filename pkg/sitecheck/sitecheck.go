@@ -0,0 +1,223 @@
+// Package sitecheck validates documentation pages while a static site is built. Docs generators
+// call it (directly, through `factcheck site`, or over POST /site/check) to add warning banners to
+// pages with findings and to fail the build when findings reach the configured severity.
+package sitecheck
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/carlisia/mcp-factcheck/embedding"
+	mcpembedding "github.com/carlisia/mcp-factcheck/internal/embedding"
+	"github.com/carlisia/mcp-factcheck/pkg/validator"
+)
+
+// SeverityNone disables a policy threshold
+const SeverityNone = "none"
+
+// Banner styles for the admonition syntax of common generators
+const (
+	StyleMarkdown   = "markdown"   // Blockquote; renders anywhere, including Hugo
+	StyleDocusaurus = "docusaurus" // :::warning admonition
+	StyleMkDocs     = "mkdocs"     // !!! warning admonition, used by Backstage TechDocs
+)
+
+// Styles lists the supported banner styles
+var Styles = []string{StyleMarkdown, StyleDocusaurus, StyleMkDocs}
+
+// Policy decides which findings add a banner and which fail the build
+type Policy struct {
+	BannerOn    string `json:"banner_on"`    // Minimum severity that adds a banner, or "none"
+	FailOn      string `json:"fail_on"`      // Minimum severity that fails the build, or "none"
+	BannerStyle string `json:"banner_style"` // One of Styles
+}
+
+// DefaultPolicy adds banners for warnings and fails only on critical findings
+func DefaultPolicy() Policy {
+	return Policy{
+		BannerOn:    validator.SeverityWarning,
+		FailOn:      validator.SeverityCritical,
+		BannerStyle: StyleMarkdown,
+	}
+}
+
+// Validate checks the policy's thresholds and style
+func (p Policy) Validate() error {
+	for _, threshold := range []string{p.BannerOn, p.FailOn} {
+		if err := ValidateThreshold(threshold); err != nil {
+			return err
+		}
+	}
+	for _, style := range Styles {
+		if p.BannerStyle == style {
+			return nil
+		}
+	}
+	return fmt.Errorf("invalid banner style %q (valid: %s)", p.BannerStyle, strings.Join(Styles, ", "))
+}
+
+// Page is a documentation source file
+type Page struct {
+	Path    string `json:"path"`
+	Content string `json:"content"`
+}
+
+// Result is the outcome of checking one page
+type Result struct {
+	Path     string                      `json:"path"`
+	Findings []validator.ValidationError `json:"findings"`
+	Banner   string                      `json:"banner,omitempty"` // Empty when no finding meets BannerOn
+	Content  string                      `json:"content"`          // Page content with the banner injected
+	Fail     bool                        `json:"fail"`             // A finding meets FailOn
+}
+
+// Checker validates pages against one spec version under a policy
+type Checker struct {
+	vectorDB    *mcpembedding.VectorDB
+	generator   *embedding.Generator
+	specVersion string
+	policy      Policy
+}
+
+// NewChecker creates a page checker
+func NewChecker(vectorDB *mcpembedding.VectorDB, generator *embedding.Generator, specVersion string, policy Policy) (*Checker, error) {
+	if err := policy.Validate(); err != nil {
+		return nil, err
+	}
+	return &Checker{
+		vectorDB:    vectorDB,
+		generator:   generator,
+		specVersion: specVersion,
+		policy:      policy,
+	}, nil
+}
+
+// Check validates a page and applies the policy
+func (c *Checker) Check(ctx context.Context, page Page) (*Result, error) {
+	result := &Result{Path: page.Path, Findings: []validator.ValidationError{}, Content: page.Content}
+
+	_, body := splitFrontMatter(page.Content)
+	if strings.TrimSpace(body) == "" {
+		return result, nil
+	}
+
+	aggregated, err := validator.Validate(ctx, c.vectorDB, c.generator, validator.Request{Content: page.Content, SpecVersion: c.specVersion, Chunked: true})
+	if err != nil {
+		return nil, fmt.Errorf("failed to validate %s: %w", page.Path, err)
+	}
+	result.Findings = aggregated.Findings()
+	validator.SortBySeverity(result.Findings)
+
+	var bannerFindings []validator.ValidationError
+	for _, f := range result.Findings {
+		if Meets(f.Severity, c.policy.FailOn) {
+			result.Fail = true
+		}
+		if Meets(f.Severity, c.policy.BannerOn) {
+			bannerFindings = append(bannerFindings, f)
+		}
+	}
+	if len(bannerFindings) > 0 {
+		result.Banner = Banner(bannerFindings, c.specVersion, c.policy.BannerStyle)
+		result.Content = InjectBanner(page.Content, result.Banner)
+	}
+	return result, nil
+}
+
+// ValidateThreshold checks that threshold is a severity or "none"
+func ValidateThreshold(threshold string) error {
+	switch threshold {
+	case SeverityNone, validator.SeverityCritical, validator.SeverityWarning, validator.SeveritySuggestion:
+		return nil
+	}
+	return fmt.Errorf("invalid severity threshold %q (valid: critical, warning, suggestion, none)", threshold)
+}
+
+// Meets reports whether severity is at least as serious as threshold
+func Meets(severity, threshold string) bool {
+	if threshold == SeverityNone {
+		return false
+	}
+	return validator.SeverityRank(severity) <= validator.SeverityRank(threshold)
+}
+
+// Banner renders an admonition summarizing findings in the given style
+func Banner(findings []validator.ValidationError, specVersion, style string) string {
+	title := fmt.Sprintf("This page may not match the MCP specification (%s)", specVersion)
+	var items []string
+	for _, f := range findings {
+		item := f.Message
+		if f.LineNumber > 0 {
+			item = fmt.Sprintf("Line %d: %s", f.LineNumber, item)
+		}
+		switch {
+		case f.SpecSection != "" && f.SpecURL != "":
+			item += fmt.Sprintf(" (see spec: [%s](%s))", f.SpecSection, f.SpecURL)
+		case f.SpecSection != "":
+			item += fmt.Sprintf(" (see spec: %s)", f.SpecSection)
+		}
+		items = append(items, "- "+item)
+	}
+
+	switch style {
+	case StyleDocusaurus:
+		return fmt.Sprintf(":::warning[%s]\n\n%s\n\n:::\n", title, strings.Join(items, "\n"))
+	case StyleMkDocs:
+		indented := make([]string, len(items))
+		for i, item := range items {
+			indented[i] = "    " + item
+		}
+		return fmt.Sprintf("!!! warning \"%s\"\n\n%s\n", title, strings.Join(indented, "\n"))
+	default:
+		quoted := make([]string, len(items))
+		for i, item := range items {
+			quoted[i] = "> " + item
+		}
+		return fmt.Sprintf("> **⚠️ %s**\n>\n%s\n", title, strings.Join(quoted, "\n"))
+	}
+}
+
+// InjectBanner inserts a banner after the page's front matter, or at the top when there is none
+func InjectBanner(content, banner string) string {
+	frontMatter, body := splitFrontMatter(content)
+	return frontMatter + banner + "\n" + strings.TrimLeft(body, "\n")
+}
+
+// splitFrontMatter separates a leading YAML (---) or TOML (+++) front matter block from the body
+func splitFrontMatter(content string) (string, string) {
+	for _, fence := range []string{"---", "+++"} {
+		if !strings.HasPrefix(content, fence+"\n") && !strings.HasPrefix(content, fence+"\r\n") {
+			continue
+		}
+		rest := content[len(fence):]
+		end := strings.Index(rest, "\n"+fence)
+		if end < 0 {
+			return "", content
+		}
+		end += len(fence) + 1 + len(fence)
+		// Include the rest of the closing fence line
+		if nl := strings.IndexByte(content[end:], '\n'); nl >= 0 {
+			end += nl + 1
+		} else {
+			end = len(content)
+		}
+		return content[:end], content[end:]
+	}
+	return "", content
+}
+
+// WithPolicy returns a copy of the checker that applies a different policy
+func (c *Checker) WithPolicy(policy Policy) (*Checker, error) {
+	if err := policy.Validate(); err != nil {
+		return nil, err
+	}
+	clone := *c
+	clone.policy = policy
+	return &clone, nil
+}
+
+// Policy returns the checker's policy
+func (c *Checker) Policy() Policy {
+	return c.policy
+}
@@ -0,0 +1,195 @@
+package spec
+
+import (
+	"encoding/json"
+	"fmt"
+	"maps"
+	"strings"
+
+	"github.com/carlisia/mcp-factcheck/embedding"
+	mcpembedding "github.com/carlisia/mcp-factcheck/internal/embedding"
+	"github.com/carlisia/mcp-factcheck/internal/specs"
+	"github.com/carlisia/mcp-factcheck/internal/toolerr"
+	"github.com/carlisia/mcp-factcheck/pkg/validator"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+const GetSpecChunkToolName = "get_spec_chunk"
+
+// MaxChunksPerRequest caps how many chunks one get_spec_chunk call returns
+const MaxChunksPerRequest = 20
+
+// Detail levels of search_spec results
+const (
+	DetailFull    = "full"     // Each result's full text
+	DetailSummary = "summary"  // The start of each result's text, cut to summaryLength
+	DetailIDsOnly = "ids_only" // Only each result's chunk ID, score, and citation
+)
+
+// DetailLevels lists the valid detail levels
+var DetailLevels = []string{DetailFull, DetailSummary, DetailIDsOnly}
+
+// summaryLength is the longest summary, in bytes, of a search result in summary detail
+const summaryLength = 240
+
+func GetSpecChunkTool() mcp.Tool {
+	schema := map[string]any{
+		"type": "object",
+		"properties": map[string]any{
+			"chunkIds": map[string]any{
+				"type":        "array",
+				"description": "IDs of the chunks to fetch, as listed by search_spec",
+				"items":       map[string]any{"type": "string"},
+				"minItems":    1,
+				"maxItems":    MaxChunksPerRequest,
+			},
+			"specVersion": map[string]any{
+				"type":        "string",
+				"description": "MCP specification version the chunks belong to",
+				"enum":        specs.ValidSpecVersions,
+				"default":     specs.DefaultSpecVersion,
+			},
+			"corpus": map[string]any{
+				"type":        "string",
+				"description": "Documentation corpus the chunks belong to, for search_spec results marked as from a corpus",
+			},
+		},
+		"required": []string{"chunkIds"},
+	}
+	schemaBytes, _ := json.Marshal(schema)
+	return mcp.NewToolWithRawSchema(GetSpecChunkToolName, "Fetch the full text of specification chunks by ID, such as those returned by search_spec with detail summary or ids_only", schemaBytes)
+}
+
+func HandleGetSpecChunk(vectorDB *mcpembedding.VectorDB, args any) ([]mcp.Content, error) {
+	params, ok := args.(map[string]any)
+	if !ok {
+		return nil, toolerr.InvalidArgs("arguments must be a map")
+	}
+	rawIDs, ok := params["chunkIds"].([]any)
+	if !ok || len(rawIDs) == 0 {
+		return nil, &validator.RequestError{Err: toolerr.InvalidArgs("chunkIds must be a non-empty array of strings")}
+	}
+	if len(rawIDs) > MaxChunksPerRequest {
+		return nil, &validator.RequestError{Err: fmt.Errorf("at most %d chunks can be fetched at once, got %d", MaxChunksPerRequest, len(rawIDs))}
+	}
+	ids := make([]string, len(rawIDs))
+	for i, raw := range rawIDs {
+		if ids[i], ok = raw.(string); !ok || ids[i] == "" {
+			return nil, &validator.RequestError{Err: toolerr.InvalidArgs("chunkIds[%d] must be a non-empty string", i)}
+		}
+	}
+
+	specVersion, ok := params["specVersion"].(string)
+	if !ok {
+		specVersion = specs.DefaultSpecVersion
+	}
+	corpus, _ := params["corpus"].(string)
+
+	chunks, err := Chunks(vectorDB, specVersion, corpus, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	var contentParts []mcp.Content
+	for _, chunk := range chunks {
+		result := embedding.SearchResult{Chunk: chunk}
+		heading := "Chunk " + chunk.ID
+		if source := validator.ResultSource(result); source != "" {
+			heading += ", from " + source
+		}
+		if citation := validator.ResultCitation(result); citation != "" {
+			heading += ", " + citation
+		}
+		contentParts = append(contentParts, mcp.NewTextContent(fmt.Sprintf("%s:\n%s\n\n", heading, chunk.Content)))
+	}
+	return contentParts, nil
+}
+
+// Chunks returns the chunks with the given IDs, in order, from specVersion or, if set,
+// from corpus. Unknown IDs are a validator.RequestError naming them.
+func Chunks(vectorDB *mcpembedding.VectorDB, specVersion, corpus string, ids []string) ([]embedding.EmbeddedChunk, error) {
+	var collection *embedding.SpecEmbedding
+	var err error
+	if corpus != "" {
+		if collection, err = vectorDB.Corpus(corpus); err != nil {
+			return nil, &validator.RequestError{Err: err}
+		}
+	} else {
+		if !specs.IsValidSpecVersion(specVersion) {
+			return nil, &validator.RequestError{Err: toolerr.SpecVersionNotFound(specVersion)}
+		}
+		if collection, err = vectorDB.Spec(specVersion); err != nil {
+			return nil, fmt.Errorf("failed to load spec %s: %w", specVersion, err)
+		}
+	}
+
+	byID := make(map[string]embedding.EmbeddedChunk, len(collection.Chunks))
+	for _, c := range collection.Chunks {
+		byID[c.ID] = c
+	}
+	chunks := make([]embedding.EmbeddedChunk, 0, len(ids))
+	var missing []string
+	for _, id := range ids {
+		c, ok := byID[id]
+		if !ok {
+			missing = append(missing, id)
+			continue
+		}
+		if corpus != "" {
+			// Mark the chunk's source as search results do, copying the metadata rather
+			// than writing to the store's cached chunk
+			metadata := maps.Clone(c.Metadata)
+			if metadata == nil {
+				metadata = map[string]any{}
+			}
+			metadata[mcpembedding.CorpusMetadataKey] = corpus
+			c.Metadata = metadata
+		}
+		c.Embedding = nil
+		chunks = append(chunks, c)
+	}
+	if len(missing) > 0 {
+		searched := "MCP " + specVersion
+		if corpus != "" {
+			searched = "corpus " + corpus
+		}
+		return nil, &validator.RequestError{Err: fmt.Errorf("no chunks with IDs %s in %s", strings.Join(missing, ", "), searched)}
+	}
+	return chunks, nil
+}
+
+// chunkSummary shortens a chunk's text to its first prose, skipping front matter and
+// headings, cut at a word boundary. Chunks with nothing else summarize to their title.
+func chunkSummary(content string) string {
+	lines := strings.Split(strings.TrimSpace(content), "\n")
+	start := 0
+	if strings.TrimSpace(lines[0]) == "---" {
+		for i := 1; i < len(lines); i++ {
+			if strings.TrimSpace(lines[i]) == "---" {
+				start = i + 1
+				break
+			}
+		}
+	}
+	var prose []string
+	for _, line := range lines[start:] {
+		line = strings.TrimSpace(line)
+		if line == "" || line == "---" || strings.HasPrefix(line, "#") || strings.HasPrefix(line, "```") {
+			continue
+		}
+		prose = append(prose, line)
+	}
+
+	summary := strings.Join(strings.Fields(strings.Join(prose, " ")), " ")
+	if summary == "" {
+		return sectionTitle(content)
+	}
+	if len(summary) <= summaryLength {
+		return summary
+	}
+	cut := strings.LastIndexByte(summary[:summaryLength], ' ')
+	if cut <= 0 {
+		cut = summaryLength
+	}
+	return summary[:cut] + "..."
+}
@@ -0,0 +1,276 @@
+package spec
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/carlisia/mcp-factcheck/embedding"
+	mcpembedding "github.com/carlisia/mcp-factcheck/internal/embedding"
+	"github.com/carlisia/mcp-factcheck/internal/specs"
+	"github.com/carlisia/mcp-factcheck/internal/toolerr"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+const CompareSpecVersionsToolName = "compare_spec_versions"
+
+// Change kinds reported by compare_spec_versions
+const (
+	ChangeAdded     = "added"
+	ChangeRemoved   = "removed"
+	ChangeReworded  = "reworded"
+	ChangeUnchanged = "unchanged"
+)
+
+// matchThreshold is the chunk-to-chunk similarity above which two chunks from
+// different versions are treated as the same passage
+const matchThreshold = 0.85
+
+// candidateFactor widens the retrieval pool used for matching so a passage that
+// ranks just outside topK in the other version isn't reported as removed or added
+const candidateFactor = 3
+
+// SpecChange describes how one retrieved passage differs between two versions
+type SpecChange struct {
+	Kind         string   `json:"kind"`
+	Similarity   float64  `json:"similarity,omitempty"`
+	FromChunkID  string   `json:"from_chunk_id,omitempty"`
+	ToChunkID    string   `json:"to_chunk_id,omitempty"`
+	FromContent  string   `json:"from_content,omitempty"`
+	ToContent    string   `json:"to_content,omitempty"`
+	RemovedLines []string `json:"removed_lines,omitempty"`
+	AddedLines   []string `json:"added_lines,omitempty"`
+}
+
+// SpecComparison is the structured diff returned by compare_spec_versions
+type SpecComparison struct {
+	Query       string         `json:"query"`
+	FromVersion string         `json:"from_version"`
+	ToVersion   string         `json:"to_version"`
+	Summary     map[string]int `json:"summary"`
+	Changes     []SpecChange   `json:"changes"`
+}
+
+func GetCompareSpecVersionsTool() mcp.Tool {
+	schema := map[string]any{
+		"type": "object",
+		"properties": map[string]any{
+			"query": map[string]any{
+				"type":        "string",
+				"description": "Topic or question to compare across versions, e.g. \"tool annotations\"",
+			},
+			"fromVersion": map[string]any{
+				"type":        "string",
+				"description": "Older MCP specification version",
+				"enum":        specs.ValidSpecVersions,
+			},
+			"toVersion": map[string]any{
+				"type":        "string",
+				"description": "Newer MCP specification version",
+				"enum":        specs.ValidSpecVersions,
+			},
+			"topK": map[string]any{
+				"type":        "integer",
+				"description": "Number of relevant passages to compare from each version",
+				"default":     5,
+				"minimum":     1,
+				"maximum":     20,
+			},
+		},
+		"required": []string{"query", "fromVersion", "toVersion"},
+	}
+	schemaBytes, _ := json.Marshal(schema)
+	return mcp.NewToolWithRawSchema(CompareSpecVersionsToolName, "Compare what the MCP specification says about a topic in two versions, reporting added, removed, and reworded passages", schemaBytes)
+}
+
+func HandleCompareSpecVersions(ctx context.Context, vectorDB *mcpembedding.VectorDB, generator *embedding.Generator, args any) ([]mcp.Content, error) {
+	params, ok := args.(map[string]any)
+	if !ok {
+		return nil, toolerr.InvalidArgs("arguments must be a map")
+	}
+	query, ok := params["query"].(string)
+	if !ok || strings.TrimSpace(query) == "" {
+		return nil, toolerr.InvalidArgs("query must be a non-empty string")
+	}
+	fromVersion, _ := params["fromVersion"].(string)
+	toVersion, _ := params["toVersion"].(string)
+	for _, v := range []string{fromVersion, toVersion} {
+		if !specs.IsValidSpecVersion(v) {
+			return nil, toolerr.SpecVersionNotFound(v)
+		}
+	}
+	if fromVersion == toVersion {
+		return nil, fmt.Errorf("fromVersion and toVersion must differ")
+	}
+
+	topK := 5
+	if k, ok := params["topK"].(float64); ok && k >= 1 {
+		topK = min(int(k), 20)
+	}
+
+	queryEmbedding, err := generator.GenerateEmbedding(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate query embedding: %w", err)
+	}
+
+	fromResults, err := vectorDB.Search(ctx, fromVersion, queryEmbedding, topK*candidateFactor)
+	if err != nil {
+		return nil, fmt.Errorf("failed to search %s: %w", fromVersion, err)
+	}
+	toResults, err := vectorDB.Search(ctx, toVersion, queryEmbedding, topK*candidateFactor)
+	if err != nil {
+		return nil, fmt.Errorf("failed to search %s: %w", toVersion, err)
+	}
+
+	comparison := CompareResults(fromResults, toResults, topK)
+	comparison.Query = query
+	comparison.FromVersion = fromVersion
+	comparison.ToVersion = toVersion
+
+	jsonBytes, _ := json.MarshalIndent(comparison, "", "  ")
+	return []mcp.Content{mcp.NewTextContent(string(jsonBytes))}, nil
+}
+
+// CompareResults pairs the top passages retrieved from each version and
+// classifies them. Both result lists may be longer than topK; the extra results
+// are only used as match candidates.
+func CompareResults(from, to []embedding.SearchResult, topK int) SpecComparison {
+	comparison := SpecComparison{Summary: map[string]int{}, Changes: []SpecChange{}}
+	paired := map[[2]string]bool{}
+
+	addPair := func(f, t embedding.EmbeddedChunk, similarity float64) {
+		key := [2]string{f.ID, t.ID}
+		if paired[key] {
+			return
+		}
+		paired[key] = true
+
+		change := SpecChange{
+			Similarity:  round(similarity),
+			FromChunkID: f.ID,
+			ToChunkID:   t.ID,
+		}
+		if normalize(f.Content) == normalize(t.Content) {
+			change.Kind = ChangeUnchanged
+		} else {
+			change.Kind = ChangeReworded
+			change.FromContent = f.Content
+			change.ToContent = t.Content
+			change.RemovedLines, change.AddedLines = diffLines(f.Content, t.Content)
+		}
+		comparison.Changes = append(comparison.Changes, change)
+	}
+
+	for _, r := range from[:min(topK, len(from))] {
+		if match, similarity, ok := bestMatch(r.Chunk, to); ok {
+			addPair(r.Chunk, match, similarity)
+			continue
+		}
+		comparison.Changes = append(comparison.Changes, SpecChange{
+			Kind:        ChangeRemoved,
+			FromChunkID: r.Chunk.ID,
+			FromContent: r.Chunk.Content,
+		})
+	}
+	for _, r := range to[:min(topK, len(to))] {
+		if match, similarity, ok := bestMatch(r.Chunk, from); ok {
+			addPair(match, r.Chunk, similarity)
+			continue
+		}
+		comparison.Changes = append(comparison.Changes, SpecChange{
+			Kind:      ChangeAdded,
+			ToChunkID: r.Chunk.ID,
+			ToContent: r.Chunk.Content,
+		})
+	}
+
+	for _, c := range comparison.Changes {
+		comparison.Summary[c.Kind]++
+	}
+	return comparison
+}
+
+// bestMatch returns the candidate most similar to chunk, if it clears matchThreshold
+func bestMatch(chunk embedding.EmbeddedChunk, candidates []embedding.SearchResult) (embedding.EmbeddedChunk, float64, bool) {
+	var best embedding.EmbeddedChunk
+	bestSimilarity := -1.0
+	for _, c := range candidates {
+		if s := cosine(chunk.Embedding, c.Chunk.Embedding); s > bestSimilarity {
+			best, bestSimilarity = c.Chunk, s
+		}
+	}
+	return best, bestSimilarity, bestSimilarity >= matchThreshold
+}
+
+// diffLines returns the lines only in a and the lines only in b, using a
+// longest-common-subsequence alignment of their non-blank lines
+func diffLines(a, b string) (removed, added []string) {
+	x, y := splitLines(a), splitLines(b)
+	lcs := make([][]int, len(x)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(y)+1)
+	}
+	for i := len(x) - 1; i >= 0; i-- {
+		for j := len(y) - 1; j >= 0; j-- {
+			if x[i] == y[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	i, j := 0, 0
+	for i < len(x) && j < len(y) {
+		switch {
+		case x[i] == y[j]:
+			i++
+			j++
+		case lcs[i+1][j] >= lcs[i][j+1]:
+			removed = append(removed, x[i])
+			i++
+		default:
+			added = append(added, y[j])
+			j++
+		}
+	}
+	removed = append(removed, x[i:]...)
+	added = append(added, y[j:]...)
+	return removed, added
+}
+
+func splitLines(s string) []string {
+	var lines []string
+	for _, line := range strings.Split(s, "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			lines = append(lines, line)
+		}
+	}
+	return lines
+}
+
+func normalize(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
+
+func cosine(a, b []float64) float64 {
+	if len(a) != len(b) || len(a) == 0 {
+		return 0
+	}
+	var dot, normA, normB float64
+	for i := range a {
+		dot += a[i] * b[i]
+		normA += a[i] * a[i]
+		normB += b[i] * b[i]
+	}
+	if normA == 0 || normB == 0 {
+		return 0
+	}
+	return dot / (math.Sqrt(normA) * math.Sqrt(normB))
+}
+
+func round(f float64) float64 {
+	return math.Round(f*10000) / 10000
+}
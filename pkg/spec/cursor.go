@@ -0,0 +1,50 @@
+package spec
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strconv"
+)
+
+// cursor is the position of a page of search results. It is bound to the search it
+// pages through, so a cursor can't be passed to a search with different arguments.
+type cursor struct {
+	Offset int    `json:"o"`
+	Search string `json:"s"` // searchFingerprint of the search's arguments
+}
+
+// searchFingerprint identifies the arguments that decide a search's ranking
+func searchFingerprint(args SearchSpecArgs) string {
+	h := sha256.New()
+	for _, part := range []string{args.Query, args.SpecVersion, args.Corpus, strconv.FormatBool(args.Rerank), strconv.FormatFloat(args.MinSimilarity, 'g', -1, 64)} {
+		h.Write([]byte(part))
+		h.Write([]byte{0})
+	}
+	return hex.EncodeToString(h.Sum(nil)[:8])
+}
+
+// EncodeCursor returns an opaque cursor for the results of the search args starting at offset
+func EncodeCursor(args SearchSpecArgs, offset int) string {
+	data, _ := json.Marshal(cursor{Offset: offset, Search: searchFingerprint(args)})
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// DecodeCursor returns the offset a cursor from EncodeCursor points to, checking it
+// was made for the search args
+func DecodeCursor(args SearchSpecArgs, s string) (int, error) {
+	data, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return 0, fmt.Errorf("invalid cursor")
+	}
+	var c cursor
+	if err := json.Unmarshal(data, &c); err != nil || c.Offset < 0 {
+		return 0, fmt.Errorf("invalid cursor")
+	}
+	if c.Search != searchFingerprint(args) {
+		return 0, fmt.Errorf("cursor belongs to a different search; pass the same query, specVersion, corpus, rerank, and minSimilarity as the first page")
+	}
+	return c.Offset, nil
+}
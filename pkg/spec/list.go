@@ -3,6 +3,7 @@ package spec
 import (
 	"encoding/json"
 	"fmt"
+	"time"
 
 	mcpembedding "github.com/carlisia/mcp-factcheck/internal/embedding"
 	"github.com/mark3labs/mcp-go/mcp"
@@ -32,8 +33,35 @@ func HandleListSpecVersions(vectorDB *mcpembedding.VectorDB, args any) ([]mcp.Co
 
 	for _, version := range versions {
 		contentParts = append(contentParts, mcp.NewTextContent(
-			fmt.Sprintf("- %s\n", version)))
+			fmt.Sprintf("- %s%s\n", version, commitNote(vectorDB, version))))
+	}
+
+	// Documentation corpora are listed too, since they are selected alongside a version
+	if corpora, err := vectorDB.ListCorpora(); err == nil && len(corpora) > 0 {
+		contentParts = append(contentParts, mcp.NewTextContent(
+			"\nAvailable documentation corpora (pass as corpus to validate_content or search_spec):\n\n"))
+		for _, corpus := range corpora {
+			contentParts = append(contentParts, mcp.NewTextContent(fmt.Sprintf("- %s\n", corpus)))
+		}
 	}
 
 	return contentParts, nil
-}
\ No newline at end of file
+}
+// commitNote describes which repository commit a version's embeddings were built from,
+// so users can tell how stale draft validation is. It is empty for embeddings that
+// predate commit tracking.
+func commitNote(vectorDB *mcpembedding.VectorDB, version string) string {
+	header, err := vectorDB.LoadHeader(version)
+	if err != nil || header.Commit == "" {
+		return ""
+	}
+	sha := header.Commit
+	if len(sha) > 12 {
+		sha = sha[:12]
+	}
+	if header.CommittedAt == nil {
+		return fmt.Sprintf(" (embedded from commit %s)", sha)
+	}
+	age := int(time.Since(*header.CommittedAt).Hours() / 24)
+	return fmt.Sprintf(" (embedded from commit %s of %s, %d days old)", sha, header.CommittedAt.Format("2006-01-02"), age)
+}
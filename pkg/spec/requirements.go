@@ -0,0 +1,120 @@
+package spec
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	mcpembedding "github.com/carlisia/mcp-factcheck/internal/embedding"
+	"github.com/carlisia/mcp-factcheck/internal/requirements"
+	"github.com/carlisia/mcp-factcheck/internal/specs"
+	"github.com/carlisia/mcp-factcheck/internal/toolerr"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+const ListRequirementsToolName = "list_requirements"
+
+// defaultRequirementsLimit is how many requirements list_requirements returns when
+// the request sets no limit
+const defaultRequirementsLimit = 50
+
+func GetListRequirementsTool() mcp.Tool {
+	levels := make([]string, len(requirements.Levels))
+	for i, level := range requirements.Levels {
+		levels[i] = string(level)
+	}
+	schema := map[string]any{
+		"type": "object",
+		"properties": map[string]any{
+			"specVersion": map[string]any{
+				"type":        "string",
+				"description": "MCP specification version to list requirements of",
+				"enum":        specs.ValidSpecVersions,
+				"default":     specs.DefaultSpecVersion,
+			},
+			"level": map[string]any{
+				"type":        "string",
+				"description": "Only list requirements of this level: MUST (also MUST NOT, REQUIRED, SHALL), SHOULD (also SHOULD NOT, RECOMMENDED), or MAY (also OPTIONAL)",
+				"enum":        levels,
+			},
+			"page": map[string]any{
+				"type":        "string",
+				"description": "Only list requirements on this spec page and its subpages, such as \"basic/lifecycle\" or \"server\"",
+			},
+			"query": map[string]any{
+				"type":        "string",
+				"description": "Only list requirements whose statement contains this text, ignoring case",
+			},
+			"limit": map[string]any{
+				"type":        "integer",
+				"description": "Maximum number of requirements to return",
+				"default":     defaultRequirementsLimit,
+				"minimum":     1,
+				"maximum":     500,
+			},
+		},
+	}
+	schemaBytes, _ := json.Marshal(schema)
+	return mcp.NewToolWithRawSchema(ListRequirementsToolName, "List the normative requirements of an MCP specification version: every sentence using an RFC 2119 keyword (MUST, SHOULD, MAY and their variants), with its level, spec page, section, and link. Use this to answer what an implementation is required to do, or to check a checklist against the spec.", schemaBytes)
+}
+
+func HandleListRequirements(vectorDB *mcpembedding.VectorDB, args any) ([]mcp.Content, error) {
+	params, ok := args.(map[string]any)
+	if !ok {
+		return nil, toolerr.InvalidArgs("arguments must be a map")
+	}
+
+	specVersion, ok := params["specVersion"].(string)
+	if !ok {
+		specVersion = specs.DefaultSpecVersion
+	}
+	if !specs.IsValidSpecVersion(specVersion) {
+		return nil, toolerr.SpecVersionNotFound(specVersion)
+	}
+
+	var levels []requirements.Level
+	if name, _ := params["level"].(string); name != "" {
+		level, err := requirements.ParseLevel(name)
+		if err != nil {
+			return nil, err
+		}
+		levels = []requirements.Level{level}
+	}
+	var pages []string
+	if page, _ := params["page"].(string); page != "" {
+		pages = []string{strings.Trim(page, "/")}
+	}
+	query, _ := params["query"].(string)
+	limit := defaultRequirementsLimit
+	if l, ok := params["limit"].(float64); ok {
+		limit = int(l)
+	}
+	if limit < 1 || limit > 500 {
+		return nil, toolerr.InvalidArgs("limit must be between 1 and 500, got %d", limit)
+	}
+
+	catalog, err := vectorDB.Requirements(specVersion)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load requirements of %s: %w", specVersion, err)
+	}
+	var matched []requirements.Requirement
+	for _, r := range catalog.Filter(levels, pages) {
+		if query == "" || strings.Contains(strings.ToLower(r.Statement), strings.ToLower(query)) {
+			matched = append(matched, r)
+		}
+	}
+
+	response := map[string]any{
+		"spec_version": specVersion,
+		"total":        len(matched),
+		"requirements": matched[:min(limit, len(matched))],
+	}
+	if len(matched) > limit {
+		response["truncated"] = true
+	}
+	if len(matched) == 0 {
+		response["requirements"] = []requirements.Requirement{}
+	}
+	payload, _ := json.MarshalIndent(response, "", "  ")
+	return []mcp.Content{mcp.NewTextContent(string(payload))}, nil
+}
@@ -0,0 +1,192 @@
+package spec
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	mcpembedding "github.com/carlisia/mcp-factcheck/internal/embedding"
+	"github.com/carlisia/mcp-factcheck/internal/specs"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// ResourceScheme is the URI scheme for spec section resources
+const ResourceScheme = "mcp-spec"
+
+// ChunkResourceTemplate addresses a single spec section
+const ChunkResourceTemplate = ResourceScheme + "://{version}/{chunkId}"
+
+// IndexURI returns the URI of the section index for a spec version
+func IndexURI(version string) string {
+	return fmt.Sprintf("%s://%s", ResourceScheme, version)
+}
+
+// ChunkURI returns the URI of one spec section
+func ChunkURI(version, chunkID string) string {
+	return fmt.Sprintf("%s://%s/%s", ResourceScheme, version, chunkID)
+}
+
+// ParseResourceURI splits a spec resource URI into its version and, for section
+// URIs, chunk ID
+func ParseResourceURI(uri string) (version, chunkID string, err error) {
+	rest, ok := strings.CutPrefix(uri, ResourceScheme+"://")
+	if !ok {
+		return "", "", fmt.Errorf("not a %s resource: %s", ResourceScheme, uri)
+	}
+	version, chunkID, _ = strings.Cut(strings.TrimSuffix(rest, "/"), "/")
+	if !specs.IsValidSpecVersion(version) {
+		return "", "", fmt.Errorf("invalid spec version: %s", version)
+	}
+	return version, chunkID, nil
+}
+
+// SectionEntry describes one spec section in a version index
+type SectionEntry struct {
+	URI    string `json:"uri"`
+	Title  string `json:"title"`
+	Length int    `json:"length"`
+}
+
+// SectionIndex lists the sections available for a spec version
+type SectionIndex struct {
+	Version  string         `json:"version"`
+	Count    int            `json:"count"`
+	Sections []SectionEntry `json:"sections"`
+}
+
+type section struct {
+	title   string
+	content string
+}
+
+// versionSections holds a spec version's sections without their embeddings
+type versionSections struct {
+	order   []string
+	byID    map[string]section
+	modTime time.Time
+}
+
+// Resources serves spec sections as MCP resources. Section text is cached per
+// vector database so reads don't re-decode the embeddings file, and reloaded
+// when the file is replaced (e.g. by specloader watch).
+type Resources struct {
+	backend func(ctx context.Context) *mcpembedding.VectorDB
+
+	mu    sync.Mutex
+	cache map[*mcpembedding.VectorDB]map[string]*versionSections
+}
+
+// NewResources creates spec resources backed by the vector database that
+// backend selects for each request
+func NewResources(backend func(ctx context.Context) *mcpembedding.VectorDB) *Resources {
+	return &Resources{
+		backend: backend,
+		cache:   map[*mcpembedding.VectorDB]map[string]*versionSections{},
+	}
+}
+
+// IndexResource returns the resource listing the sections of a spec version
+func (r *Resources) IndexResource(version string) mcp.Resource {
+	return mcp.NewResource(IndexURI(version), fmt.Sprintf("MCP specification %s", version),
+		mcp.WithResourceDescription(fmt.Sprintf("Index of the embedded sections of MCP specification %s; each entry links to a readable section", version)),
+		mcp.WithMIMEType("application/json"),
+	)
+}
+
+// ChunkTemplate returns the resource template for individual spec sections
+func (r *Resources) ChunkTemplate() mcp.ResourceTemplate {
+	return mcp.NewResourceTemplate(ChunkResourceTemplate, "MCP specification section",
+		mcp.WithTemplateDescription("Raw text of one embedded MCP specification section; list section URIs by reading "+ResourceScheme+"://{version}"),
+		mcp.WithTemplateMIMEType("text/markdown"),
+	)
+}
+
+// HandleRead serves both index and section reads
+func (r *Resources) HandleRead(ctx context.Context, req mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+	version, chunkID, err := ParseResourceURI(req.Params.URI)
+	if err != nil {
+		return nil, err
+	}
+	sections, err := r.load(ctx, version)
+	if err != nil {
+		return nil, err
+	}
+
+	if chunkID == "" {
+		index := SectionIndex{Version: version, Count: len(sections.order), Sections: make([]SectionEntry, 0, len(sections.order))}
+		for _, id := range sections.order {
+			s := sections.byID[id]
+			index.Sections = append(index.Sections, SectionEntry{URI: ChunkURI(version, id), Title: s.title, Length: len(s.content)})
+		}
+		body, _ := json.MarshalIndent(index, "", "  ")
+		return []mcp.ResourceContents{mcp.TextResourceContents{URI: req.Params.URI, MIMEType: "application/json", Text: string(body)}}, nil
+	}
+
+	s, ok := sections.byID[chunkID]
+	if !ok {
+		return nil, fmt.Errorf("spec section not found: %s", req.Params.URI)
+	}
+	return []mcp.ResourceContents{mcp.TextResourceContents{URI: req.Params.URI, MIMEType: "text/markdown", Text: s.content}}, nil
+}
+
+func (r *Resources) load(ctx context.Context, version string) (*versionSections, error) {
+	vectorDB := r.backend(ctx)
+
+	modTime, err := vectorDB.ModTime(version)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load spec %s: %w", version, err)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if sections, ok := r.cache[vectorDB][version]; ok && sections.modTime.Equal(modTime) {
+		return sections, nil
+	}
+
+	specEmbedding, err := vectorDB.Load(version)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load spec %s: %w", version, err)
+	}
+	sections := &versionSections{byID: make(map[string]section, len(specEmbedding.Chunks)), modTime: modTime}
+	for _, c := range specEmbedding.Chunks {
+		sections.order = append(sections.order, c.ID)
+		title := c.Section
+		if title == "" {
+			title = sectionTitle(c.Content)
+		}
+		sections.byID[c.ID] = section{title: title, content: c.Content}
+	}
+
+	if r.cache[vectorDB] == nil {
+		r.cache[vectorDB] = map[string]*versionSections{}
+	}
+	r.cache[vectorDB][version] = sections
+	return sections, nil
+}
+
+// sectionTitle picks the first heading or, failing that, the first line of a section
+func sectionTitle(content string) string {
+	var first string
+	for _, line := range strings.Split(content, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || line == "---" {
+			continue
+		}
+		if heading, ok := strings.CutPrefix(line, "title:"); ok {
+			return strings.TrimSpace(heading)
+		}
+		if strings.HasPrefix(line, "#") {
+			return strings.TrimSpace(strings.TrimLeft(line, "#"))
+		}
+		if first == "" {
+			first = line
+		}
+	}
+	if len(first) > 80 {
+		first = first[:80] + "..."
+	}
+	return first
+}
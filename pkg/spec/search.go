@@ -1,12 +1,18 @@
 package spec
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
+	"slices"
+	"strings"
 
+	"github.com/carlisia/mcp-factcheck/internal/features"
 	"github.com/carlisia/mcp-factcheck/internal/specs"
 	"github.com/carlisia/mcp-factcheck/embedding"
 	mcpembedding "github.com/carlisia/mcp-factcheck/internal/embedding"
+	"github.com/carlisia/mcp-factcheck/internal/toolerr"
+	"github.com/carlisia/mcp-factcheck/pkg/validator"
 	"github.com/mark3labs/mcp-go/mcp"
 )
 
@@ -16,6 +22,49 @@ type SearchSpecArgs struct {
 	Query       string `json:"query"`
 	SpecVersion string `json:"spec_version,omitempty"`
 	TopK        int    `json:"top_k,omitempty"`
+	Rerank      bool   `json:"rerank,omitempty"` // Rerank candidates with a chat model (requires the rerank feature)
+	Corpus      string `json:"corpus,omitempty"` // Documentation corpus searched alongside the spec
+	Offset      int    `json:"offset,omitempty"` // Results to skip, for paging through results
+	// Leave out results whose cosine similarity to the query is below this, 0-1
+	MinSimilarity float64 `json:"min_similarity,omitempty"`
+}
+
+// MaxSearchResults caps how deep into the ranking a search can page. A page reaching
+// past it is cut short.
+const MaxSearchResults = 100
+
+// SearchResults are the results of Search, in rank order
+type SearchResults struct {
+	Results   []embedding.SearchResult
+	Reranked  bool  // Results are in the reranker's order
+	RerankErr error // Why reranking failed, leaving the results in search order
+	HasMore   bool  // More results follow these, within MaxSearchResults
+}
+
+// SearchOutput is the structured content of search_spec results, and its declared
+// output schema
+type SearchOutput struct {
+	Query       string               `json:"query"`
+	SpecVersion string               `json:"spec_version"`
+	Corpus      string               `json:"corpus,omitempty"`
+	Detail      string               `json:"detail"`
+	Reranked    bool                 `json:"reranked"`
+	RerankError string               `json:"rerank_error,omitempty"` // Why reranking failed, leaving the results in search order
+	Results     []SearchOutputResult `json:"results"`
+	NextCursor  string               `json:"next_cursor,omitempty"` // Pass as cursor for the next page; empty on the last page
+}
+
+// SearchOutputResult is one search_spec result
+type SearchOutputResult struct {
+	Rank       int     `json:"rank"`
+	ChunkID    string  `json:"chunk_id"`
+	Similarity float64 `json:"similarity"`
+	Score      float64 `json:"score"`            // Ranking score: similarity blended with keyword relevance, or the reranker's relevance
+	Corpus     string  `json:"corpus,omitempty"` // Corpus the result came from; empty for the MCP spec
+	Section    string  `json:"section,omitempty"`
+	URL        string  `json:"url,omitempty"`
+	Content    string  `json:"content,omitempty"` // Set with detail full
+	Summary    string  `json:"summary,omitempty"` // Set with detail summary
 }
 
 func GetSearchSpecTool() mcp.Tool {
@@ -39,21 +88,48 @@ func GetSearchSpecTool() mcp.Tool {
 				"minimum":     1,
 				"maximum":     20,
 			},
+			"corpus": map[string]any{
+				"type":        "string",
+				"description": "Name of a documentation corpus ingested on the server to search alongside the MCP specification",
+			},
+			"minSimilarity": map[string]any{
+				"type":        "number",
+				"description": "Leave out results whose similarity to the query is below this",
+				"minimum":     0,
+				"maximum":     1,
+			},
+			"detail": map[string]any{
+				"type":        "string",
+				"description": "How much of each result to return: full text, a short summary, or only chunk IDs and scores. Fetch the full text of summarized results with " + GetSpecChunkToolName + ".",
+				"enum":        DetailLevels,
+				"default":     DetailFull,
+			},
+			"cursor": map[string]any{
+				"type":        "string",
+				"description": fmt.Sprintf("nextCursor of the previous page, to get the next topK results. Pass the same query and options as the first page. At most %d results can be paged through.", MaxSearchResults),
+			},
+			"rerank": map[string]any{
+				"type":        "boolean",
+				"description": fmt.Sprintf("Retrieve %d candidates and have a chat model pick the most relevant topK. Slower, but more precise for specific questions. Requires the %s feature.", validator.RerankCandidates, features.Rerank),
+				"default":     false,
+			},
 		},
 		"required": []string{"query"},
 	}
 	schemaBytes, _ := json.Marshal(schema)
-	return mcp.NewToolWithRawSchema(SearchSpecToolName, "Search MCP specification using semantic similarity", schemaBytes)
+	tool := mcp.NewToolWithRawSchema(SearchSpecToolName, "Search MCP specification using semantic similarity combined with keyword matching, so exact terms like method names rank well", schemaBytes)
+	mcp.WithOutputSchema[SearchOutput]()(&tool)
+	return tool
 }
 
-func HandleSearchSpec(vectorDB *mcpembedding.VectorDB, generator *embedding.Generator, args any) ([]mcp.Content, error) {
+func HandleSearchSpec(ctx context.Context, vectorDB *mcpembedding.VectorDB, generator *embedding.Generator, args any) (*mcp.CallToolResult, error) {
 	params, ok := args.(map[string]any)
 	if !ok {
-		return nil, fmt.Errorf("arguments must be a map")
+		return nil, toolerr.InvalidArgs("arguments must be a map")
 	}
 	query, ok := params["query"].(string)
 	if !ok {
-		return nil, fmt.Errorf("query must be a string")
+		return nil, toolerr.InvalidArgs("query must be a string")
 	}
 
 	specVersion, ok := params["specVersion"].(string)
@@ -66,32 +142,196 @@ func HandleSearchSpec(vectorDB *mcpembedding.VectorDB, generator *embedding.Gene
 		topK = int(k)
 	}
 
-	if !specs.IsValidSpecVersion(specVersion) {
-		return nil, fmt.Errorf("invalid spec version: %s", specVersion)
+	corpus, _ := params["corpus"].(string)
+	rerank, _ := params["rerank"].(bool)
+	minSimilarity, _ := params["minSimilarity"].(float64)
+	detail := DetailFull
+	if d, ok := params["detail"].(string); ok && d != "" {
+		if !slices.Contains(DetailLevels, d) {
+			return nil, &validator.RequestError{Err: fmt.Errorf("invalid detail %q (valid: %s)", d, strings.Join(DetailLevels, ", "))}
+		}
+		detail = d
 	}
 
-	// Generate embedding for query
-	queryEmbedding, err := generator.GenerateEmbedding(query)
-	if err != nil {
-		return nil, fmt.Errorf("failed to generate query embedding: %w", err)
+	searchArgs := SearchSpecArgs{
+		Query:         query,
+		SpecVersion:   specVersion,
+		TopK:          topK,
+		Rerank:        rerank,
+		Corpus:        corpus,
+		MinSimilarity: minSimilarity,
+	}
+	if c, ok := params["cursor"].(string); ok && c != "" {
+		offset, err := DecodeCursor(searchArgs, c)
+		if err != nil {
+			return nil, &validator.RequestError{Err: err}
+		}
+		searchArgs.Offset = offset
 	}
 
-	// Search specifications
-	results, err := vectorDB.Search(specVersion, queryEmbedding, topK)
+	found, err := Search(ctx, vectorDB, generator, searchArgs)
 	if err != nil {
-		return nil, fmt.Errorf("failed to search specifications: %w", err)
+		return nil, err
+	}
+	results := found.Results
+
+	searched := "MCP " + specVersion
+	if corpus != "" {
+		searched += " and " + corpus
+	}
+	if minSimilarity > 0 {
+		searched += fmt.Sprintf(" with similarity of at least %.2f", minSimilarity)
+	}
+	if searchArgs.Offset > 0 {
+		searched += fmt.Sprintf(", from result %d", searchArgs.Offset+1)
+	}
+	heading := fmt.Sprintf("Search results for '%s' in %s:\n\n", query, searched)
+	if found.RerankErr != nil {
+		heading = fmt.Sprintf("Search results for '%s' in %s (reranking failed: %v):\n\n", query, searched, found.RerankErr)
+	} else if found.Reranked {
+		heading = fmt.Sprintf("Reranked search results for '%s' in %s:\n\n", query, searched)
+	}
+	if len(results) == 0 {
+		heading = fmt.Sprintf("No search results for '%s' in %s.\n", query, searched)
+	}
+
+	output := SearchOutput{
+		Query:       query,
+		SpecVersion: specVersion,
+		Corpus:      corpus,
+		Detail:      detail,
+		Reranked:    found.Reranked,
+		Results:     make([]SearchOutputResult, 0, len(results)),
+	}
+	if found.RerankErr != nil {
+		output.RerankError = found.RerankErr.Error()
 	}
 
 	// Build response content
 	var contentParts []mcp.Content
-	contentParts = append(contentParts, mcp.NewTextContent(
-		fmt.Sprintf("Search results for '%s' in MCP %s:\n\n", query, specVersion)))
+	contentParts = append(contentParts, mcp.NewTextContent(heading))
 
+	var ids []string
 	for _, match := range results {
+		result := SearchOutputResult{
+			Rank:       match.Rank,
+			ChunkID:    match.Chunk.ID,
+			Similarity: match.Similarity,
+			Score:      match.Score,
+			Corpus:     validator.ResultSource(match),
+			Section:    match.Chunk.Section,
+			URL:        match.Chunk.URL,
+		}
+		switch detail {
+		case DetailFull:
+			result.Content = match.Chunk.Content
+		case DetailSummary:
+			result.Summary = chunkSummary(match.Chunk.Content)
+		}
+		output.Results = append(output.Results, result)
+
+		source := ", chunk " + match.Chunk.ID
+		if corpus := validator.ResultSource(match); corpus != "" {
+			source += fmt.Sprintf(", from %s", corpus)
+		}
+		if citation := validator.ResultCitation(match); citation != "" {
+			source += ", " + citation
+		}
+		scores := fmt.Sprintf("similarity: %.4f%s", match.Similarity, source)
+		if found.Reranked && match.Score >= 0 {
+			scores = fmt.Sprintf("relevance: %.1f/10, %s", match.Score*10, scores)
+		}
+		switch detail {
+		case DetailIDsOnly:
+			// One line per result, sent together below
+			ids = append(ids, fmt.Sprintf("Rank %d (%s)", match.Rank, scores))
+		case DetailSummary:
+			contentParts = append(contentParts, mcp.NewTextContent(
+				fmt.Sprintf("Rank %d (%s):\n%s\n\n", match.Rank, scores, result.Summary)))
+		default:
+			contentParts = append(contentParts, mcp.NewTextContent(
+				fmt.Sprintf("Rank %d (%s):\n%s\n\n", match.Rank, scores, match.Chunk.Content)))
+		}
+	}
+	if len(ids) > 0 {
+		contentParts = append(contentParts, mcp.NewTextContent(strings.Join(ids, "\n")+"\n\n"))
+	}
+	if detail != DetailFull && len(results) > 0 {
 		contentParts = append(contentParts, mcp.NewTextContent(
-			fmt.Sprintf("Rank %d (similarity: %.4f):\n%s\n\n", 
-				match.Rank, match.Similarity, match.Chunk.Content)))
+			fmt.Sprintf("Fetch full text with %s, passing chunk IDs as chunkIds (with the same specVersion, or corpus for results from a corpus).\n\n", GetSpecChunkToolName)))
 	}
+	if found.HasMore {
+		output.NextCursor = EncodeCursor(searchArgs, searchArgs.Offset+len(results))
+		contentParts = append(contentParts, mcp.NewTextContent(
+			fmt.Sprintf("nextCursor: %s\n(pass as cursor, with the same query and options, for the next %d results)", output.NextCursor, topK)))
+	}
+
+	return validator.StructuredResult(contentParts, output), nil
+}
 
-	return contentParts, nil
-}
\ No newline at end of file
+// Search finds the spec sections, and sections of args.Corpus if set, most relevant
+// to args.Query: the args.TopK results ranked after the first args.Offset, leaving out
+// results less similar than args.MinSimilarity. Every page of a search ranks the same
+// MaxSearchResults candidates, so pages don't overlap or skip results. A failed
+// rerank doesn't fail the search; the results keep search order and RerankErr says
+// why. Errors caused by args are validator.RequestErrors.
+func Search(ctx context.Context, vectorDB *mcpembedding.VectorDB, generator *embedding.Generator, args SearchSpecArgs) (*SearchResults, error) {
+	if args.SpecVersion == "" {
+		args.SpecVersion = specs.DefaultSpecVersion
+	}
+	if !specs.IsValidSpecVersion(args.SpecVersion) {
+		return nil, &validator.RequestError{Err: toolerr.SpecVersionNotFound(args.SpecVersion)}
+	}
+	if args.TopK < 1 || args.Offset < 0 || args.Offset >= MaxSearchResults {
+		return nil, &validator.RequestError{Err: fmt.Errorf("results %d to %d are out of range; searches return at most %d results", args.Offset+1, args.Offset+args.TopK, MaxSearchResults)}
+	}
+	if args.MinSimilarity < 0 || args.MinSimilarity > 1 {
+		return nil, &validator.RequestError{Err: toolerr.InvalidArgs("minimum similarity must be between 0 and 1, got %v", args.MinSimilarity)}
+	}
+	if args.Corpus != "" {
+		if err := vectorDB.CheckCorpus(args.Corpus); err != nil {
+			return nil, &validator.RequestError{Err: err}
+		}
+	}
+	if args.Rerank && !features.Enabled(features.Rerank) {
+		return nil, &validator.RequestError{Err: fmt.Errorf("rerank requires the %s feature; enable it with %s=%s", features.Rerank, features.EnvVar, features.Rerank)}
+	}
+
+	// Generate embedding for query
+	queryEmbedding, err := generator.GenerateEmbedding(ctx, args.Query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate query embedding: %w", err)
+	}
+
+	// Search specifications. Ranking a fixed number of candidates, whatever the page,
+	// keeps approximate search from ranking deeper pages differently.
+	results, err := validator.SearchSpecAndCorpus(ctx, vectorDB, args.SpecVersion, args.Corpus, args.Query, queryEmbedding, MaxSearchResults, validator.CurrentSettings().KeywordWeight)
+	if err != nil {
+		return nil, fmt.Errorf("failed to search specifications: %w", err)
+	}
+	if args.MinSimilarity > 0 {
+		results = slices.DeleteFunc(results, func(r embedding.SearchResult) bool {
+			return r.Similarity < args.MinSimilarity
+		})
+		for i := range results {
+			results[i].Rank = i + 1
+		}
+	}
+
+	depth := args.Offset + args.TopK
+	found := &SearchResults{HasMore: len(results) > depth}
+	if args.Rerank && len(results) > args.Offset {
+		model, _ := validator.ChatModelFor(ctx, generator, false)
+		candidates := results[:min(max(depth, validator.RerankCandidates), len(results))]
+		reranked, err := validator.RerankResults(ctx, model, args.Query, candidates, depth)
+		if err != nil {
+			// Still answer, in search order, rather than fail the whole search
+			found.RerankErr = err
+		} else {
+			found.Reranked = true
+			results = reranked
+		}
+	}
+	found.Results = results[min(args.Offset, len(results)):min(depth, len(results))]
+	return found, nil
+}
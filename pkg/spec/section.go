@@ -0,0 +1,374 @@
+package spec
+
+import (
+	"encoding/json"
+	"fmt"
+	"path"
+	"slices"
+	"strings"
+	"unicode"
+
+	"github.com/carlisia/mcp-factcheck/embedding"
+	mcpembedding "github.com/carlisia/mcp-factcheck/internal/embedding"
+	"github.com/carlisia/mcp-factcheck/internal/specs"
+	"github.com/carlisia/mcp-factcheck/internal/toolerr"
+	"github.com/carlisia/mcp-factcheck/pkg/validator"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+const GetSpecSectionToolName = "get_spec_section"
+
+// maxSectionBytes caps the text get_spec_section returns, so asking for a whole page
+// can't flood a client's context
+const maxSectionBytes = 60000
+
+// maxOtherMatches caps how many other sections matching the same query are listed
+const maxOtherMatches = 5
+
+func GetSpecSectionTool() mcp.Tool {
+	schema := map[string]any{
+		"type": "object",
+		"properties": map[string]any{
+			"section": map[string]any{
+				"type":        "string",
+				"description": `Section to retrieve: a page path with an optional heading anchor ("server/tools#list-changed-notification", "basic/lifecycle"), a heading path ("Tools > List Changed Notification"), or a heading ("List Changed Notification")`,
+			},
+			"chunkId": map[string]any{
+				"type":        "string",
+				"description": "Chunk ID from search_spec; retrieves the whole section the chunk belongs to",
+			},
+			"specVersion": map[string]any{
+				"type":        "string",
+				"description": "MCP specification version to read",
+				"enum":        specs.ValidSpecVersions,
+				"default":     specs.DefaultSpecVersion,
+			},
+		},
+	}
+	schemaBytes, _ := json.Marshal(schema)
+	return mcp.NewToolWithRawSchema(GetSpecSectionToolName, "Retrieve an entire MCP specification section verbatim, with its subsections, by page path, heading, or chunk ID, to quote authoritative text after a search", schemaBytes)
+}
+
+func HandleGetSpecSection(vectorDB *mcpembedding.VectorDB, args any) ([]mcp.Content, error) {
+	params, ok := args.(map[string]any)
+	if !ok {
+		return nil, toolerr.InvalidArgs("arguments must be a map")
+	}
+	query, _ := params["section"].(string)
+	chunkID, _ := params["chunkId"].(string)
+	query, chunkID = strings.TrimSpace(query), strings.TrimSpace(chunkID)
+	if (query == "") == (chunkID == "") {
+		return nil, &validator.RequestError{Err: fmt.Errorf("pass exactly one of section or chunkId")}
+	}
+	specVersion, ok := params["specVersion"].(string)
+	if !ok {
+		specVersion = specs.DefaultSpecVersion
+	}
+
+	section, err := Section(vectorDB, specVersion, query, chunkID)
+	if err != nil {
+		return nil, err
+	}
+
+	heading := fmt.Sprintf("MCP %s, %s", specVersion, strings.Join(section.Headings, " > "))
+	if section.Path != "" {
+		heading += fmt.Sprintf(" (%s)", section.Path)
+	}
+	if section.URL != "" {
+		heading += "\n" + section.URL
+	}
+	contentParts := []mcp.Content{mcp.NewTextContent(heading + "\n\n" + section.Text)}
+	var notes []string
+	if section.Truncated {
+		notes = append(notes, fmt.Sprintf("The section was cut at %d bytes; ask for one of its subsections for the rest.", maxSectionBytes))
+	}
+	if len(section.Others) > 0 {
+		notes = append(notes, "Other sections matching the query: "+strings.Join(section.Others, "; "))
+	}
+	if len(notes) > 0 {
+		contentParts = append(contentParts, mcp.NewTextContent(strings.Join(notes, "\n")))
+	}
+	return contentParts, nil
+}
+
+// SpecSection is a spec section with its subsections, as its chunks' text in order
+type SpecSection struct {
+	Headings  []string // Heading path, page title first
+	Path      string   // Page path and heading anchor, such as server/tools#list-changed-notification, when chunks record their file
+	URL       string   // Published URL, when chunks record it
+	Text      string
+	ChunkIDs  []string
+	Truncated bool     // Text was cut at maxSectionBytes
+	Others    []string // Heading paths of other sections the query matched
+}
+
+// outlineChunk is a spec chunk placed in its page's heading hierarchy
+type outlineChunk struct {
+	chunk    embedding.EmbeddedChunk
+	page     int      // Index of the chunk's page in the spec
+	file     string   // Page file without extension, or "" when the chunk doesn't record it
+	headings []string // Heading path, page title first
+	marker   bool     // Front matter or heading of a chunk without metadata, rather than text
+}
+
+// outline places a spec's chunks in their pages' heading hierarchies. Chunks with
+// metadata carry their file and headings; for chunks extracted without it, pages are
+// told apart by their front matter and headings by heading chunks, which such
+// extractions keep in order.
+func outline(chunks []embedding.EmbeddedChunk) []outlineChunk {
+	out := make([]outlineChunk, 0, len(chunks))
+	page, prevFile := -1, ""
+	var title string
+	var levels [7]string
+	for _, c := range chunks {
+		if c.Section != "" || c.FilePath != "" {
+			file := strings.TrimSuffix(strings.TrimSuffix(c.FilePath, ".mdx"), ".md")
+			if page < 0 || file != prevFile {
+				page++
+			}
+			prevFile = file
+			out = append(out, outlineChunk{chunk: c, page: page, file: file, headings: strings.Split(c.Section, " > ")})
+			continue
+		}
+
+		content := strings.TrimSpace(c.Content)
+		if strings.HasPrefix(content, "---") {
+			page++
+			title = sectionTitle(content)
+			clear(levels[:])
+			out = append(out, outlineChunk{chunk: c, page: page, headings: []string{title}, marker: true})
+			continue
+		}
+		page = max(page, 0)
+		if level, text := headingLine(content); level > 0 {
+			levels[level] = text
+			clear(levels[level+1:])
+			out = append(out, outlineChunk{chunk: c, page: page, headings: pathOf(title, levels[:]), marker: true})
+			continue
+		}
+		out = append(out, outlineChunk{chunk: c, page: page, headings: pathOf(title, levels[:])})
+	}
+	return out
+}
+
+// headingLine returns the level and text of a chunk that is a single markdown heading,
+// or level 0
+func headingLine(content string) (int, string) {
+	if strings.Contains(content, "\n") {
+		return 0, ""
+	}
+	level := len(content) - len(strings.TrimLeft(content, "#"))
+	if level == 0 || level > 6 || len(content) == level || content[level] != ' ' {
+		return 0, ""
+	}
+	return level, strings.ReplaceAll(strings.TrimSpace(strings.TrimRight(content[level:], "#")), "`", "")
+}
+
+// pathOf returns the open headings under the page title, as the spec loader does
+func pathOf(title string, levels []string) []string {
+	var headings []string
+	if title != "" {
+		headings = append(headings, title)
+	}
+	for level, heading := range levels {
+		if heading != "" && !(level == 1 && heading == title) {
+			headings = append(headings, heading)
+		}
+	}
+	return headings
+}
+
+// anchor returns the fragment the docs site generates for a heading
+func anchor(heading string) string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(strings.TrimSpace(heading)) {
+		switch {
+		case unicode.IsLetter(r) || unicode.IsDigit(r) || r == '-' || r == '_':
+			b.WriteRune(r)
+		case r == ' ':
+			b.WriteByte('-')
+		}
+	}
+	return b.String()
+}
+
+// matchesPage reports whether a chunk is on the page at file, a path like
+// server/tools. Chunks that don't record their file match by page title.
+func (c outlineChunk) matchesPage(file string) bool {
+	file = strings.Trim(strings.TrimSuffix(strings.TrimSuffix(file, ".mdx"), ".md"), "/")
+	if c.file != "" {
+		return c.file == file || strings.HasSuffix(c.file, "/"+file) ||
+			(path.Base(c.file) == "index" && (path.Dir(c.file) == file || strings.HasSuffix(path.Dir(c.file), "/"+file)))
+	}
+	return len(c.headings) > 0 && anchor(c.headings[0]) == anchor(path.Base(file))
+}
+
+// matchDepth returns how many of the chunk's headings make up the section query names,
+// or 0 if the chunk isn't in such a section
+func (c outlineChunk) matchDepth(query string) int {
+	if strings.Contains(query, " > ") {
+		// The path's headings must appear in order, ending with the section's own, so
+		// levels in between can be left out
+		want := strings.Split(query, " > ")
+		for depth := len(c.headings); depth >= 1; depth-- {
+			if !strings.EqualFold(strings.TrimSpace(want[len(want)-1]), c.headings[depth-1]) {
+				continue
+			}
+			next := 0
+			for _, heading := range c.headings[:depth-1] {
+				if next < len(want)-1 && strings.EqualFold(strings.TrimSpace(want[next]), heading) {
+					next++
+				}
+			}
+			if next == len(want)-1 {
+				return depth
+			}
+		}
+		return 0
+	}
+
+	file, fragment, _ := strings.Cut(query, "#")
+	if file != "" {
+		if !c.matchesPage(file) {
+			return 0
+		}
+		if fragment == "" {
+			return 1
+		}
+	}
+	for depth := len(c.headings); depth >= 1; depth-- {
+		if anchor(c.headings[depth-1]) == anchor(fragment) {
+			return depth
+		}
+	}
+	return 0
+}
+
+// Section returns the spec section named by query, or the section chunk chunkID belongs
+// to, with its subsections. When several sections match query, the first is returned
+// and the others are listed in Others. Errors caused by the arguments are
+// validator.RequestErrors.
+func Section(vectorDB *mcpembedding.VectorDB, specVersion, query, chunkID string) (*SpecSection, error) {
+	if !specs.IsValidSpecVersion(specVersion) {
+		return nil, &validator.RequestError{Err: toolerr.SpecVersionNotFound(specVersion)}
+	}
+	spec, err := vectorDB.Spec(specVersion)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load spec %s: %w", specVersion, err)
+	}
+	chunks := outline(spec.Chunks)
+
+	// A bare name is a page when one matches, else a heading
+	queries := []string{query}
+	if !strings.ContainsAny(query, "#/") && !strings.Contains(query, " > ") {
+		queries = []string{query + "#", "#" + query}
+	}
+	start, depth := -1, 0
+	var others []string
+	for _, query := range queries {
+		for i, c := range chunks {
+			d := 0
+			if chunkID != "" {
+				if c.chunk.ID == chunkID {
+					d = len(c.headings)
+				}
+			} else {
+				d = c.matchDepth(query)
+			}
+			if d == 0 {
+				continue
+			}
+			if start < 0 {
+				start, depth = i, d
+				continue
+			}
+			// Chunks of the section already found aren't other matches
+			if c.page == chunks[start].page && inSection(c, chunks[start], depth) {
+				continue
+			}
+			other := strings.Join(c.headings[:d], " > ")
+			if len(others) < maxOtherMatches && !slices.Contains(others, other) {
+				others = append(others, other)
+			}
+		}
+		if start >= 0 {
+			break
+		}
+	}
+	if start < 0 {
+		if chunkID != "" {
+			return nil, &validator.RequestError{Err: fmt.Errorf("no chunk with ID %s in MCP %s", chunkID, specVersion)}
+		}
+		return nil, &validator.RequestError{Err: fmt.Errorf("no section matching %q in MCP %s", query, specVersion)}
+	}
+
+	// The section starts at its heading: back up over the chunks before the match that
+	// are in it, such as the heading chunk itself
+	first := chunks[start]
+	for start > 0 && chunks[start-1].page == first.page && inSection(chunks[start-1], first, depth) {
+		start--
+	}
+
+	section := &SpecSection{Headings: first.headings[:depth], Others: others}
+	if first.file != "" {
+		section.Path = first.file
+		if depth > 1 {
+			section.Path += "#" + anchor(first.headings[depth-1])
+		}
+	}
+	var text strings.Builder
+	var emitted []string
+	for _, c := range chunks[start:] {
+		if c.page != first.page || !inSection(c, first, depth) {
+			break
+		}
+		if section.URL == "" && c.chunk.URL != "" {
+			page, _, _ := strings.Cut(c.chunk.URL, "#")
+			section.URL = page
+			if depth > 1 {
+				section.URL += "#" + anchor(first.headings[depth-1])
+			}
+		}
+		section.ChunkIDs = append(section.ChunkIDs, c.chunk.ID)
+		part := c.chunk.Content
+		switch {
+		case c.marker && len(c.headings) == 1:
+			// Front matter; the title is in the section's headings
+			continue
+		case c.file != "":
+			// Chunks with metadata don't hold their headings, so write the ones they open
+			var headings strings.Builder
+			for level := depth - 1; level < len(c.headings); level++ {
+				if level < len(emitted) && emitted[level] == c.headings[level] {
+					continue
+				}
+				fmt.Fprintf(&headings, "%s %s\n\n", strings.Repeat("#", min(level+1, 6)), c.headings[level])
+			}
+			emitted = c.headings
+			part = headings.String() + part
+		}
+		if text.Len()+len(part) > maxSectionBytes {
+			section.Truncated = true
+			break
+		}
+		if text.Len() > 0 {
+			text.WriteString("\n\n")
+		}
+		text.WriteString(part)
+	}
+	section.Text = text.String()
+	return section, nil
+}
+
+// inSection reports whether c falls under the first depth headings of first
+func inSection(c, first outlineChunk, depth int) bool {
+	if len(c.headings) < depth {
+		return false
+	}
+	for i := range depth {
+		if c.headings[i] != first.headings[i] {
+			return false
+		}
+	}
+	return true
+}
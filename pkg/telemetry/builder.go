@@ -3,10 +3,13 @@ package telemetry
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 
+	"github.com/carlisia/mcp-factcheck/pkg/tokens"
 	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
 	"go.opentelemetry.io/otel/trace"
 )
 
@@ -145,7 +148,7 @@ func min(a, b int) int {
 
 // StartValidationSpan creates a validation request span
 func StartValidationSpan(ctx context.Context, content, specVersion string, useChunking bool) (context.Context, trace.Span) {
-	estimatedTokens := len(content) / 4
+	estimatedTokens := tokens.Count(content)
 	
 	// Add request ID to span attributes if available
 	builder := NewSpanBuilder().
@@ -168,7 +171,7 @@ func StartValidationSpan(ctx context.Context, content, specVersion string, useCh
 
 // StartEmbeddingSpan creates an embedding generation span
 func StartEmbeddingSpan(ctx context.Context, text string) (context.Context, trace.Span) {
-	estimatedTokens := len(text) / 4
+	estimatedTokens := tokens.Count(text)
 	
 	builder := NewSpanBuilder().
 		WithKind("EMBEDDING").
@@ -219,4 +222,20 @@ func StartAnalysisSpan(ctx context.Context, numMatches int, avgSimilarity float6
 	}
 	
 	return builder.Start(ctx, "validation.analysis")
-}
\ No newline at end of file
+}
+
+// RecordError records err on span. Errors from a canceled or timed-out request
+// also set request.status, so aborted calls can be told apart from failures.
+func RecordError(span trace.Span, err error) {
+	span.RecordError(err)
+	switch {
+	case errors.Is(err, context.Canceled):
+		span.SetAttributes(attribute.String("request.status", "canceled"))
+		span.SetStatus(codes.Error, "canceled")
+	case errors.Is(err, context.DeadlineExceeded):
+		span.SetAttributes(attribute.String("request.status", "deadline_exceeded"))
+		span.SetStatus(codes.Error, "deadline exceeded")
+	default:
+		span.SetStatus(codes.Error, err.Error())
+	}
+}
@@ -9,12 +9,22 @@ type contextKey string
 
 const requestIDKey contextKey = "request_id"
 
-// WithRequestID adds a request ID to the context
+// WithRequestID adds a request ID to the context, keeping one already there so a
+// tool call's wrappers and handler log the same ID
 func WithRequestID(ctx context.Context) context.Context {
+	if GetRequestID(ctx) != "" {
+		return ctx
+	}
 	requestID := uuid.New().String()
 	return context.WithValue(ctx, requestIDKey, requestID)
 }
 
+// WithGivenRequestID adds a request ID chosen by the caller, such as one a client sent,
+// to the context
+func WithGivenRequestID(ctx context.Context, requestID string) context.Context {
+	return context.WithValue(ctx, requestIDKey, requestID)
+}
+
 // GetRequestID retrieves the request ID from context
 func GetRequestID(ctx context.Context) string {
 	if id, ok := ctx.Value(requestIDKey).(string); ok {
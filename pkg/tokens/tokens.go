@@ -0,0 +1,185 @@
+// Package tokens counts tokens the way OpenAI's embedding models do, using the
+// cl100k_base encoding. The encoding is downloaded on first use and cached; when
+// it can't be loaded, counts fall back to an estimate of four bytes per token.
+package tokens
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha1"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"github.com/pkoukk/tiktoken-go"
+)
+
+// Encoding is the tokenizer used by text-embedding-3-small and the GPT-4 family
+const Encoding = "cl100k_base"
+
+// bytesPerToken is the estimate used when the encoding is unavailable
+const bytesPerToken = 4
+
+var (
+	once    sync.Once
+	encoder *tiktoken.Tiktoken
+	loadErr error
+)
+
+// Load loads the encoding if it hasn't been loaded yet. Count calls it, so
+// calling it directly is only needed to load eagerly or to report the error.
+func Load() error {
+	once.Do(func() {
+		tiktoken.SetBpeLoader(bpeLoader{client: &http.Client{Timeout: 30 * time.Second}})
+		encoder, loadErr = tiktoken.GetEncoding(Encoding)
+		if loadErr != nil {
+			loadErr = fmt.Errorf("failed to load %s encoding: %w", Encoding, loadErr)
+		}
+	})
+	return loadErr
+}
+
+// Exact reports whether counts come from the tokenizer rather than the estimate
+func Exact() bool {
+	return Load() == nil
+}
+
+// Count returns the number of tokens in text
+func Count(text string) int {
+	if Load() != nil {
+		return len(text) / bytesPerToken
+	}
+	return len(encoder.EncodeOrdinary(text))
+}
+
+// Split cuts text into windows of at most size tokens, each starting overlap tokens
+// before the previous one ended. Without the encoding, windows are sized by the
+// estimate and cut at character boundaries.
+func Split(text string, size, overlap int) []string {
+	if text == "" || size < 1 {
+		return nil
+	}
+	overlap = min(max(overlap, 0), size-1)
+
+	if Load() != nil {
+		return splitBytes(text, size*bytesPerToken, overlap*bytesPerToken)
+	}
+	ids := encoder.EncodeOrdinary(text)
+	var windows []string
+	for start := 0; start < len(ids); start += size - overlap {
+		end := min(start+size, len(ids))
+		windows = append(windows, encoder.Decode(ids[start:end]))
+		if end == len(ids) {
+			break
+		}
+	}
+	return windows
+}
+
+// splitBytes is Split by byte counts, moving cuts back to the start of a character
+func splitBytes(text string, size, overlap int) []string {
+	var windows []string
+	for start := 0; start < len(text); {
+		end := min(start+size, len(text))
+		for end < len(text) && end > start+1 && !utf8.RuneStart(text[end]) {
+			end--
+		}
+		windows = append(windows, text[start:end])
+		if end == len(text) {
+			break
+		}
+		next := max(end-overlap, start+1)
+		for next < end && !utf8.RuneStart(text[next]) {
+			next++
+		}
+		start = next
+	}
+	return windows
+}
+
+// bpeLoader loads encodings from a local cache, downloading them with a timeout
+// on a miss. Cache files are named like tiktoken's own, so a directory populated
+// for other tiktoken tools can be shared through TIKTOKEN_CACHE_DIR.
+type bpeLoader struct {
+	client *http.Client
+}
+
+func (l bpeLoader) LoadTiktokenBpe(url string) (map[string]int, error) {
+	data, err := l.read(url)
+	if err != nil {
+		return nil, err
+	}
+
+	ranks := map[string]int{}
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		token, rank, ok := strings.Cut(scanner.Text(), " ")
+		if !ok {
+			continue
+		}
+		decoded, err := base64.StdEncoding.DecodeString(token)
+		if err != nil {
+			return nil, fmt.Errorf("invalid token %q in %s: %w", token, url, err)
+		}
+		if ranks[string(decoded)], err = strconv.Atoi(rank); err != nil {
+			return nil, fmt.Errorf("invalid rank %q in %s: %w", rank, url, err)
+		}
+	}
+	return ranks, scanner.Err()
+}
+
+// read returns the cached copy of url, downloading it first if needed
+func (l bpeLoader) read(url string) ([]byte, error) {
+	dir := os.Getenv("TIKTOKEN_CACHE_DIR")
+	if dir == "" {
+		cacheDir, err := os.UserCacheDir()
+		if err != nil {
+			cacheDir = os.TempDir()
+		}
+		dir = filepath.Join(cacheDir, "mcp-factcheck", "tiktoken")
+	}
+	sum := sha1.Sum([]byte(url))
+	path := filepath.Join(dir, hex.EncodeToString(sum[:]))
+	if data, err := os.ReadFile(path); err == nil {
+		return data, nil
+	}
+
+	resp, err := l.client.Get(url)
+	if err != nil {
+		return nil, fmt.Errorf("failed to download %s: %w", url, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to download %s: %s", url, resp.Status)
+	}
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to download %s: %w", url, err)
+	}
+
+	// Caching is best effort; the download is still usable if it fails
+	if err := os.MkdirAll(dir, 0755); err == nil {
+		if tmp, err := os.CreateTemp(dir, "*.tmp"); err == nil {
+			_, err = tmp.Write(data)
+			if closeErr := tmp.Close(); err == nil {
+				err = closeErr
+			}
+			if err == nil {
+				err = os.Rename(tmp.Name(), path)
+			}
+			if err != nil {
+				os.Remove(tmp.Name())
+			}
+		}
+	}
+	return data, nil
+}
@@ -0,0 +1,536 @@
+package validator
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/carlisia/mcp-factcheck/internal/specs"
+	"github.com/carlisia/mcp-factcheck/pkg/schema"
+)
+
+// staticAnalysis is the result of statically analyzing source code
+type staticAnalysis struct {
+	Patterns []string          // Human-readable descriptions of the MCP usage found
+	Findings []ValidationError // Spec violations, anchored to source lines
+}
+
+// sourceLiteral is a string literal found in the source
+type sourceLiteral struct {
+	value  string
+	line   int
+	offset int // of the opening quote, for analyzers that scan source text
+	end    int // just past the closing quote
+}
+
+// analyzer checks code in one language against specVersion. It returns an error
+// if the code can't be analyzed, e.g. because it doesn't parse.
+type analyzer func(code, specVersion string) (*staticAnalysis, error)
+
+// analyzers maps normalized language names to their static analyzer
+var analyzers = map[string]analyzer{
+	"go":         analyzeGoCode,
+	"typescript": analyzeTypeScriptCode,
+	"python":     analyzePythonCode,
+}
+
+// normalizeLanguage maps the aliases accepted by validate_code to analyzer names
+func normalizeLanguage(language string) string {
+	switch language = strings.ToLower(strings.TrimSpace(language)); language {
+	case "golang":
+		return "go"
+	case "ts", "tsx", "js", "javascript", "node", "nodejs":
+		return "typescript"
+	case "py", "python3":
+		return "python"
+	}
+	return language
+}
+
+// analyzeStatically runs the analyzer for language, if there is one. It returns
+// nil without an error for languages that have no analyzer.
+func analyzeStatically(code, language, specVersion string) (*staticAnalysis, error) {
+	analyze, ok := analyzers[normalizeLanguage(language)]
+	if !ok {
+		return nil, nil
+	}
+	analysis, err := analyze(code, specVersion)
+	if err != nil {
+		return nil, err
+	}
+	slices.SortStableFunc(analysis.Findings, func(a, b ValidationError) int {
+		return a.LineNumber - b.LineNumber
+	})
+	for i := range analysis.Findings {
+		if f := &analysis.Findings[i]; f.SpecURL == "" {
+			f.SpecURL = sectionURL(specVersion, f.SpecSection)
+		}
+	}
+	return analysis, nil
+}
+
+// methodLiteral matches string literals shaped like namespaced MCP method names
+var methodLiteral = regexp.MustCompile(`^(notifications|tools|resources|prompts|logging|completion|sampling|roots|elicitation)/[A-Za-z_/]+$`)
+
+// serverFeature pairs a server capability with the list method clients call once
+// it is declared and the mcp-go option and registration calls that provide it
+type serverFeature struct {
+	capability   string
+	listMethod   string
+	useMethod    string
+	option       string
+	registration []string
+}
+
+var serverFeatures = []serverFeature{
+	{"tools", "tools/list", "tools/call", "WithToolCapabilities", []string{"AddTool", "AddTools"}},
+	{"resources", "resources/list", "resources/read", "WithResourceCapabilities", []string{"AddResource", "AddResources", "AddResourceTemplate"}},
+	{"prompts", "prompts/list", "prompts/get", "WithPromptCapabilities", []string{"AddPrompt", "AddPrompts"}},
+}
+
+func (a *staticAnalysis) add(finding *ValidationError) {
+	a.Findings = append(a.Findings, *finding)
+}
+
+// describe renders the analysis as lines for the embedding input
+func (a *staticAnalysis) describe() string {
+	if len(a.Patterns) == 0 {
+		return ""
+	}
+	lines := []string{"Static analysis:"}
+	for _, p := range a.Patterns {
+		lines = append(lines, "- "+p)
+	}
+	return strings.Join(lines, "\n")
+}
+
+// checkMethodNames flags method-name literals the spec version doesn't define
+func (a *staticAnalysis) checkMethodNames(literals []sourceLiteral, specVersion string) {
+	seen := map[string]bool{}
+	for _, lit := range literals {
+		if !methodLiteral.MatchString(lit.value) || seen[lit.value] {
+			continue
+		}
+		seen[lit.value] = true
+
+		info, known := schema.LookupMethod(lit.value)
+		switch {
+		case !known:
+			finding := NewValidationError(IssueTypeInaccuracy, SeverityCritical, fmt.Sprintf("%q is not an MCP method in %s", lit.value, specVersion)).
+				WithFound(lit.value).
+				WithSpecSection("Base Protocol: Messages").
+				WithLineNumber(lit.line)
+			if closest := schema.ClosestMethod(lit.value, specVersion); closest != "" {
+				finding.WithExpected(closest).AddSuggestion(fmt.Sprintf("Use %q", closest))
+			} else {
+				finding.AddSuggestion("Use a method defined by the specification; peers answer unknown methods with -32601 (Method not found)")
+			}
+			a.add(finding)
+		case !info.DefinedIn(specVersion):
+			a.add(NewValidationError(IssueTypeUnsupported, SeverityCritical, fmt.Sprintf("%q was introduced in %s and is not available in %s", lit.value, info.Since, specVersion)).
+				WithFound(lit.value).
+				WithSpecSection("Base Protocol: Messages").
+				WithLineNumber(lit.line).
+				AddSuggestion(fmt.Sprintf("Target spec version %s or later, or only use it after negotiating that protocol version", info.Since)))
+		}
+	}
+}
+
+// checkDispatch checks that a hand-written method dispatch covers the methods the
+// spec requires. handled maps each dispatched string to the line it appears on.
+func (a *staticAnalysis) checkDispatch(handled map[string]int) {
+	methods := map[string]int{}
+	for value, line := range handled {
+		if value == "initialize" || value == "ping" || methodLiteral.MatchString(value) {
+			methods[value] = line
+		}
+	}
+	if len(methods) < 2 {
+		return
+	}
+
+	line := 0
+	for _, l := range methods {
+		if line == 0 || l < line {
+			line = l
+		}
+	}
+
+	_, handlesInitialize := methods["initialize"]
+	_, handlesInitialized := methods["notifications/initialized"]
+	switch {
+	case handlesInitialize:
+	case handlesInitialized:
+		// A client answering server requests and notifications doesn't handle initialize
+	default:
+		if slices.ContainsFunc(serverFeatures, func(f serverFeature) bool {
+			_, ok := methods[f.listMethod]
+			return ok
+		}) {
+			a.add(NewValidationError(IssueTypeMissing, SeverityCritical, "Method dispatch does not handle initialize").
+				WithExpected("initialize").
+				WithSpecSection("Lifecycle: Initialization").
+				WithLineNumber(line).
+				AddSuggestion("Handle initialize by returning protocolVersion, capabilities, and serverInfo before serving other requests"))
+		}
+	}
+	if _, ok := methods["ping"]; !ok && handlesInitialize {
+		a.add(NewValidationError(IssueTypeMissing, SeverityWarning, "Method dispatch does not handle ping").
+			WithExpected("ping").
+			WithSpecSection("Utilities: Ping").
+			WithLineNumber(line).
+			AddSuggestion("The receiver of a ping MUST respond promptly with an empty result"))
+	}
+	a.checkListMethods(methods)
+}
+
+// checkListMethods flags servers that handle a feature's use method, such as
+// tools/call, without the list method clients discover it through
+func (a *staticAnalysis) checkListMethods(handled map[string]int) {
+	for _, f := range serverFeatures {
+		_, lists := handled[f.listMethod]
+		useLine, uses := handled[f.useMethod]
+		if uses && !lists {
+			a.add(NewValidationError(IssueTypeMissing, SeverityWarning, fmt.Sprintf("Method dispatch handles %s but not %s", f.useMethod, f.listMethod)).
+				WithExpected(f.listMethod).
+				WithSpecSection("Server Features: " + strings.ToUpper(f.capability[:1]) + f.capability[1:]).
+				WithLineNumber(useLine).
+				AddSuggestion(fmt.Sprintf("Clients discover %s through %s before calling %s", f.capability, f.listMethod, f.useMethod)))
+		}
+	}
+}
+
+// checkProtocolVersion flags a protocol version string that isn't a published
+// spec version, or that differs from the version being validated against
+func (a *staticAnalysis) checkProtocolVersion(version string, line int, specVersion string) {
+	switch {
+	case version == "draft" || !specs.IsValidSpecVersion(version):
+		a.add(NewValidationError(IssueTypeInaccuracy, SeverityCritical, fmt.Sprintf("%q is not a published MCP protocol version", version)).
+			WithFound(version).
+			WithExpected(specVersion).
+			WithSpecSection("Lifecycle: Version Negotiation").
+			WithLineNumber(line).
+			AddSuggestion(fmt.Sprintf("Use a dated protocol version such as %q", specs.DefaultSpecVersion)))
+	case version != specVersion && specVersion != "draft":
+		a.add(NewValidationError(IssueTypeImprecise, SeveritySuggestion, fmt.Sprintf("Code negotiates protocol version %s but is being validated against %s", version, specVersion)).
+			WithFound(version).
+			WithExpected(specVersion).
+			WithSpecSection("Lifecycle: Version Negotiation").
+			WithLineNumber(line).
+			AddSuggestion("Validate against the version the code negotiates, or update the version it sends"))
+	}
+}
+
+// checkSendOrder flags requests in sent, a function's outgoing method literals in
+// source order, that come before its initialize request
+func (a *staticAnalysis) checkSendOrder(sent []sourceLiteral) {
+	initIndex := slices.IndexFunc(sent, func(l sourceLiteral) bool { return l.value == "initialize" })
+	if initIndex < 0 {
+		return
+	}
+	for _, lit := range sent[:initIndex] {
+		info, known := schema.LookupMethod(lit.value)
+		if !known || lit.value == "ping" {
+			continue
+		}
+		if info.Kind == schema.KindRequest || lit.value == "notifications/initialized" {
+			a.add(requestBeforeInitialize(fmt.Sprintf("%q", lit.value), lit.line))
+		}
+	}
+}
+
+// checkClientOrder flags requests made on an SDK client before the call that
+// initializes it, within the same top-level block of src
+func (a *staticAnalysis) checkClientOrder(src *sourceText, clients []string, initMethod string, requests []string) {
+	if len(clients) == 0 {
+		return
+	}
+	names := make([]string, len(clients))
+	for i, c := range clients {
+		names[i] = regexp.QuoteMeta(c)
+	}
+	calls := regexp.MustCompile(`\b(`+strings.Join(names, "|")+`)\s*\.\s*(\w+)\s*\(`).FindAllStringSubmatchIndex(src.masked, -1)
+
+	type scope struct {
+		client string
+		block  int
+	}
+	initialized := map[scope]int{}
+	for _, m := range calls {
+		key := scope{src.masked[m[2]:m[3]], src.block(m[0])}
+		if _, seen := initialized[key]; !seen && src.masked[m[4]:m[5]] == initMethod {
+			initialized[key] = m[0]
+		}
+	}
+	for _, m := range calls {
+		client, method := src.masked[m[2]:m[3]], src.masked[m[4]:m[5]]
+		initAt, ok := initialized[scope{client, src.block(m[0])}]
+		if ok && m[0] < initAt && slices.Contains(requests, method) {
+			a.add(requestBeforeInitialize(client+"."+method, src.line(m[0])))
+		}
+	}
+}
+
+// sourceText is source code prepared for pattern matching: masked is the code
+// with comments and the contents of string literals blanked out, so expressions
+// matched against it only match code
+type sourceText struct {
+	masked      string
+	literals    []sourceLiteral
+	lineStarts  []int
+	blockStarts []int // offsets where each top-level block (function, class, ...) begins
+}
+
+func newSourceText(code string, masked []byte, literals []sourceLiteral) *sourceText {
+	src := &sourceText{masked: string(masked), lineStarts: []int{0}}
+	for i, c := range code {
+		if c == '\n' {
+			src.lineStarts = append(src.lineStarts, i+1)
+		}
+	}
+	for i := range literals {
+		literals[i].line = src.line(literals[i].offset)
+	}
+	src.literals = literals
+	return src
+}
+
+// line returns the 1-based line of an offset
+func (src *sourceText) line(offset int) int {
+	return sort.SearchInts(src.lineStarts, offset+1)
+}
+
+// block returns the index of the top-level block containing an offset
+func (src *sourceText) block(offset int) int {
+	return sort.SearchInts(src.blockStarts, offset+1)
+}
+
+// literalAt returns the string literal whose opening quote is at offset
+func (src *sourceText) literalAt(offset int) (sourceLiteral, bool) {
+	i := sort.Search(len(src.literals), func(i int) bool { return src.literals[i].offset >= offset })
+	if i < len(src.literals) && src.literals[i].offset == offset {
+		return src.literals[i], true
+	}
+	return sourceLiteral{}, false
+}
+
+// dispatchPrefix matches what precedes a method name being dispatched on, as in
+// case "tools/list": or method == "tools/list"
+var dispatchPrefix = regexp.MustCompile(`\bcase\s+|(?:^|[^!=])={2,3}\s*`)
+
+// dispatched returns the string literals compared against by a dispatch, with
+// the line each first appears on
+func (src *sourceText) dispatched() map[string]int {
+	handled := map[string]int{}
+	for _, m := range dispatchPrefix.FindAllStringIndex(src.masked, -1) {
+		if lit, ok := src.literalAt(m[1]); ok {
+			if _, seen := handled[lit.value]; !seen {
+				handled[lit.value] = lit.line
+			}
+		}
+	}
+	return handled
+}
+
+// sent returns the string literals in each top-level block that aren't dispatch
+// targets, in source order
+func (src *sourceText) sent() [][]sourceLiteral {
+	targets := map[int]bool{}
+	for _, m := range dispatchPrefix.FindAllStringIndex(src.masked, -1) {
+		targets[m[1]] = true
+	}
+	blocks := make([][]sourceLiteral, len(src.blockStarts)+1)
+	for _, lit := range src.literals {
+		if !targets[lit.offset] {
+			b := src.block(lit.offset)
+			blocks[b] = append(blocks[b], lit)
+		}
+	}
+	return blocks
+}
+
+// assigned matches what separates a key from its value, as in key: "value" or
+// key = "value"
+var assigned = regexp.MustCompile(`^\s*[:=]\s*`)
+
+// assignedLiterals returns the string literals assigned to any of keys, whether
+// the key is an identifier or itself a string, as in protocolVersion: "2025-06-18"
+// or {"protocolVersion": "2025-06-18"}
+func (src *sourceText) assignedLiterals(keys ...string) []sourceLiteral {
+	var values []sourceLiteral
+	valueAfter := func(offset int) {
+		if m := assigned.FindStringIndex(src.masked[offset:]); m != nil {
+			if lit, ok := src.literalAt(offset + m[1]); ok {
+				values = append(values, lit)
+			}
+		}
+	}
+	for _, key := range keys {
+		for _, m := range regexp.MustCompile(`\b`+regexp.QuoteMeta(key)+`\b`).FindAllStringIndex(src.masked, -1) {
+			valueAfter(m[1])
+		}
+	}
+	for _, lit := range src.literals {
+		if slices.Contains(keys, lit.value) {
+			valueAfter(lit.end)
+		}
+	}
+	return values
+}
+
+// keySeparator matches what follows a key in an object literal
+var keySeparator = regexp.MustCompile(`^\s*:`)
+
+// objectKeys returns the keys of the object literal whose opening brace is at
+// open, with the line each is on
+func (src *sourceText) objectKeys(open int) map[string]int {
+	keys := map[string]int{}
+	isIdent := func(c byte) bool {
+		return c == '_' || c == '$' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9'
+	}
+	key := func(name string, end int) {
+		if keySeparator.MatchString(src.masked[end:]) {
+			keys[name] = src.line(end)
+		}
+	}
+	depth := 0
+	for i := open; i < len(src.masked); i++ {
+		switch c := src.masked[i]; {
+		case c == '{' || c == '[' || c == '(':
+			depth++
+		case c == '}' || c == ']' || c == ')':
+			if depth--; depth == 0 {
+				return keys
+			}
+		case depth != 1:
+		case c == '"' || c == '\'':
+			if lit, ok := src.literalAt(i); ok {
+				key(lit.value, lit.end)
+				i = lit.end - 1
+			}
+		case isIdent(c) && (i == 0 || !isIdent(src.masked[i-1])):
+			j := i
+			for j < len(src.masked) && isIdent(src.masked[j]) {
+				j++
+			}
+			key(src.masked[i:j], j)
+			i = j - 1
+		}
+	}
+	return keys
+}
+
+// sdkHandler is a request an SDK server registers a handler for, with the
+// capability the server must declare to serve it
+type sdkHandler struct {
+	method     string
+	capability string
+}
+
+// importedNames parses an import list such as "Server, Client as C" into a map
+// from each imported name to its local name
+func importedNames(list, alias string) map[string]string {
+	names := map[string]string{}
+	for _, spec := range strings.Split(list, ",") {
+		fields := strings.Fields(spec)
+		switch {
+		case len(fields) == 1:
+			names[fields[0]] = fields[0]
+		case len(fields) == 3 && fields[1] == alias:
+			names[fields[0]] = fields[2]
+		}
+	}
+	return names
+}
+
+// constructed returns the names of variables and fields assigned the result of
+// calling constructor, as in client = new Client(...) or session = ClientSession(...)
+func (src *sourceText) constructed(constructor string) []string {
+	var names []string
+	pattern := regexp.MustCompile(`\b(\w+)\s*(?::\s*[\w.<>]+\s*)?=\s*(?:await\s+)?(?:new\s+)?` + regexp.QuoteMeta(constructor) + `\s*\(`)
+	for _, m := range pattern.FindAllStringSubmatch(src.masked, -1) {
+		if !slices.Contains(names, m[1]) {
+			names = append(names, m[1])
+		}
+	}
+	return names
+}
+
+// firstLine returns the line of the first match of pattern in the code, or 0
+func (src *sourceText) firstLine(pattern string) int {
+	if m := regexp.MustCompile(pattern).FindStringIndex(src.masked); m != nil {
+		return src.line(m[0])
+	}
+	return 0
+}
+
+// usesCustomJSONRPC reports whether the code builds JSON-RPC messages by hand
+func (src *sourceText) usesCustomJSONRPC() bool {
+	return slices.ContainsFunc(src.literals, func(l sourceLiteral) bool { return l.value == "2.0" }) &&
+		(slices.ContainsFunc(src.literals, func(l sourceLiteral) bool { return l.value == "jsonrpc" }) ||
+			regexp.MustCompile(`\bjsonrpc\s*:`).MatchString(src.masked))
+}
+
+// checkHandWritten runs the checks for hand-written protocol code: method names,
+// dispatch coverage, send order, and protocol version strings
+func (a *staticAnalysis) checkHandWritten(src *sourceText, specVersion string, versionKeys ...string) {
+	a.checkMethodNames(src.literals, specVersion)
+	a.checkDispatch(src.dispatched())
+	for _, sent := range src.sent() {
+		a.checkSendOrder(sent)
+	}
+	for _, lit := range src.assignedLiterals(versionKeys...) {
+		a.checkProtocolVersion(lit.value, lit.line, specVersion)
+	}
+}
+
+// blank replaces code[from:to] with spaces, keeping newlines so offsets and
+// lines stay the same
+func blank(masked []byte, from, to int) {
+	for i := from; i < to && i < len(masked); i++ {
+		if masked[i] != '\n' {
+			masked[i] = ' '
+		}
+	}
+}
+
+// scanQuoted returns the end of the string literal whose contents start at from
+// and that is closed by quote. Single-line literals end at a newline if unclosed.
+func scanQuoted(code string, from int, quote string, singleLine bool) int {
+	for i := from; i < len(code); i++ {
+		switch {
+		case code[i] == '\\':
+			i++
+		case singleLine && code[i] == '\n':
+			return i
+		case strings.HasPrefix(code[i:], quote):
+			return i
+		}
+	}
+	return len(code)
+}
+
+// checkSSETransport flags use of the HTTP+SSE transport, which 2025-03-26
+// replaced with Streamable HTTP
+func (a *staticAnalysis) checkSSETransport(name, replacement string, line int, specVersion string) {
+	if specVersion == "2024-11-05" {
+		return
+	}
+	a.add(NewValidationError(IssueTypeUnsupported, SeverityWarning, fmt.Sprintf("%s uses the HTTP+SSE transport, which %s replaces with Streamable HTTP", name, specVersion)).
+		WithFound(name).
+		WithExpected(replacement).
+		WithSpecSection("Transports: Backwards Compatibility").
+		WithLineNumber(line).
+		AddSuggestion("Use the Streamable HTTP transport, keeping the SSE endpoints only to support clients of the 2024-11-05 protocol"))
+}
+
+func requestBeforeInitialize(what string, line int) *ValidationError {
+	return NewValidationError(IssueTypeInaccuracy, SeverityWarning, fmt.Sprintf("%s is sent before the initialize request", what)).
+		WithFound(what).
+		WithExpected("initialize, then notifications/initialized, then other requests").
+		WithSpecSection("Lifecycle: Initialization").
+		WithLineNumber(line).
+		AddSuggestion("The client SHOULD NOT send requests other than pings before the server has responded to initialize")
+}
@@ -0,0 +1,275 @@
+package validator
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"sync/atomic"
+
+	"github.com/carlisia/mcp-factcheck/embedding"
+	mcpembedding "github.com/carlisia/mcp-factcheck/internal/embedding"
+	"github.com/carlisia/mcp-factcheck/internal/limits"
+	"github.com/carlisia/mcp-factcheck/internal/specs"
+	"github.com/carlisia/mcp-factcheck/internal/toolerr"
+	"github.com/carlisia/mcp-factcheck/pkg/logger"
+	"github.com/mark3labs/mcp-go/mcp"
+	"go.uber.org/zap"
+)
+
+const ValidateBatchToolName = "validate_batch"
+
+// BatchItem is one document of a batch validation
+type BatchItem struct {
+	ID          string `json:"id"` // Path or name identifying the document, unique within the batch
+	Content     string `json:"content"`
+	ContextType string `json:"context_type,omitempty"` // One of ContextTypes, or empty to check accuracy only
+}
+
+// BatchItemResult summarizes the validation of one document of a batch
+type BatchItemResult struct {
+	ID         string            `json:"id"`
+	IsValid    bool              `json:"is_valid"`
+	Confidence float64           `json:"confidence"`
+	Chunks     int               `json:"chunks,omitempty"` // Chunks validated, when the document was validated in chunks
+	Findings   []ValidationError `json:"findings,omitempty"`
+	Summary    string            `json:"summary,omitempty"`
+	Score      *Score            `json:"score,omitempty"` // Set when the batch asked for scoring
+	Error      string            `json:"error,omitempty"` // Why the document couldn't be validated; the other fields are then unset
+}
+
+// BatchStats aggregates the results of a batch
+type BatchStats struct {
+	Documents          int            `json:"documents"`
+	Valid              int            `json:"valid"`
+	Invalid            int            `json:"invalid"`
+	Failed             int            `json:"failed"` // Documents that couldn't be validated
+	FindingsBySeverity map[string]int `json:"findings_by_severity"`
+	AverageConfidence  float64        `json:"average_confidence"` // Over the documents that were validated
+}
+
+// BatchResult is the validation of every document of a batch, in the order given
+type BatchResult struct {
+	SpecVersion string            `json:"spec_version"`
+	Results     []BatchItemResult `json:"results"`
+	Stats       BatchStats        `json:"stats"`
+	Summary     string            `json:"summary"`
+}
+
+func GetValidateBatchTool() mcp.Tool {
+	schema := map[string]any{
+		"type": "object",
+		"properties": map[string]any{
+			"items": map[string]any{
+				"type":        "array",
+				"description": "Documents to validate",
+				"minItems":    1,
+				"items": map[string]any{
+					"type": "object",
+					"properties": map[string]any{
+						"id": map[string]any{
+							"type":        "string",
+							"description": "Path or name identifying the document in the results and the validation history. Must be unique within the batch",
+						},
+						"content": map[string]any{
+							"type":        "string",
+							"description": "Content of the document",
+						},
+						"contextType": map[string]any{
+							"type":        "string",
+							"description": "Type of content of this document, as for validate_content. Omit to check accuracy only",
+							"enum":        ContextTypes,
+						},
+					},
+					"required": []string{"id", "content"},
+				},
+			},
+			"specVersion": map[string]any{
+				"type":        "string",
+				"description": "MCP specification version to validate every document against",
+				"enum":        specs.ValidSpecVersions,
+				"default":     specs.DefaultSpecVersion,
+			},
+			"score": map[string]any{
+				"type":        "boolean",
+				"description": "Rate each document from 0 to 100 with a letter grade, as validate_content does (default: false)",
+				"default":     false,
+			},
+			"corpus": map[string]any{
+				"type":        "string",
+				"description": "Name of a documentation corpus ingested on the server to check every document against alongside the MCP specification",
+			},
+		},
+		"required": []string{"items"},
+	}
+	schemaBytes, _ := json.Marshal(schema)
+
+	description := `Validate many documents against the MCP specification in one call, such as every page of a documentation site.
+
+USE THIS INSTEAD OF calling validate_content repeatedly WHEN checking a docs tree or a set of related pages. Documents are validated concurrently, each as validate_content would, and the result lists each document's validity, confidence, and findings by id, with totals across the batch. A document that can't be validated is reported with an error without failing the others.`
+
+	return mcp.NewToolWithRawSchema(ValidateBatchToolName, description, schemaBytes)
+}
+
+// HandleValidateBatch validates every document of a batch
+func HandleValidateBatch(ctx context.Context, vectorDB *mcpembedding.VectorDB, generator *embedding.Generator, args any) ([]mcp.Content, error) {
+	params, ok := args.(map[string]any)
+	if !ok {
+		return nil, toolerr.InvalidArgs("arguments must be a map")
+	}
+	rawItems, ok := params["items"].([]any)
+	if !ok || len(rawItems) == 0 {
+		return nil, toolerr.InvalidArgs("items must be a non-empty array")
+	}
+	items := make([]BatchItem, len(rawItems))
+	for i, raw := range rawItems {
+		fields, ok := raw.(map[string]any)
+		if !ok {
+			return nil, toolerr.InvalidArgs("items[%d] must be an object", i)
+		}
+		id, _ := fields["id"].(string)
+		content, ok := fields["content"].(string)
+		if id == "" || !ok {
+			return nil, fmt.Errorf("items[%d] needs an id and content", i)
+		}
+		contextType, _ := fields["contextType"].(string)
+		items[i] = BatchItem{ID: id, Content: content, ContextType: contextType}
+	}
+	specVersion, _ := params["specVersion"].(string)
+	corpus, _ := params["corpus"].(string)
+	score, _ := params["score"].(bool)
+
+	result, err := ValidateBatch(ctx, vectorDB, generator, items, Request{SpecVersion: specVersion, Corpus: corpus, Score: score})
+	if err != nil {
+		return nil, err
+	}
+	jsonBytes, _ := json.MarshalIndent(result, "", "  ")
+	return []mcp.Content{mcp.NewTextContent(string(jsonBytes))}, nil
+}
+
+// ValidateBatch validates each item as Validate would with the options of req, named
+// by its id, validating up to batch_workers items at once. An item that fails is
+// reported in its result's Error; only a canceled ctx or an invalid batch fails the
+// whole call.
+func ValidateBatch(ctx context.Context, vectorDB *mcpembedding.VectorDB, generator *embedding.Generator, items []BatchItem, req Request) (*BatchResult, error) {
+	if err := limits.CheckBatchItems(len(items)); err != nil {
+		return nil, err
+	}
+	if req.SpecVersion == "" {
+		req.SpecVersion = specs.DefaultSpecVersion
+	}
+	if !specs.IsValidSpecVersion(req.SpecVersion) {
+		return nil, invalid(toolerr.SpecVersionNotFound(req.SpecVersion))
+	}
+	if req.Corpus != "" {
+		if err := vectorDB.CheckCorpus(req.Corpus); err != nil {
+			return nil, invalid(err)
+		}
+	}
+	seen := make(map[string]bool, len(items))
+	for _, item := range items {
+		if seen[item.ID] {
+			return nil, invalid(fmt.Errorf("duplicate id %q in batch", item.ID))
+		}
+		seen[item.ID] = true
+	}
+
+	workers := ToolSettingsFor(ValidateContentToolName).BatchWorkers
+	logger.WithRequestID(ctx).Info("Starting batch validation",
+		zap.Int("documents", len(items)),
+		zap.Int("workers", workers),
+		zap.String("spec_version", req.SpecVersion))
+
+	results := make([]BatchItemResult, len(items))
+	indexes := make(chan int)
+	// Progress counts documents; the chunks of documents validated at once would
+	// interleave
+	itemCtx := WithProgress(ctx, nil)
+	var done atomic.Int64
+	var wg sync.WaitGroup
+	for range min(workers, len(items)) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				itemReq := req
+				itemReq.Document = items[i].ID
+				itemReq.Content = items[i].Content
+				itemReq.ContextType = items[i].ContextType
+				results[i] = validateBatchItem(itemCtx, vectorDB, generator, itemReq)
+				finished := int(done.Add(1))
+				reportProgress(ctx, finished, len(items), fmt.Sprintf("Validated %d of %d documents", finished, len(items)))
+			}
+		}()
+	}
+dispatch:
+	for i := range items {
+		select {
+		case indexes <- i:
+		case <-ctx.Done():
+			break dispatch
+		}
+	}
+	close(indexes)
+	wg.Wait()
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	stats := batchStats(results)
+	return &BatchResult{
+		SpecVersion: req.SpecVersion,
+		Results:     results,
+		Stats:       stats,
+		Summary: fmt.Sprintf("Validated %d documents: %d valid, %d invalid, %d failed, with %d critical findings",
+			stats.Documents, stats.Valid, stats.Invalid, stats.Failed, stats.FindingsBySeverity[SeverityCritical]),
+	}, nil
+}
+
+// validateBatchItem validates one document of a batch, reporting failures in the result
+func validateBatchItem(ctx context.Context, vectorDB *mcpembedding.VectorDB, generator *embedding.Generator, req Request) BatchItemResult {
+	item := BatchItemResult{ID: req.Document}
+	result, err := Validate(ctx, vectorDB, generator, req)
+	if err != nil {
+		logger.WithRequestID(ctx).Warn("Batch document failed",
+			zap.String("document", req.Document),
+			zap.Error(err))
+		item.Error = err.Error()
+		return item
+	}
+	item.IsValid = result.Overall.IsValid
+	item.Confidence = result.Overall.Confidence
+	item.Chunks = len(result.ChunkResults)
+	item.Findings = result.Findings()
+	item.Summary = result.Summary
+	item.Score = result.Score
+	if item.Summary == "" {
+		item.Summary = fmt.Sprintf("%d findings", len(item.Findings))
+	}
+	return item
+}
+
+// batchStats totals the results of a batch
+func batchStats(results []BatchItemResult) BatchStats {
+	stats := BatchStats{Documents: len(results), FindingsBySeverity: map[string]int{}}
+	var confidence float64
+	for _, r := range results {
+		switch {
+		case r.Error != "":
+			stats.Failed++
+			continue
+		case r.IsValid:
+			stats.Valid++
+		default:
+			stats.Invalid++
+		}
+		confidence += r.Confidence
+		for _, finding := range r.Findings {
+			stats.FindingsBySeverity[finding.Severity]++
+		}
+	}
+	if validated := stats.Valid + stats.Invalid; validated > 0 {
+		stats.AverageConfidence = confidence / float64(validated)
+	}
+	return stats
+}
@@ -0,0 +1,62 @@
+package validator
+
+import (
+	"context"
+	"errors"
+
+	"github.com/carlisia/mcp-factcheck/embedding"
+	"github.com/carlisia/mcp-factcheck/internal/factcheck"
+	"github.com/carlisia/mcp-factcheck/internal/sampling"
+	"github.com/carlisia/mcp-factcheck/pkg/telemetry"
+)
+
+// Sources of chat models, as reported in corrected_by
+const (
+	ChatSampling = "sampling" // The MCP client's model, through sampling/createMessage
+	ChatOpenAI   = "openai"   // The server's OpenAI chat model
+)
+
+// ErrNoChatModel is returned by LLM-backed checks when the server has no OpenAI API
+// key and the client doesn't support sampling
+var ErrNoChatModel = errors.New("no chat model available: set OPENAI_API_KEY on the server or use a client that supports MCP sampling")
+
+// ChatModel is the chat model an LLM-backed check asks
+type ChatModel struct {
+	Ask    factcheck.Asker
+	Source string // ChatSampling or ChatOpenAI
+}
+
+// ChatModelFor returns the chat model for LLM-backed checks run with ctx: the
+// server's OpenAI model, or the MCP client's through sampling when the client
+// supports it and either preferSampling is set or the server has no API key. It
+// returns false when neither is available.
+func ChatModelFor(ctx context.Context, generator *embedding.Generator, preferSampling bool) (ChatModel, bool) {
+	client := sampling.FromContext(ctx)
+	if client.Available() && (preferSampling || generator.Client() == nil) {
+		return ChatModel{Ask: client.Ask, Source: ChatSampling}, true
+	}
+	if generator.Client() != nil {
+		return ChatModel{Ask: factcheck.OpenAI(generator.Client()), Source: ChatOpenAI}, true
+	}
+	return ChatModel{}, false
+}
+
+// name returns the model's name for telemetry; sampling leaves the choice of model
+// to the client
+func (m ChatModel) name() string {
+	if m.Source == ChatOpenAI {
+		return factcheck.Model()
+	}
+	return m.Source
+}
+
+// spanBuilder starts a span of kind for a request to the model
+func (m ChatModel) spanBuilder(kind string) telemetry.SpanBuilder {
+	builder := telemetry.NewSpanBuilder().WithKind(kind)
+	if m.Source == ChatOpenAI {
+		builder = builder.WithModel(factcheck.Model(), "openai", "openai")
+	} else {
+		builder = builder.WithModel(m.Source, "mcp", m.Source)
+	}
+	return builder
+}
@@ -5,22 +5,33 @@ import (
 	"encoding/json"
 	"fmt"
 	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
 
 	"github.com/carlisia/mcp-factcheck/embedding"
+	"github.com/carlisia/mcp-factcheck/internal/cost"
 	mcpembedding "github.com/carlisia/mcp-factcheck/internal/embedding"
+	"github.com/carlisia/mcp-factcheck/internal/factcheck"
+	"github.com/carlisia/mcp-factcheck/internal/limits"
+	"github.com/carlisia/mcp-factcheck/pkg/logger"
 	"github.com/carlisia/mcp-factcheck/pkg/telemetry"
+	"github.com/carlisia/mcp-factcheck/pkg/tokens"
 	"github.com/mark3labs/mcp-go/mcp"
-	"github.com/tmc/langchaingo/textsplitter"
 	"go.opentelemetry.io/otel/attribute"
+	"go.uber.org/zap"
 )
 
 // ContentChunk represents a logical piece of content for validation
 type ContentChunk struct {
-	ID       string `json:"id"`
-	Text     string `json:"text"`
-	Position int    `json:"position"`
-	Type     string `json:"type"` // "paragraph", "heading", "code_block", "list_item"
-	Level    int    `json:"level,omitempty"` // For headings (1-6)
+	ID        string `json:"id"`
+	Text      string `json:"text"`
+	Position  int    `json:"position"`
+	Type      string `json:"type"`                 // "section", "paragraph", "code_block", "list", "table", "quote", or "text_chunk" for size-based splits
+	Level     int    `json:"level,omitempty"`      // Level (1-6) of the heading a section starts with
+	StartLine int    `json:"start_line,omitempty"` // 1-based line in the original content where the chunk begins
+	EndLine   int    `json:"end_line,omitempty"`   // 1-based line in the original content where the chunk ends
+	Heading   string `json:"heading,omitempty"`    // Nearest markdown heading at or above the chunk's first line
 }
 
 // ChunkingResult contains the chunked content and metadata
@@ -29,67 +40,134 @@ type ChunkingResult struct {
 	TotalChunks int           `json:"total_chunks"`
 	TotalChars  int           `json:"total_chars"`
 	EstTokens   int           `json:"estimated_tokens"`
+	Options     ChunkOptions  `json:"chunking"` // Strategy and sizes used, with auto resolved to the strategy it chose
 }
 
-// ChunkContent splits content into logical chunks for validation using langchaingo
+// ChunkContent splits content into logical chunks for validation with the configured strategy
 func ChunkContent(content string) *ChunkingResult {
+	return chunkContent(content, ChunkOptions{}.withDefaults(ToolSettingsFor(ValidateContentToolName)))
+}
+
+// ChunkContentWith splits content into chunks with opts, falling back to the
+// configured settings for unset fields
+func ChunkContentWith(content string, opts ChunkOptions) (*ChunkingResult, error) {
+	opts = opts.withDefaults(ToolSettingsFor(ValidateContentToolName))
+	if err := opts.Validate(); err != nil {
+		return nil, err
+	}
+	return chunkContent(content, opts), nil
+}
+
+func chunkContent(content string, opts ChunkOptions) *ChunkingResult {
 	if strings.TrimSpace(content) == "" {
 		return &ChunkingResult{
 			Chunks:      []ContentChunk{},
 			TotalChunks: 0,
 			TotalChars:  0,
 			EstTokens:   0,
+			Options:     opts,
 		}
 	}
 
-	// Choose splitter based on content type
-	var splitter textsplitter.TextSplitter
-	
-	// Use markdown splitter if content contains markdown-like patterns
-	if strings.Contains(content, "#") || strings.Contains(content, "```") || 
-	   strings.Contains(content, "- ") || strings.Contains(content, "* ") {
-		splitter = textsplitter.NewMarkdownTextSplitter(
-			textsplitter.WithChunkSize(800),    // Smaller chunks for better granularity
-			textsplitter.WithChunkOverlap(100), // Overlap for context preservation
-		)
-	} else {
-		// Use recursive character splitter for plain text
-		splitter = textsplitter.NewRecursiveCharacter(
-			textsplitter.WithChunkSize(800),    // Smaller chunks for better granularity
-			textsplitter.WithChunkOverlap(100), // Overlap for context preservation
-		)
-	}
-	
-	// Split the content
-	docs, err := splitter.SplitText(content)
-	if err != nil {
-		// Fallback to simple splitting if the splitter fails
-		docs = []string{content}
-	}
-
-	// Convert to our ContentChunk format
-	chunks := make([]ContentChunk, len(docs))
-	for i, doc := range docs {
-		chunks[i] = ContentChunk{
-			ID:       generateChunkID("chunk", i),
-			Text:     strings.TrimSpace(doc),
-			Position: i,
-			Type:     "text_chunk", // langchaingo doesn't classify types, so use generic
+	pieces, strategy := splitContent(content, opts)
+	opts.Strategy = strategy
+
+	// Number the pieces and anchor them to lines of the content
+	chunks := make([]ContentChunk, 0, len(pieces))
+	headings := sectionHeadings(content)
+	offset := 0
+	for _, chunk := range pieces {
+		chunk.Text = strings.TrimSpace(chunk.Text)
+		if chunk.Text == "" {
+			continue
+		}
+		// Size-based splitters don't track lines, so find the text in the content
+		if chunk.StartLine == 0 {
+			chunk.StartLine, offset = locateChunk(content, chunk.Text, offset)
+			if chunk.StartLine > 0 {
+				chunk.EndLine = locateChunkEnd(content, chunk.Text, offset-1)
+			}
+		}
+		chunk.Position = len(chunks)
+		chunk.ID = generateChunkID("chunk", chunk.Position)
+		if chunk.StartLine > 0 {
+			chunk.Heading = headings[chunk.StartLine-1]
 		}
+		chunks = append(chunks, chunk)
 	}
 
 	// Calculate metadata
 	totalChars := len(content)
-	estTokens := totalChars / 4 // Rough approximation
+	estTokens := tokens.Count(content)
 
 	return &ChunkingResult{
 		Chunks:      chunks,
 		TotalChunks: len(chunks),
 		TotalChars:  totalChars,
 		EstTokens:   estTokens,
+		Options:     opts,
 	}
 }
 
+// sectionHeadings returns, for each line of content, the text of the nearest markdown
+// heading at or above it. Lines starting with '#' inside fenced code blocks are ignored.
+func sectionHeadings(content string) []string {
+	lines := strings.Split(content, "\n")
+	headings := make([]string, len(lines))
+	var current string
+	inFence := false
+	for i, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		switch {
+		case strings.HasPrefix(trimmed, "```"), strings.HasPrefix(trimmed, "~~~"):
+			inFence = !inFence
+		case !inFence && strings.HasPrefix(trimmed, "#"):
+			level := len(trimmed) - len(strings.TrimLeft(trimmed, "#"))
+			if level <= 6 && (level == len(trimmed) || trimmed[level] == ' ') {
+				current = strings.TrimSpace(trimmed[level:])
+			}
+		}
+		headings[i] = current
+	}
+	return headings
+}
+
+// locateChunk finds where chunk text begins in content, searching from offset since chunks are
+// emitted in order. It returns the 1-based line number (0 if not found) and the offset to resume from.
+func locateChunk(content, text string, offset int) (int, int) {
+	if offset > len(content) {
+		return 0, offset
+	}
+	// Splitters normalize whitespace and repeat parent headings in later chunks,
+	// so anchor on the first line of the chunk that appears verbatim after offset
+	for _, line := range strings.Split(text, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		if idx := strings.Index(content[offset:], line); idx >= 0 {
+			start := offset + idx
+			return strings.Count(content[:start], "\n") + 1, start + 1
+		}
+	}
+	return 0, offset
+}
+
+// locateChunkEnd returns the 1-based line where the chunk's last line appears at or after start,
+// or 0 if it can't be found
+func locateChunkEnd(content, text string, start int) int {
+	lines := strings.Split(text, "\n")
+	for i := len(lines) - 1; i >= 0; i-- {
+		line := strings.TrimSpace(lines[i])
+		if line == "" {
+			continue
+		}
+		if idx := strings.Index(content[start:], line); idx >= 0 {
+			return strings.Count(content[:start+idx], "\n") + 1
+		}
+	}
+	return 0
+}
 
 func generateChunkID(prefix string, position int) string {
 	return fmt.Sprintf("%s-%d", prefix, position)
@@ -101,6 +179,9 @@ type ChunkValidationResult struct {
 	Validation ValidationResult   `json:"validation,omitempty"`
 	Matches    []ValidationMatch  `json:"matches,omitempty"`
 	Error      string            `json:"error,omitempty"`
+	Reused     bool              `json:"reused,omitempty"` // Unchanged since the validation being revalidated, so not checked again
+
+	embedding []float64 // The chunk's embedding, for the coverage check
 }
 
 // AggregatedValidationResult contains validation results for all chunks
@@ -109,10 +190,85 @@ type AggregatedValidationResult struct {
 	Overall      ValidationResult        `json:"overall_validation"`
 	Summary      string                 `json:"summary"`
 	SpecVersion  string                 `json:"spec_version"`
+	Chunking     ChunkOptions           `json:"chunking"` // How the content was split
+	Matches      []ValidationMatch      `json:"matches,omitempty"` // Best matching spec sections, when the content was validated as a whole
+	UnchangedSince *time.Time           `json:"unchanged_since,omitempty"` // When the same content was validated earlier in the session, if this result is that one's
+	Score        *Score                 `json:"score,omitempty"`    // Set when the request asked for scoring
+}
+
+// Findings collects the structured findings of every validated chunk, in document order
+func (r AggregatedValidationResult) Findings() []ValidationError {
+	var findings []ValidationError
+	for _, cr := range r.ChunkResults {
+		if cr.Error != "" {
+			continue
+		}
+		findings = append(findings, cr.Validation.Errors...)
+	}
+	// Missing requirements aren't anchored to any chunk
+	return append(findings, r.Overall.Errors...)
+}
+
+// newFinding turns a failed validation into a structured finding, or returns nil if the text passed
+func newFinding(text string, validation ValidationResult, matches []ValidationMatch, settings Settings) *ValidationError {
+	if validation.IsValid {
+		return nil
+	}
+
+	var finding *ValidationError
+	if validation.Confidence < settings.LowSimilarityThreshold {
+		finding = NewValidationError(IssueTypeInaccuracy, SeverityCritical, "Section shows low alignment with the MCP specification")
+	} else {
+		finding = NewValidationError(IssueTypeImprecise, SeverityWarning, "Section may not align with the MCP specification")
+	}
+	finding.WithFound(getContentPreview(text, 200))
+	finding.SuggestedRewrite = validation.CorrectedVersion
+	finding.Confidence = validation.Confidence
+	if len(matches) > 0 {
+		if matches[0].Section != "" {
+			finding.WithSpecSection(matches[0].Section)
+		} else {
+			finding.WithSpecSection(matches[0].Topic)
+		}
+		finding.WithSpecURL(matches[0].URL)
+	}
+	for _, suggestion := range validation.Suggestions {
+		finding.AddSuggestion(suggestion)
+	}
+	for _, claim := range validation.Claims {
+		if claim.Verdict == factcheck.VerdictContradicted && claim.Citation != "" {
+			finding.AddSuggestion(fmt.Sprintf("%q contradicts the spec: %q", claim.Claim, claim.Citation))
+		}
+	}
+	return finding
 }
 
 // HandleChunkedValidation processes long content by chunking it and validating each piece
 func HandleChunkedValidation(ctx context.Context, vectorDB *mcpembedding.VectorDB, generator *embedding.Generator, content, specVersion string) ([]mcp.Content, error) {
+	aggregated, err := ValidateChunked(ctx, vectorDB, generator, content, specVersion)
+	if err != nil {
+		return nil, err
+	}
+	reportResult(ctx, content, *aggregated)
+
+	// Format response
+	response := FormatChunkedValidationResult(*aggregated)
+	return []mcp.Content{mcp.NewTextContent(response)}, nil
+}
+
+// reportResult reports a validation's result to the observer in ctx, if any
+func reportResult(ctx context.Context, content string, result AggregatedValidationResult) {
+	reportOutcome(ctx, Outcome{
+		Content:     content,
+		SpecVersion: result.SpecVersion,
+		Valid:       result.Overall.IsValid,
+		Confidence:  result.Overall.Confidence,
+		Findings:    result.Findings(),
+	})
+}
+
+// ValidateChunked chunks content and validates each chunk, returning the structured result
+func ValidateChunked(ctx context.Context, vectorDB *mcpembedding.VectorDB, generator *embedding.Generator, content, specVersion string) (*AggregatedValidationResult, error) {
 	// Start content chunking span using telemetry builder
 	ctx, chunkingSpan := telemetry.NewSpanBuilder().
 		WithKind("CHAIN").
@@ -120,16 +276,23 @@ func HandleChunkedValidation(ctx context.Context, vectorDB *mcpembedding.VectorD
 		WithCustom(
 			attribute.String("session.id", "chunked-validation"),
 			attribute.Int("content.length", len(content)),
-			attribute.Int("content.estimated_tokens", len(content)/4),
+			attribute.Int("content.estimated_tokens", tokens.Count(content)),
 		).
 		Start(ctx, "content.chunking")
 	defer chunkingSpan.End()
 	
 	// Chunk the content
-	chunkingResult := ChunkContent(content)
+	chunkingResult, err := ChunkContentWith(content, chunkingFrom(ctx))
+	if err != nil {
+		telemetry.RecordError(chunkingSpan, err)
+		return nil, err
+	}
 	
 	// Add chunking results to span using OpenInference conventions
 	chunkingSpan.SetAttributes(
+		attribute.String("chunks.strategy", chunkingResult.Options.Strategy),
+		attribute.Int("chunks.size", chunkingResult.Options.Size),
+		attribute.Int("chunks.overlap", chunkingResult.Options.Overlap),
 		attribute.Int("chunks.total", chunkingResult.TotalChunks),
 		attribute.Int("chunks.total_chars", chunkingResult.TotalChars),
 		attribute.Int("chunks.estimated_tokens", chunkingResult.EstTokens),
@@ -139,115 +302,65 @@ func HandleChunkedValidation(ctx context.Context, vectorDB *mcpembedding.VectorD
 	if len(chunkingResult.Chunks) == 0 {
 		return nil, fmt.Errorf("no valid chunks found in content")
 	}
+	if err := limits.CheckChunks(chunkingResult.TotalChunks); err != nil {
+		telemetry.RecordError(chunkingSpan, err)
+		return nil, err
+	}
+	// Refuse the whole document up front rather than reporting every chunk as failed
+	if err := cost.Allow(); err != nil {
+		telemetry.RecordError(chunkingSpan, err)
+		return nil, err
+	}
 	
-	// Validate each chunk
-	var chunkResults []ChunkValidationResult
+	// Validate chunks concurrently, keeping results in document order. Chunks unchanged
+	// since a validation being revalidated keep their earlier results.
+	settings := ToolSettingsFor(ValidateContentToolName)
+	chunkResults := make([]ChunkValidationResult, len(chunkingResult.Chunks))
+	previous := previousChunks(ctx)
+	var changed []int
+	for i, chunk := range chunkingResult.Chunks {
+		if prev, ok := previous[chunk.Text]; ok {
+			chunkResults[i] = prev.reusedFor(chunk)
+		} else {
+			changed = append(changed, i)
+		}
+	}
+	changedChunks := make([]ContentChunk, len(changed))
+	for j, i := range changed {
+		changedChunks[j] = chunkingResult.Chunks[i]
+	}
+	validated, err := validateChunks(ctx, vectorDB, generator, changedChunks, specVersion)
+	if err != nil {
+		telemetry.RecordError(chunkingSpan, err)
+		return nil, err
+	}
+	for j, i := range changed {
+		chunkResults[i] = validated[j]
+	}
+
 	var totalSimilarity float64
 	var totalChunks int
-	
-	for _, chunk := range chunkingResult.Chunks {
-		// Start span for individual chunk validation using telemetry builder
-		chunkCtx, chunkSpan := telemetry.NewSpanBuilder().
-			WithKind("CHAIN").
-			WithInput(chunk.Text, "text/plain").
-			WithCustom(
-				attribute.String("chunk.id", chunk.ID),
-				attribute.String("chunk.type", chunk.Type),
-				attribute.Int("chunk.length", len(chunk.Text)),
-			).
-			Start(ctx, "chunk.validation")
-		
-		// Generate embedding for this chunk using telemetry builder
-		embeddingCtx, embeddingSpan := telemetry.StartEmbeddingSpan(chunkCtx, chunk.Text)
-		
-		chunkEmbedding, err := generator.GenerateEmbedding(chunk.Text)
-		embeddingSpan.End()
-		
-		if err != nil {
-			embeddingSpan.SetAttributes(attribute.String("embedding.error", err.Error()))
-			embeddingSpan.RecordError(err)
-			chunkSpan.SetAttributes(attribute.String("chunk.error", err.Error()))
-			chunkSpan.RecordError(err)
-			chunkSpan.End()
-			
-			chunkResults = append(chunkResults, ChunkValidationResult{
-				Chunk: chunk,
-				Error: fmt.Sprintf("failed to generate embedding: %v", err),
-			})
-			continue
-		}
-		
-		// Search for relevant spec sections using telemetry builder
-		searchCtx, searchSpan := telemetry.StartRetrievalSpan(embeddingCtx, specVersion, 3)
-		searchSpan.SetAttributes(attribute.String("chunk_id", chunk.ID))
-		
-		results, err := vectorDB.Search(specVersion, chunkEmbedding, 3)
-		
-		if err != nil {
-			searchSpan.SetAttributes(attribute.String("search.error", err.Error()))
-			searchSpan.RecordError(err)
-			searchSpan.End()
-			chunkSpan.SetAttributes(attribute.String("chunk.error", err.Error()))
-			chunkSpan.RecordError(err)
-			chunkSpan.End()
-			
-			chunkResults = append(chunkResults, ChunkValidationResult{
-				Chunk: chunk,
-				Error: fmt.Sprintf("failed to search specifications: %v", err),
-			})
+	for _, chunkResult := range chunkResults {
+		if chunkResult.Error != "" {
 			continue
 		}
-		
-		// Calculate search results metrics
-		var avgSimilarity float64
-		if len(results) > 0 {
-			var totalSim float64
-			for _, result := range results {
-				totalSim += result.Similarity
-			}
-			avgSimilarity = totalSim / float64(len(results))
-		}
-		
-		searchSpan.SetAttributes(
-			attribute.Int("document_count", len(results)),
-			attribute.Float64("avg_similarity", avgSimilarity),
-			attribute.Bool("has_results", len(results) > 0),
-		)
-		searchSpan.End()
-		
-		// Analyze validation for this chunk
-		validation := analyzeChunkValidation(chunk.Text, results, specVersion)
-		matches := summarizeChunkMatches(results, 2)
-		
-		// Add chunk validation results to span
-		chunkSpan.SetAttributes(
-			attribute.Float64("chunk.confidence", validation.Confidence),
-			attribute.Bool("chunk.is_valid", validation.IsValid),
-			attribute.Int("chunk.matches_count", len(matches)),
-			attribute.String("output.mime_type", "application/json"),
-		)
-		chunkSpan.End()
-		
-		chunkResults = append(chunkResults, ChunkValidationResult{
-			Chunk:      chunk,
-			Validation: validation,
-			Matches:    matches,
-		})
-		
+
 		// Track overall metrics
-		totalSimilarity += validation.Confidence
+		totalSimilarity += chunkResult.Validation.Confidence
 		totalChunks++
-		
-		// Use searchCtx to keep context chain
-		_ = searchCtx
 	}
 	
+	if totalChunks == 0 {
+		return nil, fmt.Errorf("no chunks could be validated: %s", chunkResults[0].Error)
+	}
+
 	// Create overall validation summary
 	avgConfidence := totalSimilarity / float64(totalChunks)
 	overallValidation := ValidationResult{
-		IsValid:     avgConfidence > 0.7,
+		IsValid:     avgConfidence > settings.SimilarityThreshold,
 		Confidence:  avgConfidence,
 		SpecVersion: specVersion,
+		ContextType: contextTypeFrom(ctx),
 	}
 	
 	// Set overall issues and suggestions
@@ -255,7 +368,7 @@ func HandleChunkedValidation(ctx context.Context, vectorDB *mcpembedding.VectorD
 		overallValidation.Issues = []string{
 			fmt.Sprintf("%d chunks analyzed with average confidence %.2f", totalChunks, avgConfidence),
 		}
-		if avgConfidence < 0.5 {
+		if avgConfidence < settings.LowSimilarityThreshold {
 			overallValidation.Issues = append(overallValidation.Issues, "Multiple sections show low alignment with MCP specification")
 		}
 		overallValidation.Suggestions = []string{
@@ -263,18 +376,174 @@ func HandleChunkedValidation(ctx context.Context, vectorDB *mcpembedding.VectorD
 			"Consider using standard MCP terminology throughout",
 		}
 	}
+
+	var embeddings [][]float64
+	for _, chunkResult := range chunkResults {
+		if chunkResult.embedding != nil {
+			embeddings = append(embeddings, chunkResult.embedding)
+		}
+	}
+	applyCoverage(ctx, vectorDB, specVersion, content, embeddings, &overallValidation)
+	applyVersionChecks(content, specVersion, &overallValidation)
+	applyTerminology(content, specVersion, &overallValidation)
+	applyRules(ctx, content, specVersion, &overallValidation)
 	
+	summary := fmt.Sprintf("Analyzed %d content chunks", len(chunkResults))
+	if previous != nil {
+		summary = fmt.Sprintf("Revalidated %d changed of %d content chunks", len(changed), len(chunkResults))
+	}
+
 	// Create aggregated result
-	aggregated := AggregatedValidationResult{
+	return &AggregatedValidationResult{
 		ChunkResults: chunkResults,
 		Overall:      overallValidation,
-		Summary:      fmt.Sprintf("Analyzed %d content chunks", len(chunkResults)),
+		Summary:      summary,
 		SpecVersion:  specVersion,
+		Chunking:     chunkingResult.Options,
+	}, nil
+}
+
+// validateChunks validates chunks concurrently with the configured number of workers,
+// returning their results in the same order
+func validateChunks(ctx context.Context, vectorDB *mcpembedding.VectorDB, generator *embedding.Generator, chunks []ContentChunk, specVersion string) ([]ChunkValidationResult, error) {
+	settings := ToolSettingsFor(ValidateContentToolName)
+	results := make([]ChunkValidationResult, len(chunks))
+	indexes := make(chan int)
+	log := logger.WithRequestID(ctx)
+	var done atomic.Int64
+	var wg sync.WaitGroup
+	for range min(settings.ChunkWorkers, len(chunks)) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				results[i] = ValidateChunk(ctx, vectorDB, generator, chunks[i], specVersion)
+				finished := int(done.Add(1))
+				reportProgress(ctx, finished, len(chunks), fmt.Sprintf("Validated %d of %d chunks", finished, len(chunks)))
+				log.Info("Validated chunk",
+					zap.Int("done", finished),
+					zap.Int("total", len(chunks)),
+					zap.String("chunk_id", chunks[i].ID),
+					zap.Float64("confidence", results[i].Validation.Confidence))
+			}
+		}()
+	}
+dispatch:
+	for i := range chunks {
+		select {
+		case indexes <- i:
+		case <-ctx.Done():
+			break dispatch
+		}
 	}
+	close(indexes)
+	wg.Wait()
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
+// ValidateChunk embeds a single chunk and compares it against the spec. Failures are reported
+// in the result's Error field so one bad chunk doesn't abort the whole document.
+func ValidateChunk(ctx context.Context, vectorDB *mcpembedding.VectorDB, generator *embedding.Generator, chunk ContentChunk, specVersion string) ChunkValidationResult {
+	// A chunk picked up after the request was canceled isn't worth starting
+	if err := ctx.Err(); err != nil {
+		return ChunkValidationResult{Chunk: chunk, Error: err.Error()}
+	}
+	settings := ToolSettingsFor(ValidateContentToolName)
+
+	// Start span for individual chunk validation using telemetry builder
+	chunkCtx, chunkSpan := telemetry.NewSpanBuilder().
+		WithKind("CHAIN").
+		WithInput(chunk.Text, "text/plain").
+		WithCustom(
+			attribute.String("chunk.id", chunk.ID),
+			attribute.String("chunk.type", chunk.Type),
+			attribute.Int("chunk.length", len(chunk.Text)),
+		).
+		Start(ctx, "chunk.validation")
+	defer chunkSpan.End()
 	
-	// Format response
-	response := FormatChunkedValidationResult(aggregated)
-	return []mcp.Content{mcp.NewTextContent(response)}, nil
+	// Generate embedding for this chunk using telemetry builder
+	embeddingCtx, embeddingSpan := telemetry.StartEmbeddingSpan(chunkCtx, chunk.Text)
+	
+	chunkEmbedding, err := generator.GenerateEmbedding(embeddingCtx, chunk.Text)
+	embeddingSpan.End()
+	
+	if err != nil {
+		embeddingSpan.SetAttributes(attribute.String("embedding.error", err.Error()))
+		telemetry.RecordError(embeddingSpan, err)
+		chunkSpan.SetAttributes(attribute.String("chunk.error", err.Error()))
+		telemetry.RecordError(chunkSpan, err)
+		
+		return ChunkValidationResult{
+			Chunk: chunk,
+			Error: fmt.Sprintf("failed to generate embedding: %v", err),
+		}
+	}
+	
+	// Search for relevant spec sections using telemetry builder
+	searchCtx, searchSpan := telemetry.StartRetrievalSpan(embeddingCtx, specVersion, settings.ChunkTopK)
+	searchSpan.SetAttributes(attribute.String("chunk_id", chunk.ID))
+	
+	results, err := searchSpec(searchCtx, vectorDB, generator, settings, specVersion, chunk.Text, chunkEmbedding, settings.ChunkTopK)
+	
+	if err != nil {
+		searchSpan.SetAttributes(attribute.String("search.error", err.Error()))
+		telemetry.RecordError(searchSpan, err)
+		searchSpan.End()
+		chunkSpan.SetAttributes(attribute.String("chunk.error", err.Error()))
+		telemetry.RecordError(chunkSpan, err)
+		
+		return ChunkValidationResult{
+			Chunk: chunk,
+			Error: fmt.Sprintf("failed to search specifications: %v", err),
+		}
+	}
+	
+	// Calculate search results metrics
+	var avgSimilarity float64
+	if len(results) > 0 {
+		var totalSim float64
+		for _, result := range results {
+			totalSim += result.Similarity
+		}
+		avgSimilarity = totalSim / float64(len(results))
+	}
+	
+	searchSpan.SetAttributes(
+		attribute.Int("document_count", len(results)),
+		attribute.Float64("avg_similarity", avgSimilarity),
+		attribute.Bool("has_results", len(results) > 0),
+	)
+	searchSpan.End()
+	
+	// Analyze validation for this chunk
+	validation := analyzeChunkValidation(chunk.Text, results, specVersion)
+	applyClaimCheck(chunkCtx, chunk.Text, results, &validation)
+	applyRewrite(chunkCtx, chunk.Text, results, &validation)
+	matches := summarizeChunkMatches(results, 2)
+	applyExplanations(chunkCtx, chunk.Text, chunk.StartLine, results, matches)
+	if finding := newFinding(chunk.Text, validation, matches, ToolSettingsFor(ValidateContentToolName)); finding != nil {
+		finding.WithLineRange(chunk.StartLine, chunk.EndLine)
+		validation.Errors = []ValidationError{*finding}
+	}
+	
+	// Add chunk validation results to span
+	chunkSpan.SetAttributes(
+		attribute.Float64("chunk.confidence", validation.Confidence),
+		attribute.Bool("chunk.is_valid", validation.IsValid),
+		attribute.Int("chunk.matches_count", len(matches)),
+		attribute.String("output.mime_type", "application/json"),
+	)
+	
+	return ChunkValidationResult{
+		Chunk:      chunk,
+		Validation: validation,
+		Matches:    matches,
+		embedding:  chunkEmbedding,
+	}
 }
 
 // analyzeChunkValidation determines if a chunk is valid and provides insights
@@ -296,7 +565,8 @@ func analyzeChunkValidation(content string, results []embedding.SearchResult, sp
 	avgSimilarity := totalSimilarity / float64(len(results))
 	
 	// Determine validation based on similarity thresholds
-	isValid := avgSimilarity > 0.7
+	settings := ToolSettingsFor(ValidateContentToolName)
+	isValid := avgSimilarity > settings.SimilarityThreshold
 	confidence := avgSimilarity
 	
 	var issues []string
@@ -304,7 +574,7 @@ func analyzeChunkValidation(content string, results []embedding.SearchResult, sp
 	
 	if !isValid {
 		issues = append(issues, "Content section may not align with MCP specification")
-		if avgSimilarity < 0.5 {
+		if avgSimilarity < settings.LowSimilarityThreshold {
 			issues = append(issues, "Low similarity to MCP patterns detected")
 		}
 		suggestions = append(suggestions, "Review this section against MCP specification")
@@ -355,6 +625,9 @@ func summarizeChunkMatches(results []embedding.SearchResult, maxMatches int) []V
 			Topic:     topic,
 			Relevance: result.Similarity,
 			Summary:   summary,
+			Source:    ResultSource(result),
+			Section:   result.Chunk.Section,
+			URL:       result.Chunk.URL,
 		})
 	}
 	return matches
@@ -362,15 +635,27 @@ func summarizeChunkMatches(results []embedding.SearchResult, maxMatches int) []V
 
 // FormatChunkedValidationResult creates a structured response for chunked validation
 func FormatChunkedValidationResult(result AggregatedValidationResult) string {
+	response := chunkedResponse(result)
+	
+	jsonBytes, _ := json.MarshalIndent(response, "", "  ")
+	return string(jsonBytes)
+}
+// chunkedResponse is the response body for a chunked validation, for tools to extend
+func chunkedResponse(result AggregatedValidationResult) map[string]interface{} {
 	response := map[string]interface{}{
 		"validation_type": "chunked_content",
 		"total_chunks":    len(result.ChunkResults),
 		"overall":         result.Overall,
 		"summary":         result.Summary,
 		"spec_version":    result.SpecVersion,
+		"chunking":        result.Chunking,
 		"chunk_details":   result.ChunkResults,
 	}
-	
-	jsonBytes, _ := json.MarshalIndent(response, "", "  ")
-	return string(jsonBytes)
-}
\ No newline at end of file
+	if result.UnchangedSince != nil {
+		response["unchanged_since"] = result.UnchangedSince
+	}
+	if result.Score != nil {
+		response["score"] = result.Score
+	}
+	return response
+}
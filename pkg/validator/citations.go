@@ -0,0 +1,32 @@
+package validator
+
+import "github.com/carlisia/mcp-factcheck/internal/specs"
+
+// sectionPages maps the spec sections static analysis findings cite to their page
+// and anchor under a version's published URL
+var sectionPages = map[string]string{
+	"Overview":                            "",
+	"Architecture":                        "architecture",
+	"Transports":                          "basic/transports",
+	"Authorization":                       "basic/authorization",
+	"Base Protocol: Messages":             "basic#messages",
+	"Lifecycle: Initialization":           "basic/lifecycle#initialization",
+	"Lifecycle: Version Negotiation":      "basic/lifecycle#version-negotiation",
+	"Lifecycle: Capability Negotiation":   "basic/lifecycle#capability-negotiation",
+	"Utilities: Ping":                     "basic/utilities/ping",
+	"Transports: Backwards Compatibility": "basic/transports#backwards-compatibility",
+	"Server Features: Tools":              "server/tools",
+	"Server Features: Resources":          "server/resources",
+	"Server Features: Prompts":            "server/prompts",
+	"Client Features: Elicitation":        "client/elicitation",
+}
+
+// sectionURL returns the link to a named spec section in specVersion, or "" if the
+// section has no known page
+func sectionURL(specVersion, section string) string {
+	page, ok := sectionPages[section]
+	if !ok {
+		return ""
+	}
+	return specs.PageURL(specVersion, page)
+}
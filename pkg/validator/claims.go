@@ -0,0 +1,72 @@
+package validator
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/carlisia/mcp-factcheck/embedding"
+	"github.com/carlisia/mcp-factcheck/internal/factcheck"
+	"github.com/carlisia/mcp-factcheck/pkg/logger"
+	"github.com/carlisia/mcp-factcheck/pkg/telemetry"
+	"go.opentelemetry.io/otel/attribute"
+	"go.uber.org/zap"
+)
+
+type claimCheckKey struct{}
+
+// WithClaimCheck enables the LLM claim verification pass for validations run with ctx.
+// Low-confidence content is sent, with its retrieved spec sections, to model.
+func WithClaimCheck(ctx context.Context, model ChatModel) context.Context {
+	return context.WithValue(ctx, claimCheckKey{}, model)
+}
+
+// applyClaimCheck verifies the claims in low-confidence content and folds the verdicts
+// into the validation result. When every claim is supported the similarity verdict is
+// overridden; contradicted claims are reported as issues.
+func applyClaimCheck(ctx context.Context, content string, results []embedding.SearchResult, validation *ValidationResult) {
+	model, ok := ctx.Value(claimCheckKey{}).(ChatModel)
+	if !ok || validation.IsValid || len(results) == 0 {
+		return
+	}
+
+	ctx, span := model.spanBuilder("LLM").
+		WithInput(content, "text/plain").
+		Start(ctx, "claim.verification")
+	defer span.End()
+
+	sections := make([]factcheck.SpecSection, len(results))
+	for i, r := range results {
+		sections[i] = factcheck.SpecSection{ID: r.Chunk.ID, Content: r.Chunk.Content}
+	}
+
+	claims, err := factcheck.VerifyClaims(ctx, model.Ask, content, sections)
+	if err != nil {
+		telemetry.RecordError(span, err)
+		logger.WithRequestID(ctx).Warn("Claim check failed", zap.Error(err))
+		validation.Issues = append(validation.Issues, "Claim check could not be completed")
+		return
+	}
+	validation.Claims = claims
+
+	var supported, contradicted int
+	for _, c := range claims {
+		switch c.Verdict {
+		case factcheck.VerdictSupported:
+			supported++
+		case factcheck.VerdictContradicted:
+			contradicted++
+			validation.Issues = append(validation.Issues, fmt.Sprintf("Claim contradicts the specification: %s", c.Claim))
+		}
+	}
+	span.SetAttributes(
+		attribute.Int("claims.total", len(claims)),
+		attribute.Int("claims.supported", supported),
+		attribute.Int("claims.contradicted", contradicted),
+	)
+
+	if len(claims) > 0 && supported == len(claims) {
+		validation.IsValid = true
+		validation.Issues = nil
+		validation.Suggestions = nil
+	}
+}
@@ -9,6 +9,7 @@ import (
 	"github.com/carlisia/mcp-factcheck/embedding"
 	mcpembedding "github.com/carlisia/mcp-factcheck/internal/embedding"
 	"github.com/carlisia/mcp-factcheck/internal/specs"
+	"github.com/carlisia/mcp-factcheck/internal/toolerr"
 	"github.com/carlisia/mcp-factcheck/pkg/logger"
 	"github.com/mark3labs/mcp-go/mcp"
 	"go.uber.org/zap"
@@ -50,17 +51,23 @@ func GetValidateCodeTool() mcp.Tool {
 			},
 			"language": map[string]any{
 				"type":        "string",
-				"description": "Programming language of the code",
+				"description": "Programming language of the code. Go, TypeScript/JavaScript, and Python code is also analyzed statically for SDK usage",
 				"default":     "go",
 			},
+			"document": map[string]any{
+				"type":        "string",
+				"description": "Name or path identifying the source file, so this run can be compared with earlier runs of the same file in the validation history",
+			},
 		},
 		"required": []string{"code"},
 	}
 	schemaBytes, _ := json.Marshal(schema)
-	return mcp.NewToolWithRawSchema(ValidateCodeToolName, "Validate code against MCP specification and protocol requirements. Uses the most current spec version by default. On first use, inform the user that other versions (2025-03-26, 2024-11-05, draft) are available by specifying specVersion parameter.", schemaBytes)
+	tool := mcp.NewToolWithRawSchema(ValidateCodeToolName, "Validate code against MCP specification and protocol requirements. Uses the most current spec version by default. On first use, inform the user that other versions (2025-03-26, 2024-11-05, draft) are available by specifying specVersion parameter.", schemaBytes)
+	mcp.WithOutputSchema[ValidationOutput]()(&tool)
+	return tool
 }
 
-func HandleValidateCode(ctx context.Context, vectorDB *mcpembedding.VectorDB, generator *embedding.Generator, args any) ([]mcp.Content, error) {
+func HandleValidateCode(ctx context.Context, vectorDB *mcpembedding.VectorDB, generator *embedding.Generator, args any) (*mcp.CallToolResult, error) {
 	// Get structured logger with request ID
 	log := logger.WithRequestID(ctx)
 	
@@ -69,7 +76,7 @@ func HandleValidateCode(ctx context.Context, vectorDB *mcpembedding.VectorDB, ge
 		log.Error("Invalid arguments type for validate_code", 
 			zap.String("expected", "map[string]any"),
 			zap.String("actual", fmt.Sprintf("%T", args)))
-		return nil, fmt.Errorf("arguments must be a map")
+		return nil, toolerr.InvalidArgs("arguments must be a map")
 	}
 	
 	code, ok := params["code"].(string)
@@ -78,7 +85,7 @@ func HandleValidateCode(ctx context.Context, vectorDB *mcpembedding.VectorDB, ge
 			zap.String("expected", "string"),
 			zap.String("actual", fmt.Sprintf("%T", params["code"])),
 			zap.Any("value", params["code"]))
-		return nil, fmt.Errorf("code must be a string")
+		return nil, toolerr.InvalidArgs("code must be a string")
 	}
 
 	specVersion, ok := params["specVersion"].(string)
@@ -87,42 +94,67 @@ func HandleValidateCode(ctx context.Context, vectorDB *mcpembedding.VectorDB, ge
 		log.Debug("Using default spec version for code validation", zap.String("version", specVersion))
 	}
 
-	language, ok := params["language"].(string)
-	if !ok {
+	language, _ := params["language"].(string)
+	if language == "" {
 		language = "go"
 		log.Debug("Using default language for code validation", zap.String("language", language))
 	}
 
-	if !specs.IsValidSpecVersion(specVersion) {
-		log.Error("Invalid spec version for code validation", 
-			zap.String("version", specVersion),
-			zap.Strings("valid_versions", specs.ValidSpecVersions))
-		return nil, fmt.Errorf("invalid spec version: %s", specVersion)
+	document, _ := params["document"].(string)
+
+	result, err := Validate(ctx, vectorDB, generator, Request{
+		Content:     code,
+		Language:    language,
+		SpecVersion: specVersion,
+		Document:    document,
+	})
+	if err != nil {
+		return nil, err
 	}
 
-	log.Info("Starting code validation", 
-		zap.Int("code_length", len(code)),
-		zap.String("spec_version", specVersion),
-		zap.String("language", language),
-		zap.String("code_preview", getCodePreview(code, 100)))
+	log.Info("Code validation completed successfully", 
+		zap.Int("findings", len(result.Findings())))
+	
+	return validationToolResult(result), nil
+}
+
+// validateCode validates source code in language, returning a result without chunk results
+func validateCode(ctx context.Context, vectorDB *mcpembedding.VectorDB, generator *embedding.Generator, code, language, specVersion string) (*AggregatedValidationResult, error) {
+	log := logger.WithRequestID(ctx)
 
 	// Analyze code to extract MCP-relevant patterns and concepts
 	log.Debug("Analyzing code for MCP patterns", zap.String("language", language))
 	codeAnalysis := analyzeCodeForMCPPatterns(code, language)
+
+	// Languages with a static analyzer are also parsed, for exact checks the
+	// embedding comparison can't make
+	static, parseErr := analyzeStatically(code, language, specVersion)
+	if parseErr != nil {
+		log.Debug("Code could not be analyzed statically, skipping static analysis", zap.Error(parseErr))
+	} else if static != nil {
+		log.Debug("Static analysis completed",
+			zap.Strings("patterns", static.Patterns),
+			zap.Int("finding_count", len(static.Findings)))
+		if description := static.describe(); description != "" {
+			codeAnalysis += "\n" + description
+		}
+	}
 	
 	// Generate embedding for the code analysis
 	log.Debug("Generating embedding for code analysis")
-	codeEmbedding, err := generator.GenerateEmbedding(codeAnalysis)
+	codeEmbedding, err := generator.GenerateEmbedding(ctx, codeAnalysis)
 	if err != nil {
 		log.Error("Failed to generate code embedding", zap.Error(err))
 		return nil, fmt.Errorf("failed to generate code embedding: %w", err)
 	}
 
 	// Search for relevant spec sections
+	settings := ToolSettingsFor(ValidateCodeToolName)
+	topK := settings.TopK
 	log.Debug("Searching for relevant spec sections", 
 		zap.String("spec_version", specVersion),
-		zap.Int("max_results", 8))
-	results, err := vectorDB.Search(specVersion, codeEmbedding, 8)
+		zap.Int("max_results", topK))
+	results, err := searchSpec(ctx, vectorDB, generator, settings, specVersion, codeAnalysis+"\n"+code, codeEmbedding, topK)
 	if err != nil {
 		log.Error("Failed to search specifications", zap.Error(err))
 		return nil, fmt.Errorf("failed to search specifications: %w", err)
@@ -133,27 +165,24 @@ func HandleValidateCode(ctx context.Context, vectorDB *mcpembedding.VectorDB, ge
 		zap.Float64("max_similarity", getMaxSimilarity(results)))
 
 	// Analyze code validation results
-	validationResult := analyzeCodeValidation(code, codeAnalysis, results, specVersion)
-	matches := summarizeCodeMatches(results, 3)
-	
-	// Create optimized response
-	response := FormatValidationResult(validationResult, matches)
-	
-	log.Info("Code validation completed successfully", 
-		zap.Int("response_length", len(response)))
-	
-	return []mcp.Content{mcp.NewTextContent(response)}, nil
+	return &AggregatedValidationResult{
+		Overall:     analyzeCodeValidation(code, codeAnalysis, results, specVersion, static),
+		Matches:     summarizeCodeMatches(results, 3),
+		Summary:     fmt.Sprintf("Validated %s code", language),
+		SpecVersion: specVersion,
+	}, nil
 }
 
-// analyzeCodeValidation determines if code follows MCP patterns
-func analyzeCodeValidation(code, codeAnalysis string, results []embedding.SearchResult, specVersion string) ValidationResult {
+// analyzeCodeValidation determines if code follows MCP patterns. static holds the
+// results of language-specific static analysis, or nil if none ran.
+func analyzeCodeValidation(code, codeAnalysis string, results []embedding.SearchResult, specVersion string, static *staticAnalysis) ValidationResult {
 	if len(results) == 0 {
-		return ValidationResult{
+		return withStaticFindings(ValidationResult{
 			IsValid:     false,
 			Confidence:  0.1,
 			Issues:      []string{"No MCP-related patterns found in code"},
 			SpecVersion: specVersion,
-		}
+		}, static)
 	}
 
 	// Calculate similarity score
@@ -176,20 +205,32 @@ func analyzeCodeValidation(code, codeAnalysis string, results []embedding.Search
 	}
 
 	// Determine validation
-	isValid := avgSimilarity > 0.6 && len(detectedPatterns) > 0
+	settings := ToolSettingsFor(ValidateCodeToolName)
+	isValid := avgSimilarity > settings.SimilarityThreshold && len(detectedPatterns) > 0
 	confidence := avgSimilarity * (float64(len(detectedPatterns)) / 3.0) // Boost confidence with pattern detection
 
 	var issues []string
 	var suggestions []string
 
+	var errors []ValidationError
+	lastLine := strings.Count(strings.TrimRight(code, "\n"), "\n") + 1
+
 	if !isValid {
 		if len(detectedPatterns) == 0 {
 			issues = append(issues, "No MCP patterns detected in code")
 			suggestions = append(suggestions, "Ensure code implements MCP protocol patterns")
+			errors = append(errors, *NewValidationError(IssueTypeMissing, SeverityWarning, "No MCP patterns detected in code").
+				WithLineRange(1, lastLine).
+				AddSuggestion("Ensure code implements MCP protocol patterns"))
 		}
-		if avgSimilarity < 0.5 {
+		if avgSimilarity < settings.LowSimilarityThreshold {
 			issues = append(issues, "Code structure doesn't match MCP specification patterns")
 			suggestions = append(suggestions, "Review MCP specification for proper implementation patterns")
+			finding := NewValidationError(IssueTypeInaccuracy, SeverityCritical, "Code structure doesn't match MCP specification patterns").
+				WithLineRange(1, lastLine).
+				AddSuggestion("Review MCP specification for proper implementation patterns")
+			finding.Confidence = avgSimilarity
+			errors = append(errors, *finding)
 		}
 	}
 
@@ -199,6 +240,7 @@ func analyzeCodeValidation(code, codeAnalysis string, results []embedding.Search
 		Issues:      issues,
 		Suggestions: suggestions,
 		SpecVersion: specVersion,
+		Errors:      errors,
 	}
 
 	// Add detected patterns to suggestions if valid
@@ -206,6 +248,26 @@ func analyzeCodeValidation(code, codeAnalysis string, results []embedding.Search
 		result.Suggestions = append(result.Suggestions, fmt.Sprintf("Detected MCP patterns: %s", strings.Join(detectedPatterns, ", ")))
 	}
 
+	return withStaticFindings(result, static)
+}
+
+// withStaticFindings adds static analysis findings to a result; a critical finding
+// makes the code invalid regardless of similarity
+func withStaticFindings(result ValidationResult, static *staticAnalysis) ValidationResult {
+	if static == nil {
+		return result
+	}
+	for _, finding := range static.Findings {
+		result.Errors = append(result.Errors, finding)
+		issue := finding.Message
+		if finding.LineNumber > 0 {
+			issue = fmt.Sprintf("Line %d: %s", finding.LineNumber, issue)
+		}
+		result.Issues = append(result.Issues, issue)
+		if finding.Severity == SeverityCritical {
+			result.IsValid = false
+		}
+	}
 	return result
 }
 
@@ -244,6 +306,8 @@ func summarizeCodeMatches(results []embedding.SearchResult, maxMatches int) []Va
 			Topic:     topic,
 			Relevance: result.Similarity,
 			Summary:   summary,
+			Section:   result.Chunk.Section,
+			URL:       result.Chunk.URL,
 		})
 	}
 	return matches
@@ -4,11 +4,13 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"maps"
 	"strings"
 
 	"github.com/carlisia/mcp-factcheck/embedding"
 	mcpembedding "github.com/carlisia/mcp-factcheck/internal/embedding"
 	"github.com/carlisia/mcp-factcheck/internal/specs"
+	"github.com/carlisia/mcp-factcheck/internal/toolerr"
 	"github.com/carlisia/mcp-factcheck/pkg/logger"
 	"github.com/carlisia/mcp-factcheck/pkg/telemetry"
 	"github.com/mark3labs/mcp-go/mcp"
@@ -73,6 +75,7 @@ type ValidateContentArgs struct {
 	Content     string `json:"content"`
 	SpecVersion string `json:"spec_version,omitempty"`
 	UseChunking bool   `json:"use_chunking,omitempty"` // Enable chunk-level validation
+	ClaimCheck  bool   `json:"claim_check,omitempty"`  // Verify claims with a chat model (requires the claim_check feature)
 }
 
 func GetValidateContentTool() mcp.Tool {
@@ -85,9 +88,8 @@ func GetValidateContentTool() mcp.Tool {
 			},
 			"contextType": map[string]any{
 				"type":        "string",
-				"description": "Type of content being validated to determine which spec sections are relevant",
-				"enum":        []string{"full-implementation", "client", "server", "transport", "protocol-overview", "tutorial", "documentation", "blog post"},
-				"default":     "full-implementation",
+				"description": "Type of content being validated. Spec sections relevant to it rank higher in the references. Implementation types (full-implementation, client, server, transport) are also checked for MUST requirements of their spec sections the content doesn't address, reported as missing findings. Omit to check accuracy only",
+				"enum":        ContextTypes,
 			},
 			"specVersion": map[string]any{
 				"type":        "string",
@@ -97,12 +99,41 @@ func GetValidateContentTool() mcp.Tool {
 			},
 			"useChunking": map[string]any{
 				"type":        "boolean",
-				"description": "Enable chunk-level validation for long content (default: false)",
+				"description": "Enable chunk-level validation for long content (default: false). Implied by chunkStrategy, chunkSize, and chunkOverlap",
 				"default":     false,
 			},
+			"claimCheck": map[string]any{
+				"type":        "boolean",
+				"description": "Verify individual claims in low-confidence content with a chat model and return per-claim verdicts citing spec text. Requires the claim_check feature on the server (default: false)",
+				"default":     false,
+			},
+			"suggestFix": map[string]any{
+				"type":        "boolean",
+				"description": "Return a corrected version of inaccurate content. It is written by the client's model through MCP sampling when the client supports sampling, and by the server's chat model otherwise. Requires the suggest_rewrite feature on the server (default: false)",
+				"default":     false,
+			},
+			"explain": map[string]any{
+				"type":        "boolean",
+				"description": "Explain each reference: highlight the phrases of the content it shares with the spec section, and quote the section's sentences closest to the content, each marked as supporting or contradicting it (default: false)",
+				"default":     false,
+			},
+			"score": map[string]any{
+				"type":        "boolean",
+				"description": "Rate the content from 0 to 100 with a letter grade (A-F) and a breakdown by category (terminology, protocol flow, transport, security), from chunk confidences and finding severities (default: false)",
+				"default":     false,
+			},
+			"corpus": map[string]any{
+				"type":        "string",
+				"description": "Name of a documentation corpus ingested on the server, such as a team's internal API docs, to check the content against alongside the MCP specification. References from the corpus carry its name as their source",
+			},
+			"document": map[string]any{
+				"type":        "string",
+				"description": "Name, path, or URL identifying the document, so this run can be compared with earlier runs of the same document in the validation history, and the document revalidated after edits with revalidate_changed_sections",
+			},
 		},
 		"required": []string{"content"},
 	}
+	maps.Copy(schema["properties"].(map[string]any), chunkingSchema())
 	schemaBytes, _ := json.Marshal(schema)
 
 	description := `Strictly validate MCP content against the embedded official MCP specification. 
@@ -117,10 +148,12 @@ Returns specific spec violations with section references and correct language fr
 
 Be explicit about limitations: If validation tools show high confidence but you haven't verified specific claims, state that clearly rather than giving blanket approval.`
 
-	return mcp.NewToolWithRawSchema(ValidateContentToolName, description, schemaBytes)
+	tool := mcp.NewToolWithRawSchema(ValidateContentToolName, description, schemaBytes)
+	mcp.WithOutputSchema[ValidationOutput]()(&tool)
+	return tool
 }
 
-func HandleValidateContent(ctx context.Context, vectorDB *mcpembedding.VectorDB, generator *embedding.Generator, args any) ([]mcp.Content, error) {
+func HandleValidateContent(ctx context.Context, vectorDB *mcpembedding.VectorDB, generator *embedding.Generator, args any) (*mcp.CallToolResult, error) {
 	// Get structured logger with request ID
 	log := logger.WithRequestID(ctx)
 	
@@ -129,7 +162,7 @@ func HandleValidateContent(ctx context.Context, vectorDB *mcpembedding.VectorDB,
 		log.Error("Invalid arguments type", 
 			zap.String("expected", "map[string]any"),
 			zap.String("actual", fmt.Sprintf("%T", args)))
-		return nil, fmt.Errorf("arguments must be a map")
+		return nil, toolerr.InvalidArgs("arguments must be a map")
 	}
 
 	log.Debug("Processing validate_content request", 
@@ -141,7 +174,7 @@ func HandleValidateContent(ctx context.Context, vectorDB *mcpembedding.VectorDB,
 			zap.String("expected", "string"),
 			zap.String("actual", fmt.Sprintf("%T", params["content"])),
 			zap.Any("value", params["content"]))
-		return nil, fmt.Errorf("content must be a string")
+		return nil, toolerr.InvalidArgs("content must be a string")
 	}
 
 	specVersion, ok := params["specVersion"].(string)
@@ -150,57 +183,37 @@ func HandleValidateContent(ctx context.Context, vectorDB *mcpembedding.VectorDB,
 		log.Debug("Using default spec version", zap.String("version", specVersion))
 	}
 
-	useChunking, ok := params["useChunking"].(bool)
-	if !ok {
-		useChunking = false
-	}
-
-	if !specs.IsValidSpecVersion(specVersion) {
-		log.Error("Invalid spec version", 
-			zap.String("version", specVersion),
-			zap.Strings("valid_versions", specs.ValidSpecVersions))
-		return nil, fmt.Errorf("invalid spec version: %s", specVersion)
-	}
-
-	// Start parent span with actual content and parameters
-	ctx, requestSpan := telemetry.StartValidationSpan(ctx, content, specVersion, useChunking)
-	defer requestSpan.End()
-
-	// Add structured logging for request details
-	log.Info("Starting content validation", 
-		zap.Int("content_length", len(content)),
-		zap.String("spec_version", specVersion),
-		zap.Bool("use_chunking", useChunking),
-		zap.String("content_preview", getContentPreview(content, 100)))
-
-	// Check if we should use chunking based on content length or explicit request
-	shouldChunk := useChunking || len(content) > 500 // Auto-chunk for moderately long content
-
-	var result []mcp.Content
-	var err error
-
-	if shouldChunk {
-		requestSpan.SetAttributes(attribute.String("validation.strategy", "chunked"))
-		result, err = HandleChunkedValidation(ctx, vectorDB, generator, content, specVersion)
-	} else {
-		requestSpan.SetAttributes(attribute.String("validation.strategy", "single"))
-		result, err = handleSingleValidation(ctx, vectorDB, generator, content, specVersion)
+	useChunking, _ := params["useChunking"].(bool)
+	corpus, _ := params["corpus"].(string)
+	contextType, _ := params["contextType"].(string)
+	document, _ := params["document"].(string)
+	claimCheck, _ := params["claimCheck"].(bool)
+	suggestFix, _ := params["suggestFix"].(bool)
+	score, _ := params["score"].(bool)
+	explain, _ := params["explain"].(bool)
+	chunking, err := chunkOptionsArgs(params)
+	if err != nil {
+		return nil, err
 	}
 
-	// Add result attributes to parent span
+	result, err := Validate(ctx, vectorDB, generator, Request{
+		Content:     content,
+		SpecVersion: specVersion,
+		ContextType: contextType,
+		Corpus:      corpus,
+		Document:    document,
+		Chunked:     useChunking,
+		Chunking:    chunking,
+		ClaimCheck:  claimCheck,
+		SuggestFix:  suggestFix,
+		Score:       score,
+		Explain:     explain,
+	})
 	if err != nil {
-		requestSpan.SetAttributes(attribute.String("validation.error", err.Error()))
-		requestSpan.RecordError(err)
-	} else {
-		resultJSON, _ := json.Marshal(result)
-		requestSpan.SetAttributes(
-			attribute.String("output.value", string(resultJSON)),
-			attribute.String("output.mime_type", "application/json"),
-			attribute.Bool("validation.success", true),
-		)
+		return nil, err
 	}
 
-	return result, err
+	return validationToolResult(result), nil
 }
 
 // analyzeContentValidation determines if content is valid and provides insights
@@ -222,7 +235,8 @@ func analyzeContentValidation(content string, results []embedding.SearchResult,
 	avgSimilarity := totalSimilarity / float64(len(results))
 
 	// Determine validation based on similarity thresholds
-	isValid := avgSimilarity > 0.7
+	settings := ToolSettingsFor(ValidateContentToolName)
+	isValid := avgSimilarity > settings.SimilarityThreshold
 	confidence := avgSimilarity
 
 	var issues []string
@@ -230,7 +244,7 @@ func analyzeContentValidation(content string, results []embedding.SearchResult,
 
 	if !isValid {
 		issues = append(issues, "Content may not align with MCP specification")
-		if avgSimilarity < 0.5 {
+		if avgSimilarity < settings.LowSimilarityThreshold {
 			issues = append(issues, "Low similarity to MCP patterns detected")
 		}
 		suggestions = append(suggestions, "Review content against MCP specification")
@@ -281,32 +295,38 @@ func summarizeContentMatches(results []embedding.SearchResult, maxMatches int) [
 			Topic:     topic,
 			Relevance: result.Similarity,
 			Summary:   summary,
+			Source:    ResultSource(result),
+			Section:   result.Chunk.Section,
+			URL:       result.Chunk.URL,
 		})
 	}
 	return matches
 }
 
-func handleSingleValidation(ctx context.Context, vectorDB *mcpembedding.VectorDB, generator *embedding.Generator, content, specVersion string) ([]mcp.Content, error) {
+// validateWhole validates content as a single piece, returning a result without chunk results
+func validateWhole(ctx context.Context, vectorDB *mcpembedding.VectorDB, generator *embedding.Generator, content, specVersion string) (*AggregatedValidationResult, error) {
 	// Start embedding generation span using telemetry builder
 	embeddingCtx, embeddingSpan := telemetry.StartEmbeddingSpan(ctx, content)
 
 	// Generate embedding for content
-	contentEmbedding, err := generator.GenerateEmbedding(content)
+	contentEmbedding, err := generator.GenerateEmbedding(embeddingCtx, content)
 	embeddingSpan.End()
 	if err != nil {
 		embeddingSpan.SetAttributes(attribute.String("embedding.error", err.Error()))
-		embeddingSpan.RecordError(err)
+		telemetry.RecordError(embeddingSpan, err)
 		return nil, fmt.Errorf("failed to generate content embedding: %w", err)
 	}
 
 	// Start vector search span using telemetry builder
-	searchCtx, searchSpan := telemetry.StartRetrievalSpan(embeddingCtx, specVersion, 5)
+	settings := ToolSettingsFor(ValidateContentToolName)
+	topK := settings.TopK
+	searchCtx, searchSpan := telemetry.StartRetrievalSpan(embeddingCtx, specVersion, topK)
 
 	// Search for relevant spec sections
-	results, err := vectorDB.Search(specVersion, contentEmbedding, 5)
+	results, err := searchSpec(searchCtx, vectorDB, generator, settings, specVersion, content, contentEmbedding, topK)
 	if err != nil {
 		searchSpan.SetAttributes(attribute.String("search.error", err.Error()))
-		searchSpan.RecordError(err)
+		telemetry.RecordError(searchSpan, err)
 		searchSpan.End()
 		return nil, fmt.Errorf("failed to search specifications: %w", err)
 	}
@@ -315,12 +335,18 @@ func handleSingleValidation(ctx context.Context, vectorDB *mcpembedding.VectorDB
 	var retrievalDocs []telemetry.RetrievalDocument
 	var totalSimilarity float64
 	for i, result := range results {
+		source := "mcp_specification"
+		if corpus := ResultSource(result); corpus != "" {
+			source = "corpus:" + corpus
+		}
 		retrievalDocs = append(retrievalDocs, telemetry.RetrievalDocument{
 			ID:      fmt.Sprintf("mcp_doc_%d", i),
 			Score:   result.Similarity,
 			Content: result.Chunk.Content,
 			Metadata: map[string]interface{}{
-				"source":     "mcp_specification",
+				"source":     source,
+				"section":    result.Chunk.Section,
+				"url":        result.Chunk.URL,
 				"version":    specVersion,
 				"chunk_type": "specification_section",
 			},
@@ -333,7 +359,7 @@ func handleSingleValidation(ctx context.Context, vectorDB *mcpembedding.VectorDB
 	// Add retrieval results to span using telemetry builder
 	searchSpan.SetAttributes(
 		attribute.String("retrieval.query", content[:min(200, len(content))]),
-		attribute.Int("retrieval.top_k", 5),
+		attribute.Int("retrieval.top_k", topK),
 		attribute.Float64("retrieval.similarity.avg", avgSimilarity),
 		attribute.Float64("retrieval.similarity.max", getMaxSimilarity(results)),
 		attribute.Float64("retrieval.similarity.min", getMinSimilarity(results)),
@@ -349,7 +375,24 @@ func handleSingleValidation(ctx context.Context, vectorDB *mcpembedding.VectorDB
 
 	// Analyze validation results
 	validationResult := analyzeContentValidation(content, results, specVersion)
+	validationResult.ContextType = contextTypeFrom(ctx)
+	applyClaimCheck(searchCtx, content, results, &validationResult)
 	matches := summarizeContentMatches(results, 3)
+	applyExplanations(ctx, content, 1, results, matches)
+	alignment := newFinding(content, validationResult, matches, ToolSettingsFor(ValidateContentToolName))
+	if alignment != nil {
+		alignment.WithLineRange(1, strings.Count(strings.TrimRight(content, "\n"), "\n")+1)
+		validationResult.Errors = []ValidationError{*alignment}
+	}
+	applyCoverage(searchCtx, vectorDB, specVersion, content, [][]float64{contentEmbedding}, &validationResult)
+	applyVersionChecks(content, specVersion, &validationResult)
+	applyTerminology(content, specVersion, &validationResult)
+	applyRules(ctx, content, specVersion, &validationResult)
+	applyRewrite(searchCtx, content, results, &validationResult)
+	if alignment != nil {
+		// The rewrite is of all of content, like the alignment finding, which is first
+		validationResult.Errors[0].SuggestedRewrite = validationResult.CorrectedVersion
+	}
 
 	analysisSpan.SetAttributes(
 		attribute.Bool("validation.is_valid", validationResult.IsValid),
@@ -358,8 +401,10 @@ func handleSingleValidation(ctx context.Context, vectorDB *mcpembedding.VectorDB
 	)
 	analysisSpan.End()
 
-	// Create optimized response
-	response := FormatValidationResult(validationResult, matches)
-
-	return []mcp.Content{mcp.NewTextContent(response)}, nil
+	return &AggregatedValidationResult{
+		Overall:     validationResult,
+		Matches:     matches,
+		Summary:     "Validated the content as a whole",
+		SpecVersion: specVersion,
+	}, nil
 }
@@ -0,0 +1,136 @@
+package validator
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/carlisia/mcp-factcheck/embedding"
+	"github.com/carlisia/mcp-factcheck/internal/requirements"
+)
+
+// Context types of validated content, the values of validate_content's contextType
+const (
+	ContextFullImplementation = "full-implementation"
+	ContextClient             = "client"
+	ContextServer             = "server"
+	ContextTransport          = "transport"
+	ContextProtocolOverview   = "protocol-overview"
+	ContextTutorial           = "tutorial"
+	ContextDocumentation      = "documentation"
+	ContextBlogPost           = "blog post"
+)
+
+// ContextTypes lists the valid context types
+var ContextTypes = []string{
+	ContextFullImplementation,
+	ContextClient,
+	ContextServer,
+	ContextTransport,
+	ContextProtocolOverview,
+	ContextTutorial,
+	ContextDocumentation,
+	ContextBlogPost,
+}
+
+// OutOfScopeWeight scales the ranking score of spec sections outside the pages a
+// context type is about, so in-scope sections win close calls without hiding a
+// clearly better match elsewhere
+const OutOfScopeWeight = 0.85
+
+// contextScope is the part of the spec a context type is about. Pages are spec page
+// paths such as "basic/transports", and cover their subpages.
+type contextScope struct {
+	pages        []string // Pages retrieval favors; nil favors none
+	requirements []string // Pages whose MUST requirements content of this type is expected to address
+}
+
+// contextScopes maps each context type to its scope. Narrative content is checked
+// against the whole spec and isn't expected to cover any particular requirements.
+var contextScopes = map[string]contextScope{
+	ContextFullImplementation: {
+		requirements: []string{"basic", "client", "server"},
+	},
+	ContextClient: {
+		pages:        []string{"architecture", "basic", "client"},
+		requirements: []string{"basic/lifecycle", "basic/transports", "client"},
+	},
+	ContextServer: {
+		pages:        []string{"architecture", "basic", "server"},
+		requirements: []string{"basic/lifecycle", "basic/transports", "server"},
+	},
+	ContextTransport: {
+		pages:        []string{"basic/transports", "basic/authorization", "basic"},
+		requirements: []string{"basic/transports"},
+	},
+	ContextProtocolOverview: {
+		pages: []string{requirements.OverviewPage, "architecture", "basic", "basic/lifecycle"},
+	},
+	ContextTutorial:      {},
+	ContextDocumentation: {},
+	ContextBlogPost:      {},
+}
+
+// ParseContextType checks that name is a valid context type
+func ParseContextType(name string) (string, error) {
+	if !slices.Contains(ContextTypes, name) {
+		return "", fmt.Errorf("invalid contextType %q (valid: %s)", name, strings.Join(ContextTypes, ", "))
+	}
+	return name, nil
+}
+
+type contextTypeKey struct{}
+
+// WithContextType makes validations run with ctx favor the spec sections relevant to
+// content of contextType
+func WithContextType(ctx context.Context, contextType string) context.Context {
+	return context.WithValue(ctx, contextTypeKey{}, contextType)
+}
+
+func contextTypeFrom(ctx context.Context) string {
+	contextType, _ := ctx.Value(contextTypeKey{}).(string)
+	return contextType
+}
+
+// specPage returns the spec page a search result came from, such as
+// "basic/transports", or "" if it is unknown. Chunks embedded before they carried a
+// file path or URL, and corpus chunks, have no known page.
+func specPage(result embedding.SearchResult, specVersion string) string {
+	if ResultSource(result) != "" {
+		return ""
+	}
+	return requirements.Page(result.Chunk.FilePath, result.Chunk.URL, specVersion)
+}
+
+// scopeCandidates returns how many results to retrieve so that weighting by
+// contextType can still fill topK
+func scopeCandidates(contextType string, topK int) int {
+	if len(contextScopes[contextType].pages) == 0 {
+		return topK
+	}
+	return 2 * topK
+}
+
+// weighByScope scales down the scores of spec results outside contextType's pages,
+// reorders them, and returns the top K. Results whose page is unknown keep their score.
+func weighByScope(results []embedding.SearchResult, contextType, specVersion string, topK int) []embedding.SearchResult {
+	pages := contextScopes[contextType].pages
+	if len(pages) == 0 {
+		return results[:min(topK, len(results))]
+	}
+	for i := range results {
+		if page := specPage(results[i], specVersion); page != "" && !requirements.InPages(page, pages) {
+			results[i].Score *= OutOfScopeWeight
+		}
+	}
+	sort.SliceStable(results, func(i, j int) bool {
+		return results[i].Score > results[j].Score
+	})
+	results = results[:min(topK, len(results))]
+	for i := range results {
+		results[i].Rank = i + 1
+	}
+	return results
+}
@@ -0,0 +1,75 @@
+package validator
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"sort"
+
+	"github.com/carlisia/mcp-factcheck/embedding"
+	mcpembedding "github.com/carlisia/mcp-factcheck/internal/embedding"
+)
+
+type corpusKey struct{}
+
+// WithCorpus makes validations run with ctx also retrieve from a documentation corpus,
+// so content is checked against a team's own docs alongside the MCP spec
+func WithCorpus(ctx context.Context, corpus string) context.Context {
+	return context.WithValue(ctx, corpusKey{}, corpus)
+}
+
+func corpusFrom(ctx context.Context) string {
+	corpus, _ := ctx.Value(corpusKey{}).(string)
+	return corpus
+}
+
+// ResultSource returns the corpus a search result came from, or "" for the MCP spec
+func ResultSource(result embedding.SearchResult) string {
+	corpus, _ := result.Chunk.Metadata[mcpembedding.CorpusMetadataKey].(string)
+	return corpus
+}
+
+// ResultCitation returns where in the docs a search result came from, as its section and
+// URL, or "" for chunks embedded without that metadata
+func ResultCitation(result embedding.SearchResult) string {
+	switch {
+	case result.Chunk.Section != "" && result.Chunk.URL != "":
+		return result.Chunk.Section + " (" + result.Chunk.URL + ")"
+	case result.Chunk.URL != "":
+		return result.Chunk.URL
+	default:
+		return result.Chunk.Section
+	}
+}
+
+// SearchSpecAndCorpus retrieves the topK sections most relevant to query from specVersion
+// and, if corpus is set, from that corpus, merged by ranking score
+func SearchSpecAndCorpus(ctx context.Context, vectorDB *mcpembedding.VectorDB, specVersion, corpus, query string, queryEmbedding []float64, topK int, keywordWeight float64) ([]embedding.SearchResult, error) {
+	results, err := vectorDB.HybridSearch(ctx, specVersion, query, queryEmbedding, topK, keywordWeight)
+	if err != nil || corpus == "" {
+		return results, err
+	}
+	corpusResults, err := vectorDB.SearchCorpus(ctx, corpus, query, queryEmbedding, topK, keywordWeight)
+	if err != nil {
+		return nil, fmt.Errorf("failed to search corpus %s: %w", corpus, err)
+	}
+	for i := range corpusResults {
+		// Copy the metadata rather than writing to the store's cached chunk
+		metadata := maps.Clone(corpusResults[i].Chunk.Metadata)
+		if metadata == nil {
+			metadata = map[string]any{}
+		}
+		metadata[mcpembedding.CorpusMetadataKey] = corpus
+		corpusResults[i].Chunk.Metadata = metadata
+	}
+
+	results = append(results, corpusResults...)
+	sort.SliceStable(results, func(i, j int) bool {
+		return results[i].Score > results[j].Score
+	})
+	results = results[:min(topK, len(results))]
+	for i := range results {
+		results[i].Rank = i + 1
+	}
+	return results, nil
+}
@@ -0,0 +1,184 @@
+package validator
+
+import (
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/carlisia/mcp-factcheck/embedding"
+	mcpembedding "github.com/carlisia/mcp-factcheck/internal/embedding"
+	"github.com/carlisia/mcp-factcheck/internal/requirements"
+	"github.com/carlisia/mcp-factcheck/pkg/logger"
+	"go.uber.org/zap"
+)
+
+// Coverage summarizes how many of the spec requirements content of its context type
+// is expected to address it does address
+type Coverage struct {
+	ContextType  string `json:"context_type"`
+	Requirements int    `json:"requirements"` // MUST statements on the context type's pages
+	Addressed    int    `json:"addressed"`
+}
+
+// requirement is a mandatory spec statement with the embedding of the passage stating it
+type requirement struct {
+	requirements.Requirement
+	embedding []float64 // nil when the passage isn't among the embedded chunks
+}
+
+// mandatoryRequirements returns the MUST-level requirements of a spec version on
+// pages, in spec order, with their passages' embeddings. Prohibitions are left out,
+// since content can't be expected to address them. Requirements on unknown pages
+// never match, so embeddings made before chunks carried their file path yield none.
+func mandatoryRequirements(vectorDB *mcpembedding.VectorDB, spec *embedding.SpecEmbedding, pages []string) ([]requirement, error) {
+	catalog, err := vectorDB.Requirements(spec.Version)
+	if err != nil {
+		return nil, err
+	}
+	embeddings := map[string][]float64{}
+	for _, chunk := range spec.Chunks {
+		embeddings[fmt.Sprintf("%x", sha256.Sum256([]byte(chunk.Content)))] = chunk.Embedding
+	}
+
+	var found []requirement
+	for _, r := range catalog.Filter([]requirements.Level{requirements.LevelMust}, pages) {
+		if !r.Prohibition() {
+			found = append(found, requirement{Requirement: r, embedding: embeddings[r.ContentHash]})
+		}
+	}
+	return found, nil
+}
+
+// addressed reports whether content addresses a requirement, by naming one of its
+// code terms or by being at least threshold similar to the passage stating it, and
+// returns the best similarity of the content's embeddings to that passage
+func (r requirement) addressed(lowerContent string, embeddings [][]float64, threshold float64) (bool, float64) {
+	best := 0.0
+	for _, e := range embeddings {
+		best = max(best, cosine(e, r.embedding))
+	}
+	if best >= threshold {
+		return true, best
+	}
+	for _, term := range r.Terms {
+		if strings.Contains(lowerContent, term) {
+			return true, best
+		}
+	}
+	return false, best
+}
+
+// checkCoverage checks content against the requirements of the context type set with
+// WithContextType and returns a missing finding for each spec page with requirements
+// it doesn't address. embeddings are the content's, one per validated piece. It
+// returns nil when the context type expects no requirements or none are known.
+func checkCoverage(ctx context.Context, vectorDB *mcpembedding.VectorDB, specVersion, content string, embeddings [][]float64) (*Coverage, []ValidationError, error) {
+	contextType := contextTypeFrom(ctx)
+	pages := contextScopes[contextType].requirements
+	if len(pages) == 0 || len(embeddings) == 0 {
+		return nil, nil, nil
+	}
+	spec, err := vectorDB.Spec(specVersion)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to load spec requirements: %w", err)
+	}
+	if err := spec.CheckModel(embedding.CurrentModel()); err != nil {
+		return nil, nil, err
+	}
+	required, err := mandatoryRequirements(vectorDB, spec, pages)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to load spec requirements: %w", err)
+	}
+	if len(required) == 0 {
+		return nil, nil, nil
+	}
+
+	threshold := ToolSettingsFor(ValidateContentToolName).CoverageThreshold
+	lowerContent := strings.ToLower(content)
+	coverage := &Coverage{ContextType: contextType, Requirements: len(required)}
+	var order []string
+	total := map[string]int{}
+	missing := map[string][]requirement{}
+	similarity := map[string]float64{}
+	for _, r := range required {
+		if total[r.Page] == 0 {
+			order = append(order, r.Page)
+		}
+		total[r.Page]++
+		ok, best := r.addressed(lowerContent, embeddings, threshold)
+		if ok {
+			coverage.Addressed++
+			continue
+		}
+		if len(missing[r.Page]) == 0 {
+			similarity[r.Page] = best
+		}
+		missing[r.Page] = append(missing[r.Page], r)
+	}
+
+	var findings []ValidationError
+	for _, page := range order {
+		gaps := missing[page]
+		if len(gaps) == 0 {
+			continue
+		}
+		first := gaps[0]
+		section := first.Section
+		if section == "" {
+			section = page
+		}
+		finding := NewMissingRequirementError(first.Statement, section)
+		finding.Message = fmt.Sprintf("Content does not address %d of %d requirements on the %s page", len(gaps), total[page], page)
+		url := first.URL
+		if url == "" {
+			url = requirements.PageURL(specVersion, page)
+		}
+		finding.WithSpecURL(url)
+		finding.Confidence = similarity[page]
+		for _, gap := range gaps[1:min(len(gaps), 3)] {
+			finding.AddSuggestion("Also required: " + gap.Statement)
+		}
+		findings = append(findings, *finding)
+	}
+	return coverage, findings, nil
+}
+
+// applyCoverage adds the findings of the coverage check to result. Coverage is
+// checked on top of accuracy, so a failed check is logged rather than failing the
+// validation.
+func applyCoverage(ctx context.Context, vectorDB *mcpembedding.VectorDB, specVersion, content string, embeddings [][]float64, result *ValidationResult) {
+	coverage, findings, err := checkCoverage(ctx, vectorDB, specVersion, content, embeddings)
+	if err != nil {
+		if ctx.Err() == nil {
+			logger.WithRequestID(ctx).Warn("Coverage check failed", zap.Error(err))
+		}
+		return
+	}
+	if coverage == nil {
+		return
+	}
+	result.Coverage = coverage
+	result.Errors = append(result.Errors, findings...)
+	if len(findings) > 0 {
+		result.Issues = append(result.Issues, fmt.Sprintf("Content addresses %d of %d MUST requirements for %s content", coverage.Addressed, coverage.Requirements, coverage.ContextType))
+		result.Suggestions = append(result.Suggestions, "Cover the spec requirements listed in the missing findings, or set contextType to the kind of content this is")
+	}
+}
+
+func cosine(a, b []float64) float64 {
+	if len(a) != len(b) || len(a) == 0 {
+		return 0
+	}
+	var dot, normA, normB float64
+	for i := range a {
+		dot += a[i] * b[i]
+		normA += a[i] * a[i]
+		normB += b[i] * b[i]
+	}
+	if normA == 0 || normB == 0 {
+		return 0
+	}
+	return dot / (math.Sqrt(normA) * math.Sqrt(normB))
+}
@@ -0,0 +1,402 @@
+package validator
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"strings"
+
+	"github.com/carlisia/mcp-factcheck/embedding"
+	"github.com/carlisia/mcp-factcheck/internal/cost"
+	mcpembedding "github.com/carlisia/mcp-factcheck/internal/embedding"
+	"github.com/carlisia/mcp-factcheck/internal/limits"
+	"github.com/carlisia/mcp-factcheck/internal/specs"
+	"github.com/carlisia/mcp-factcheck/internal/toolerr"
+	"github.com/carlisia/mcp-factcheck/pkg/logger"
+	"github.com/carlisia/mcp-factcheck/pkg/telemetry"
+	"github.com/mark3labs/mcp-go/mcp"
+	"go.opentelemetry.io/otel/attribute"
+	"go.uber.org/zap"
+)
+
+const ValidateDiffToolName = "validate_diff"
+
+// maxDiffCells caps the size of the line alignment table; documents whose changed
+// middle is larger are treated as changed throughout it
+const maxDiffCells = 4_000_000
+
+// DiffResult is the validation of the lines that changed between two versions of a
+// document
+type DiffResult struct {
+	SpecVersion  string                  `json:"spec_version"`
+	LinesAdded   int                     `json:"lines_added"`
+	LinesRemoved int                     `json:"lines_removed"`
+	IsValid      bool                    `json:"is_valid"`               // No findings in the new version's changed lines
+	Introduced   []ValidationError       `json:"introduced,omitempty"`   // Findings in changed lines the old version didn't have
+	Persisting   []ValidationError       `json:"persisting,omitempty"`   // Findings in changed lines the old version also had
+	Resolved     []ValidationError       `json:"resolved,omitempty"`     // Findings in the old version's changed lines that are gone
+	ChunkResults []ChunkValidationResult `json:"chunk_results"`          // The new version's chunks touching changed lines
+	PriorSource  string                  `json:"prior_source,omitempty"` // "session" when the old version's findings came from an earlier validation in the session
+	Summary      string                  `json:"summary"`
+}
+
+func GetValidateDiffTool() mcp.Tool {
+	schema := map[string]any{
+		"type": "object",
+		"properties": map[string]any{
+			"oldContent": map[string]any{
+				"type":        "string",
+				"description": "Previous version of the document",
+			},
+			"newContent": map[string]any{
+				"type":        "string",
+				"description": "Current version of the document",
+			},
+			"specVersion": map[string]any{
+				"type":        "string",
+				"description": "MCP specification version to validate against",
+				"enum":        specs.ValidSpecVersions,
+				"default":     specs.DefaultSpecVersion,
+			},
+			"contextType": map[string]any{
+				"type":        "string",
+				"description": "Type of content being validated, as for validate_content. Spec sections relevant to it rank higher in the references",
+				"enum":        ContextTypes,
+			},
+			"corpus": map[string]any{
+				"type":        "string",
+				"description": "Name of a documentation corpus ingested on the server to check against alongside the MCP specification",
+			},
+			"document": map[string]any{
+				"type":        "string",
+				"description": "Document name given to validate_content earlier in this session. When that run validated oldContent, its findings are reused instead of checking the old version's changed lines again",
+			},
+		},
+		"required": []string{"oldContent", "newContent"},
+	}
+	maps.Copy(schema["properties"].(map[string]any), chunkingSchema())
+	schemaBytes, _ := json.Marshal(schema)
+
+	description := `Validate only what changed between two versions of a document, and report which earlier issues the edit resolved.
+
+USE THIS INSTEAD OF validate_content WHEN iterating on a long document such as a blog post: pass the previous and current versions. Lines are diffed, only the sections with added or changed lines are checked, and findings are reported as introduced, persisting, or resolved compared with the same sections of the old version.`
+
+	return mcp.NewToolWithRawSchema(ValidateDiffToolName, description, schemaBytes)
+}
+
+// HandleValidateDiff validates the changed lines between two versions of a document
+func HandleValidateDiff(ctx context.Context, vectorDB *mcpembedding.VectorDB, generator *embedding.Generator, args any) ([]mcp.Content, error) {
+	params, ok := args.(map[string]any)
+	if !ok {
+		return nil, toolerr.InvalidArgs("arguments must be a map")
+	}
+	oldContent, okOld := params["oldContent"].(string)
+	newContent, okNew := params["newContent"].(string)
+	if !okOld || !okNew {
+		return nil, toolerr.InvalidArgs("oldContent and newContent must be strings")
+	}
+	specVersion, _ := params["specVersion"].(string)
+	contextType, _ := params["contextType"].(string)
+	corpus, _ := params["corpus"].(string)
+	document, _ := params["document"].(string)
+	chunking, err := chunkOptionsArgs(params)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := ValidateDiff(ctx, vectorDB, generator, oldContent, Request{
+		Content:     newContent,
+		SpecVersion: specVersion,
+		ContextType: contextType,
+		Corpus:      corpus,
+		Document:    document,
+		Chunking:    chunking,
+	})
+	if err != nil {
+		return nil, err
+	}
+	jsonBytes, _ := json.MarshalIndent(result, "", "  ")
+	return []mcp.Content{mcp.NewTextContent(string(jsonBytes))}, nil
+}
+
+// ValidateDiff validates the sections of req.Content with lines added or changed since
+// oldContent, and compares their findings with those of the old version's changed
+// sections. Findings match across versions by type and message, since the flagged
+// text and the spec sections it is closest to change with the edit.
+func ValidateDiff(ctx context.Context, vectorDB *mcpembedding.VectorDB, generator *embedding.Generator, oldContent string, req Request) (*DiffResult, error) {
+	if req.SpecVersion == "" {
+		req.SpecVersion = specs.DefaultSpecVersion
+	}
+	ctx, err := req.context(ctx, vectorDB, generator)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, span := telemetry.NewSpanBuilder().
+		WithKind("CHAIN").
+		WithInput(req.Content, "text/plain").
+		WithCustom(
+			attribute.Int("content.old_length", len(oldContent)),
+			attribute.Int("content.new_length", len(req.Content)),
+		).
+		Start(ctx, "content.diff_validation")
+	defer span.End()
+
+	removed, added := changedLines(oldContent, req.Content)
+	result := &DiffResult{
+		SpecVersion:  req.SpecVersion,
+		LinesAdded:   len(added),
+		LinesRemoved: len(removed),
+		ChunkResults: []ChunkValidationResult{},
+	}
+	span.SetAttributes(
+		attribute.Int("diff.lines_added", len(added)),
+		attribute.Int("diff.lines_removed", len(removed)),
+	)
+
+	opts := chunkingFrom(ctx)
+	newChunks, err := ChunkContentWith(req.Content, opts)
+	if err != nil {
+		return nil, err
+	}
+	if err := limits.CheckChunks(newChunks.TotalChunks); err != nil {
+		telemetry.RecordError(span, err)
+		return nil, err
+	}
+	changedNew := touching(newChunks.Chunks, added)
+
+	// The old version's findings come from the session when it was validated there
+	priorResults, fromSession := sessionResults(ctx, req.Document, oldContent)
+	if fromSession {
+		result.PriorSource = "session"
+	}
+	var changedOld []ContentChunk
+	if !fromSession && len(removed) > 0 {
+		oldChunks, err := ChunkContentWith(oldContent, opts)
+		if err != nil {
+			return nil, err
+		}
+		changedOld = touching(oldChunks.Chunks, removed)
+	}
+
+	if len(changedNew)+len(changedOld) > 0 {
+		if err := cost.Allow(); err != nil {
+			telemetry.RecordError(span, err)
+			return nil, err
+		}
+	}
+	logger.WithRequestID(ctx).Info("Validating document diff",
+		zap.Int("lines_added", len(added)),
+		zap.Int("lines_removed", len(removed)),
+		zap.Int("new_chunks", len(changedNew)),
+		zap.Int("old_chunks", len(changedOld)),
+		zap.Bool("prior_from_session", fromSession))
+
+	validated, err := validateChunks(ctx, vectorDB, generator, append(changedNew, changedOld...), req.SpecVersion)
+	if err != nil {
+		telemetry.RecordError(span, err)
+		return nil, err
+	}
+	result.ChunkResults = append(result.ChunkResults, validated[:len(changedNew)]...)
+	if !fromSession {
+		priorResults = validated[len(changedNew):]
+	}
+
+	current := chunkFindings(result.ChunkResults, nil)
+	current = append(current, lineFindings(ctx, req.Content, req.SpecVersion, added)...)
+	prior := chunkFindings(priorResults, removed)
+	prior = append(prior, lineFindings(ctx, oldContent, req.SpecVersion, removed)...)
+
+	result.Introduced, result.Persisting, result.Resolved = compareFindings(prior, current)
+	result.IsValid = len(current) == 0
+	result.Summary = fmt.Sprintf("Validated %d changed of %d content chunks: %d findings introduced, %d persisting, %d resolved",
+		len(changedNew), newChunks.TotalChunks, len(result.Introduced), len(result.Persisting), len(result.Resolved))
+	span.SetAttributes(
+		attribute.Int("diff.introduced", len(result.Introduced)),
+		attribute.Int("diff.resolved", len(result.Resolved)),
+		attribute.Bool("validation.success", true),
+	)
+	return result, nil
+}
+
+// sessionResults returns the chunk results of the session's last validation of
+// document, when it validated content
+func sessionResults(ctx context.Context, document, content string) ([]ChunkValidationResult, bool) {
+	memory := documentMemoryFrom(ctx)
+	if memory == nil || document == "" {
+		return nil, false
+	}
+	state, ok := memory.Recall(document)
+	if !ok || state.Request.Content != content || state.Result.ChunkResults == nil {
+		return nil, false
+	}
+	return state.Result.ChunkResults, true
+}
+
+// touching returns the chunks spanning any of lines. Chunks that couldn't be located
+// in the content are included when anything changed.
+func touching(chunks []ContentChunk, lines map[int]bool) []ContentChunk {
+	var selected []ContentChunk
+	if len(lines) == 0 {
+		return selected
+	}
+	for _, chunk := range chunks {
+		if chunk.StartLine == 0 || spans(chunk, lines) {
+			selected = append(selected, chunk)
+		}
+	}
+	return selected
+}
+
+func spans(chunk ContentChunk, lines map[int]bool) bool {
+	end := max(chunk.EndLine, chunk.StartLine)
+	for line := chunk.StartLine; line <= end; line++ {
+		if lines[line] {
+			return true
+		}
+	}
+	return false
+}
+
+// chunkFindings collects the findings of validated chunks, keeping only chunks
+// spanning lines unless lines is nil
+func chunkFindings(results []ChunkValidationResult, lines map[int]bool) []ValidationError {
+	var findings []ValidationError
+	if lines != nil && len(lines) == 0 {
+		return findings
+	}
+	for _, cr := range results {
+		if cr.Error != "" || (lines != nil && cr.Chunk.StartLine > 0 && !spans(cr.Chunk, lines)) {
+			continue
+		}
+		findings = append(findings, cr.Validation.Errors...)
+	}
+	return findings
+}
+
+// lineFindings runs the line-anchored version, terminology, and custom rule checks on
+// content and keeps the findings on lines
+func lineFindings(ctx context.Context, content, specVersion string, lines map[int]bool) []ValidationError {
+	if len(lines) == 0 {
+		return nil
+	}
+	var checks ValidationResult
+	applyVersionChecks(content, specVersion, &checks)
+	applyTerminology(content, specVersion, &checks)
+	applyRules(ctx, content, specVersion, &checks)
+	var findings []ValidationError
+	for _, finding := range checks.Errors {
+		if lines[finding.LineNumber] {
+			findings = append(findings, finding)
+		}
+	}
+	return findings
+}
+
+// diffFindingKey identifies a finding across versions of a document
+type diffFindingKey struct {
+	issueType, message string
+}
+
+// compareFindings splits current into findings prior didn't have and findings it
+// had, and returns the prior findings that current no longer has
+func compareFindings(prior, current []ValidationError) (introduced, persisting, resolved []ValidationError) {
+	key := func(f ValidationError) diffFindingKey {
+		return diffFindingKey{f.Type, f.Message}
+	}
+	remaining := map[diffFindingKey]int{}
+	for _, f := range prior {
+		remaining[key(f)]++
+	}
+	for _, f := range current {
+		if k := key(f); remaining[k] > 0 {
+			remaining[k]--
+			persisting = append(persisting, f)
+		} else {
+			introduced = append(introduced, f)
+		}
+	}
+	for _, f := range prior {
+		if k := key(f); remaining[k] > 0 {
+			remaining[k]--
+			resolved = append(resolved, f)
+		}
+	}
+	return introduced, persisting, resolved
+}
+
+// changedLines diffs the lines of a and b and returns the 1-based numbers of the
+// lines only in a and of the lines only in b. Lines are compared without surrounding
+// whitespace, and blank lines never count as changed.
+func changedLines(a, b string) (removed, added map[int]bool) {
+	x, y := strings.Split(a, "\n"), strings.Split(b, "\n")
+	for i := range x {
+		x[i] = strings.TrimSpace(x[i])
+	}
+	for i := range y {
+		y[i] = strings.TrimSpace(y[i])
+	}
+	removed, added = map[int]bool{}, map[int]bool{}
+
+	// Edits are usually local, so only align what lies between the common ends
+	prefix := 0
+	for prefix < len(x) && prefix < len(y) && x[prefix] == y[prefix] {
+		prefix++
+	}
+	suffix := 0
+	for suffix < len(x)-prefix && suffix < len(y)-prefix && x[len(x)-1-suffix] == y[len(y)-1-suffix] {
+		suffix++
+	}
+	mx, my := x[prefix:len(x)-suffix], y[prefix:len(y)-suffix]
+	mark := func(lines map[int]bool, text []string, i int) {
+		if text[i] != "" {
+			lines[prefix+i+1] = true
+		}
+	}
+
+	if (len(mx)+1)*(len(my)+1) > maxDiffCells {
+		for i := range mx {
+			mark(removed, mx, i)
+		}
+		for j := range my {
+			mark(added, my, j)
+		}
+		return removed, added
+	}
+
+	lcs := make([][]int32, len(mx)+1)
+	for i := range lcs {
+		lcs[i] = make([]int32, len(my)+1)
+	}
+	for i := len(mx) - 1; i >= 0; i-- {
+		for j := len(my) - 1; j >= 0; j-- {
+			if mx[i] == my[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+	i, j := 0, 0
+	for i < len(mx) && j < len(my) {
+		switch {
+		case mx[i] == my[j]:
+			i++
+			j++
+		case lcs[i+1][j] >= lcs[i][j+1]:
+			mark(removed, mx, i)
+			i++
+		default:
+			mark(added, my, j)
+			j++
+		}
+	}
+	for ; i < len(mx); i++ {
+		mark(removed, mx, i)
+	}
+	for ; j < len(my); j++ {
+		mark(added, my, j)
+	}
+	return removed, added
+}
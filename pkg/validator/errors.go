@@ -2,6 +2,7 @@ package validator
 
 import (
 	"fmt"
+	"sort"
 	"strings"
 )
 
@@ -12,8 +13,13 @@ type ValidationError struct {
 	Message     string   `json:"message"`     // Human readable description
 	Found       string   `json:"found"`       // What was found in the content
 	Expected    string   `json:"expected"`    // What should be there instead
+	SuggestedRewrite string `json:"suggested_rewrite,omitempty"` // Replacement for the flagged content, when the request asked for a fix
 	SpecSection string   `json:"spec_section"` // Which part of spec this relates to
+	SpecURL     string   `json:"spec_url,omitempty"`    // Published page and anchor of that section, when known
+	Rule        string   `json:"rule,omitempty"`        // Custom rule that produced the finding, if any
 	LineNumber  int      `json:"line_number,omitempty"` // Line number if available
+	EndLine     int      `json:"end_line,omitempty"`    // Last line of the flagged section, if it spans several
+	Confidence  float64  `json:"confidence,omitempty"`  // Similarity score that produced the finding, if any
 	Suggestions []string `json:"suggestions"` // Actionable suggestions
 }
 
@@ -60,12 +66,27 @@ func (e *ValidationError) WithSpecSection(section string) *ValidationError {
 	return e
 }
 
+// WithSpecURL sets the link to the relevant spec section
+func (e *ValidationError) WithSpecURL(url string) *ValidationError {
+	e.SpecURL = url
+	return e
+}
+
 // WithLineNumber sets the line number
 func (e *ValidationError) WithLineNumber(line int) *ValidationError {
 	e.LineNumber = line
 	return e
 }
 
+// WithLineRange sets the first and last lines of the flagged section
+func (e *ValidationError) WithLineRange(start, end int) *ValidationError {
+	e.LineNumber = start
+	if end > start {
+		e.EndLine = end
+	}
+	return e
+}
+
 // AddSuggestion adds an actionable suggestion
 func (e *ValidationError) AddSuggestion(suggestion string) *ValidationError {
 	e.Suggestions = append(e.Suggestions, suggestion)
@@ -106,6 +127,9 @@ func (e *ValidationError) FormatErrorMessage() string {
 	if e.SpecSection != "" {
 		details = append(details, fmt.Sprintf("Spec Reference: %s", e.SpecSection))
 	}
+	if e.SpecURL != "" {
+		details = append(details, fmt.Sprintf("Spec Link: %s", e.SpecURL))
+	}
 	
 	// Add suggestions
 	if len(e.Suggestions) > 0 {
@@ -155,4 +179,24 @@ func NewUnsupportedFeatureError(feature, reason string) *ValidationError {
 		WithExpected(reason).
 		AddSuggestion("Remove references to unsupported features").
 		AddSuggestion("Check the latest MCP specification for supported features")
-}
\ No newline at end of file
+}
+// SeverityRank orders severities from most to least serious; unknown severities sort last
+func SeverityRank(severity string) int {
+	switch severity {
+	case SeverityCritical:
+		return 0
+	case SeverityWarning:
+		return 1
+	case SeveritySuggestion:
+		return 2
+	default:
+		return 3
+	}
+}
+
+// SortBySeverity orders findings critical first, keeping document order within a severity
+func SortBySeverity(findings []ValidationError) {
+	sort.SliceStable(findings, func(i, j int) bool {
+		return SeverityRank(findings[i].Severity) < SeverityRank(findings[j].Severity)
+	})
+}
@@ -0,0 +1,253 @@
+package validator
+
+import (
+	"context"
+	"math"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/carlisia/mcp-factcheck/embedding"
+)
+
+// Explanation shows why a spec section matched the content: the phrases of the content
+// it shares with the section, and the section's sentences closest to the content
+type Explanation struct {
+	Highlights []Highlight   `json:"highlights"` // Most influential first
+	Excerpts   []SpecExcerpt `json:"excerpts"`   // Most overlapping first
+}
+
+// Highlight is a phrase of the content that shares terms with a spec section
+type Highlight struct {
+	Text   string  `json:"text"`
+	Line   int     `json:"line"`   // Line of the validated text the phrase starts on
+	Weight float64 `json:"weight"` // Share of the content's overlap with the section due to this phrase
+}
+
+// SpecExcerpt is a sentence quoted from a spec section, with the content sentence it
+// overlaps most
+type SpecExcerpt struct {
+	Text    string  `json:"text"`
+	Claim   string  `json:"claim"`   // Sentence of the content it was compared with
+	Overlap float64 `json:"overlap"` // Cosine similarity of the two sentences' terms, 0-1
+	Stance  string  `json:"stance"`  // "supports", or "contradicts" when exactly one of the two is negated
+}
+
+// Excerpt stances. They are lexical cues, not verdicts; claimCheck verifies claims.
+const (
+	StanceSupports    = "supports"
+	StanceContradicts = "contradicts"
+)
+
+const (
+	maxHighlights  = 5
+	maxExcerpts    = 3
+	maxExcerptSize = 400
+)
+
+// explainTerm matches words, keeping compound identifiers like
+// notifications/initialized or list_changed together
+var explainTerm = regexp.MustCompile(`[A-Za-z0-9]+(?:[/_.-][A-Za-z0-9]+)*`)
+
+// negation matches wording that negates a sentence's claim
+var negation = regexp.MustCompile(`(?i)\b(?:not|never|no|cannot|neither|nor|without)\b|n't\b`)
+
+// stopwords carry no meaning of their own, so they don't count as overlap
+var stopwords = map[string]bool{}
+
+func init() {
+	for _, w := range strings.Fields(`a an and are as at be been but by can do does for from has have if in into is it
+		its may must of on or should shall so such that the their them then there these they this to was were what when
+		which while who will with would you your also any each other than only via`) {
+		stopwords[w] = true
+	}
+}
+
+type explainKey struct{}
+
+// WithExplain makes validations run with ctx explain each spec match
+func WithExplain(ctx context.Context) context.Context {
+	return context.WithValue(ctx, explainKey{}, true)
+}
+
+// applyExplanations explains matches, which summarize the first results in order, when
+// ctx asks for explanations. Lines are counted from firstLine, the line of the
+// validated text content starts on.
+func applyExplanations(ctx context.Context, content string, firstLine int, results []embedding.SearchResult, matches []ValidationMatch) {
+	if explain, _ := ctx.Value(explainKey{}).(bool); !explain {
+		return
+	}
+	for i := range matches {
+		matches[i].Explanation = Explain(content, firstLine, results[i].Chunk.Content)
+	}
+}
+
+// explainToken is a term of a text with its byte range
+type explainToken struct {
+	term       string
+	start, end int
+}
+
+func explainTokens(text string) []explainToken {
+	var tokens []explainToken
+	for _, loc := range explainTerm.FindAllStringIndex(text, -1) {
+		tokens = append(tokens, explainToken{term: strings.ToLower(text[loc[0]:loc[1]]), start: loc[0], end: loc[1]})
+	}
+	return tokens
+}
+
+// termSet returns the meaningful terms of text
+func termSet(text string) map[string]bool {
+	set := map[string]bool{}
+	for _, t := range explainTokens(text) {
+		if !stopwords[t.term] {
+			set[t.term] = true
+		}
+	}
+	return set
+}
+
+// Explain compares content with the text of a spec section it matched. Shared terms
+// stand in for what drew the two embeddings together: highlights are runs of content
+// terms found in the section, joined across stopwords, weighted by the share of the
+// shared terms they hold. Each spec sentence is paired with the content sentence whose
+// terms it overlaps most.
+func Explain(content string, firstLine int, spec string) *Explanation {
+	specTerms := termSet(spec)
+	tokens := explainTokens(content)
+
+	type phrase struct {
+		start, end int
+		shared     int
+	}
+	var phrases []phrase
+	var current *phrase
+	totalShared := 0
+	prevEnd := 0
+	for _, t := range tokens {
+		// Phrases end at punctuation and line breaks
+		if strings.ContainsAny(content[prevEnd:t.start], ".!?;:,()\n") {
+			current = nil
+		}
+		prevEnd = t.end
+		switch {
+		case stopwords[t.term]:
+			// Stopwords join shared terms into one phrase but never start or end one
+		case specTerms[t.term]:
+			totalShared++
+			if current == nil {
+				phrases = append(phrases, phrase{start: t.start})
+				current = &phrases[len(phrases)-1]
+			}
+			current.end = t.end
+			current.shared++
+		default:
+			current = nil
+		}
+	}
+
+	explanation := &Explanation{Highlights: []Highlight{}, Excerpts: []SpecExcerpt{}}
+	for _, p := range phrases {
+		text := content[p.start:p.end]
+		// A lone common word isn't a phrase worth pointing at
+		if p.shared == 1 && len(text) < 6 && !strings.ContainsAny(text, "/_.-") {
+			continue
+		}
+		explanation.Highlights = append(explanation.Highlights, Highlight{
+			Text:   text,
+			Line:   firstLine + strings.Count(content[:p.start], "\n"),
+			Weight: round2(float64(p.shared) / float64(totalShared)),
+		})
+	}
+	sort.SliceStable(explanation.Highlights, func(i, j int) bool {
+		return explanation.Highlights[i].Weight > explanation.Highlights[j].Weight
+	})
+	if len(explanation.Highlights) > maxHighlights {
+		explanation.Highlights = explanation.Highlights[:maxHighlights]
+	}
+
+	claims := sentences(content)
+	claimTerms := make([]map[string]bool, len(claims))
+	for i, claim := range claims {
+		claimTerms[i] = termSet(claim)
+	}
+	for _, sentence := range sentences(spec) {
+		terms := termSet(sentence)
+		best, bestOverlap := -1, 0.0
+		for i := range claims {
+			if overlap := termOverlap(terms, claimTerms[i]); overlap > bestOverlap {
+				best, bestOverlap = i, overlap
+			}
+		}
+		if best < 0 {
+			continue
+		}
+		stance := StanceSupports
+		if negation.MatchString(sentence) != negation.MatchString(claims[best]) {
+			stance = StanceContradicts
+		}
+		explanation.Excerpts = append(explanation.Excerpts, SpecExcerpt{
+			Text:    truncateExcerpt(sentence),
+			Claim:   truncateExcerpt(claims[best]),
+			Overlap: round2(bestOverlap),
+			Stance:  stance,
+		})
+	}
+	sort.SliceStable(explanation.Excerpts, func(i, j int) bool {
+		return explanation.Excerpts[i].Overlap > explanation.Excerpts[j].Overlap
+	})
+	if len(explanation.Excerpts) > maxExcerpts {
+		explanation.Excerpts = explanation.Excerpts[:maxExcerpts]
+	}
+	return explanation
+}
+
+// sentences splits text into sentences, skipping headings
+func sentences(text string) []string {
+	lines := strings.Split(text, "\n")
+	for i, line := range lines {
+		if strings.HasPrefix(strings.TrimSpace(line), "#") {
+			lines[i] = ""
+		}
+	}
+	text = strings.Join(lines, "\n")
+	var out []string
+	start := 0
+	add := func(s string) {
+		s = strings.TrimSpace(s)
+		if s != "" {
+			out = append(out, strings.Join(strings.Fields(s), " "))
+		}
+	}
+	for _, loc := range sentenceEnd.FindAllStringIndex(text, -1) {
+		add(text[start:loc[1]])
+		start = loc[1]
+	}
+	add(text[start:])
+	return out
+}
+
+// termOverlap is the cosine similarity of two term sets
+func termOverlap(a, b map[string]bool) float64 {
+	if len(a) == 0 || len(b) == 0 {
+		return 0
+	}
+	shared := 0
+	for term := range a {
+		if b[term] {
+			shared++
+		}
+	}
+	return float64(shared) / math.Sqrt(float64(len(a)*len(b)))
+}
+
+func truncateExcerpt(s string) string {
+	if len(s) <= maxExcerptSize {
+		return s
+	}
+	return s[:maxExcerptSize] + "..."
+}
+
+func round2(v float64) float64 {
+	return math.Round(v*100) / 100
+}
@@ -0,0 +1,129 @@
+package validator
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"maps"
+	"path/filepath"
+	"strings"
+
+	"github.com/carlisia/mcp-factcheck/embedding"
+	mcpembedding "github.com/carlisia/mcp-factcheck/internal/embedding"
+	"github.com/carlisia/mcp-factcheck/internal/limits"
+	"github.com/carlisia/mcp-factcheck/internal/roots"
+	"github.com/carlisia/mcp-factcheck/internal/specs"
+	"github.com/carlisia/mcp-factcheck/internal/toolerr"
+	"github.com/carlisia/mcp-factcheck/pkg/logger"
+	"github.com/mark3labs/mcp-go/mcp"
+	"go.uber.org/zap"
+)
+
+const ValidateFileToolName = "validate_file"
+
+func GetValidateFileTool(files *roots.Set) mcp.Tool {
+	schema := map[string]any{
+		"type": "object",
+		"properties": map[string]any{
+			"path": map[string]any{
+				"type":        "string",
+				"description": fmt.Sprintf("Path or file:// URI of the file to validate. Absolute paths must be inside one of the server's roots (%s), and inside one of the client's roots when it declares them; relative paths are resolved against them", strings.Join(files.Dirs(), ", ")),
+			},
+			"specVersion": map[string]any{
+				"type":        "string",
+				"description": "MCP specification version to validate against",
+				"enum":        specs.ValidSpecVersions,
+				"default":     specs.DefaultSpecVersion,
+			},
+			"corpus": map[string]any{
+				"type":        "string",
+				"description": "Name of a documentation corpus ingested on the server to check documents against alongside the MCP specification",
+			},
+		},
+		"required": []string{"path"},
+	}
+	maps.Copy(schema["properties"].(map[string]any), chunkingSchema())
+	schemaBytes, _ := json.Marshal(schema)
+
+	description := `Read a file from the user's workspace on the server side and validate it against the embedded official MCP specification.
+
+USE THIS INSTEAD OF validate_content OR validate_code WHEN the file is open in the editor or named by path, so its contents don't have to be passed as an argument.
+
+Go, TypeScript/JavaScript, and Python files are validated as code; everything else as content. Findings carry line numbers in the file.`
+
+	return mcp.NewToolWithRawSchema(ValidateFileToolName, description, schemaBytes)
+}
+
+// codeLanguage returns the validate_code language for a source file, or "" for documents
+func codeLanguage(path string) string {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".go":
+		return "go"
+	case ".ts", ".tsx", ".mts", ".cts", ".js", ".jsx", ".mjs", ".cjs":
+		return "typescript"
+	case ".py":
+		return "python"
+	}
+	return ""
+}
+
+// HandleValidateFile reads a file within the roots and validates it with validate_code
+// or validate_content, depending on its extension
+func HandleValidateFile(ctx context.Context, vectorDB *mcpembedding.VectorDB, generator *embedding.Generator, files *roots.Set, args any) ([]mcp.Content, error) {
+	log := logger.WithRequestID(ctx)
+
+	params, ok := args.(map[string]any)
+	if !ok {
+		return nil, toolerr.InvalidArgs("arguments must be a map")
+	}
+	path, _ := params["path"].(string)
+
+	f, absPath, err := files.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	info, err := f.Stat()
+	if err != nil {
+		return nil, fmt.Errorf("failed to stat %s: %w", absPath, err)
+	}
+	// The file stands in for a content argument, so it gets the same cap
+	if err := limits.CheckContentLength(absPath, int(info.Size())); err != nil {
+		return nil, err
+	}
+	data, err := io.ReadAll(io.LimitReader(f, info.Size()))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", absPath, err)
+	}
+
+	delegated := map[string]any{}
+	if specVersion, ok := params["specVersion"]; ok {
+		delegated["specVersion"] = specVersion
+	}
+
+	ctx = WithDocument(ctx, absPath)
+	var result *mcp.CallToolResult
+	language := codeLanguage(absPath)
+	log.Info("Validating file",
+		zap.String("path", absPath),
+		zap.Int("size", len(data)),
+		zap.String("language", language))
+	if language != "" {
+		delegated["code"] = string(data)
+		delegated["language"] = language
+		result, err = HandleValidateCode(ctx, vectorDB, generator, delegated)
+	} else {
+		delegated["content"] = string(data)
+		for _, key := range []string{"corpus", "chunkStrategy", "chunkSize", "chunkOverlap"} {
+			if value, ok := params[key]; ok {
+				delegated[key] = value
+			}
+		}
+		result, err = HandleValidateContent(ctx, vectorDB, generator, delegated)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return append([]mcp.Content{mcp.NewTextContent(fmt.Sprintf("File: %s\n", absPath))}, result.Content...), nil
+}
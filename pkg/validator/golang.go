@@ -0,0 +1,316 @@
+package validator
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Import paths recognized by the Go analyzer
+const (
+	mcpGoServerImport = "github.com/mark3labs/mcp-go/server"
+	mcpGoClientImport = "github.com/mark3labs/mcp-go/client"
+	mcpGoImport       = "github.com/mark3labs/mcp-go/mcp"
+	goSDKImportPrefix = "github.com/modelcontextprotocol/go-sdk"
+)
+
+// mcpGoClientRequests are the mcp-go client methods that send a request other than ping
+var mcpGoClientRequests = []string{
+	"ListResources", "ListResourcesByPage", "ListResourceTemplates", "ListResourceTemplatesByPage",
+	"ReadResource", "Subscribe", "Unsubscribe", "ListPrompts", "ListPromptsByPage", "GetPrompt",
+	"ListTools", "ListToolsByPage", "CallTool", "SetLevel", "Complete",
+}
+
+// goLiteral is a string literal found in the source
+type goLiteral struct {
+	value  string
+	pos    token.Pos
+	inCase bool // part of a switch case, i.e. a dispatch target rather than a message sent
+}
+
+// goSource wraps a parsed file with the line offset added to make it parseable
+type goSource struct {
+	fset   *token.FileSet
+	file   *ast.File
+	offset int
+}
+
+func (src *goSource) line(pos token.Pos) int {
+	return max(1, src.fset.Position(pos).Line-src.offset)
+}
+
+// parseGoSnippet parses a whole file, or a snippet of declarations or statements
+func parseGoSnippet(code string) (*goSource, error) {
+	wrappers := []struct {
+		prefix, suffix string
+	}{
+		{"", ""},
+		{"package snippet\n", ""},
+		{"package snippet\nfunc _() {\n", "\n}"},
+	}
+	var firstErr error
+	for _, w := range wrappers {
+		fset := token.NewFileSet()
+		file, err := parser.ParseFile(fset, "snippet.go", w.prefix+code+w.suffix, parser.SkipObjectResolution)
+		if err == nil {
+			return &goSource{fset: fset, file: file, offset: strings.Count(w.prefix, "\n")}, nil
+		}
+		if firstErr == nil {
+			firstErr = err
+		}
+	}
+	return nil, firstErr
+}
+
+// analyzeGoCode parses Go source and checks its MCP usage against specVersion:
+// method names, capability declarations, dispatch coverage, initialize ordering,
+// and protocol version strings. It returns an error if the code doesn't parse.
+func analyzeGoCode(code, specVersion string) (*staticAnalysis, error) {
+	src, err := parseGoSnippet(code)
+	if err != nil {
+		return nil, err
+	}
+
+	analysis := &staticAnalysis{}
+	imports := map[string]string{} // local name -> import path
+	for _, imp := range src.file.Imports {
+		path, _ := strconv.Unquote(imp.Path.Value)
+		name := path[strings.LastIndex(path, "/")+1:]
+		if imp.Name != nil {
+			name = imp.Name.Name
+		}
+		imports[name] = path
+	}
+	importsPath := func(match func(string) bool) string {
+		for name, path := range imports {
+			if match(path) {
+				return name
+			}
+		}
+		return ""
+	}
+	serverPkg := importsPath(func(p string) bool { return p == mcpGoServerImport })
+	clientPkg := importsPath(func(p string) bool { return p == mcpGoClientImport })
+	if serverPkg != "" {
+		analysis.Patterns = append(analysis.Patterns, "mcp-go MCP server implementation")
+	}
+	if clientPkg != "" {
+		analysis.Patterns = append(analysis.Patterns, "mcp-go MCP client implementation")
+	}
+	if importsPath(func(p string) bool { return p == mcpGoImport }) != "" && serverPkg == "" && clientPkg == "" {
+		analysis.Patterns = append(analysis.Patterns, "mcp-go protocol types")
+	}
+	if importsPath(func(p string) bool { return strings.HasPrefix(p, goSDKImportPrefix) }) != "" {
+		analysis.Patterns = append(analysis.Patterns, "official Go SDK MCP implementation")
+	}
+
+	literals := collectGoLiterals(src.file)
+	jsonrpcLiterals := slices.ContainsFunc(literals, func(l goLiteral) bool { return l.value == "2.0" }) &&
+		slices.ContainsFunc(literals, func(l goLiteral) bool { return l.value == "jsonrpc" })
+	if jsonrpcLiterals || hasJSONRPCTag(src.file) {
+		analysis.Patterns = append(analysis.Patterns, "custom JSON-RPC protocol implementation")
+	}
+
+	var lineLiterals []sourceLiteral
+	handled := map[string]int{}
+	for _, lit := range literals {
+		line := src.line(lit.pos)
+		lineLiterals = append(lineLiterals, sourceLiteral{value: lit.value, line: line})
+		if lit.inCase {
+			if _, ok := handled[lit.value]; !ok {
+				handled[lit.value] = line
+			}
+		}
+	}
+	analysis.checkMethodNames(lineLiterals, specVersion)
+	analysis.checkDispatch(handled)
+	analysis.checkInitializeOrder(src, clientPkg != "")
+	analysis.checkGoProtocolVersions(src, specVersion)
+	if serverPkg != "" {
+		analysis.checkMCPGoCapabilities(src, serverPkg)
+	}
+	return analysis, nil
+}
+
+// collectGoLiterals returns every string literal, marking those used as switch cases
+func collectGoLiterals(file *ast.File) []goLiteral {
+	caseLits := map[*ast.BasicLit]bool{}
+	ast.Inspect(file, func(n ast.Node) bool {
+		if clause, ok := n.(*ast.CaseClause); ok {
+			for _, expr := range clause.List {
+				if lit, ok := expr.(*ast.BasicLit); ok {
+					caseLits[lit] = true
+				}
+			}
+		}
+		return true
+	})
+
+	var literals []goLiteral
+	ast.Inspect(file, func(n ast.Node) bool {
+		lit, ok := n.(*ast.BasicLit)
+		if !ok || lit.Kind != token.STRING {
+			return true
+		}
+		value, err := strconv.Unquote(lit.Value)
+		if err != nil {
+			return true
+		}
+		literals = append(literals, goLiteral{value: value, pos: lit.Pos(), inCase: caseLits[lit]})
+		return true
+	})
+	return literals
+}
+
+// hasJSONRPCTag reports whether a struct declares a field tagged json:"jsonrpc"
+func hasJSONRPCTag(file *ast.File) bool {
+	found := false
+	ast.Inspect(file, func(n ast.Node) bool {
+		if f, ok := n.(*ast.Field); ok && f.Tag != nil && strings.Contains(f.Tag.Value, `json:"jsonrpc`) {
+			found = true
+		}
+		return !found
+	})
+	return found
+}
+
+// checkInitializeOrder flags requests sent before the initialize request in the same function
+func (a *staticAnalysis) checkInitializeOrder(src *goSource, usesMCPGoClient bool) {
+	for _, decl := range src.file.Decls {
+		fn, ok := decl.(*ast.FuncDecl)
+		if !ok || fn.Body == nil {
+			continue
+		}
+
+		// mcp-go clients: c.ListTools(...) before c.Initialize(...) on the same receiver
+		if usesMCPGoClient {
+			initialized := map[string]token.Pos{}
+			var calls []*ast.CallExpr
+			ast.Inspect(fn.Body, func(n ast.Node) bool {
+				if call, ok := n.(*ast.CallExpr); ok {
+					calls = append(calls, call)
+				}
+				return true
+			})
+			for _, call := range calls {
+				if recv, method, ok := selectorCall(call); ok && method == "Initialize" {
+					if _, seen := initialized[recv]; !seen {
+						initialized[recv] = call.Pos()
+					}
+				}
+			}
+			for _, call := range calls {
+				recv, method, ok := selectorCall(call)
+				initPos, hasInit := initialized[recv]
+				if ok && hasInit && slices.Contains(mcpGoClientRequests, method) && call.Pos() < initPos {
+					a.add(requestBeforeInitialize(fmt.Sprintf("%s.%s", recv, method), src.line(call.Pos())))
+				}
+			}
+		}
+
+		// Hand-written messages: method literals sent before "initialize"
+		var sent []sourceLiteral
+		ast.Inspect(fn.Body, func(n ast.Node) bool {
+			if _, ok := n.(*ast.CaseClause); ok {
+				return false
+			}
+			if lit, ok := n.(*ast.BasicLit); ok && lit.Kind == token.STRING {
+				if value, err := strconv.Unquote(lit.Value); err == nil {
+					sent = append(sent, sourceLiteral{value: value, line: src.line(lit.Pos())})
+				}
+			}
+			return true
+		})
+		a.checkSendOrder(sent)
+	}
+}
+
+// selectorCall returns the receiver identifier and method of a call like c.Method(...)
+func selectorCall(call *ast.CallExpr) (string, string, bool) {
+	sel, ok := call.Fun.(*ast.SelectorExpr)
+	if !ok {
+		return "", "", false
+	}
+	recv, ok := sel.X.(*ast.Ident)
+	if !ok {
+		return "", "", false
+	}
+	return recv.Name, sel.Sel.Name, true
+}
+
+// checkGoProtocolVersions checks protocolVersion fields set to string literals
+func (a *staticAnalysis) checkGoProtocolVersions(src *goSource, specVersion string) {
+	ast.Inspect(src.file, func(n ast.Node) bool {
+		var key string
+		var value ast.Expr
+		switch n := n.(type) {
+		case *ast.KeyValueExpr:
+			switch k := n.Key.(type) {
+			case *ast.Ident:
+				key = k.Name
+			case *ast.BasicLit:
+				key, _ = strconv.Unquote(k.Value)
+			}
+			value = n.Value
+		case *ast.AssignStmt:
+			if len(n.Lhs) != 1 || len(n.Rhs) != 1 {
+				return true
+			}
+			if sel, ok := n.Lhs[0].(*ast.SelectorExpr); ok {
+				key = sel.Sel.Name
+			}
+			value = n.Rhs[0]
+		default:
+			return true
+		}
+		if !strings.EqualFold(key, "protocolVersion") {
+			return true
+		}
+		lit, ok := value.(*ast.BasicLit)
+		if !ok || lit.Kind != token.STRING {
+			return true
+		}
+		version, _ := strconv.Unquote(lit.Value)
+		a.checkProtocolVersion(version, src.line(lit.Pos()), specVersion)
+		return true
+	})
+}
+
+// checkMCPGoCapabilities flags capabilities declared on an mcp-go server without
+// anything registered to serve them
+func (a *staticAnalysis) checkMCPGoCapabilities(src *goSource, serverPkg string) {
+	options := map[string]token.Pos{}
+	registered := map[string]bool{}
+	ast.Inspect(src.file, func(n ast.Node) bool {
+		call, ok := n.(*ast.CallExpr)
+		if !ok {
+			return true
+		}
+		recv, method, ok := selectorCall(call)
+		if !ok {
+			return true
+		}
+		if recv == serverPkg && strings.HasPrefix(method, "With") {
+			options[method] = call.Pos()
+		} else {
+			registered[method] = true
+		}
+		return true
+	})
+
+	for _, f := range serverFeatures {
+		pos, declared := options[f.option]
+		if !declared || slices.ContainsFunc(f.registration, func(m string) bool { return registered[m] }) {
+			continue
+		}
+		a.add(NewValidationError(IssueTypeMissing, SeverityWarning, fmt.Sprintf("Server declares the %s capability but registers no %s", f.capability, f.capability)).
+			WithFound(fmt.Sprintf("%s.%s", serverPkg, f.option)).
+			WithSpecSection("Lifecycle: Capability Negotiation").
+			WithLineNumber(src.line(pos)).
+			AddSuggestion(fmt.Sprintf("Register %s with %s, or drop the capability so clients don't call %s", f.capability, strings.Join(f.registration, "/"), f.listMethod)))
+	}
+}
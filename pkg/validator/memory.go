@@ -0,0 +1,121 @@
+package validator
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"time"
+)
+
+// DocumentState is what a session remembers about the last validation of a document
+type DocumentState struct {
+	Fingerprint string // Hash of the content, options, and settings the document was validated with
+	Request     Request
+	Result      *AggregatedValidationResult
+	ValidatedAt time.Time
+}
+
+// DocumentMemory remembers the documents validated in one session. Documents are
+// named by Request.Document, or by their fingerprint when unnamed.
+type DocumentMemory interface {
+	Recall(name string) (DocumentState, bool)
+	Remember(name string, state DocumentState)
+}
+
+type memoryKey struct{}
+
+type previousKey struct{}
+
+// WithDocumentMemory makes validations run with ctx remember what they validated in
+// memory, and answer from it when the same content is validated again with the same
+// options and settings
+func WithDocumentMemory(ctx context.Context, memory DocumentMemory) context.Context {
+	return context.WithValue(ctx, memoryKey{}, memory)
+}
+
+func documentMemoryFrom(ctx context.Context) DocumentMemory {
+	memory, _ := ctx.Value(memoryKey{}).(DocumentMemory)
+	return memory
+}
+
+// withPrevious makes chunked validations run with ctx reuse the results of chunks of
+// previous whose text hasn't changed
+func withPrevious(ctx context.Context, previous *AggregatedValidationResult) context.Context {
+	return context.WithValue(ctx, previousKey{}, previous)
+}
+
+// previousChunks returns the reusable chunk results of the previous validation in
+// ctx, by chunk text, or nil when there is none
+func previousChunks(ctx context.Context) map[string]ChunkValidationResult {
+	previous, _ := ctx.Value(previousKey{}).(*AggregatedValidationResult)
+	if previous == nil {
+		return nil
+	}
+	chunks := make(map[string]ChunkValidationResult, len(previous.ChunkResults))
+	for _, cr := range previous.ChunkResults {
+		if cr.Error == "" {
+			chunks[cr.Chunk.Text] = cr
+		}
+	}
+	return chunks
+}
+
+// reusedFor returns a previous chunk result moved to where chunk is in the new content
+func (r ChunkValidationResult) reusedFor(chunk ContentChunk) ChunkValidationResult {
+	r.Chunk = chunk
+	r.Reused = true
+	errs := make([]ValidationError, len(r.Validation.Errors))
+	for i, finding := range r.Validation.Errors {
+		finding.EndLine = 0
+		errs[i] = *finding.WithLineRange(chunk.StartLine, chunk.EndLine)
+	}
+	r.Validation.Errors = errs
+	return r
+}
+
+// fingerprint hashes everything that decides the request's result: the content and
+// options, except the document's name, and the validator settings in effect
+func (r Request) fingerprint() string {
+	r.Document = ""
+	request, _ := json.Marshal(r)
+	settings, _ := json.Marshal(ToolSettingsFor(ValidateContentToolName))
+	sum := sha256.Sum256(append(append(request, 0), settings...))
+	return hex.EncodeToString(sum[:])
+}
+
+// memoryName names the request's document in a DocumentMemory
+func (r Request) memoryName(fingerprint string) string {
+	if r.Document != "" {
+		return r.Document
+	}
+	return "sha256:" + fingerprint
+}
+
+// recallUnchanged returns the remembered result when the request's document was
+// last validated with the same fingerprint
+func recallUnchanged(ctx context.Context, req Request, fingerprint string) (*AggregatedValidationResult, bool) {
+	memory := documentMemoryFrom(ctx)
+	if memory == nil {
+		return nil, false
+	}
+	state, ok := memory.Recall(req.memoryName(fingerprint))
+	if !ok || state.Fingerprint != fingerprint {
+		return nil, false
+	}
+	result := *state.Result
+	result.UnchangedSince = &state.ValidatedAt
+	return &result, true
+}
+
+// remember records a completed validation in the memory in ctx, if any
+func remember(ctx context.Context, req Request, fingerprint string, result *AggregatedValidationResult) {
+	if memory := documentMemoryFrom(ctx); memory != nil {
+		memory.Remember(req.memoryName(fingerprint), DocumentState{
+			Fingerprint: fingerprint,
+			Request:     req,
+			Result:      result,
+			ValidatedAt: time.Now(),
+		})
+	}
+}
@@ -0,0 +1,47 @@
+package validator
+
+import "context"
+
+// Outcome is the structured result of one validation run, reported to the observer in
+// the run's context once it completes
+type Outcome struct {
+	Document    string // URL, file path, or caller-given name of what was validated, if known
+	Content     string
+	SpecVersion string
+	Valid       bool
+	Confidence  float64
+	Findings    []ValidationError
+}
+
+type observerKey struct{}
+
+type documentKey struct{}
+
+// WithObserver makes validations run with ctx report their outcome to observe. Observers
+// already in ctx keep receiving outcomes too.
+func WithObserver(ctx context.Context, observe func(Outcome)) context.Context {
+	if outer, _ := ctx.Value(observerKey{}).(func(Outcome)); outer != nil {
+		inner := observe
+		observe = func(outcome Outcome) {
+			inner(outcome)
+			outer(outcome)
+		}
+	}
+	return context.WithValue(ctx, observerKey{}, observe)
+}
+
+// WithDocument names the document that validations run with ctx are checking, so
+// outcomes for the same document can be compared over time
+func WithDocument(ctx context.Context, document string) context.Context {
+	return context.WithValue(ctx, documentKey{}, document)
+}
+
+// reportOutcome passes a completed validation to the observer in ctx, if any
+func reportOutcome(ctx context.Context, outcome Outcome) {
+	observe, _ := ctx.Value(observerKey{}).(func(Outcome))
+	if observe == nil {
+		return
+	}
+	outcome.Document, _ = ctx.Value(documentKey{}).(string)
+	observe(outcome)
+}
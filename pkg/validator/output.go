@@ -0,0 +1,63 @@
+package validator
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// ValidationOutput is the structured content of validate_content and validate_code
+// results, and their declared output schema. It holds the result the text content
+// describes, in one shape whatever the request's options, so clients can read it
+// without parsing text.
+type ValidationOutput struct {
+	SpecVersion    string                  `json:"spec_version"`
+	Valid          bool                    `json:"valid"`
+	Confidence     float64                 `json:"confidence"`
+	Findings       []ValidationError       `json:"findings"` // Most severe first
+	Validation     ValidationResult        `json:"validation"`
+	References     []ValidationMatch       `json:"references,omitempty"` // Best matching spec sections, when the content was validated as a whole
+	Chunks         []ChunkValidationResult `json:"chunks,omitempty"`     // Per-chunk results, when the content was chunked
+	Summary        string                  `json:"summary,omitempty"`
+	Score          *Score                  `json:"score,omitempty"`
+	UnchangedSince *time.Time              `json:"unchanged_since,omitempty"`
+}
+
+// NewValidationOutput returns the structured content of a validation result
+func NewValidationOutput(result *AggregatedValidationResult) ValidationOutput {
+	findings := result.Findings()
+	if findings == nil {
+		findings = []ValidationError{}
+	}
+	SortBySeverity(findings)
+	return ValidationOutput{
+		SpecVersion:    result.SpecVersion,
+		Valid:          result.Overall.IsValid,
+		Confidence:     result.Overall.Confidence,
+		Findings:       findings,
+		Validation:     result.Overall,
+		References:     result.Matches,
+		Chunks:         result.ChunkResults,
+		Summary:        result.Summary,
+		Score:          result.Score,
+		UnchangedSince: result.UnchangedSince,
+	}
+}
+
+// validationToolResult returns a validation result as text content, for clients that
+// don't read structured content, alongside its structured content
+func validationToolResult(result *AggregatedValidationResult) *mcp.CallToolResult {
+	return StructuredResult([]mcp.Content{mcp.NewTextContent(formatResult(result))}, NewValidationOutput(result))
+}
+
+// StructuredResult returns a tool result with content and structured content. As the
+// MCP spec recommends for backwards compatibility, the structured content is also
+// appended to content as a JSON text block: mcp-go doesn't yet serialize
+// structuredContent, so until it does that block is how clients receive it.
+func StructuredResult(content []mcp.Content, structured any) *mcp.CallToolResult {
+	if payload, err := json.Marshal(structured); err == nil {
+		content = append(content, mcp.NewTextContent(string(payload)))
+	}
+	return &mcp.CallToolResult{Content: content, StructuredContent: structured}
+}
@@ -0,0 +1,39 @@
+package validator
+
+import (
+	"context"
+	"sync"
+)
+
+// ProgressFunc receives a validation's progress: done of total units of work, such as
+// chunks, finished
+type ProgressFunc func(done, total int, message string)
+
+type progressKey struct{}
+
+// WithProgress makes validations run with ctx report their progress to report, which
+// a nil report turns off. Units finish concurrently, so reports can arrive out of
+// order; report only receives the ones that advance, as MCP progress must.
+func WithProgress(ctx context.Context, report ProgressFunc) context.Context {
+	if report == nil {
+		return context.WithValue(ctx, progressKey{}, ProgressFunc(nil))
+	}
+	var mu sync.Mutex
+	last := 0
+	return context.WithValue(ctx, progressKey{}, ProgressFunc(func(done, total int, message string) {
+		mu.Lock()
+		defer mu.Unlock()
+		if done <= last {
+			return
+		}
+		last = done
+		report(done, total, message)
+	}))
+}
+
+// reportProgress passes progress to the reporter in ctx, if any
+func reportProgress(ctx context.Context, done, total int, message string) {
+	if report, _ := ctx.Value(progressKey{}).(ProgressFunc); report != nil {
+		report(done, total, message)
+	}
+}
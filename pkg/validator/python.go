@@ -0,0 +1,201 @@
+package validator
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+var (
+	// pyImport matches from-imports of the MCP SDK and FastMCP, capturing the
+	// module and the imported names
+	pyImport = regexp.MustCompile(`(?m)^[ \t]*from[ \t]+((?:mcp|fastmcp)(?:\.[\w.]+)?)[ \t]+import[ \t]+(\([^)]*\)|[^\n]+)`)
+
+	// pyDecorator matches decorators on an object, like @mcp.tool() or @server.list_tools
+	pyDecorator = regexp.MustCompile(`(?m)^[ \t]*@[ \t]*(\w+)[ \t]*\.[ \t]*(\w+)([ \t]*\()?`)
+
+	// pySessionContext matches sessions opened with async with ClientSession(...) as session
+	pySessionContext = regexp.MustCompile(`\bClientSession\s*\([^)]*\)\s*as\s+(\w+)`)
+)
+
+// pyServerDecorators maps the low-level Python SDK Server decorators to the
+// request they register a handler for
+var pyServerDecorators = map[string]sdkHandler{
+	"list_tools":              {"tools/list", "tools"},
+	"call_tool":               {"tools/call", "tools"},
+	"list_resources":          {"resources/list", "resources"},
+	"list_resource_templates": {"resources/templates/list", "resources"},
+	"read_resource":           {"resources/read", "resources"},
+	"subscribe_resource":      {"resources/subscribe", "resources"},
+	"unsubscribe_resource":    {"resources/unsubscribe", "resources"},
+	"list_prompts":            {"prompts/list", "prompts"},
+	"get_prompt":              {"prompts/get", "prompts"},
+	"set_logging_level":       {"logging/setLevel", "logging"},
+	"completion":              {"completion/complete", "completions"},
+}
+
+// pyClientRequests are the Python SDK ClientSession methods that send a request other than ping
+var pyClientRequests = []string{
+	"list_tools", "call_tool", "list_resources", "list_resource_templates", "read_resource",
+	"subscribe_resource", "unsubscribe_resource", "list_prompts", "get_prompt", "set_logging_level", "complete",
+}
+
+// pyTransports pairs the Python SDK transport functions and classes with the
+// transport they implement and, for HTTP+SSE, their Streamable HTTP replacement
+var pyTransports = [][3]string{
+	{"stdio_server", "stdio transport", ""},
+	{"stdio_client", "stdio transport", ""},
+	{"StreamableHTTPSessionManager", "Streamable HTTP transport", ""},
+	{"streamablehttp_client", "Streamable HTTP transport", ""},
+	{"SseServerTransport", "HTTP+SSE transport", "StreamableHTTPSessionManager"},
+	{"sse_client", "HTTP+SSE transport", "streamablehttp_client"},
+}
+
+// scanPython masks comments and string contents in Python source. Each top-level
+// function or class, with its decorators, and the code between them, is a
+// separate block.
+func scanPython(code string) *sourceText {
+	masked := []byte(code)
+	var literals []sourceLiteral
+	for i := 0; i < len(code); {
+		switch {
+		case code[i] == '#':
+			end := strings.IndexByte(code[i:], '\n')
+			if end < 0 {
+				end = len(code) - i
+			}
+			blank(masked, i, i+end)
+			i += end
+		case code[i] == '"' || code[i] == '\'':
+			quote := code[i : i+1]
+			if strings.HasPrefix(code[i:], strings.Repeat(quote, 3)) {
+				quote = strings.Repeat(quote, 3)
+			}
+			start := i + len(quote)
+			end := scanQuoted(code, start, quote, len(quote) == 1)
+			literals = append(literals, sourceLiteral{value: code[start:end], offset: i, end: min(end+len(quote), len(code))})
+			blank(masked, start, end)
+			i = end + len(quote)
+		default:
+			i++
+		}
+	}
+
+	src := newSourceText(code, masked, literals)
+	inDef, afterDecorator := false, false
+	for _, start := range src.lineStarts {
+		line := src.masked[start:]
+		if end := strings.IndexByte(line, '\n'); end >= 0 {
+			line = line[:end]
+		}
+		if strings.TrimSpace(line) == "" || line[0] == ' ' || line[0] == '\t' {
+			continue
+		}
+		header := line[0] == '@' || strings.HasPrefix(line, "def ") || strings.HasPrefix(line, "async def ") || strings.HasPrefix(line, "class ")
+		switch {
+		case header && !afterDecorator:
+			src.blockStarts = append(src.blockStarts, start)
+			inDef = true
+		case !header && inDef:
+			src.blockStarts = append(src.blockStarts, start)
+			inDef = false
+		}
+		afterDecorator = line[0] == '@'
+	}
+	return src
+}
+
+// analyzePythonCode checks Python MCP code against specVersion: FastMCP and
+// low-level SDK servers, ClientSession clients, and hand-written protocol code
+func analyzePythonCode(code, specVersion string) (*staticAnalysis, error) {
+	src := scanPython(code)
+	analysis := &staticAnalysis{}
+
+	// Names imported from the SDK, mapped to their local names, and the module
+	// each came from
+	imported := map[string]string{}
+	modules := map[string]string{}
+	for _, m := range pyImport.FindAllStringSubmatch(src.masked, -1) {
+		list := strings.Trim(strings.TrimSpace(m[2]), "()")
+		for name, local := range importedNames(strings.ReplaceAll(list, "\n", " "), "as") {
+			imported[name] = local
+			modules[name] = m[1]
+		}
+	}
+
+	decorators := pyDecorator.FindAllStringSubmatchIndex(src.masked, -1)
+	if local, ok := imported["FastMCP"]; ok {
+		servers := src.constructed(local)
+		counts := map[string]int{}
+		for _, m := range decorators {
+			receiver, kind := src.masked[m[2]:m[3]], src.masked[m[4]:m[5]]
+			if !slices.Contains(servers, receiver) || (kind != "tool" && kind != "resource" && kind != "prompt") {
+				continue
+			}
+			counts[kind]++
+			// The SDK's FastMCP decorators take arguments and raise TypeError when applied bare
+			if m[6] < 0 && strings.HasPrefix(modules["FastMCP"], "mcp.") {
+				analysis.add(bareDecorator(receiver, kind, src.line(m[0])))
+			}
+		}
+		analysis.Patterns = append(analysis.Patterns, "Python FastMCP server implementation"+registrationSummary(counts))
+	}
+	if local, ok := imported["Server"]; ok {
+		analysis.Patterns = append(analysis.Patterns, "Python SDK low-level MCP server implementation (Server)")
+		servers := src.constructed(local)
+		handled := map[string]int{}
+		for _, m := range decorators {
+			receiver, name := src.masked[m[2]:m[3]], src.masked[m[4]:m[5]]
+			handler, ok := pyServerDecorators[name]
+			if !ok || !slices.Contains(servers, receiver) {
+				continue
+			}
+			handled[handler.method] = src.line(m[0])
+			if m[6] < 0 {
+				analysis.add(bareDecorator(receiver, name, src.line(m[0])))
+			}
+		}
+		// The low-level server derives its capabilities from the handlers registered,
+		// so only discovery can be incomplete
+		analysis.checkListMethods(handled)
+	}
+	if local, ok := imported["ClientSession"]; ok {
+		analysis.Patterns = append(analysis.Patterns, "Python SDK MCP client implementation (ClientSession)")
+		sessions := src.constructed(local)
+		for _, m := range pySessionContext.FindAllStringSubmatch(src.masked, -1) {
+			if !slices.Contains(sessions, m[1]) {
+				sessions = append(sessions, m[1])
+			}
+		}
+		analysis.checkClientOrder(src, sessions, "initialize", pyClientRequests)
+	}
+	for _, t := range pyTransports {
+		name, transport := t[0], t[1]
+		local, ok := imported[name]
+		if !ok {
+			continue
+		}
+		if !slices.Contains(analysis.Patterns, transport) {
+			analysis.Patterns = append(analysis.Patterns, transport)
+		}
+		if replacement := t[2]; replacement != "" {
+			analysis.checkSSETransport(name, replacement, src.firstLine(`\b`+regexp.QuoteMeta(local)+`\b`), specVersion)
+		}
+	}
+	if src.usesCustomJSONRPC() {
+		analysis.Patterns = append(analysis.Patterns, "custom JSON-RPC protocol implementation")
+	}
+
+	analysis.checkHandWritten(src, specVersion, "protocolVersion", "protocol_version")
+	return analysis, nil
+}
+
+func bareDecorator(receiver, name string, line int) *ValidationError {
+	found := fmt.Sprintf("@%s.%s", receiver, name)
+	return NewValidationError(IssueTypeInaccuracy, SeverityCritical, fmt.Sprintf("%s is used without being called, so the handler is never registered", found)).
+		WithFound(found).
+		WithExpected(found + "()").
+		WithLineNumber(line).
+		AddSuggestion(fmt.Sprintf("Call the decorator: %s()", found))
+}
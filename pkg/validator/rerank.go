@@ -0,0 +1,102 @@
+package validator
+
+import (
+	"context"
+	"sort"
+
+	"github.com/carlisia/mcp-factcheck/embedding"
+	mcpembedding "github.com/carlisia/mcp-factcheck/internal/embedding"
+	"github.com/carlisia/mcp-factcheck/internal/factcheck"
+	"github.com/carlisia/mcp-factcheck/pkg/logger"
+	"github.com/carlisia/mcp-factcheck/pkg/telemetry"
+	"go.opentelemetry.io/otel/attribute"
+	"go.uber.org/zap"
+)
+
+// RerankCandidates is how many search results are retrieved for the reranker to choose from
+const RerankCandidates = 20
+
+// RerankResults reorders search candidates by the chat model's judgement of their
+// relevance to query and returns the top K. Score holds the model's relevance,
+// scaled to [0, 1]; Similarity is left as the vector similarity.
+func RerankResults(ctx context.Context, model ChatModel, query string, candidates []embedding.SearchResult, topK int) ([]embedding.SearchResult, error) {
+	if model.Ask == nil {
+		return nil, ErrNoChatModel
+	}
+	ctx, span := model.spanBuilder("RERANKER").
+		WithInput(query, "text/plain").
+		WithCustom(
+			attribute.String("reranker.model_name", model.name()),
+			attribute.Int("reranker.top_k", topK),
+			attribute.Int("reranker.input_documents", len(candidates)),
+		).
+		Start(ctx, "rerank")
+	defer span.End()
+
+	sections := make([]factcheck.SpecSection, len(candidates))
+	for i, c := range candidates {
+		sections[i] = factcheck.SpecSection{ID: c.Chunk.ID, Content: c.Chunk.Content}
+	}
+	scores, err := factcheck.Rerank(ctx, model.Ask, query, sections)
+	if err != nil {
+		telemetry.RecordError(span, err)
+		return nil, err
+	}
+
+	reranked := make([]embedding.SearchResult, len(candidates))
+	copy(reranked, candidates)
+	for i := range reranked {
+		score, ok := scores[reranked[i].Chunk.ID]
+		if !ok {
+			score = -1 // Unscored candidates go last, in their search order
+		}
+		reranked[i].Score = score / 10
+	}
+	// Stable, so candidates the model scores equally keep their search order
+	sort.SliceStable(reranked, func(i, j int) bool {
+		return reranked[i].Score > reranked[j].Score
+	})
+	reranked = reranked[:min(topK, len(reranked))]
+	for i := range reranked {
+		reranked[i].Rank = i + 1
+	}
+
+	span.SetAttributes(
+		attribute.Int("reranker.output_documents", len(reranked)),
+		attribute.Int("reranker.scored_documents", len(scores)),
+	)
+	return reranked, nil
+}
+
+// searchSpec retrieves the topK spec sections for query, including sections of the
+// corpus set with WithCorpus and favoring the pages of the context type set with
+// WithContextType. With the rerank setting on,
+// it retrieves RerankCandidates and lets the chat model pick the topK, falling back
+// to search order if reranking fails or there is no chat model.
+func searchSpec(ctx context.Context, vectorDB *mcpembedding.VectorDB, generator *embedding.Generator, settings Settings, specVersion, query string, queryEmbedding []float64, topK int) ([]embedding.SearchResult, error) {
+	corpus := corpusFrom(ctx)
+	contextType := contextTypeFrom(ctx)
+	retrieve := scopeCandidates(contextType, topK)
+	model, ok := ChatModelFor(ctx, generator, false)
+	if !settings.Rerank || !ok {
+		results, err := SearchSpecAndCorpus(ctx, vectorDB, specVersion, corpus, query, queryEmbedding, retrieve, settings.KeywordWeight)
+		if err != nil {
+			return nil, err
+		}
+		return weighByScope(results, contextType, specVersion, topK), nil
+	}
+
+	candidates, err := SearchSpecAndCorpus(ctx, vectorDB, specVersion, corpus, query, queryEmbedding, max(retrieve, RerankCandidates), settings.KeywordWeight)
+	if err != nil {
+		return nil, err
+	}
+	reranked, err := RerankResults(ctx, model, query, candidates, len(candidates))
+	if err != nil {
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return nil, ctxErr
+		}
+		logger.WithRequestID(ctx).Warn("Reranking failed, using search order", zap.Error(err))
+		return weighByScope(candidates, contextType, specVersion, topK), nil
+	}
+	return weighByScope(reranked, contextType, specVersion, topK), nil
+}
@@ -0,0 +1,85 @@
+package validator
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/carlisia/mcp-factcheck/embedding"
+	mcpembedding "github.com/carlisia/mcp-factcheck/internal/embedding"
+	"github.com/carlisia/mcp-factcheck/internal/toolerr"
+	"github.com/carlisia/mcp-factcheck/pkg/logger"
+	"github.com/mark3labs/mcp-go/mcp"
+	"go.uber.org/zap"
+)
+
+const RevalidateChangedSectionsToolName = "revalidate_changed_sections"
+
+func GetRevalidateChangedSectionsTool() mcp.Tool {
+	schema := map[string]any{
+		"type": "object",
+		"properties": map[string]any{
+			"document": map[string]any{
+				"type":        "string",
+				"description": "Document name given to validate_content earlier in this session",
+			},
+			"content": map[string]any{
+				"type":        "string",
+				"description": "The document's current content",
+			},
+		},
+		"required": []string{"document", "content"},
+	}
+	schemaBytes, _ := json.Marshal(schema)
+
+	description := `Re-check a document validated earlier in this session after editing it, validating only the sections that changed.
+
+USE THIS INSTEAD OF validate_content WHEN revising a document that validate_content already checked with the same document name: the options of that run are reused, sections whose text is unchanged keep their earlier results (marked "reused"), and the overall result covers the whole new content.`
+
+	return mcp.NewToolWithRawSchema(RevalidateChangedSectionsToolName, description, schemaBytes)
+}
+
+// HandleRevalidateChangedSections validates the new content of a document remembered
+// in the session, reusing the results of chunks that haven't changed
+func HandleRevalidateChangedSections(ctx context.Context, vectorDB *mcpembedding.VectorDB, generator *embedding.Generator, args any) ([]mcp.Content, error) {
+	params, ok := args.(map[string]any)
+	if !ok {
+		return nil, toolerr.InvalidArgs("arguments must be a map")
+	}
+	document, _ := params["document"].(string)
+	content, ok := params["content"].(string)
+	if document == "" || !ok {
+		return nil, fmt.Errorf("document and content are required")
+	}
+
+	memory := documentMemoryFrom(ctx)
+	if memory == nil {
+		return nil, errors.New("revalidation needs an MCP session to remember documents in")
+	}
+	state, ok := memory.Recall(document)
+	if !ok {
+		return nil, fmt.Errorf("%q hasn't been validated in this session; validate it with %s and document set first", document, ValidateContentToolName)
+	}
+
+	// Revalidate with the remembered options, splitting the same way so unchanged
+	// sections produce the same chunks
+	req := state.Request
+	req.Content = content
+	if state.Result.ChunkResults != nil {
+		if !req.chunked() {
+			req.Chunked = true
+		}
+		ctx = withPrevious(ctx, state.Result)
+	}
+	logger.WithRequestID(ctx).Info("Revalidating document",
+		zap.String("document", document),
+		zap.Int("previous_chunks", len(state.Result.ChunkResults)),
+		zap.Time("validated_at", state.ValidatedAt))
+
+	result, err := Validate(ctx, vectorDB, generator, req)
+	if err != nil {
+		return nil, err
+	}
+	return []mcp.Content{mcp.NewTextContent(formatResult(result))}, nil
+}
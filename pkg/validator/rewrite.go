@@ -0,0 +1,52 @@
+package validator
+
+import (
+	"context"
+
+	"github.com/carlisia/mcp-factcheck/embedding"
+	"github.com/carlisia/mcp-factcheck/internal/factcheck"
+	"github.com/carlisia/mcp-factcheck/pkg/logger"
+	"github.com/carlisia/mcp-factcheck/pkg/telemetry"
+	"go.uber.org/zap"
+)
+
+type rewriteKey struct{}
+
+// WithRewrite enables corrected versions of inaccurate content for validations run
+// with ctx, written by model
+func WithRewrite(ctx context.Context, model ChatModel) context.Context {
+	return context.WithValue(ctx, rewriteKey{}, model)
+}
+
+// applyRewrite sets the corrected version of content that validation found
+// inaccurate, written from the retrieved spec sections and the validation's issues
+func applyRewrite(ctx context.Context, content string, results []embedding.SearchResult, validation *ValidationResult) {
+	model, ok := ctx.Value(rewriteKey{}).(ChatModel)
+	if !ok || validation.IsValid || len(results) == 0 {
+		return
+	}
+
+	ctx, span := model.spanBuilder("LLM").
+		WithInput(content, "text/plain").
+		Start(ctx, "content.rewrite")
+	defer span.End()
+
+	sections := make([]factcheck.SpecSection, len(results))
+	for i, result := range results {
+		sections[i] = factcheck.SpecSection{ID: result.Chunk.ID, Content: result.Chunk.Content}
+	}
+	issues := append([]string(nil), validation.Issues...)
+	for _, finding := range validation.Errors {
+		issues = append(issues, finding.Message)
+	}
+
+	corrected, err := factcheck.SuggestRewrite(ctx, model.Ask, content, sections, issues)
+	if err != nil {
+		telemetry.RecordError(span, err)
+		logger.WithRequestID(ctx).Warn("Rewrite failed", zap.String("source", model.Source), zap.Error(err))
+		validation.Issues = append(validation.Issues, "A corrected version could not be generated")
+		return
+	}
+	validation.CorrectedVersion = corrected
+	validation.CorrectedBy = model.Source
+}
@@ -0,0 +1,320 @@
+package validator
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"github.com/carlisia/mcp-factcheck/pkg/logger"
+	"go.uber.org/zap"
+)
+
+// Document is the prose a Rule checks
+type Document struct {
+	Content     string
+	SpecVersion string
+	ContextType string // One of ContextTypes, or empty when the request gave none
+}
+
+// Rule is a custom check run on prose alongside the embedding-based validation, such
+// as a house style or a project's own requirements. Its findings are added to the
+// result with the severities it gives them. Go programs register rules with
+// RegisterRule; rules declared in the rules setting are loaded from the config file.
+type Rule interface {
+	Name() string // Identifies the rule in its findings
+	Check(doc Document) []ValidationError
+}
+
+var (
+	registeredMu    sync.RWMutex
+	registeredRules []Rule
+
+	// Rules compiled from the rules setting by SetSettings
+	declaredRules atomic.Pointer[[]Rule]
+)
+
+// RegisterRule adds rule to every later validation. It panics if the rule has no
+// name or another rule has the same name, since findings name the rule they came from.
+func RegisterRule(rule Rule) {
+	registeredMu.Lock()
+	defer registeredMu.Unlock()
+	name := rule.Name()
+	if name == "" {
+		panic("validator: RegisterRule with an empty rule name")
+	}
+	if slices.ContainsFunc(registeredRules, func(r Rule) bool { return r.Name() == name }) {
+		panic("validator: RegisterRule called twice for rule " + name)
+	}
+	registeredRules = append(registeredRules, rule)
+}
+
+// Rules returns the registered rules followed by the rules of the current settings
+func Rules() []Rule {
+	registeredMu.RLock()
+	rules := slices.Clone(registeredRules)
+	registeredMu.RUnlock()
+	if declared := declaredRules.Load(); declared != nil {
+		rules = append(rules, *declared...)
+	}
+	return rules
+}
+
+// CheckRules runs every rule on doc and returns their findings, most severe first.
+// Findings are marked with the rule that produced them. A rule that panics is skipped.
+func CheckRules(doc Document) []ValidationError {
+	var findings []ValidationError
+	for _, rule := range Rules() {
+		for _, finding := range checkRule(rule, doc) {
+			if finding.Rule == "" {
+				finding.Rule = rule.Name()
+			}
+			if finding.SpecURL == "" && finding.SpecSection != "" {
+				finding.SpecURL = sectionURL(doc.SpecVersion, finding.SpecSection)
+			}
+			findings = append(findings, finding)
+		}
+	}
+	SortBySeverity(findings)
+	return findings
+}
+
+func checkRule(rule Rule, doc Document) (findings []ValidationError) {
+	defer func() {
+		if r := recover(); r != nil {
+			logger.Get().Error("Custom rule panicked", zap.String("rule", rule.Name()), zap.Any("panic", r))
+			findings = nil
+		}
+	}()
+	return rule.Check(doc)
+}
+
+// applyRules adds the findings of the custom rules for content to result
+func applyRules(ctx context.Context, content, specVersion string, result *ValidationResult) {
+	result.Errors = append(result.Errors, CheckRules(Document{
+		Content:     content,
+		SpecVersion: specVersion,
+		ContextType: contextTypeFrom(ctx),
+	})...)
+}
+
+// Kinds of declared rules
+const (
+	RuleKindRegex           = "regex"            // Flags text matching a regular expression
+	RuleKindForbiddenPhrase = "forbidden_phrase" // Flags any of a list of phrases
+	RuleKindRequiredSection = "required_section" // Flags content without a heading
+)
+
+// RuleKinds lists the kinds of declared rules
+var RuleKinds = []string{RuleKindRegex, RuleKindForbiddenPhrase, RuleKindRequiredSection}
+
+// RuleConfig declares a rule in the rules setting. Regex and forbidden phrase rules
+// report each distinct match once, at its first line, and skip fenced code blocks
+// as terminology checks do.
+type RuleConfig struct {
+	Name         string   `json:"name"`
+	Kind         string   `json:"kind"`                    // One of RuleKinds
+	Pattern      string   `json:"pattern,omitempty"`       // Regular expression flagged by regex rules
+	Phrases      []string `json:"phrases,omitempty"`       // Phrases flagged by forbidden_phrase rules, ignoring case
+	Section      string   `json:"section,omitempty"`       // Heading required_section rules look for, ignoring case
+	Trigger      string   `json:"trigger,omitempty"`       // Regular expression the content must match for the rule to apply; empty always applies
+	ContextTypes []string `json:"context_types,omitempty"` // Context types the rule applies to; empty for all content
+	SpecVersions []string `json:"spec_versions,omitempty"` // Spec versions the rule applies to; empty for all
+	Severity     string   `json:"severity,omitempty"`      // critical, warning, or suggestion; warning when empty
+	Type         string   `json:"type,omitempty"`          // Issue type of findings; missing for required sections and imprecise otherwise when empty
+	Message      string   `json:"message,omitempty"`       // Message of findings; describes the match when empty
+	Suggestion   string   `json:"suggestion,omitempty"`
+	SpecSection  string   `json:"spec_section,omitempty"` // Spec section findings cite
+}
+
+// declaredRule is a compiled RuleConfig
+type declaredRule struct {
+	RuleConfig
+	pattern *regexp.Regexp // For regex and forbidden phrase rules
+	trigger *regexp.Regexp
+}
+
+// compileRules checks rule declarations and compiles them
+func compileRules(configs []RuleConfig) ([]Rule, error) {
+	rules := make([]Rule, 0, len(configs))
+	names := map[string]bool{}
+	for i, c := range configs {
+		if c.Name == "" {
+			return nil, fmt.Errorf("rule %d has no name", i)
+		}
+		if names[c.Name] {
+			return nil, fmt.Errorf("duplicate rule name: %s", c.Name)
+		}
+		names[c.Name] = true
+		rule, err := compileRule(c)
+		if err != nil {
+			return nil, fmt.Errorf("rule %s: %w", c.Name, err)
+		}
+		rules = append(rules, rule)
+	}
+	return rules, nil
+}
+
+func compileRule(c RuleConfig) (*declaredRule, error) {
+	if c.Severity == "" {
+		c.Severity = SeverityWarning
+	}
+	if !slices.Contains([]string{SeverityCritical, SeverityWarning, SeveritySuggestion}, c.Severity) {
+		return nil, fmt.Errorf("severity must be critical, warning, or suggestion, got %q", c.Severity)
+	}
+	if c.Type == "" {
+		c.Type = IssueTypeImprecise
+		if c.Kind == RuleKindRequiredSection {
+			c.Type = IssueTypeMissing
+		}
+	}
+	if !slices.Contains([]string{IssueTypeInaccuracy, IssueTypeMissing, IssueTypeImprecise, IssueTypeUnsupported}, c.Type) {
+		return nil, fmt.Errorf("type must be inaccuracy, missing, imprecise, or unsupported, got %q", c.Type)
+	}
+	for _, contextType := range c.ContextTypes {
+		if _, err := ParseContextType(contextType); err != nil {
+			return nil, err
+		}
+	}
+
+	rule := &declaredRule{RuleConfig: c}
+	var err error
+	switch c.Kind {
+	case RuleKindRegex:
+		if c.Pattern == "" {
+			return nil, fmt.Errorf("regex rules need a pattern")
+		}
+		if rule.pattern, err = regexp.Compile(c.Pattern); err != nil {
+			return nil, fmt.Errorf("invalid pattern: %w", err)
+		}
+	case RuleKindForbiddenPhrase:
+		if len(c.Phrases) == 0 {
+			return nil, fmt.Errorf("forbidden_phrase rules need phrases")
+		}
+		quoted := make([]string, len(c.Phrases))
+		for i, phrase := range c.Phrases {
+			quoted[i] = regexp.QuoteMeta(phrase)
+		}
+		rule.pattern = regexp.MustCompile(`(?i)` + strings.Join(quoted, "|"))
+	case RuleKindRequiredSection:
+		if strings.TrimSpace(c.Section) == "" {
+			return nil, fmt.Errorf("required_section rules need a section")
+		}
+	default:
+		return nil, fmt.Errorf("kind must be one of %v, got %q", RuleKinds, c.Kind)
+	}
+	if c.Trigger != "" {
+		if rule.trigger, err = regexp.Compile(c.Trigger); err != nil {
+			return nil, fmt.Errorf("invalid trigger: %w", err)
+		}
+	}
+	return rule, nil
+}
+
+func (r *declaredRule) Name() string {
+	return r.RuleConfig.Name
+}
+
+func (r *declaredRule) Check(doc Document) []ValidationError {
+	if len(r.ContextTypes) > 0 && !slices.Contains(r.ContextTypes, doc.ContextType) {
+		return nil
+	}
+	if len(r.SpecVersions) > 0 && !slices.Contains(r.SpecVersions, doc.SpecVersion) {
+		return nil
+	}
+	if r.trigger != nil && !r.trigger.MatchString(doc.Content) {
+		return nil
+	}
+	if r.Kind == RuleKindRequiredSection {
+		return r.checkSection(doc.Content)
+	}
+	return r.checkMatches(doc.Content)
+}
+
+// checkSection reports content without the required heading
+func (r *declaredRule) checkSection(content string) []ValidationError {
+	fence := ""
+	for _, line := range strings.Split(content, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if fence != "" {
+			if strings.HasPrefix(trimmed, fence) && strings.Trim(trimmed, fence[:1]) == "" {
+				fence = ""
+			}
+			continue
+		}
+		if fence = codeFence(trimmed); fence != "" {
+			continue
+		}
+		if level := headingLevel(trimmed); level > 0 && strings.EqualFold(strings.TrimSpace(trimmed[level:]), strings.TrimSpace(r.Section)) {
+			return nil
+		}
+	}
+	message := r.Message
+	if message == "" {
+		message = fmt.Sprintf("Content has no %q section", r.Section)
+	}
+	return []ValidationError{*r.finding(message).WithExpected(r.Section)}
+}
+
+// checkMatches reports each distinct match of the rule's pattern at its first line
+func (r *declaredRule) checkMatches(content string) []ValidationError {
+	type occurrence struct {
+		found string
+		line  int
+		count int
+	}
+	var order []string
+	occurrences := map[string]*occurrence{}
+
+	fence := ""
+	for n, line := range strings.Split(content, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if fence != "" {
+			if strings.HasPrefix(trimmed, fence) && strings.Trim(trimmed, fence[:1]) == "" {
+				fence = ""
+			}
+			continue
+		}
+		if fence = codeFence(trimmed); fence != "" {
+			continue
+		}
+		for _, found := range r.pattern.FindAllString(line, -1) {
+			key := strings.ToLower(found)
+			if o, ok := occurrences[key]; ok {
+				o.count++
+				continue
+			}
+			order = append(order, key)
+			occurrences[key] = &occurrence{found: found, line: n + 1, count: 1}
+		}
+	}
+
+	findings := make([]ValidationError, 0, len(order))
+	for _, key := range order {
+		o := occurrences[key]
+		message := r.Message
+		switch {
+		case message != "":
+		case r.Kind == RuleKindForbiddenPhrase:
+			message = fmt.Sprintf("%q is a forbidden phrase", o.found)
+		default:
+			message = fmt.Sprintf("%q matches rule %s", o.found, r.RuleConfig.Name)
+		}
+		if o.count > 1 {
+			message += fmt.Sprintf(" (%d occurrences)", o.count)
+		}
+		findings = append(findings, *r.finding(message).WithFound(o.found).WithLineNumber(o.line))
+	}
+	return findings
+}
+
+func (r *declaredRule) finding(message string) *ValidationError {
+	finding := NewValidationError(r.Type, r.Severity, message).WithSpecSection(r.SpecSection)
+	if r.Suggestion != "" {
+		finding.AddSuggestion(r.Suggestion)
+	}
+	return finding
+}
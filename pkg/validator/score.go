@@ -0,0 +1,151 @@
+package validator
+
+import (
+	"math"
+	"strings"
+)
+
+// Scoring categories. A finding belongs to the first category whose keywords appear in
+// its spec section or message; findings matching none count toward CategoryGeneral.
+const (
+	CategoryTerminology  = "terminology"
+	CategoryProtocolFlow = "protocol_flow"
+	CategoryTransport    = "transport"
+	CategorySecurity     = "security"
+	CategoryGeneral      = "general"
+)
+
+// ScoreCategories lists the categories of a score's breakdown, in order
+var ScoreCategories = []string{CategoryTerminology, CategoryProtocolFlow, CategoryTransport, CategorySecurity, CategoryGeneral}
+
+// categoryKeywords decide the category of a finding. Terminology comes first, since
+// a wording finding is about the wording whichever section it cites, and security
+// before transport, so authorization over HTTP counts as security.
+var categoryKeywords = []struct {
+	category string
+	keywords []string
+}{
+	{CategoryTerminology, []string{"not the spec's term", "terminology"}},
+	{CategorySecurity, []string{"security", "authorization", "oauth", "token", "consent", "trust"}},
+	{CategoryTransport, []string{"transport", "stdio", "http", "sse", "session"}},
+	{CategoryProtocolFlow, []string{"lifecycle", "initializ", "version negotiation", "capabilit", "json-rpc", "message", "request", "notification", "cancel", "ping", "progress", "sampling", "roots"}},
+}
+
+// Rubric weights. The score blends how closely the content matches the spec with how
+// many findings it has, weighted by severity.
+const (
+	confidenceWeight  = 0.4 // Share of the score from match confidence; the rest comes from findings
+	criticalPenalty   = 20  // Points a critical finding takes off its category and the findings component
+	warningPenalty    = 8
+	suggestionPenalty = 2
+)
+
+// gradeFloors are the lowest scores of each grade, best first
+var gradeFloors = []struct {
+	grade string
+	floor int
+}{
+	{"A", 90}, {"B", 80}, {"C", 70}, {"D", 60}, {"F", 0},
+}
+
+// Score rates a validation result from 0 to 100 and grades it A through F
+type Score struct {
+	Score      int                      `json:"score"`
+	Grade      string                   `json:"grade"`
+	Confidence int                      `json:"confidence"` // Match confidence component, 0-100
+	Categories map[string]CategoryScore `json:"categories"` // Breakdown by category, each 0-100
+}
+
+// CategoryScore is the part of a score due to findings of one category
+type CategoryScore struct {
+	Score    int `json:"score"`
+	Findings int `json:"findings"`
+}
+
+// ScoreResult scores a validation result. Chunk confidences, or the overall confidence
+// when the content was validated whole, are scaled so that low_similarity_threshold
+// scores 0 and similarity_threshold scores 100. Findings take severity penalties off
+// 100, overall and for their category.
+func ScoreResult(result AggregatedValidationResult, settings Settings) *Score {
+	confidence := result.Overall.Confidence
+	if result.ChunkResults != nil {
+		var sum float64
+		var n int
+		for _, cr := range result.ChunkResults {
+			if cr.Error == "" {
+				sum += cr.Validation.Confidence
+				n++
+			}
+		}
+		if n > 0 {
+			confidence = sum / float64(n)
+		}
+	}
+	confidenceScore := 1.0
+	if span := settings.SimilarityThreshold - settings.LowSimilarityThreshold; span > 0 {
+		confidenceScore = clamp((confidence-settings.LowSimilarityThreshold)/span, 0, 1)
+	}
+
+	penalties := map[string]int{}
+	counts := map[string]int{}
+	total := 0
+	for _, finding := range result.Findings() {
+		category := FindingCategory(finding)
+		penalty := severityPenalty(finding.Severity)
+		penalties[category] += penalty
+		counts[category]++
+		total += penalty
+	}
+
+	categories := make(map[string]CategoryScore, len(ScoreCategories))
+	for _, category := range ScoreCategories {
+		categories[category] = CategoryScore{Score: max(0, 100-penalties[category]), Findings: counts[category]}
+	}
+	findingsScore := float64(max(0, 100-total)) / 100
+	score := int(math.Round(100 * (confidenceWeight*confidenceScore + (1-confidenceWeight)*findingsScore)))
+	return &Score{
+		Score:      score,
+		Grade:      Grade(score),
+		Confidence: int(math.Round(100 * confidenceScore)),
+		Categories: categories,
+	}
+}
+
+// FindingCategory returns the scoring category of a finding
+func FindingCategory(f ValidationError) string {
+	text := strings.ToLower(f.SpecSection + " " + f.Message)
+	for _, c := range categoryKeywords {
+		for _, keyword := range c.keywords {
+			if strings.Contains(text, keyword) {
+				return c.category
+			}
+		}
+	}
+	return CategoryGeneral
+}
+
+// Grade returns the letter grade of a 0-100 score
+func Grade(score int) string {
+	for _, g := range gradeFloors {
+		if score >= g.floor {
+			return g.grade
+		}
+	}
+	return "F"
+}
+
+func severityPenalty(severity string) int {
+	switch severity {
+	case SeverityCritical:
+		return criticalPenalty
+	case SeverityWarning:
+		return warningPenalty
+	case SeveritySuggestion:
+		return suggestionPenalty
+	}
+	return 0
+}
+
+func clamp(v, lo, hi float64) float64 {
+	return math.Max(lo, math.Min(hi, v))
+}
@@ -0,0 +1,199 @@
+package validator
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/carlisia/mcp-factcheck/pkg/tokens"
+	"github.com/tmc/langchaingo/textsplitter"
+)
+
+// markdownBlock is a run of lines split as a unit: a heading, a fenced code block,
+// or a paragraph, list, table, or quote ending at a blank line
+type markdownBlock struct {
+	kind       string
+	level      int // Heading level, for headings
+	start, end int // 0-based line range, end exclusive
+	tokens     int
+}
+
+// listItem matches the first line of a bulleted or numbered list item
+var listItem = regexp.MustCompile(`^([-*+]|\d+[.)])\s`)
+
+// codeFence returns the backticks or tildes opening a fenced code block on a trimmed line, if any
+func codeFence(line string) string {
+	for _, c := range []string{"`", "~"} {
+		if strings.HasPrefix(line, strings.Repeat(c, 3)) {
+			return strings.Repeat(c, len(line)-len(strings.TrimLeft(line, c)))
+		}
+	}
+	return ""
+}
+
+// headingLevel returns the level of an ATX heading on a trimmed line, or 0
+func headingLevel(line string) int {
+	level := len(line) - len(strings.TrimLeft(line, "#"))
+	if level == 0 || level > 6 || (level < len(line) && line[level] != ' ' && line[level] != '\t') {
+		return 0
+	}
+	return level
+}
+
+// parseBlocks splits markdown lines into blocks. An unclosed code fence runs to the end.
+func parseBlocks(lines []string) []markdownBlock {
+	var blocks []markdownBlock
+	for i := 0; i < len(lines); {
+		trimmed := strings.TrimSpace(lines[i])
+		switch {
+		case trimmed == "":
+			i++
+		case codeFence(trimmed) != "":
+			marker := codeFence(trimmed)
+			end := i + 1
+			for end < len(lines) {
+				line := strings.TrimSpace(lines[end])
+				end++
+				if strings.HasPrefix(line, marker) && strings.Trim(line, marker[:1]) == "" {
+					break
+				}
+			}
+			blocks = append(blocks, markdownBlock{kind: "code_block", start: i, end: end})
+			i = end
+		case headingLevel(trimmed) > 0:
+			blocks = append(blocks, markdownBlock{kind: "heading", level: headingLevel(trimmed), start: i, end: i + 1})
+			i++
+		default:
+			end := i + 1
+			for end < len(lines) {
+				line := strings.TrimSpace(lines[end])
+				if line == "" || codeFence(line) != "" || headingLevel(line) > 0 {
+					break
+				}
+				end++
+			}
+			kind := "paragraph"
+			switch {
+			case listItem.MatchString(trimmed):
+				kind = "list"
+			case strings.HasPrefix(trimmed, "|"):
+				kind = "table"
+			case strings.HasPrefix(trimmed, ">"):
+				kind = "quote"
+			}
+			blocks = append(blocks, markdownBlock{kind: kind, start: i, end: end})
+			i = end
+		}
+	}
+	for i := range blocks {
+		blocks[i].tokens = tokens.Count(strings.Join(lines[blocks[i].start:blocks[i].end], "\n"))
+	}
+	return blocks
+}
+
+// splitSections splits markdown into one chunk per heading and its body. Sections
+// longer than opts.Size are split between blocks; code blocks are never split, and
+// other blocks are split by size only when one alone is too long. A heading with no
+// body of its own stays with the section after it. Sections don't overlap.
+func splitSections(content string, opts ChunkOptions) []ContentChunk {
+	lines := strings.Split(content, "\n")
+
+	var sections [][]markdownBlock
+	var current []markdownBlock
+	hasBody := false
+	for _, block := range parseBlocks(lines) {
+		if block.kind == "heading" && hasBody {
+			sections = append(sections, current)
+			current, hasBody = nil, false
+		}
+		current = append(current, block)
+		hasBody = hasBody || block.kind != "heading"
+	}
+	if len(current) > 0 {
+		sections = append(sections, current)
+	}
+
+	var chunks []ContentChunk
+	for _, section := range sections {
+		var group []markdownBlock
+		groupTokens := 0
+		onlyHeadings := true
+		emit := func() {
+			if len(group) > 0 {
+				chunks = append(chunks, blockChunk(lines, group))
+			}
+			group, groupTokens, onlyHeadings = nil, 0, true
+		}
+
+		for _, block := range section {
+			if block.tokens > opts.Size && block.kind != "code_block" && block.kind != "heading" {
+				// Headings waiting for this block still label its pieces through Heading
+				if !onlyHeadings {
+					emit()
+				}
+				group, groupTokens, onlyHeadings = nil, 0, true
+				chunks = append(chunks, splitBlock(lines, block, opts)...)
+				continue
+			}
+			if groupTokens+block.tokens > opts.Size && !onlyHeadings {
+				emit()
+			}
+			group = append(group, block)
+			groupTokens += block.tokens
+			onlyHeadings = onlyHeadings && block.kind == "heading"
+		}
+		emit()
+	}
+	return chunks
+}
+
+// blockChunk makes a chunk of consecutive blocks. Chunks starting at a heading are
+// sections of that heading's level; others take the kind of their blocks.
+func blockChunk(lines []string, group []markdownBlock) ContentChunk {
+	first, last := group[0], group[len(group)-1]
+	chunk := ContentChunk{
+		Text:      strings.Join(lines[first.start:last.end], "\n"),
+		Type:      first.kind,
+		StartLine: first.start + 1,
+		EndLine:   last.end,
+	}
+	if first.kind == "heading" {
+		chunk.Type, chunk.Level = "section", first.level
+	}
+	for _, block := range group[1:] {
+		if block.kind != first.kind {
+			chunk.Type = "section"
+		}
+	}
+	return chunk
+}
+
+// splitBlock splits a block too long for one chunk by size, anchoring the pieces
+// to the block's lines
+func splitBlock(lines []string, block markdownBlock, opts ChunkOptions) []ContentChunk {
+	text := strings.Join(lines[block.start:block.end], "\n")
+	docs, err := textsplitter.NewRecursiveCharacter(
+		textsplitter.WithChunkSize(opts.Size),
+		textsplitter.WithChunkOverlap(opts.Overlap),
+		textsplitter.WithLenFunc(tokens.Count),
+	).SplitText(text)
+	if err != nil {
+		docs = []string{text}
+	}
+
+	pieces := make([]ContentChunk, 0, len(docs))
+	offset := 0
+	for _, doc := range docs {
+		doc = strings.TrimSpace(doc)
+		piece := ContentChunk{Text: doc, Type: block.kind}
+		var line int
+		if line, offset = locateChunk(text, doc, offset); line > 0 {
+			piece.StartLine = block.start + line
+			piece.EndLine = piece.StartLine
+			if end := locateChunkEnd(text, doc, offset-1); end > 0 {
+				piece.EndLine = block.start + end
+			}
+		}
+		pieces = append(pieces, piece)
+	}
+	return pieces
+}
@@ -0,0 +1,221 @@
+package validator
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"sync/atomic"
+
+	"github.com/carlisia/mcp-factcheck/embedding"
+)
+
+// Settings holds validator tuning knobs that can be changed at runtime
+type Settings struct {
+	SimilarityThreshold    float64 `json:"similarity_threshold"`     // Average similarity above which content is considered valid
+	LowSimilarityThreshold float64 `json:"low_similarity_threshold"` // Average similarity below which content is flagged as low similarity
+	TopK                   int     `json:"top_k"`                    // Spec matches retrieved for single validation
+	ChunkTopK              int     `json:"chunk_top_k"`              // Spec matches retrieved per chunk
+	ChunkStrategy          string  `json:"chunk_strategy"`           // How content is split: auto, markdown, recursive, sentence, or token
+	ChunkSize              int     `json:"chunk_size"`               // Maximum tokens per chunk
+	ChunkOverlap           int     `json:"chunk_overlap"`            // Tokens shared between adjacent chunks
+	AutoChunkLength        int     `json:"auto_chunk_length"`        // Content longer than this is always chunked
+	ChunkWorkers           int     `json:"chunk_workers"`            // Chunks of one document validated concurrently
+	BatchWorkers           int     `json:"batch_workers"`            // Documents of one validate_batch call validated concurrently
+	KeywordWeight          float64 `json:"keyword_weight"`           // Share of BM25 keyword relevance in search ranking; 0 ranks by similarity alone
+	Rerank                 bool    `json:"rerank"`                   // Rerank search candidates with the chat model before validating
+	CoverageThreshold      float64 `json:"coverage_threshold"`       // Similarity to a spec requirement at which content counts as addressing it
+
+	Tools  map[string]ToolSettings  `json:"tools,omitempty"`  // Per-tool overrides, keyed by tool name
+	Models map[string]ModelSettings `json:"models,omitempty"` // Per-embedding-model overrides, keyed by model name
+	Rules  []RuleConfig             `json:"rules,omitempty"`  // Custom rules run on prose alongside the spec checks
+}
+
+// ToolSettings overrides the shared thresholds for one tool. Zero fields inherit the shared value.
+type ToolSettings struct {
+	SimilarityThreshold    float64 `json:"similarity_threshold,omitempty"`
+	LowSimilarityThreshold float64 `json:"low_similarity_threshold,omitempty"`
+	TopK                   int     `json:"top_k,omitempty"`
+}
+
+// ModelSettings overrides the similarity thresholds while queries are embedded with one
+// model, since each model spreads similarities over its own range. Zero fields inherit
+// the shared value. Its tool overrides replace the shared tools' thresholds, which are
+// tuned for another model.
+type ModelSettings struct {
+	SimilarityThreshold    float64                 `json:"similarity_threshold,omitempty"`
+	LowSimilarityThreshold float64                 `json:"low_similarity_threshold,omitempty"`
+	CoverageThreshold      float64                 `json:"coverage_threshold,omitempty"`
+	Tools                  map[string]ToolSettings `json:"tools,omitempty"`
+}
+
+// TunableTools lists the tools that accept per-tool overrides
+var TunableTools = []string{ValidateContentToolName, ValidateCodeToolName}
+
+// DefaultSettings returns the built-in validator settings
+func DefaultSettings() Settings {
+	return Settings{
+		SimilarityThreshold:    0.7,
+		LowSimilarityThreshold: 0.5,
+		TopK:                   5,
+		ChunkTopK:              3,
+		ChunkStrategy:          ChunkStrategyAuto,
+		ChunkSize:              200,
+		ChunkOverlap:           25,
+		AutoChunkLength:        500,
+		ChunkWorkers:           4,
+		BatchWorkers:           4,
+		KeywordWeight:          0.3,
+		CoverageThreshold:      0.8,
+		Tools: map[string]ToolSettings{
+			// Code is compared through a pattern summary, which matches the spec less closely than prose
+			ValidateCodeToolName: {SimilarityThreshold: 0.6, LowSimilarityThreshold: 0.5, TopK: 8},
+		},
+		Models: map[string]ModelSettings{
+			// The local model only measures shared vocabulary. Against the spec, prose
+			// about MCP scores about 0.2 to 0.3 and unrelated prose about 0.16; text
+			// addressing a requirement about 0.27; MCP code about 0.04 to 0.06 and other
+			// code near 0.
+			embedding.LocalModel: {
+				SimilarityThreshold:    0.19,
+				LowSimilarityThreshold: 0.17,
+				CoverageThreshold:      0.25,
+				Tools: map[string]ToolSettings{
+					ValidateCodeToolName: {SimilarityThreshold: 0.03, LowSimilarityThreshold: 0.02},
+				},
+			},
+		},
+	}
+}
+
+// ForTool returns the settings in effect for one tool, with its overrides applied
+func (s Settings) ForTool(name string) Settings {
+	t := s.Tools[name]
+	s.Tools = nil
+	if t.SimilarityThreshold != 0 {
+		s.SimilarityThreshold = t.SimilarityThreshold
+	}
+	if t.LowSimilarityThreshold != 0 {
+		s.LowSimilarityThreshold = t.LowSimilarityThreshold
+	}
+	if t.TopK != 0 {
+		s.TopK = t.TopK
+	}
+	return s
+}
+
+// ForModel returns the settings in effect while queries are embedded with model, with
+// its overrides applied
+func (s Settings) ForModel(model string) Settings {
+	m, ok := s.Models[model]
+	s.Models = nil
+	if !ok {
+		return s
+	}
+	if m.SimilarityThreshold != 0 {
+		s.SimilarityThreshold = m.SimilarityThreshold
+	}
+	if m.LowSimilarityThreshold != 0 {
+		s.LowSimilarityThreshold = m.LowSimilarityThreshold
+	}
+	if m.CoverageThreshold != 0 {
+		s.CoverageThreshold = m.CoverageThreshold
+	}
+	tools := make(map[string]ToolSettings, len(s.Tools))
+	for name, t := range s.Tools {
+		tools[name] = ToolSettings{TopK: t.TopK}
+	}
+	for name, t := range m.Tools {
+		if t.TopK == 0 {
+			t.TopK = tools[name].TopK
+		}
+		tools[name] = t
+	}
+	s.Tools = tools
+	return s
+}
+
+// Validate checks that settings are internally consistent
+func (s Settings) Validate() error {
+	if s.SimilarityThreshold <= 0 || s.SimilarityThreshold > 1 {
+		return fmt.Errorf("similarity_threshold must be in (0, 1], got %v", s.SimilarityThreshold)
+	}
+	if s.LowSimilarityThreshold < 0 || s.LowSimilarityThreshold > s.SimilarityThreshold {
+		return fmt.Errorf("low_similarity_threshold must be in [0, similarity_threshold], got %v", s.LowSimilarityThreshold)
+	}
+	if s.TopK < 1 || s.ChunkTopK < 1 {
+		return fmt.Errorf("top_k and chunk_top_k must be at least 1")
+	}
+	if s.ChunkSize < 1 {
+		return fmt.Errorf("chunk_size must be at least 1, got %d", s.ChunkSize)
+	}
+	if s.ChunkOverlap < 0 || s.ChunkOverlap >= s.ChunkSize {
+		return fmt.Errorf("chunk_overlap must be in [0, chunk_size), got %d", s.ChunkOverlap)
+	}
+	if s.ChunkStrategy != "" && !slices.Contains(ChunkStrategies, s.ChunkStrategy) {
+		return fmt.Errorf("chunk_strategy must be one of %v, got %q", ChunkStrategies, s.ChunkStrategy)
+	}
+	if s.ChunkSize > maxChunkSize {
+		return fmt.Errorf("chunk_size must be at most %d, got %d", maxChunkSize, s.ChunkSize)
+	}
+	if s.ChunkWorkers < 1 {
+		return fmt.Errorf("chunk_workers must be at least 1, got %d", s.ChunkWorkers)
+	}
+	if s.BatchWorkers < 1 {
+		return fmt.Errorf("batch_workers must be at least 1, got %d", s.BatchWorkers)
+	}
+	if s.KeywordWeight < 0 || s.KeywordWeight > 1 {
+		return fmt.Errorf("keyword_weight must be in [0, 1], got %v", s.KeywordWeight)
+	}
+	if s.CoverageThreshold <= 0 || s.CoverageThreshold > 1 {
+		return fmt.Errorf("coverage_threshold must be in (0, 1], got %v", s.CoverageThreshold)
+	}
+	if _, err := compileRules(s.Rules); err != nil {
+		return fmt.Errorf("rules: %w", err)
+	}
+	for name := range s.Tools {
+		if !slices.Contains(TunableTools, name) {
+			return fmt.Errorf("tools: unknown tool %q (valid: %v)", name, TunableTools)
+		}
+		if err := s.ForTool(name).Validate(); err != nil {
+			return fmt.Errorf("tools.%s: %w", name, err)
+		}
+	}
+	for _, model := range slices.Sorted(maps.Keys(s.Models)) {
+		if !slices.Contains(embedding.Models, model) {
+			return fmt.Errorf("models: unknown embedding model %q (valid: %v)", model, embedding.Models)
+		}
+		if err := s.ForModel(model).Validate(); err != nil {
+			return fmt.Errorf("models.%s: %w", model, err)
+		}
+	}
+	return nil
+}
+
+var currentSettings atomic.Pointer[Settings]
+
+func init() {
+	defaults := DefaultSettings()
+	currentSettings.Store(&defaults)
+}
+
+// CurrentSettings returns the settings in effect
+func CurrentSettings() Settings {
+	return *currentSettings.Load()
+}
+
+// ToolSettingsFor returns the current settings for one tool, under the embedding model
+// in use
+func ToolSettingsFor(name string) Settings {
+	return CurrentSettings().ForModel(embedding.CurrentModel().Name).ForTool(name)
+}
+
+// SetSettings atomically replaces the settings used by subsequent validations
+func SetSettings(s Settings) error {
+	if err := s.Validate(); err != nil {
+		return err
+	}
+	rules, _ := compileRules(s.Rules)
+	currentSettings.Store(&s)
+	declaredRules.Store(&rules)
+	return nil
+}
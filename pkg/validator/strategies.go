@@ -0,0 +1,258 @@
+package validator
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/carlisia/mcp-factcheck/pkg/tokens"
+	"github.com/tmc/langchaingo/textsplitter"
+)
+
+// Chunking strategies for splitting content before chunked validation
+const (
+	ChunkStrategyAuto      = "auto"      // section for markdown-like content, recursive otherwise
+	ChunkStrategySection   = "section"   // One chunk per heading and its body, never splitting code blocks
+	ChunkStrategyMarkdown  = "markdown"  // Split at headings, keeping code blocks and lists together
+	ChunkStrategyRecursive = "recursive" // Split at paragraphs, then lines, then words
+	ChunkStrategySentence  = "sentence"  // Pack whole sentences into each chunk
+	ChunkStrategyToken     = "token"     // Fixed windows of tokens, ignoring structure
+)
+
+// ChunkStrategies lists the valid chunking strategies
+var ChunkStrategies = []string{ChunkStrategyAuto, ChunkStrategySection, ChunkStrategyMarkdown, ChunkStrategyRecursive, ChunkStrategySentence, ChunkStrategyToken}
+
+// maxChunkSize is the largest chunk the embedding model accepts, in tokens
+const maxChunkSize = 8191
+
+// ChunkOptions chooses how content is split. Zero fields use the current settings.
+type ChunkOptions struct {
+	Strategy string `json:"strategy"`
+	Size     int    `json:"chunk_size"`    // Maximum tokens per chunk
+	Overlap  int    `json:"chunk_overlap"` // Tokens shared between adjacent chunks
+}
+
+// withDefaults fills the unset fields from settings
+func (o ChunkOptions) withDefaults(settings Settings) ChunkOptions {
+	if o.Strategy == "" {
+		o.Strategy = settings.ChunkStrategy
+	}
+	if o.Size == 0 {
+		o.Size = settings.ChunkSize
+		if o.Overlap == 0 {
+			o.Overlap = settings.ChunkOverlap
+		}
+	}
+	return o
+}
+
+// Validate checks that the options are usable
+func (o ChunkOptions) Validate() error {
+	if o.Strategy != "" && !slices.Contains(ChunkStrategies, o.Strategy) {
+		return fmt.Errorf("unknown chunk strategy %q (valid: %v)", o.Strategy, ChunkStrategies)
+	}
+	if o.Size < 0 || o.Size > maxChunkSize {
+		return fmt.Errorf("chunk size must be in [1, %d], got %d", maxChunkSize, o.Size)
+	}
+	if o.Overlap < 0 || (o.Size > 0 && o.Overlap >= o.Size) {
+		return fmt.Errorf("chunk overlap must be at least 0 and less than the chunk size, got %d", o.Overlap)
+	}
+	return nil
+}
+
+type chunkingKey struct{}
+
+// WithChunking makes chunked validations run with ctx split content with opts
+// instead of the configured strategy and sizes
+func WithChunking(ctx context.Context, opts ChunkOptions) context.Context {
+	return context.WithValue(ctx, chunkingKey{}, opts)
+}
+
+func chunkingFrom(ctx context.Context) ChunkOptions {
+	opts, _ := ctx.Value(chunkingKey{}).(ChunkOptions)
+	return opts
+}
+
+// chunkingSchema returns the tool argument schemas for choosing a chunking strategy
+func chunkingSchema() map[string]any {
+	return map[string]any{
+		"chunkStrategy": map[string]any{
+			"type":        "string",
+			"description": "How long content is split for chunked validation: section keeps each heading with its body and never splits code blocks, markdown splits at headings to fill chunks, recursive at paragraphs then lines, sentence packs whole sentences, token cuts fixed token windows. auto picks section or recursive from the content. Defaults to the server's setting",
+			"enum":        ChunkStrategies,
+		},
+		"chunkSize": map[string]any{
+			"type":        "integer",
+			"description": "Maximum tokens per chunk. Defaults to the server's setting",
+			"minimum":     1,
+			"maximum":     maxChunkSize,
+		},
+		"chunkOverlap": map[string]any{
+			"type":        "integer",
+			"description": "Tokens shared between adjacent chunks; must be less than chunkSize. Defaults to the server's setting",
+			"minimum":     0,
+		},
+	}
+}
+
+// chunkingArgs reads the chunkStrategy, chunkSize, and chunkOverlap arguments into ctx
+func chunkingArgs(ctx context.Context, params map[string]any) (context.Context, error) {
+	opts, err := chunkOptionsArgs(params)
+	if err != nil {
+		return nil, err
+	}
+	if opts == (ChunkOptions{}) {
+		return ctx, nil
+	}
+
+	// An overlap given without a size is checked against the configured size
+	if err := opts.withDefaults(ToolSettingsFor(ValidateContentToolName)).Validate(); err != nil {
+		return nil, err
+	}
+	return WithChunking(ctx, opts), nil
+}
+
+// chunkOptionsArgs reads the chunkStrategy, chunkSize, and chunkOverlap arguments,
+// leaving the fields of missing ones unset
+func chunkOptionsArgs(params map[string]any) (ChunkOptions, error) {
+	var opts ChunkOptions
+	opts.Strategy, _ = params["chunkStrategy"].(string)
+	if size, ok := params["chunkSize"].(float64); ok {
+		if size < 1 {
+			return ChunkOptions{}, fmt.Errorf("chunkSize must be at least 1")
+		}
+		opts.Size = int(size)
+	}
+	if overlap, ok := params["chunkOverlap"].(float64); ok {
+		opts.Overlap = int(overlap)
+	}
+	return opts, nil
+}
+
+// looksLikeMarkdown reports whether content has markdown structure worth splitting on
+func looksLikeMarkdown(content string) bool {
+	return strings.Contains(content, "#") || strings.Contains(content, "```") ||
+		strings.Contains(content, "- ") || strings.Contains(content, "* ")
+}
+
+// splitContent splits content with resolved options, returning the pieces and the
+// concrete strategy used. Pieces without a StartLine are located in the content by
+// the caller.
+func splitContent(content string, opts ChunkOptions) ([]ContentChunk, string) {
+	strategy := opts.Strategy
+	if strategy == ChunkStrategyAuto || strategy == "" {
+		strategy = ChunkStrategyRecursive
+		if looksLikeMarkdown(content) {
+			strategy = ChunkStrategySection
+		}
+	}
+	if strategy == ChunkStrategySection {
+		return splitSections(content, opts), strategy
+	}
+
+	sizing := []textsplitter.Option{
+		textsplitter.WithChunkSize(opts.Size),
+		textsplitter.WithChunkOverlap(opts.Overlap),
+		textsplitter.WithLenFunc(tokens.Count), // Sizes are in embedding model tokens
+	}
+
+	var docs []string
+	var err error
+	switch strategy {
+	case ChunkStrategyMarkdown:
+		docs, err = textsplitter.NewMarkdownTextSplitter(sizing...).SplitText(content)
+	case ChunkStrategySentence:
+		docs = splitSentences(content, opts)
+	case ChunkStrategyToken:
+		docs = tokens.Split(content, opts.Size, opts.Overlap)
+	default:
+		docs, err = textsplitter.NewRecursiveCharacter(sizing...).SplitText(content)
+	}
+	if err != nil {
+		// Fallback to simple splitting if the splitter fails
+		docs = []string{content}
+	}
+	pieces := make([]ContentChunk, len(docs))
+	for i, doc := range docs {
+		pieces[i] = ContentChunk{Text: doc, Type: "text_chunk"}
+	}
+	return pieces, strategy
+}
+
+// sentenceEnd matches the whitespace after a sentence-ending punctuation mark, or a
+// paragraph break
+var sentenceEnd = regexp.MustCompile(`[.!?]["')\]]*\s+|\n[ \t]*\n\s*`)
+
+// span is a sentence's byte range in the content
+type span struct {
+	start, end int
+	tokens     int
+}
+
+// splitSentences packs whole sentences into chunks of at most opts.Size tokens.
+// Chunks are cut from the content as written, adjacent chunks share trailing
+// sentences totalling at most opts.Overlap tokens, and sentences too long for one
+// chunk are split recursively.
+func splitSentences(content string, opts ChunkOptions) []string {
+	var sentences []span
+	addSentence := func(start, end int) {
+		text := content[start:end]
+		start += len(text) - len(strings.TrimLeft(text, " \t\r\n"))
+		end -= len(text) - len(strings.TrimRight(text, " \t\r\n"))
+		if start < end {
+			sentences = append(sentences, span{start, end, tokens.Count(content[start:end])})
+		}
+	}
+	start := 0
+	for _, loc := range sentenceEnd.FindAllStringIndex(content, -1) {
+		addSentence(start, loc[1])
+		start = loc[1]
+	}
+	addSentence(start, len(content))
+
+	var chunks []string
+	var current []span
+	currentTokens := 0
+	flush := func() {
+		if len(current) == 0 {
+			return
+		}
+		chunks = append(chunks, content[current[0].start:current[len(current)-1].end])
+		// Carry trailing sentences into the next chunk as overlap
+		keep := len(current)
+		carriedTokens := 0
+		for keep > 1 && carriedTokens+current[keep-1].tokens <= opts.Overlap {
+			keep--
+			carriedTokens += current[keep].tokens
+		}
+		current, currentTokens = current[keep:], carriedTokens
+	}
+
+	for _, sentence := range sentences {
+		if sentence.tokens > opts.Size {
+			flush()
+			current, currentTokens = nil, 0
+			pieces, _ := textsplitter.NewRecursiveCharacter(
+				textsplitter.WithChunkSize(opts.Size),
+				textsplitter.WithChunkOverlap(opts.Overlap),
+				textsplitter.WithLenFunc(tokens.Count),
+			).SplitText(content[sentence.start:sentence.end])
+			chunks = append(chunks, pieces...)
+			continue
+		}
+		if currentTokens+sentence.tokens > opts.Size {
+			flush()
+			// Drop overlap that would leave no room for the sentence
+			for len(current) > 0 && currentTokens+sentence.tokens > opts.Size {
+				currentTokens -= current[0].tokens
+				current = current[1:]
+			}
+		}
+		current = append(current, sentence)
+		currentTokens += sentence.tokens
+	}
+	flush()
+	return chunks
+}
@@ -0,0 +1,285 @@
+package validator
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/carlisia/mcp-factcheck/internal/specs"
+	"github.com/carlisia/mcp-factcheck/internal/toolerr"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+const CheckTerminologyToolName = "check_terminology"
+
+// term is a terminology dictionary entry: wording the spec doesn't use, and what it
+// uses instead
+type term struct {
+	pattern   *regexp.Regexp
+	canonical string // Replacement, which may refer to pattern's groups as $1 and the spec version as {version}; empty when there is none
+	issue     string // Issue type of findings; IssueTypeImprecise when empty
+	severity  string
+	reason    string
+	section   string // Spec section findings cite, a key of sectionPages when it has a page
+	since     string // First spec version the entry applies to; empty for all
+	until     string // First spec version it no longer applies to; empty for none
+}
+
+// appliesTo reports whether the entry is checked in specVersion
+func (t term) appliesTo(specVersion string) bool {
+	return (t.since == "" || !versionBefore(specVersion, t.since)) &&
+		(t.until == "" || versionBefore(specVersion, t.until))
+}
+
+// versionBefore reports whether spec version a precedes b. Dated versions sort by
+// date, and the draft follows them all.
+func versionBefore(a, b string) bool {
+	switch {
+	case a == b || a == "draft":
+		return false
+	case b == "draft":
+		return true
+	}
+	return a < b
+}
+
+// terminology is the curated dictionary of non-canonical MCP terms, followed by the
+// features of other spec versions
+var terminology = append([]term{
+	{
+		pattern:   regexp.MustCompile(`(?i)\bMCP[- ]plug-?ins?\b`),
+		canonical: "MCP server",
+		severity:  SeverityWarning,
+		reason:    "The spec calls programs that expose tools, resources, and prompts to clients MCP servers",
+		section:   "Architecture",
+	},
+	{
+		pattern:   regexp.MustCompile(`(?i)\bMCP[- ]extensions?\b`),
+		canonical: "MCP server",
+		severity:  SeveritySuggestion,
+		reason:    "Capabilities are added to a host by connecting it to MCP servers, not by installing extensions",
+		section:   "Architecture",
+	},
+	{
+		pattern:   regexp.MustCompile(`(?i)\bfunction[- ]call(s|ing)?\b`),
+		canonical: "tool call",
+		severity:  SeveritySuggestion,
+		reason:    "Function calling is a model API feature; MCP clients invoke server tools with tools/call",
+		section:   "Server Features: Tools",
+	},
+	{
+		pattern:   regexp.MustCompile(`(?i)\bModel (Content|Contexts|Contextual) Protocol\b`),
+		canonical: "Model Context Protocol",
+		severity:  SeverityWarning,
+		reason:    "The protocol's name is Model Context Protocol",
+		section:   "Overview",
+	},
+	{
+		pattern:   regexp.MustCompile(`(?i)\bMCP (v|version ?)?\d+(\.\d+)+\b`),
+		canonical: "MCP {version}",
+		issue:     IssueTypeInaccuracy,
+		severity:  SeverityWarning,
+		reason:    "MCP protocol versions are dates, such as 2025-06-18, not semantic version numbers",
+		section:   "Lifecycle: Version Negotiation",
+	},
+	{
+		pattern:   regexp.MustCompile(`(?i)\bserver[- ]side events\b`),
+		canonical: "Server-Sent Events",
+		severity:  SeverityWarning,
+		reason:    "The HTTP transports stream messages as Server-Sent Events (SSE)",
+		section:   "Transports",
+	},
+	{
+		pattern:   regexp.MustCompile(`(?i)\bJSON RPC\b`),
+		canonical: "JSON-RPC",
+		severity:  SeveritySuggestion,
+		reason:    "MCP messages are JSON-RPC 2.0 messages",
+		section:   "Base Protocol: Messages",
+	},
+	{
+		pattern:   regexp.MustCompile(`(?i)\b(stdin/stdout|standard I/O|standard IO) transport\b`),
+		canonical: "stdio transport",
+		severity:  SeveritySuggestion,
+		reason:    "The spec names the transport over a subprocess's standard input and output stdio",
+		section:   "Transports",
+	},
+	{
+		pattern:   regexp.MustCompile(`\b(tools|resources|prompts)\.(list|call|read|get)\b`),
+		canonical: "$1/$2",
+		issue:     IssueTypeInaccuracy,
+		severity:  SeverityWarning,
+		reason:    "MCP method names separate the feature and the operation with a slash",
+		section:   "Base Protocol: Messages",
+	},
+	{
+		pattern:   regexp.MustCompile(`(?i)\b(streaming HTTP|HTTP streaming|streamable-HTTP) transport\b`),
+		canonical: "Streamable HTTP transport",
+		severity:  SeveritySuggestion,
+		reason:    "The spec names the HTTP transport introduced in 2025-03-26 Streamable HTTP",
+		section:   "Transports",
+		since:     "2025-03-26",
+	},
+}, featureTerms()...)
+
+// featureTerms returns entries flagging each feature under the spec versions without it
+func featureTerms() []term {
+	var terms []term
+	for _, f := range versionedFeatures {
+		entry := term{
+			pattern:  f.pattern,
+			issue:    IssueTypeUnsupported,
+			severity: SeverityWarning,
+			reason:   f.availability(),
+			section:  f.section,
+		}
+		if f.since != "" {
+			before := entry
+			before.until = f.since
+			terms = append(terms, before)
+		}
+		if f.until != "" {
+			after := entry
+			after.since = f.until
+			after.canonical = f.replacement
+			terms = append(terms, after)
+		}
+	}
+	return terms
+}
+
+// CheckTerminology flags wording in content that the spec version doesn't use, with
+// the canonical term where there is one. Each distinct wording is reported once, at
+// its first line. Fenced code blocks are skipped, since code may legitimately use
+// other names.
+func CheckTerminology(content, specVersion string) []ValidationError {
+	type occurrence struct {
+		entry int
+		found string
+		line  int
+		count int
+	}
+	var order []string
+	occurrences := map[string]*occurrence{}
+
+	fence := ""
+	for n, line := range strings.Split(content, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if fence != "" {
+			if strings.HasPrefix(trimmed, fence) && strings.Trim(trimmed, fence[:1]) == "" {
+				fence = ""
+			}
+			continue
+		}
+		if fence = codeFence(trimmed); fence != "" {
+			continue
+		}
+		for i, entry := range terminology {
+			if !entry.appliesTo(specVersion) {
+				continue
+			}
+			for _, found := range entry.pattern.FindAllString(line, -1) {
+				key := fmt.Sprintf("%d:%s", i, strings.ToLower(found))
+				if o, ok := occurrences[key]; ok {
+					o.count++
+					continue
+				}
+				order = append(order, key)
+				occurrences[key] = &occurrence{entry: i, found: found, line: n + 1, count: 1}
+			}
+		}
+	}
+
+	findings := make([]ValidationError, 0, len(order))
+	for _, key := range order {
+		o := occurrences[key]
+		entry := terminology[o.entry]
+		issue := entry.issue
+		if issue == "" {
+			issue = IssueTypeImprecise
+		}
+
+		var message string
+		var replacement string
+		if entry.canonical != "" {
+			canonical := strings.ReplaceAll(entry.canonical, "{version}", specVersion)
+			replacement = entry.pattern.ReplaceAllString(o.found, canonical)
+			message = fmt.Sprintf("%q is not the spec's term; use %q", o.found, replacement)
+		} else {
+			message = fmt.Sprintf("%q is not part of MCP %s", o.found, specVersion)
+		}
+		if o.count > 1 {
+			message += fmt.Sprintf(" (%d occurrences)", o.count)
+		}
+
+		finding := NewValidationError(issue, entry.severity, message).
+			WithFound(o.found).
+			WithExpected(replacement).
+			WithSpecSection(entry.section).
+			WithSpecURL(sectionURL(specVersion, entry.section)).
+			WithLineNumber(o.line).
+			AddSuggestion(entry.reason)
+		findings = append(findings, *finding)
+	}
+	SortBySeverity(findings)
+	return findings
+}
+
+// applyTerminology adds the terminology findings for content to result
+func applyTerminology(content, specVersion string, result *ValidationResult) {
+	result.Errors = append(result.Errors, CheckTerminology(content, specVersion)...)
+}
+
+func GetCheckTerminologyTool() mcp.Tool {
+	schema := map[string]any{
+		"type": "object",
+		"properties": map[string]any{
+			"content": map[string]any{
+				"type":        "string",
+				"description": "Text to check for non-canonical MCP terminology",
+			},
+			"specVersion": map[string]any{
+				"type":        "string",
+				"description": "MCP specification version whose terminology applies",
+				"enum":        specs.ValidSpecVersions,
+				"default":     specs.DefaultSpecVersion,
+			},
+		},
+		"required": []string{"content"},
+	}
+	schemaBytes, _ := json.Marshal(schema)
+
+	description := `Check text for MCP terminology the specification doesn't use, such as "MCP plugin" for "MCP server", "function call" for "tool call", dotted method names, semantic version numbers for MCP versions, and features named under a spec version that doesn't have them.
+
+Returns line-anchored findings with a severity and the canonical replacement. Fast and deterministic: no embeddings or model calls. validate_content runs the same checks.`
+
+	return mcp.NewToolWithRawSchema(CheckTerminologyToolName, description, schemaBytes)
+}
+
+func HandleCheckTerminology(args any) ([]mcp.Content, error) {
+	params, ok := args.(map[string]any)
+	if !ok {
+		return nil, toolerr.InvalidArgs("arguments must be a map")
+	}
+	content, ok := params["content"].(string)
+	if !ok {
+		return nil, toolerr.InvalidArgs("content must be a string")
+	}
+	specVersion, ok := params["specVersion"].(string)
+	if !ok || specVersion == "" {
+		specVersion = specs.DefaultSpecVersion
+	}
+	if !slices.Contains(specs.ValidSpecVersions, specVersion) {
+		return nil, toolerr.SpecVersionNotFound(specVersion)
+	}
+
+	findings := CheckTerminology(content, specVersion)
+	response := map[string]any{
+		"spec_version": specVersion,
+		"count":        len(findings),
+		"findings":     findings,
+	}
+	jsonBytes, _ := json.MarshalIndent(response, "", "  ")
+	return []mcp.Content{mcp.NewTextContent(string(jsonBytes))}, nil
+}
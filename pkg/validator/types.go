@@ -1,6 +1,14 @@
 package validator
 
-import "encoding/json"
+import (
+	"encoding/json"
+
+	"github.com/carlisia/mcp-factcheck/internal/factcheck"
+)
+
+// LocalModelCaveat is the LowConfidence of validations run with the local embedding
+// model, as servers without an OpenAI API key do
+const LocalModelCaveat = "Validated with the local embedding model, which compares vocabulary rather than meaning, because the server has no OpenAI API key: treat the verdict as a hint, and expect accurate content to be flagged and inaccurate content with spec vocabulary to pass"
 
 // ValidationResult represents a structured validation response
 type ValidationResult struct {
@@ -9,7 +17,13 @@ type ValidationResult struct {
 	Issues       []string `json:"issues,omitempty"`
 	Suggestions  []string `json:"suggestions,omitempty"`
 	CorrectedVersion string `json:"corrected_version,omitempty"`
+	CorrectedBy  string   `json:"corrected_by,omitempty"` // Model that wrote CorrectedVersion: "sampling" or "openai"
+	LowConfidence string  `json:"low_confidence,omitempty"` // Why the verdict is less reliable than usual, when it is
 	SpecVersion  string   `json:"spec_version"`
+	ContextType  string   `json:"context_type,omitempty"` // Kind of content validated, which decides the spec sections favored
+	Coverage     *Coverage `json:"coverage,omitempty"`    // Spec requirements the content addresses, for context types that expect some
+	Errors       []ValidationError        `json:"errors,omitempty"` // Structured findings, anchored to lines of the input
+	Claims       []factcheck.ClaimVerdict `json:"claims,omitempty"` // Set by the optional LLM claim check
 }
 
 // ValidationMatch represents a summarized spec match
@@ -17,6 +31,10 @@ type ValidationMatch struct {
 	Topic      string  `json:"topic"`
 	Relevance  float64 `json:"relevance"`
 	Summary    string  `json:"summary"`
+	Source     string  `json:"source,omitempty"` // Corpus the match came from; empty for the MCP spec
+	Section    string  `json:"section,omitempty"` // Heading hierarchy of the match, such as "Transports > Streamable HTTP"
+	URL        string  `json:"url,omitempty"`     // Published page and anchor of the match
+	Explanation *Explanation `json:"explanation,omitempty"` // Why the section matched, when the request asked to explain
 }
 
 // SummarizeMatches creates concise summaries from search results
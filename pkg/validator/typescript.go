@@ -0,0 +1,251 @@
+package validator
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// tsSDKModule is the module path prefix of the official TypeScript SDK
+const tsSDKModule = "@modelcontextprotocol/sdk"
+
+var (
+	// tsImport matches the start of a named import or require, up to the module path
+	tsImport = regexp.MustCompile(`\bimport\s+(?:type\s+)?\{([^}]*)\}\s*from\s*|\b(?:const|let|var)\s*\{([^}]*)\}\s*=\s*require\s*\(\s*`)
+
+	// tsRegistration matches McpServer registration calls like server.tool(...)
+	tsRegistration = regexp.MustCompile(`\b(\w+)\s*\.\s*(tool|registerTool|resource|registerResource|prompt|registerPrompt)\s*\(`)
+
+	// tsRequestHandler matches low-level handler registrations like
+	// server.setRequestHandler(ListToolsRequestSchema, ...)
+	tsRequestHandler = regexp.MustCompile(`\.\s*setRequestHandler\s*\(\s*(\w+)`)
+
+	// tsCapabilities matches the opening of a server capabilities object
+	tsCapabilities = regexp.MustCompile(`\bcapabilities\s*:\s*\{|\bregisterCapabilities\s*\(\s*\{`)
+)
+
+// tsRequestSchemas maps the TypeScript SDK request schemas a server registers
+// handlers for to the request they handle. The SDK throws when a handler is
+// registered for a capability the server didn't declare.
+var tsRequestSchemas = map[string]sdkHandler{
+	"ListToolsRequestSchema":             {"tools/list", "tools"},
+	"CallToolRequestSchema":              {"tools/call", "tools"},
+	"ListResourcesRequestSchema":         {"resources/list", "resources"},
+	"ListResourceTemplatesRequestSchema": {"resources/templates/list", "resources"},
+	"ReadResourceRequestSchema":          {"resources/read", "resources"},
+	"SubscribeRequestSchema":             {"resources/subscribe", "resources"},
+	"UnsubscribeRequestSchema":           {"resources/unsubscribe", "resources"},
+	"ListPromptsRequestSchema":           {"prompts/list", "prompts"},
+	"GetPromptRequestSchema":             {"prompts/get", "prompts"},
+	"SetLevelRequestSchema":              {"logging/setLevel", "logging"},
+	"CompleteRequestSchema":              {"completion/complete", "completions"},
+}
+
+// tsClientRequests are the TypeScript SDK Client methods that send a request other than ping
+var tsClientRequests = []string{
+	"listTools", "callTool", "listResources", "listResourceTemplates", "readResource",
+	"subscribeResource", "unsubscribeResource", "listPrompts", "getPrompt", "setLoggingLevel", "complete",
+}
+
+// tsTransports pairs the TypeScript SDK transport classes with the transport they implement
+var tsTransports = [][2]string{
+	{"StdioServerTransport", "stdio transport"},
+	{"StdioClientTransport", "stdio transport"},
+	{"StreamableHTTPServerTransport", "Streamable HTTP transport"},
+	{"StreamableHTTPClientTransport", "Streamable HTTP transport"},
+	{"SSEServerTransport", "HTTP+SSE transport"},
+	{"SSEClientTransport", "HTTP+SSE transport"},
+}
+
+// scanTypeScript masks comments and string contents in TypeScript or JavaScript
+// source. Each top-level brace-delimited block, and the code between them, is a
+// separate block.
+func scanTypeScript(code string) *sourceText {
+	masked := []byte(code)
+	var literals []sourceLiteral
+	for i := 0; i < len(code); {
+		switch {
+		case strings.HasPrefix(code[i:], "//"):
+			end := strings.IndexByte(code[i:], '\n')
+			if end < 0 {
+				end = len(code) - i
+			}
+			blank(masked, i, i+end)
+			i += end
+		case strings.HasPrefix(code[i:], "/*"):
+			end := strings.Index(code[i+2:], "*/")
+			if end < 0 {
+				end = len(code) - i
+			} else {
+				end += 4
+			}
+			blank(masked, i, i+end)
+			i += end
+		case code[i] == '"' || code[i] == '\'' || code[i] == '`':
+			end := scanQuoted(code, i+1, code[i:i+1], code[i] != '`')
+			literals = append(literals, sourceLiteral{value: code[i+1 : end], offset: i, end: min(end+1, len(code))})
+			blank(masked, i+1, end)
+			i = end + 1
+		default:
+			i++
+		}
+	}
+
+	src := newSourceText(code, masked, literals)
+	depth := 0
+	for i, c := range src.masked {
+		switch c {
+		case '{':
+			if depth == 0 {
+				src.blockStarts = append(src.blockStarts, i)
+			}
+			depth++
+		case '}':
+			if depth = max(depth-1, 0); depth == 0 {
+				src.blockStarts = append(src.blockStarts, i+1)
+			}
+		}
+	}
+	return src
+}
+
+// analyzeTypeScriptCode checks TypeScript or JavaScript MCP code against
+// specVersion: TypeScript SDK servers and clients, and hand-written protocol code
+func analyzeTypeScriptCode(code, specVersion string) (*staticAnalysis, error) {
+	src := scanTypeScript(code)
+	analysis := &staticAnalysis{}
+
+	// Names imported from the SDK, mapped to their local names
+	imported := map[string]string{}
+	for _, m := range tsImport.FindAllStringSubmatchIndex(src.masked, -1) {
+		module, ok := src.literalAt(m[1])
+		if !ok || !strings.HasPrefix(module.value, tsSDKModule) {
+			continue
+		}
+		list := src.masked[max(m[2], m[4]):max(m[3], m[5])]
+		alias := "as"
+		if m[4] >= 0 {
+			alias = ":" // const { Client: C } = require(...)
+			list = strings.ReplaceAll(list, ":", " : ")
+		}
+		for name, local := range importedNames(list, alias) {
+			imported[name] = local
+		}
+	}
+
+	if local, ok := imported["McpServer"]; ok {
+		servers := src.constructed(local)
+		counts := map[string]int{}
+		for _, m := range tsRegistration.FindAllStringSubmatch(src.masked, -1) {
+			if slices.Contains(servers, m[1]) {
+				counts[strings.ToLower(strings.TrimPrefix(m[2], "register"))]++
+			}
+		}
+		analysis.Patterns = append(analysis.Patterns, "TypeScript SDK MCP server implementation (McpServer)"+registrationSummary(counts))
+	}
+	if _, ok := imported["Server"]; ok {
+		analysis.Patterns = append(analysis.Patterns, "TypeScript SDK low-level MCP server implementation (Server)")
+		analysis.checkTypeScriptHandlers(src, imported)
+	}
+	if local, ok := imported["Client"]; ok {
+		analysis.Patterns = append(analysis.Patterns, "TypeScript SDK MCP client implementation")
+		analysis.checkClientOrder(src, src.constructed(local), "connect", tsClientRequests)
+	}
+	for _, t := range tsTransports {
+		name, transport := t[0], t[1]
+		local, ok := imported[name]
+		if !ok {
+			continue
+		}
+		if !slices.Contains(analysis.Patterns, transport) {
+			analysis.Patterns = append(analysis.Patterns, transport)
+		}
+		if strings.HasPrefix(name, "SSE") {
+			analysis.checkSSETransport(name, strings.Replace(name, "SSE", "StreamableHTTP", 1), src.firstLine(`\b`+regexp.QuoteMeta(local)+`\b`), specVersion)
+		}
+	}
+	if src.usesCustomJSONRPC() {
+		analysis.Patterns = append(analysis.Patterns, "custom JSON-RPC protocol implementation")
+	}
+
+	analysis.checkHandWritten(src, specVersion, "protocolVersion")
+	return analysis, nil
+}
+
+// checkTypeScriptHandlers checks the request handlers registered on a low-level
+// Server against the capabilities it declares
+func (a *staticAnalysis) checkTypeScriptHandlers(src *sourceText, imported map[string]string) {
+	schemas := map[string]sdkHandler{}
+	for name, handler := range tsRequestSchemas {
+		if local, ok := imported[name]; ok {
+			schemas[local] = handler
+		}
+	}
+
+	handled := map[string]int{}
+	var handlers []sdkHandler
+	var lines []int
+	for _, m := range tsRequestHandler.FindAllStringSubmatchIndex(src.masked, -1) {
+		if handler, ok := schemas[src.masked[m[2]:m[3]]]; ok {
+			handled[handler.method] = src.line(m[2])
+			handlers = append(handlers, handler)
+			lines = append(lines, src.line(m[2]))
+		}
+	}
+	a.checkListMethods(handled)
+
+	declared := map[string]int{}
+	blocks := tsCapabilities.FindAllStringIndex(src.masked, -1)
+	for _, m := range blocks {
+		for key, line := range src.objectKeys(m[1] - 1) {
+			declared[key] = line
+		}
+	}
+	if len(blocks) == 0 {
+		return // declared elsewhere, e.g. in a variable
+	}
+
+	reported := map[string]bool{}
+	for i, h := range handlers {
+		if _, ok := declared[h.capability]; ok || reported[h.capability] {
+			continue
+		}
+		reported[h.capability] = true
+		a.add(NewValidationError(IssueTypeMissing, SeverityCritical, fmt.Sprintf("Server handles %s but does not declare the %s capability", h.method, h.capability)).
+			WithFound(h.method).
+			WithExpected(h.capability + " capability").
+			WithSpecSection("Lifecycle: Capability Negotiation").
+			WithLineNumber(lines[i]).
+			AddSuggestion(fmt.Sprintf("Declare capabilities: { %s: {} } in the Server options; the SDK throws when a handler is registered for an undeclared capability", h.capability)))
+	}
+	for _, f := range serverFeatures {
+		line, ok := declared[f.capability]
+		if _, lists := handled[f.listMethod]; ok && !lists {
+			a.add(NewValidationError(IssueTypeMissing, SeverityWarning, fmt.Sprintf("Server declares the %s capability but handles no %s", f.capability, f.listMethod)).
+				WithFound(f.capability + " capability").
+				WithExpected(f.listMethod).
+				WithSpecSection("Lifecycle: Capability Negotiation").
+				WithLineNumber(line).
+				AddSuggestion(fmt.Sprintf("Handle %s, or drop the capability so clients don't call it", f.listMethod)))
+		}
+	}
+}
+
+// registrationSummary describes registration counts like " registering 2 tools and 1 prompt"
+func registrationSummary(counts map[string]int) string {
+	var parts []string
+	for _, kind := range []string{"tool", "resource", "prompt"} {
+		switch n := counts[kind]; n {
+		case 0:
+		case 1:
+			parts = append(parts, "1 "+kind)
+		default:
+			parts = append(parts, fmt.Sprintf("%d %ss", n, kind))
+		}
+	}
+	if len(parts) == 0 {
+		return ""
+	}
+	return " registering " + strings.Join(parts, ", ")
+}
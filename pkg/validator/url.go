@@ -0,0 +1,141 @@
+package validator
+
+import (
+	"context"
+	"encoding/json"
+	"maps"
+
+	"github.com/carlisia/mcp-factcheck/embedding"
+	mcpembedding "github.com/carlisia/mcp-factcheck/internal/embedding"
+	"github.com/carlisia/mcp-factcheck/internal/fetch"
+	"github.com/carlisia/mcp-factcheck/internal/limits"
+	"github.com/carlisia/mcp-factcheck/internal/specs"
+	"github.com/carlisia/mcp-factcheck/internal/toolerr"
+	"github.com/carlisia/mcp-factcheck/pkg/logger"
+	"github.com/carlisia/mcp-factcheck/pkg/telemetry"
+	"github.com/mark3labs/mcp-go/mcp"
+	"go.opentelemetry.io/otel/attribute"
+	"go.uber.org/zap"
+)
+
+const ValidateURLToolName = "validate_url"
+
+func GetValidateURLTool() mcp.Tool {
+	schema := map[string]any{
+		"type": "object",
+		"properties": map[string]any{
+			"url": map[string]any{
+				"type":        "string",
+				"description": "http(s) URL of the page to validate, such as a blog post, README, or documentation page. HTML pages are reduced to the markdown of their main content; markdown and plain text are checked as served",
+			},
+			"specVersion": map[string]any{
+				"type":        "string",
+				"description": "MCP specification version to validate against",
+				"enum":        specs.ValidSpecVersions,
+				"default":     specs.DefaultSpecVersion,
+			},
+			"corpus": map[string]any{
+				"type":        "string",
+				"description": "Name of a documentation corpus ingested on the server to check the page against alongside the MCP specification",
+			},
+		},
+		"required": []string{"url"},
+	}
+	maps.Copy(schema["properties"].(map[string]any), chunkingSchema())
+	schemaBytes, _ := json.Marshal(schema)
+
+	description := `Fetch a web page and validate its MCP content against the embedded official MCP specification.
+
+USE THIS INSTEAD OF validate_content WHEN the user gives a link to a blog post, README, or docs page about MCP, rather than pasting its text.
+
+The page is split into sections and each is checked separately. Returns per-section findings with the nearest heading and line numbers in the extracted markdown.`
+
+	return mcp.NewToolWithRawSchema(ValidateURLToolName, description, schemaBytes)
+}
+
+// HandleValidateURL fetches a page, extracts its main text, and runs chunked validation on it
+func HandleValidateURL(ctx context.Context, vectorDB *mcpembedding.VectorDB, generator *embedding.Generator, args any) ([]mcp.Content, error) {
+	log := logger.WithRequestID(ctx)
+
+	params, ok := args.(map[string]any)
+	if !ok {
+		return nil, toolerr.InvalidArgs("arguments must be a map")
+	}
+
+	rawURL, _ := params["url"].(string)
+	if !fetch.IsURL(rawURL) {
+		return nil, toolerr.InvalidArgs("url must be an http or https URL")
+	}
+
+	specVersion, ok := params["specVersion"].(string)
+	if !ok {
+		specVersion = specs.DefaultSpecVersion
+	}
+	if !specs.IsValidSpecVersion(specVersion) {
+		return nil, toolerr.SpecVersionNotFound(specVersion)
+	}
+
+	if corpus, _ := params["corpus"].(string); corpus != "" {
+		if err := vectorDB.CheckCorpus(corpus); err != nil {
+			return nil, err
+		}
+		ctx = WithCorpus(ctx, corpus)
+	}
+
+	ctx, err := chunkingArgs(ctx, params)
+	if err != nil {
+		return nil, err
+	}
+
+	fetchCtx, fetchSpan := telemetry.NewSpanBuilder().
+		WithKind("CHAIN").
+		WithInput(rawURL, "text/uri-list").
+		Start(ctx, "url.fetch")
+	doc, err := fetch.GetPublic(fetchCtx, rawURL)
+	if err != nil {
+		telemetry.RecordError(fetchSpan, err)
+		fetchSpan.End()
+		return nil, err
+	}
+	fetchSpan.SetAttributes(
+		attribute.String("url.final", doc.URL),
+		attribute.String("document.title", doc.Title),
+		attribute.Int("content.length", len(doc.Text)),
+	)
+	fetchSpan.End()
+
+	// The fetched text stands in for a content argument, so it gets the same cap
+	if err := limits.CheckContentLength("content", len(doc.Text)); err != nil {
+		return nil, err
+	}
+
+	log.Info("Fetched page for validation",
+		zap.String("url", doc.URL),
+		zap.String("title", doc.Title),
+		zap.Int("content_length", len(doc.Text)))
+
+	ctx, requestSpan := telemetry.StartValidationSpan(ctx, doc.Text, specVersion, true)
+	defer requestSpan.End()
+	requestSpan.SetAttributes(
+		attribute.String("validation.strategy", "chunked"),
+		attribute.String("validation.url", doc.URL),
+	)
+
+	aggregated, err := ValidateChunked(ctx, vectorDB, generator, doc.Text, specVersion)
+	if err != nil {
+		requestSpan.SetAttributes(attribute.String("validation.error", err.Error()))
+		telemetry.RecordError(requestSpan, err)
+		return nil, err
+	}
+	requestSpan.SetAttributes(attribute.Bool("validation.success", true))
+	reportResult(WithDocument(ctx, doc.URL), doc.Text, *aggregated)
+
+	response := chunkedResponse(*aggregated)
+	response["validation_type"] = "url"
+	response["url"] = doc.URL
+	if doc.Title != "" {
+		response["title"] = doc.Title
+	}
+	jsonBytes, _ := json.MarshalIndent(response, "", "  ")
+	return []mcp.Content{mcp.NewTextContent(string(jsonBytes))}, nil
+}
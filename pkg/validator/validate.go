@@ -0,0 +1,227 @@
+package validator
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/carlisia/mcp-factcheck/embedding"
+	mcpembedding "github.com/carlisia/mcp-factcheck/internal/embedding"
+	"github.com/carlisia/mcp-factcheck/internal/features"
+	"github.com/carlisia/mcp-factcheck/internal/specs"
+	"github.com/carlisia/mcp-factcheck/internal/toolerr"
+	"github.com/carlisia/mcp-factcheck/pkg/logger"
+	"github.com/carlisia/mcp-factcheck/pkg/telemetry"
+	"go.opentelemetry.io/otel/attribute"
+	"go.uber.org/zap"
+)
+
+// Request is a request to validate content. Every frontend validates through one: the
+// MCP tools parse their arguments into it, and the HTTP API, bots, and CLI build it
+// directly, so an option added here is available to all of them.
+type Request struct {
+	Content     string       `json:"content"`
+	Language    string       `json:"language,omitempty"`     // Language of Content when it is source code, validated as validate_code does
+	SpecVersion string       `json:"spec_version,omitempty"` // specs.DefaultSpecVersion when empty
+	ContextType string       `json:"context_type,omitempty"` // One of ContextTypes, or empty to check accuracy only
+	Corpus      string       `json:"corpus,omitempty"`       // Ingested corpus to check against alongside the spec
+	Document    string       `json:"document,omitempty"`     // Name, path, or URL of the document, for the validation history
+	Chunked     bool         `json:"chunked,omitempty"`      // Validate in chunks even when the content is short
+	Chunking    ChunkOptions `json:"chunking,omitzero"`      // Overrides the configured chunking; implies Chunked
+	ClaimCheck  bool         `json:"claim_check,omitempty"`  // Verify claims with a chat model (requires the claim_check feature)
+	SuggestFix  bool         `json:"suggest_fix,omitempty"`  // Write a corrected version (requires the suggest_rewrite feature)
+	Score       bool         `json:"score,omitempty"`        // Rate the result from 0 to 100 with a letter grade; see ScoreResult
+	Explain     bool         `json:"explain,omitempty"`      // Explain each spec match with shared phrases and quoted spec sentences
+}
+
+// RequestError reports an invalid request option, as opposed to a failure while
+// validating
+type RequestError struct {
+	Err error
+}
+
+func (e *RequestError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *RequestError) Unwrap() error {
+	return e.Err
+}
+
+// invalid marks err as caused by the request
+func invalid(err error) error {
+	return &RequestError{Err: err}
+}
+
+// chunked reports whether the request's content is validated in chunks
+func (r Request) chunked() bool {
+	if r.Language != "" {
+		return false
+	}
+	return r.Chunked || r.Chunking != (ChunkOptions{}) ||
+		len(r.Content) > ToolSettingsFor(ValidateContentToolName).AutoChunkLength
+}
+
+// context checks the request's options and returns ctx carrying them
+func (r Request) context(ctx context.Context, vectorDB *mcpembedding.VectorDB, generator *embedding.Generator) (context.Context, error) {
+	if !specs.IsValidSpecVersion(r.SpecVersion) {
+		return nil, invalid(toolerr.SpecVersionNotFound(r.SpecVersion))
+	}
+	if r.Language != "" && (r.Chunked || r.Chunking != (ChunkOptions{}) || r.ContextType != "" || r.Corpus != "" || r.ClaimCheck || r.SuggestFix || r.Explain) {
+		return nil, invalid(errors.New("chunking, context types, corpora, claim checks, suggested fixes, and explanations apply to prose, not code"))
+	}
+	if r.Document != "" {
+		ctx = WithDocument(ctx, r.Document)
+	}
+	if r.Chunking != (ChunkOptions{}) {
+		// An overlap given without a size is checked against the configured size
+		if err := r.Chunking.withDefaults(ToolSettingsFor(ValidateContentToolName)).Validate(); err != nil {
+			return nil, invalid(err)
+		}
+		ctx = WithChunking(ctx, r.Chunking)
+	}
+	if r.Corpus != "" {
+		if err := vectorDB.CheckCorpus(r.Corpus); err != nil {
+			return nil, invalid(err)
+		}
+		ctx = WithCorpus(ctx, r.Corpus)
+	}
+	if r.ContextType != "" {
+		contextType, err := ParseContextType(r.ContextType)
+		if err != nil {
+			return nil, invalid(err)
+		}
+		ctx = WithContextType(ctx, contextType)
+	}
+
+	if r.Explain {
+		ctx = WithExplain(ctx)
+	}
+	if r.ClaimCheck {
+		if !features.Enabled(features.ClaimCheck) {
+			return nil, invalid(fmt.Errorf("claimCheck requires the %s feature; enable it with %s=%s", features.ClaimCheck, features.EnvVar, features.ClaimCheck))
+		}
+		// With sampling_judge, claims are judged by the host's model even when the
+		// server has an API key
+		model, ok := ChatModelFor(ctx, generator, features.Enabled(features.SamplingJudge))
+		if !ok {
+			return nil, fmt.Errorf("claimCheck: %w", ErrNoChatModel)
+		}
+		ctx = WithClaimCheck(ctx, model)
+	}
+	if r.SuggestFix {
+		if !features.Enabled(features.SuggestRewrite) {
+			return nil, invalid(fmt.Errorf("suggestFix requires the %s feature; enable it with %s=%s", features.SuggestRewrite, features.EnvVar, features.SuggestRewrite))
+		}
+		// Prefer the host's model, so corrections don't need the server's API key
+		model, ok := ChatModelFor(ctx, generator, true)
+		if !ok {
+			return nil, fmt.Errorf("suggestFix: %w", ErrNoChatModel)
+		}
+		ctx = WithRewrite(ctx, model)
+	}
+	return ctx, nil
+}
+
+// Validate validates content against the spec. Long prose, or prose the request asks
+// to chunk, is validated chunk by chunk; otherwise, and always for code, the result
+// has no chunk results and carries the best matching spec sections in Matches. Either
+// way the result's Findings are the request's findings. Errors caused by the
+// request's options are RequestErrors. With a DocumentMemory in ctx, content validated
+// before with the same options and settings returns the earlier result, with
+// UnchangedSince set, instead of being validated again.
+func Validate(ctx context.Context, vectorDB *mcpembedding.VectorDB, generator *embedding.Generator, req Request) (*AggregatedValidationResult, error) {
+	if req.SpecVersion == "" {
+		req.SpecVersion = specs.DefaultSpecVersion
+	}
+	ctx, err := req.context(ctx, vectorDB, generator)
+	if err != nil {
+		return nil, err
+	}
+	fingerprint := req.fingerprint()
+	if result, ok := recallUnchanged(ctx, req, fingerprint); ok {
+		logger.WithRequestID(ctx).Info("Content unchanged since last validation",
+			zap.String("document", req.Document),
+			zap.Time("validated_at", *result.UnchangedSince))
+		return result, nil
+	}
+	chunked := req.chunked()
+
+	ctx, requestSpan := telemetry.StartValidationSpan(ctx, req.Content, req.SpecVersion, chunked)
+	defer requestSpan.End()
+	requestSpan.SetAttributes(attribute.String("validation.context_type", contextTypeFrom(ctx)))
+
+	logger.WithRequestID(ctx).Info("Starting content validation",
+		zap.Int("content_length", len(req.Content)),
+		zap.String("spec_version", req.SpecVersion),
+		zap.Bool("use_chunking", chunked),
+		zap.String("context_type", contextTypeFrom(ctx)),
+		zap.String("language", req.Language),
+		zap.String("content_preview", getContentPreview(req.Content, 100)))
+
+	var result *AggregatedValidationResult
+	switch {
+	case req.Language != "":
+		requestSpan.SetAttributes(attribute.String("validation.strategy", "code"))
+		result, err = validateCode(ctx, vectorDB, generator, req.Content, req.Language, req.SpecVersion)
+	case chunked:
+		requestSpan.SetAttributes(attribute.String("validation.strategy", "chunked"))
+		result, err = ValidateChunked(ctx, vectorDB, generator, req.Content, req.SpecVersion)
+	default:
+		requestSpan.SetAttributes(attribute.String("validation.strategy", "single"))
+		result, err = validateWhole(ctx, vectorDB, generator, req.Content, req.SpecVersion)
+	}
+	if err != nil {
+		requestSpan.SetAttributes(attribute.String("validation.error", err.Error()))
+		telemetry.RecordError(requestSpan, err)
+		return nil, err
+	}
+	if embedding.CurrentModel().Name == embedding.LocalModel {
+		result.Overall.LowConfidence = LocalModelCaveat
+	}
+
+	if req.Score {
+		tool := ValidateContentToolName
+		if req.Language != "" {
+			tool = ValidateCodeToolName
+		}
+		result.Score = ScoreResult(*result, ToolSettingsFor(tool))
+		requestSpan.SetAttributes(
+			attribute.Int("validation.score", result.Score.Score),
+			attribute.String("validation.grade", result.Score.Grade),
+		)
+	}
+
+	resultJSON, _ := json.Marshal(result)
+	requestSpan.SetAttributes(
+		attribute.String("output.value", string(resultJSON)),
+		attribute.String("output.mime_type", "application/json"),
+		attribute.Bool("validation.success", true),
+	)
+	reportResult(ctx, req.Content, *result)
+	remember(ctx, req, fingerprint, result)
+	return result, nil
+}
+
+// formatResult formats a Validate result as the validation tools return it
+func formatResult(result *AggregatedValidationResult) string {
+	if result.ChunkResults != nil {
+		return FormatChunkedValidationResult(*result)
+	}
+	if result.UnchangedSince == nil && result.Score == nil {
+		return FormatValidationResult(result.Overall, result.Matches)
+	}
+	response := map[string]any{
+		"validation": result.Overall,
+		"references": result.Matches,
+	}
+	if result.UnchangedSince != nil {
+		response["unchanged_since"] = result.UnchangedSince
+	}
+	if result.Score != nil {
+		response["score"] = result.Score
+	}
+	jsonBytes, _ := json.MarshalIndent(response, "", "  ")
+	return string(jsonBytes)
+}
@@ -0,0 +1,333 @@
+package validator
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/carlisia/mcp-factcheck/internal/specs"
+)
+
+// feature is protocol behavior that exists only in some spec versions
+type feature struct {
+	name        string
+	pattern     *regexp.Regexp
+	section     string // Spec section findings cite
+	since       string // First spec version with the feature; empty for all
+	until       string // First spec version without it; empty for none
+	replacement string // What replaced it in until, if anything
+}
+
+// in reports whether the feature exists in specVersion
+func (f feature) in(specVersion string) bool {
+	return (f.since == "" || !versionBefore(specVersion, f.since)) &&
+		(f.until == "" || versionBefore(specVersion, f.until))
+}
+
+// versionedFeatures lists the behavior added or removed between published spec versions
+var versionedFeatures = []feature{
+	{
+		name:    "Streamable HTTP",
+		pattern: regexp.MustCompile(`(?i)\bStreamable HTTP\b`),
+		section: "Transports",
+		since:   "2025-03-26",
+	},
+	{
+		name:        "The HTTP+SSE transport",
+		pattern:     regexp.MustCompile(`(?i)\b(HTTP ?\+ ?SSE( transport)?|HTTP with SSE( transport)?|SSE transport)\b`),
+		section:     "Transports: Backwards Compatibility",
+		until:       "2025-03-26",
+		replacement: "Streamable HTTP transport",
+	},
+	{
+		name:    "OAuth 2.1 authorization",
+		pattern: regexp.MustCompile(`(?i)\bOAuth ?2\.1\b`),
+		section: "Authorization",
+		since:   "2025-03-26",
+	},
+	{
+		name:    "Tool annotations",
+		pattern: regexp.MustCompile(`(?i)\btool annotations\b`),
+		section: "Server Features: Tools",
+		since:   "2025-03-26",
+	},
+	{
+		name:    "Audio content",
+		pattern: regexp.MustCompile(`(?i)\baudio content\b`),
+		section: "Server Features: Tools",
+		since:   "2025-03-26",
+	},
+	{
+		name:    "The completions capability",
+		pattern: regexp.MustCompile(`(?i)\bcompletions capability\b`),
+		section: "Lifecycle: Capability Negotiation",
+		since:   "2025-03-26",
+	},
+	{
+		name:    "JSON-RPC batching",
+		pattern: regexp.MustCompile(`(?i)\bJSON-RPC batch(es|ing)?\b`),
+		section: "Base Protocol: Messages",
+		since:   "2025-03-26",
+		until:   "2025-06-18",
+	},
+	{
+		name:    "Elicitation",
+		pattern: regexp.MustCompile(`(?i)\belicitation\b`),
+		section: "Client Features: Elicitation",
+		since:   "2025-06-18",
+	},
+	{
+		name:    "Structured tool output",
+		pattern: regexp.MustCompile(`(?i)\b(structured (tool )?output|structuredContent)\b`),
+		section: "Server Features: Tools",
+		since:   "2025-06-18",
+	},
+	{
+		name:    "Resource links",
+		pattern: regexp.MustCompile(`(?i)\bresource links?\b`),
+		section: "Server Features: Tools",
+		since:   "2025-06-18",
+	},
+	{
+		name:    "The MCP-Protocol-Version header",
+		pattern: regexp.MustCompile(`(?i)\bMCP-Protocol-Version\b`),
+		section: "Transports",
+		since:   "2025-06-18",
+	},
+}
+
+var (
+	// datedVersion matches a date written as YYYY-MM-DD, YYYY/MM/DD, or YYYY.MM.DD,
+	// with or without zero padding
+	datedVersion = regexp.MustCompile(`\b(20\d\d)([-/.])(\d{1,2})([-/.])(\d{1,2})\b`)
+	// spelledVersion matches a date with its month spelled out, as in "June 18, 2025"
+	spelledVersion = regexp.MustCompile(`\b(January|February|March|April|May|June|July|August|September|October|November|December) (\d{1,2}),? (20\d\d)\b`)
+	// numberedVersion matches a semantic version number given as the protocol version
+	numberedVersion = regexp.MustCompile(`(?i)\b(protocol[ _-]?version)["'` + "`" + `]?\s*(:|=|is|of)?\s*["'` + "`" + `]?(v?\d+\.\d+(\.\d+)?)\b`)
+	// versionLead and versionTrail match the words that mark a date as naming a spec
+	// version when they come just before or after it
+	versionLead  = regexp.MustCompile(`(?i)(version|protocolVersion|spec|specification|revision|MCP|release)["'` + "`" + `]?\s*(:|=|is|of)?\s*["'` + "`" + `(]?$`)
+	versionTrail = regexp.MustCompile(`(?i)^["'` + "`" + `)]?\s*(spec|specification|version|revision|protocol|release)\b`)
+	// versionChange matches wording about a change between versions, which names
+	// features under the version that added or removed them
+	versionChange = regexp.MustCompile(`(?i)\b(introduced|introduces|added|adds|removed|removes|deprecated|deprecates|replaced|replaces|dropped|changed|changes|before|prior to|until|since|no longer|superseded|new in)\b`)
+)
+
+// versionMention is a spec version named in content
+type versionMention struct {
+	found   string // As written
+	version string // In canonical YYYY-MM-DD form
+	line    int
+	start   int // Offset in the line
+	end     int
+}
+
+// CheckVersions flags protocol version strings in content that aren't published MCP
+// versions or aren't written in the canonical YYYY-MM-DD form, features claimed
+// under a version that doesn't have them, and versions other than the one the
+// content is being validated against. Dates only count as versions next to wording
+// like "version" or "spec", so publication dates aren't mistaken for them.
+func CheckVersions(content, specVersion string) []ValidationError {
+	var findings []ValidationError
+	reported := map[string]bool{}
+	report := func(key string, finding *ValidationError) {
+		if !reported[key] {
+			reported[key] = true
+			findings = append(findings, *finding)
+		}
+	}
+
+	for n, line := range strings.Split(content, "\n") {
+		for _, m := range numberedVersion.FindAllStringSubmatch(line, -1) {
+			report("number:"+m[3], NewValidationError(IssueTypeInaccuracy, SeverityWarning, fmt.Sprintf("%q is not an MCP protocol version; protocol versions are dates", m[3])).
+				WithFound(m[3]).
+				WithExpected(specVersion).
+				WithSpecSection("Lifecycle: Version Negotiation").
+				WithSpecURL(sectionURL(specVersion, "Lifecycle: Version Negotiation")).
+				WithLineNumber(n+1).
+				AddSuggestion(fmt.Sprintf("Use a dated protocol version such as %q", specs.DefaultSpecVersion)))
+		}
+
+		mentions := versionMentions(line, n+1)
+		var published []versionMention
+		for _, m := range mentions {
+			switch {
+			case !slices.Contains(publishedVersions(), m.version):
+				report("invalid:"+m.version, NewValidationError(IssueTypeInaccuracy, SeverityWarning, fmt.Sprintf("%q is not a published MCP protocol version", m.found)).
+					WithFound(m.found).
+					WithExpected(nearestVersion(m.version)).
+					WithSpecSection("Lifecycle: Version Negotiation").
+					WithSpecURL(sectionURL(specVersion, "Lifecycle: Version Negotiation")).
+					WithLineNumber(m.line).
+					AddSuggestion(fmt.Sprintf("Published versions are %s", strings.Join(publishedVersions(), ", "))))
+				continue
+			case m.found != m.version:
+				report("format:"+m.found, NewValidationError(IssueTypeImprecise, SeveritySuggestion, fmt.Sprintf("Write protocol version %q as %q", m.found, m.version)).
+					WithFound(m.found).
+					WithExpected(m.version).
+					WithSpecSection("Lifecycle: Version Negotiation").
+					WithSpecURL(sectionURL(specVersion, "Lifecycle: Version Negotiation")).
+					WithLineNumber(m.line).
+					AddSuggestion("Protocol versions are exchanged as YYYY-MM-DD strings, so write them the same way"))
+			}
+			published = append(published, m)
+			if m.version != specVersion && specVersion != "draft" {
+				report("other:"+m.version, NewValidationError(IssueTypeImprecise, SeveritySuggestion, fmt.Sprintf("Content refers to protocol version %s but is being validated against %s", m.version, specVersion)).
+					WithFound(m.found).
+					WithExpected(specVersion).
+					WithSpecSection("Lifecycle: Version Negotiation").
+					WithLineNumber(m.line).
+					AddSuggestion("Validate against the version the content describes, or update the version it names"))
+			}
+		}
+
+		for _, finding := range checkVersionLabels(line, n+1, published, specVersion) {
+			report(finding.Message, &finding)
+		}
+	}
+	SortBySeverity(findings)
+	return findings
+}
+
+// versionMentions returns the dates on a line that name a spec version
+func versionMentions(line string, lineNumber int) []versionMention {
+	var mentions []versionMention
+	add := func(found string, year, month, day int, start, end int) {
+		if !versionLead.MatchString(line[:start]) && !versionTrail.MatchString(line[end:]) {
+			return
+		}
+		mentions = append(mentions, versionMention{
+			found:   found,
+			version: fmt.Sprintf("%04d-%02d-%02d", year, month, day),
+			line:    lineNumber,
+			start:   start,
+			end:     end,
+		})
+	}
+	for _, loc := range datedVersion.FindAllStringSubmatchIndex(line, -1) {
+		if line[loc[4]:loc[5]] != line[loc[8]:loc[9]] {
+			continue // Mixed separators, as in 2025-06/18, aren't a date
+		}
+		var year, month, day int
+		fmt.Sscanf(line[loc[2]:loc[3]]+" "+line[loc[6]:loc[7]]+" "+line[loc[10]:loc[11]], "%d %d %d", &year, &month, &day)
+		add(line[loc[0]:loc[1]], year, month, day, loc[0], loc[1])
+	}
+	for _, loc := range spelledVersion.FindAllStringSubmatchIndex(line, -1) {
+		date, err := time.Parse("January 2 2006", line[loc[2]:loc[3]]+" "+line[loc[4]:loc[5]]+" "+line[loc[6]:loc[7]])
+		if err != nil {
+			continue
+		}
+		add(line[loc[0]:loc[1]], date.Year(), int(date.Month()), date.Day(), loc[0], loc[1])
+	}
+	slices.SortFunc(mentions, func(a, b versionMention) int { return a.start - b.start })
+	return mentions
+}
+
+// checkVersionLabels flags features a sentence claims for the one published version
+// it names when that version doesn't have them. Sentences naming several versions,
+// or describing a change between versions, are left alone, as are those naming
+// specVersion, whose features the terminology pass already checks.
+func checkVersionLabels(line string, lineNumber int, mentions []versionMention, specVersion string) []ValidationError {
+	var findings []ValidationError
+	start := 0
+	ends := append(sentenceEnd.FindAllStringIndex(line, -1), []int{len(line), len(line)})
+	for _, loc := range ends {
+		sentence, from, to := line[start:loc[1]], start, loc[1]
+		start = loc[1]
+
+		var named []versionMention
+		for _, m := range mentions {
+			if m.start >= from && m.end <= to && !slices.ContainsFunc(named, func(n versionMention) bool { return n.version == m.version }) {
+				named = append(named, m)
+			}
+		}
+		if len(named) != 1 || named[0].version == specVersion || versionChange.MatchString(sentence) {
+			continue
+		}
+		label := named[0]
+		for _, f := range versionedFeatures {
+			found := f.pattern.FindString(sentence)
+			if found == "" || f.in(label.version) {
+				continue
+			}
+			findings = append(findings, *NewValidationError(IssueTypeInaccuracy, SeverityWarning, fmt.Sprintf("%q is described under protocol version %s, which doesn't have it", found, label.version)).
+				WithFound(found).
+				WithExpected(f.versions()).
+				WithSpecSection(f.section).
+				WithSpecURL(sectionURL(label.version, f.section)).
+				WithLineNumber(lineNumber).
+				AddSuggestion(f.availability()))
+		}
+	}
+	return findings
+}
+
+// versions describes the spec versions that have the feature
+func (f feature) versions() string {
+	switch {
+	case f.since != "" && f.until != "":
+		return fmt.Sprintf("protocol versions from %s until %s", f.since, f.until)
+	case f.since != "":
+		return fmt.Sprintf("protocol version %s or later", f.since)
+	case f.until != "":
+		return fmt.Sprintf("protocol versions before %s", f.until)
+	}
+	return "any protocol version"
+}
+
+// availability explains when the feature was added and removed
+func (f feature) availability() string {
+	var parts []string
+	if f.since != "" {
+		parts = append(parts, "was introduced in "+f.since)
+	}
+	switch {
+	case f.replacement != "":
+		parts = append(parts, fmt.Sprintf("was replaced by the %s in %s", f.replacement, f.until))
+	case f.until != "":
+		parts = append(parts, "was removed in "+f.until)
+	}
+	return f.name + " " + strings.Join(parts, " and ")
+}
+
+// publishedVersions returns the spec versions other than the draft
+func publishedVersions() []string {
+	var versions []string
+	for _, version := range specs.ValidSpecVersions {
+		if version != "draft" {
+			versions = append(versions, version)
+		}
+	}
+	return versions
+}
+
+// nearestVersion returns the published version closest in date to an unknown one,
+// the likely intended version of a typo
+func nearestVersion(version string) string {
+	target, err := time.Parse(time.DateOnly, version)
+	if err != nil {
+		return specs.DefaultSpecVersion
+	}
+	nearest, best := specs.DefaultSpecVersion, time.Duration(-1)
+	for _, candidate := range publishedVersions() {
+		date, err := time.Parse(time.DateOnly, candidate)
+		if err != nil {
+			continue
+		}
+		distance := date.Sub(target)
+		if distance < 0 {
+			distance = -distance
+		}
+		if best < 0 || distance < best {
+			nearest, best = candidate, distance
+		}
+	}
+	return nearest
+}
+
+// applyVersionChecks adds the version findings for content to result
+func applyVersionChecks(content, specVersion string, result *ValidationResult) {
+	result.Errors = append(result.Errors, CheckVersions(content, specVersion)...)
+}
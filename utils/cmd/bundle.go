@@ -0,0 +1,96 @@
+package main
+
+import (
+	"compress/gzip"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+
+	specembedding "github.com/carlisia/mcp-factcheck/embedding"
+	specs "github.com/carlisia/mcp-factcheck/internal/specs"
+	"github.com/carlisia/mcp-factcheck/utils/embedding"
+	"github.com/carlisia/mcp-factcheck/vectorstore"
+	"github.com/spf13/cobra"
+)
+
+var bundleCmd = &cobra.Command{
+	Use:   "bundle",
+	Short: "Compress spec embeddings for compiling into the server binaries",
+	Long: `Re-encode spec embeddings from --data-dir in --store-format and gzip them into
+--output, named <version>.emb.gz (or <version>.json.gz for the json format).
+
+Binaries built after bundling install these embeddings into an empty data directory
+on first run, so a fresh install works without copying or generating embeddings.
+go generate ./internal/bundled runs this command with the default version.`,
+	RunE: runBundle,
+}
+
+var (
+	bundleVersions []string
+	bundleDataDir  string
+	bundleOutput   string
+)
+
+func init() {
+	bundleCmd.Flags().StringSliceVar(&bundleVersions, "version", []string{specs.DefaultSpecVersion}, "Spec versions to bundle")
+	bundleCmd.Flags().StringVar(&bundleDataDir, "data-dir", "./data/embeddings", "Directory containing the embeddings to bundle")
+	bundleCmd.Flags().StringVar(&bundleOutput, "output", "./internal/bundled/embeddings", "Directory to write the compressed embeddings to")
+}
+
+func runBundle(cmd *cobra.Command, args []string) error {
+	store := embedding.NewEmbeddingStore(bundleDataDir)
+	for _, version := range bundleVersions {
+		if !specs.IsValidSpecVersion(version) {
+			return fmt.Errorf("invalid spec version: %s. Valid versions: %v", version, specs.ValidSpecVersions)
+		}
+		spec, err := store.Load(version)
+		if err != nil {
+			return fmt.Errorf("failed to load %s: %w", version, err)
+		}
+		target := filepath.Join(bundleOutput, version+storeFormat.Extension()+".gz")
+		size, err := compressEmbeddings(spec, target)
+		if err != nil {
+			return fmt.Errorf("failed to bundle %s: %w", version, err)
+		}
+		log.Printf("Bundled %s into %s (%.1f MB)", version, target, float64(size)/(1<<20))
+	}
+	return nil
+}
+
+// compressEmbeddings writes spec gzipped in --store-format to target through a
+// temporary file, returning the compressed size
+func compressEmbeddings(spec *specembedding.SpecEmbedding, target string) (int64, error) {
+	tmp, err := os.CreateTemp(filepath.Dir(target), ".bundle-*.tmp")
+	if err != nil {
+		return 0, err
+	}
+	defer os.Remove(tmp.Name())
+
+	gz, err := gzip.NewWriterLevel(tmp, gzip.BestCompression)
+	if err != nil {
+		tmp.Close()
+		return 0, err
+	}
+	if err := vectorstore.Encode(gz, spec, storeFormat); err != nil {
+		tmp.Close()
+		return 0, err
+	}
+	if err := gz.Close(); err != nil {
+		tmp.Close()
+		return 0, err
+	}
+	if err := tmp.Chmod(0o644); err != nil {
+		tmp.Close()
+		return 0, err
+	}
+	info, err := tmp.Stat()
+	if err != nil {
+		tmp.Close()
+		return 0, err
+	}
+	if err := tmp.Close(); err != nil {
+		return 0, err
+	}
+	return info.Size(), os.Rename(tmp.Name(), target)
+}
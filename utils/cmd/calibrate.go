@@ -0,0 +1,163 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"text/tabwriter"
+
+	specembedding "github.com/carlisia/mcp-factcheck/embedding"
+	"github.com/carlisia/mcp-factcheck/internal/calibrate"
+	"github.com/carlisia/mcp-factcheck/internal/config"
+	mcpembedding "github.com/carlisia/mcp-factcheck/internal/embedding"
+	"github.com/carlisia/mcp-factcheck/internal/specs"
+	"github.com/carlisia/mcp-factcheck/pkg/validator"
+	"github.com/spf13/cobra"
+)
+
+var calibrateCmd = &cobra.Command{
+	Use:   "calibrate <examples.jsonl>",
+	Short: "Tune the validator's similarity thresholds against labeled examples",
+	Long: `Validate each labeled example, then sweep similarity thresholds from 0.01 to 1 and
+report the precision and recall of flagging the examples labeled "issue".
+
+Examples are JSON Lines with an "input" to validate and a "label" of "issue" or
+"no_issue", plus an optional "id", "spec_version", and "context_type". The output of
+` + "`factcheck eval export --labeled-only`" + ` can be used as is; unlabeled records are skipped.
+
+similarity_threshold is tuned to the threshold with the best F1 score, and
+low_similarity_threshold, below which findings are critical, to the highest threshold
+at or below it that flags with at least --min-precision. With --write, both are saved
+to the validator section of --config, which the server picks up on its next reload.
+
+Examples are validated with the settings of --config, so retrieval matches the server's.`,
+	Example: `  specloader calibrate data/calibration.jsonl
+  factcheck eval export --labeled-only -o labeled.jsonl && specloader calibrate --write labeled.jsonl`,
+	Args:         cobra.ExactArgs(1),
+	RunE:         runCalibrate,
+	SilenceUsage: true,
+}
+
+var (
+	calibrateDataDir      string
+	calibrateSpecVersion  string
+	calibrateConfig       string
+	calibrateMinPrecision float64
+	calibrateWrite        bool
+)
+
+func init() {
+	calibrateCmd.Flags().StringVar(&calibrateDataDir, "data-dir", "./data/embeddings", "Directory containing the embeddings to validate against")
+	calibrateCmd.Flags().StringVar(&calibrateSpecVersion, "spec-version", specs.DefaultSpecVersion, "Spec version for examples that don't name one")
+	calibrateCmd.Flags().StringVar(&calibrateConfig, "config", config.DefaultConfigPath(), "Server config file to read settings from and, with --write, save the thresholds to (default: $XDG_CONFIG_HOME/mcp-factcheck/config.json)")
+	calibrateCmd.Flags().Float64Var(&calibrateMinPrecision, "min-precision", 0.9, "Precision the low similarity threshold must flag issues with")
+	calibrateCmd.Flags().BoolVar(&calibrateWrite, "write", false, "Save the tuned thresholds to --config")
+}
+
+func runCalibrate(cmd *cobra.Command, args []string) error {
+	if !specs.IsValidSpecVersion(calibrateSpecVersion) {
+		return fmt.Errorf("invalid spec version: %s. Valid versions: %v", calibrateSpecVersion, specs.ValidSpecVersions)
+	}
+	if calibrateMinPrecision <= 0 || calibrateMinPrecision > 1 {
+		return fmt.Errorf("--min-precision must be in (0, 1], got %v", calibrateMinPrecision)
+	}
+	configPath := calibrateConfig
+	if configPath == "" {
+		dir, err := os.UserConfigDir()
+		if err != nil {
+			return fmt.Errorf("no --config given and no user config directory: %w", err)
+		}
+		configPath = filepath.Join(dir, config.AppName, "config.json")
+	}
+	if _, err := os.Stat(configPath); err == nil {
+		cfg, err := config.Load(configPath)
+		if err != nil {
+			return err
+		}
+		if err := validator.SetSettings(cfg.Validator); err != nil {
+			return err
+		}
+	}
+
+	examples, err := calibrate.ReadExamples(args[0])
+	if err != nil {
+		return err
+	}
+	if len(examples) == 0 {
+		return fmt.Errorf("no labeled examples in %s", args[0])
+	}
+
+	dataDir, err := filepath.Abs(calibrateDataDir)
+	if err != nil {
+		return err
+	}
+	vectorDB := mcpembedding.NewVectorDB(dataDir)
+	generator, err := specembedding.NewGenerator()
+	if err != nil {
+		return fmt.Errorf("failed to create embedding generator: %w", err)
+	}
+
+	log.Printf("Validating %d labeled examples...", len(examples))
+	observations := make([]calibrate.Observation, 0, len(examples))
+	for i, example := range examples {
+		specVersion := example.SpecVersion
+		if specVersion == "" {
+			specVersion = calibrateSpecVersion
+		}
+		result, err := validator.Validate(cmd.Context(), vectorDB, generator, validator.Request{
+			Content:     example.Input,
+			SpecVersion: specVersion,
+			ContextType: example.ContextType,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to validate example %s: %w", example.ID, err)
+		}
+		observations = append(observations, calibrate.Observation{Example: example, Confidence: result.Overall.Confidence})
+		if (i+1)%25 == 0 {
+			log.Printf("Validated %d/%d examples", i+1, len(examples))
+		}
+	}
+
+	result, err := calibrate.Tune(observations, calibrateMinPrecision)
+	if err != nil {
+		return err
+	}
+	if len(observations) < calibrate.MinExamples {
+		log.Printf("Warning: only %d examples; label at least %d before relying on the tuned thresholds", len(observations), calibrate.MinExamples)
+	}
+	printCalibration(cmd, result)
+
+	current := validator.CurrentSettings()
+	fmt.Fprintf(cmd.OutOrStdout(), "\nsimilarity_threshold:     %.2f (currently %.2f)\n", result.SimilarityThreshold, current.SimilarityThreshold)
+	fmt.Fprintf(cmd.OutOrStdout(), "low_similarity_threshold: %.2f (currently %.2f)\n", result.LowSimilarityThreshold, current.LowSimilarityThreshold)
+	if !calibrateWrite {
+		return nil
+	}
+	if err := config.WriteThresholds(configPath, result.SimilarityThreshold, result.LowSimilarityThreshold); err != nil {
+		return err
+	}
+	fmt.Fprintf(cmd.OutOrStdout(), "Wrote thresholds to %s\n", configPath)
+	return nil
+}
+
+// printCalibration prints the sweep every 0.05, plus the chosen thresholds
+func printCalibration(cmd *cobra.Command, result *calibrate.Result) {
+	w := tabwriter.NewWriter(cmd.OutOrStdout(), 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "THRESHOLD\tPRECISION\tRECALL\tF1\tTP\tFP\tFN\tTN\t")
+	for i, p := range result.Points {
+		mark := ""
+		switch p.Threshold {
+		case result.SimilarityThreshold:
+			mark = "<- similarity_threshold"
+		case result.LowSimilarityThreshold:
+			mark = "<- low_similarity_threshold"
+		default:
+			if (i+1)%5 != 0 {
+				continue
+			}
+		}
+		fmt.Fprintf(w, "%.2f\t%.3f\t%.3f\t%.3f\t%d\t%d\t%d\t%d\t%s\n", p.Threshold, p.Precision, p.Recall, p.F1, p.TP, p.FP, p.FN, p.TN, mark)
+	}
+	w.Flush()
+}
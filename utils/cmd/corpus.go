@@ -0,0 +1,99 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"log"
+	"path/filepath"
+
+	specembedding "github.com/carlisia/mcp-factcheck/embedding"
+	mcpembedding "github.com/carlisia/mcp-factcheck/internal/embedding"
+	"github.com/carlisia/mcp-factcheck/utils/embedding"
+	utilspecs "github.com/carlisia/mcp-factcheck/utils/specs"
+	"github.com/spf13/cobra"
+)
+
+var corpusCmd = &cobra.Command{
+	Use:   "corpus",
+	Short: "Ingest a directory of markdown docs as a named corpus",
+	Long: `Chunk and embed every markdown file under --dir into a named documentation corpus,
+stored in <data-dir>/corpora/<name>.json.
+
+Pass the name as the corpus argument of validate_content or search_spec to check
+content against these docs alongside the MCP specification. Running the command
+again replaces the corpus; with --incremental, unchanged chunks keep their embeddings.`,
+	RunE: runCorpus,
+}
+
+var (
+	corpusName        string
+	corpusDir         string
+	corpusDataDir     string
+	corpusBaseURL     string
+	corpusIncremental bool
+	corpusBatch       = embedding.DefaultBatchOptions()
+)
+
+func init() {
+	corpusCmd.Flags().StringVar(&corpusName, "name", "", "Corpus name, used as the corpus argument of the MCP tools (required)")
+	corpusCmd.Flags().StringVar(&corpusDir, "dir", "", "Directory of markdown files to ingest (required)")
+	corpusCmd.Flags().StringVar(&corpusBaseURL, "base-url", "", "URL the docs are published at, so matches link to the page and section they came from")
+	corpusCmd.Flags().StringVar(&corpusDataDir, "data-dir", "./data/embeddings", "Directory to store vector database")
+	corpusCmd.Flags().IntVar(&corpusBatch.BatchSize, "batch-size", corpusBatch.BatchSize, "Maximum chunks sent per embeddings request (1-2048)")
+	corpusCmd.Flags().IntVar(&corpusBatch.MaxRetries, "max-retries", corpusBatch.MaxRetries, "Retries per batch on rate limits and transient errors")
+	corpusCmd.Flags().BoolVar(&corpusIncremental, "incremental", false, "Reuse stored embeddings for unchanged chunks and only embed new or modified ones")
+
+	corpusCmd.MarkFlagRequired("name")
+	corpusCmd.MarkFlagRequired("dir")
+}
+
+func runCorpus(cmd *cobra.Command, args []string) error {
+	if err := mcpembedding.ValidateCorpusName(corpusName); err != nil {
+		return err
+	}
+
+	chunks, err := utilspecs.LoadMarkdownDir(corpusDir, corpusBaseURL)
+	if err != nil {
+		return fmt.Errorf("failed to load corpus: %w", err)
+	}
+	log.Printf("Loaded %d chunks from %s", len(chunks), corpusDir)
+
+	corpusBatch.Progress = func(done, total int) {
+		log.Printf("Embedded %d/%d chunks", done, total)
+	}
+	generator, err := embedding.NewBatchGenerator(corpusBatch)
+	if err != nil {
+		return fmt.Errorf("failed to create embedding generator: %w", err)
+	}
+
+	storeDir := filepath.Join(corpusDataDir, mcpembedding.CorporaDir)
+	embeddingStore := newEmbeddingStore(storeDir)
+
+	var previous *specembedding.SpecEmbedding
+	if corpusIncremental {
+		previous, err = embeddingStore.Load(corpusName)
+		switch {
+		case errors.Is(err, fs.ErrNotExist):
+			log.Printf("No stored embeddings for corpus %s, embedding all chunks", corpusName)
+		case err != nil:
+			return fmt.Errorf("failed to load stored embeddings: %w", err)
+		}
+	}
+
+	corpusEmbedding, reused, err := generator.UpdateSpecEmbeddings(corpusName, chunks, previous)
+	if err != nil {
+		return fmt.Errorf("failed to generate embeddings: %w", err)
+	}
+	log.Printf("Generated embeddings for %d chunks (%d reused, %d embedded)", corpusEmbedding.Count, reused, corpusEmbedding.Count-reused)
+
+	for i := range corpusEmbedding.Chunks {
+		corpusEmbedding.Chunks[i].Metadata[mcpembedding.CorpusMetadataKey] = corpusName
+	}
+
+	if err := embeddingStore.Store(corpusEmbedding); err != nil {
+		return fmt.Errorf("failed to store embeddings: %w", err)
+	}
+	log.Printf("Stored corpus %s in %s", corpusName, storeDir)
+	return nil
+}
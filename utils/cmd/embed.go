@@ -1,11 +1,12 @@
 package main
 
 import (
-	"encoding/json"
+	"errors"
 	"fmt"
+	"io/fs"
 	"log"
-	"os"
 
+	specembedding "github.com/carlisia/mcp-factcheck/embedding"
 	"github.com/carlisia/mcp-factcheck/utils/embedding"
 	"github.com/spf13/cobra"
 )
@@ -13,18 +14,26 @@ import (
 var embedCmd = &cobra.Command{
 	Use:   "embed",
 	Short: "Generate embeddings from local spec files",
-	Long:  "Generate embeddings from existing spec JSON files in data/specs/",
+	Long: `Generate embeddings from existing spec JSON files in data/specs/.
+
+With --incremental, embeddings already stored for the version are reused for chunks whose
+content is unchanged, and only new or modified chunks are sent to OpenAI.`,
 	RunE:  runEmbed,
 }
 
 var (
-	embedVersion string
-	embedDataDir string
+	embedVersion     string
+	embedDataDir     string
+	embedIncremental bool
+	embedBatch       = embedding.DefaultBatchOptions()
 )
 
 func init() {
 	embedCmd.Flags().StringVar(&embedVersion, "version", "", "MCP spec version to generate embeddings for (required)")
 	embedCmd.Flags().StringVar(&embedDataDir, "data-dir", "./data/embeddings", "Directory to store vector database")
+	embedCmd.Flags().IntVar(&embedBatch.BatchSize, "batch-size", embedBatch.BatchSize, "Maximum chunks sent per embeddings request (1-2048)")
+	embedCmd.Flags().IntVar(&embedBatch.MaxRetries, "max-retries", embedBatch.MaxRetries, "Retries per batch on rate limits and transient errors")
+	embedCmd.Flags().BoolVar(&embedIncremental, "incremental", false, "Reuse stored embeddings for unchanged chunks and only embed new or modified ones")
 	
 	embedCmd.MarkFlagRequired("version")
 }
@@ -34,33 +43,60 @@ func runEmbed(cmd *cobra.Command, args []string) error {
 	log.Printf("Generating embeddings for MCP specification version: %s", embedVersion)
 
 	// Load chunks from local JSON file
-	specFile := fmt.Sprintf("./data/specs/%s-spec.json", embedVersion)
-	chunks, err := loadChunksFromJSON(specFile)
+	specPath := fmt.Sprintf("./data/specs/%s-spec.json", embedVersion)
+	extracted, err := readSpecFile(specPath)
+	if err == nil && len(extracted.Chunks) == 0 {
+		err = fmt.Errorf("no chunks found in file")
+	}
 	if err != nil {
-		return fmt.Errorf("failed to load chunks from %s: %w", specFile, err)
+		return fmt.Errorf("failed to load chunks from %s: %w", specPath, err)
 	}
+	chunks := extracted.Chunks
 
-	log.Printf("Successfully loaded %d chunks from %s", len(chunks), specFile)
+	log.Printf("Successfully loaded %d chunks from %s", len(chunks), specPath)
 
 	// Generate embeddings
 	log.Println("Generating embeddings...")
 	
 	// Create batch embedding generator
-	generator, err := embedding.NewBatchGenerator()
+	embedBatch.Progress = func(done, total int) {
+		log.Printf("Embedded %d/%d chunks", done, total)
+	}
+	generator, err := embedding.NewBatchGenerator(embedBatch)
 	if err != nil {
 		return fmt.Errorf("failed to create embedding generator: %w", err)
 	}
 
-	// Generate embeddings for all chunks
-	specEmbedding, err := generator.GenerateSpecEmbeddings(embedVersion, chunks)
+	embeddingStore := newEmbeddingStore(embedDataDir)
+
+	// Load the previous embeddings so unchanged chunks can be reused
+	var previous *specembedding.SpecEmbedding
+	if embedIncremental {
+		previous, err = embeddingStore.Load(embedVersion)
+		switch {
+		case errors.Is(err, fs.ErrNotExist):
+			log.Printf("No stored embeddings for %s, embedding all chunks", embedVersion)
+		case err != nil:
+			return fmt.Errorf("failed to load stored embeddings: %w", err)
+		}
+	}
+
+	// Generate embeddings for new and changed chunks
+	specEmbedding, reused, err := generator.UpdateSpecEmbeddings(embedVersion, chunks, previous)
 	if err != nil {
 		return fmt.Errorf("failed to generate embeddings: %w", err)
 	}
 
-	log.Printf("Generated embeddings for %d chunks", specEmbedding.Count)
+	log.Printf("Generated embeddings for %d chunks (%d reused, %d embedded)", specEmbedding.Count, reused, specEmbedding.Count-reused)
+
+	// Carry the commit the chunks were extracted at into the embeddings
+	specEmbedding.Commit = extracted.Commit
+	specEmbedding.CommittedAt = extracted.CommittedAt
+	if extracted.Commit != "" {
+		log.Printf("Spec extracted at commit %s", extracted.Commit)
+	}
 
 	// Store in embedding database
-	embeddingStore := embedding.NewEmbeddingStore(embedDataDir)
 	if err := embeddingStore.Store(specEmbedding); err != nil {
 		return fmt.Errorf("failed to store embeddings: %w", err)
 	}
@@ -69,27 +105,3 @@ func runEmbed(cmd *cobra.Command, args []string) error {
 	log.Printf("Embedding generation complete for version %s", embedVersion)
 	return nil
 }
-
-func loadChunksFromJSON(filePath string) ([]string, error) {
-	file, err := os.Open(filePath)
-	if err != nil {
-		return nil, fmt.Errorf("failed to open file: %w", err)
-	}
-	defer file.Close()
-
-	var data struct {
-		Chunks []string `json:"chunks"`
-		Count  int      `json:"count"`
-	}
-
-	decoder := json.NewDecoder(file)
-	if err := decoder.Decode(&data); err != nil {
-		return nil, fmt.Errorf("failed to decode JSON: %w", err)
-	}
-
-	if len(data.Chunks) == 0 {
-		return nil, fmt.Errorf("no chunks found in file")
-	}
-
-	return data.Chunks, nil
-}
\ No newline at end of file
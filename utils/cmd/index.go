@@ -0,0 +1,60 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"path/filepath"
+	"slices"
+	"time"
+
+	mcpembedding "github.com/carlisia/mcp-factcheck/internal/embedding"
+	"github.com/spf13/cobra"
+)
+
+var indexCmd = &cobra.Command{
+	Use:   "index",
+	Short: "Build approximate search indexes for stored embeddings",
+	Long: `Build the HNSW index of every spec version and corpus in --data-dir and store it
+next to the embeddings as <version>.hnsw.
+
+embed, corpus, watch, and reembed build the index of every version they write, so this
+is only needed for embeddings written by older builds or copied in, such as the JSON
+files in data/embeddings. Versions without a current index are searched exactly.`,
+	RunE: runIndex,
+}
+
+var (
+	indexDataDir  string
+	indexVersions []string
+)
+
+func init() {
+	indexCmd.Flags().StringVar(&indexDataDir, "data-dir", "./data/embeddings", "Directory containing the embeddings to index")
+	indexCmd.Flags().StringSliceVar(&indexVersions, "version", nil, "Spec versions or corpus names to index (default all)")
+}
+
+func runIndex(cmd *cobra.Command, args []string) error {
+	indexed := 0
+	for _, dir := range []string{indexDataDir, filepath.Join(indexDataDir, mcpembedding.CorporaDir)} {
+		store := newEmbeddingStore(dir)
+		names, err := store.List()
+		if err != nil {
+			return err
+		}
+		for _, name := range names {
+			if len(indexVersions) > 0 && !slices.Contains(indexVersions, name) {
+				continue
+			}
+			start := time.Now()
+			if err := store.BuildIndex(name); err != nil {
+				return fmt.Errorf("failed to index %s: %w", name, err)
+			}
+			log.Printf("Indexed %s in %s", name, time.Since(start).Round(time.Millisecond))
+			indexed++
+		}
+	}
+	if indexed == 0 {
+		return fmt.Errorf("no embeddings to index in %s", indexDataDir)
+	}
+	return nil
+}
@@ -3,7 +3,11 @@ package main
 import (
 	"fmt"
 	"os"
+	"strings"
 
+	specembedding "github.com/carlisia/mcp-factcheck/embedding"
+	"github.com/carlisia/mcp-factcheck/utils/embedding"
+	"github.com/carlisia/mcp-factcheck/vectorstore"
 	"github.com/joho/godotenv"
 	"github.com/spf13/cobra"
 )
@@ -12,12 +16,50 @@ var rootCmd = &cobra.Command{
 	Use:   "specloader",
 	Short: "Utility tool for managing MCP fact-check specifications",
 	Long:  "A utility tool for extracting, embedding, and managing MCP specification versions for the fact-check server.",
+	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+		var err error
+		if storeFormat, err = vectorstore.ParseFormat(storeFormatName); err != nil {
+			return err
+		}
+		// Use the same model as the server, or its searches refuse the embeddings
+		model, err := specembedding.ResolveModel(embeddingModel, embeddingDimensions)
+		if err != nil {
+			return err
+		}
+		return specembedding.SetModel(model)
+	},
 }
 
+var (
+	embeddingModel      string
+	embeddingDimensions int
+	storeFormatName     string
+	storeFormat         vectorstore.Format
+)
+
 func init() {
+	rootCmd.PersistentFlags().StringVar(&embeddingModel, "embedding-model", "", fmt.Sprintf("Embedding model: %s (default: $%s or %s)", strings.Join(specembedding.Models, ", "), specembedding.ModelEnvVar, specembedding.DefaultModel))
+	rootCmd.PersistentFlags().IntVar(&embeddingDimensions, "embedding-dimensions", 0, fmt.Sprintf("Vector length requested from text-embedding-3 models (default: $%s or the model's full length)", specembedding.DimensionsEnvVar))
+	rootCmd.PersistentFlags().StringVar(&storeFormatName, "store-format", string(vectorstore.DefaultFormat), fmt.Sprintf("Format new embeddings are written in: %v. Stored files of any format are read", vectorstore.Formats))
+
 	rootCmd.AddCommand(specCmd)
 	rootCmd.AddCommand(embedCmd)
+	rootCmd.AddCommand(corpusCmd)
+	rootCmd.AddCommand(watchCmd)
+	rootCmd.AddCommand(bundleCmd)
+	rootCmd.AddCommand(verifyCmd)
+	rootCmd.AddCommand(reembedCmd)
+	rootCmd.AddCommand(indexCmd)
+	rootCmd.AddCommand(requirementsCmd)
 	rootCmd.AddCommand(testCmd)
+	rootCmd.AddCommand(calibrateCmd)
+}
+
+// newEmbeddingStore opens the embeddings in dir, writing them in --store-format
+func newEmbeddingStore(dir string) *embedding.EmbeddingStore {
+	store := embedding.NewEmbeddingStore(dir)
+	store.SetFormat(storeFormat)
+	return store
 }
 
 func main() {
@@ -0,0 +1,91 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"path/filepath"
+	"slices"
+
+	mcpembedding "github.com/carlisia/mcp-factcheck/internal/embedding"
+	"github.com/carlisia/mcp-factcheck/utils/embedding"
+	"github.com/spf13/cobra"
+)
+
+var reembedCmd = &cobra.Command{
+	Use:   "reembed",
+	Short: "Migrate stored embeddings to the current embedding model",
+	Long: `Embed the stored chunks of every spec version and corpus in --data-dir again with
+the current embedding model, keeping chunk IDs and metadata. Files already embedded
+with that model are skipped unless --force is set.
+
+The chunks are read from the stored embeddings, so the spec doesn't need to be
+extracted again. The server refuses to search embeddings made with a different model
+than its queries, since their similarities are meaningless.`,
+	RunE: runReembed,
+}
+
+var (
+	reembedDataDir  string
+	reembedVersions []string
+	reembedForce    bool
+	reembedBatch    = embedding.DefaultBatchOptions()
+)
+
+func init() {
+	reembedCmd.Flags().StringVar(&reembedDataDir, "data-dir", "./data/embeddings", "Directory containing the embeddings to migrate")
+	reembedCmd.Flags().StringSliceVar(&reembedVersions, "version", nil, "Spec versions or corpus names to migrate (default all)")
+	reembedCmd.Flags().BoolVar(&reembedForce, "force", false, "Re-embed files already embedded with the current model")
+	reembedCmd.Flags().IntVar(&reembedBatch.BatchSize, "batch-size", reembedBatch.BatchSize, "Maximum chunks sent per embeddings request (1-2048)")
+	reembedCmd.Flags().IntVar(&reembedBatch.MaxRetries, "max-retries", reembedBatch.MaxRetries, "Retries per batch on rate limits and transient errors")
+}
+
+func runReembed(cmd *cobra.Command, args []string) error {
+	reembedBatch.Progress = func(done, total int) {
+		log.Printf("Embedded %d/%d chunks", done, total)
+	}
+	generator, err := embedding.NewBatchGenerator(reembedBatch)
+	if err != nil {
+		return fmt.Errorf("failed to create embedding generator: %w", err)
+	}
+	model := generator.Model()
+
+	migrated, matched := 0, 0
+	for _, dir := range []string{reembedDataDir, filepath.Join(reembedDataDir, mcpembedding.CorporaDir)} {
+		store := newEmbeddingStore(dir)
+		names, err := store.List()
+		if err != nil {
+			return err
+		}
+		for _, name := range names {
+			if len(reembedVersions) > 0 && !slices.Contains(reembedVersions, name) {
+				continue
+			}
+			matched++
+
+			stored, err := store.Load(name)
+			if err != nil {
+				return fmt.Errorf("failed to load %s: %w", name, err)
+			}
+			if !reembedForce && stored.CheckModel(model) == nil {
+				log.Printf("%s is already embedded with %s, skipping", name, model.Name)
+				continue
+			}
+
+			log.Printf("Re-embedding %d chunks of %s from %s to %s", len(stored.Chunks), name, stored.EmbeddingModel(), model.Name)
+			reembedded, err := generator.Reembed(stored)
+			if err != nil {
+				return fmt.Errorf("failed to re-embed %s: %w", name, err)
+			}
+			if err := store.Store(reembedded); err != nil {
+				return fmt.Errorf("failed to store %s: %w", name, err)
+			}
+			migrated++
+		}
+	}
+
+	if len(reembedVersions) > 0 && matched < len(reembedVersions) {
+		return fmt.Errorf("found %d of the %d requested versions in %s", matched, len(reembedVersions), reembedDataDir)
+	}
+	log.Printf("Re-embedded %d of %d files with %s", migrated, matched, model)
+	return nil
+}
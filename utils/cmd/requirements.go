@@ -0,0 +1,98 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/carlisia/mcp-factcheck/internal/requirements"
+	"github.com/carlisia/mcp-factcheck/internal/specs"
+	"github.com/spf13/cobra"
+)
+
+var requirementsCmd = &cobra.Command{
+	Use:   "requirements",
+	Short: "Extract the MUST/SHOULD/MAY requirements of extracted specs",
+	Long: `Parse the spec files extracted by ` + "`specloader spec`" + ` for RFC 2119 normative
+statements (MUST, SHOULD, MAY and their variants) and store them as a requirements
+catalog per version in --output-dir, where the server's list_requirements tool and
+missing-requirement check read them.
+
+Without a stored catalog, the server extracts one from the embedded chunks on each
+use, so this only saves that work and pins the catalog to the extracted spec. Spec
+files extracted before chunks carried their file path give requirements without a
+page, which the missing-requirement check can't scope.`,
+	RunE: runRequirements,
+}
+
+var (
+	requirementsVersions  []string
+	requirementsSpecsDir  string
+	requirementsOutputDir string
+)
+
+func init() {
+	requirementsCmd.Flags().StringSliceVar(&requirementsVersions, "version", nil, "Spec versions to extract requirements of (default every version in --specs-dir)")
+	requirementsCmd.Flags().StringVar(&requirementsSpecsDir, "specs-dir", "./data/specs", "Directory containing the extracted {version}-spec.json files")
+	requirementsCmd.Flags().StringVar(&requirementsOutputDir, "output-dir", filepath.Join("./data/embeddings", requirements.Dir), "Directory to write the catalogs to: the requirements directory of the server's data dir")
+}
+
+func runRequirements(cmd *cobra.Command, args []string) error {
+	versions := requirementsVersions
+	if len(versions) == 0 {
+		for _, version := range specs.ValidSpecVersions {
+			if _, err := os.Stat(specFilePath(version)); err == nil {
+				versions = append(versions, version)
+			}
+		}
+		if len(versions) == 0 {
+			return fmt.Errorf("no extracted specs in %s; run `specloader spec` first", requirementsSpecsDir)
+		}
+	}
+
+	for _, version := range versions {
+		if !specs.IsValidSpecVersion(version) {
+			return fmt.Errorf("invalid spec version: %s. Valid versions: %v", version, specs.ValidSpecVersions)
+		}
+		data, err := readSpecFile(specFilePath(version))
+		if err != nil {
+			return fmt.Errorf("failed to read spec %s: %w", version, err)
+		}
+
+		located := 0
+		passages := make([]requirements.Passage, len(data.Chunks))
+		for i, chunk := range data.Chunks {
+			passages[i] = requirements.Passage{Content: chunk.Content, FilePath: chunk.FilePath, Section: chunk.Section(), URL: chunk.URL}
+			if chunk.FilePath != "" {
+				located++
+			}
+		}
+		catalog := requirements.Extract(version, passages)
+		catalog.Commit, catalog.CommittedAt = data.Commit, data.CommittedAt
+		if err := requirements.Save(requirementsOutputDir, catalog); err != nil {
+			return fmt.Errorf("failed to save requirements of %s: %w", version, err)
+		}
+
+		counts := map[requirements.Level]int{}
+		for _, r := range catalog.Requirements {
+			counts[r.Level]++
+		}
+		var summary []string
+		for _, level := range requirements.Levels {
+			summary = append(summary, fmt.Sprintf("%d %s", counts[level], level))
+		}
+		log.Printf("Extracted %d requirements of %s (%s) to %s", catalog.Count, version, strings.Join(summary, ", "), requirements.Path(requirementsOutputDir, version))
+		if located == 0 {
+			log.Printf("Warning: the chunks of %s don't record their pages; extract it again with `specloader spec` so requirements can be scoped to pages", version)
+		}
+	}
+	return nil
+}
+
+// specFilePath returns the file `specloader spec` writes a version to by default,
+// in --specs-dir
+func specFilePath(version string) string {
+	return filepath.Join(requirementsSpecsDir, version+"-spec.json")
+}
@@ -6,6 +6,7 @@ import (
 	"log"
 	"os"
 	"path/filepath"
+	"time"
 
 	specs "github.com/carlisia/mcp-factcheck/internal/specs"
 	utilspecs "github.com/carlisia/mcp-factcheck/utils/specs"
@@ -14,19 +15,30 @@ import (
 
 var specCmd = &cobra.Command{
 	Use:   "spec",
-	Short: "Extract MCP specification from GitHub",
-	Long:  "Extract MCP specification content from GitHub and save as JSON files",
+	Short: "Extract MCP specification from GitHub or a local clone",
+	Long: `Extract MCP specification content from GitHub and save as JSON files.
+
+The spec is read at the current head of the main branch, or at --ref (a commit SHA, branch,
+or tag), and the resolved commit is recorded so the embeddings report which commit they
+correspond to. This matters most for the draft spec, which changes between releases.
+
+With --repo-dir, the spec is read from a local clone of modelcontextprotocol/modelcontextprotocol
+instead, which needs no network access and is not subject to GitHub rate limits.`,
 	RunE:  runSpec,
 }
 
 var (
 	specVersion    string
 	specOutputPath string
+	specRepoDir    string
+	specRef        string
 )
 
 func init() {
 	specCmd.Flags().StringVar(&specVersion, "version", "", "MCP spec version to extract (required)")
 	specCmd.Flags().StringVar(&specOutputPath, "output", "", "Output path for spec JSON file (default: ./data/specs/{version}-spec.json)")
+	specCmd.Flags().StringVar(&specRef, "ref", "", "Commit SHA, branch, or tag of the MCP repository to extract (default: main)")
+	specCmd.Flags().StringVar(&specRepoDir, "repo-dir", "", "Read the spec from a local clone of the MCP repository instead of GitHub")
 	
 	specCmd.MarkFlagRequired("version")
 }
@@ -39,11 +51,38 @@ func runSpec(cmd *cobra.Command, args []string) error {
 
 	log.Printf("Extracting MCP specification version: %s", specVersion)
 
-	// Extract spec content from GitHub
+	// Extract spec content from GitHub or a local clone
 	specPath := utilspecs.BuildSpecPath(specVersion)
 	specSource := utilspecs.SpecSource{
-		Type: "github_repo",
-		Path: specPath,
+		Type:    "github_repo",
+		Path:    specPath,
+		BaseURL: utilspecs.BuildSpecURL(specVersion),
+	}
+	var commit utilspecs.Commit
+	origin := "GitHub"
+	if specRepoDir != "" {
+		if specRef != "" {
+			return fmt.Errorf("--ref cannot be used with --repo-dir; check out the commit in the clone instead")
+		}
+		specSource = utilspecs.SpecSource{
+			Type:    "local_dir",
+			Path:    filepath.Join(specRepoDir, filepath.FromSlash(specPath)),
+			BaseURL: specSource.BaseURL,
+		}
+		origin = specSource.Path
+	} else {
+		// Pin the extraction to one commit so every file is read at the same revision
+		ref := specRef
+		if ref == "" {
+			ref = utilspecs.MCPRepoBranch
+		}
+		resolved, err := utilspecs.ResolveCommit(cmd.Context(), ref)
+		if err != nil {
+			return err
+		}
+		commit = resolved
+		specSource.Ref = commit.SHA
+		origin = fmt.Sprintf("GitHub at %s", commit.SHA)
 	}
 
 	chunks, err := utilspecs.LoadSpec(specSource)
@@ -51,7 +90,7 @@ func runSpec(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("failed to load spec: %w", err)
 	}
 
-	log.Printf("Successfully loaded %d chunks from GitHub", len(chunks))
+	log.Printf("Successfully loaded %d chunks from %s", len(chunks), origin)
 
 	// Set default output path if not specified
 	if specOutputPath == "" {
@@ -59,7 +98,7 @@ func runSpec(cmd *cobra.Command, args []string) error {
 	}
 
 	// Save raw chunks to JSON file
-	if err := saveSpecToFile(chunks, specOutputPath); err != nil {
+	if err := writeSpecFile(newSpecFile(specVersion, commit, chunks), specOutputPath); err != nil {
 		return fmt.Errorf("failed to save to file: %w", err)
 	}
 	log.Printf("Saved spec chunks to: %s", specOutputPath)
@@ -68,28 +107,63 @@ func runSpec(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
-func saveSpecToFile(chunks []string, path string) error {
+// specFile is the extracted spec JSON consumed by embed
+type specFile struct {
+	Chunks      []utilspecs.Chunk `json:"chunks"`
+	Commit      string     `json:"commit,omitempty"`       // MCP repository commit the chunks were extracted at, when known
+	CommittedAt *time.Time `json:"committed_at,omitempty"` // When that commit was made
+	Count       int        `json:"count"`
+	Version     string     `json:"version"`
+}
+
+// newSpecFile builds the spec JSON for chunks extracted at commit, which may be zero
+func newSpecFile(version string, commit utilspecs.Commit, chunks []utilspecs.Chunk) specFile {
+	data := specFile{Version: version, Commit: commit.SHA, Chunks: chunks, Count: len(chunks)}
+	if !commit.Date.IsZero() {
+		date := commit.Date.UTC()
+		data.CommittedAt = &date
+	}
+	return data
+}
+
+// readSpecFile reads an extracted spec JSON file
+func readSpecFile(path string) (specFile, error) {
+	var data specFile
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return data, err
+	}
+	if err := json.Unmarshal(content, &data); err != nil {
+		return data, fmt.Errorf("failed to decode %s: %w", path, err)
+	}
+	return data, nil
+}
+
+// writeSpecFile writes extracted chunks through a temporary file so readers never see a partial file
+func writeSpecFile(data specFile, path string) error {
 	// Create directory if it doesn't exist
 	dir := filepath.Dir(path)
 	if err := os.MkdirAll(dir, 0755); err != nil {
 		return err
 	}
 
-	// Create extraction data structure
-	specData := map[string]any{
-		"version": specVersion,
-		"chunks":  chunks,
-		"count":   len(chunks),
-	}
-
-	// Write to JSON file
-	file, err := os.Create(path)
+	file, err := os.CreateTemp(dir, "."+filepath.Base(path)+".tmp-*")
 	if err != nil {
 		return err
 	}
-	defer file.Close()
+	defer os.Remove(file.Name())
 
 	encoder := json.NewEncoder(file)
 	encoder.SetIndent("", "  ")
-	return encoder.Encode(specData)
-}
\ No newline at end of file
+	if err := encoder.Encode(data); err != nil {
+		file.Close()
+		return err
+	}
+	if err := file.Close(); err != nil {
+		return err
+	}
+	if err := os.Chmod(file.Name(), 0644); err != nil {
+		return err
+	}
+	return os.Rename(file.Name(), path)
+}
@@ -1,10 +1,12 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"log"
 
 	"github.com/carlisia/mcp-factcheck/utils/embedding"
+	utilspecs "github.com/carlisia/mcp-factcheck/utils/specs"
 	"github.com/spf13/cobra"
 )
 
@@ -20,15 +22,15 @@ func runTest(cmd *cobra.Command, args []string) error {
 	log.Println("Testing embedding generation...")
 
 	// Create test chunks
-	testChunks := []string{
-		"The Model Context Protocol (MCP) is a protocol for integrating AI assistants with external systems.",
-		"MCP servers expose resources and tools that clients can discover and use.",
-		"Resources in MCP represent data that can be read by clients, such as files or database records.",
-		"Tools in MCP represent actions that can be performed by clients, such as executing code or making API calls.",
+	testChunks := []utilspecs.Chunk{
+		{Content: "The Model Context Protocol (MCP) is a protocol for integrating AI assistants with external systems."},
+		{Content: "MCP servers expose resources and tools that clients can discover and use."},
+		{Content: "Resources in MCP represent data that can be read by clients, such as files or database records."},
+		{Content: "Tools in MCP represent actions that can be performed by clients, such as executing code or making API calls."},
 	}
 
 	// Create batch embedding generator
-	generator, err := embedding.NewBatchGenerator()
+	generator, err := embedding.NewBatchGenerator(embedding.DefaultBatchOptions())
 	if err != nil {
 		return fmt.Errorf("failed to create embedding generator: %w", err)
 	}
@@ -54,7 +56,7 @@ func runTest(cmd *cobra.Command, args []string) error {
 	if err != nil {
 		return fmt.Errorf("failed to create query generator: %w", err)
 	}
-	queryEmbedding, err := queryGenerator.GenerateEmbedding("What are MCP tools?")
+	queryEmbedding, err := queryGenerator.GenerateEmbedding(context.Background(), "What are MCP tools?")
 	if err != nil {
 		return fmt.Errorf("failed to generate query embedding: %w", err)
 	}
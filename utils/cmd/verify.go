@@ -0,0 +1,101 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+
+	specembedding "github.com/carlisia/mcp-factcheck/embedding"
+	mcpembedding "github.com/carlisia/mcp-factcheck/internal/embedding"
+	"github.com/carlisia/mcp-factcheck/utils/embedding"
+	"github.com/spf13/cobra"
+)
+
+var verifyCmd = &cobra.Command{
+	Use:   "verify",
+	Short: "Check stored spec and corpus embeddings for corruption",
+	Long: `Check every stored version in --data-dir and every corpus in --data-dir/corpora.
+
+A file fails if it can't be decoded, has no chunks, records a different version than
+its name, has chunks with duplicate or missing IDs, empty content or embeddings, or
+mixed dimensions, or wasn't embedded with the server's model. Exits non-zero if any
+file fails.`,
+	RunE:         runVerify,
+	SilenceUsage: true, // Failures are findings, not usage errors
+}
+
+var verifyDataDir string
+
+func init() {
+	verifyCmd.Flags().StringVar(&verifyDataDir, "data-dir", "./data/embeddings", "Directory containing the embeddings to verify")
+}
+
+func runVerify(cmd *cobra.Command, args []string) error {
+	out := cmd.OutOrStdout()
+	checked, failed := 0, 0
+	for _, dir := range []string{verifyDataDir, filepath.Join(verifyDataDir, mcpembedding.CorporaDir)} {
+		store := embedding.NewEmbeddingStore(dir)
+		names, err := store.List()
+		if err != nil {
+			return err
+		}
+		for _, name := range names {
+			label := name
+			if dir != verifyDataDir {
+				label = mcpembedding.CorporaDir + "/" + name
+			}
+
+			checked++
+			summary, problems := verifyEmbedding(store, name)
+			if len(problems) == 0 {
+				fmt.Fprintf(out, "ok    %s: %s\n", label, summary)
+				continue
+			}
+			failed++
+			fmt.Fprintf(out, "FAIL  %s\n", label)
+			for _, problem := range problems {
+				fmt.Fprintf(out, "        %s\n", problem)
+			}
+		}
+	}
+
+	if checked == 0 {
+		return fmt.Errorf("no embeddings found in %s", verifyDataDir)
+	}
+	fmt.Fprintf(out, "\nVerified %d files: %d ok, %d failed\n", checked, checked-failed, failed)
+	if failed > 0 {
+		return fmt.Errorf("%d of %d embedding files failed verification; regenerate them with `specloader spec` and `specloader embed` (or `specloader corpus`), or migrate model mismatches with `specloader reembed`", failed, checked)
+	}
+	return nil
+}
+
+// verifyEmbedding loads one stored embedding and returns a summary of it, or the problems found
+func verifyEmbedding(store *embedding.EmbeddingStore, name string) (string, []string) {
+	spec, err := store.Load(name)
+	if err != nil {
+		return "", []string{decodeProblem(err)}
+	}
+
+	problems := specembedding.Verify(spec, name)
+	if err := spec.CheckModel(specembedding.CurrentModel()); err != nil {
+		problems = append(problems, err.Error())
+	}
+	return fmt.Sprintf("%d chunks, %s, %d dimensions", len(spec.Chunks), spec.EmbeddingModel(), spec.EmbeddingDimensions()), problems
+}
+
+// decodeProblem explains a load error, pointing at where the JSON broke
+func decodeProblem(err error) string {
+	var syntax *json.SyntaxError
+	var typeErr *json.UnmarshalTypeError
+	switch {
+	case errors.Is(err, io.ErrUnexpectedEOF):
+		return "file is truncated: the JSON ends before the document is complete"
+	case errors.As(err, &syntax):
+		return fmt.Sprintf("invalid JSON at byte %d: %v", syntax.Offset, syntax)
+	case errors.As(err, &typeErr):
+		return fmt.Sprintf("unexpected %s for field %q at byte %d", typeErr.Value, typeErr.Field, typeErr.Offset)
+	}
+	return err.Error()
+}
@@ -0,0 +1,175 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"slices"
+	"syscall"
+	"time"
+
+	specembedding "github.com/carlisia/mcp-factcheck/embedding"
+	specs "github.com/carlisia/mcp-factcheck/internal/specs"
+	"github.com/carlisia/mcp-factcheck/utils/embedding"
+	utilspecs "github.com/carlisia/mcp-factcheck/utils/specs"
+	"github.com/spf13/cobra"
+)
+
+var watchCmd = &cobra.Command{
+	Use:   "watch",
+	Short: "Keep spec embeddings in sync with the MCP repository",
+	Long: `Poll the MCP GitHub repository and refresh spec versions whose pages changed.
+
+Each poll costs one GitHub request per version. When a version has a new commit, it is
+re-extracted and, if its content changed, re-embedded incrementally. The spec JSON and the
+embeddings file are each replaced atomically, so a server using --data-dir picks up the new
+spec on its next request without a restart.`,
+	Example: `  specloader watch --data-dir /srv/factcheck/embeddings --interval 6h
+  specloader watch --version draft --once`,
+	RunE: runWatch,
+}
+
+var (
+	watchVersions []string
+	watchDataDir  string
+	watchSpecsDir string
+	watchInterval time.Duration
+	watchOnce     bool
+	watchBatch    = embedding.DefaultBatchOptions()
+)
+
+func init() {
+	watchCmd.Flags().StringSliceVar(&watchVersions, "version", specs.ValidSpecVersions, "MCP spec versions to keep up to date")
+	watchCmd.Flags().StringVar(&watchDataDir, "data-dir", "./data/embeddings", "Directory to store vector database")
+	watchCmd.Flags().StringVar(&watchSpecsDir, "specs-dir", "./data/specs", "Directory for extracted spec JSON files")
+	watchCmd.Flags().DurationVar(&watchInterval, "interval", time.Hour, "How often to poll GitHub for changes")
+	watchCmd.Flags().BoolVar(&watchOnce, "once", false, "Check each version once and exit")
+	watchCmd.Flags().IntVar(&watchBatch.BatchSize, "batch-size", watchBatch.BatchSize, "Maximum chunks sent per embeddings request (1-2048)")
+	watchCmd.Flags().IntVar(&watchBatch.MaxRetries, "max-retries", watchBatch.MaxRetries, "Retries per batch on rate limits and transient errors")
+}
+
+func runWatch(cmd *cobra.Command, args []string) error {
+	for _, version := range watchVersions {
+		if !specs.IsValidSpecVersion(version) {
+			return fmt.Errorf("invalid spec version: %s. Valid versions: %v", version, specs.ValidSpecVersions)
+		}
+	}
+	if watchInterval < time.Minute {
+		return fmt.Errorf("interval must be at least 1m, got %s", watchInterval)
+	}
+
+	generator, err := embedding.NewBatchGenerator(watchBatch)
+	if err != nil {
+		return fmt.Errorf("failed to create embedding generator: %w", err)
+	}
+	store := newEmbeddingStore(watchDataDir)
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	ticker := time.NewTicker(watchInterval)
+	defer ticker.Stop()
+	for {
+		failed := 0
+		for _, version := range watchVersions {
+			if err := syncVersion(ctx, generator, store, version); err != nil {
+				// Keep serving the previous files; the next poll retries
+				log.Printf("Failed to update %s: %v", version, err)
+				failed++
+			}
+		}
+		if watchOnce {
+			if failed > 0 {
+				return fmt.Errorf("%d of %d versions failed to update", failed, len(watchVersions))
+			}
+			return nil
+		}
+
+		log.Printf("Next check in %s", watchInterval)
+		select {
+		case <-ctx.Done():
+			log.Printf("Stopping spec watch")
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// syncVersion re-extracts and re-embeds a spec version if the repository changed since
+// the commit recorded in its spec JSON
+func syncVersion(ctx context.Context, generator *embedding.BatchGenerator, store *embedding.EmbeddingStore, version string) error {
+	repoPath := utilspecs.BuildSpecPath(version)
+	commit, err := utilspecs.LatestCommit(ctx, repoPath)
+	if err != nil {
+		return err
+	}
+
+	path := filepath.Join(watchSpecsDir, fmt.Sprintf("%s-spec.json", version))
+	current, err := readSpecFile(path)
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+
+	previous, err := store.Load(version)
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("failed to load stored embeddings: %w", err)
+	}
+
+	if current.Commit == commit.SHA && previous != nil && previous.Commit == commit.SHA {
+		log.Printf("%s is up to date at %s", version, shortSHA(commit.SHA))
+		return nil
+	}
+
+	log.Printf("Extracting %s at %s", version, shortSHA(commit.SHA))
+	chunks, err := utilspecs.LoadSpec(utilspecs.SpecSource{Type: "github_repo", Path: repoPath, Ref: commit.SHA, BaseURL: utilspecs.BuildSpecURL(version)})
+	if err != nil {
+		return fmt.Errorf("failed to load spec: %w", err)
+	}
+
+	// A new commit under the version directory may not change any page content, in
+	// which case only the recorded commit is updated
+	var specEmbedding *specembedding.SpecEmbedding
+	reused := 0
+	if previous != nil && slices.EqualFunc(chunks, current.Chunks, utilspecs.Chunk.Equal) {
+		specEmbedding = previous
+		reused = previous.Count
+		log.Printf("%s content unchanged at %s", version, shortSHA(commit.SHA))
+	} else if specEmbedding, reused, err = generator.UpdateSpecEmbeddings(version, chunks, previous); err != nil {
+		return fmt.Errorf("failed to generate embeddings: %w", err)
+	}
+	pinCommit(specEmbedding, commit)
+
+	// Store the embeddings before recording the commit, so an interrupted update is
+	// retried on the next poll
+	if err := store.Store(specEmbedding); err != nil {
+		return fmt.Errorf("failed to store embeddings: %w", err)
+	}
+	if err := writeSpecFile(newSpecFile(version, commit, chunks), path); err != nil {
+		return fmt.Errorf("failed to save %s: %w", path, err)
+	}
+
+	log.Printf("Updated %s to %s: %d chunks (%d reused, %d embedded)", version, shortSHA(commit.SHA), specEmbedding.Count, reused, specEmbedding.Count-reused)
+	return nil
+}
+
+func shortSHA(sha string) string {
+	if len(sha) > 7 {
+		return sha[:7]
+	}
+	return sha
+}
+
+// pinCommit records the commit a spec embedding was extracted at
+func pinCommit(specEmbedding *specembedding.SpecEmbedding, commit utilspecs.Commit) {
+	specEmbedding.Commit = commit.SHA
+	specEmbedding.CommittedAt = nil
+	if !commit.Date.IsZero() {
+		date := commit.Date.UTC()
+		specEmbedding.CommittedAt = &date
+	}
+}
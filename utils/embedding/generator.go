@@ -1,24 +1,66 @@
 package embedding
 
 import (
+	"context"
 	"crypto/sha256"
 	"fmt"
+	"time"
 
 	"github.com/carlisia/mcp-factcheck/embedding"
+	"github.com/carlisia/mcp-factcheck/utils/specs"
 )
 
+// BatchOptions controls how chunks are grouped into embedding requests
+type BatchOptions struct {
+	BatchSize     int                   // Maximum chunks per request
+	MaxBatchChars int                   // Maximum total characters per request, keeping requests under the token limit
+	MaxRetries    int                   // Retries per batch for rate limits and transient errors
+	InitialDelay  time.Duration         // Upper bound of the first retry delay; doubles on each attempt
+	Progress      func(done, total int) // Called after each batch, may be nil
+}
+
+// DefaultBatchOptions returns batching settings that stay well within OpenAI's request limits
+func DefaultBatchOptions() BatchOptions {
+	return BatchOptions{
+		BatchSize:     100,
+		MaxBatchChars: 200000,
+		MaxRetries:    5,
+		InitialDelay:  time.Second,
+	}
+}
+
 // BatchGenerator handles batch embedding generation for spec processing
 type BatchGenerator struct {
 	generator *embedding.Generator
+	options   BatchOptions
 }
 
 // NewBatchGenerator creates a new batch embedding generator
-func NewBatchGenerator() (*BatchGenerator, error) {
+func NewBatchGenerator(options BatchOptions) (*BatchGenerator, error) {
+	if options.BatchSize < 1 || options.BatchSize > 2048 {
+		return nil, fmt.Errorf("batch size must be between 1 and 2048, got %d", options.BatchSize)
+	}
+	if options.MaxRetries < 0 {
+		return nil, fmt.Errorf("max retries cannot be negative")
+	}
 	gen, err := embedding.NewGenerator()
 	if err != nil {
 		return nil, err
 	}
-	return &BatchGenerator{generator: gen}, nil
+	// Batches are large and few, so they are retried without client-side pacing
+	policy := embedding.DefaultRetryPolicy()
+	policy.RequestsPerMinute = 0
+	policy.MaxRetries = options.MaxRetries
+	policy.InitialBackoffMS = int(options.InitialDelay.Milliseconds())
+	if err := gen.SetRetryPolicy(policy); err != nil {
+		return nil, err
+	}
+	return &BatchGenerator{generator: gen, options: options}, nil
+}
+
+// Model returns the model chunks are embedded with
+func (g *BatchGenerator) Model() embedding.Model {
+	return g.generator.Model()
 }
 
 // NewGenerator creates a new generator (alias for compatibility)
@@ -26,51 +68,158 @@ func NewGenerator() (*embedding.Generator, error) {
 	return embedding.NewGenerator()
 }
 
-// GenerateSpecEmbeddings creates embeddings for all chunks in a spec
-func (g *BatchGenerator) GenerateSpecEmbeddings(version string, chunks []string) (*embedding.SpecEmbedding, error) {
-	var embeddedChunks []embedding.EmbeddedChunk
+// GenerateSpecEmbeddings creates embeddings for all chunks in a spec, several chunks per API request
+func (g *BatchGenerator) GenerateSpecEmbeddings(version string, chunks []specs.Chunk) (*embedding.SpecEmbedding, error) {
+	specEmbedding, _, err := g.UpdateSpecEmbeddings(version, chunks, nil)
+	return specEmbedding, err
+}
+
+// UpdateSpecEmbeddings is GenerateSpecEmbeddings that reuses the embeddings in previous
+// for chunks whose content is unchanged, so only new and modified chunks are sent to
+// OpenAI. It also returns how many chunks were reused. previous may be nil.
+func (g *BatchGenerator) UpdateSpecEmbeddings(version string, chunks []specs.Chunk, previous *embedding.SpecEmbedding) (*embedding.SpecEmbedding, int, error) {
+	ctx := context.Background()
 
-	for i, chunk := range chunks {
-		if len(chunk) == 0 {
-			continue // Skip empty chunks
+	// Chunks are matched by content hash rather than ID, since an edit earlier in the
+	// spec shifts the index of every later chunk. Vectors from another model can't be
+	// mixed with new ones, so then every chunk is embedded again.
+	known := make(map[string][]float64)
+	if previous != nil && previous.CheckModel(g.generator.Model()) == nil {
+		for _, chunk := range previous.Chunks {
+			if len(chunk.Embedding) > 0 {
+				known[contentHash(chunk.Content)] = chunk.Embedding
+			}
 		}
+	}
 
-		// Generate embedding
-		embeddingData, err := g.generator.GenerateEmbedding(chunk)
-		if err != nil {
-			return nil, fmt.Errorf("failed to generate embedding for chunk %d: %w", i, err)
+	// Keep original indexes so chunk IDs stay stable when empty chunks are skipped
+	var indexes []int
+	vectors := make(map[int][]float64)
+	for i, chunk := range chunks {
+		if len(chunk.Content) == 0 {
+			continue
+		}
+		if vector, ok := known[contentHash(chunk.Content)]; ok {
+			vectors[i] = vector
+			continue
 		}
+		indexes = append(indexes, i)
+	}
+	reused := len(vectors)
 
-		// Create chunk ID
-		chunkID := generateChunkID(version, i, chunk)
+	texts := make([]string, len(indexes))
+	for j, i := range indexes {
+		texts[j] = chunks[i].Content
+	}
+	batch, err := g.embedAll(ctx, texts, indexes)
+	if err != nil {
+		return nil, 0, err
+	}
+	for j, i := range indexes {
+		vectors[i] = batch[j]
+	}
 
-		embeddedChunk := embedding.EmbeddedChunk{
-			ID:        chunkID,
+	embeddedChunks := make([]embedding.EmbeddedChunk, 0, len(vectors))
+	for i, chunk := range chunks {
+		vector, ok := vectors[i]
+		if !ok {
+			continue
+		}
+		embeddedChunks = append(embeddedChunks, embedding.EmbeddedChunk{
+			ID:        generateChunkID(version, i, chunk.Content),
 			Version:   version,
-			Content:   chunk,
-			Embedding: embeddingData,
+			FilePath:  chunk.FilePath,
+			Section:   chunk.Section(),
+			URL:       chunk.URL,
+			Content:   chunk.Content,
+			Embedding: vector,
 			Metadata: map[string]any{
-				"chunk_index": i,
-				"length":      len(chunk),
+				"chunk_index":  i,
+				"length":       len(chunk.Content),
+				"content_hash": contentHash(chunk.Content),
 			},
+		})
+	}
+
+	return &embedding.SpecEmbedding{
+		Version:    version,
+		Model:      g.generator.Model().Name,
+		Dimensions: g.generator.Model().Dimensions,
+		Chunks:     embeddedChunks,
+		Count:      len(embeddedChunks),
+	}, reused, nil
+}
+
+// Reembed returns a copy of stored with every chunk embedded again by this generator's
+// model, keeping chunk IDs and metadata. It migrates embeddings made with another
+// model without re-extracting their source.
+func (g *BatchGenerator) Reembed(stored *embedding.SpecEmbedding) (*embedding.SpecEmbedding, error) {
+	texts := make([]string, len(stored.Chunks))
+	indexes := make([]int, len(stored.Chunks))
+	for i, chunk := range stored.Chunks {
+		texts[i], indexes[i] = chunk.Content, i
+	}
+	vectors, err := g.embedAll(context.Background(), texts, indexes)
+	if err != nil {
+		return nil, err
+	}
+
+	reembedded := *stored
+	reembedded.Model = g.generator.Model().Name
+	reembedded.Dimensions = g.generator.Model().Dimensions
+	reembedded.Chunks = make([]embedding.EmbeddedChunk, len(stored.Chunks))
+	for i, chunk := range stored.Chunks {
+		chunk.Embedding = vectors[i]
+		reembedded.Chunks[i] = chunk
+	}
+	reembedded.Count = len(reembedded.Chunks)
+	return &reembedded, nil
+}
+
+// embedAll embeds texts several per request, returning the vectors in the same order.
+// indexes are the texts' chunk indexes, for error messages.
+func (g *BatchGenerator) embedAll(ctx context.Context, texts []string, indexes []int) ([][]float64, error) {
+	vectors := make([][]float64, 0, len(texts))
+	for start := 0; start < len(texts); {
+		end := g.batchEnd(texts, start)
+
+		batch, err := g.generator.GenerateEmbeddings(ctx, texts[start:end])
+		if err != nil {
+			return nil, fmt.Errorf("failed to generate embeddings for chunks %d-%d: %w", indexes[start], indexes[end-1], err)
 		}
+		vectors = append(vectors, batch...)
 
-		embeddedChunks = append(embeddedChunks, embeddedChunk)
+		start = end
+		if g.options.Progress != nil {
+			g.options.Progress(start, len(texts))
+		}
 	}
+	return vectors, nil
+}
 
-	return &embedding.SpecEmbedding{
-		Version: version,
-		Chunks:  embeddedChunks,
-		Count:   len(embeddedChunks),
-	}, nil
+// batchEnd returns the exclusive end of the batch starting at start, bounded by count and characters
+func (g *BatchGenerator) batchEnd(texts []string, start int) int {
+	end := start
+	chars := 0
+	for end < len(texts) && end-start < g.options.BatchSize {
+		size := len(texts[end])
+		// Always take at least one chunk so an oversized chunk still gets sent
+		if end > start && g.options.MaxBatchChars > 0 && chars+size > g.options.MaxBatchChars {
+			break
+		}
+		chars += size
+		end++
+	}
+	return end
 }
 
 // generateChunkID creates a unique ID for a chunk
 func generateChunkID(version string, index int, content string) string {
-	// Create a hash of the content for uniqueness
-	hasher := sha256.New()
-	hasher.Write([]byte(content))
-	hash := fmt.Sprintf("%x", hasher.Sum(nil))[:8]
-	
-	return fmt.Sprintf("%s_%d_%s", version, index, hash)
-}
\ No newline at end of file
+	// Include a prefix of the content hash for uniqueness
+	return fmt.Sprintf("%s_%d_%s", version, index, contentHash(content)[:8])
+}
+
+// contentHash returns the hex SHA-256 of a chunk's content
+func contentHash(content string) string {
+	return fmt.Sprintf("%x", sha256.Sum256([]byte(content)))
+}
@@ -17,7 +17,26 @@ func NewEmbeddingStore(dataDir string) *EmbeddingStore {
 	}
 }
 
+// SetFormat changes the format embeddings are written in
+func (es *EmbeddingStore) SetFormat(format vectorstore.Format) {
+	es.store.SetFormat(format)
+}
+
 // Store saves a spec embedding to the database
 func (es *EmbeddingStore) Store(specEmbedding *embedding.SpecEmbedding) error {
 	return es.store.Store(specEmbedding)
-}
\ No newline at end of file
+}
+// Load reads the stored embeddings for a spec version
+func (es *EmbeddingStore) Load(version string) (*embedding.SpecEmbedding, error) {
+	return es.store.Load(version)
+}
+
+// List returns the versions stored in the data directory
+func (es *EmbeddingStore) List() ([]string, error) {
+	return es.store.ListVersions()
+}
+
+// BuildIndex builds and stores the approximate search index of a stored version
+func (es *EmbeddingStore) BuildIndex(version string) error {
+	return es.store.BuildIndex(version)
+}
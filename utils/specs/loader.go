@@ -3,47 +3,90 @@ package specs
 import (
 	"context"
 	"fmt"
+	"io/fs"
 	"os"
+	"path"
+	"path/filepath"
 	"strings"
+	"unicode"
 
+	"github.com/carlisia/mcp-factcheck/internal/secrets"
 	"github.com/google/go-github/v57/github"
 )
 
 // LoadSpec loads MCP specification from local directory or GitHub repo
-func LoadSpec(source SpecSource) ([]string, error) {
+func LoadSpec(source SpecSource) ([]Chunk, error) {
 	switch source.Type {
 	case "local_dir":
-		return loadSpecFromLocal(source.Path)
+		return LoadMarkdownDir(source.Path, source.BaseURL)
 	case "github_repo":
-		return loadSpecFromMCPRepo(source.Path)
+		ref := source.Ref
+		if ref == "" {
+			ref = MCPRepoBranch
+		}
+		return loadSpecFromMCPRepo(source.Path, ref, source.BaseURL)
 	default:
 		return nil, fmt.Errorf("unsupported spec source type: %s", source.Type)
 	}
 }
 
-// loadSpecFromLocal loads markdown files from a local directory
-func loadSpecFromLocal(specDir string) ([]string, error) {
-	// This is a simplified implementation - the full version would walk directories
-	return nil, fmt.Errorf("local loading not implemented")
-}
+// LoadMarkdownDir loads and chunks every markdown file under dir, such as a version
+// directory inside a clone of the MCP repository or any other documentation set.
+// Chunk URLs are built from baseURL, which may be empty.
+func LoadMarkdownDir(dir, baseURL string) ([]Chunk, error) {
+	info, err := os.Stat(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read directory: %w", err)
+	}
+	if !info.IsDir() {
+		return nil, fmt.Errorf("not a directory: %s", dir)
+	}
+
+	var chunks []Chunk
+
+	// WalkDir visits entries in lexical order, matching the GitHub tree order
+	err = filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() || !isMarkdownFile(path) {
+			return nil
+		}
+
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return fmt.Errorf("failed to read %s: %w", path, err)
+		}
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			rel = path
+		}
 
-// loadSpecFromMCPRepo loads markdown files from the MCP repository using GitHub API
-func loadSpecFromMCPRepo(repoPath string) ([]string, error) {
-	// Create GitHub client
-	var client *github.Client
-	if token := os.Getenv("GITHUB_TOKEN"); token != "" {
-		client = github.NewClient(nil).WithAuthToken(token)
-	} else {
-		client = github.NewClient(nil)
+		chunks = append(chunks, parseMarkdownFile(filepath.ToSlash(rel), string(content), baseURL)...)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if len(chunks) == 0 {
+		return nil, fmt.Errorf("no markdown files found in directory: %s", dir)
 	}
 
+	return dedupeChunks(chunks), nil
+}
+
+// loadSpecFromMCPRepo loads markdown files from the MCP repository at ref using GitHub API
+func loadSpecFromMCPRepo(repoPath, ref, baseURL string) ([]Chunk, error) {
+	client := newGitHubClient()
+
 	// Get directory tree recursively
-	tree, _, err := client.Git.GetTree(context.Background(), MCPRepoOwner, MCPRepoName, MCPRepoBranch, true)
+	tree, _, err := client.Git.GetTree(context.Background(), MCPRepoOwner, MCPRepoName, ref, true)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get GitHub tree: %w", err)
 	}
 
-	var allChunks []string
+	var allChunks []Chunk
 	
 	// Find all markdown files in the specified directory
 	for _, entry := range tree.Entries {
@@ -52,10 +95,10 @@ func loadSpecFromMCPRepo(repoPath string) ([]string, error) {
 		}
 		
 		// Check if file is in the target directory and is a markdown file
-		if strings.HasPrefix(*entry.Path, repoPath) && (strings.HasSuffix(*entry.Path, ".md") || strings.HasSuffix(*entry.Path, ".mdx")) {
+		if strings.HasPrefix(*entry.Path, repoPath) && isMarkdownFile(*entry.Path) {
 			// Get file content
 			fileContent, _, _, err := client.Repositories.GetContents(context.Background(), MCPRepoOwner, MCPRepoName, *entry.Path, &github.RepositoryContentGetOptions{
-				Ref: MCPRepoBranch,
+				Ref: ref,
 			})
 			if err != nil {
 				continue // Skip files we can't read
@@ -67,8 +110,8 @@ func loadSpecFromMCPRepo(repoPath string) ([]string, error) {
 					continue // Skip files we can't decode
 				}
 				
-				chunks := parseMarkdownSections(content)
-				allChunks = append(allChunks, chunks...)
+				rel := strings.TrimPrefix(strings.TrimPrefix(*entry.Path, repoPath), "/")
+				allChunks = append(allChunks, parseMarkdownFile(rel, content, baseURL)...)
 			}
 		}
 	}
@@ -77,21 +120,267 @@ func loadSpecFromMCPRepo(repoPath string) ([]string, error) {
 		return nil, fmt.Errorf("no markdown files found in repository path: %s", repoPath)
 	}
 
-	return allChunks, nil
+	return dedupeChunks(allChunks), nil
 }
 
-// parseMarkdownSections splits markdown content into logical sections
-func parseMarkdownSections(content string) []string {
-	var chunks []string
-	
-	// Split by double newlines to get paragraphs/sections
-	sections := strings.Split(content, "\n\n")
-	for _, section := range sections {
-		trimmed := strings.TrimSpace(section)
-		if len(trimmed) > 0 {
-			chunks = append(chunks, trimmed)
+// LatestCommit returns the most recent commit on the MCP repository's main branch that
+// touched repoPath. It costs one API request, so it can be polled cheaply to decide
+// whether a spec version needs re-extracting.
+func LatestCommit(ctx context.Context, repoPath string) (Commit, error) {
+	commits, _, err := newGitHubClient().Repositories.ListCommits(ctx, MCPRepoOwner, MCPRepoName, &github.CommitsListOptions{
+		SHA:         MCPRepoBranch,
+		Path:        repoPath,
+		ListOptions: github.ListOptions{PerPage: 1},
+	})
+	if err != nil {
+		return Commit{}, fmt.Errorf("failed to list commits for %s: %w", repoPath, err)
+	}
+	if len(commits) == 0 || commits[0].SHA == nil {
+		return Commit{}, fmt.Errorf("no commits found for repository path: %s", repoPath)
+	}
+	return toCommit(commits[0]), nil
+}
+
+// ResolveCommit resolves a branch, tag, or (possibly abbreviated) SHA in the MCP
+// repository to the full commit, so extraction can be pinned to it
+func ResolveCommit(ctx context.Context, ref string) (Commit, error) {
+	commit, _, err := newGitHubClient().Repositories.GetCommit(ctx, MCPRepoOwner, MCPRepoName, ref, nil)
+	if err != nil {
+		return Commit{}, fmt.Errorf("failed to resolve %s: %w", ref, err)
+	}
+	if commit.SHA == nil {
+		return Commit{}, fmt.Errorf("failed to resolve %s: no commit SHA returned", ref)
+	}
+	return toCommit(commit), nil
+}
+
+func toCommit(commit *github.RepositoryCommit) Commit {
+	c := Commit{SHA: commit.GetSHA()}
+	if date := commit.GetCommit().GetCommitter().GetDate(); !date.IsZero() {
+		c.Date = date.Time
+	}
+	return c
+}
+
+// newGitHubClient creates a GitHub client, authenticated when GITHUB_TOKEN is set
+func newGitHubClient() *github.Client {
+	if token, err := secrets.Lookup("GITHUB_TOKEN"); err == nil {
+		return github.NewClient(nil).WithAuthToken(token)
+	}
+	return github.NewClient(nil)
+}
+
+// isMarkdownFile reports whether path names a markdown or MDX page
+func isMarkdownFile(path string) bool {
+	return strings.HasSuffix(path, ".md") || strings.HasSuffix(path, ".mdx")
+}
+
+// stripFrontMatter removes a leading YAML front matter block (between "---" lines)
+// so page metadata such as titles and sidebar settings isn't embedded as spec text
+func stripFrontMatter(content string) string {
+	content = strings.TrimPrefix(content, "\ufeff")
+	normalized := strings.ReplaceAll(content, "\r\n", "\n")
+	if !strings.HasPrefix(normalized, "---\n") {
+		return content
+	}
+	rest := normalized[len("---\n"):]
+	for offset := 0; offset <= len(rest); {
+		end := strings.IndexByte(rest[offset:], '\n')
+		line := rest[offset:]
+		if end >= 0 {
+			line = rest[offset : offset+end]
+		}
+		if strings.TrimRight(line, " \t") == "---" {
+			if end < 0 {
+				return ""
+			}
+			return rest[offset+end+1:]
+		}
+		if end < 0 {
+			break
 		}
+		offset += end + 1
+	}
+	// Unterminated block: leave the content alone rather than dropping the page
+	return content
+}
+
+// parseMarkdownFile chunks one page, labelling each chunk with the file it came from,
+// the headings it falls under, and its published URL when baseURL is set
+func parseMarkdownFile(relPath, content, baseURL string) []Chunk {
+	page := pageURL(relPath, baseURL)
+
+	var chunks []Chunk
+	for _, section := range parseMarkdownSections(stripFrontMatter(content), frontMatterTitle(content)) {
+		chunk := Chunk{Content: section.content, FilePath: relPath, Headings: section.headings}
+		if page != "" {
+			chunk.URL = page
+			if section.anchor != "" {
+				chunk.URL += "#" + section.anchor
+			}
+		}
+		chunks = append(chunks, chunk)
 	}
-	
 	return chunks
-}
\ No newline at end of file
+}
+
+// markdownSection is one paragraph of a page with the headings above it
+type markdownSection struct {
+	content  string
+	headings []string
+	anchor   string // Anchor of the nearest heading; empty above the first heading
+}
+
+// parseMarkdownSections splits markdown content into paragraphs, tracking the heading
+// hierarchy each one falls under. Headings become context for the paragraphs below
+// them rather than chunks of their own, and fenced code blocks are kept whole even
+// when they contain blank lines. title, if set, heads every hierarchy.
+func parseMarkdownSections(content, title string) []markdownSection {
+	var (
+		sections  []markdownSection
+		levels    [7]string // levels[n] is the open heading of level n
+		anchor    string
+		paragraph []string
+		fence     string // Marker of the open code fence, if any
+	)
+
+	flush := func() {
+		text := strings.TrimSpace(strings.Join(paragraph, "\n"))
+		paragraph = paragraph[:0]
+		if text != "" {
+			sections = append(sections, markdownSection{content: text, headings: headingPath(title, levels[:]), anchor: anchor})
+		}
+	}
+
+	for _, line := range strings.Split(strings.ReplaceAll(content, "\r\n", "\n"), "\n") {
+		trimmed := strings.TrimSpace(line)
+		switch {
+		case fence != "":
+			paragraph = append(paragraph, line)
+			if strings.HasPrefix(trimmed, fence) && strings.Trim(trimmed, fence[:1]) == "" {
+				fence = ""
+			}
+		case fenceMarker(trimmed) != "":
+			fence = fenceMarker(trimmed)
+			paragraph = append(paragraph, line)
+		case trimmed == "":
+			flush()
+		default:
+			level, text := parseHeading(trimmed)
+			if level == 0 {
+				paragraph = append(paragraph, line)
+				continue
+			}
+			flush()
+			levels[level] = text
+			clear(levels[level+1:])
+			anchor = headingAnchor(text)
+		}
+	}
+	flush()
+
+	return sections
+}
+
+// fenceMarker returns the backticks or tildes opening a fenced code block on line, if any
+func fenceMarker(line string) string {
+	for _, c := range []string{"`", "~"} {
+		if strings.HasPrefix(line, strings.Repeat(c, 3)) {
+			return c + strings.Repeat(c, len(line)-len(strings.TrimLeft(line, c))-1)
+		}
+	}
+	return ""
+}
+
+// parseHeading returns the level and text of an ATX heading such as "## Transports",
+// or level 0 if line is not a heading
+func parseHeading(line string) (int, string) {
+	level := len(line) - len(strings.TrimLeft(line, "#"))
+	if level == 0 || level > 6 || (len(line) > level && line[level] != ' ' && line[level] != '\t') {
+		return 0, ""
+	}
+	text := strings.TrimSpace(line[level:])
+	// Closing sequences ("## Title ##") are not part of the heading
+	if trimmed := strings.TrimRight(text, "#"); trimmed == "" || strings.HasSuffix(trimmed, " ") {
+		text = strings.TrimSpace(trimmed)
+	}
+	return level, strings.ReplaceAll(text, "`", "")
+}
+
+// headingPath returns the open headings, outermost first, under the page title. A
+// level-one heading repeating the title is only listed once.
+func headingPath(title string, levels []string) []string {
+	var path []string
+	if title != "" {
+		path = append(path, title)
+	}
+	for level, heading := range levels {
+		if heading != "" && !(level == 1 && heading == title) {
+			path = append(path, heading)
+		}
+	}
+	return path
+}
+
+// headingAnchor returns the fragment the docs site generates for a heading: lowercase,
+// spaces turned into hyphens, and punctuation dropped
+func headingAnchor(heading string) string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(heading) {
+		switch {
+		case unicode.IsLetter(r) || unicode.IsDigit(r) || r == '-' || r == '_':
+			b.WriteRune(r)
+		case r == ' ':
+			b.WriteByte('-')
+		}
+	}
+	return b.String()
+}
+
+// pageURL returns where the page at relPath is published under baseURL, or "" without
+// a base URL. Pages are served without their extension, and index pages at their directory.
+func pageURL(relPath, baseURL string) string {
+	if baseURL == "" {
+		return ""
+	}
+	page := strings.TrimSuffix(strings.TrimSuffix(relPath, ".mdx"), ".md")
+	if path.Base(page) == "index" {
+		if page = path.Dir(page); page == "." {
+			page = ""
+		}
+	}
+	url := strings.TrimRight(baseURL, "/")
+	if page != "" {
+		url += "/" + page
+	}
+	return url
+}
+
+// frontMatterTitle returns the title field of a page's YAML front matter, if any
+func frontMatterTitle(content string) string {
+	normalized := strings.ReplaceAll(strings.TrimPrefix(content, "\ufeff"), "\r\n", "\n")
+	body := stripFrontMatter(normalized)
+	if len(body) == len(normalized) {
+		return ""
+	}
+	for _, line := range strings.Split(normalized[:len(normalized)-len(body)], "\n") {
+		if value, ok := strings.CutPrefix(line, "title:"); ok {
+			return strings.Trim(strings.TrimSpace(value), `"'`)
+		}
+	}
+	return ""
+}
+
+// dedupeChunks drops chunks whose content already appeared, such as notes repeated
+// across pages, keeping the first occurrence so each passage is embedded once
+func dedupeChunks(chunks []Chunk) []Chunk {
+	seen := make(map[string]bool, len(chunks))
+	unique := chunks[:0]
+	for _, chunk := range chunks {
+		if !seen[chunk.Content] {
+			seen[chunk.Content] = true
+			unique = append(unique, chunk)
+		}
+	}
+	return unique
+}
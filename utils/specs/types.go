@@ -1,7 +1,53 @@
 package specs
 
+import (
+	"encoding/json"
+	"slices"
+	"strings"
+	"time"
+)
+
 // SpecSource represents a source for MCP specification content
 type SpecSource struct {
-	Type string `json:"type"` // "local_dir" or "github_repo"
-	Path string `json:"path"` // Directory path or repository path
-}
\ No newline at end of file
+	Type    string `json:"type"` // "local_dir" or "github_repo"
+	Path    string `json:"path"` // Directory path or repository path
+	Ref     string `json:"ref,omitempty"` // Commit SHA, branch, or tag to read a GitHub repo at; defaults to MCPRepoBranch
+	BaseURL string `json:"base_url,omitempty"` // Published site the pages live under, for chunk URLs; none when empty
+}
+
+// Commit identifies the MCP repository commit a spec was extracted at
+type Commit struct {
+	SHA  string
+	Date time.Time
+}
+
+// Chunk is one passage of a markdown page, with where it came from
+type Chunk struct {
+	Content  string   `json:"content"`
+	FilePath string   `json:"file_path,omitempty"` // Relative to the spec or corpus directory
+	Headings []string `json:"headings,omitempty"`  // Heading hierarchy above the passage, page title first
+	URL      string   `json:"url,omitempty"`       // Published page, anchored at the nearest heading
+}
+
+// Section returns the heading hierarchy as a citation, such as "Transports > Streamable HTTP"
+func (c Chunk) Section() string {
+	return strings.Join(c.Headings, " > ")
+}
+
+// Equal reports whether two chunks have the same content and metadata
+func (c Chunk) Equal(other Chunk) bool {
+	return c.Content == other.Content && c.FilePath == other.FilePath &&
+		c.URL == other.URL && slices.Equal(c.Headings, other.Headings)
+}
+
+// UnmarshalJSON also accepts a bare string, the format of spec files extracted
+// before chunks carried metadata
+func (c *Chunk) UnmarshalJSON(data []byte) error {
+	var content string
+	if err := json.Unmarshal(data, &content); err == nil {
+		*c = Chunk{Content: content}
+		return nil
+	}
+	type plain Chunk
+	return json.Unmarshal(data, (*plain)(c))
+}
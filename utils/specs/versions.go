@@ -1,5 +1,7 @@
 package specs
 
+import mcpspecs "github.com/carlisia/mcp-factcheck/internal/specs"
+
 // MCP GitHub repository constants
 const (
 	MCPRepoOwner    = "modelcontextprotocol"
@@ -11,4 +13,10 @@ const (
 // BuildSpecPath creates the repository path for a given spec version
 func BuildSpecPath(version string) string {
 	return MCPSpecBasePath + "/" + version
-}
\ No newline at end of file
+}
+
+// BuildSpecURL returns the published URL of a spec version, under which each page
+// lives at its path in the version directory
+func BuildSpecURL(version string) string {
+	return mcpspecs.PageURL(version, "")
+}
@@ -0,0 +1,313 @@
+package vectorstore
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"encoding/gob"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"time"
+
+	"github.com/carlisia/mcp-factcheck/embedding"
+)
+
+// Format is how a Store encodes embeddings on disk. Files of every format are read
+// regardless of the format a Store writes.
+type Format string
+
+const (
+	FormatJSON    Format = "json"    // Indented JSON with float64 vectors; large, but readable and diffable
+	FormatFloat32 Format = "float32" // Binary with float32 vectors, lossless for OpenAI embeddings, about a seventh of the JSON size
+	FormatInt8    Format = "int8"    // Binary with vectors quantized to 8 bits, about a 25th of the JSON size; similarities move by up to about 0.005
+)
+
+// Formats lists the valid storage formats
+var Formats = []Format{FormatJSON, FormatFloat32, FormatInt8}
+
+// DefaultFormat is the format new embeddings are written in
+const DefaultFormat = FormatFloat32
+
+// ParseFormat checks that name is a valid storage format
+func ParseFormat(name string) (Format, error) {
+	for _, f := range Formats {
+		if string(f) == name {
+			return f, nil
+		}
+	}
+	return "", fmt.Errorf("unknown storage format %q (valid: %v)", name, Formats)
+}
+
+// File name extensions of the JSON and binary formats
+const (
+	jsonExt   = ".json"
+	binaryExt = ".emb"
+)
+
+// Extension returns the file name extension of embeddings stored in format f
+func (f Format) Extension() string {
+	if f == FormatJSON {
+		return jsonExt
+	}
+	return binaryExt
+}
+
+// binaryMagic starts every binary embeddings file, followed by a one-byte format version
+var binaryMagic = []byte("MCPFEMB")
+
+// binaryFormatVersion is incremented when the binary layout changes incompatibly
+const binaryFormatVersion = 1
+
+// maxPreallocatedChunks caps the chunks allocated up front from a file's count, which
+// isn't trusted until the chunks are read
+const maxPreallocatedChunks = 1024
+
+// binaryHeader is the first gob value of a binary file. Count binaryChunk values follow.
+type binaryHeader struct {
+	Version     string
+	Commit      string
+	CommittedAt *time.Time
+	Model       string
+	Dimensions  int
+	Encoding    Format
+	Count       int
+}
+
+// binaryChunk is an EmbeddedChunk with its vector packed as Encoding describes
+type binaryChunk struct {
+	ID       string
+	Version  string
+	FilePath string
+	Section  string
+	URL      string
+	Content  string
+	Metadata []byte  // JSON, so values decode as they do from JSON files
+	Scale    float32 // int8 vectors are the packed values times Scale
+	Vector   []byte  // Little-endian float32s, or int8s
+}
+
+// Encode writes spec to w in format f
+func Encode(w io.Writer, spec *embedding.SpecEmbedding, f Format) error {
+	if f == FormatJSON {
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(spec)
+	}
+
+	bw := bufio.NewWriter(w)
+	bw.Write(binaryMagic)
+	bw.WriteByte(binaryFormatVersion)
+	encoder := gob.NewEncoder(bw)
+	header := binaryHeader{
+		Version:     spec.Version,
+		Commit:      spec.Commit,
+		CommittedAt: spec.CommittedAt,
+		Model:       spec.Model,
+		Dimensions:  spec.Dimensions,
+		Encoding:    f,
+		Count:       len(spec.Chunks),
+	}
+	if err := encoder.Encode(header); err != nil {
+		return err
+	}
+	for _, chunk := range spec.Chunks {
+		packed := binaryChunk{
+			ID:       chunk.ID,
+			Version:  chunk.Version,
+			FilePath: chunk.FilePath,
+			Section:  chunk.Section,
+			URL:      chunk.URL,
+			Content:  chunk.Content,
+		}
+		if len(chunk.Metadata) > 0 {
+			metadata, err := json.Marshal(chunk.Metadata)
+			if err != nil {
+				return fmt.Errorf("chunk %s: %w", chunk.ID, err)
+			}
+			packed.Metadata = metadata
+		}
+		if f == FormatInt8 {
+			packed.Vector, packed.Scale = quantize(chunk.Embedding)
+		} else {
+			packed.Vector = packFloat32(chunk.Embedding)
+		}
+		if err := encoder.Encode(packed); err != nil {
+			return err
+		}
+	}
+	return bw.Flush()
+}
+
+// Decode reads embeddings in any format from r. With headerOnly, the chunks are
+// skipped where the format allows.
+func Decode(r io.Reader, headerOnly bool) (*embedding.SpecEmbedding, error) {
+	br := bufio.NewReader(r)
+	prefix, err := br.Peek(len(binaryMagic) + 1)
+	if err != nil || !bytes.Equal(prefix[:len(binaryMagic)], binaryMagic) {
+		// Not a binary file, so JSON as written before the binary format existed
+		if headerOnly {
+			return decodeJSONHeader(br)
+		}
+		var spec embedding.SpecEmbedding
+		if err := json.NewDecoder(br).Decode(&spec); err != nil {
+			return nil, err
+		}
+		return &spec, nil
+	}
+	if version := prefix[len(binaryMagic)]; version != binaryFormatVersion {
+		return nil, fmt.Errorf("binary format version %d is not supported by this build (expected %d)", version, binaryFormatVersion)
+	}
+	br.Discard(len(prefix))
+
+	decoder := gob.NewDecoder(br)
+	var header binaryHeader
+	if err := decoder.Decode(&header); err != nil {
+		if err == io.EOF {
+			err = io.ErrUnexpectedEOF
+		}
+		return nil, fmt.Errorf("header: %w", err)
+	}
+	if header.Count < 0 {
+		return nil, fmt.Errorf("header: invalid chunk count %d", header.Count)
+	}
+	if header.Dimensions < 0 {
+		return nil, fmt.Errorf("header: invalid dimensions %d", header.Dimensions)
+	}
+	spec := &embedding.SpecEmbedding{
+		Version:     header.Version,
+		Commit:      header.Commit,
+		CommittedAt: header.CommittedAt,
+		Model:       header.Model,
+		Dimensions:  header.Dimensions,
+		Count:       header.Count,
+	}
+	if headerOnly {
+		return spec, nil
+	}
+
+	spec.Chunks = make([]embedding.EmbeddedChunk, 0, min(header.Count, maxPreallocatedChunks))
+	for i := 0; i < header.Count; i++ {
+		var packed binaryChunk
+		if err := decoder.Decode(&packed); err != nil {
+			if err == io.EOF {
+				err = io.ErrUnexpectedEOF
+			}
+			return nil, fmt.Errorf("chunk %d of %d: %w", i+1, header.Count, err)
+		}
+		chunk := embedding.EmbeddedChunk{
+			ID:       packed.ID,
+			Version:  packed.Version,
+			FilePath: packed.FilePath,
+			Section:  packed.Section,
+			URL:      packed.URL,
+			Content:  packed.Content,
+		}
+		if len(packed.Metadata) > 0 {
+			if err := json.Unmarshal(packed.Metadata, &chunk.Metadata); err != nil {
+				return nil, fmt.Errorf("chunk %s metadata: %w", packed.ID, err)
+			}
+		}
+		switch header.Encoding {
+		case FormatInt8:
+			chunk.Embedding = dequantize(packed.Vector, packed.Scale)
+		case FormatFloat32:
+			if len(packed.Vector)%4 != 0 {
+				return nil, fmt.Errorf("chunk %s: vector is %d bytes, not a whole number of float32s", packed.ID, len(packed.Vector))
+			}
+			chunk.Embedding = unpackFloat32(packed.Vector)
+		default:
+			return nil, fmt.Errorf("unknown vector encoding %q", header.Encoding)
+		}
+		spec.Chunks = append(spec.Chunks, chunk)
+	}
+	return spec, nil
+}
+
+// decodeJSONHeader decodes the fields of a JSON file that precede "chunks", which
+// is where Encode writes them
+func decodeJSONHeader(r io.Reader) (*embedding.SpecEmbedding, error) {
+	decoder := json.NewDecoder(r)
+	if token, err := decoder.Token(); err != nil || token != json.Delim('{') {
+		return nil, fmt.Errorf("expected an object")
+	}
+
+	header := map[string]json.RawMessage{}
+	for decoder.More() {
+		token, err := decoder.Token()
+		if err != nil {
+			return nil, err
+		}
+		key, _ := token.(string)
+		if key == "chunks" {
+			break
+		}
+		var value json.RawMessage
+		if err := decoder.Decode(&value); err != nil {
+			return nil, err
+		}
+		header[key] = value
+	}
+
+	raw, _ := json.Marshal(header)
+	var spec embedding.SpecEmbedding
+	if err := json.Unmarshal(raw, &spec); err != nil {
+		return nil, err
+	}
+	return &spec, nil
+}
+
+func packFloat32(vector []float64) []byte {
+	if len(vector) == 0 {
+		return nil
+	}
+	packed := make([]byte, 4*len(vector))
+	for i, v := range vector {
+		binary.LittleEndian.PutUint32(packed[4*i:], math.Float32bits(float32(v)))
+	}
+	return packed
+}
+
+func unpackFloat32(packed []byte) []float64 {
+	if len(packed) == 0 {
+		return nil
+	}
+	vector := make([]float64, len(packed)/4)
+	for i := range vector {
+		vector[i] = float64(math.Float32frombits(binary.LittleEndian.Uint32(packed[4*i:])))
+	}
+	return vector
+}
+
+// quantize maps a vector onto int8 values scaled by its largest magnitude. Cosine
+// similarity ignores the scale, so similarities change only by the rounding error.
+func quantize(vector []float64) ([]byte, float32) {
+	if len(vector) == 0 {
+		return nil, 0
+	}
+	var peak float64
+	for _, v := range vector {
+		peak = max(peak, math.Abs(v))
+	}
+	scale := peak / 127
+	packed := make([]byte, len(vector))
+	if scale == 0 {
+		return packed, 0
+	}
+	for i, v := range vector {
+		packed[i] = byte(int8(math.Round(v / scale)))
+	}
+	return packed, float32(scale)
+}
+
+func dequantize(packed []byte, scale float32) []float64 {
+	if len(packed) == 0 {
+		return nil
+	}
+	vector := make([]float64, len(packed))
+	for i, b := range packed {
+		vector[i] = float64(int8(b)) * float64(scale)
+	}
+	return vector
+}
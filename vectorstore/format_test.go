@@ -0,0 +1,100 @@
+package vectorstore
+
+import (
+	"bytes"
+	"encoding/gob"
+	"strings"
+	"testing"
+
+	"github.com/carlisia/mcp-factcheck/embedding"
+)
+
+// binaryFile returns a binary file holding header followed by chunks
+func binaryFile(t *testing.T, header binaryHeader, chunks ...binaryChunk) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	buf.Write(binaryMagic)
+	buf.WriteByte(binaryFormatVersion)
+	encoder := gob.NewEncoder(&buf)
+	if err := encoder.Encode(header); err != nil {
+		t.Fatal(err)
+	}
+	for _, chunk := range chunks {
+		if err := encoder.Encode(chunk); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return buf.Bytes()
+}
+
+func TestDecodeBadCount(t *testing.T) {
+	chunk := binaryChunk{ID: "a", Vector: packFloat32([]float64{1, 0})}
+	tests := []struct {
+		name       string
+		count      int
+		dimensions int
+		wantErr    string
+	}{
+		{name: "negative count", count: -1, dimensions: 2, wantErr: "invalid chunk count -1"},
+		{name: "negative dimensions", count: 1, dimensions: -2, wantErr: "invalid dimensions -2"},
+		{name: "huge count", count: 1 << 50, dimensions: 2, wantErr: "chunk 2 of"},
+		{name: "count over the chunks", count: 3, dimensions: 2, wantErr: "unexpected EOF"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data := binaryFile(t, binaryHeader{Dimensions: tt.dimensions, Encoding: FormatFloat32, Count: tt.count}, chunk)
+			for _, headerOnly := range []bool{false, true} {
+				_, err := Decode(bytes.NewReader(data), headerOnly)
+				if headerOnly && tt.count >= 0 && tt.dimensions >= 0 {
+					// The chunks aren't read, so only the header's values are checked
+					continue
+				}
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("Decode(headerOnly %v) err = %v, want %q", headerOnly, err, tt.wantErr)
+				}
+			}
+		})
+	}
+}
+
+func TestEncodeDecode(t *testing.T) {
+	spec := &embedding.SpecEmbedding{
+		Version:    "2025-06-18",
+		Model:      "test",
+		Dimensions: 2,
+		Count:      2,
+		Chunks: []embedding.EmbeddedChunk{
+			{ID: "a", Content: "first", Embedding: []float64{0.5, -0.25}},
+			{ID: "b", Content: "second", Embedding: []float64{1, 0}, Metadata: map[string]any{"level": "MUST"}},
+		},
+	}
+	for _, format := range Formats {
+		t.Run(string(format), func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := Encode(&buf, spec, format); err != nil {
+				t.Fatal(err)
+			}
+			got, err := Decode(&buf, false)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got.Version != spec.Version || len(got.Chunks) != len(spec.Chunks) {
+				t.Fatalf("decoded version %s with %d chunks, want %s with %d", got.Version, len(got.Chunks), spec.Version, len(spec.Chunks))
+			}
+			for i, chunk := range got.Chunks {
+				want := spec.Chunks[i]
+				if chunk.ID != want.ID || chunk.Content != want.Content || len(chunk.Embedding) != len(want.Embedding) {
+					t.Errorf("chunk %d = %+v, want %+v", i, chunk, want)
+				}
+				for j, v := range chunk.Embedding {
+					if diff := v - want.Embedding[j]; diff > 0.01 || diff < -0.01 {
+						t.Errorf("chunk %d vector[%d] = %v, want %v", i, j, v, want.Embedding[j])
+					}
+				}
+			}
+			if got.Chunks[1].Metadata["level"] != "MUST" {
+				t.Errorf("metadata = %v, want level MUST", got.Chunks[1].Metadata)
+			}
+		})
+	}
+}
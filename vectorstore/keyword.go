@@ -0,0 +1,92 @@
+package vectorstore
+
+import (
+	"math"
+	"regexp"
+	"strings"
+
+	"github.com/carlisia/mcp-factcheck/embedding"
+)
+
+// BM25 parameters, at their usual values
+const (
+	bm25K1 = 1.2
+	bm25B  = 0.75
+)
+
+// termPattern matches words, keeping compound identifiers like
+// notifications/initialized or list_changed together
+var termPattern = regexp.MustCompile(`[a-z0-9]+(?:[/_.-][a-z0-9]+)*`)
+
+// terms splits text into lowercase search terms. Compound identifiers yield the
+// whole identifier and each of its parts, so an exact method name scores higher
+// than the same words appearing apart.
+func terms(text string) []string {
+	var out []string
+	for _, term := range termPattern.FindAllString(strings.ToLower(text), -1) {
+		out = append(out, term)
+		if strings.ContainsAny(term, "/_.-") {
+			out = append(out, strings.FieldsFunc(term, func(r rune) bool { return strings.ContainsRune("/_.-", r) })...)
+		}
+	}
+	return out
+}
+
+// keywordIndex is a BM25 index over the chunks of one spec version
+type keywordIndex struct {
+	termFreqs []map[string]int // per chunk, in chunk order
+	lengths   []int
+	avgLength float64
+	docFreq   map[string]int
+}
+
+func newKeywordIndex(chunks []embedding.EmbeddedChunk) *keywordIndex {
+	idx := &keywordIndex{
+		termFreqs: make([]map[string]int, len(chunks)),
+		lengths:   make([]int, len(chunks)),
+		docFreq:   map[string]int{},
+	}
+	total := 0
+	for i, chunk := range chunks {
+		freqs := map[string]int{}
+		for _, term := range terms(chunk.Section + "\n" + chunk.Content) {
+			freqs[term]++
+			idx.lengths[i]++
+		}
+		for term := range freqs {
+			idx.docFreq[term]++
+		}
+		idx.termFreqs[i] = freqs
+		total += idx.lengths[i]
+	}
+	if len(chunks) > 0 {
+		idx.avgLength = float64(total) / float64(len(chunks))
+	}
+	return idx
+}
+
+// scores returns the BM25 score of every chunk for query, in chunk order
+func (idx *keywordIndex) scores(query string) []float64 {
+	scores := make([]float64, len(idx.termFreqs))
+	queryTerms := map[string]bool{}
+	for _, term := range terms(query) {
+		queryTerms[term] = true
+	}
+	n := float64(len(idx.termFreqs))
+	for term := range queryTerms {
+		df := float64(idx.docFreq[term])
+		if df == 0 {
+			continue
+		}
+		idf := math.Log(1 + (n-df+0.5)/(df+0.5))
+		for i, freqs := range idx.termFreqs {
+			tf := float64(freqs[term])
+			if tf == 0 {
+				continue
+			}
+			norm := 1 - bm25B + bm25B*float64(idx.lengths[i])/idx.avgLength
+			scores[i] += idf * tf * (bm25K1 + 1) / (tf + bm25K1*norm)
+		}
+	}
+	return scores
+}
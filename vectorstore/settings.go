@@ -0,0 +1,47 @@
+package vectorstore
+
+import (
+	"fmt"
+	"sync/atomic"
+)
+
+// SearchSettings chooses between exact and approximate nearest neighbor search
+type SearchSettings struct {
+	ExactBelow int // Collections with fewer chunks are searched exactly; negative searches every collection exactly
+	Ef         int // Nearest neighbors the index returns per search, raised to topK when smaller
+}
+
+// DefaultSearchSettings searches spec-sized collections exactly, where scoring every
+// chunk takes about a millisecond, and larger corpora through their index
+func DefaultSearchSettings() SearchSettings {
+	return SearchSettings{ExactBelow: 2000, Ef: 100}
+}
+
+// Validate checks that the settings are usable
+func (s SearchSettings) Validate() error {
+	if s.Ef < 1 {
+		return fmt.Errorf("ef must be at least 1, got %d", s.Ef)
+	}
+	return nil
+}
+
+var currentSearch atomic.Pointer[SearchSettings]
+
+func init() {
+	defaults := DefaultSearchSettings()
+	currentSearch.Store(&defaults)
+}
+
+// CurrentSearchSettings returns the settings used by subsequent searches
+func CurrentSearchSettings() SearchSettings {
+	return *currentSearch.Load()
+}
+
+// SetSearchSettings atomically replaces the settings used by subsequent searches
+func SetSearchSettings(s SearchSettings) error {
+	if err := s.Validate(); err != nil {
+		return err
+	}
+	currentSearch.Store(&s)
+	return nil
+}
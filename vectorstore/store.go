@@ -1,12 +1,19 @@
 package vectorstore
 
 import (
-	"encoding/json"
+	"cmp"
+	"context"
+	"errors"
 	"fmt"
+	"io/fs"
 	"math"
 	"os"
 	"path/filepath"
+	"slices"
 	"sort"
+	"strings"
+	"sync"
+	"time"
 
 	"github.com/carlisia/mcp-factcheck/embedding"
 )
@@ -14,11 +21,49 @@ import (
 // Store handles storage and retrieval of embeddings from the filesystem
 type Store struct {
 	dataDir string
+	format  Format // Format Store writes
+
+	mu          sync.Mutex
+	collections map[string]*collection // loaded embeddings by spec version
+}
+
+// collection is a version's loaded embeddings and their search indexes, valid while
+// the embeddings file keeps the modification time it was loaded at
+type collection struct {
+	version  string
+	modTime  time.Time
+	spec     *embedding.SpecEmbedding
+	keywords *keywordIndex // Built on first keyword search
+	index    *hnswIndex    // Loaded on first approximate search; nil when missing or stale
+	loaded   bool          // Whether loading index was attempted
 }
 
+// cancelCheckInterval is how many chunks are scored between checks for cancellation
+const cancelCheckInterval = 256
+
 // NewStore creates a new vector store
 func NewStore(dataDir string) *Store {
-	return &Store{dataDir: dataDir}
+	return &Store{dataDir: dataDir, format: DefaultFormat, collections: map[string]*collection{}}
+}
+
+// SetFormat changes the format Store writes. Stored files keep their format until
+// they are written again.
+func (s *Store) SetFormat(f Format) {
+	s.format = f
+}
+
+// Path returns the file holding a version's embeddings. A version stored in both
+// formats, as by a build that predates the binary format, is read from the binary
+// file. The error wraps fs.ErrNotExist when the version isn't stored.
+func (s *Store) Path(version string) (string, error) {
+	var err error
+	for _, ext := range []string{binaryExt, jsonExt} {
+		path := filepath.Join(s.dataDir, version+ext)
+		if _, err = os.Stat(path); err == nil {
+			return path, nil
+		}
+	}
+	return "", err
 }
 
 // Store saves a spec embedding to the database
@@ -28,70 +73,210 @@ func (s *Store) Store(specEmbedding *embedding.SpecEmbedding) error {
 		return fmt.Errorf("failed to create data directory: %w", err)
 	}
 
-	// Save to JSON file
-	filename := filepath.Join(s.dataDir, fmt.Sprintf("%s.json", specEmbedding.Version))
-	file, err := os.Create(filename)
+	// Write to a temporary file and rename it into place, so a server reading the
+	// version concurrently sees either the old or the new embeddings, never a partial file
+	filename := filepath.Join(s.dataDir, specEmbedding.Version+s.format.Extension())
+	file, err := os.CreateTemp(s.dataDir, "."+filepath.Base(filename)+".tmp-*")
 	if err != nil {
 		return fmt.Errorf("failed to create file: %w", err)
 	}
-	defer file.Close()
+	defer os.Remove(file.Name())
 
-	encoder := json.NewEncoder(file)
-	encoder.SetIndent("", "  ")
-	if err := encoder.Encode(specEmbedding); err != nil {
+	if err := Encode(file, specEmbedding, s.format); err != nil {
+		file.Close()
 		return fmt.Errorf("failed to encode spec embedding: %w", err)
 	}
+	if err := file.Close(); err != nil {
+		return fmt.Errorf("failed to write spec embedding: %w", err)
+	}
+	if err := os.Chmod(file.Name(), 0644); err != nil {
+		return fmt.Errorf("failed to set file permissions: %w", err)
+	}
+	if err := os.Rename(file.Name(), filename); err != nil {
+		return fmt.Errorf("failed to replace %s: %w", filename, err)
+	}
+
+	// Remove the version's file in the other format, which would otherwise shadow or
+	// duplicate the new one
+	other := jsonExt
+	if s.format == FormatJSON {
+		other = binaryExt
+	}
+	if err := os.Remove(filepath.Join(s.dataDir, specEmbedding.Version+other)); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("failed to remove the previous %s file: %w", other, err)
+	}
+
+	return s.writeIndex(specEmbedding)
+}
+
+// BuildIndex builds and persists the approximate search index of a stored version.
+// Store does this for every version it writes; BuildIndex adds indexes to
+// embeddings written by older builds or copied in.
+func (s *Store) BuildIndex(version string) error {
+	specEmbedding, err := s.Load(version)
+	if err != nil {
+		return err
+	}
+	return s.writeIndex(specEmbedding)
+}
+
+// writeIndex builds the HNSW index of specEmbedding and writes it next to the
+// embeddings as <version>.hnsw, replacing any previous index atomically
+func (s *Store) writeIndex(specEmbedding *embedding.SpecEmbedding) error {
+	filename := filepath.Join(s.dataDir, specEmbedding.Version+indexExt)
+	file, err := os.CreateTemp(s.dataDir, "."+filepath.Base(filename)+".tmp-*")
+	if err != nil {
+		return fmt.Errorf("failed to create index file: %w", err)
+	}
+	defer os.Remove(file.Name())
 
+	if err := writeIndex(file, buildIndex(specEmbedding.Chunks)); err != nil {
+		file.Close()
+		return fmt.Errorf("failed to encode index: %w", err)
+	}
+	if err := file.Close(); err != nil {
+		return fmt.Errorf("failed to write index: %w", err)
+	}
+	if err := os.Chmod(file.Name(), 0644); err != nil {
+		return fmt.Errorf("failed to set file permissions: %w", err)
+	}
+	if err := os.Rename(file.Name(), filename); err != nil {
+		return fmt.Errorf("failed to replace %s: %w", filename, err)
+	}
 	return nil
 }
 
-// Load retrieves a spec embedding from the database
+// ModTime returns when a spec version's embeddings were last written
+func (s *Store) ModTime(version string) (time.Time, error) {
+	path, err := s.Path(version)
+	if err != nil {
+		return time.Time{}, err
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return info.ModTime(), nil
+}
+
+// Load retrieves a spec embedding from the database, in whichever format it is stored
 func (s *Store) Load(version string) (*embedding.SpecEmbedding, error) {
-	filename := filepath.Join(s.dataDir, fmt.Sprintf("%s.json", version))
-	
+	return s.load(version, false)
+}
+
+// LoadHeader reads a spec embedding's version, commit, and model without decoding
+// its chunks, which are by far the largest part of the file
+func (s *Store) LoadHeader(version string) (*embedding.SpecEmbedding, error) {
+	return s.load(version, true)
+}
+
+func (s *Store) load(version string, headerOnly bool) (*embedding.SpecEmbedding, error) {
+	filename, err := s.Path(version)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open file: %w", err)
+	}
 	file, err := os.Open(filename)
 	if err != nil {
 		return nil, fmt.Errorf("failed to open file: %w", err)
 	}
 	defer file.Close()
 
-	var specEmbedding embedding.SpecEmbedding
-	decoder := json.NewDecoder(file)
-	if err := decoder.Decode(&specEmbedding); err != nil {
+	specEmbedding, err := Decode(file, headerOnly)
+	if err != nil {
 		return nil, fmt.Errorf("failed to decode spec embedding: %w", err)
 	}
+	return specEmbedding, nil
+}
 
-	return &specEmbedding, nil
+// Cached returns a version's embeddings from the cache searches use, loading them
+// if the file changed. Callers must not modify the result.
+func (s *Store) Cached(version string) (*embedding.SpecEmbedding, error) {
+	c, err := s.collection(version)
+	if err != nil {
+		return nil, err
+	}
+	return c.spec, nil
 }
 
 // Search performs similarity search against a spec version
-func (s *Store) Search(version string, queryEmbedding []float64, topK int) ([]embedding.SearchResult, error) {
+func (s *Store) Search(ctx context.Context, version string, queryEmbedding []float64, topK int) ([]embedding.SearchResult, error) {
+	return s.HybridSearch(ctx, version, "", queryEmbedding, topK, 0)
+}
+
+// HybridSearch ranks a spec version's chunks by a weighted sum of vector similarity
+// and BM25 keyword relevance to query, so exact terms like "notifications/initialized"
+// rank well even when their embeddings aren't the closest. keywordWeight is in [0, 1];
+// at 0 the ranking is by similarity alone. Keyword scores are divided by the best
+// match's so both parts span [0, 1]. Results keep the cosine similarity in Similarity,
+// which thresholds are applied to, and the combined score in Score. The search stops
+// with ctx's error once ctx is done.
+func (s *Store) HybridSearch(ctx context.Context, version, query string, queryEmbedding []float64, topK int, keywordWeight float64) ([]embedding.SearchResult, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
 	// Load spec embeddings
-	specEmbedding, err := s.Load(version)
+	c, err := s.collection(version)
 	if err != nil {
 		return nil, fmt.Errorf("failed to load spec embeddings: %w", err)
 	}
+	specEmbedding := c.spec
+	// Queries are embedded with the current model
+	if err := specEmbedding.CheckModel(embedding.CurrentModel()); err != nil {
+		return nil, err
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	var keywordScores []float64
+	var maxKeywordScore float64
+	if keywordWeight > 0 && query != "" {
+		keywordScores = s.keywordIndex(c).scores(query)
+		for _, score := range keywordScores {
+			maxKeywordScore = max(maxKeywordScore, score)
+		}
+	}
+
+	// Score every chunk, or only the index's nearest neighbors and the best keyword
+	// matches when the collection is large enough to have it searched approximately
+	candidates := s.candidates(c, queryEmbedding, keywordScores, topK)
 
 	// Calculate similarities
-	var results []embedding.SearchResult
-	for _, chunk := range specEmbedding.Chunks {
+	results := make([]embedding.SearchResult, 0, len(candidates))
+	for n, i := range candidates {
+		if n%cancelCheckInterval == 0 {
+			if err := ctx.Err(); err != nil {
+				return nil, err
+			}
+		}
+		chunk := specEmbedding.Chunks[i]
 		similarity := cosineSimilarity(queryEmbedding, chunk.Embedding)
+		score := similarity
+		if maxKeywordScore > 0 {
+			score = (1-keywordWeight)*similarity + keywordWeight*keywordScores[i]/maxKeywordScore
+		}
 		results = append(results, embedding.SearchResult{
 			Chunk:      chunk,
 			Similarity: similarity,
+			Score:      score,
 		})
 	}
 
-	// Sort by similarity (descending)
+	// Sort by score (descending), breaking ties by chunk ID so the same search always
+	// ranks the same way, which paging through results relies on
 	sort.Slice(results, func(i, j int) bool {
-		return results[i].Similarity > results[j].Similarity
+		if results[i].Score != results[j].Score {
+			return results[i].Score > results[j].Score
+		}
+		return results[i].Chunk.ID < results[j].Chunk.ID
 	})
 
 	// Add rank and limit to topK
 	if topK > len(results) {
 		topK = len(results)
 	}
-	
+
 	for i := 0; i < topK; i++ {
 		results[i].Rank = i + 1
 	}
@@ -99,21 +284,121 @@ func (s *Store) Search(version string, queryEmbedding []float64, topK int) ([]em
 	return results[:topK], nil
 }
 
-// ListVersions returns all available spec versions in the database
-func (s *Store) ListVersions() ([]string, error) {
-	files, err := filepath.Glob(filepath.Join(s.dataDir, "*.json"))
+// collection returns a version's embeddings, loading them if the file changed since
+// they were last loaded
+func (s *Store) collection(version string) (*collection, error) {
+	modTime, err := s.ModTime(version)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open file: %w", err)
+	}
+	s.mu.Lock()
+	c, ok := s.collections[version]
+	s.mu.Unlock()
+	if ok && c.modTime.Equal(modTime) {
+		return c, nil
+	}
+
+	// Loads of the same version may race; the last one to finish is cached
+	spec, err := s.Load(version)
 	if err != nil {
-		return nil, fmt.Errorf("failed to list files: %w", err)
+		return nil, err
+	}
+	// Servers without an API key embed queries locally, so they search local
+	// embeddings of the chunks. An index over the stored vectors doesn't apply to them.
+	reembedded := false
+	if embedding.CurrentModel().Name == embedding.LocalModel && spec.EmbeddingModel() != embedding.LocalModel {
+		spec, reembedded = spec.EmbedLocally(), true
+	}
+	c = &collection{version: version, modTime: modTime, spec: spec, loaded: reembedded}
+	s.mu.Lock()
+	s.collections[version] = c
+	s.mu.Unlock()
+	return c, nil
+}
+
+// keywordIndex returns the keyword index for a collection's chunks, building it on
+// first use
+func (s *Store) keywordIndex(c *collection) *keywordIndex {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if c.keywords == nil {
+		c.keywords = newKeywordIndex(c.spec.Chunks)
+	}
+	return c.keywords
+}
+
+// candidates returns the indexes of the chunks worth scoring for a query: all of
+// them, or for collections of at least SearchSettings.ExactBelow chunks with an
+// index, the index's approximate nearest neighbors and the best keyword matches.
+// A missing or stale index falls back to exact search.
+func (s *Store) candidates(c *collection, queryEmbedding []float64, keywordScores []float64, topK int) []int {
+	chunks := c.spec.Chunks
+	settings := CurrentSearchSettings()
+	all := func() []int {
+		indexes := make([]int, len(chunks))
+		for i := range indexes {
+			indexes[i] = i
+		}
+		return indexes
+	}
+	if settings.ExactBelow < 0 || len(chunks) < settings.ExactBelow {
+		return all()
+	}
+	index := s.hnsw(c)
+	if index == nil {
+		return all()
+	}
+
+	ef := max(settings.Ef, topK)
+	candidates := index.search(queryEmbedding, ef)
+	if len(keywordScores) > 0 {
+		seen := make(map[int]bool, len(candidates))
+		for _, i := range candidates {
+			seen[i] = true
+		}
+		best := all()
+		slices.SortStableFunc(best, func(a, b int) int { return cmp.Compare(keywordScores[b], keywordScores[a]) })
+		for _, i := range best[:min(ef, len(best))] {
+			if !seen[i] && keywordScores[i] > 0 {
+				candidates = append(candidates, i)
+			}
+		}
 	}
+	return candidates
+}
 
+// hnsw returns a collection's approximate search index, loading it on first use,
+// or nil if the version has no usable index
+func (s *Store) hnsw(c *collection) *hnswIndex {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !c.loaded {
+		c.loaded = true
+		file, err := os.Open(filepath.Join(s.dataDir, c.version+indexExt))
+		if err != nil {
+			return nil
+		}
+		defer file.Close()
+		c.index, _ = readIndex(file, c.spec.Chunks)
+	}
+	return c.index
+}
+
+// ListVersions returns all available spec versions in the database, in either format
+func (s *Store) ListVersions() ([]string, error) {
 	var versions []string
-	for _, file := range files {
-		base := filepath.Base(file)
-		version := base[:len(base)-5] // Remove .json extension
-		versions = append(versions, version)
+	for _, ext := range []string{jsonExt, binaryExt} {
+		files, err := filepath.Glob(filepath.Join(s.dataDir, "*"+ext))
+		if err != nil {
+			return nil, fmt.Errorf("failed to list files: %w", err)
+		}
+		for _, file := range files {
+			versions = append(versions, strings.TrimSuffix(filepath.Base(file), ext))
+		}
 	}
 
-	return versions, nil
+	slices.Sort(versions)
+	return slices.Compact(versions), nil
 }
 
 // cosineSimilarity calculates cosine similarity between two vectors